@@ -1,14 +1,21 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	stdlog "log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	gogogrpc "github.com/gogo/protobuf/grpc"
 	"github.com/gorilla/mux"
 	"github.com/rakyll/statik/fs"
+	"github.com/spf13/cast"
 
 	abci "github.com/line/ostracon/abci/types"
 	ostjson "github.com/line/ostracon/libs/json"
@@ -88,13 +95,33 @@ import (
 	"github.com/line/lbm-sdk/x/wasm"
 	wasmclient "github.com/line/lbm-sdk/x/wasm/client"
 
+	"github.com/line/lfb/app/indexer"
+	"github.com/line/lfb/app/metrics"
 	appparams "github.com/line/lfb/app/params"
-
-	// unnamed import of statik for swagger UI support
-	_ "github.com/line/lbm-sdk/client/docs/statik"
+	"github.com/line/lfb/app/streaming"
+	"github.com/line/lfb/app/tracing"
+	"github.com/line/lfb/client/docs/statik"
+	"github.com/line/lfb/client/events"
+	"github.com/line/lfb/client/grpc/overview"
+	"github.com/line/lfb/client/health"
+	"github.com/line/lfb/x/audit"
+	auditkeeper "github.com/line/lfb/x/audit/keeper"
+	audittypes "github.com/line/lfb/x/audit/types"
+	"github.com/line/lfb/x/keyrotation"
+	keyrotationkeeper "github.com/line/lfb/x/keyrotation/keeper"
+	keyrotationtypes "github.com/line/lfb/x/keyrotation/types"
+	"github.com/line/lfb/x/paramschedule"
+	paramscheduleclient "github.com/line/lfb/x/paramschedule/client"
+	paramschedulekeeper "github.com/line/lfb/x/paramschedule/keeper"
+	paramscheduletypes "github.com/line/lfb/x/paramschedule/types"
 )
 
-const appName = "LFB"
+const (
+	appName = "LFB"
+
+	// tracerShutdownTimeout is the time given to the tracer to export its last spans on Close.
+	tracerShutdownTimeout = 5 * time.Second
+)
 
 var (
 	// DefaultNodeHome default home directories for the application daemon
@@ -112,7 +139,8 @@ var (
 		mint.AppModuleBasic{},
 		distr.AppModuleBasic{},
 		gov.NewAppModuleBasic(
-			append(wasmclient.ProposalHandlers, paramsclient.ProposalHandler, distrclient.ProposalHandler, upgradeclient.ProposalHandler, upgradeclient.CancelProposalHandler)...,
+			append(wasmclient.ProposalHandlers, paramsclient.ProposalHandler, distrclient.ProposalHandler, upgradeclient.ProposalHandler, upgradeclient.CancelProposalHandler,
+				paramscheduleclient.ProposalHandler, paramscheduleclient.CancelProposalHandler)...,
 		),
 		params.AppModuleBasic{},
 		crisis.AppModuleBasic{},
@@ -120,6 +148,9 @@ var (
 		ibc.AppModuleBasic{},
 		upgrade.AppModuleBasic{},
 		evidence.AppModuleBasic{},
+		audit.AppModuleBasic{},
+		paramschedule.AppModuleBasic{},
+		keyrotation.AppModuleBasic{},
 		transfer.AppModuleBasic{},
 		vesting.AppModuleBasic{},
 		wasm.AppModuleBasic{},
@@ -163,21 +194,24 @@ type LinkApp struct { // nolint: golint
 	memKeys map[string]*sdk.MemoryStoreKey
 
 	// keepers
-	AccountKeeper    authkeeper.AccountKeeper
-	BankKeeper       bankkeeper.Keeper
-	CapabilityKeeper *capabilitykeeper.Keeper
-	StakingKeeper    stakingkeeper.Keeper
-	SlashingKeeper   slashingkeeper.Keeper
-	MintKeeper       mintkeeper.Keeper
-	DistrKeeper      distrkeeper.Keeper
-	GovKeeper        govkeeper.Keeper
-	CrisisKeeper     crisiskeeper.Keeper
-	UpgradeKeeper    upgradekeeper.Keeper
-	ParamsKeeper     paramskeeper.Keeper
-	IBCKeeper        *ibckeeper.Keeper // IBC Keeper must be a pointer in the app, so we can SetRouter on it correctly
-	EvidenceKeeper   evidencekeeper.Keeper
-	TransferKeeper   ibctransferkeeper.Keeper
-	WasmKeeper       wasm.Keeper
+	AccountKeeper       authkeeper.AccountKeeper
+	BankKeeper          bankkeeper.Keeper
+	CapabilityKeeper    *capabilitykeeper.Keeper
+	StakingKeeper       stakingkeeper.Keeper
+	SlashingKeeper      slashingkeeper.Keeper
+	MintKeeper          mintkeeper.Keeper
+	DistrKeeper         distrkeeper.Keeper
+	GovKeeper           govkeeper.Keeper
+	CrisisKeeper        crisiskeeper.Keeper
+	UpgradeKeeper       upgradekeeper.Keeper
+	ParamsKeeper        paramskeeper.Keeper
+	IBCKeeper           *ibckeeper.Keeper // IBC Keeper must be a pointer in the app, so we can SetRouter on it correctly
+	EvidenceKeeper      evidencekeeper.Keeper
+	AuditKeeper         auditkeeper.Keeper
+	ParamScheduleKeeper paramschedulekeeper.Keeper
+	KeyRotationKeeper   keyrotationkeeper.Keeper
+	TransferKeeper      ibctransferkeeper.Keeper
+	WasmKeeper          wasm.Keeper
 
 	// make scoped keepers public for test purposes
 	ScopedIBCKeeper      capabilitykeeper.ScopedKeeper
@@ -189,6 +223,20 @@ type LinkApp struct { // nolint: golint
 
 	// simulation manager
 	sm *module.SimulationManager
+
+	// the thresholds of the health and readiness endpoints
+	healthConfig health.Config
+	// whether the pinned codes have been loaded into the wasm VM cache
+	wasmVMCacheInitialized bool
+
+	// the listener writing the state changes to files; nil if the streaming is disabled
+	stateListener *streaming.Listener
+	// the indexer writing the blocks to the SQL database; nil if the indexer is disabled
+	indexer *indexer.Indexer
+	// the Prometheus metrics of the app; nil if the telemetry is disabled
+	metrics *metrics.Metrics
+	// the tracer of the execution of the blocks; nil if the tracing is disabled
+	tracer *tracing.Tracer
 }
 
 func init() {
@@ -209,6 +257,19 @@ func NewLinkApp(
 	legacyAmino := encodingConfig.Amino
 	interfaceRegistry := encodingConfig.InterfaceRegistry
 
+	streamingConfig, err := streaming.ReadConfig(appOpts)
+	if err != nil {
+		panic("error while reading streaming config: " + err.Error())
+	}
+	var stateListener *streaming.Listener
+	if streamingConfig.Enable {
+		if stateListener, err = streaming.NewListener(homePath, streamingConfig); err != nil {
+			panic(err)
+		}
+		// the store option replaces the multistore the other options configure
+		baseAppOptions = append([]func(*baseapp.BaseApp){stateListener.StoreOption(db)}, baseAppOptions...)
+	}
+
 	bApp := baseapp.NewBaseApp(appName, logger, db, encodingConfig.TxConfig.TxDecoder(), baseAppOptions...)
 	bApp.SetCommitMultiStoreTracer(traceStore)
 	bApp.SetAppVersion(version.Version)
@@ -219,7 +280,8 @@ func NewLinkApp(
 		minttypes.StoreKey, distrtypes.StoreKey, slashingtypes.StoreKey,
 		govtypes.StoreKey, paramstypes.StoreKey, ibchost.StoreKey, upgradetypes.StoreKey,
 		evidencetypes.StoreKey, ibctransfertypes.StoreKey, capabilitytypes.StoreKey,
-		wasm.StoreKey,
+		wasm.StoreKey, audittypes.StoreKey, paramscheduletypes.StoreKey,
+		keyrotationtypes.StoreKey,
 	)
 	memKeys := sdk.NewMemoryStoreKeys(capabilitytypes.MemStoreKey)
 
@@ -231,6 +293,22 @@ func NewLinkApp(
 		invCheckPeriod:    invCheckPeriod,
 		keys:              keys,
 		memKeys:           memKeys,
+		stateListener:     stateListener,
+	}
+	if err := streamingConfig.ValidateKeys(keys); err != nil {
+		panic("error while reading streaming config: " + err.Error())
+	}
+	if cast.ToBool(appOpts.Get("telemetry.enabled")) {
+		app.metrics = metrics.New(metrics.RegistererFromOptions(appOpts))
+	}
+	tracingConfig, err := tracing.ReadConfig(appOpts)
+	if err != nil {
+		panic("error while reading tracing config: " + err.Error())
+	}
+	if tracingConfig.Enable {
+		if app.tracer, err = tracing.New(homePath, tracingConfig); err != nil {
+			panic(err)
+		}
 	}
 
 	app.ParamsKeeper = initParamsKeeper(appCodec, legacyAmino, keys[paramstypes.StoreKey])
@@ -274,6 +352,9 @@ func NewLinkApp(
 	app.StakingKeeper = *stakingKeeper.SetHooks(
 		stakingtypes.NewMultiStakingHooks(app.DistrKeeper.Hooks(), app.SlashingKeeper.Hooks()),
 	)
+	app.KeyRotationKeeper = keyrotationkeeper.NewKeeper(
+		appCodec, keys[keyrotationtypes.StoreKey], app.GetSubspace(keyrotationtypes.ModuleName), app.StakingKeeper, app.SlashingKeeper,
+	)
 
 	// Create IBC Keeper
 	app.IBCKeeper = ibckeeper.NewKeeper(
@@ -334,13 +415,20 @@ func NewLinkApp(
 	)
 
 	// register the proposal types
+	// the changes made by the param change, upgrade and wasm proposals are recorded in the audit log
+	app.AuditKeeper = auditkeeper.NewKeeper(appCodec, keys[audittypes.StoreKey], &app.GovKeeper)
+	app.ParamScheduleKeeper = paramschedulekeeper.NewKeeper(appCodec, keys[paramscheduletypes.StoreKey], app.ParamsKeeper, app.AuditKeeper)
 	govRouter := govtypes.NewRouter()
 	govRouter.AddRoute(govtypes.RouterKey, govtypes.ProposalHandler).
-		AddRoute(paramproposal.RouterKey, params.NewParamChangeProposalHandler(app.ParamsKeeper)).
+		AddRoute(paramproposal.RouterKey, audit.NewParamChangeProposalHandler(app.AuditKeeper, app.ParamsKeeper,
+			params.NewParamChangeProposalHandler(app.ParamsKeeper))).
 		AddRoute(distrtypes.RouterKey, distr.NewCommunityPoolSpendProposalHandler(app.DistrKeeper)).
-		AddRoute(upgradetypes.RouterKey, upgrade.NewSoftwareUpgradeProposalHandler(app.UpgradeKeeper)).
+		AddRoute(upgradetypes.RouterKey, audit.NewUpgradeProposalHandler(app.AuditKeeper, app.UpgradeKeeper, appCodec,
+			upgrade.NewSoftwareUpgradeProposalHandler(app.UpgradeKeeper))).
 		AddRoute(ibchost.RouterKey, ibcclient.NewClientUpdateProposalHandler(app.IBCKeeper.ClientKeeper)).
-		AddRoute(wasm.RouterKey, wasm.NewWasmProposalHandler(app.WasmKeeper, wasm.EnableAllProposals))
+		AddRoute(wasm.RouterKey, audit.NewWasmProposalHandler(app.AuditKeeper, app.WasmKeeper,
+			wasm.NewWasmProposalHandler(app.WasmKeeper, wasm.EnableAllProposals))).
+		AddRoute(paramscheduletypes.RouterKey, paramschedule.NewProposalHandler(app.ParamScheduleKeeper))
 
 	app.GovKeeper = govkeeper.NewKeeper(
 		appCodec, keys[govtypes.StoreKey], app.GetSubspace(govtypes.ModuleName), app.AccountKeeper, app.BankKeeper,
@@ -375,10 +463,14 @@ func NewLinkApp(
 		mint.NewAppModule(appCodec, app.MintKeeper, app.AccountKeeper),
 		slashing.NewAppModule(appCodec, app.SlashingKeeper, app.AccountKeeper, app.BankKeeper, app.StakingKeeper),
 		distr.NewAppModule(appCodec, app.DistrKeeper, app.AccountKeeper, app.BankKeeper, app.StakingKeeper),
-		staking.NewAppModule(appCodec, app.StakingKeeper, app.AccountKeeper, app.BankKeeper),
+		// the validator set updates of staking carry the consensus key rotations
+		keyrotation.NewStakingModule(staking.NewAppModule(appCodec, app.StakingKeeper, app.AccountKeeper, app.BankKeeper), app.KeyRotationKeeper),
 		upgrade.NewAppModule(app.UpgradeKeeper),
 		wasm.NewAppModule(appCodec, &app.WasmKeeper, app.StakingKeeper),
 		evidence.NewAppModule(app.EvidenceKeeper),
+		audit.NewAppModule(app.AuditKeeper),
+		paramschedule.NewAppModule(app.ParamScheduleKeeper),
+		keyrotation.NewAppModule(app.KeyRotationKeeper),
 		ibc.NewAppModule(app.IBCKeeper),
 		params.NewAppModule(app.ParamsKeeper),
 		transferModule,
@@ -388,11 +480,15 @@ func NewLinkApp(
 	// there is nothing left over in the validator fee pool, so as to keep the
 	// CanWithdrawInvariant invariant.
 	// NOTE: staking module is required if HistoricalEntries param > 0
+	// the keyrotation BeginBlocker carries the slashing by the evidence of old consensus keys over
+	// to the current keys, so it runs after the evidence BeginBlocker
 	app.mm.SetOrderBeginBlockers(
 		upgradetypes.ModuleName, minttypes.ModuleName, distrtypes.ModuleName, slashingtypes.ModuleName,
-		evidencetypes.ModuleName, stakingtypes.ModuleName, ibchost.ModuleName,
+		evidencetypes.ModuleName, keyrotationtypes.ModuleName, stakingtypes.ModuleName, ibchost.ModuleName,
 	)
-	app.mm.SetOrderEndBlockers(crisistypes.ModuleName, govtypes.ModuleName, stakingtypes.ModuleName)
+	// the scheduled param changes are applied before the EndBlocker of staking so that the
+	// changes of the staking params take effect at the block they are applied at
+	app.mm.SetOrderEndBlockers(crisistypes.ModuleName, govtypes.ModuleName, paramscheduletypes.ModuleName, stakingtypes.ModuleName)
 
 	// NOTE: The genutils module must occur after staking so that pools are
 	// properly initialized with tokens from genesis accounts.
@@ -406,12 +502,27 @@ func NewLinkApp(
 		slashingtypes.ModuleName, govtypes.ModuleName, minttypes.ModuleName, crisistypes.ModuleName,
 		ibchost.ModuleName, genutiltypes.ModuleName, evidencetypes.ModuleName, ibctransfertypes.ModuleName,
 		// wasm after ibc transfer
-		wasm.ModuleName,
+		wasm.ModuleName, audittypes.ModuleName, paramscheduletypes.ModuleName, keyrotationtypes.ModuleName,
 	)
 
 	app.mm.RegisterInvariants(&app.CrisisKeeper)
+	var msgServer gogogrpc.Server = app.MsgServiceRouter()
+	if app.tracer != nil {
+		app.SetRouter(app.tracer.Router(app.Router()))
+		msgServer = app.tracer.MsgServer(msgServer)
+	}
+	if app.metrics != nil {
+		app.SetRouter(app.metrics.Router(app.Router(), app.contractCodeID))
+		msgServer = app.metrics.MsgServer(msgServer, app.contractCodeID)
+	}
 	app.mm.RegisterRoutes(app.Router(), app.QueryRouter(), encodingConfig.Amino)
-	app.mm.RegisterServices(module.NewConfigurator(app.MsgServiceRouter(), app.GRPCQueryRouter()))
+	app.mm.RegisterServices(module.NewConfigurator(msgServer, app.GRPCQueryRouter()))
+	if app.metrics != nil {
+		app.metrics.InstrumentModules(app.mm)
+	}
+	if app.tracer != nil {
+		app.tracer.InstrumentModules(app.mm)
+	}
 
 	// create the simulation manager and define the order of the modules for deterministic simulations
 	//
@@ -442,14 +553,34 @@ func NewLinkApp(
 	// initialize BaseApp
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
-	app.SetAnteHandler(
-		ante.NewAnteHandler(
-			app.AccountKeeper, app.BankKeeper, ante.DefaultSigVerificationGasConsumer,
-			encodingConfig.TxConfig.SignModeHandler(),
-		),
+	anteHandler := ante.NewAnteHandler(
+		app.AccountKeeper, app.BankKeeper, ante.DefaultSigVerificationGasConsumer,
+		encodingConfig.TxConfig.SignModeHandler(),
 	)
+	if app.metrics != nil {
+		anteHandler = app.metrics.AnteHandler(anteHandler)
+	}
+	app.SetAnteHandler(anteHandler)
 	app.SetEndBlocker(app.EndBlocker)
 
+	if app.healthConfig, err = health.ReadConfig(appOpts); err != nil {
+		panic("error while reading health config: " + err.Error())
+	}
+
+	indexerConfig, err := indexer.ReadConfig(appOpts)
+	if err != nil {
+		panic("error while reading indexer config: " + err.Error())
+	}
+	if indexerConfig.Enable {
+		indexerDB, err := indexer.Open(indexerConfig.DBPath(homePath))
+		if err != nil {
+			panic(err)
+		}
+		app.indexer = indexer.NewIndexer(indexerDB, encodingConfig.TxConfig.TxDecoder(), appCodec)
+	}
+
+	app.registerUpgrade()
+
 	if loadLatest {
 		if err := app.LoadLatestVersion(); err != nil {
 			ostos.Exit(err.Error())
@@ -469,6 +600,7 @@ func NewLinkApp(
 		if err := app.WasmKeeper.InitializePinnedCodes(ctx); err != nil {
 			panic(err)
 		}
+		app.wasmVMCacheInitialized = true
 	}
 	app.ScopedIBCKeeper = scopedIBCKeeper
 	app.ScopedTransferKeeper = scopedTransferKeeper
@@ -490,16 +622,25 @@ func (app *LinkApp) Name() string { return app.BaseApp.Name() }
 
 // BeginBlocker application updates every begin block
 func (app *LinkApp) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	if app.tracer != nil {
+		defer app.tracer.End(app.tracer.Start("BeginBlocker"))
+	}
 	return app.mm.BeginBlock(ctx, req)
 }
 
 // EndBlocker application updates every end block
 func (app *LinkApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	if app.tracer != nil {
+		defer app.tracer.End(app.tracer.Start("EndBlocker"))
+	}
 	return app.mm.EndBlock(ctx, req)
 }
 
 // InitChainer application update at chain initialization
 func (app *LinkApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+	if app.tracer != nil {
+		defer app.tracer.End(app.tracer.Start("InitChainer"))
+	}
 	var genesisState GenesisState
 	if err := ostjson.Unmarshal(req.AppStateBytes, &genesisState); err != nil {
 		panic(err)
@@ -507,6 +648,159 @@ func (app *LinkApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci
 	return app.mm.InitGenesis(ctx, app.appCodec, genesisState)
 }
 
+// CheckTxSync implements the ABCI interface, observing the latency of CheckTx if the telemetry is enabled.
+func (app *LinkApp) CheckTxSync(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	if app.metrics != nil {
+		defer app.metrics.ObserveCheckTx(req.Type, time.Now())
+	}
+	return app.BaseApp.CheckTxSync(req)
+}
+
+// CheckTxAsync implements the ABCI interface, observing the latency of CheckTx, including the time
+// the tx waits to be checked, if the telemetry is enabled.
+func (app *LinkApp) CheckTxAsync(req abci.RequestCheckTx, callback abci.CheckTxCallback) {
+	if app.metrics == nil {
+		app.BaseApp.CheckTxAsync(req, callback)
+		return
+	}
+	start := time.Now()
+	app.BaseApp.CheckTxAsync(req, func(res abci.ResponseCheckTx) {
+		app.metrics.ObserveCheckTx(req.Type, start)
+		callback(res)
+	})
+}
+
+// InitChain implements the ABCI interface, recording the genesis state changes and tracing the genesis if enabled.
+func (app *LinkApp) InitChain(req abci.RequestInitChain) abci.ResponseInitChain {
+	if app.tracer != nil {
+		app.tracer.StartTrace("InitChain")
+		defer app.tracer.EndTrace()
+	}
+	if app.stateListener == nil {
+		return app.BaseApp.InitChain(req)
+	}
+	app.stateListener.InitChain(req.InitialHeight)
+	defer app.stateListener.Branched()
+	return app.BaseApp.InitChain(req)
+}
+
+// BeginBlock implements the ABCI interface, recording the state changes, indexing and tracing the block if enabled.
+func (app *LinkApp) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	if app.tracer != nil {
+		// the trace of the block ends when it is committed
+		app.tracer.StartBlock(req.Header.Height)
+	}
+	if app.stateListener != nil {
+		app.stateListener.BeginBlock(req.Header.Height)
+		defer app.stateListener.Branched()
+	}
+	res := app.BaseApp.BeginBlock(req)
+	if app.indexer != nil {
+		app.indexer.BeginBlock(req, res)
+	}
+	return res
+}
+
+// DeliverTx implements the ABCI interface, recording the state changes, indexing and tracing the tx if enabled.
+func (app *LinkApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliverTx) {
+	if app.stateListener != nil {
+		app.stateListener.DeliverTx()
+	}
+	if app.tracer != nil {
+		span := app.tracer.StartTx(req.Tx)
+		defer func() { app.tracer.EndTx(span, res) }()
+	}
+	res = app.BaseApp.DeliverTx(req)
+	if app.indexer != nil {
+		app.indexer.DeliverTx(req, res)
+	}
+	return res
+}
+
+// EndBlock implements the ABCI interface, recording the state changes and indexing the events if enabled.
+func (app *LinkApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
+	if app.stateListener != nil {
+		app.stateListener.EndBlock()
+	}
+	res := app.BaseApp.EndBlock(req)
+	if app.indexer != nil {
+		app.indexer.EndBlock(res)
+	}
+	return res
+}
+
+// Commit implements the ABCI interface. If the streaming is enabled, the state changes of the block
+// are written before the block is committed; the node halts if they cannot be written. If the
+// indexer is enabled, the block is indexed once committed; the node keeps running if it cannot be,
+// and the block and the following ones are indexed by CatchUpIndexer when the node restarts.
+// If the tracing is enabled, the trace of the block ends with the commit.
+func (app *LinkApp) Commit() abci.ResponseCommit {
+	if app.stateListener != nil {
+		if err := app.stateListener.Commit(); err != nil {
+			panic(fmt.Errorf("failed to write the state changes of the block: %w", err))
+		}
+	}
+	if app.tracer != nil {
+		// the span of the commit ends with the trace of the block
+		app.tracer.Start("Commit")
+		defer app.tracer.EndTrace()
+	}
+	res := app.BaseApp.Commit()
+	if app.indexer != nil {
+		if err := app.indexer.Commit(); err != nil {
+			app.Logger().Error("failed to index the block", "err", err)
+		}
+	}
+	return res
+}
+
+// CatchUpIndexer indexes the blocks committed but not indexed, e.g. as the node stopped before
+// indexing them, from the block store, if the indexer is enabled. It must be called once the
+// latest version is loaded, before the node starts.
+func (app *LinkApp) CatchUpIndexer(store indexer.BlockStore) error {
+	if app.indexer == nil {
+		return nil
+	}
+	return app.indexer.CatchUp(store, app.LastBlockHeight())
+}
+
+// IndexerEnabled returns whether the indexer is enabled.
+func (app *LinkApp) IndexerEnabled() bool {
+	return app.indexer != nil
+}
+
+// Close exports the spans of the tracer not exported yet and stops it, and closes the database
+// of the indexer. It must be called once the node has stopped, as the app can't trace or index
+// the blocks anymore.
+func (app *LinkApp) Close() error {
+	var errs []string
+	if app.tracer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+		defer cancel()
+		if err := app.tracer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to shut the tracer down: %s", err))
+		}
+	}
+	if app.indexer != nil {
+		if err := app.indexer.DB().Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to close the indexer database: %s", err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// contractCodeID returns the code ID of the contract for the metrics of the wasm executions.
+func (app *LinkApp) contractCodeID(ctx sdk.Context, contract string) (uint64, bool) {
+	info := app.WasmKeeper.GetContractInfo(ctx, sdk.AccAddress(contract))
+	if info == nil {
+		return 0, false
+	}
+	return info.CodeID, true
+}
+
 // LoadHeight loads a particular height
 func (app *LinkApp) LoadHeight(height int64) error {
 	return app.LoadVersion(height)
@@ -561,6 +855,11 @@ func (app *LinkApp) GetKey(storeKey string) *sdk.KVStoreKey {
 	return app.keys[storeKey]
 }
 
+// GetKeys returns the KVStoreKeys of all the persistent stores of the app.
+func (app *LinkApp) GetKeys() map[string]*sdk.KVStoreKey {
+	return app.keys
+}
+
 // GetMemKey returns the MemStoreKey for the provided mem key.
 //
 // NOTE: This is solely used for testing purposes.
@@ -592,11 +891,21 @@ func (app *LinkApp) RegisterAPIRoutes(apiSvr *api.Server, apiConfig config.APICo
 	authtx.RegisterGRPCGatewayRoutes(clientCtx, apiSvr.GRPCGatewayRouter)
 	// Register new tendermint queries routes from grpc-gateway.
 	tmservice.RegisterGRPCGatewayRoutes(clientCtx, apiSvr.GRPCGatewayRouter)
+	// Register the account overview route from grpc-gateway.
+	overview.RegisterGRPCGatewayRoutes(clientCtx, apiSvr.GRPCGatewayRouter)
 
 	// Register legacy and grpc-gateway routes for all modules.
 	ModuleBasics.RegisterRESTRoutes(clientCtx, apiSvr.Router)
 	ModuleBasics.RegisterGRPCGatewayRoutes(clientCtx, apiSvr.GRPCGatewayRouter)
 
+	// Register the health and readiness probes.
+	health.RegisterRoutes(clientCtx, apiSvr.Router, app.healthConfig, func() bool { return app.wasmVMCacheInitialized })
+	// Register the websocket event gateway.
+	events.RegisterRoutes(clientCtx, apiSvr.Router, apiConfig.EnableUnsafeCORS)
+	if app.indexer != nil {
+		indexer.RegisterRoutes(apiSvr.Router, app.indexer.DB())
+	}
+
 	// register swagger API from root so that other applications can override easily
 	if apiConfig.Swagger {
 		RegisterSwaggerAPI(apiSvr.Router)
@@ -606,6 +915,8 @@ func (app *LinkApp) RegisterAPIRoutes(apiSvr *api.Server, apiConfig config.APICo
 // RegisterTxService implements the Application.RegisterTxService method.
 func (app *LinkApp) RegisterTxService(clientCtx client.Context) {
 	authtx.RegisterTxService(app.BaseApp.GRPCQueryRouter(), clientCtx, app.BaseApp.Simulate, app.interfaceRegistry)
+	overview.RegisterAccountOverviewService(app.BaseApp.GRPCQueryRouter(), app.AccountKeeper, app.BankKeeper,
+		stakingkeeper.Querier{Keeper: app.StakingKeeper}, app.DistrKeeper)
 }
 
 // RegisterTendermintService implements the Application.RegisterTendermintService method.
@@ -613,9 +924,10 @@ func (app *LinkApp) RegisterTendermintService(clientCtx client.Context) {
 	tmservice.RegisterTendermintService(app.BaseApp.GRPCQueryRouter(), clientCtx, app.interfaceRegistry)
 }
 
-// RegisterSwaggerAPI registers swagger route with API Server
+// RegisterSwaggerAPI registers swagger route with API Server. The spec is generated from the
+// query services of the modules in ModuleBasics and of the app by `make proto-swagger-gen`.
 func RegisterSwaggerAPI(rtr *mux.Router) {
-	statikFS, err := fs.New()
+	statikFS, err := fs.NewWithNamespace(statik.Lfb)
 	if err != nil {
 		panic(err)
 	}
@@ -648,6 +960,7 @@ func initParamsKeeper(appCodec codec.BinaryMarshaler, legacyAmino *codec.LegacyA
 	paramsKeeper.Subspace(ibctransfertypes.ModuleName)
 	paramsKeeper.Subspace(ibchost.ModuleName)
 	paramsKeeper.Subspace(wasm.ModuleName)
+	paramsKeeper.Subspace(keyrotationtypes.ModuleName)
 
 	return paramsKeeper
 }
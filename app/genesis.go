@@ -2,6 +2,12 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/line/lbm-sdk/codec"
+	"github.com/line/lbm-sdk/types/bech32"
+	authtypes "github.com/line/lbm-sdk/x/auth/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
 )
 
 // The genesis state of the blockchain is represented here as a map of raw json
@@ -18,3 +24,44 @@ func NewDefaultGenesisState() GenesisState {
 	encCfg := MakeEncodingConfig()
 	return ModuleBasics.DefaultGenesis(encCfg.Marshaler)
 }
+
+// Addresses returns the addresses of the accounts and the balances of the genesis state.
+func (gs GenesisState) Addresses(cdc codec.Marshaler) ([]string, error) {
+	authGenState := authtypes.GetGenesisStateFromAppState(cdc, gs)
+	accs, err := authtypes.UnpackAccounts(authGenState.Accounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts from any: %w", err)
+	}
+
+	bankGenState := banktypes.GetGenesisStateFromAppState(cdc, gs)
+	addrs := make([]string, 0, len(accs)+len(bankGenState.Balances))
+	for _, acc := range accs {
+		addrs = append(addrs, acc.GetAddress().String())
+	}
+	for _, balance := range bankGenState.Balances {
+		addrs = append(addrs, balance.Address)
+	}
+	return addrs, nil
+}
+
+// Bech32Prefix returns the bech32 prefix shared by all accounts of the genesis
+// state, or an empty string if it has no accounts.
+func (gs GenesisState) Bech32Prefix(cdc codec.Marshaler) (string, error) {
+	addrs, err := gs.Addresses(cdc)
+	if err != nil {
+		return "", err
+	}
+
+	var prefix string
+	for _, addr := range addrs {
+		hrp, _, err := bech32.DecodeAndConvert(addr)
+		if err != nil {
+			return "", fmt.Errorf("invalid genesis account address %s: %w", addr, err)
+		}
+		if prefix != "" && hrp != prefix {
+			return "", fmt.Errorf("genesis accounts use more than one bech32 prefix: %s and %s", prefix, hrp)
+		}
+		prefix = hrp
+	}
+	return prefix, nil
+}
@@ -0,0 +1,55 @@
+package indexer
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cast"
+
+	servertypes "github.com/line/lbm-sdk/server/types"
+)
+
+// The keys of the [indexer] section of app.toml.
+const (
+	FlagEnable = "indexer.enable"
+	FlagPath   = "indexer.path"
+)
+
+// Config is the configuration of the indexer.
+type Config struct {
+	// Enable is whether the blocks are indexed.
+	Enable bool
+	// Path is the path of the database; relative to the home directory if not absolute.
+	Path string
+}
+
+// DefaultConfig returns the default configuration, with the indexer disabled.
+func DefaultConfig() Config {
+	return Config{
+		Path: "data/indexer.db",
+	}
+}
+
+// ReadConfig reads the configuration from app.toml, falling back to the defaults.
+func ReadConfig(opts servertypes.AppOptions) (Config, error) {
+	cfg := DefaultConfig()
+	var err error
+	if v := opts.Get(FlagEnable); v != nil {
+		if cfg.Enable, err = cast.ToBoolE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagPath); v != nil {
+		if cfg.Path, err = cast.ToStringE(v); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// DBPath returns the path of the database of the node at the home directory.
+func (c Config) DBPath(homePath string) string {
+	if filepath.IsAbs(c.Path) {
+		return c.Path
+	}
+	return filepath.Join(homePath, c.Path)
+}
@@ -0,0 +1,326 @@
+// Package indexer indexes the blocks, txs, messages, events and attributes of the node into
+// an embedded SQLite database, for the range queries and joins the KV indexer of ostracon
+// cannot do, e.g. all the sends above an amount between two dates.
+//
+// The indexer is configured in the [indexer] section of app.toml:
+//
+//	[indexer]
+//	# whether the blocks are indexed
+//	enable = false
+//	# the path of the database, relative to the home directory
+//	path = "data/indexer.db"
+//
+// The blocks are indexed when they are committed, from the height the indexer is enabled at.
+// The indexed heights have no gap: a block that cannot be indexed, e.g. as the node stopped
+// before indexing it or as the database failed, stops the indexing of the following blocks,
+// until the node is restarted and CatchUp indexes the missing blocks from the block store.
+// The results of the queries have the last indexed height.
+//
+// See Schema for the tables and Queries for the canned queries, which are served by the API
+// server and by the 'query sql' command.
+package indexer
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/line/ostracon/abci/types"
+	ocstate "github.com/line/ostracon/proto/ostracon/state"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	octypes "github.com/line/ostracon/types"
+
+	"github.com/line/lbm-sdk/codec"
+	sdk "github.com/line/lbm-sdk/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+)
+
+// Indexer collects the data of a block through the ABCI methods and writes it to the database
+// when the block is committed. The ABCI methods are called one at a time, so the indexer is
+// not safe for concurrent use.
+type Indexer struct {
+	db        *sql.DB
+	txDecoder sdk.TxDecoder
+	cdc       codec.JSONMarshaler
+
+	block *block
+}
+
+// block is the data of the block being executed.
+type block struct {
+	header ocproto.Header
+	hash   []byte
+	txs    []txResult
+	events []event
+}
+
+type txResult struct {
+	tx  []byte
+	res abci.ResponseDeliverTx
+}
+
+type event struct {
+	abci.Event
+	// tx is the index of the tx of the event, or -1 for the events of BeginBlock and EndBlock.
+	tx int
+}
+
+// NewIndexer creates an indexer writing to the database.
+func NewIndexer(db *sql.DB, txDecoder sdk.TxDecoder, cdc codec.JSONMarshaler) *Indexer {
+	return &Indexer{
+		db:        db,
+		txDecoder: txDecoder,
+		cdc:       cdc,
+	}
+}
+
+// DB returns the database of the indexer.
+func (idx *Indexer) DB() *sql.DB {
+	return idx.db
+}
+
+// BeginBlock starts collecting the data of the block.
+func (idx *Indexer) BeginBlock(req abci.RequestBeginBlock, res abci.ResponseBeginBlock) {
+	idx.block = &block{header: req.Header, hash: req.Hash}
+	idx.block.addEvents(res.Events, -1)
+}
+
+// DeliverTx collects the tx and its result.
+func (idx *Indexer) DeliverTx(req abci.RequestDeliverTx, res abci.ResponseDeliverTx) {
+	if idx.block == nil {
+		return
+	}
+	idx.block.addEvents(res.Events, len(idx.block.txs))
+	idx.block.txs = append(idx.block.txs, txResult{tx: req.Tx, res: res})
+}
+
+// EndBlock collects the events of EndBlock.
+func (idx *Indexer) EndBlock(res abci.ResponseEndBlock) {
+	if idx.block == nil {
+		return
+	}
+	idx.block.addEvents(res.Events, -1)
+}
+
+// Commit writes the block to the database. The rows of a block that is indexed again,
+// e.g. when it is replayed, are replaced. The block is not written if the block before it is
+// not indexed.
+func (idx *Indexer) Commit() error {
+	b := idx.block
+	idx.block = nil
+	if b == nil {
+		return nil
+	}
+	return idx.writeBlock(b)
+}
+
+// BlockStore is the store of the blocks and of their ABCI results the missing blocks are
+// indexed from.
+type BlockStore interface {
+	LoadBlock(height int64) *octypes.Block
+	LoadABCIResponses(height int64) (*ocstate.ABCIResponses, error)
+}
+
+// CatchUp indexes the blocks after the last indexed height up to the height, which is the
+// height of the last block committed by the app, from the block store. Nothing is indexed if
+// no block is, as the indexing starts with the next block.
+func (idx *Indexer) CatchUp(store BlockStore, height int64) error {
+	last, err := idx.LastHeight()
+	if err != nil {
+		return err
+	}
+	if last == 0 {
+		return nil
+	}
+	for h := last + 1; h <= height; h++ {
+		blk := store.LoadBlock(h)
+		if blk == nil {
+			return fmt.Errorf("the block at height %d is not in the block store", h)
+		}
+		res, err := store.LoadABCIResponses(h)
+		if err != nil {
+			return fmt.Errorf("failed to load the results of the block at height %d: %w", h, err)
+		}
+		if len(res.DeliverTxs) != len(blk.Txs) {
+			return fmt.Errorf("the block at height %d has %d txs but %d results", h, len(blk.Txs), len(res.DeliverTxs))
+		}
+
+		b := &block{header: *blk.Header.ToProto(), hash: blk.Hash()}
+		if res.BeginBlock != nil {
+			b.addEvents(res.BeginBlock.Events, -1)
+		}
+		for i, tx := range blk.Txs {
+			b.addEvents(res.DeliverTxs[i].Events, i)
+			b.txs = append(b.txs, txResult{tx: tx, res: *res.DeliverTxs[i]})
+		}
+		if res.EndBlock != nil {
+			b.addEvents(res.EndBlock.Events, -1)
+		}
+		if err := idx.writeBlock(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LastHeight returns the last indexed height; 0 if no block is indexed.
+func (idx *Indexer) LastHeight() (int64, error) {
+	return lastHeight(idx.db)
+}
+
+func lastHeight(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}) (int64, error) {
+	var height int64
+	err := q.QueryRow("SELECT COALESCE(MAX(height), 0) FROM blocks").Scan(&height)
+	return height, err
+}
+
+func (idx *Indexer) writeBlock(b *block) error {
+	dbTx, err := idx.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := idx.write(dbTx, b); err != nil {
+		_ = dbTx.Rollback()
+		return fmt.Errorf("failed to index the block at height %d: %w", b.header.Height, err)
+	}
+	return dbTx.Commit()
+}
+
+func (b *block) addEvents(events []abci.Event, tx int) {
+	for _, ev := range events {
+		b.events = append(b.events, event{Event: ev, tx: tx})
+	}
+}
+
+func (idx *Indexer) write(dbTx *sql.Tx, b *block) error {
+	height := b.header.Height
+	last, err := lastHeight(dbTx)
+	if err != nil {
+		return err
+	}
+	if last != 0 && height > last+1 {
+		return fmt.Errorf("the blocks from height %d on are not indexed; restart the node to index them", last+1)
+	}
+
+	for _, table := range tables {
+		if _, err := dbTx.Exec("DELETE FROM "+table+" WHERE height = ?", height); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dbTx.Exec(
+		"INSERT INTO blocks (height, hash, time, proposer, num_txs) VALUES (?, ?, ?, ?, ?)",
+		height, fmt.Sprintf("%X", b.hash), b.header.Time.UTC(),
+		sdk.BytesToConsAddress(b.header.ProposerAddress).String(), len(b.txs),
+	); err != nil {
+		return err
+	}
+
+	for i, t := range b.txs {
+		if err := idx.writeTx(dbTx, height, i, t); err != nil {
+			return err
+		}
+	}
+
+	for i, ev := range b.events {
+		var txIndex interface{}
+		if ev.tx >= 0 {
+			txIndex = ev.tx
+		}
+		if _, err := dbTx.Exec(
+			"INSERT INTO events (height, event_index, tx_index, type) VALUES (?, ?, ?, ?)",
+			height, i, txIndex, ev.Type,
+		); err != nil {
+			return err
+		}
+		for j, attr := range ev.Attributes {
+			if _, err := dbTx.Exec(
+				"INSERT INTO attributes (height, event_index, attr_index, key, value) VALUES (?, ?, ?, ?, ?)",
+				height, i, j, string(attr.Key), string(attr.Value),
+			); err != nil {
+				return err
+			}
+		}
+		if ev.Type == banktypes.EventTypeTransfer {
+			if err := writeTransfer(dbTx, height, i, txIndex, ev.Event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (idx *Indexer) writeTx(dbTx *sql.Tx, height int64, index int, t txResult) error {
+	// a tx that cannot be decoded is indexed without its memo, fee and messages
+	var memo, fee string
+	var msgs []sdk.Msg
+	if tx, err := idx.txDecoder(t.tx); err == nil {
+		if memoTx, ok := tx.(sdk.TxWithMemo); ok {
+			memo = memoTx.GetMemo()
+		}
+		if feeTx, ok := tx.(sdk.FeeTx); ok {
+			fee = feeTx.GetFee().String()
+		}
+		msgs = tx.GetMsgs()
+	}
+
+	if _, err := dbTx.Exec(
+		`INSERT INTO txs (height, tx_index, hash, code, codespace, log, gas_wanted, gas_used, memo, fee)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		height, index, fmt.Sprintf("%X", octypes.Tx(t.tx).Hash()), t.res.Code, t.res.Codespace, t.res.Log,
+		t.res.GasWanted, t.res.GasUsed, memo, fee,
+	); err != nil {
+		return err
+	}
+
+	for i, msg := range msgs {
+		var signer string
+		if signers := msg.GetSigners(); len(signers) > 0 {
+			signer = signers[0].String()
+		}
+		body, err := idx.cdc.MarshalJSON(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := dbTx.Exec(
+			"INSERT INTO messages (height, tx_index, msg_index, type_url, signer, body) VALUES (?, ?, ?, ?, ?, ?)",
+			height, index, i, "/"+proto.MessageName(msg), signer, string(body),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTransfer writes a row for every coin of the transfer event.
+func writeTransfer(dbTx *sql.Tx, height int64, eventIndex int, txIndex interface{}, ev abci.Event) error {
+	var sender, recipient, amount string
+	for _, attr := range ev.Attributes {
+		switch string(attr.Key) {
+		case banktypes.AttributeKeySender:
+			sender = string(attr.Value)
+		case banktypes.AttributeKeyRecipient:
+			recipient = string(attr.Value)
+		case sdk.AttributeKeyAmount:
+			amount = string(attr.Value)
+		}
+	}
+	coins, err := sdk.ParseCoinsNormalized(strings.TrimSpace(amount))
+	if err != nil {
+		// the event is still in the events table
+		return nil
+	}
+	for _, coin := range coins {
+		if _, err := dbTx.Exec(
+			`INSERT INTO transfers (height, event_index, tx_index, sender, recipient, denom, amount)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			height, eventIndex, txIndex, sender, recipient, coin.Denom, padAmount(coin.Amount.String()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	ocstate "github.com/line/ostracon/proto/ostracon/state"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	octypes "github.com/line/ostracon/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	sdk "github.com/line/lbm-sdk/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+
+	link "github.com/line/lfb/app"
+	"github.com/line/lfb/app/indexer"
+)
+
+var (
+	encCfg = link.MakeEncodingConfig()
+	start  = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	alice    = newAddress()
+	bob      = newAddress()
+	carol    = newAddress()
+	contract = newAddress()
+)
+
+func newAddress() sdk.AccAddress {
+	return sdk.BytesToAccAddress(secp256k1.GenPrivKey().PubKey().Address())
+}
+
+// testBlock is a block and its ABCI results.
+type testBlock struct {
+	height int64
+	txs    [][]byte
+	res    ocstate.ABCIResponses
+}
+
+func newBlock(height int64) *testBlock {
+	return &testBlock{
+		height: height,
+		res: ocstate.ABCIResponses{
+			BeginBlock: &abci.ResponseBeginBlock{},
+			EndBlock:   &abci.ResponseEndBlock{},
+		},
+	}
+}
+
+func (b *testBlock) time() time.Time {
+	return start.Add(time.Duration(b.height) * time.Hour)
+}
+
+func (b *testBlock) header() ocproto.Header {
+	return ocproto.Header{Height: b.height, Time: b.time()}
+}
+
+func (b *testBlock) addTx(t *testing.T, msg sdk.Msg, memo string, code uint32, events ...abci.Event) {
+	builder := encCfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(msg))
+	builder.SetMemo(memo)
+	builder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("stake", 10)))
+	tx, err := encCfg.TxConfig.TxEncoder()(builder.GetTx())
+	require.NoError(t, err)
+	b.txs = append(b.txs, tx)
+	b.res.DeliverTxs = append(b.res.DeliverTxs, &abci.ResponseDeliverTx{Code: code, GasUsed: 1000, Events: events})
+}
+
+// index indexes the block through the ABCI methods.
+func (b *testBlock) index(idx *indexer.Indexer) error {
+	idx.BeginBlock(abci.RequestBeginBlock{Header: b.header()}, *b.res.BeginBlock)
+	for i, tx := range b.txs {
+		idx.DeliverTx(abci.RequestDeliverTx{Tx: tx}, *b.res.DeliverTxs[i])
+	}
+	idx.EndBlock(*b.res.EndBlock)
+	return idx.Commit()
+}
+
+func event(typ string, kvs ...string) abci.Event {
+	ev := abci.Event{Type: typ}
+	for i := 0; i < len(kvs); i += 2 {
+		ev.Attributes = append(ev.Attributes, abci.EventAttribute{Key: []byte(kvs[i]), Value: []byte(kvs[i+1])})
+	}
+	return ev
+}
+
+func transfer(from, to sdk.AccAddress, amount string) abci.Event {
+	return event(banktypes.EventTypeTransfer,
+		banktypes.AttributeKeyRecipient, to.String(), banktypes.AttributeKeySender, from.String(), sdk.AttributeKeyAmount, amount)
+}
+
+func txHash(tx []byte) string {
+	return fmt.Sprintf("%X", octypes.Tx(tx).Hash())
+}
+
+func newIndexer(t *testing.T) *indexer.Indexer {
+	db, err := indexer.Open(filepath.Join(t.TempDir(), "indexer.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return indexer.NewIndexer(db, encCfg.TxConfig.TxDecoder(), encCfg.Marshaler)
+}
+
+// testBlocks returns three blocks of sends, among which amounts over int64, and of a contract
+// execution.
+func testBlocks(t *testing.T) []*testBlock {
+	b1 := newBlock(1)
+	b1.addTx(t, banktypes.NewMsgSend(alice, bob, sdk.NewCoins(sdk.NewInt64Coin("stake", 100))), "first", 0,
+		transfer(alice, bob, "100stake"))
+
+	b2 := newBlock(2)
+	b2.addTx(t, banktypes.NewMsgSend(bob, carol, nil), "", 0,
+		transfer(bob, carol, "10000000000000000000000ucony"))
+	b2.addTx(t, &wasmtypes.MsgExecuteContract{Sender: carol.String(), Contract: contract.String(), Msg: []byte("{}")}, "", 0,
+		event("wasm", "contract_address", contract.String(), "action", "enqueue"),
+		transfer(carol, contract, "10000000000000000000001ucony"))
+	// a failed tx has no events
+	b2.addTx(t, banktypes.NewMsgSend(alice, carol, nil), "", 5)
+
+	// the transfer of BeginBlock has no tx
+	b3 := newBlock(3)
+	b3.res.BeginBlock.Events = []abci.Event{transfer(bob, alice, "7stake")}
+	return []*testBlock{b1, b2, b3}
+}
+
+func TestQueries(t *testing.T) {
+	idx := newIndexer(t)
+	blocks := testBlocks(t)
+	for _, b := range blocks {
+		require.NoError(t, b.index(idx))
+	}
+	// a replayed block replaces its rows
+	require.NoError(t, blocks[2].index(idx))
+
+	run := func(name string, params map[string]string) indexer.Result {
+		q, ok := indexer.FindQuery(name)
+		require.True(t, ok, name)
+		res, err := q.Run(context.Background(), idx.DB(), params)
+		require.NoError(t, err, name)
+		require.Equal(t, int64(3), res.Height)
+		return res
+	}
+	// column returns the values of the column of the rows as text
+	column := func(res indexer.Result, name string) []string {
+		for i, c := range res.Columns {
+			if c == name {
+				values := make([]string, len(res.Rows))
+				for j, row := range res.Rows {
+					values[j] = indexer.FormatValue(row[i])
+				}
+				return values
+			}
+		}
+		t.Fatalf("no column %s in %v", name, res.Columns)
+		return nil
+	}
+	hour := func(h int) string {
+		return start.Add(time.Duration(h) * time.Hour).Format(time.RFC3339)
+	}
+
+	// every query is tested
+	tested := map[string]bool{}
+	for _, tc := range []struct {
+		query  string
+		params map[string]string
+		column string
+		values []string
+	}{
+		{"sends", nil, "amount", []string{"100", "10000000000000000000000", "10000000000000000000001", "7"}},
+		{"sends", map[string]string{"denom": "ucony", "min_amount": "10000000000000000000001"}, "amount",
+			[]string{"10000000000000000000001"}},
+		{"sends", map[string]string{"min_amount": "100"}, "amount",
+			[]string{"100", "10000000000000000000000", "10000000000000000000001"}},
+		{"sends", map[string]string{"address": alice.String()}, "height", []string{"1", "3"}},
+		{"sends", map[string]string{"from": hour(2), "to": hour(3)}, "height", []string{"2", "2"}},
+		{"sends", map[string]string{"from": hour(3)}, "tx_hash", []string{""}},
+		{"sends", map[string]string{"limit": "1"}, "height", []string{"1"}},
+		{"account-txs", map[string]string{"address": alice.String()}, "hash",
+			[]string{txHash(blocks[1].txs[2]), txHash(blocks[0].txs[0])}},
+		{"account-txs", map[string]string{"address": alice.String(), "to": hour(2)}, "memo", []string{"first"}},
+		{"account-txs", map[string]string{"address": contract.String()}, "hash", []string{txHash(blocks[1].txs[1])}},
+		{"wasm-events", map[string]string{"contract": contract.String()}, "key", []string{"contract_address", "action"}},
+		{"wasm-events", map[string]string{"contract": contract.String(), "action": "dequeue"}, "key", []string{}},
+		{"wasm-events", map[string]string{"contract": contract.String(), "from": hour(3)}, "key", []string{}},
+		{"contract-sends", map[string]string{"contract": contract.String()}, "amount", []string{"10000000000000000000001"}},
+		{"contract-sends", map[string]string{"contract": contract.String(), "denom": "stake"}, "amount", []string{}},
+		{"msg-types", nil, "type_url", []string{"/lbm.bank.v1.MsgSend", "/lbm.wasm.v1.MsgExecuteContract"}},
+		{"msg-types", nil, "succeeded", []string{"2", "1"}},
+		{"msg-types", map[string]string{"to": hour(2)}, "count", []string{"1"}},
+		{"tx", map[string]string{"hash": txHash(blocks[0].txs[0])}, "signer", []string{alice.String()}},
+	} {
+		tested[tc.query] = true
+		require.Equal(t, tc.values, column(run(tc.query, tc.params), tc.column), "%s %v", tc.query, tc.params)
+	}
+	for _, q := range indexer.Queries {
+		require.True(t, tested[q.Name], q.Name)
+	}
+
+	var paramErr indexer.ParamError
+	q, _ := indexer.FindQuery("account-txs")
+	for _, params := range []map[string]string{
+		{},
+		{"address": alice.String(), "denom": "stake"},
+		{"address": "link1invalid"},
+		{"address": alice.String(), "from": "yesterday"},
+		{"address": alice.String(), "limit": "1001"},
+	} {
+		_, err := q.Run(context.Background(), idx.DB(), params)
+		require.True(t, errors.As(err, &paramErr), "%v: %v", params, err)
+	}
+}
+
+// blockStore is the store of the test blocks.
+type blockStore map[int64]*testBlock
+
+func (s blockStore) LoadBlock(height int64) *octypes.Block {
+	b, ok := s[height]
+	if !ok {
+		return nil
+	}
+	txs := make([]octypes.Tx, len(b.txs))
+	for i, tx := range b.txs {
+		txs[i] = tx
+	}
+	block := octypes.MakeBlock(b.height, txs, nil, nil)
+	block.Time = b.time()
+	return block
+}
+
+func (s blockStore) LoadABCIResponses(height int64) (*ocstate.ABCIResponses, error) {
+	b, ok := s[height]
+	if !ok {
+		return nil, fmt.Errorf("no results at height %d", height)
+	}
+	return &b.res, nil
+}
+
+func TestCatchUp(t *testing.T) {
+	idx := newIndexer(t)
+	blocks := testBlocks(t)
+	store := blockStore{}
+	for _, b := range blocks {
+		store[b.height] = b
+	}
+
+	// the indexing starts with the first block, so there is nothing to catch up
+	require.NoError(t, idx.CatchUp(store, 2))
+	last, err := idx.LastHeight()
+	require.NoError(t, err)
+	require.Zero(t, last)
+
+	// the block 2 is not indexed, e.g. as the node stopped, so the block 3 is not either
+	require.NoError(t, blocks[0].index(idx))
+	require.Error(t, blocks[2].index(idx))
+	last, err = idx.LastHeight()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), last)
+
+	// the missing blocks are indexed from the block store on the restart
+	require.Error(t, idx.CatchUp(store, 4))
+	require.NoError(t, idx.CatchUp(store, 3))
+	last, err = idx.LastHeight()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), last)
+
+	q, _ := indexer.FindQuery("tx")
+	res, err := q.Run(context.Background(), idx.DB(), map[string]string{"hash": txHash(blocks[1].txs[1])})
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 1)
+	q, _ = indexer.FindQuery("sends")
+	res, err = q.Run(context.Background(), idx.DB(), nil)
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 4)
+}
@@ -0,0 +1,320 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+// The kinds of the parameters of the queries.
+const (
+	KindAddress = "address"
+	KindTime    = "time"
+	KindInt     = "int"
+	KindText    = "text"
+)
+
+// The limit of the rows of a query.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Param is a parameter of a query. The parameters that are not given are NULL.
+type Param struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description"`
+}
+
+// Query is a canned query. Its SQL refers to the parameters and the limit of the rows
+// as named parameters, e.g. :address and :limit.
+type Query struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Params      []Param `json:"params"`
+	SQL         string  `json:"-"`
+}
+
+// Result is the result of a query.
+type Result struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	// Height is the last indexed height; the blocks after it are not in the result.
+	Height int64 `json:"height"`
+}
+
+var (
+	paramAddress = Param{Name: "address", Kind: KindAddress, Description: "the account address"}
+	paramFrom    = Param{Name: "from", Kind: KindTime, Description: "the time from which on (inclusive), RFC 3339"}
+	paramTo      = Param{Name: "to", Kind: KindTime, Description: "the time until which (exclusive), RFC 3339"}
+	paramLimit   = Param{Name: "limit", Kind: KindInt,
+		Description: fmt.Sprintf("the maximum number of rows; %d by default, at most %d", DefaultLimit, MaxLimit)}
+)
+
+// Queries are the canned queries.
+var Queries = []Query{
+	{
+		Name:        "sends",
+		Description: "The transfers of coins, optionally of a denom, from an amount on, of an address and between two times.",
+		Params: []Param{
+			{Name: "denom", Kind: KindText, Description: "the denom of the coins"},
+			{Name: "min_amount", Kind: KindInt, Description: "the minimum amount of the coins"},
+			paramAddress, paramFrom, paramTo, paramLimit,
+		},
+		SQL: `SELECT b.time, t.height, tx.hash AS tx_hash, t.sender, t.recipient, LTRIM(t.amount, '0') AS amount, t.denom
+FROM transfers t
+JOIN blocks b ON b.height = t.height
+LEFT JOIN txs tx ON tx.height = t.height AND tx.tx_index = t.tx_index
+WHERE (:denom IS NULL OR t.denom = :denom)
+  AND (:min_amount IS NULL OR t.amount >= :min_amount)
+  AND (:address IS NULL OR t.sender = :address OR t.recipient = :address)
+  AND (:from IS NULL OR b.time >= :from)
+  AND (:to IS NULL OR b.time < :to)
+ORDER BY t.height, t.event_index
+LIMIT :limit`,
+	},
+	{
+		Name:        "account-txs",
+		Description: "The txs with a message signed by the address or a transfer from or to it, newest first.",
+		Params:      []Param{withRequired(paramAddress), paramFrom, paramTo, paramLimit},
+		SQL: `SELECT b.time, tx.height, tx.hash, tx.code, tx.gas_used, tx.fee, tx.memo
+FROM txs tx
+JOIN blocks b ON b.height = tx.height
+WHERE (EXISTS (SELECT 1 FROM messages m
+        WHERE m.height = tx.height AND m.tx_index = tx.tx_index AND m.signer = :address)
+    OR EXISTS (SELECT 1 FROM transfers t
+        WHERE t.height = tx.height AND t.tx_index = tx.tx_index AND (t.sender = :address OR t.recipient = :address)))
+  AND (:from IS NULL OR b.time >= :from)
+  AND (:to IS NULL OR b.time < :to)
+ORDER BY tx.height DESC, tx.tx_index DESC
+LIMIT :limit`,
+	},
+	{
+		Name:        "wasm-events",
+		Description: "The attributes of the wasm events of the contract, optionally of an action.",
+		Params: []Param{
+			{Name: "contract", Kind: KindAddress, Required: true, Description: "the address of the contract"},
+			{Name: "action", Kind: KindText, Description: "the value of the action attribute of the events"},
+			paramFrom, paramTo, paramLimit,
+		},
+		SQL: `SELECT b.time, e.height, tx.hash AS tx_hash, e.event_index, a.key, a.value
+FROM events e
+JOIN attributes c ON c.height = e.height AND c.event_index = e.event_index
+  AND c.key = 'contract_address' AND c.value = :contract
+JOIN attributes a ON a.height = e.height AND a.event_index = e.event_index
+JOIN blocks b ON b.height = e.height
+LEFT JOIN txs tx ON tx.height = e.height AND tx.tx_index = e.tx_index
+WHERE e.type = 'wasm'
+  AND (:action IS NULL OR EXISTS (SELECT 1 FROM attributes x
+        WHERE x.height = e.height AND x.event_index = e.event_index AND x.key = 'action' AND x.value = :action))
+  AND (:from IS NULL OR b.time >= :from)
+  AND (:to IS NULL OR b.time < :to)
+ORDER BY e.height, e.event_index, a.attr_index
+LIMIT :limit`,
+	},
+	{
+		Name:        "contract-sends",
+		Description: "The transfers of the txs that emitted wasm events of the contract.",
+		Params: []Param{
+			{Name: "contract", Kind: KindAddress, Required: true, Description: "the address of the contract"},
+			{Name: "denom", Kind: KindText, Description: "the denom of the coins"},
+			paramFrom, paramTo, paramLimit,
+		},
+		SQL: `SELECT b.time, t.height, tx.hash AS tx_hash, t.sender, t.recipient, LTRIM(t.amount, '0') AS amount, t.denom
+FROM transfers t
+JOIN blocks b ON b.height = t.height
+JOIN txs tx ON tx.height = t.height AND tx.tx_index = t.tx_index
+WHERE EXISTS (SELECT 1 FROM events e
+    JOIN attributes c ON c.height = e.height AND c.event_index = e.event_index
+    WHERE e.height = t.height AND e.tx_index = t.tx_index AND e.type = 'wasm'
+      AND c.key = 'contract_address' AND c.value = :contract)
+  AND (:denom IS NULL OR t.denom = :denom)
+  AND (:from IS NULL OR b.time >= :from)
+  AND (:to IS NULL OR b.time < :to)
+ORDER BY t.height, t.event_index
+LIMIT :limit`,
+	},
+	{
+		Name:        "msg-types",
+		Description: "The number of messages of every type, and of those in successful txs, between two times.",
+		Params:      []Param{paramFrom, paramTo, paramLimit},
+		SQL: `SELECT m.type_url, COUNT(*) AS count, SUM(CASE WHEN tx.code = 0 THEN 1 ELSE 0 END) AS succeeded
+FROM messages m
+JOIN txs tx ON tx.height = m.height AND tx.tx_index = m.tx_index
+JOIN blocks b ON b.height = m.height
+WHERE (:from IS NULL OR b.time >= :from)
+  AND (:to IS NULL OR b.time < :to)
+GROUP BY m.type_url
+ORDER BY count DESC, m.type_url
+LIMIT :limit`,
+	},
+	{
+		Name:        "tx",
+		Description: "The result and the messages of the tx.",
+		Params: []Param{
+			{Name: "hash", Kind: KindText, Required: true, Description: "the hash of the tx in hex"},
+			paramLimit,
+		},
+		SQL: `SELECT tx.height, tx.tx_index, tx.code, tx.codespace, tx.gas_wanted, tx.gas_used, tx.fee, tx.memo,
+  m.msg_index, m.type_url, m.signer, m.body
+FROM txs tx
+LEFT JOIN messages m ON m.height = tx.height AND m.tx_index = tx.tx_index
+WHERE tx.hash = UPPER(:hash)
+ORDER BY tx.height, m.msg_index
+LIMIT :limit`,
+	},
+}
+
+func withRequired(p Param) Param {
+	p.Required = true
+	return p
+}
+
+// FindQuery returns the canned query of the name.
+func FindQuery(name string) (Query, bool) {
+	for _, q := range Queries {
+		if q.Name == name {
+			return q, true
+		}
+	}
+	return Query{}, false
+}
+
+// ParamError is the error of invalid parameters of a query.
+type ParamError struct {
+	msg string
+}
+
+func (e ParamError) Error() string {
+	return e.msg
+}
+
+func paramErrorf(format string, args ...interface{}) error {
+	return ParamError{msg: fmt.Sprintf(format, args...)}
+}
+
+// args converts the given parameters to the named arguments of the query.
+func (q Query) args(params map[string]string) ([]interface{}, error) {
+	known := map[string]bool{}
+	args := make([]interface{}, 0, len(q.Params))
+	for _, p := range q.Params {
+		known[p.Name] = true
+		value, ok := params[p.Name]
+		if !ok || value == "" {
+			switch {
+			case p.Name == paramLimit.Name:
+				args = append(args, sql.Named(p.Name, DefaultLimit))
+			case p.Required:
+				return nil, paramErrorf("the parameter %s is required", p.Name)
+			default:
+				args = append(args, sql.Named(p.Name, nil))
+			}
+			continue
+		}
+		arg, err := p.convert(value)
+		if err != nil {
+			return nil, paramErrorf("invalid %s: %s", p.Name, err)
+		}
+		args = append(args, sql.Named(p.Name, arg))
+	}
+	for name := range params {
+		if !known[name] {
+			return nil, paramErrorf("unknown parameter %s of the query %s", name, q.Name)
+		}
+	}
+	return args, nil
+}
+
+func (p Param) convert(value string) (interface{}, error) {
+	switch p.Kind {
+	case KindAddress:
+		if err := sdk.ValidateAccAddress(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case KindTime:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+		// the times of the blocks are stored in UTC
+		return t.UTC(), nil
+	case KindInt:
+		// amounts may exceed int64, so they are compared as the zero-padded strings
+		n, ok := sdk.NewIntFromString(value)
+		if !ok || n.IsNegative() {
+			return nil, fmt.Errorf("not a non-negative integer: %s", value)
+		}
+		if p.Name == paramLimit.Name {
+			if n.GT(sdk.NewInt(MaxLimit)) {
+				return nil, fmt.Errorf("at most %d", MaxLimit)
+			}
+			return n.Int64(), nil
+		}
+		return padAmount(n.String()), nil
+	default:
+		return value, nil
+	}
+}
+
+// Run runs the query with the parameters on the database.
+func (q Query) Run(ctx context.Context, db *sql.DB, params map[string]string) (Result, error) {
+	args, err := q.args(params)
+	if err != nil {
+		return Result{}, err
+	}
+	rows, err := db.QueryContext(ctx, q.SQL, args...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer rows.Close()
+
+	res := Result{Rows: [][]interface{}{}}
+	if res.Columns, err = rows.Columns(); err != nil {
+		return Result{}, err
+	}
+	for rows.Next() {
+		row := make([]interface{}, len(res.Columns))
+		ptrs := make([]interface{}, len(row))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return Result{}, err
+		}
+		for i, v := range row {
+			// text is scanned as bytes, which would be encoded as base64
+			if bz, ok := v.([]byte); ok {
+				row[i] = string(bz)
+			}
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return Result{}, err
+	}
+	res.Height, err = lastHeight(db)
+	return res, err
+}
+
+// FormatValue formats a value of a row as text.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
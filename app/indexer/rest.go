@@ -0,0 +1,48 @@
+package indexer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/line/lbm-sdk/types/rest"
+)
+
+// RegisterRoutes registers the routes of the canned queries on the database:
+//
+//	GET /lfb/indexer/v1/queries         the queries and their parameters
+//	GET /lfb/indexer/v1/queries/{name}  the result of the query, with the parameters in the query string
+func RegisterRoutes(r *mux.Router, db *sql.DB) {
+	r.HandleFunc("/lfb/indexer/v1/queries", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, Queries)
+	}).Methods("GET")
+	r.HandleFunc("/lfb/indexer/v1/queries/{name}", func(w http.ResponseWriter, req *http.Request) {
+		q, ok := FindQuery(mux.Vars(req)["name"])
+		if !ok {
+			rest.WriteErrorResponse(w, http.StatusNotFound, "unknown query "+mux.Vars(req)["name"])
+			return
+		}
+		params := map[string]string{}
+		for name, values := range req.URL.Query() {
+			params[name] = values[len(values)-1]
+		}
+		res, err := q.Run(req.Context(), db, params)
+		var paramErr ParamError
+		switch {
+		case errors.As(err, &paramErr):
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case err != nil:
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeJSON(w, res)
+		}
+	}).Methods("GET")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,131 @@
+package indexer
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Schema is the schema of the database. It only uses the types and statements SQLite and
+// Postgres have in common, so the tables can be copied to Postgres as they are.
+//
+// The rows of a block are identified by its height and their position in the block: tx_index
+// is the index of a tx in the block, and event_index the index of an event in the events of
+// the block, in the order of BeginBlock, the txs and EndBlock. The tx_index of the events of
+// BeginBlock and EndBlock is NULL.
+//
+// The amounts of the transfers are text, zero-padded to AmountDigits digits, so that they are
+// compared exactly as text: SQLite stores the integers over int64 as floats.
+const Schema = `
+CREATE TABLE IF NOT EXISTS blocks (
+	height   BIGINT    NOT NULL PRIMARY KEY,
+	hash     TEXT      NOT NULL,
+	time     TIMESTAMP NOT NULL,
+	proposer TEXT      NOT NULL,
+	num_txs  INTEGER   NOT NULL
+);
+CREATE INDEX IF NOT EXISTS blocks_time ON blocks (time);
+
+CREATE TABLE IF NOT EXISTS txs (
+	height     BIGINT  NOT NULL,
+	tx_index   INTEGER NOT NULL,
+	hash       TEXT    NOT NULL,
+	code       INTEGER NOT NULL,
+	codespace  TEXT    NOT NULL,
+	log        TEXT    NOT NULL,
+	gas_wanted BIGINT  NOT NULL,
+	gas_used   BIGINT  NOT NULL,
+	memo       TEXT    NOT NULL,
+	fee        TEXT    NOT NULL,
+	PRIMARY KEY (height, tx_index)
+);
+CREATE INDEX IF NOT EXISTS txs_hash ON txs (hash);
+
+CREATE TABLE IF NOT EXISTS messages (
+	height    BIGINT  NOT NULL,
+	tx_index  INTEGER NOT NULL,
+	msg_index INTEGER NOT NULL,
+	type_url  TEXT    NOT NULL,
+	signer    TEXT    NOT NULL,
+	body      TEXT    NOT NULL,
+	PRIMARY KEY (height, tx_index, msg_index)
+);
+CREATE INDEX IF NOT EXISTS messages_type_url ON messages (type_url);
+CREATE INDEX IF NOT EXISTS messages_signer ON messages (signer);
+
+CREATE TABLE IF NOT EXISTS events (
+	height      BIGINT  NOT NULL,
+	event_index INTEGER NOT NULL,
+	tx_index    INTEGER,
+	type        TEXT    NOT NULL,
+	PRIMARY KEY (height, event_index)
+);
+CREATE INDEX IF NOT EXISTS events_type ON events (type);
+
+CREATE TABLE IF NOT EXISTS attributes (
+	height      BIGINT  NOT NULL,
+	event_index INTEGER NOT NULL,
+	attr_index  INTEGER NOT NULL,
+	key         TEXT    NOT NULL,
+	value       TEXT    NOT NULL,
+	PRIMARY KEY (height, event_index, attr_index)
+);
+CREATE INDEX IF NOT EXISTS attributes_key_value ON attributes (key, value);
+
+CREATE TABLE IF NOT EXISTS transfers (
+	height      BIGINT         NOT NULL,
+	event_index INTEGER        NOT NULL,
+	tx_index    INTEGER,
+	sender      TEXT           NOT NULL,
+	recipient   TEXT           NOT NULL,
+	denom       TEXT           NOT NULL,
+	amount      TEXT           NOT NULL
+);
+CREATE INDEX IF NOT EXISTS transfers_height ON transfers (height, event_index);
+CREATE INDEX IF NOT EXISTS transfers_sender ON transfers (sender);
+CREATE INDEX IF NOT EXISTS transfers_recipient ON transfers (recipient);
+CREATE INDEX IF NOT EXISTS transfers_denom_amount ON transfers (denom, amount);
+`
+
+// AmountDigits is the number of digits of the amounts, which are at most 256 bits.
+const AmountDigits = 78
+
+// padAmount zero-pads the amount to AmountDigits digits.
+func padAmount(amount string) string {
+	if len(amount) >= AmountDigits {
+		return amount
+	}
+	return strings.Repeat("0", AmountDigits-len(amount)) + amount
+}
+
+// tables are the tables of the schema, which all have the column height.
+var tables = []string{"blocks", "txs", "messages", "events", "attributes", "transfers"}
+
+// Open opens the database at the path for indexing, creating it with the schema if it does not exist.
+func Open(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate", path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(Schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the schema: %w", err)
+	}
+	return db, nil
+}
+
+// OpenReadOnly opens the existing database at the path for querying.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro&_busy_timeout=5000", path))
+}
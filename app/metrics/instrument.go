@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	gogogrpc "github.com/gogo/protobuf/grpc"
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/line/ostracon/abci/types"
+	"google.golang.org/grpc"
+
+	sdk "github.com/line/lbm-sdk/types"
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+	"github.com/line/lbm-sdk/types/module"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+)
+
+// CodeIDFunc returns the code ID of the contract, or false if there is no such contract.
+type CodeIDFunc func(ctx sdk.Context, contract string) (uint64, bool)
+
+// Router wraps the router so that the handlers it routes to count the messages and the gas they
+// use, and the gas of the wasm executions per code ID the function returns. Only the messages
+// of DeliverTx are measured; the gas of the ante handler is not part of the gas of a message.
+func (m *Metrics) Router(r sdk.Router, codeID CodeIDFunc) sdk.Router {
+	return router{Router: r, metrics: m, codeID: codeID}
+}
+
+type router struct {
+	sdk.Router
+	metrics *Metrics
+	codeID  CodeIDFunc
+}
+
+func (r router) AddRoute(route sdk.Route) sdk.Router {
+	r.Router.AddRoute(route)
+	return r
+}
+
+func (r router) Route(ctx sdk.Context, path string) sdk.Handler {
+	h := r.Router.Route(ctx, path)
+	if h == nil {
+		return nil
+	}
+	return func(ctx sdk.Context, msg sdk.Msg) (res *sdk.Result, err error) {
+		if ctx.IsCheckTx() {
+			return h(ctx, msg)
+		}
+		start := ctx.GasMeter().GasConsumedToLimit()
+		// the deferred function also observes the messages running out of gas, which panic
+		panicked := true
+		defer func() {
+			r.metrics.observeMsg(ctx, r.codeID, msg, ctx.GasMeter().GasConsumedToLimit()-start, !panicked && err == nil)
+		}()
+		res, err = h(ctx, msg)
+		panicked = false
+		return res, err
+	}
+}
+
+// MsgServer wraps the server the Msg services are registered on so that their handlers count
+// the messages and the gas they use like the handlers of Router.
+func (m *Metrics) MsgServer(s gogogrpc.Server, codeID CodeIDFunc) gogogrpc.Server {
+	return msgServer{Server: s, metrics: m, codeID: codeID}
+}
+
+type msgServer struct {
+	gogogrpc.Server
+	metrics *Metrics
+	codeID  CodeIDFunc
+}
+
+func (s msgServer) RegisterService(sd *grpc.ServiceDesc, handler interface{}) {
+	desc := *sd
+	desc.Methods = make([]grpc.MethodDesc, len(sd.Methods))
+	for i, method := range sd.Methods {
+		h := method.Handler
+		desc.Methods[i] = grpc.MethodDesc{
+			MethodName: method.MethodName,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (res interface{}, err error) {
+				sdkCtx, ok := ctx.Value(sdk.SdkContextKey).(sdk.Context)
+				if !ok || sdkCtx.IsCheckTx() || interceptor == nil {
+					return h(srv, ctx, dec, interceptor)
+				}
+				// the router passes the message to the handler of the interceptor
+				var msg sdk.Msg
+				capture := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+						msg, _ = req.(sdk.Msg)
+						return handler(ctx, req)
+					})
+				}
+				start := sdkCtx.GasMeter().GasConsumedToLimit()
+				panicked := true
+				defer func() {
+					if msg != nil {
+						s.metrics.observeMsg(sdkCtx, s.codeID, msg, sdkCtx.GasMeter().GasConsumedToLimit()-start, !panicked && err == nil)
+					}
+				}()
+				res, err = h(srv, ctx, dec, capture)
+				panicked = false
+				return res, err
+			},
+		}
+	}
+	s.Server.RegisterService(&desc, handler)
+}
+
+func (m *Metrics) observeMsg(ctx sdk.Context, codeID CodeIDFunc, msg sdk.Msg, gasUsed sdk.Gas, ok bool) {
+	typeURL := "/" + proto.MessageName(msg)
+	result := "success"
+	if !ok {
+		result = "failure"
+	}
+	m.Msgs.WithLabelValues(typeURL, result).Inc()
+	m.MsgGasUsed.WithLabelValues(typeURL).Add(float64(gasUsed))
+
+	if exec, isExec := msg.(*wasmtypes.MsgExecuteContract); isExec && codeID != nil {
+		// looking up the contract must not consume the gas of the tx
+		if id, found := codeID(ctx.WithGasMeter(sdk.NewInfiniteGasMeter()), exec.Contract); found {
+			m.WasmExecuteGasUsed.WithLabelValues(strconv.FormatUint(id, 10)).Observe(float64(gasUsed))
+		}
+	}
+}
+
+// AnteHandler wraps the ante handler so that the txs it rejects are counted by the mode of the
+// tx and the codespace and the description of the registered error of the rejection.
+func (m *Metrics) AnteHandler(ah sdk.AnteHandler) sdk.AnteHandler {
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (newCtx sdk.Context, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				// the panics are turned into errors by the recovery of BaseApp
+				rejection := sdkerrors.ErrPanic
+				if _, ok := r.(sdk.ErrorOutOfGas); ok {
+					rejection = sdkerrors.ErrOutOfGas
+				}
+				m.reject(ctx, simulate, rejection)
+				panic(r)
+			}
+		}()
+		newCtx, err = ah(ctx, tx, simulate)
+		if err != nil {
+			m.reject(ctx, simulate, err)
+		}
+		return newCtx, err
+	}
+}
+
+func (m *Metrics) reject(ctx sdk.Context, simulate bool, err error) {
+	var mode string
+	switch {
+	case simulate:
+		mode = "simulate"
+	case ctx.IsReCheckTx():
+		mode = "recheck"
+	case ctx.IsCheckTx():
+		mode = "check"
+	default:
+		mode = "deliver"
+	}
+	codespace, reason := sdkerrors.UndefinedCodespace, "internal"
+	var root *sdkerrors.Error
+	if errors.As(err, &root) {
+		codespace, reason = root.Codespace(), root.Error()
+	}
+	m.AnteRejections.WithLabelValues(mode, codespace, reason).Inc()
+}
+
+// InstrumentModules wraps the modules of the manager so that the durations of their
+// BeginBlockers and EndBlockers are observed.
+func (m *Metrics) InstrumentModules(mm *module.Manager) {
+	for name, mod := range mm.Modules {
+		mm.Modules[name] = timedModule{AppModule: mod, metrics: m, name: name}
+	}
+}
+
+type timedModule struct {
+	module.AppModule
+	metrics *Metrics
+	name    string
+}
+
+func (mod timedModule) BeginBlock(ctx sdk.Context, req abci.RequestBeginBlock) {
+	defer func(start time.Time) {
+		mod.metrics.BeginBlockerDuration.WithLabelValues(mod.name).Observe(time.Since(start).Seconds())
+	}(time.Now())
+	mod.AppModule.BeginBlock(ctx, req)
+}
+
+func (mod timedModule) EndBlock(ctx sdk.Context, req abci.RequestEndBlock) []abci.ValidatorUpdate {
+	defer func(start time.Time) {
+		mod.metrics.EndBlockerDuration.WithLabelValues(mod.name).Observe(time.Since(start).Seconds())
+	}(time.Now())
+	return mod.AppModule.EndBlock(ctx, req)
+}
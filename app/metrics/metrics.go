@@ -0,0 +1,124 @@
+// Package metrics exports the Prometheus metrics of the application: the messages and the gas
+// they use per type URL, the gas of the wasm executions per code ID, the txs the ante handler
+// rejects and why, the durations of the BeginBlockers and EndBlockers of the modules, and the
+// latency of CheckTx.
+//
+// The metrics are enabled with the telemetry of the node in app.toml, and registered on the
+// default registry, which the Prometheus endpoint of the node and the /metrics endpoint of the
+// API server export:
+//
+//	[telemetry]
+//	enabled = true
+//
+// See docs/validators/metrics.md for the metrics and a sample Grafana dashboard.
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	abci "github.com/line/ostracon/abci/types"
+
+	servertypes "github.com/line/lbm-sdk/server/types"
+)
+
+const namespace = "lfb"
+
+// FlagRegisterer is the key of the app options giving the registerer of the metrics instead of the
+// default registry, e.g. a private registry in tests. It cannot be set in app.toml.
+const FlagRegisterer = "telemetry.registerer"
+
+// RegistererFromOptions returns the registerer of the app options, or the default registry.
+func RegistererFromOptions(opts servertypes.AppOptions) prometheus.Registerer {
+	if r, ok := opts.Get(FlagRegisterer).(prometheus.Registerer); ok {
+		return r
+	}
+	return prometheus.DefaultRegisterer
+}
+
+// Metrics are the metrics of the application.
+type Metrics struct {
+	// Msgs is the number of the executed messages by type URL and result.
+	Msgs *prometheus.CounterVec
+	// MsgGasUsed is the gas the handlers of the messages used by type URL.
+	MsgGasUsed *prometheus.CounterVec
+	// WasmExecuteGasUsed is the gas the executions of the contracts used by code ID.
+	WasmExecuteGasUsed *prometheus.HistogramVec
+	// AnteRejections is the number of the txs the ante handler rejected by mode, codespace and reason.
+	AnteRejections *prometheus.CounterVec
+	// BeginBlockerDuration is the duration of the BeginBlockers by module.
+	BeginBlockerDuration *prometheus.HistogramVec
+	// EndBlockerDuration is the duration of the EndBlockers by module.
+	EndBlockerDuration *prometheus.HistogramVec
+	// CheckTxDuration is the latency of CheckTx by type, new or recheck.
+	CheckTxDuration *prometheus.HistogramVec
+}
+
+// New creates the metrics and registers them on the registerer. Metrics already registered,
+// e.g. by another app in the same process, are reused.
+func New(r prometheus.Registerer) *Metrics {
+	blockerBuckets := prometheus.ExponentialBuckets(0.0005, 4, 8)
+	return &Metrics{
+		Msgs: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "msgs_total",
+			Help:      "The number of the executed messages by type URL and result.",
+		}, []string{"type", "result"})).(*prometheus.CounterVec),
+		MsgGasUsed: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "msg_gas_used_total",
+			Help:      "The gas the handlers of the messages used by type URL.",
+		}, []string{"type"})).(*prometheus.CounterVec),
+		WasmExecuteGasUsed: register(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "wasm_execute_gas_used",
+			Help:      "The gas the executions of the contracts used by code ID.",
+			Buckets:   prometheus.ExponentialBuckets(10000, 4, 10),
+		}, []string{"code_id"})).(*prometheus.HistogramVec),
+		AnteRejections: register(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "ante_rejections_total",
+			Help:      "The number of the txs the ante handler rejected by mode, codespace and reason.",
+		}, []string{"mode", "codespace", "reason"})).(*prometheus.CounterVec),
+		BeginBlockerDuration: register(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "begin_blocker_duration_seconds",
+			Help:      "The duration of the BeginBlockers by module.",
+			Buckets:   blockerBuckets,
+		}, []string{"module"})).(*prometheus.HistogramVec),
+		EndBlockerDuration: register(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "end_blocker_duration_seconds",
+			Help:      "The duration of the EndBlockers by module.",
+			Buckets:   blockerBuckets,
+		}, []string{"module"})).(*prometheus.HistogramVec),
+		CheckTxDuration: register(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "check_tx_duration_seconds",
+			Help:      "The latency of CheckTx by type, new or recheck.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		}, []string{"type"})).(*prometheus.HistogramVec),
+	}
+}
+
+func register(r prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+	if err := r.Register(c); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			return registered.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
+
+// ObserveCheckTx observes the latency of a CheckTx that started at the time.
+func (m *Metrics) ObserveCheckTx(typ abci.CheckTxType, start time.Time) {
+	label := "new"
+	if typ == abci.CheckTxType_Recheck {
+		label = "recheck"
+	}
+	m.CheckTxDuration.WithLabelValues(label).Observe(time.Since(start).Seconds())
+}
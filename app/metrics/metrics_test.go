@@ -0,0 +1,104 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	"github.com/line/lbm-sdk/simapp/helpers"
+	sdk "github.com/line/lbm-sdk/types"
+	authtypes "github.com/line/lbm-sdk/x/auth/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+	wasmkeeper "github.com/line/lbm-sdk/x/wasm/keeper"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+
+	link "github.com/line/lfb/app"
+	"github.com/line/lfb/app/metrics"
+)
+
+const chainID = "metrics-test"
+
+type appOptions map[string]interface{}
+
+func (o appOptions) Get(key string) interface{} {
+	return o[key]
+}
+
+// TestMsgMetrics checks the metrics of the messages routed by their legacy route and by their
+// Msg service.
+func TestMsgMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	encCfg := link.MakeEncodingConfig()
+	app := link.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, true, map[int64]bool{}, t.TempDir(), 0, encCfg,
+		appOptions{"telemetry.enabled": true, metrics.FlagRegisterer: registry}, nil)
+
+	priv := secp256k1.GenPrivKey()
+	from := sdk.BytesToAccAddress(priv.PubKey().Address())
+	to := sdk.BytesToAccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1000000))
+	genesis := link.NewDefaultGenesisState()
+	genesis[authtypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(authtypes.NewGenesisState(
+		authtypes.DefaultParams(), authtypes.GenesisAccounts{authtypes.NewBaseAccount(from, nil, 0)}))
+	genesis[banktypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(banktypes.NewGenesisState(
+		banktypes.DefaultParams(), []banktypes.Balance{{Address: from.String(), Coins: coins}}, coins, nil))
+	stateBytes, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{ChainId: chainID, AppStateBytes: stateBytes, ConsensusParams: link.DefaultConsensusParams})
+
+	header := ocproto.Header{ChainID: chainID, Height: 1, Time: time.Now()}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	wasmCode, err := ioutil.ReadFile("../../cli_test/contracts/queue/contract.wasm")
+	require.NoError(t, err)
+	contractKeeper := wasmkeeper.NewDefaultPermissionKeeper(&app.WasmKeeper)
+	ctx := app.BaseApp.NewContext(false, header)
+	codeID, err := contractKeeper.Create(ctx, from, wasmCode, "", "", nil)
+	require.NoError(t, err)
+	contract, _, err := contractKeeper.Instantiate(ctx, codeID, from, "", []byte("{}"), "queue", nil)
+	require.NoError(t, err)
+
+	send := banktypes.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 100)))
+	overspend := banktypes.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 2000000)))
+	execute := &wasmtypes.MsgExecuteContract{Sender: from.String(), Contract: contract.String(), Msg: []byte(`{"enqueue":{"value":1}}`)}
+	for seq, msg := range []sdk.Msg{
+		send,
+		sdk.ServiceMsg{MethodName: "/lbm.bank.v1.Msg/Send", Request: send},
+		sdk.ServiceMsg{MethodName: "/lbm.bank.v1.Msg/Send", Request: overspend},
+		sdk.ServiceMsg{MethodName: "/lbm.wasm.v1.Msg/ExecuteContract", Request: execute},
+	} {
+		tx, err := helpers.GenTx(encCfg.TxConfig, []sdk.Msg{msg}, sdk.NewCoins(), 1000000, chainID, []uint64{0}, []uint64{uint64(seq)}, priv)
+		require.NoError(t, err)
+		bz, err := encCfg.TxConfig.TxEncoder()(tx)
+		require.NoError(t, err)
+		app.DeliverTx(abci.RequestDeliverTx{Tx: bz})
+	}
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	// the metrics of the app are registered on the private registry
+	m := metrics.New(registry)
+	require.Equal(t, float64(2), testutil.ToFloat64(m.Msgs.WithLabelValues("/lbm.bank.v1.MsgSend", "success")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.Msgs.WithLabelValues("/lbm.bank.v1.MsgSend", "failure")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.Msgs.WithLabelValues("/lbm.wasm.v1.MsgExecuteContract", "success")))
+	require.Positive(t, testutil.ToFloat64(m.MsgGasUsed.WithLabelValues("/lbm.bank.v1.MsgSend")))
+	require.Positive(t, testutil.ToFloat64(m.MsgGasUsed.WithLabelValues("/lbm.wasm.v1.MsgExecuteContract")))
+	require.Equal(t, 1, testutil.CollectAndCount(m.WasmExecuteGasUsed, "lfb_wasm_execute_gas_used"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	names := map[string]bool{}
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	require.True(t, names["lfb_begin_blocker_duration_seconds"])
+	require.True(t, names["lfb_end_blocker_duration_seconds"])
+}
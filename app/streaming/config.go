@@ -0,0 +1,74 @@
+package streaming
+
+import (
+	"fmt"
+
+	"github.com/spf13/cast"
+
+	"github.com/line/lbm-sdk/server"
+	servertypes "github.com/line/lbm-sdk/server/types"
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+// The keys of the [streaming] section of app.toml.
+const (
+	FlagEnable = "streaming.enable"
+	FlagDir    = "streaming.dir"
+	FlagKeys   = "streaming.keys"
+)
+
+// Config is the configuration of the state streaming.
+type Config struct {
+	// Enable is whether the state changes are written to files.
+	Enable bool
+	// Dir is the directory of the files; relative to the home directory if not absolute.
+	Dir string
+	// Keys are the store keys of the stores whose changes are written; all the KV stores if empty.
+	Keys []string
+}
+
+// DefaultConfig returns the default configuration, with the streaming disabled.
+func DefaultConfig() Config {
+	return Config{
+		Dir: "data/streaming",
+	}
+}
+
+// ReadConfig reads the configuration from app.toml, falling back to the defaults.
+func ReadConfig(opts servertypes.AppOptions) (Config, error) {
+	cfg := DefaultConfig()
+	var err error
+	if v := opts.Get(FlagEnable); v != nil {
+		if cfg.Enable, err = cast.ToBoolE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagDir); v != nil {
+		if cfg.Dir, err = cast.ToStringE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagKeys); v != nil {
+		if cfg.Keys, err = cast.ToStringSliceE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.Enable {
+		// the snapshots of state sync are taken from the root multistore of the SDK,
+		// which the streaming replaces
+		if v := opts.Get(server.FlagStateSyncSnapshotInterval); v != nil && cast.ToUint64(v) > 0 {
+			return cfg, fmt.Errorf("the streaming cannot be enabled together with state sync snapshots")
+		}
+	}
+	return cfg, nil
+}
+
+// ValidateKeys checks that the configured store keys are keys of the app.
+func (c Config) ValidateKeys(keys map[string]*sdk.KVStoreKey) error {
+	for _, name := range c.Keys {
+		if _, ok := keys[name]; !ok {
+			return fmt.Errorf("unknown store key %q", name)
+		}
+	}
+	return nil
+}
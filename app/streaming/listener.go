@@ -0,0 +1,190 @@
+// Package streaming writes the changes of the state of the app to files, for indexers and
+// other consumers to follow the state without querying the node.
+//
+// Every write and delete of a key of the streamed stores is recorded, with the height of
+// the block, the ABCI method it is made in and the index of the tx that made it. The
+// changes of a block are written to a file of its own, block-<height>.pb, when the block
+// is committed, as StoreKVPair messages each prefixed with its length as a uvarint. The
+// changes of the genesis are in the file of the initial height. Only the changes that are
+// committed are recorded, so there are none of failed txs.
+//
+// The streaming is configured in the [streaming] section of app.toml:
+//
+//	[streaming]
+//	# whether the state changes are written to files
+//	enable = false
+//	# the directory of the files, relative to the home directory
+//	dir = "data/streaming"
+//	# the store keys of the streamed stores, e.g. ["bank", "wasm"]; all if empty
+//	keys = []
+//
+// As the streaming replaces the root multistore of the app, it cannot be enabled together
+// with state sync snapshots.
+package streaming
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/line/lbm-sdk/baseapp"
+	"github.com/line/lbm-sdk/store"
+	"github.com/line/lbm-sdk/store/dbadapter"
+	"github.com/line/lbm-sdk/store/types"
+	tmdb "github.com/line/tm-db/v2"
+	"github.com/line/tm-db/v2/memdb"
+)
+
+// Listener records the state changes of the blocks and writes them to files.
+//
+// The app informs it of the ABCI methods, which the listener attributes the changes to.
+// They are called one at a time, so the listener is not safe for concurrent use.
+type Listener struct {
+	dir  string
+	keys map[string]bool
+	// db is the empty database of the branches of the deliver state.
+	db types.KVStore
+
+	// branching is whether the next branch of the root multistore is the deliver state.
+	branching bool
+	height    int64
+	phase     Phase
+	txIndex   uint32
+	txs       uint32
+	buf       bytes.Buffer
+}
+
+// NewListener creates a listener for the configuration, creating the directory of the files.
+func NewListener(homePath string, cfg Config) (*Listener, error) {
+	dir := cfg.Dir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(homePath, dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	l := &Listener{
+		dir: dir,
+		db:  dbadapter.Store{DB: memdb.NewDB()},
+	}
+	if len(cfg.Keys) > 0 {
+		l.keys = make(map[string]bool, len(cfg.Keys))
+		for _, name := range cfg.Keys {
+			l.keys[name] = true
+		}
+	}
+	return l, nil
+}
+
+// Dir returns the directory of the files.
+func (l *Listener) Dir() string {
+	return l.dir
+}
+
+// StoreOption returns the BaseApp option setting the root multistore of the app on the db
+// to one whose changes are recorded. It must precede the options configuring the
+// multistore, as it replaces it.
+func (l *Listener) StoreOption(db tmdb.DB) func(*baseapp.BaseApp) {
+	return func(bapp *baseapp.BaseApp) {
+		bapp.SetCMS(&commitMultiStore{
+			CommitMultiStore: store.NewCommitMultiStore(db),
+			listener:         l,
+			listened:         map[types.StoreKey]bool{},
+		})
+	}
+}
+
+// streams returns whether the changes of the store are recorded.
+func (l *Listener) streams(name string, typ types.StoreType) bool {
+	if l.keys != nil {
+		return l.keys[name]
+	}
+	return typ == types.StoreTypeIAVL || typ == types.StoreTypeDB
+}
+
+// InitChain starts recording the changes of the genesis, which are committed in the
+// block at the initial height. It must be called before the deliver state is set up.
+func (l *Listener) InitChain(initialHeight int64) {
+	if initialHeight == 0 {
+		initialHeight = 1
+	}
+	l.branching = true
+	l.height = initialHeight
+	l.phase = PhaseInitChain
+}
+
+// BeginBlock starts recording the changes of the block. It must be called before
+// the deliver state is set up.
+func (l *Listener) BeginBlock(height int64) {
+	l.branching = true
+	l.height = height
+	l.phase = PhaseBeginBlock
+	l.txs = 0
+}
+
+// Branched is called once the deliver state is set up, i.e. after InitChain and BeginBlock.
+// The deliver state of InitChain is kept for the first block, so BeginBlock does not always
+// branch the root multistore.
+func (l *Listener) Branched() {
+	l.branching = false
+}
+
+// DeliverTx attributes the following changes to the next tx of the block.
+func (l *Listener) DeliverTx() {
+	l.phase = PhaseDeliverTx
+	l.txIndex = l.txs
+	l.txs++
+}
+
+// EndBlock attributes the following changes to EndBlock.
+func (l *Listener) EndBlock() {
+	l.phase = PhaseEndBlock
+}
+
+// Commit writes the changes of the block to its file. It must be called before the block is
+// committed, so a block whose changes could not be written is executed again on restart.
+func (l *Listener) Commit() error {
+	defer l.buf.Reset()
+	path := filepath.Join(l.dir, FileName(l.height))
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(l.buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// record appends a change to the changes of the block.
+func (l *Listener) record(storeKey string, key, value []byte, delete bool) {
+	pair := StoreKVPair{
+		BlockHeight: l.height,
+		Phase:       l.phase,
+		StoreKey:    storeKey,
+		Delete:      delete,
+		Key:         key,
+		Value:       value,
+	}
+	if l.phase == PhaseDeliverTx {
+		pair.TxIndex = l.txIndex
+	}
+	bz, err := pair.Marshal()
+	if err != nil {
+		panic(fmt.Errorf("failed to marshal the state change: %w", err))
+	}
+	var size [binary.MaxVarintLen64]byte
+	l.buf.Write(size[:binary.PutUvarint(size[:], uint64(len(bz)))])
+	l.buf.Write(bz)
+}
@@ -0,0 +1,123 @@
+package streaming
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	filePrefix = "block-"
+	fileSuffix = ".pb"
+)
+
+// FileName returns the name of the file of the changes of the block at the height.
+func FileName(height int64) string {
+	return fmt.Sprintf("%s%012d%s", filePrefix, height, fileSuffix)
+}
+
+// Decoder reads the length-prefixed changes of a file.
+type Decoder struct {
+	r *bufio.Reader
+}
+
+// NewDecoder returns a decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next change into pair. It returns io.EOF if there are no more changes.
+func (d *Decoder) Decode(pair *StoreKVPair) error {
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+	bz := make([]byte, size)
+	if _, err := io.ReadFull(d.r, bz); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	*pair = StoreKVPair{}
+	return pair.Unmarshal(bz)
+}
+
+// ReadFile reads the changes of a file.
+func ReadFile(path string) ([]StoreKVPair, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pairs []StoreKVPair
+	d := NewDecoder(f)
+	for {
+		var pair StoreKVPair
+		if err := d.Decode(&pair); err == io.EOF {
+			return pairs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		pairs = append(pairs, pair)
+	}
+}
+
+// Heights returns the heights of the blocks whose files are in the directory, in ascending order.
+func Heights(dir string) ([]int64, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var heights []int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		height, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// ReadBlock reads the changes of the block at the height from the directory.
+func ReadBlock(dir string, height int64) ([]StoreKVPair, error) {
+	return ReadFile(filepath.Join(dir, FileName(height)))
+}
+
+// Walk calls fn with the changes of the blocks in the directory from the height on,
+// in the order they were made. It stops at the first error fn returns.
+func Walk(dir string, fromHeight int64, fn func(pair StoreKVPair) error) error {
+	heights, err := Heights(dir)
+	if err != nil {
+		return err
+	}
+	for _, height := range heights {
+		if height < fromHeight {
+			continue
+		}
+		pairs, err := ReadBlock(dir, height)
+		if err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			if err := fn(pair); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package streaming
+
+import (
+	"io"
+
+	"github.com/line/lbm-sdk/store/cachekv"
+	"github.com/line/lbm-sdk/store/cachemulti"
+	"github.com/line/lbm-sdk/store/tracekv"
+	"github.com/line/lbm-sdk/store/types"
+	tmdb "github.com/line/tm-db/v2"
+)
+
+// commitMultiStore is the root multistore of the app. The branch it creates for the
+// deliver state of a block is a deliverMultiStore, whose changes are recorded.
+type commitMultiStore struct {
+	types.CommitMultiStore
+	listener *Listener
+
+	// keys are the keys of the mounted stores, and listened the keys of the stores
+	// whose changes are recorded.
+	keys     []types.StoreKey
+	listened map[types.StoreKey]bool
+
+	traceWriter  io.Writer
+	traceContext types.TraceContext
+}
+
+var _ types.CommitMultiStore = &commitMultiStore{}
+
+func (s *commitMultiStore) MountStoreWithDB(key types.StoreKey, typ types.StoreType, db tmdb.DB) {
+	s.CommitMultiStore.MountStoreWithDB(key, typ, db)
+	s.keys = append(s.keys, key)
+	if s.listener.streams(key.Name(), typ) {
+		s.listened[key] = true
+	}
+}
+
+func (s *commitMultiStore) SetTracer(w io.Writer) types.MultiStore {
+	s.traceWriter = w
+	s.CommitMultiStore.SetTracer(w)
+	return s
+}
+
+func (s *commitMultiStore) SetTracingContext(tc types.TraceContext) types.MultiStore {
+	s.traceContext = tc
+	s.CommitMultiStore.SetTracingContext(tc)
+	return s
+}
+
+// CacheMultiStore branches the multistore, recording the changes of the branch
+// if it is the deliver state of a block.
+func (s *commitMultiStore) CacheMultiStore() types.CacheMultiStore {
+	cms := s.CommitMultiStore.CacheMultiStore()
+	if !s.listener.branching {
+		return cms
+	}
+	s.listener.branching = false
+	return &deliverMultiStore{branch: cms, root: s}
+}
+
+// deliverMultiStore is the deliver state of a block. The changes made to its stores
+// directly, and those written from its branches, e.g. by the txs, are recorded.
+type deliverMultiStore struct {
+	branch
+	root *commitMultiStore
+}
+
+// branch is the branch of the root multistore the deliverMultiStore extends.
+type branch = types.CacheMultiStore
+
+var _ types.CacheMultiStore = &deliverMultiStore{}
+
+func (s *deliverMultiStore) GetStore(key types.StoreKey) types.Store {
+	return s.GetKVStore(key)
+}
+
+func (s *deliverMultiStore) GetKVStore(key types.StoreKey) types.KVStore {
+	store := s.branch.GetKVStore(key)
+	if !s.root.listened[key] {
+		return store
+	}
+	return &listenStore{KVStore: store, name: key.Name(), listener: s.root.listener}
+}
+
+// CacheMultiStore branches the multistore over the listened stores, so the changes
+// of the branch are recorded when it is written.
+func (s *deliverMultiStore) CacheMultiStore() types.CacheMultiStore {
+	stores := make(map[types.StoreKey]types.CacheWrapper, len(s.root.keys))
+	for _, key := range s.root.keys {
+		stores[key] = s.GetKVStore(key)
+	}
+	// the branches merge their tracing context into the given one, so it is copied
+	var tc types.TraceContext
+	if s.root.traceContext != nil {
+		tc = make(types.TraceContext, len(s.root.traceContext))
+		for k, v := range s.root.traceContext {
+			tc[k] = v
+		}
+	}
+	return cachemulti.NewFromKVStore(s.root.listener.db, stores, nil, s.root.traceWriter, tc)
+}
+
+func (s *deliverMultiStore) CacheWrap() types.CacheWrap {
+	return s.CacheMultiStore().(types.CacheWrap)
+}
+
+func (s *deliverMultiStore) CacheWrapWithTrace(_ io.Writer, _ types.TraceContext) types.CacheWrap {
+	return s.CacheWrap()
+}
+
+func (s *deliverMultiStore) SetTracer(w io.Writer) types.MultiStore {
+	s.branch = s.branch.SetTracer(w).(types.CacheMultiStore)
+	return s
+}
+
+func (s *deliverMultiStore) SetTracingContext(tc types.TraceContext) types.MultiStore {
+	s.branch = s.branch.SetTracingContext(tc).(types.CacheMultiStore)
+	return s
+}
+
+// listenStore records the writes and deletes of a store to the listener.
+type listenStore struct {
+	types.KVStore
+	name     string
+	listener *Listener
+}
+
+func (s *listenStore) Set(key, value []byte) {
+	s.KVStore.Set(key, value)
+	s.listener.record(s.name, key, value, false)
+}
+
+func (s *listenStore) Delete(key []byte) {
+	s.KVStore.Delete(key)
+	s.listener.record(s.name, key, nil, true)
+}
+
+func (s *listenStore) CacheWrap() types.CacheWrap {
+	return cachekv.NewStore(s)
+}
+
+func (s *listenStore) CacheWrapWithTrace(w io.Writer, tc types.TraceContext) types.CacheWrap {
+	return cachekv.NewStore(tracekv.NewStore(s, w, tc))
+}
@@ -0,0 +1,631 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: lfb/streaming/v1/streaming.proto
+
+package streaming
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Phase is the ABCI method a state change is made in.
+type Phase int32
+
+const (
+	// PHASE_UNSPECIFIED defines a no-op phase.
+	PhaseUnspecified Phase = 0
+	// PHASE_INIT_CHAIN defines the changes of the genesis, made in InitChain.
+	PhaseInitChain Phase = 1
+	// PHASE_BEGIN_BLOCK defines the changes made in BeginBlock.
+	PhaseBeginBlock Phase = 2
+	// PHASE_DELIVER_TX defines the changes made by a tx in DeliverTx.
+	PhaseDeliverTx Phase = 3
+	// PHASE_END_BLOCK defines the changes made in EndBlock.
+	PhaseEndBlock Phase = 4
+)
+
+var Phase_name = map[int32]string{
+	0: "PHASE_UNSPECIFIED",
+	1: "PHASE_INIT_CHAIN",
+	2: "PHASE_BEGIN_BLOCK",
+	3: "PHASE_DELIVER_TX",
+	4: "PHASE_END_BLOCK",
+}
+
+var Phase_value = map[string]int32{
+	"PHASE_UNSPECIFIED": 0,
+	"PHASE_INIT_CHAIN":  1,
+	"PHASE_BEGIN_BLOCK": 2,
+	"PHASE_DELIVER_TX":  3,
+	"PHASE_END_BLOCK":   4,
+}
+
+func (x Phase) String() string {
+	return proto.EnumName(Phase_name, int32(x))
+}
+
+func (Phase) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e414b580edca7028, []int{0}
+}
+
+// StoreKVPair is a write or delete of a key of a store.
+type StoreKVPair struct {
+	// block_height is the height of the block the change is committed in.
+	BlockHeight int64 `protobuf:"varint,1,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	Phase       Phase `protobuf:"varint,2,opt,name=phase,proto3,enum=lfb.streaming.v1.Phase" json:"phase,omitempty"`
+	// tx_index is the index of the tx in the block for the changes of PHASE_DELIVER_TX.
+	TxIndex  uint32 `protobuf:"varint,3,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	StoreKey string `protobuf:"bytes,4,opt,name=store_key,json=storeKey,proto3" json:"store_key,omitempty"`
+	// delete is whether the key is deleted; value is empty then.
+	Delete bool   `protobuf:"varint,5,opt,name=delete,proto3" json:"delete,omitempty"`
+	Key    []byte `protobuf:"bytes,6,opt,name=key,proto3" json:"key,omitempty"`
+	Value  []byte `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StoreKVPair) Reset()         { *m = StoreKVPair{} }
+func (m *StoreKVPair) String() string { return proto.CompactTextString(m) }
+func (*StoreKVPair) ProtoMessage()    {}
+func (*StoreKVPair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e414b580edca7028, []int{0}
+}
+func (m *StoreKVPair) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StoreKVPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StoreKVPair.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StoreKVPair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreKVPair.Merge(m, src)
+}
+func (m *StoreKVPair) XXX_Size() int {
+	return m.Size()
+}
+func (m *StoreKVPair) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreKVPair.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreKVPair proto.InternalMessageInfo
+
+func (m *StoreKVPair) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *StoreKVPair) GetPhase() Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return PhaseUnspecified
+}
+
+func (m *StoreKVPair) GetTxIndex() uint32 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+func (m *StoreKVPair) GetStoreKey() string {
+	if m != nil {
+		return m.StoreKey
+	}
+	return ""
+}
+
+func (m *StoreKVPair) GetDelete() bool {
+	if m != nil {
+		return m.Delete
+	}
+	return false
+}
+
+func (m *StoreKVPair) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StoreKVPair) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("lfb.streaming.v1.Phase", Phase_name, Phase_value)
+	proto.RegisterType((*StoreKVPair)(nil), "lfb.streaming.v1.StoreKVPair")
+}
+
+func init() { proto.RegisterFile("lfb/streaming/v1/streaming.proto", fileDescriptor_e414b580edca7028) }
+
+var fileDescriptor_e414b580edca7028 = []byte{
+	// 454 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0xcd, 0x6e, 0xd3, 0x40,
+	0x14, 0x85, 0x3d, 0xcd, 0x4f, 0xd3, 0xe9, 0x9f, 0x3b, 0x44, 0x60, 0x8c, 0x64, 0x4d, 0x59, 0x20,
+	0xab, 0x08, 0x5b, 0x85, 0x25, 0xab, 0x3a, 0x31, 0xc4, 0x4a, 0x65, 0x22, 0x27, 0xad, 0x10, 0x1b,
+	0xcb, 0x4e, 0x6e, 0xec, 0x51, 0x5d, 0xdb, 0x72, 0xa6, 0x51, 0xfa, 0x06, 0x28, 0x2b, 0x5e, 0x20,
+	0x2b, 0x5e, 0x86, 0x65, 0x77, 0xb0, 0x44, 0xc9, 0x8b, 0x20, 0x8f, 0x8b, 0x82, 0xd8, 0xdd, 0x73,
+	0xee, 0xa7, 0x73, 0x47, 0xa3, 0x83, 0x69, 0x32, 0x0d, 0xcd, 0x19, 0x2f, 0x20, 0xb8, 0x65, 0x69,
+	0x64, 0xce, 0xcf, 0xb7, 0xc2, 0xc8, 0x8b, 0x8c, 0x67, 0x44, 0x4e, 0xa6, 0xa1, 0xb1, 0x35, 0xe7,
+	0xe7, 0x6a, 0x3b, 0xca, 0xa2, 0x4c, 0x2c, 0xcd, 0x72, 0xaa, 0xb8, 0x97, 0x3f, 0x11, 0xde, 0x1f,
+	0xf2, 0xac, 0x80, 0xfe, 0xf5, 0x20, 0x60, 0x05, 0x39, 0xc5, 0x07, 0x61, 0x92, 0x8d, 0x6f, 0xfc,
+	0x18, 0x58, 0x14, 0x73, 0x05, 0x51, 0xa4, 0xd7, 0xbc, 0x7d, 0xe1, 0xf5, 0x84, 0x45, 0xde, 0xe0,
+	0x46, 0x1e, 0x07, 0x33, 0x50, 0x76, 0x28, 0xd2, 0x8f, 0xde, 0x3e, 0x33, 0xfe, 0x3f, 0x65, 0x0c,
+	0xca, 0xb5, 0x57, 0x51, 0xe4, 0x39, 0x6e, 0xf1, 0x85, 0xcf, 0xd2, 0x09, 0x2c, 0x94, 0x1a, 0x45,
+	0xfa, 0xa1, 0xb7, 0xcb, 0x17, 0x4e, 0x29, 0xc9, 0x0b, 0xbc, 0x37, 0x2b, 0x6f, 0xfb, 0x37, 0x70,
+	0xaf, 0xd4, 0x29, 0xd2, 0xf7, 0xbc, 0x96, 0x30, 0xfa, 0x70, 0x4f, 0x9e, 0xe2, 0xe6, 0x04, 0x12,
+	0xe0, 0xa0, 0x34, 0x28, 0xd2, 0x5b, 0xde, 0xa3, 0x22, 0x32, 0xae, 0x95, 0x78, 0x93, 0x22, 0xfd,
+	0xc0, 0x2b, 0x47, 0xd2, 0xc6, 0x8d, 0x79, 0x90, 0xdc, 0x81, 0xb2, 0x2b, 0xbc, 0x4a, 0x9c, 0xad,
+	0x11, 0x6e, 0x88, 0x87, 0x90, 0xd7, 0xf8, 0x64, 0xd0, 0xbb, 0x18, 0xda, 0xfe, 0x95, 0x3b, 0x1c,
+	0xd8, 0x1d, 0xe7, 0x83, 0x63, 0x77, 0x65, 0x49, 0x6d, 0x2f, 0x57, 0x54, 0x16, 0xc4, 0x55, 0x3a,
+	0xcb, 0x61, 0xcc, 0xa6, 0x0c, 0x26, 0x44, 0xc7, 0x72, 0x05, 0x3b, 0xae, 0x33, 0xf2, 0x3b, 0xbd,
+	0x0b, 0xc7, 0x95, 0x91, 0x4a, 0x96, 0x2b, 0x7a, 0x24, 0x58, 0x27, 0x65, 0xbc, 0x13, 0x07, 0x2c,
+	0x25, 0x67, 0x7f, 0x63, 0x2d, 0xfb, 0xa3, 0xe3, 0xfa, 0xd6, 0xe5, 0xa7, 0x4e, 0x5f, 0xde, 0x51,
+	0x9f, 0x2c, 0x57, 0xf4, 0x58, 0xa0, 0x16, 0x44, 0x2c, 0xb5, 0xca, 0x9f, 0xdb, 0xa6, 0x76, 0xed,
+	0x4b, 0xe7, 0xda, 0xf6, 0xfc, 0xd1, 0x67, 0xb9, 0xf6, 0x4f, 0x6a, 0x17, 0x12, 0x36, 0x87, 0x62,
+	0xb4, 0x20, 0xaf, 0xf0, 0x71, 0x45, 0xda, 0x6e, 0xf7, 0x31, 0xb3, 0xae, 0x9e, 0x2c, 0x57, 0xf4,
+	0x50, 0x80, 0x76, 0x3a, 0x11, 0x89, 0x6a, 0xfd, 0xeb, 0x77, 0x4d, 0xb2, 0xde, 0xff, 0x58, 0x6b,
+	0xe8, 0x61, 0xad, 0xa1, 0xdf, 0x6b, 0x0d, 0x7d, 0xdb, 0x68, 0xd2, 0xc3, 0x46, 0x93, 0x7e, 0x6d,
+	0x34, 0xe9, 0xcb, 0x69, 0xc4, 0x78, 0x7c, 0x17, 0x1a, 0xe3, 0xec, 0xd6, 0x4c, 0x58, 0x0a, 0x66,
+	0x59, 0x99, 0x20, 0xcf, 0xb7, 0x4d, 0x09, 0x9b, 0xa2, 0x02, 0xef, 0xfe, 0x0c, 0x00, 0x70, 0xd1,
+	0x94, 0xa7, 0x4e, 0x02, 0x00, 0x00,
+}
+
+func (m *StoreKVPair) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreKVPair) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StoreKVPair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintStreaming(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintStreaming(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Delete {
+		i--
+		if m.Delete {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.StoreKey) > 0 {
+		i -= len(m.StoreKey)
+		copy(dAtA[i:], m.StoreKey)
+		i = encodeVarintStreaming(dAtA, i, uint64(len(m.StoreKey)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.TxIndex != 0 {
+		i = encodeVarintStreaming(dAtA, i, uint64(m.TxIndex))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Phase != 0 {
+		i = encodeVarintStreaming(dAtA, i, uint64(m.Phase))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.BlockHeight != 0 {
+		i = encodeVarintStreaming(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintStreaming(dAtA []byte, offset int, v uint64) int {
+	offset -= sovStreaming(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *StoreKVPair) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlockHeight != 0 {
+		n += 1 + sovStreaming(uint64(m.BlockHeight))
+	}
+	if m.Phase != 0 {
+		n += 1 + sovStreaming(uint64(m.Phase))
+	}
+	if m.TxIndex != 0 {
+		n += 1 + sovStreaming(uint64(m.TxIndex))
+	}
+	l = len(m.StoreKey)
+	if l > 0 {
+		n += 1 + l + sovStreaming(uint64(l))
+	}
+	if m.Delete {
+		n += 2
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovStreaming(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovStreaming(uint64(l))
+	}
+	return n
+}
+
+func sovStreaming(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozStreaming(x uint64) (n int) {
+	return sovStreaming(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *StoreKVPair) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStreaming
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreKVPair: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreKVPair: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Phase", wireType)
+			}
+			m.Phase = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Phase |= Phase(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxIndex", wireType)
+			}
+			m.TxIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TxIndex |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StoreKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delete", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Delete = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStreaming(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStreaming
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipStreaming(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowStreaming
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowStreaming
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthStreaming
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupStreaming
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthStreaming
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthStreaming        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowStreaming          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupStreaming = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,148 @@
+package streaming_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	"github.com/line/lbm-sdk/simapp"
+	"github.com/line/lbm-sdk/simapp/helpers"
+	sdk "github.com/line/lbm-sdk/types"
+	authtypes "github.com/line/lbm-sdk/x/auth/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+
+	link "github.com/line/lfb/app"
+	"github.com/line/lfb/app/streaming"
+)
+
+const chainID = "streaming-test"
+
+type appOptions map[string]interface{}
+
+func (o appOptions) Get(key string) interface{} {
+	return o[key]
+}
+
+// TestReconstructBankBalances checks that the balances of the bank module can be rebuilt
+// from the streamed state changes alone.
+func TestReconstructBankBalances(t *testing.T) {
+	home := t.TempDir()
+	encCfg := link.MakeEncodingConfig()
+	app := link.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, true, map[int64]bool{}, home, 0, encCfg,
+		appOptions{streaming.FlagEnable: true, streaming.FlagKeys: []string{banktypes.StoreKey}}, nil)
+
+	// the genesis funds three accounts
+	privs := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	var accounts authtypes.GenesisAccounts
+	var balances []banktypes.Balance
+	supply := sdk.NewCoins()
+	for _, priv := range privs {
+		addr := sdk.BytesToAccAddress(priv.PubKey().Address())
+		coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1000000), sdk.NewInt64Coin("ucony", 500))
+		accounts = append(accounts, authtypes.NewBaseAccount(addr, nil, 0))
+		balances = append(balances, banktypes.Balance{Address: addr.String(), Coins: coins})
+		supply = supply.Add(coins...)
+	}
+	genesis := link.NewDefaultGenesisState()
+	genesis[authtypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(authtypes.NewGenesisState(authtypes.DefaultParams(), accounts))
+	genesis[banktypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(
+		banktypes.NewGenesisState(banktypes.DefaultParams(), balances, supply, nil))
+	stateBytes, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{
+		ChainId:         chainID,
+		AppStateBytes:   stateBytes,
+		ConsensusParams: simapp.DefaultConsensusParams,
+	})
+
+	// every block, each account sends to the next, and the last tx fails
+	seqs := make([]uint64, len(privs))
+	now := time.Now()
+	for height := int64(1); height <= 3; height++ {
+		app.BeginBlock(abci.RequestBeginBlock{Header: ocproto.Header{ChainID: chainID, Height: height, Time: now}})
+		for i, priv := range privs {
+			to := sdk.BytesToAccAddress(privs[(i+1)%len(privs)].PubKey().Address())
+			amount := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 100*height+int64(i)))
+			res := deliver(t, app, priv, seqs[i], banktypes.NewMsgSend(sdk.BytesToAccAddress(priv.PubKey().Address()), to, amount))
+			require.True(t, res.IsOK(), res.Log)
+			seqs[i]++
+		}
+		overspend := sdk.NewCoins(sdk.NewInt64Coin("ucony", 1000))
+		res := deliver(t, app, privs[0], seqs[0], banktypes.NewMsgSend(
+			sdk.BytesToAccAddress(privs[0].PubKey().Address()), sdk.BytesToAccAddress(privs[1].PubKey().Address()), overspend))
+		require.False(t, res.IsOK())
+		seqs[0]++ // the ante handler increments the sequence of a failed msg
+		app.EndBlock(abci.RequestEndBlock{Height: height})
+		app.Commit()
+	}
+
+	// rebuild the balances from the stream
+	dir := streaming.DefaultConfig().Dir
+	heights, err := streaming.Heights(home + "/" + dir)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3}, heights)
+
+	rebuilt := map[string]map[string]sdk.Coin{}
+	var txChanges int
+	err = streaming.Walk(home+"/"+dir, 0, func(pair streaming.StoreKVPair) error {
+		require.Equal(t, banktypes.StoreKey, pair.StoreKey)
+		if pair.Phase == streaming.PhaseDeliverTx {
+			require.Less(t, pair.TxIndex, uint32(len(privs)), "the failed tx must not change the state")
+			txChanges++
+		}
+		if !bytes.HasPrefix(pair.Key, banktypes.BalancesPrefix) {
+			return nil
+		}
+		key := string(pair.Key[len(banktypes.BalancesPrefix):])
+		addr := banktypes.AddressFromBalancesStore([]byte(key)).String()
+		denom := key[strings.Index(key, banktypes.AddressDenomDelimiter)+1:]
+		if pair.Delete {
+			delete(rebuilt[addr], denom)
+			return nil
+		}
+		var coin sdk.Coin
+		if err := coin.Unmarshal(pair.Value); err != nil {
+			return err
+		}
+		if rebuilt[addr] == nil {
+			rebuilt[addr] = map[string]sdk.Coin{}
+		}
+		rebuilt[addr][denom] = coin
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotZero(t, txChanges)
+
+	ctx := app.BaseApp.NewContext(true, ocproto.Header{})
+	expected := app.BankKeeper.GetAccountsBalances(ctx)
+	require.NotEmpty(t, expected)
+	var actual []banktypes.Balance
+	for addr, coins := range rebuilt {
+		balance := banktypes.Balance{Address: addr, Coins: sdk.NewCoins()}
+		for _, coin := range coins {
+			balance.Coins = balance.Coins.Add(coin)
+		}
+		if !balance.Coins.Empty() {
+			actual = append(actual, balance)
+		}
+	}
+	require.ElementsMatch(t, expected, actual)
+}
+
+func deliver(t *testing.T, app *link.LinkApp, priv *secp256k1.PrivKey, seq uint64, msg sdk.Msg) abci.ResponseDeliverTx {
+	txCfg := link.MakeEncodingConfig().TxConfig
+	tx, err := helpers.GenTx(txCfg, []sdk.Msg{msg}, sdk.NewCoins(), 200000, chainID, []uint64{0}, []uint64{seq}, priv)
+	require.NoError(t, err)
+	bz, err := txCfg.TxEncoder()(tx)
+	require.NoError(t, err)
+	return app.DeliverTx(abci.RequestDeliverTx{Tx: bz})
+}
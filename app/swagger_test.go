@@ -0,0 +1,67 @@
+package app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/line/ostracon/libs/log"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/server/api"
+	"github.com/line/lbm-sdk/server/config"
+	"github.com/line/lbm-sdk/simapp"
+
+	link "github.com/line/lfb/app"
+)
+
+// TestSwaggerRoutesServedByGateway checks that every route of the swagger spec served by
+// the API server is served by its gRPC gateway.
+func TestSwaggerRoutesServedByGateway(t *testing.T) {
+	app := link.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, true, map[int64]bool{}, t.TempDir(), 0,
+		link.MakeEncodingConfig(), simapp.EmptyAppOptions{}, nil)
+	apiSvr := api.New(client.Context{}, log.NewNopLogger())
+	app.RegisterAPIRoutes(apiSvr, config.APIConfig{Swagger: true})
+
+	rec := httptest.NewRecorder()
+	apiSvr.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/swagger.yaml", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var spec struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(rec.Body.Bytes(), &spec))
+	require.NotEmpty(t, spec.Paths)
+
+	// the client has no node, so a served route fails in its handler, while the gateway
+	// answers a route it does not serve as unimplemented
+	var unserved []string
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			method = strings.ToUpper(method)
+			if !served(apiSvr.GRPCGatewayRouter, method, paramRegexp.ReplaceAllString(path, "1")) {
+				unserved = append(unserved, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(unserved)
+	require.Empty(t, unserved, "routes in the swagger spec that are not served by the gateway; run `make proto-swagger-gen update-swagger-docs`")
+
+	require.True(t, served(apiSvr.GRPCGatewayRouter, http.MethodGet, "/lbm/bank/v1/balances/1"))
+	require.False(t, served(apiSvr.GRPCGatewayRouter, http.MethodGet, "/lfb/unknown/v1/route"))
+	require.False(t, served(apiSvr.GRPCGatewayRouter, http.MethodDelete, "/lbm/bank/v1/balances/1"))
+}
+
+var paramRegexp = regexp.MustCompile(`\{[^}]+\}`)
+
+// served returns true if a handler of the gateway handles the request.
+func served(handler http.Handler, method, path string) bool {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader("{}")))
+	return rec.Code != http.StatusNotImplemented
+}
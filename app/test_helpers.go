@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/json"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	octypes "github.com/line/ostracon/types"
+	"github.com/line/tm-db/v2/memdb"
+
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	"github.com/line/lbm-sdk/simapp"
+	sdk "github.com/line/lbm-sdk/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+)
+
+// DefaultConsensusParams defines the default consensus params used in LinkApp testing.
+var DefaultConsensusParams = &abci.ConsensusParams{
+	Block: &abci.BlockParams{
+		MaxBytes: 200000,
+		MaxGas:   2000000,
+	},
+	Evidence: &ocproto.EvidenceParams{
+		MaxAgeNumBlocks: 302400,
+		MaxAgeDuration:  504 * time.Hour, // 3 weeks is the max duration
+		MaxBytes:        10000,
+	},
+	Validator: &ocproto.ValidatorParams{
+		PubKeyTypes: []string{
+			octypes.ABCIPubKeyTypeEd25519,
+		},
+	},
+}
+
+// Setup initializes a new LinkApp on a memory DB with the default genesis state. A Nop logger
+// is set in LinkApp.
+func Setup(isCheckTx bool) *LinkApp {
+	encodingConfig := MakeEncodingConfig()
+	app := NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 5,
+		encodingConfig, simapp.EmptyAppOptions{}, nil)
+	if !isCheckTx {
+		// init chain must be called to stop deliverState from being nil
+		stateBytes, err := json.MarshalIndent(NewDefaultGenesisState(), "", " ")
+		if err != nil {
+			panic(err)
+		}
+		app.InitChain(abci.RequestInitChain{
+			Validators:      []abci.ValidatorUpdate{},
+			ConsensusParams: DefaultConsensusParams,
+			AppStateBytes:   stateBytes,
+		})
+	}
+	return app
+}
+
+// AddTestAddrs creates accNum accounts with random addresses and an initial balance of accAmt
+// in the bond denom, and returns their addresses.
+func AddTestAddrs(app *LinkApp, ctx sdk.Context, accNum int, accAmt sdk.Int) []sdk.AccAddress {
+	coins := sdk.NewCoins(sdk.NewCoin(app.StakingKeeper.BondDenom(ctx), accAmt))
+	addrs := make([]sdk.AccAddress, accNum)
+	for i := range addrs {
+		addrs[i] = sdk.BytesToAccAddress(secp256k1.GenPrivKey().PubKey().Address())
+		if err := FundAccount(app, ctx, addrs[i], coins); err != nil {
+			panic(err)
+		}
+	}
+	return addrs
+}
+
+// FundAccount adds the coins to the balance of the account and to the total supply, creating
+// the account if it doesn't exist.
+func FundAccount(app *LinkApp, ctx sdk.Context, addr sdk.AccAddress, coins sdk.Coins) error {
+	if app.AccountKeeper.GetAccount(ctx, addr) == nil {
+		app.AccountKeeper.SetAccount(ctx, app.AccountKeeper.NewAccountWithAddress(ctx, addr))
+	}
+	prevSupply := app.BankKeeper.GetSupply(ctx)
+	app.BankKeeper.SetSupply(ctx, banktypes.NewSupply(prevSupply.GetTotal().Add(coins...)))
+	return app.BankKeeper.AddCoins(ctx, addr, coins)
+}
@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cast"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	servertypes "github.com/line/lbm-sdk/server/types"
+)
+
+// The keys of the [tracing] section of app.toml.
+const (
+	FlagEnable       = "tracing.enable"
+	FlagExporter     = "tracing.exporter"
+	FlagOTLPEndpoint = "tracing.otlp-endpoint"
+	FlagOTLPInsecure = "tracing.otlp-insecure"
+	FlagFile         = "tracing.file"
+	FlagSampleRate   = "tracing.sample-rate"
+)
+
+// The exporters of the spans.
+const (
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// Config is the configuration of the tracing.
+type Config struct {
+	// Enable is whether the blocks are traced.
+	Enable bool
+	// Exporter is the exporter of the spans, otlp or file.
+	Exporter string
+	// SpanExporter is the exporter of the spans given in the options instead of the name of
+	// one, e.g. an in-memory exporter in tests. It cannot be set in app.toml.
+	SpanExporter sdktrace.SpanExporter
+	// OTLPEndpoint is the host and port of the OTLP/HTTP endpoint of the collector.
+	OTLPEndpoint string
+	// OTLPInsecure is whether the collector is connected to without TLS.
+	OTLPInsecure bool
+	// File is the path of the file the spans are appended to as JSON lines, relative to the
+	// home directory if not absolute.
+	File string
+	// SampleRate is the fraction of the blocks that are traced, from 0 to 1.
+	SampleRate float64
+}
+
+// DefaultConfig returns the default configuration, which disables the tracing.
+func DefaultConfig() Config {
+	return Config{
+		Enable:       false,
+		Exporter:     ExporterOTLP,
+		OTLPEndpoint: "localhost:4318",
+		File:         "data/traces.json",
+		SampleRate:   1,
+	}
+}
+
+// ReadConfig reads the configuration from app.toml, falling back to the defaults.
+func ReadConfig(opts servertypes.AppOptions) (Config, error) {
+	cfg := DefaultConfig()
+	var err error
+	if v := opts.Get(FlagEnable); v != nil {
+		if cfg.Enable, err = cast.ToBoolE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagExporter); v != nil {
+		if exporter, ok := v.(sdktrace.SpanExporter); ok {
+			cfg.SpanExporter = exporter
+		} else if cfg.Exporter, err = cast.ToStringE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagOTLPEndpoint); v != nil {
+		if cfg.OTLPEndpoint, err = cast.ToStringE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagOTLPInsecure); v != nil {
+		if cfg.OTLPInsecure, err = cast.ToBoolE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagFile); v != nil {
+		if cfg.File, err = cast.ToStringE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagSampleRate); v != nil {
+		if cfg.SampleRate, err = cast.ToFloat64E(v); err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.SpanExporter == nil && cfg.Exporter != ExporterOTLP && cfg.Exporter != ExporterFile {
+		return cfg, fmt.Errorf("unknown %s %q; expected %s or %s", FlagExporter, cfg.Exporter, ExporterOTLP, ExporterFile)
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return cfg, fmt.Errorf("%s must be from 0 to 1: %v", FlagSampleRate, cfg.SampleRate)
+	}
+	return cfg, nil
+}
+
+// FilePath returns the path of the file of the spans of the node at the home directory.
+func (c Config) FilePath(home string) string {
+	if filepath.IsAbs(c.File) {
+		return c.File
+	}
+	return filepath.Join(home, c.File)
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gogogrpc "github.com/gogo/protobuf/grpc"
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/line/ostracon/abci/types"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/module"
+)
+
+// errPanic marks the spans of the handlers that panicked, e.g. running out of gas.
+var errPanic = errors.New("panic")
+
+// Router wraps the router so that the handlers it routes to are traced in DeliverTx.
+func (t *Tracer) Router(r sdk.Router) sdk.Router {
+	return router{Router: r, tracer: t}
+}
+
+type router struct {
+	sdk.Router
+	tracer *Tracer
+}
+
+func (r router) AddRoute(route sdk.Route) sdk.Router {
+	r.Router.AddRoute(route)
+	return r
+}
+
+func (r router) Route(ctx sdk.Context, path string) sdk.Handler {
+	h := r.Router.Route(ctx, path)
+	if h == nil {
+		return nil
+	}
+	return func(ctx sdk.Context, msg sdk.Msg) (res *sdk.Result, err error) {
+		if ctx.IsCheckTx() {
+			return h(ctx, msg)
+		}
+		span := r.tracer.Start("/"+proto.MessageName(msg), attribute.String("msg.route", path))
+		err = errPanic
+		defer func() { r.tracer.EndWithError(span, err) }()
+		return h(ctx, msg)
+	}
+}
+
+// MsgServer wraps the server the Msg services are registered on so that their handlers are
+// traced in DeliverTx.
+func (t *Tracer) MsgServer(s gogogrpc.Server) gogogrpc.Server {
+	return msgServer{Server: s, tracer: t}
+}
+
+type msgServer struct {
+	gogogrpc.Server
+	tracer *Tracer
+}
+
+func (s msgServer) RegisterService(sd *grpc.ServiceDesc, handler interface{}) {
+	desc := *sd
+	desc.Methods = make([]grpc.MethodDesc, len(sd.Methods))
+	for i, method := range sd.Methods {
+		name := fmt.Sprintf("/%s/%s", sd.ServiceName, method.MethodName)
+		h := method.Handler
+		desc.Methods[i] = grpc.MethodDesc{
+			MethodName: method.MethodName,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (res interface{}, err error) {
+				if sdkCtx, ok := ctx.Value(sdk.SdkContextKey).(sdk.Context); !ok || sdkCtx.IsCheckTx() {
+					return h(srv, ctx, dec, interceptor)
+				}
+				span := s.tracer.Start(name)
+				err = errPanic
+				defer func() { s.tracer.EndWithError(span, err) }()
+				return h(srv, ctx, dec, interceptor)
+			},
+		}
+	}
+	s.Server.RegisterService(&desc, handler)
+}
+
+// InstrumentModules wraps the modules of the manager so that their BeginBlockers and
+// EndBlockers are traced.
+func (t *Tracer) InstrumentModules(mm *module.Manager) {
+	for name, mod := range mm.Modules {
+		mm.Modules[name] = tracedModule{AppModule: mod, tracer: t, name: name}
+	}
+}
+
+type tracedModule struct {
+	module.AppModule
+	tracer *Tracer
+	name   string
+}
+
+func (mod tracedModule) BeginBlock(ctx sdk.Context, req abci.RequestBeginBlock) {
+	defer mod.tracer.End(mod.tracer.Start("BeginBlock "+mod.name, attribute.String("module", mod.name)))
+	mod.AppModule.BeginBlock(ctx, req)
+}
+
+func (mod tracedModule) EndBlock(ctx sdk.Context, req abci.RequestEndBlock) []abci.ValidatorUpdate {
+	defer mod.tracer.End(mod.tracer.Start("EndBlock "+mod.name, attribute.String("module", mod.name)))
+	return mod.AppModule.EndBlock(ctx, req)
+}
@@ -0,0 +1,188 @@
+// Package tracing traces the execution of the blocks with OpenTelemetry, to tell which module,
+// tx or message makes a block slow.
+//
+// Every block is a trace, whose root span covers the block from BeginBlock to Commit. Its
+// children are the spans of the BeginBlocker and the EndBlocker, with a span for every module,
+// and of every DeliverTx, with a span for every message handler. The genesis is a trace of its
+// own, with the span of the InitChainer. Only the execution of the blocks is traced: CheckTx,
+// the simulations and the queries are not.
+//
+// The tracing is configured in the [tracing] section of app.toml:
+//
+//	[tracing]
+//	# whether the blocks are traced
+//	enable = false
+//	# the exporter of the spans: otlp to an OTLP/HTTP collector, or file
+//	exporter = "otlp"
+//	# the host and port of the OTLP/HTTP endpoint of the collector
+//	otlp-endpoint = "localhost:4318"
+//	# whether the collector is connected to without TLS
+//	otlp-insecure = false
+//	# the file the spans are appended to as JSON lines, relative to the home directory
+//	file = "data/traces.json"
+//	# the fraction of the blocks that are traced, from 0 to 1
+//	sample-rate = 1.0
+//
+// The spans are sent to the collector in batches, and written to the file as they end.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	abci "github.com/line/ostracon/abci/types"
+	octypes "github.com/line/ostracon/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/line/lbm-sdk/version"
+)
+
+const instrumentationName = "github.com/line/lfb/app/tracing"
+
+// Tracer starts and ends the spans of the execution of the blocks. The spans are nested: a span
+// started is the child of the span started before it and not ended yet. The ABCI methods
+// executing the blocks are called one at a time, so the tracer is not safe for concurrent use.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	// file is the file of the file exporter, closed on Shutdown; nil for the other exporters
+	file *os.File
+
+	// spans are the contexts of the spans started and not ended yet, the root span first
+	spans []context.Context
+}
+
+// New creates a tracer exporting the spans of the node at the home directory as configured.
+func New(home string, cfg Config) (*Tracer, error) {
+	var opt sdktrace.TracerProviderOption
+	var file *os.File
+	switch {
+	case cfg.SpanExporter != nil:
+		opt = sdktrace.WithSyncer(cfg.SpanExporter)
+	case cfg.Exporter == ExporterFile:
+		path := cfg.FilePath(home)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		file = f
+		opt = sdktrace.WithSyncer(exporter)
+	default:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+		}
+		opt = sdktrace.WithBatcher(exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		opt,
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(version.AppName),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(instrumentationName),
+		file:     file,
+	}, nil
+}
+
+// Shutdown exports the spans not exported yet and stops the tracer.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	err := t.provider.Shutdown(ctx)
+	if t.file != nil {
+		if cerr := t.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// StartTrace starts the root span of a trace, ending the spans of the previous trace not ended
+// yet, e.g. of a block that was not committed.
+func (t *Tracer) StartTrace(name string, attrs ...attribute.KeyValue) trace.Span {
+	t.EndTrace()
+	return t.Start(name, attrs...)
+}
+
+// EndTrace ends the spans not ended yet, the root span last.
+func (t *Tracer) EndTrace() {
+	for len(t.spans) > 0 {
+		t.End(trace.SpanFromContext(t.spans[len(t.spans)-1]))
+	}
+}
+
+// StartBlock starts the trace of the block at the height.
+func (t *Tracer) StartBlock(height int64) {
+	t.StartTrace("Block", attribute.Int64("block.height", height))
+}
+
+// StartTx starts the span of the DeliverTx of the tx.
+func (t *Tracer) StartTx(tx []byte) trace.Span {
+	return t.Start("DeliverTx", attribute.String("tx.hash", fmt.Sprintf("%X", octypes.Tx(tx).Hash())))
+}
+
+// EndTx ends the span of the DeliverTx with its result, marking it failed if the tx failed.
+func (t *Tracer) EndTx(span trace.Span, res abci.ResponseDeliverTx) {
+	span.SetAttributes(
+		attribute.Int64("tx.code", int64(res.Code)),
+		attribute.Int64("tx.gas_wanted", res.GasWanted),
+		attribute.Int64("tx.gas_used", res.GasUsed),
+	)
+	if !res.IsOK() {
+		span.SetStatus(codes.Error, res.Log)
+	}
+	t.End(span)
+}
+
+// Start starts a span as the child of the latest span not ended yet.
+func (t *Tracer) Start(name string, attrs ...attribute.KeyValue) trace.Span {
+	parent := context.Background()
+	if len(t.spans) > 0 {
+		parent = t.spans[len(t.spans)-1]
+	}
+	ctx, span := t.tracer.Start(parent, name, trace.WithAttributes(attrs...))
+	t.spans = append(t.spans, ctx)
+	return span
+}
+
+// End ends the span, which is the latest span not ended yet.
+func (t *Tracer) End(span trace.Span) {
+	if len(t.spans) > 0 {
+		t.spans = t.spans[:len(t.spans)-1]
+	}
+	span.End()
+}
+
+// EndWithError ends the span, marking it failed if the error is not nil.
+func (t *Tracer) EndWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	t.End(span)
+}
@@ -0,0 +1,179 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	"github.com/line/lbm-sdk/simapp"
+	"github.com/line/lbm-sdk/simapp/helpers"
+	sdk "github.com/line/lbm-sdk/types"
+	authtypes "github.com/line/lbm-sdk/x/auth/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+
+	link "github.com/line/lfb/app"
+	"github.com/line/lfb/app/indexer"
+	"github.com/line/lfb/app/tracing"
+)
+
+const chainID = "tracing-test"
+
+type appOptions map[string]interface{}
+
+func (o appOptions) Get(key string) interface{} {
+	return o[key]
+}
+
+// TestTraceBlock checks the spans of the genesis and of a block with a tx that succeeds and
+// one that fails.
+func TestTraceBlock(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	app, priv := newApp(t, appOptions{tracing.FlagEnable: true, tracing.FlagExporter: exporter})
+
+	genesis := exporter.GetSpans()
+	require.Len(t, genesis, 2)
+	require.Equal(t, "InitChainer", genesis[0].Name)
+	require.Equal(t, "InitChain", genesis[1].Name)
+	require.Equal(t, genesis[1].SpanContext.SpanID(), genesis[0].Parent.SpanID())
+	exporter.Reset()
+
+	runBlock(t, app, priv)
+
+	spans := map[string]tracetest.SpanStubs{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = append(spans[span.Name], span)
+	}
+	require.Len(t, spans["Block"], 1)
+	block := spans["Block"][0]
+	require.False(t, block.Parent.IsValid())
+	require.Contains(t, block.Attributes, attribute.Int64("block.height", 1))
+	require.NotEqual(t, genesis[1].SpanContext.TraceID(), block.SpanContext.TraceID())
+
+	childOf := func(parent tracetest.SpanStub, names ...string) {
+		for _, name := range names {
+			require.NotEmpty(t, spans[name], name)
+			for _, span := range spans[name] {
+				require.Equal(t, block.SpanContext.TraceID(), span.SpanContext.TraceID(), name)
+				require.Equal(t, parent.SpanContext.SpanID(), span.Parent.SpanID(), name)
+			}
+		}
+	}
+	childOf(block, "BeginBlocker", "DeliverTx", "EndBlocker", "Commit")
+	childOf(spans["BeginBlocker"][0], "BeginBlock mint", "BeginBlock distribution", "BeginBlock staking")
+	childOf(spans["EndBlocker"][0], "EndBlock gov", "EndBlock staking")
+
+	// the second tx overspends, so it and its message fail
+	txs, msgs := spans["DeliverTx"], spans["/lbm.bank.v1.MsgSend"]
+	require.Len(t, txs, 2)
+	require.Len(t, msgs, 2)
+	for i := range txs {
+		require.Equal(t, txs[i].SpanContext.SpanID(), msgs[i].Parent.SpanID())
+	}
+	require.Equal(t, codes.Unset, txs[0].Status.Code)
+	require.Equal(t, codes.Unset, msgs[0].Status.Code)
+	require.Contains(t, txs[0].Attributes, attribute.Int64("tx.code", 0))
+	require.Equal(t, codes.Error, txs[1].Status.Code)
+	require.Equal(t, codes.Error, msgs[1].Status.Code)
+	require.Contains(t, msgs[1].Status.Description, "insufficient funds")
+}
+
+// TestTraceSampling checks that no block is traced with the sample rate 0.
+func TestTraceSampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	app, priv := newApp(t, appOptions{
+		tracing.FlagEnable: true, tracing.FlagExporter: exporter, tracing.FlagSampleRate: 0,
+	})
+	runBlock(t, app, priv)
+	require.Empty(t, exporter.GetSpans())
+}
+
+// TestCloseApp checks that closing the app exports the spans to the file and closes the
+// database of the indexer.
+func TestCloseApp(t *testing.T) {
+	dir := t.TempDir()
+	traces := filepath.Join(dir, "traces.json")
+	app, priv := newApp(t, appOptions{
+		tracing.FlagEnable: true, tracing.FlagExporter: tracing.ExporterFile, tracing.FlagFile: traces,
+		indexer.FlagEnable: true, indexer.FlagPath: filepath.Join(dir, "indexer.db"),
+	})
+	runBlock(t, app, priv)
+	require.True(t, app.IndexerEnabled())
+
+	require.NoError(t, app.Close())
+	bz, err := ioutil.ReadFile(traces)
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"Name":"Block"`)
+
+	// the blocks after Close are neither traced nor indexed
+	header := ocproto.Header{ChainID: chainID, Height: 2, Time: time.Now()}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: 2})
+	app.Commit()
+	after, err := ioutil.ReadFile(traces)
+	require.NoError(t, err)
+	require.Equal(t, bz, after)
+	db, err := indexer.OpenReadOnly(filepath.Join(dir, "indexer.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	height, err := indexer.NewIndexer(db, nil, nil).LastHeight()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), height)
+}
+
+// newApp creates an app with the options and inits its chain with a funded account.
+func newApp(t *testing.T, opts appOptions) (*link.LinkApp, *secp256k1.PrivKey) {
+	encCfg := link.MakeEncodingConfig()
+	app := link.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, true, map[int64]bool{}, t.TempDir(), 0, encCfg,
+		opts, nil)
+
+	priv := secp256k1.GenPrivKey()
+	addr := sdk.BytesToAccAddress(priv.PubKey().Address())
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1000000))
+	genesis := link.NewDefaultGenesisState()
+	genesis[authtypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(authtypes.NewGenesisState(
+		authtypes.DefaultParams(), authtypes.GenesisAccounts{authtypes.NewBaseAccount(addr, nil, 0)}))
+	genesis[banktypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(banktypes.NewGenesisState(
+		banktypes.DefaultParams(), []banktypes.Balance{{Address: addr.String(), Coins: coins}}, coins, nil))
+	stateBytes, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{
+		ChainId:         chainID,
+		AppStateBytes:   stateBytes,
+		ConsensusParams: simapp.DefaultConsensusParams,
+	})
+	return app, priv
+}
+
+// runBlock runs a block with a send and a send overspending.
+func runBlock(t *testing.T, app *link.LinkApp, priv *secp256k1.PrivKey) {
+	from := sdk.BytesToAccAddress(priv.PubKey().Address())
+	to := sdk.BytesToAccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	app.BeginBlock(abci.RequestBeginBlock{Header: ocproto.Header{ChainID: chainID, Height: 1, Time: time.Now()}})
+	res := deliver(t, app, priv, 0, banktypes.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 100))))
+	require.True(t, res.IsOK(), res.Log)
+	res = deliver(t, app, priv, 1, banktypes.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 2000000))))
+	require.False(t, res.IsOK())
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+}
+
+func deliver(t *testing.T, app *link.LinkApp, priv *secp256k1.PrivKey, seq uint64, msg sdk.Msg) abci.ResponseDeliverTx {
+	txCfg := link.MakeEncodingConfig().TxConfig
+	tx, err := helpers.GenTx(txCfg, []sdk.Msg{msg}, sdk.NewCoins(), 200000, chainID, []uint64{0}, []uint64{seq}, priv)
+	require.NoError(t, err)
+	bz, err := txCfg.TxEncoder()(tx)
+	require.NoError(t, err)
+	return app.DeliverTx(abci.RequestDeliverTx{Tx: bz})
+}
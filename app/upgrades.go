@@ -0,0 +1,47 @@
+package app
+
+import (
+	"fmt"
+
+	storetypes "github.com/line/lbm-sdk/store/types"
+	sdk "github.com/line/lbm-sdk/types"
+	upgradetypes "github.com/line/lbm-sdk/x/upgrade/types"
+
+	audittypes "github.com/line/lfb/x/audit/types"
+	keyrotationtypes "github.com/line/lfb/x/keyrotation/types"
+	paramscheduletypes "github.com/line/lfb/x/paramschedule/types"
+)
+
+// UpgradeName is the name of the software upgrade plan adding the audit, paramschedule and
+// keyrotation modules to a chain started by a binary without them. The binary with the modules
+// mounts their stores at the height of the plan only, so that the app hash of the earlier
+// heights is kept.
+const UpgradeName = "lfb-audit-paramschedule-keyrotation"
+
+// upgradeModules are the modules the upgrade adds, whose stores are mounted and whose state is
+// initialized with their default genesis state.
+var upgradeModules = []string{audittypes.ModuleName, paramscheduletypes.ModuleName, keyrotationtypes.ModuleName}
+
+// registerUpgrade sets the handler of the upgrade, and the store loader adding the stores of
+// the modules if the node is restarted at the height of the upgrade. It must be called before
+// the latest version is loaded.
+func (app *LinkApp) registerUpgrade() {
+	app.UpgradeKeeper.SetUpgradeHandler(UpgradeName, func(ctx sdk.Context, _ upgradetypes.Plan) {
+		for _, name := range upgradeModules {
+			m := app.mm.Modules[name]
+			m.InitGenesis(ctx, app.appCodec, m.DefaultGenesis(app.appCodec))
+		}
+	})
+
+	upgradeInfo, err := app.UpgradeKeeper.ReadUpgradeInfoFromDisk()
+	if err != nil {
+		panic(fmt.Sprintf("failed to read upgrade info from disk: %s", err))
+	}
+	if upgradeInfo.Name == UpgradeName && !app.UpgradeKeeper.IsSkipHeight(upgradeInfo.Height) {
+		stores := make([]string, len(upgradeModules))
+		for i, name := range upgradeModules {
+			stores[i] = app.keys[name].Name()
+		}
+		app.SetStoreLoader(upgradetypes.UpgradeStoreLoader(upgradeInfo.Height, &storetypes.StoreUpgrades{Added: stores}))
+	}
+}
@@ -4,13 +4,19 @@ package clitest
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	cryptocodec "github.com/line/lbm-sdk/crypto/codec"
+	"github.com/line/lbm-sdk/crypto/keys/ed25519"
+	cryptotypes "github.com/line/lbm-sdk/crypto/types"
 	sdk "github.com/line/lbm-sdk/types"
 
+	ostcfg "github.com/line/ostracon/config"
 	"github.com/line/ostracon/privval"
+
+	keyrotation "github.com/line/lfb/x/keyrotation/types"
 )
 
 func TestMultiValidatorAndSendTokens(t *testing.T) {
@@ -152,4 +158,155 @@ func TestMultiValidatorAddNodeAndPromoteValidator(t *testing.T) {
 		require.Len(t, validatorDelegations.DelegationResponses, 1)
 		require.NotZero(t, validatorDelegations.DelegationResponses[0].Delegation.GetShares())
 	}
-}
\ No newline at end of file
+}
+
+func TestMultiValidatorRotateConsPubKey(t *testing.T) {
+	t.Parallel()
+
+	fg := InitFixturesGroup(t)
+	fg.LFBStartCluster()
+	defer fg.Cleanup()
+
+	f1 := fg.Fixture(0)
+	valAddr := f1.KeyAddress(f1.Moniker).ToValAddress()
+
+	// the validator moves to the node of f2 by rotating its consensus key to the key of the node
+	f2 := fg.AddFullNode()
+
+	consPubKey := func(f *Fixtures) (cryptotypes.PubKey, string) {
+		privVal := privval.LoadFilePVEmptyState(f.PrivValidatorKeyFile(), "")
+		pubkey, err := privVal.GetPubKey()
+		require.NoError(t, err)
+		pk, err := cryptocodec.FromOcPubKeyInterface(pubkey)
+		require.NoError(t, err)
+		return pk, sdk.MustBech32ifyPubKey(sdk.Bech32PubKeyTypeConsPub, pk)
+	}
+	oldPubKey, oldConsPubKey := consPubKey(f1)
+	newPubKey, newConsPubKey := consPubKey(f2)
+
+	var height int64
+	{
+		out, err := f1.TxKeyRotationRotateConsPubKey(f1.Moniker, newConsPubKey, "-y")
+		require.NoError(t, err)
+		res := UnmarshalTxResponse(t, out.Bytes())
+		require.Zero(t, res.Code, res.RawLog)
+		height = res.Height
+	}
+	{
+		// Ensure the validator has the new key and keeps its tokens
+		validator := f1.QueryStakingValidator(valAddr)
+		pk, err := validator.ConsPubKey()
+		require.NoError(t, err)
+		require.True(t, newPubKey.Equals(pk))
+		require.True(sdk.IntEq(t, sdk.TokensFromConsensusPower(100), validator.Tokens))
+
+		rotation := f1.QueryKeyRotationRotation(valAddr)
+		require.Equal(t, height, rotation.Height)
+		pk, err = rotation.OldPubKey()
+		require.NoError(t, err)
+		require.True(t, oldPubKey.Equals(pk))
+
+		// Ensure the signing info of the new key starts at the rotation
+		info := f1.QuerySigningInfo(newConsPubKey)
+		require.Equal(t, height, info.StartHeight)
+		require.Equal(t, sdk.BytesToConsAddress(newPubKey.Address()).String(), info.Address)
+	}
+	{
+		// Ensure the validator set replaced the old key with the new one
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.NoError(t, fg.Network.WaitForNextBlock())
+
+		var addrs []string
+		for _, v := range f1.Validators().Validators {
+			addrs = append(addrs, v.Address.String())
+		}
+		require.Len(t, addrs, 4)
+		require.Contains(t, addrs, newPubKey.Address().String())
+		require.NotContains(t, addrs, oldPubKey.Address().String())
+
+		// Ensure the node of f2 signs the blocks for the validator
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		info := f1.QuerySigningInfo(newConsPubKey)
+		require.Positive(t, info.IndexOffset)
+		require.Zero(t, info.MissedBlocksCounter)
+	}
+	{
+		// Ensure the validator can't rotate its key again within the cooldown period
+		out, err := f1.TxKeyRotationRotateConsPubKey(f1.Moniker,
+			sdk.MustBech32ifyPubKey(sdk.Bech32PubKeyTypeConsPub, ed25519.GenPrivKey().PubKey()), "-y")
+		require.NoError(t, err)
+		res := UnmarshalTxResponse(t, out.Bytes())
+		require.Equal(t, keyrotation.ErrCooldown.ABCICode(), res.Code, res.RawLog)
+
+		// Ensure another validator can't take the old key
+		f3 := fg.Fixture(1)
+		out, err = f3.TxKeyRotationRotateConsPubKey(f3.Moniker, oldConsPubKey, "-y")
+		require.NoError(t, err)
+		res = UnmarshalTxResponse(t, out.Bytes())
+		require.Equal(t, keyrotation.ErrPubKeyExists.ABCICode(), res.Code, res.RawLog)
+	}
+
+	// Ensure the chain keeps producing blocks
+	require.NoError(t, fg.Network.WaitForNextBlock())
+}
+
+func TestMultiValidatorRemoteSigner(t *testing.T) {
+	t.Parallel()
+
+	fg := InitFixturesGroup(t)
+	fg.LFBStartClusterWithRemoteSigners()
+	defer fg.Cleanup()
+
+	f1 := fg.Fixture(0)
+	fg.AddFullNode()
+
+	signerCfg := ostcfg.DefaultConfig()
+	signerCfg.SetRoot(f1.SignerHome())
+	privVal := privval.LoadFilePVEmptyState(signerCfg.PrivValidatorKeyFile(), "")
+	pubkey, err := privVal.GetPubKey()
+	require.NoError(t, err)
+	pk, err := cryptocodec.FromOcPubKeyInterface(pubkey)
+	require.NoError(t, err)
+	consPubKey := sdk.MustBech32ifyPubKey(sdk.Bech32PubKeyTypeConsPub, pk)
+
+	lastSignedHeight := func() int64 {
+		return privval.LoadFilePV(signerCfg.PrivValidatorKeyFile(), signerCfg.PrivValidatorStateFile()).LastSignState.Height
+	}
+	{
+		// Ensure the validator signs with the key of the remote signer
+		require.True(t, f1.SignerConnected())
+
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		info := f1.QuerySigningInfo(consPubKey)
+		require.Positive(t, info.IndexOffset)
+		require.Zero(t, info.MissedBlocksCounter)
+		require.Positive(t, lastSignedHeight())
+	}
+	{
+		// Ensure the chain keeps producing blocks without the signer, and the validator misses them
+		f1.StopSigner()
+		require.False(t, f1.SignerConnected())
+		height := lastSignedHeight()
+
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.Equal(t, height, lastSignedHeight())
+		require.Positive(t, f1.QuerySigningInfo(consPubKey).MissedBlocksCounter)
+	}
+	{
+		// Ensure the validator signs again once the signer reconnects
+		f1.StartSigner()
+		require.Eventually(t, f1.SignerConnected, 10*time.Second, 100*time.Millisecond)
+
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		missed := f1.QuerySigningInfo(consPubKey).MissedBlocksCounter
+		height := lastSignedHeight()
+
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.NoError(t, fg.Network.WaitForNextBlock())
+		require.Equal(t, missed, f1.QuerySigningInfo(consPubKey).MissedBlocksCounter)
+		require.Greater(t, lastSignedHeight(), height)
+	}
+}
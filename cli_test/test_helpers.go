@@ -22,7 +22,10 @@ import (
 
 	"github.com/line/lfb/app"
 	lfbcmd "github.com/line/lfb/cmd/lfb/cmd"
+	"github.com/line/lfb/signer"
 	lfbtypes "github.com/line/lfb/types"
+	keyrotationcli "github.com/line/lfb/x/keyrotation/client/cli"
+	keyrotation "github.com/line/lfb/x/keyrotation/types"
 
 	"github.com/line/lbm-sdk/baseapp"
 	"github.com/line/lbm-sdk/client"
@@ -162,6 +165,10 @@ type Fixtures struct {
 	TMPort   string
 	Moniker  string
 	T        *testing.T
+
+	// SignerAddr is the priv_validator_laddr of a node with a remote signer.
+	SignerAddr string
+	signer     *signer.Signer
 }
 
 func getHomeDir(t *testing.T) string {
@@ -554,6 +561,16 @@ func (f *Fixtures) TxStakingUnbond(from, shares string, validator sdk.ValAddress
 	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
 }
 
+// ___________________________________________________________________________________
+// lfb tx keyrotation
+
+// TxKeyRotationRotateConsPubKey is lfb tx keyrotation rotate-cons-pubkey
+func (f *Fixtures) TxKeyRotationRotateConsPubKey(from, consPubKey string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("--keyring-backend=test %s --from=%s --node=%s", consPubKey, from, f.RPCAddr)
+	cmd := keyrotationcli.NewRotateConsPubKeyCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
 // ___________________________________________________________________________________
 // lfb tx gov
 
@@ -953,6 +970,23 @@ func (f *Fixtures) QuerySlashingParams(flags ...string) slashing.Params {
 	return params
 }
 
+// ___________________________________________________________________________________
+// query keyrotation
+
+// QueryKeyRotationRotation is lfb query keyrotation rotation
+func (f *Fixtures) QueryKeyRotationRotation(valAddr sdk.ValAddress, flags ...string) keyrotation.Rotation {
+	args := fmt.Sprintf("%s --node=%s -o=json", valAddr, f.RPCAddr)
+	cmd := keyrotationcli.GetCmdQueryRotation()
+	out, err := testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+	require.NoError(f.T, err)
+	require.NotNil(f.T, out)
+	cdc, _ := app.MakeCodecs()
+	var rotation keyrotation.Rotation
+	err = cdc.UnmarshalJSON(out.Bytes(), &rotation)
+	require.NoError(f.T, err)
+	return rotation
+}
+
 // ___________________________________________________________________________________
 // query distribution
 
@@ -1037,6 +1071,25 @@ func (f *Fixtures) Status() *ostctypes.ResultStatus {
 	return netInfo
 }
 
+// Validators returns the validator set of the latest block
+func (f *Fixtures) Validators() *ostctypes.ResultValidators {
+	ostc, err := osthttp.New(fmt.Sprintf("tcp://0.0.0.0:%s", f.Port), "/websocket")
+	if err != nil {
+		panic(fmt.Sprintf("failed to create Tendermint HTTP client: %s", err))
+	}
+
+	err = ostc.Start()
+	require.NoError(f.T, err)
+	defer func() {
+		err := ostc.Stop()
+		require.NoError(f.T, err)
+	}()
+
+	validators, err := ostc.Validators(context.Background(), nil, nil, nil)
+	require.NoError(f.T, err)
+	return validators
+}
+
 // ___________________________________________________________________________________
 // linkcli mempool
 
@@ -1157,6 +1210,39 @@ func (fg *FixtureGroup) LFBStartCluster(flags ...string) {
 	fg.Network = testnet.NewWithoutInit(fg.T, cfg, fg.BaseDir, validators)
 }
 
+// LFBStartClusterWithRemoteSigners starts the nodes with a remote signer each, which holds
+// the key and the state of the validator in the signer home of the fixture.
+func (fg *FixtureGroup) LFBStartClusterWithRemoteSigners() {
+	genDoc, err := osttypes.GenesisDocFromJSON(fg.genesisFileContent)
+	require.NoError(fg.T, err)
+
+	var appState app.GenesisState
+	require.NoError(fg.T, legacy.Cdc.UnmarshalJSON(genDoc.AppState, &appState))
+
+	cfg := newTestnetConfig(fg.T, appState, fg.T.Name(), "")
+
+	validators := make([]*testnet.Validator, 0)
+	for _, f := range fg.fixturesMap {
+		ctx := server.NewDefaultContext()
+		f.SignerAddr, _ = newTCPAddr(fg.T)
+		ctx.Config.PrivValidatorListenAddr = f.SignerAddr
+		validators = append(validators, newValidator(f, cfg, srvconfig.DefaultConfig(), ctx))
+
+		// the node has no key of the validator anymore
+		signerCfg := ostcfg.DefaultConfig()
+		signerCfg.SetRoot(f.SignerHome())
+		require.NoError(fg.T, os.MkdirAll(filepath.Join(f.SignerHome(), "config"), 0755))
+		require.NoError(fg.T, os.MkdirAll(filepath.Join(f.SignerHome(), "data"), 0755))
+		require.NoError(fg.T, os.Rename(ctx.Config.PrivValidatorKeyFile(), signerCfg.PrivValidatorKeyFile()))
+		require.NoError(fg.T, os.Rename(ctx.Config.PrivValidatorStateFile(), signerCfg.PrivValidatorStateFile()))
+
+		// the signers dial until the nodes listen
+		f.StartSigner()
+	}
+
+	fg.Network = testnet.NewWithoutInit(fg.T, cfg, fg.BaseDir, validators)
+}
+
 func (fg *FixtureGroup) AddFullNode(flags ...string) *Fixtures {
 	t := fg.T
 	idx := len(fg.fixturesMap)
@@ -1233,10 +1319,45 @@ func (fg *FixtureGroup) Fixture(index int) *Fixtures {
 func (fg *FixtureGroup) Cleanup() {
 	fg.Network.Cleanup()
 	for _, f := range fg.fixturesMap {
+		if f.signer != nil && f.signer.IsRunning() {
+			f.StopSigner()
+		}
 		f.Cleanup()
 	}
 }
 
+// ___________________________________________________________________________________
+// remote signer
+
+// SignerHome returns the home directory of the remote signer of the fixture.
+func (f *Fixtures) SignerHome() string {
+	return filepath.Join(f.Home, "signer")
+}
+
+// StartSigner starts the remote signer of the fixture, which dials its node.
+func (f *Fixtures) StartSigner() {
+	cfg := ostcfg.DefaultConfig()
+	cfg.SetRoot(f.SignerHome())
+
+	addr := strings.Replace(f.SignerAddr, "0.0.0.0", "127.0.0.1", 1)
+	logger := log.NewOCLogger(log.NewSyncWriter(os.Stdout)).With("module", "signer", "moniker", f.Moniker)
+	s, err := signer.New(logger, addr, f.T.Name(), cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile(),
+		signer.WithRetryInterval(100*time.Millisecond))
+	require.NoError(f.T, err)
+	require.NoError(f.T, s.Start())
+	f.signer = s
+}
+
+// StopSigner stops the remote signer of the fixture.
+func (f *Fixtures) StopSigner() {
+	require.NoError(f.T, f.signer.Stop())
+}
+
+// SignerConnected returns whether the remote signer of the fixture is connected to its node.
+func (f *Fixtures) SignerConnected() bool {
+	return f.signer != nil && f.signer.IsConnected()
+}
+
 // ___________________________________________________________________________________
 // utils
 
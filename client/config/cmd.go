@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+)
+
+// Cmd returns a CLI command to interactively create and update client.toml.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config [key] [value]",
+		Short: "Get and set client configuration and network profiles",
+		Long: `Get and set the values of client.toml in the home directory.
+
+Without arguments the whole configuration is printed. With a key its value is
+printed, and with a key and a value the value is stored. Keys of a network
+profile are addressed as profiles.<name>.<key>. The chain-id and node of the
+mainnet and testnet profiles are empty until they are set for the network used.
+
+Example:
+	lfb config profile testnet
+	lfb config chain-id my-chain
+	lfb config profiles.mainnet.node tcp://<rpc-host>:26657
+	lfb config profiles.local.node tcp://localhost:26657
+	lfb config profiles.consortium.bech32-prefix cons
+`,
+		Args: cobra.RangeArgs(0, 2),
+		RunE: runConfigCmd,
+	}
+	return cmd
+}
+
+func runConfigCmd(cmd *cobra.Command, args []string) error {
+	home := client.GetClientContextFromCmd(cmd).HomeDir
+
+	conf, err := ReadConfig(home)
+	switch {
+	case os.IsNotExist(err):
+		conf = DefaultClientConfig()
+	case err != nil:
+		return err
+	}
+
+	switch len(args) {
+	case 0:
+		bz, err := json.MarshalIndent(conf, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(bz))
+		return nil
+
+	case 1:
+		value, err := conf.get(args[0])
+		if err != nil {
+			return err
+		}
+		cmd.Println(value)
+		return nil
+
+	default:
+		if err := conf.set(args[0], args[1]); err != nil {
+			return err
+		}
+		return WriteConfigToFile(home, conf)
+	}
+}
+
+// splitProfileKey splits a key of the form profiles.<name>.<key>.
+func splitProfileKey(key string) (name, field string, ok bool) {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) != 3 || parts[0] != "profiles" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// profileField returns a pointer to the setting of the profile named by key.
+func profileField(p *Profile, key string) (*string, error) {
+	switch key {
+	case "chain-id":
+		return &p.ChainID, nil
+	case "node":
+		return &p.Node, nil
+	case "gas-prices":
+		return &p.GasPrices, nil
+	case "broadcast-mode":
+		return &p.BroadcastMode, nil
+	case "keyring-backend":
+		return &p.KeyringBackend, nil
+	case "bech32-mode":
+		return &p.Bech32Mode, nil
+	case "bech32-prefix":
+		return &p.Bech32Prefix, nil
+	case "coin-type":
+		return &p.CoinType, nil
+	default:
+		return nil, fmt.Errorf("unknown key: %s", key)
+	}
+}
+
+func (c *ClientConfig) get(key string) (string, error) {
+	switch key {
+	case "profile":
+		return c.Profile, nil
+	case "output":
+		return c.Output, nil
+	}
+
+	p := c.Settings
+	if name, field, ok := splitProfileKey(key); ok {
+		if p, ok = c.lookupProfile(name); !ok {
+			return "", fmt.Errorf("unknown network profile %q", name)
+		}
+		key = field
+	}
+
+	ptr, err := profileField(&p, key)
+	if err != nil {
+		return "", err
+	}
+	return *ptr, nil
+}
+
+func (c *ClientConfig) set(key, value string) error {
+	switch key {
+	case "profile":
+		if value != "" {
+			if _, err := c.ResolveProfile(value); err != nil {
+				return err
+			}
+		}
+		c.Profile = value
+		return nil
+	case "output":
+		c.Output = value
+		return nil
+	}
+
+	name, field, isProfileKey := splitProfileKey(key)
+	p := c.Settings
+	if isProfileKey {
+		p, _ = c.lookupProfile(name)
+		key = field
+	}
+
+	ptr, err := profileField(&p, key)
+	if err != nil {
+		return err
+	}
+	*ptr = value
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	if !isProfileKey {
+		c.Settings = p
+		return nil
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+	c.Profiles[name] = p
+	return nil
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/line/lbm-sdk/client/flags"
+	ostcli "github.com/line/ostracon/libs/cli"
+
+	lfbtypes "github.com/line/lfb/types"
+)
+
+const (
+	// FlagProfile selects the named network profile used to fill in client flags.
+	FlagProfile = "profile"
+
+	// FlagTestnet switches the bech32 address prefix to the testnet one.
+	FlagTestnet = "testnet"
+
+	// FlagBech32Prefix and FlagCoinType define a custom network, overriding the preset selected by FlagTestnet.
+	FlagBech32Prefix = "bech32-prefix"
+	FlagCoinType     = "coin-type"
+
+	// Bech32ModeMainnet and Bech32ModeTestnet are the valid values of the bech32-mode setting.
+	Bech32ModeMainnet = "mainnet"
+	Bech32ModeTestnet = "testnet"
+
+	// ProfileMainnet, ProfileTestnet and ProfileLocal are the built-in network profiles.
+	ProfileMainnet = "mainnet"
+	ProfileTestnet = "testnet"
+	ProfileLocal   = "local"
+
+	fileName = "client.toml"
+)
+
+// Profile is a named set of client settings for a single network.
+type Profile struct {
+	ChainID        string `mapstructure:"chain-id" json:"chain-id"`
+	Node           string `mapstructure:"node" json:"node"`
+	GasPrices      string `mapstructure:"gas-prices" json:"gas-prices"`
+	BroadcastMode  string `mapstructure:"broadcast-mode" json:"broadcast-mode"`
+	KeyringBackend string `mapstructure:"keyring-backend" json:"keyring-backend"`
+	Bech32Mode     string `mapstructure:"bech32-mode" json:"bech32-mode"`
+	Bech32Prefix   string `mapstructure:"bech32-prefix" json:"bech32-prefix"`
+	CoinType       string `mapstructure:"coin-type" json:"coin-type"`
+}
+
+// ClientConfig defines the content of client.toml.
+//
+// The top-level settings take precedence over the ones of the profile selected in
+// client.toml, and the ones of a profile given by --profile take precedence over the
+// top-level settings. Command line flags take precedence over all of them.
+type ClientConfig struct {
+	Profile  string             `mapstructure:"profile" json:"profile"`
+	Output   string             `mapstructure:"output" json:"output"`
+	Settings Profile            `mapstructure:",squash" json:"settings"`
+	Profiles map[string]Profile `mapstructure:"profiles" json:"profiles"`
+}
+
+// DefaultProfiles returns the built-in network profiles. The mainnet and testnet profiles
+// leave the chain ID and the node empty: LFB has no public network with a well-known chain ID
+// and RPC endpoint, so they are set by the operator of the network, e.g. with
+// `lfb config profiles.mainnet.chain-id <chain-id>`.
+func DefaultProfiles() map[string]Profile {
+	return map[string]Profile{
+		ProfileMainnet: {
+			BroadcastMode:  flags.BroadcastSync,
+			KeyringBackend: "os",
+			Bech32Mode:     Bech32ModeMainnet,
+		},
+		ProfileTestnet: {
+			BroadcastMode:  flags.BroadcastSync,
+			KeyringBackend: "os",
+			Bech32Mode:     Bech32ModeTestnet,
+		},
+		ProfileLocal: {
+			Node:           "tcp://localhost:26657",
+			BroadcastMode:  flags.BroadcastBlock,
+			KeyringBackend: "test",
+			Bech32Mode:     Bech32ModeMainnet,
+		},
+	}
+}
+
+// DefaultClientConfig returns the configuration written by `config` when no client.toml exists yet.
+func DefaultClientConfig() *ClientConfig {
+	return &ClientConfig{
+		Output:   "text",
+		Profiles: DefaultProfiles(),
+	}
+}
+
+// ConfigPath returns the path of client.toml under the given home directory.
+func ConfigPath(home string) string {
+	return filepath.Join(home, "config", fileName)
+}
+
+// ResolveProfile returns the effective client settings. A profile named explicitly,
+// e.g. by --profile, overrides the top-level settings of the config, which in turn
+// override the profile selected in the config when name is empty.
+func (c *ClientConfig) ResolveProfile(name string) (Profile, error) {
+	explicit := name != ""
+	if !explicit {
+		name = c.Profile
+	}
+
+	var p Profile
+	if name != "" {
+		var ok bool
+		if p, ok = c.lookupProfile(name); !ok {
+			return Profile{}, fmt.Errorf("unknown network profile %q; available profiles: %v", name, c.ProfileNames())
+		}
+	}
+
+	if explicit {
+		p = c.Settings.overriddenBy(p)
+	} else {
+		p = p.overriddenBy(c.Settings)
+	}
+	return p, p.Validate()
+}
+
+// overriddenBy returns the settings of p with the ones set in o taking precedence.
+func (p Profile) overriddenBy(o Profile) Profile {
+	p.ChainID = override(p.ChainID, o.ChainID)
+	p.Node = override(p.Node, o.Node)
+	p.GasPrices = override(p.GasPrices, o.GasPrices)
+	p.BroadcastMode = override(p.BroadcastMode, o.BroadcastMode)
+	p.KeyringBackend = override(p.KeyringBackend, o.KeyringBackend)
+	p.Bech32Mode = override(p.Bech32Mode, o.Bech32Mode)
+	p.Bech32Prefix = override(p.Bech32Prefix, o.Bech32Prefix)
+	p.CoinType = override(p.CoinType, o.CoinType)
+	return p
+}
+
+// lookupProfile returns the configured profile of the given name, falling back
+// to the built-in one.
+func (c *ClientConfig) lookupProfile(name string) (Profile, bool) {
+	if p, ok := c.Profiles[name]; ok {
+		return p, true
+	}
+	p, ok := DefaultProfiles()[name]
+	return p, ok
+}
+
+// ProfileNames returns the sorted names of the built-in and configured profiles.
+func (c *ClientConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range DefaultProfiles() {
+		if _, ok := c.Profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks the values which can not be validated by the flags they are applied to.
+func (p Profile) Validate() error {
+	switch p.Bech32Mode {
+	case "", Bech32ModeMainnet, Bech32ModeTestnet:
+	default:
+		return fmt.Errorf("invalid bech32-mode %q; must be one of %s|%s", p.Bech32Mode, Bech32ModeMainnet, Bech32ModeTestnet)
+	}
+
+	switch p.BroadcastMode {
+	case "", flags.BroadcastSync, flags.BroadcastAsync, flags.BroadcastBlock:
+	default:
+		return fmt.Errorf("invalid broadcast-mode %q; must be one of %s|%s|%s",
+			p.BroadcastMode, flags.BroadcastSync, flags.BroadcastAsync, flags.BroadcastBlock)
+	}
+
+	if p.Bech32Prefix != "" {
+		network := lfbtypes.Network{Bech32Prefix: p.Bech32Prefix}
+		if err := network.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.CoinType != "" {
+		if _, err := strconv.ParseUint(p.CoinType, 10, 32); err != nil {
+			return fmt.Errorf("invalid coin-type %q: %w", p.CoinType, err)
+		}
+	}
+
+	return nil
+}
+
+// flagValues maps the settings of the profile to the flags they fill in.
+func (p Profile) flagValues() map[string]string {
+	values := map[string]string{
+		flags.FlagChainID:        p.ChainID,
+		flags.FlagNode:           p.Node,
+		flags.FlagGasPrices:      p.GasPrices,
+		flags.FlagBroadcastMode:  p.BroadcastMode,
+		flags.FlagKeyringBackend: p.KeyringBackend,
+		FlagBech32Prefix:         p.Bech32Prefix,
+		FlagCoinType:             p.CoinType,
+	}
+	switch p.Bech32Mode {
+	case Bech32ModeMainnet:
+		values[FlagTestnet] = "false"
+	case Bech32ModeTestnet:
+		values[FlagTestnet] = "true"
+	}
+	return values
+}
+
+// ApplyClientConfig reads client.toml from the home directory of the command and
+// sets every flag of the command which is not given on the command line to the
+// value of the selected network profile. It is a no-op when neither client.toml
+// nor --profile is present.
+func ApplyClientConfig(cmd *cobra.Command) error {
+	flagSet := cmd.Flags()
+	home, _ := flagSet.GetString(flags.FlagHome)
+	profile, _ := flagSet.GetString(FlagProfile)
+
+	conf, err := ReadConfig(home)
+	switch {
+	case os.IsNotExist(err):
+		if profile == "" {
+			return nil
+		}
+		conf = DefaultClientConfig()
+	case err != nil:
+		return err
+	}
+
+	settings, err := conf.ResolveProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	values := settings.flagValues()
+	values[ostcli.OutputFlag] = conf.Output
+	return setUnchangedFlags(flagSet, values)
+}
+
+func setUnchangedFlags(flagSet *pflag.FlagSet, values map[string]string) error {
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		f := flagSet.Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := flagSet.Set(name, value); err != nil {
+			return fmt.Errorf("invalid %s %q in %s: %w", name, value, fileName, err)
+		}
+	}
+	return nil
+}
+
+func override(base, value string) string {
+	if value != "" {
+		return value
+	}
+	return base
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client/flags"
+	ostcli "github.com/line/ostracon/libs/cli"
+)
+
+func testConfig() *ClientConfig {
+	conf := DefaultClientConfig()
+	conf.Profile = ProfileTestnet
+	conf.Settings = Profile{ChainID: "top-chain", Node: "tcp://top:26657"}
+	conf.Profiles["consortium"] = Profile{ChainID: "consortium-chain", KeyringBackend: "file", Bech32Prefix: "cons"}
+	return conf
+}
+
+func TestResolveProfile(t *testing.T) {
+	conf := testConfig()
+
+	// the top-level settings override the profile selected in the config
+	p, err := conf.ResolveProfile("")
+	require.NoError(t, err)
+	require.Equal(t, Profile{ChainID: "top-chain", Node: "tcp://top:26657", BroadcastMode: flags.BroadcastSync,
+		KeyringBackend: "os", Bech32Mode: Bech32ModeTestnet}, p)
+
+	// and are overridden by the profile given explicitly, filling in what it does not set
+	p, err = conf.ResolveProfile("consortium")
+	require.NoError(t, err)
+	require.Equal(t, Profile{ChainID: "consortium-chain", Node: "tcp://top:26657", KeyringBackend: "file", Bech32Prefix: "cons"}, p)
+	p, err = conf.ResolveProfile(ProfileLocal)
+	require.NoError(t, err)
+	require.Equal(t, "top-chain", p.ChainID)
+	require.Equal(t, "tcp://localhost:26657", p.Node)
+	require.Equal(t, "test", p.KeyringBackend)
+
+	_, err = conf.ResolveProfile("unknown")
+	require.Error(t, err)
+
+	// without a selected profile, only the top-level settings apply
+	conf.Profile = ""
+	p, err = conf.ResolveProfile("")
+	require.NoError(t, err)
+	require.Equal(t, conf.Settings, p)
+
+	conf.Settings.BroadcastMode = "fast"
+	_, err = conf.ResolveProfile("")
+	require.Error(t, err)
+}
+
+// newTestCmd returns a command with the flags filled in by ApplyClientConfig.
+func newTestCmd(home string, args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.Flags().String(flags.FlagHome, home, "")
+	cmd.Flags().String(FlagProfile, "", "")
+	cmd.Flags().String(flags.FlagChainID, "", "")
+	cmd.Flags().String(flags.FlagNode, "tcp://localhost:26657", "")
+	cmd.Flags().String(flags.FlagKeyringBackend, "os", "")
+	cmd.Flags().String(flags.FlagBroadcastMode, flags.BroadcastSync, "")
+	cmd.Flags().Bool(FlagTestnet, false, "")
+	cmd.Flags().String(ostcli.OutputFlag, "text", "")
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func TestApplyClientConfig(t *testing.T) {
+	home := t.TempDir()
+	flag := func(cmd *cobra.Command, name string) string {
+		return cmd.Flags().Lookup(name).Value.String()
+	}
+
+	// nothing to apply without client.toml or --profile
+	cmd := newTestCmd(home)
+	require.NoError(t, ApplyClientConfig(cmd))
+	require.Equal(t, "", flag(cmd, flags.FlagChainID))
+	cmd = newTestCmd(home, "--profile", ProfileLocal)
+	require.NoError(t, ApplyClientConfig(cmd))
+	require.Equal(t, "test", flag(cmd, flags.FlagKeyringBackend))
+
+	conf := testConfig()
+	conf.Output = "json"
+	require.NoError(t, WriteConfigToFile(home, conf))
+
+	cmd = newTestCmd(home)
+	require.NoError(t, ApplyClientConfig(cmd))
+	require.Equal(t, "top-chain", flag(cmd, flags.FlagChainID))
+	require.Equal(t, "tcp://top:26657", flag(cmd, flags.FlagNode))
+	require.Equal(t, "true", flag(cmd, FlagTestnet))
+	require.Equal(t, "json", flag(cmd, ostcli.OutputFlag))
+
+	// --profile overrides the top-level settings, and the flags override both
+	cmd = newTestCmd(home, "--profile", ProfileLocal, "--chain-id", "flag-chain")
+	require.NoError(t, ApplyClientConfig(cmd))
+	require.Equal(t, "flag-chain", flag(cmd, flags.FlagChainID))
+	require.Equal(t, "tcp://localhost:26657", flag(cmd, flags.FlagNode))
+	require.Equal(t, "test", flag(cmd, flags.FlagKeyringBackend))
+	require.Equal(t, flags.BroadcastBlock, flag(cmd, flags.FlagBroadcastMode))
+	require.Equal(t, "false", flag(cmd, FlagTestnet))
+
+	require.Error(t, ApplyClientConfig(newTestCmd(home, "--profile", "unknown")))
+}
+
+func TestWriteConfigToFile(t *testing.T) {
+	home := t.TempDir()
+	conf := testConfig()
+	// the values are escaped in the TOML strings
+	conf.Settings.ChainID = `chain"with\quotes`
+	conf.Settings.GasPrices = "0.1stake\n# injected = true"
+	conf.Settings.Node = "tcp://node\t:26657\x7f"
+	conf.Profiles["my network"] = Profile{ChainID: "<my-chain>", Node: "tcp://é:26657"}
+	require.NoError(t, WriteConfigToFile(home, conf))
+
+	read, err := ReadConfig(home)
+	require.NoError(t, err)
+	require.Equal(t, conf, read)
+
+	require.NoError(t, read.set("chain-id", "new-chain"))
+	require.NoError(t, read.set("profiles.consortium.node", "tcp://consortium:26657"))
+	require.Error(t, read.set("profiles.consortium.bech32-mode", "devnet"))
+	require.Error(t, read.set("profile", "unknown"))
+	require.NoError(t, WriteConfigToFile(home, read))
+	read, err = ReadConfig(home)
+	require.NoError(t, err)
+	value, err := read.get("chain-id")
+	require.NoError(t, err)
+	require.Equal(t, "new-chain", value)
+	value, err = read.get("profiles.consortium.node")
+	require.NoError(t, err)
+	require.Equal(t, "tcp://consortium:26657", value)
+	value, err = read.get("profiles.mainnet.broadcast-mode")
+	require.NoError(t, err)
+	require.Equal(t, flags.BroadcastSync, value)
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+const defaultConfigTemplate = `# This is a TOML config file.
+# For more information, see https://github.com/toml-lang/toml
+
+###############################################################################
+###                           Client Configuration                            ###
+###############################################################################
+
+# The network profile to use when --profile is not given (e.g. mainnet|testnet|local).
+# Settings below override the values of this profile, and are overridden by the
+# values of a profile given by --profile; command line flags override all of them.
+profile = {{ quote .Profile }}
+# Output format (text|json)
+output = {{ quote .Output }}
+# The network chain ID
+chain-id = {{ quote .Settings.ChainID }}
+# <host>:<port> to ostracon RPC interface for this chain
+node = {{ quote .Settings.Node }}
+# Gas prices in decimal format to determine the transaction fee
+gas-prices = {{ quote .Settings.GasPrices }}
+# Transaction broadcasting mode (sync|async|block)
+broadcast-mode = {{ quote .Settings.BroadcastMode }}
+# Keyring's backend (os|file|kwallet|pass|test)
+keyring-backend = {{ quote .Settings.KeyringBackend }}
+# Bech32 address prefix mode (mainnet|testnet)
+bech32-mode = {{ quote .Settings.Bech32Mode }}
+# Bech32 address prefix of a custom network, overriding bech32-mode
+bech32-prefix = {{ quote .Settings.Bech32Prefix }}
+# HD coin type of a custom network
+coin-type = {{ quote .Settings.CoinType }}
+
+###############################################################################
+###                             Network Profiles                            ###
+###############################################################################
+{{ range $name, $p := .Profiles }}
+[profiles.{{ quote $name }}]
+chain-id = {{ quote $p.ChainID }}
+node = {{ quote $p.Node }}
+gas-prices = {{ quote $p.GasPrices }}
+broadcast-mode = {{ quote $p.BroadcastMode }}
+keyring-backend = {{ quote $p.KeyringBackend }}
+bech32-mode = {{ quote $p.Bech32Mode }}
+bech32-prefix = {{ quote $p.Bech32Prefix }}
+coin-type = {{ quote $p.CoinType }}
+{{ end }}`
+
+var configTemplate = template.Must(template.New("clientConfigFileTemplate").
+	Funcs(template.FuncMap{"quote": quote}).
+	Parse(defaultConfigTemplate))
+
+// quote returns the string as a TOML basic string. The escapes of JSON are valid in TOML,
+// which also requires DEL to be escaped.
+func quote(s string) (string, error) {
+	bz, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(string(bz), "\x7f", `\u007f`), nil
+}
+
+// WriteConfigToFile writes the client configuration to client.toml under the given home directory.
+func WriteConfigToFile(home string, config *ClientConfig) error {
+	var buffer bytes.Buffer
+	if err := configTemplate.Execute(&buffer, config); err != nil {
+		return err
+	}
+
+	configPath := ConfigPath(home)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configPath, buffer.Bytes(), 0600)
+}
+
+// ReadConfig reads client.toml from the given home directory. The returned error
+// satisfies os.IsNotExist if the file does not exist.
+func ReadConfig(home string) (*ClientConfig, error) {
+	configPath := ConfigPath(home)
+	if _, err := os.Stat(configPath); err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	conf := new(ClientConfig)
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
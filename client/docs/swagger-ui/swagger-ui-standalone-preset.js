@@ -0,0 +1,13173 @@
+! function(t, e) {
+    "object" == typeof exports && "object" == typeof module ? module.exports = e() : "function" == typeof define && define.amd ? define([], e) : "object" == typeof exports ? exports.SwaggerUIStandalonePreset = e() : t.SwaggerUIStandalonePreset = e()
+}(this, function() {
+    return function(t) {
+        var e = {};
+
+        function n(r) {
+            if (e[r]) return e[r].exports;
+            var i = e[r] = {
+                i: r,
+                l: !1,
+                exports: {}
+            };
+            return t[r].call(i.exports, i, i.exports, n), i.l = !0, i.exports
+        }
+        return n.m = t, n.c = e, n.d = function(t, e, r) {
+            n.o(t, e) || Object.defineProperty(t, e, {
+                configurable: !1,
+                enumerable: !0,
+                get: r
+            })
+        }, n.n = function(t) {
+            var e = t && t.__esModule ? function() {
+                return t.default
+            } : function() {
+                return t
+            };
+            return n.d(e, "a", e), e
+        }, n.o = function(t, e) {
+            return Object.prototype.hasOwnProperty.call(t, e)
+        }, n.p = "/dist", n(n.s = 206)
+    }([function(t, e, n) {
+        "use strict";
+        var r = n(52),
+            i = ["kind", "resolve", "construct", "instanceOf", "predicate", "represent", "defaultStyle", "styleAliases"],
+            o = ["scalar", "sequence", "mapping"];
+        t.exports = function(t, e) {
+            var n, u;
+            if (e = e || {}, Object.keys(e).forEach(function(e) {
+                if (-1 === i.indexOf(e)) throw new r('Unknown option "' + e + '" is met in definition of "' + t + '" YAML type.')
+            }), this.tag = t, this.kind = e.kind || null, this.resolve = e.resolve || function() {
+                return !0
+            }, this.construct = e.construct || function(t) {
+                return t
+            }, this.instanceOf = e.instanceOf || null, this.predicate = e.predicate || null, this.represent = e.represent || null, this.defaultStyle = e.defaultStyle || null, this.styleAliases = (n = e.styleAliases || null, u = {}, null !== n && Object.keys(n).forEach(function(t) {
+                n[t].forEach(function(e) {
+                    u[String(e)] = t
+                })
+            }), u), -1 === o.indexOf(this.kind)) throw new r('Unknown kind "' + this.kind + '" is specified for "' + t + '" YAML type.')
+        }
+    }, function(t, e, n) {
+        var r = n(133)("wks"),
+            i = n(98),
+            o = n(5).Symbol,
+            u = "function" == typeof o;
+        (t.exports = function(t) {
+            return r[t] || (r[t] = u && o[t] || (u ? o : i)("Symbol." + t))
+        }).store = r
+    }, function(t, e) {
+        var n = t.exports = {
+            version: "2.5.5"
+        };
+        "number" == typeof __e && (__e = n)
+    }, function(t, e, n) {
+        var r = n(5),
+            i = n(19),
+            o = n(17),
+            u = n(30),
+            a = n(60),
+            s = function(t, e, n) {
+                var c, f, l, p, h = t & s.F,
+                    d = t & s.G,
+                    v = t & s.S,
+                    y = t & s.P,
+                    g = t & s.B,
+                    m = d ? r : v ? r[e] || (r[e] = {}) : (r[e] || {}).prototype,
+                    _ = d ? i : i[e] || (i[e] = {}),
+                    b = _.prototype || (_.prototype = {});
+                for (c in d && (n = e), n) l = ((f = !h && m && void 0 !== m[c]) ? m : n)[c], p = g && f ? a(l, r) : y && "function" == typeof l ? a(Function.call, l) : l, m && u(m, c, l, t & s.U), _[c] != l && o(_, c, p), y && b[c] != l && (b[c] = l)
+            };
+        r.core = i, s.F = 1, s.G = 2, s.S = 4, s.P = 8, s.B = 16, s.W = 32, s.U = 64, s.R = 128, t.exports = s
+    }, function(t, e, n) {
+        var r = n(3),
+            i = n(43),
+            o = n(10),
+            u = /"/g,
+            a = function(t, e, n, r) {
+                var i = String(o(t)),
+                    a = "<" + e;
+                return "" !== n && (a += " " + n + '="' + String(r).replace(u, "&quot;") + '"'), a + ">" + i + "</" + e + ">"
+            };
+        t.exports = function(t, e) {
+            var n = {};
+            n[t] = e(a), r(r.P + r.F * i(function() {
+                var e = "" [t]('"');
+                return e !== e.toLowerCase() || e.split('"').length > 3
+            }), "String", n)
+        }
+    }, function(t, e) {
+        var n = t.exports = "undefined" != typeof window && window.Math == Math ? window : "undefined" != typeof self && self.Math == Math ? self : Function("return this")();
+        "number" == typeof __g && (__g = n)
+    }, function(t, e, n) {
+        var r = n(93)("wks"),
+            i = n(55),
+            o = n(9).Symbol,
+            u = "function" == typeof o;
+        (t.exports = function(t) {
+            return r[t] || (r[t] = u && o[t] || (u ? o : i)("Symbol." + t))
+        }).store = r
+    }, function(t, e, n) {
+        var r = n(169),
+            i = "object" == typeof self && self && self.Object === Object && self,
+            o = r || i || Function("return this")();
+        t.exports = o
+    }, function(t, e) {
+        var n = Array.isArray;
+        t.exports = n
+    }, function(t, e) {
+        var n = t.exports = "undefined" != typeof window && window.Math == Math ? window : "undefined" != typeof self && self.Math == Math ? self : Function("return this")();
+        "number" == typeof __g && (__g = n)
+    }, function(t, e) {
+        t.exports = function(t) {
+            if (void 0 == t) throw TypeError("Can't call method on  " + t);
+            return t
+        }
+    }, function(t, e) {
+        var n;
+        n = function() {
+            return this
+        }();
+        try {
+            n = n || Function("return this")() || (0, eval)("this")
+        } catch (t) {
+            "object" == typeof window && (n = window)
+        }
+        t.exports = n
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            if ("function" != typeof t) throw new TypeError(t + " is not a function");
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(9),
+            i = n(2),
+            o = n(126),
+            u = n(26),
+            a = n(16),
+            s = function(t, e, n) {
+                var c, f, l, p = t & s.F,
+                    h = t & s.G,
+                    d = t & s.S,
+                    v = t & s.P,
+                    y = t & s.B,
+                    g = t & s.W,
+                    m = h ? i : i[e] || (i[e] = {}),
+                    _ = m.prototype,
+                    b = h ? r : d ? r[e] : (r[e] || {}).prototype;
+                for (c in h && (n = e), n)(f = !p && b && void 0 !== b[c]) && a(m, c) || (l = f ? b[c] : n[c], m[c] = h && "function" != typeof b[c] ? n[c] : y && f ? o(l, r) : g && b[c] == l ? function(t) {
+                    var e = function(e, n, r) {
+                        if (this instanceof t) {
+                            switch (arguments.length) {
+                                case 0:
+                                    return new t;
+                                case 1:
+                                    return new t(e);
+                                case 2:
+                                    return new t(e, n)
+                            }
+                            return new t(e, n, r)
+                        }
+                        return t.apply(this, arguments)
+                    };
+                    return e.prototype = t.prototype, e
+                }(l) : v && "function" == typeof l ? o(Function.call, l) : l, v && ((m.virtual || (m.virtual = {}))[c] = l, t & s.R && _ && !_[c] && u(_, c, l)))
+            };
+        s.F = 1, s.G = 2, s.S = 4, s.P = 8, s.B = 16, s.W = 32, s.U = 64, s.R = 128, t.exports = s
+    }, function(t, e, n) {
+        var r = n(27),
+            i = n(127),
+            o = n(89),
+            u = Object.defineProperty;
+        e.f = n(15) ? Object.defineProperty : function(t, e, n) {
+            if (r(t), e = o(e, !0), r(n), i) try {
+                return u(t, e, n)
+            } catch (t) {}
+            if ("get" in n || "set" in n) throw TypeError("Accessors not supported!");
+            return "value" in n && (t[e] = n.value), t
+        }
+    }, function(t, e, n) {
+        t.exports = !n(29)(function() {
+            return 7 != Object.defineProperty({}, "a", {
+                get: function() {
+                    return 7
+                }
+            }).a
+        })
+    }, function(t, e) {
+        var n = {}.hasOwnProperty;
+        t.exports = function(t, e) {
+            return n.call(t, e)
+        }
+    }, function(t, e, n) {
+        var r = n(57),
+            i = n(134);
+        t.exports = n(42) ? function(t, e, n) {
+            return r.f(t, e, i(1, n))
+        } : function(t, e, n) {
+            return t[e] = n, t
+        }
+    }, function(t, e, n) {
+        var r = n(31);
+        t.exports = function(t) {
+            if (!r(t)) throw TypeError(t + " is not an object!");
+            return t
+        }
+    }, function(t, e) {
+        var n = t.exports = {
+            version: "2.5.5"
+        };
+        "number" == typeof __e && (__e = n)
+    }, function(t, e, n) {
+        "use strict";
+        var r = function(t) {};
+        t.exports = function(t, e, n, i, o, u, a, s) {
+            if (r(e), !t) {
+                var c;
+                if (void 0 === e) c = new Error("Minified exception occurred; use the non-minified dev environment for the full error message and additional helpful warnings.");
+                else {
+                    var f = [n, i, o, u, a, s],
+                        l = 0;
+                    (c = new Error(e.replace(/%s/g, function() {
+                        return f[l++]
+                    }))).name = "Invariant Violation"
+                }
+                throw c.framesToPop = 1, c
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(79),
+            i = Object.keys || function(t) {
+                var e = [];
+                for (var n in t) e.push(n);
+                return e
+            };
+        t.exports = l;
+        var o = n(51);
+        o.inherits = n(35);
+        var u = n(187),
+            a = n(117);
+        o.inherits(l, u);
+        for (var s = i(a.prototype), c = 0; c < s.length; c++) {
+            var f = s[c];
+            l.prototype[f] || (l.prototype[f] = a.prototype[f])
+        }
+
+        function l(t) {
+            if (!(this instanceof l)) return new l(t);
+            u.call(this, t), a.call(this, t), t && !1 === t.readable && (this.readable = !1), t && !1 === t.writable && (this.writable = !1), this.allowHalfOpen = !0, t && !1 === t.allowHalfOpen && (this.allowHalfOpen = !1), this.once("end", p)
+        }
+
+        function p() {
+            this.allowHalfOpen || this._writableState.ended || r.nextTick(h, this)
+        }
+
+        function h(t) {
+            t.end()
+        }
+        Object.defineProperty(l.prototype, "writableHighWaterMark", {
+            enumerable: !1,
+            get: function() {
+                return this._writableState.highWaterMark
+            }
+        }), Object.defineProperty(l.prototype, "destroyed", {
+            get: function() {
+                return void 0 !== this._readableState && void 0 !== this._writableState && (this._readableState.destroyed && this._writableState.destroyed)
+            },
+            set: function(t) {
+                void 0 !== this._readableState && void 0 !== this._writableState && (this._readableState.destroyed = t, this._writableState.destroyed = t)
+            }
+        }), l.prototype._destroy = function(t, e) {
+            this.push(null), this.end(), r.nextTick(e, t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(194)();
+        t.exports = function(t) {
+            return t !== r && null !== t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(464),
+            i = Math.max;
+        t.exports = function(t) {
+            return i(0, r(t))
+        }
+    }, function(t, e, n) {
+        "use strict"
+    }, function(t, e, n) {
+        var r = n(124),
+            i = n(87);
+        t.exports = function(t) {
+            return r(i(t))
+        }
+    }, function(t, e, n) {
+        var r = n(14),
+            i = n(54);
+        t.exports = n(15) ? function(t, e, n) {
+            return r.f(t, e, i(1, n))
+        } : function(t, e, n) {
+            return t[e] = n, t
+        }
+    }, function(t, e, n) {
+        var r = n(28);
+        t.exports = function(t) {
+            if (!r(t)) throw TypeError(t + " is not an object!");
+            return t
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return "object" == typeof t ? null !== t : "function" == typeof t
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            try {
+                return !!t()
+            } catch (t) {
+                return !0
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(5),
+            i = n(17),
+            o = n(58),
+            u = n(98)("src"),
+            a = Function.toString,
+            s = ("" + a).split("toString");
+        n(19).inspectSource = function(t) {
+            return a.call(t)
+        }, (t.exports = function(t, e, n, a) {
+            var c = "function" == typeof n;
+            c && (o(n, "name") || i(n, "name", e)), t[e] !== n && (c && (o(n, u) || i(n, u, t[e] ? "" + t[e] : s.join(String(e)))), t === r ? t[e] = n : a ? t[e] ? t[e] = n : i(t, e, n) : (delete t[e], i(t, e, n)))
+        })(Function.prototype, "toString", function() {
+            return "function" == typeof this && this[u] || a.call(this)
+        })
+    }, function(t, e) {
+        t.exports = function(t) {
+            return "object" == typeof t ? null !== t : "function" == typeof t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(46),
+            i = n(162),
+            o = (n(66), n(160), Object.prototype.hasOwnProperty),
+            u = n(163),
+            a = {
+                key: !0,
+                ref: !0,
+                __self: !0,
+                __source: !0
+            };
+
+        function s(t) {
+            return void 0 !== t.ref
+        }
+
+        function c(t) {
+            return void 0 !== t.key
+        }
+        var f = function(t, e, n, r, i, o, a) {
+            var s = {
+                $$typeof: u,
+                type: t,
+                key: e,
+                ref: n,
+                props: a,
+                _owner: o
+            };
+            return s
+        };
+        f.createElement = function(t, e, n) {
+            var r, u = {},
+                l = null,
+                p = null;
+            if (null != e)
+                for (r in s(e) && (p = e.ref), c(e) && (l = "" + e.key), void 0 === e.__self ? null : e.__self, void 0 === e.__source ? null : e.__source, e) o.call(e, r) && !a.hasOwnProperty(r) && (u[r] = e[r]);
+            var h = arguments.length - 2;
+            if (1 === h) u.children = n;
+            else if (h > 1) {
+                for (var d = Array(h), v = 0; v < h; v++) d[v] = arguments[v + 2];
+                0, u.children = d
+            }
+            if (t && t.defaultProps) {
+                var y = t.defaultProps;
+                for (r in y) void 0 === u[r] && (u[r] = y[r])
+            }
+            return f(t, l, p, 0, 0, i.current, u)
+        }, f.createFactory = function(t) {
+            var e = f.createElement.bind(null, t);
+            return e.type = t, e
+        }, f.cloneAndReplaceKey = function(t, e) {
+            return f(t.type, e, t.ref, t._self, t._source, t._owner, t.props)
+        }, f.cloneElement = function(t, e, n) {
+            var u, l, p = r({}, t.props),
+                h = t.key,
+                d = t.ref,
+                v = (t._self, t._source, t._owner);
+            if (null != e)
+                for (u in s(e) && (d = e.ref, v = i.current), c(e) && (h = "" + e.key), t.type && t.type.defaultProps && (l = t.type.defaultProps), e) o.call(e, u) && !a.hasOwnProperty(u) && (void 0 === e[u] && void 0 !== l ? p[u] = l[u] : p[u] = e[u]);
+            var y = arguments.length - 2;
+            if (1 === y) p.children = n;
+            else if (y > 1) {
+                for (var g = Array(y), m = 0; m < y; m++) g[m] = arguments[m + 2];
+                p.children = g
+            }
+            return f(t.type, h, d, 0, 0, v, p)
+        }, f.isValidElement = function(t) {
+            return "object" == typeof t && null !== t && t.$$typeof === u
+        }, t.exports = f
+    }, function(t, e, n) {
+        var r = n(365),
+            i = n(368);
+        t.exports = function(t, e) {
+            var n = i(t, e);
+            return r(n) ? n : void 0
+        }
+    }, function(t, e) {
+        var n, r, i = t.exports = {};
+
+        function o() {
+            throw new Error("setTimeout has not been defined")
+        }
+
+        function u() {
+            throw new Error("clearTimeout has not been defined")
+        }
+
+        function a(t) {
+            if (n === setTimeout) return setTimeout(t, 0);
+            if ((n === o || !n) && setTimeout) return n = setTimeout, setTimeout(t, 0);
+            try {
+                return n(t, 0)
+            } catch (e) {
+                try {
+                    return n.call(null, t, 0)
+                } catch (e) {
+                    return n.call(this, t, 0)
+                }
+            }
+        }! function() {
+            try {
+                n = "function" == typeof setTimeout ? setTimeout : o
+            } catch (t) {
+                n = o
+            }
+            try {
+                r = "function" == typeof clearTimeout ? clearTimeout : u
+            } catch (t) {
+                r = u
+            }
+        }();
+        var s, c = [],
+            f = !1,
+            l = -1;
+
+        function p() {
+            f && s && (f = !1, s.length ? c = s.concat(c) : l = -1, c.length && h())
+        }
+
+        function h() {
+            if (!f) {
+                var t = a(p);
+                f = !0;
+                for (var e = c.length; e;) {
+                    for (s = c, c = []; ++l < e;) s && s[l].run();
+                    l = -1, e = c.length
+                }
+                s = null, f = !1,
+                    function(t) {
+                        if (r === clearTimeout) return clearTimeout(t);
+                        if ((r === u || !r) && clearTimeout) return r = clearTimeout, clearTimeout(t);
+                        try {
+                            r(t)
+                        } catch (e) {
+                            try {
+                                return r.call(null, t)
+                            } catch (e) {
+                                return r.call(this, t)
+                            }
+                        }
+                    }(t)
+            }
+        }
+
+        function d(t, e) {
+            this.fun = t, this.array = e
+        }
+
+        function v() {}
+        i.nextTick = function(t) {
+            var e = new Array(arguments.length - 1);
+            if (arguments.length > 1)
+                for (var n = 1; n < arguments.length; n++) e[n - 1] = arguments[n];
+            c.push(new d(t, e)), 1 !== c.length || f || a(h)
+        }, d.prototype.run = function() {
+            this.fun.apply(null, this.array)
+        }, i.title = "browser", i.browser = !0, i.env = {}, i.argv = [], i.version = "", i.versions = {}, i.on = v, i.addListener = v, i.once = v, i.off = v, i.removeListener = v, i.removeAllListeners = v, i.emit = v, i.prependListener = v, i.prependOnceListener = v, i.listeners = function(t) {
+            return []
+        }, i.binding = function(t) {
+            throw new Error("process.binding is not supported")
+        }, i.cwd = function() {
+            return "/"
+        }, i.chdir = function(t) {
+            throw new Error("process.chdir is not supported")
+        }, i.umask = function() {
+            return 0
+        }
+    }, function(t, e) {
+        "function" == typeof Object.create ? t.exports = function(t, e) {
+            t.super_ = e, t.prototype = Object.create(e.prototype, {
+                constructor: {
+                    value: t,
+                    enumerable: !1,
+                    writable: !0,
+                    configurable: !0
+                }
+            })
+        } : t.exports = function(t, e) {
+            t.super_ = e;
+            var n = function() {};
+            n.prototype = e.prototype, t.prototype = new n, t.prototype.constructor = t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(22);
+        t.exports = function(t) {
+            if (!r(t)) throw new TypeError("Cannot use null or undefined");
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+
+        function r(t) {
+            return void 0 === t || null === t
+        }
+        t.exports.isNothing = r, t.exports.isObject = function(t) {
+            return "object" == typeof t && null !== t
+        }, t.exports.toArray = function(t) {
+            return Array.isArray(t) ? t : r(t) ? [] : [t]
+        }, t.exports.repeat = function(t, e) {
+            var n, r = "";
+            for (n = 0; n < e; n += 1) r += t;
+            return r
+        }, t.exports.isNegativeZero = function(t) {
+            return 0 === t && Number.NEGATIVE_INFINITY === 1 / t
+        }, t.exports.extend = function(t, e) {
+            var n, r, i, o;
+            if (e)
+                for (n = 0, r = (o = Object.keys(e)).length; n < r; n += 1) t[i = o[n]] = e[i];
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(37),
+            i = n(52),
+            o = n(0);
+
+        function u(t, e, n) {
+            var r = [];
+            return t.include.forEach(function(t) {
+                n = u(t, e, n)
+            }), t[e].forEach(function(t) {
+                n.forEach(function(e, n) {
+                    e.tag === t.tag && e.kind === t.kind && r.push(n)
+                }), n.push(t)
+            }), n.filter(function(t, e) {
+                return -1 === r.indexOf(e)
+            })
+        }
+
+        function a(t) {
+            this.include = t.include || [], this.implicit = t.implicit || [], this.explicit = t.explicit || [], this.implicit.forEach(function(t) {
+                if (t.loadKind && "scalar" !== t.loadKind) throw new i("There is a non-scalar type in the implicit list of a schema. Implicit resolving of such types is not supported.")
+            }), this.compiledImplicit = u(this, "implicit", []), this.compiledExplicit = u(this, "explicit", []), this.compiledTypeMap = function() {
+                var t, e, n = {
+                    scalar: {},
+                    sequence: {},
+                    mapping: {},
+                    fallback: {}
+                };
+
+                function r(t) {
+                    n[t.kind][t.tag] = n.fallback[t.tag] = t
+                }
+                for (t = 0, e = arguments.length; t < e; t += 1) arguments[t].forEach(r);
+                return n
+            }(this.compiledImplicit, this.compiledExplicit)
+        }
+        a.DEFAULT = null, a.create = function() {
+            var t, e;
+            switch (arguments.length) {
+                case 1:
+                    t = a.DEFAULT, e = arguments[0];
+                    break;
+                case 2:
+                    t = arguments[0], e = arguments[1];
+                    break;
+                default:
+                    throw new i("Wrong number of arguments for Schema.create function")
+            }
+            if (t = r.toArray(t), e = r.toArray(e), !t.every(function(t) {
+                return t instanceof a
+            })) throw new i("Specified list of super schemas (or a single Schema object) contains a non-Schema object.");
+            if (!e.every(function(t) {
+                return t instanceof o
+            })) throw new i("Specified list of YAML types (or a single Type object) contains a non-Type object.");
+            return new a({
+                include: t,
+                explicit: e
+            })
+        }, t.exports = a
+    }, function(t, e) {
+        t.exports = {}
+    }, function(t, e, n) {
+        var r = n(130),
+            i = n(94);
+        t.exports = Object.keys || function(t) {
+            return r(t, i)
+        }
+    }, function(t, e) {
+        var n = {}.toString;
+        t.exports = function(t) {
+            return n.call(t).slice(8, -1)
+        }
+    }, function(t, e, n) {
+        t.exports = !n(43)(function() {
+            return 7 != Object.defineProperty({}, "a", {
+                get: function() {
+                    return 7
+                }
+            }).a
+        })
+    }, function(t, e) {
+        t.exports = function(t) {
+            try {
+                return !!t()
+            } catch (t) {
+                return !0
+            }
+        }
+    }, function(t, e) {
+        t.exports = {}
+    }, function(t, e, n) {
+        var r = n(59),
+            i = Math.min;
+        t.exports = function(t) {
+            return t > 0 ? i(r(t), 9007199254740991) : 0
+        }
+    }, function(t, e, n) {
+        "use strict";
+        /*
+        object-assign
+        (c) Sindre Sorhus
+        @license MIT
+        */
+        var r = Object.getOwnPropertySymbols,
+            i = Object.prototype.hasOwnProperty,
+            o = Object.prototype.propertyIsEnumerable;
+        t.exports = function() {
+            try {
+                if (!Object.assign) return !1;
+                var t = new String("abc");
+                if (t[5] = "de", "5" === Object.getOwnPropertyNames(t)[0]) return !1;
+                for (var e = {}, n = 0; n < 10; n++) e["_" + String.fromCharCode(n)] = n;
+                if ("0123456789" !== Object.getOwnPropertyNames(e).map(function(t) {
+                    return e[t]
+                }).join("")) return !1;
+                var r = {};
+                return "abcdefghijklmnopqrst".split("").forEach(function(t) {
+                    r[t] = t
+                }), "abcdefghijklmnopqrst" === Object.keys(Object.assign({}, r)).join("")
+            } catch (t) {
+                return !1
+            }
+        }() ? Object.assign : function(t, e) {
+            for (var n, u, a = function(t) {
+                if (null === t || void 0 === t) throw new TypeError("Object.assign cannot be called with null or undefined");
+                return Object(t)
+            }(t), s = 1; s < arguments.length; s++) {
+                for (var c in n = Object(arguments[s])) i.call(n, c) && (a[c] = n[c]);
+                if (r) {
+                    u = r(n);
+                    for (var f = 0; f < u.length; f++) o.call(n, u[f]) && (a[u[f]] = n[u[f]])
+                }
+            }
+            return a
+        }
+    }, function(t, e, n) {
+        var r = n(343);
+        t.exports = function(t) {
+            return null == t ? "" : r(t)
+        }
+    }, function(t, e, n) {
+        var r = n(69),
+            i = n(345),
+            o = n(346),
+            u = "[object Null]",
+            a = "[object Undefined]",
+            s = r ? r.toStringTag : void 0;
+        t.exports = function(t) {
+            return null == t ? void 0 === t ? a : u : s && s in Object(t) ? i(t) : o(t)
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return null != t && "object" == typeof t
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            var e = typeof t;
+            return null != t && ("object" == e || "function" == e)
+        }
+    }, function(t, e, n) {
+        (function(t) {
+            function n(t) {
+                return Object.prototype.toString.call(t)
+            }
+            e.isArray = function(t) {
+                return Array.isArray ? Array.isArray(t) : "[object Array]" === n(t)
+            }, e.isBoolean = function(t) {
+                return "boolean" == typeof t
+            }, e.isNull = function(t) {
+                return null === t
+            }, e.isNullOrUndefined = function(t) {
+                return null == t
+            }, e.isNumber = function(t) {
+                return "number" == typeof t
+            }, e.isString = function(t) {
+                return "string" == typeof t
+            }, e.isSymbol = function(t) {
+                return "symbol" == typeof t
+            }, e.isUndefined = function(t) {
+                return void 0 === t
+            }, e.isRegExp = function(t) {
+                return "[object RegExp]" === n(t)
+            }, e.isObject = function(t) {
+                return "object" == typeof t && null !== t
+            }, e.isDate = function(t) {
+                return "[object Date]" === n(t)
+            }, e.isError = function(t) {
+                return "[object Error]" === n(t) || t instanceof Error
+            }, e.isFunction = function(t) {
+                return "function" == typeof t
+            }, e.isPrimitive = function(t) {
+                return null === t || "boolean" == typeof t || "number" == typeof t || "string" == typeof t || "symbol" == typeof t || void 0 === t
+            }, e.isBuffer = t.isBuffer
+        }).call(e, n(68).Buffer)
+    }, function(t, e, n) {
+        "use strict";
+
+        function r(t, e) {
+            Error.call(this), this.name = "YAMLException", this.reason = t, this.mark = e, this.message = (this.reason || "(unknown reason)") + (this.mark ? " " + this.mark.toString() : ""), Error.captureStackTrace ? Error.captureStackTrace(this, this.constructor) : this.stack = (new Error).stack || ""
+        }
+        r.prototype = Object.create(Error.prototype), r.prototype.constructor = r, r.prototype.toString = function(t) {
+            var e = this.name + ": ";
+            return e += this.reason || "(unknown reason)", !t && this.mark && (e += " " + this.mark.toString()), e
+        }, t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(38);
+        t.exports = new r({
+            include: [n(202)],
+            implicit: [n(531), n(532)],
+            explicit: [n(533), n(534), n(535), n(536)]
+        })
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return {
+                enumerable: !(1 & t),
+                configurable: !(2 & t),
+                writable: !(4 & t),
+                value: e
+            }
+        }
+    }, function(t, e) {
+        var n = 0,
+            r = Math.random();
+        t.exports = function(t) {
+            return "Symbol(".concat(void 0 === t ? "" : t, ")_", (++n + r).toString(36))
+        }
+    }, function(t, e, n) {
+        var r = n(87);
+        t.exports = function(t) {
+            return Object(r(t))
+        }
+    }, function(t, e, n) {
+        var r = n(18),
+            i = n(224),
+            o = n(225),
+            u = Object.defineProperty;
+        e.f = n(42) ? Object.defineProperty : function(t, e, n) {
+            if (r(t), e = o(e, !0), r(n), i) try {
+                return u(t, e, n)
+            } catch (t) {}
+            if ("get" in n || "set" in n) throw TypeError("Accessors not supported!");
+            return "value" in n && (t[e] = n.value), t
+        }
+    }, function(t, e) {
+        var n = {}.hasOwnProperty;
+        t.exports = function(t, e) {
+            return n.call(t, e)
+        }
+    }, function(t, e) {
+        var n = Math.ceil,
+            r = Math.floor;
+        t.exports = function(t) {
+            return isNaN(t = +t) ? 0 : (t > 0 ? r : n)(t)
+        }
+    }, function(t, e, n) {
+        var r = n(61);
+        t.exports = function(t, e, n) {
+            if (r(t), void 0 === e) return t;
+            switch (n) {
+                case 1:
+                    return function(n) {
+                        return t.call(e, n)
+                    };
+                case 2:
+                    return function(n, r) {
+                        return t.call(e, n, r)
+                    };
+                case 3:
+                    return function(n, r, i) {
+                        return t.call(e, n, r, i)
+                    }
+            }
+            return function() {
+                return t.apply(e, arguments)
+            }
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            if ("function" != typeof t) throw TypeError(t + " is not a function!");
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(230),
+            i = n(10);
+        t.exports = function(t) {
+            return r(i(t))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(17),
+            i = n(30),
+            o = n(43),
+            u = n(10),
+            a = n(1);
+        t.exports = function(t, e, n) {
+            var s = a(t),
+                c = n(u, s, "" [t]),
+                f = c[0],
+                l = c[1];
+            o(function() {
+                var e = {};
+                return e[s] = function() {
+                    return 7
+                }, 7 != "" [t](e)
+            }) && (i(String.prototype, t, f), r(RegExp.prototype, s, 2 == e ? function(t, e) {
+                return l.call(t, this, e)
+            } : function(t) {
+                return l.call(t, this)
+            }))
+        }
+    }, function(t, e) {
+        e.f = {}.propertyIsEnumerable
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            for (var e = arguments.length - 1, n = "Minified React error #" + t + "; visit http://facebook.github.io/react/docs/error-decoder.html?invariant=" + t, r = 0; r < e; r++) n += "&args[]=" + encodeURIComponent(arguments[r + 1]);
+            n += " for the full message or use the non-minified dev environment for full errors and additional helpful warnings.";
+            var i = new Error(n);
+            throw i.name = "Invariant Violation", i.framesToPop = 1, i
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(67);
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+
+        function r(t) {
+            return function() {
+                return t
+            }
+        }
+        var i = function() {};
+        i.thatReturns = r, i.thatReturnsFalse = r(!1), i.thatReturnsTrue = r(!0), i.thatReturnsNull = r(null), i.thatReturnsThis = function() {
+            return this
+        }, i.thatReturnsArgument = function(t) {
+            return t
+        }, t.exports = i
+    }, function(t, e, n) {
+        "use strict";
+        (function(t) {
+            /*!
+             * The buffer module from node.js, for the browser.
+             *
+             * @author   Feross Aboukhadijeh <feross@feross.org> <http://feross.org>
+             * @license  MIT
+             */
+            var r = n(325),
+                i = n(326),
+                o = n(167);
+
+            function u() {
+                return s.TYPED_ARRAY_SUPPORT ? 2147483647 : 1073741823
+            }
+
+            function a(t, e) {
+                if (u() < e) throw new RangeError("Invalid typed array length");
+                return s.TYPED_ARRAY_SUPPORT ? (t = new Uint8Array(e)).__proto__ = s.prototype : (null === t && (t = new s(e)), t.length = e), t
+            }
+
+            function s(t, e, n) {
+                if (!(s.TYPED_ARRAY_SUPPORT || this instanceof s)) return new s(t, e, n);
+                if ("number" == typeof t) {
+                    if ("string" == typeof e) throw new Error("If encoding is specified then the first argument must be a string");
+                    return l(this, t)
+                }
+                return c(this, t, e, n)
+            }
+
+            function c(t, e, n, r) {
+                if ("number" == typeof e) throw new TypeError('"value" argument must not be a number');
+                return "undefined" != typeof ArrayBuffer && e instanceof ArrayBuffer ? function(t, e, n, r) {
+                    if (e.byteLength, n < 0 || e.byteLength < n) throw new RangeError("'offset' is out of bounds");
+                    if (e.byteLength < n + (r || 0)) throw new RangeError("'length' is out of bounds");
+                    e = void 0 === n && void 0 === r ? new Uint8Array(e) : void 0 === r ? new Uint8Array(e, n) : new Uint8Array(e, n, r);
+                    s.TYPED_ARRAY_SUPPORT ? (t = e).__proto__ = s.prototype : t = p(t, e);
+                    return t
+                }(t, e, n, r) : "string" == typeof e ? function(t, e, n) {
+                    "string" == typeof n && "" !== n || (n = "utf8");
+                    if (!s.isEncoding(n)) throw new TypeError('"encoding" must be a valid string encoding');
+                    var r = 0 | d(e, n),
+                        i = (t = a(t, r)).write(e, n);
+                    i !== r && (t = t.slice(0, i));
+                    return t
+                }(t, e, n) : function(t, e) {
+                    if (s.isBuffer(e)) {
+                        var n = 0 | h(e.length);
+                        return 0 === (t = a(t, n)).length ? t : (e.copy(t, 0, 0, n), t)
+                    }
+                    if (e) {
+                        if ("undefined" != typeof ArrayBuffer && e.buffer instanceof ArrayBuffer || "length" in e) return "number" != typeof e.length || (r = e.length) != r ? a(t, 0) : p(t, e);
+                        if ("Buffer" === e.type && o(e.data)) return p(t, e.data)
+                    }
+                    var r;
+                    throw new TypeError("First argument must be a string, Buffer, ArrayBuffer, Array, or array-like object.")
+                }(t, e)
+            }
+
+            function f(t) {
+                if ("number" != typeof t) throw new TypeError('"size" argument must be a number');
+                if (t < 0) throw new RangeError('"size" argument must not be negative')
+            }
+
+            function l(t, e) {
+                if (f(e), t = a(t, e < 0 ? 0 : 0 | h(e)), !s.TYPED_ARRAY_SUPPORT)
+                    for (var n = 0; n < e; ++n) t[n] = 0;
+                return t
+            }
+
+            function p(t, e) {
+                var n = e.length < 0 ? 0 : 0 | h(e.length);
+                t = a(t, n);
+                for (var r = 0; r < n; r += 1) t[r] = 255 & e[r];
+                return t
+            }
+
+            function h(t) {
+                if (t >= u()) throw new RangeError("Attempt to allocate Buffer larger than maximum size: 0x" + u().toString(16) + " bytes");
+                return 0 | t
+            }
+
+            function d(t, e) {
+                if (s.isBuffer(t)) return t.length;
+                if ("undefined" != typeof ArrayBuffer && "function" == typeof ArrayBuffer.isView && (ArrayBuffer.isView(t) || t instanceof ArrayBuffer)) return t.byteLength;
+                "string" != typeof t && (t = "" + t);
+                var n = t.length;
+                if (0 === n) return 0;
+                for (var r = !1;;) switch (e) {
+                    case "ascii":
+                    case "latin1":
+                    case "binary":
+                        return n;
+                    case "utf8":
+                    case "utf-8":
+                    case void 0:
+                        return U(t).length;
+                    case "ucs2":
+                    case "ucs-2":
+                    case "utf16le":
+                    case "utf-16le":
+                        return 2 * n;
+                    case "hex":
+                        return n >>> 1;
+                    case "base64":
+                        return B(t).length;
+                    default:
+                        if (r) return U(t).length;
+                        e = ("" + e).toLowerCase(), r = !0
+                }
+            }
+
+            function v(t, e, n) {
+                var r = t[e];
+                t[e] = t[n], t[n] = r
+            }
+
+            function y(t, e, n, r, i) {
+                if (0 === t.length) return -1;
+                if ("string" == typeof n ? (r = n, n = 0) : n > 2147483647 ? n = 2147483647 : n < -2147483648 && (n = -2147483648), n = +n, isNaN(n) && (n = i ? 0 : t.length - 1), n < 0 && (n = t.length + n), n >= t.length) {
+                    if (i) return -1;
+                    n = t.length - 1
+                } else if (n < 0) {
+                    if (!i) return -1;
+                    n = 0
+                }
+                if ("string" == typeof e && (e = s.from(e, r)), s.isBuffer(e)) return 0 === e.length ? -1 : g(t, e, n, r, i);
+                if ("number" == typeof e) return e &= 255, s.TYPED_ARRAY_SUPPORT && "function" == typeof Uint8Array.prototype.indexOf ? i ? Uint8Array.prototype.indexOf.call(t, e, n) : Uint8Array.prototype.lastIndexOf.call(t, e, n) : g(t, [e], n, r, i);
+                throw new TypeError("val must be string, number or Buffer")
+            }
+
+            function g(t, e, n, r, i) {
+                var o, u = 1,
+                    a = t.length,
+                    s = e.length;
+                if (void 0 !== r && ("ucs2" === (r = String(r).toLowerCase()) || "ucs-2" === r || "utf16le" === r || "utf-16le" === r)) {
+                    if (t.length < 2 || e.length < 2) return -1;
+                    u = 2, a /= 2, s /= 2, n /= 2
+                }
+
+                function c(t, e) {
+                    return 1 === u ? t[e] : t.readUInt16BE(e * u)
+                }
+                if (i) {
+                    var f = -1;
+                    for (o = n; o < a; o++)
+                        if (c(t, o) === c(e, -1 === f ? 0 : o - f)) {
+                            if (-1 === f && (f = o), o - f + 1 === s) return f * u
+                        } else -1 !== f && (o -= o - f), f = -1
+                } else
+                    for (n + s > a && (n = a - s), o = n; o >= 0; o--) {
+                        for (var l = !0, p = 0; p < s; p++)
+                            if (c(t, o + p) !== c(e, p)) {
+                                l = !1;
+                                break
+                            }
+                        if (l) return o
+                    }
+                return -1
+            }
+
+            function m(t, e, n, r) {
+                n = Number(n) || 0;
+                var i = t.length - n;
+                r ? (r = Number(r)) > i && (r = i) : r = i;
+                var o = e.length;
+                if (o % 2 != 0) throw new TypeError("Invalid hex string");
+                r > o / 2 && (r = o / 2);
+                for (var u = 0; u < r; ++u) {
+                    var a = parseInt(e.substr(2 * u, 2), 16);
+                    if (isNaN(a)) return u;
+                    t[n + u] = a
+                }
+                return u
+            }
+
+            function _(t, e, n, r) {
+                return q(U(e, t.length - n), t, n, r)
+            }
+
+            function b(t, e, n, r) {
+                return q(function(t) {
+                    for (var e = [], n = 0; n < t.length; ++n) e.push(255 & t.charCodeAt(n));
+                    return e
+                }(e), t, n, r)
+            }
+
+            function w(t, e, n, r) {
+                return b(t, e, n, r)
+            }
+
+            function x(t, e, n, r) {
+                return q(B(e), t, n, r)
+            }
+
+            function S(t, e, n, r) {
+                return q(function(t, e) {
+                    for (var n, r, i, o = [], u = 0; u < t.length && !((e -= 2) < 0); ++u) n = t.charCodeAt(u), r = n >> 8, i = n % 256, o.push(i), o.push(r);
+                    return o
+                }(e, t.length - n), t, n, r)
+            }
+
+            function E(t, e, n) {
+                return 0 === e && n === t.length ? r.fromByteArray(t) : r.fromByteArray(t.slice(e, n))
+            }
+
+            function A(t, e, n) {
+                n = Math.min(t.length, n);
+                for (var r = [], i = e; i < n;) {
+                    var o, u, a, s, c = t[i],
+                        f = null,
+                        l = c > 239 ? 4 : c > 223 ? 3 : c > 191 ? 2 : 1;
+                    if (i + l <= n) switch (l) {
+                        case 1:
+                            c < 128 && (f = c);
+                            break;
+                        case 2:
+                            128 == (192 & (o = t[i + 1])) && (s = (31 & c) << 6 | 63 & o) > 127 && (f = s);
+                            break;
+                        case 3:
+                            o = t[i + 1], u = t[i + 2], 128 == (192 & o) && 128 == (192 & u) && (s = (15 & c) << 12 | (63 & o) << 6 | 63 & u) > 2047 && (s < 55296 || s > 57343) && (f = s);
+                            break;
+                        case 4:
+                            o = t[i + 1], u = t[i + 2], a = t[i + 3], 128 == (192 & o) && 128 == (192 & u) && 128 == (192 & a) && (s = (15 & c) << 18 | (63 & o) << 12 | (63 & u) << 6 | 63 & a) > 65535 && s < 1114112 && (f = s)
+                    }
+                    null === f ? (f = 65533, l = 1) : f > 65535 && (f -= 65536, r.push(f >>> 10 & 1023 | 55296), f = 56320 | 1023 & f), r.push(f), i += l
+                }
+                return function(t) {
+                    var e = t.length;
+                    if (e <= O) return String.fromCharCode.apply(String, t);
+                    var n = "",
+                        r = 0;
+                    for (; r < e;) n += String.fromCharCode.apply(String, t.slice(r, r += O));
+                    return n
+                }(r)
+            }
+            e.Buffer = s, e.SlowBuffer = function(t) {
+                +t != t && (t = 0);
+                return s.alloc(+t)
+            }, e.INSPECT_MAX_BYTES = 50, s.TYPED_ARRAY_SUPPORT = void 0 !== t.TYPED_ARRAY_SUPPORT ? t.TYPED_ARRAY_SUPPORT : function() {
+                try {
+                    var t = new Uint8Array(1);
+                    return t.__proto__ = {
+                        __proto__: Uint8Array.prototype,
+                        foo: function() {
+                            return 42
+                        }
+                    }, 42 === t.foo() && "function" == typeof t.subarray && 0 === t.subarray(1, 1).byteLength
+                } catch (t) {
+                    return !1
+                }
+            }(), e.kMaxLength = u(), s.poolSize = 8192, s._augment = function(t) {
+                return t.__proto__ = s.prototype, t
+            }, s.from = function(t, e, n) {
+                return c(null, t, e, n)
+            }, s.TYPED_ARRAY_SUPPORT && (s.prototype.__proto__ = Uint8Array.prototype, s.__proto__ = Uint8Array, "undefined" != typeof Symbol && Symbol.species && s[Symbol.species] === s && Object.defineProperty(s, Symbol.species, {
+                value: null,
+                configurable: !0
+            })), s.alloc = function(t, e, n) {
+                return function(t, e, n, r) {
+                    return f(e), e <= 0 ? a(t, e) : void 0 !== n ? "string" == typeof r ? a(t, e).fill(n, r) : a(t, e).fill(n) : a(t, e)
+                }(null, t, e, n)
+            }, s.allocUnsafe = function(t) {
+                return l(null, t)
+            }, s.allocUnsafeSlow = function(t) {
+                return l(null, t)
+            }, s.isBuffer = function(t) {
+                return !(null == t || !t._isBuffer)
+            }, s.compare = function(t, e) {
+                if (!s.isBuffer(t) || !s.isBuffer(e)) throw new TypeError("Arguments must be Buffers");
+                if (t === e) return 0;
+                for (var n = t.length, r = e.length, i = 0, o = Math.min(n, r); i < o; ++i)
+                    if (t[i] !== e[i]) {
+                        n = t[i], r = e[i];
+                        break
+                    }
+                return n < r ? -1 : r < n ? 1 : 0
+            }, s.isEncoding = function(t) {
+                switch (String(t).toLowerCase()) {
+                    case "hex":
+                    case "utf8":
+                    case "utf-8":
+                    case "ascii":
+                    case "latin1":
+                    case "binary":
+                    case "base64":
+                    case "ucs2":
+                    case "ucs-2":
+                    case "utf16le":
+                    case "utf-16le":
+                        return !0;
+                    default:
+                        return !1
+                }
+            }, s.concat = function(t, e) {
+                if (!o(t)) throw new TypeError('"list" argument must be an Array of Buffers');
+                if (0 === t.length) return s.alloc(0);
+                var n;
+                if (void 0 === e)
+                    for (e = 0, n = 0; n < t.length; ++n) e += t[n].length;
+                var r = s.allocUnsafe(e),
+                    i = 0;
+                for (n = 0; n < t.length; ++n) {
+                    var u = t[n];
+                    if (!s.isBuffer(u)) throw new TypeError('"list" argument must be an Array of Buffers');
+                    u.copy(r, i), i += u.length
+                }
+                return r
+            }, s.byteLength = d, s.prototype._isBuffer = !0, s.prototype.swap16 = function() {
+                var t = this.length;
+                if (t % 2 != 0) throw new RangeError("Buffer size must be a multiple of 16-bits");
+                for (var e = 0; e < t; e += 2) v(this, e, e + 1);
+                return this
+            }, s.prototype.swap32 = function() {
+                var t = this.length;
+                if (t % 4 != 0) throw new RangeError("Buffer size must be a multiple of 32-bits");
+                for (var e = 0; e < t; e += 4) v(this, e, e + 3), v(this, e + 1, e + 2);
+                return this
+            }, s.prototype.swap64 = function() {
+                var t = this.length;
+                if (t % 8 != 0) throw new RangeError("Buffer size must be a multiple of 64-bits");
+                for (var e = 0; e < t; e += 8) v(this, e, e + 7), v(this, e + 1, e + 6), v(this, e + 2, e + 5), v(this, e + 3, e + 4);
+                return this
+            }, s.prototype.toString = function() {
+                var t = 0 | this.length;
+                return 0 === t ? "" : 0 === arguments.length ? A(this, 0, t) : function(t, e, n) {
+                    var r = !1;
+                    if ((void 0 === e || e < 0) && (e = 0), e > this.length) return "";
+                    if ((void 0 === n || n > this.length) && (n = this.length), n <= 0) return "";
+                    if ((n >>>= 0) <= (e >>>= 0)) return "";
+                    for (t || (t = "utf8");;) switch (t) {
+                        case "hex":
+                            return I(this, e, n);
+                        case "utf8":
+                        case "utf-8":
+                            return A(this, e, n);
+                        case "ascii":
+                            return j(this, e, n);
+                        case "latin1":
+                        case "binary":
+                            return k(this, e, n);
+                        case "base64":
+                            return E(this, e, n);
+                        case "ucs2":
+                        case "ucs-2":
+                        case "utf16le":
+                        case "utf-16le":
+                            return M(this, e, n);
+                        default:
+                            if (r) throw new TypeError("Unknown encoding: " + t);
+                            t = (t + "").toLowerCase(), r = !0
+                    }
+                }.apply(this, arguments)
+            }, s.prototype.equals = function(t) {
+                if (!s.isBuffer(t)) throw new TypeError("Argument must be a Buffer");
+                return this === t || 0 === s.compare(this, t)
+            }, s.prototype.inspect = function() {
+                var t = "",
+                    n = e.INSPECT_MAX_BYTES;
+                return this.length > 0 && (t = this.toString("hex", 0, n).match(/.{2}/g).join(" "), this.length > n && (t += " ... ")), "<Buffer " + t + ">"
+            }, s.prototype.compare = function(t, e, n, r, i) {
+                if (!s.isBuffer(t)) throw new TypeError("Argument must be a Buffer");
+                if (void 0 === e && (e = 0), void 0 === n && (n = t ? t.length : 0), void 0 === r && (r = 0), void 0 === i && (i = this.length), e < 0 || n > t.length || r < 0 || i > this.length) throw new RangeError("out of range index");
+                if (r >= i && e >= n) return 0;
+                if (r >= i) return -1;
+                if (e >= n) return 1;
+                if (e >>>= 0, n >>>= 0, r >>>= 0, i >>>= 0, this === t) return 0;
+                for (var o = i - r, u = n - e, a = Math.min(o, u), c = this.slice(r, i), f = t.slice(e, n), l = 0; l < a; ++l)
+                    if (c[l] !== f[l]) {
+                        o = c[l], u = f[l];
+                        break
+                    }
+                return o < u ? -1 : u < o ? 1 : 0
+            }, s.prototype.includes = function(t, e, n) {
+                return -1 !== this.indexOf(t, e, n)
+            }, s.prototype.indexOf = function(t, e, n) {
+                return y(this, t, e, n, !0)
+            }, s.prototype.lastIndexOf = function(t, e, n) {
+                return y(this, t, e, n, !1)
+            }, s.prototype.write = function(t, e, n, r) {
+                if (void 0 === e) r = "utf8", n = this.length, e = 0;
+                else if (void 0 === n && "string" == typeof e) r = e, n = this.length, e = 0;
+                else {
+                    if (!isFinite(e)) throw new Error("Buffer.write(string, encoding, offset[, length]) is no longer supported");
+                    e |= 0, isFinite(n) ? (n |= 0, void 0 === r && (r = "utf8")) : (r = n, n = void 0)
+                }
+                var i = this.length - e;
+                if ((void 0 === n || n > i) && (n = i), t.length > 0 && (n < 0 || e < 0) || e > this.length) throw new RangeError("Attempt to write outside buffer bounds");
+                r || (r = "utf8");
+                for (var o = !1;;) switch (r) {
+                    case "hex":
+                        return m(this, t, e, n);
+                    case "utf8":
+                    case "utf-8":
+                        return _(this, t, e, n);
+                    case "ascii":
+                        return b(this, t, e, n);
+                    case "latin1":
+                    case "binary":
+                        return w(this, t, e, n);
+                    case "base64":
+                        return x(this, t, e, n);
+                    case "ucs2":
+                    case "ucs-2":
+                    case "utf16le":
+                    case "utf-16le":
+                        return S(this, t, e, n);
+                    default:
+                        if (o) throw new TypeError("Unknown encoding: " + r);
+                        r = ("" + r).toLowerCase(), o = !0
+                }
+            }, s.prototype.toJSON = function() {
+                return {
+                    type: "Buffer",
+                    data: Array.prototype.slice.call(this._arr || this, 0)
+                }
+            };
+            var O = 4096;
+
+            function j(t, e, n) {
+                var r = "";
+                n = Math.min(t.length, n);
+                for (var i = e; i < n; ++i) r += String.fromCharCode(127 & t[i]);
+                return r
+            }
+
+            function k(t, e, n) {
+                var r = "";
+                n = Math.min(t.length, n);
+                for (var i = e; i < n; ++i) r += String.fromCharCode(t[i]);
+                return r
+            }
+
+            function I(t, e, n) {
+                var r = t.length;
+                (!e || e < 0) && (e = 0), (!n || n < 0 || n > r) && (n = r);
+                for (var i = "", o = e; o < n; ++o) i += F(t[o]);
+                return i
+            }
+
+            function M(t, e, n) {
+                for (var r = t.slice(e, n), i = "", o = 0; o < r.length; o += 2) i += String.fromCharCode(r[o] + 256 * r[o + 1]);
+                return i
+            }
+
+            function T(t, e, n) {
+                if (t % 1 != 0 || t < 0) throw new RangeError("offset is not uint");
+                if (t + e > n) throw new RangeError("Trying to access beyond buffer length")
+            }
+
+            function C(t, e, n, r, i, o) {
+                if (!s.isBuffer(t)) throw new TypeError('"buffer" argument must be a Buffer instance');
+                if (e > i || e < o) throw new RangeError('"value" argument is out of bounds');
+                if (n + r > t.length) throw new RangeError("Index out of range")
+            }
+
+            function P(t, e, n, r) {
+                e < 0 && (e = 65535 + e + 1);
+                for (var i = 0, o = Math.min(t.length - n, 2); i < o; ++i) t[n + i] = (e & 255 << 8 * (r ? i : 1 - i)) >>> 8 * (r ? i : 1 - i)
+            }
+
+            function R(t, e, n, r) {
+                e < 0 && (e = 4294967295 + e + 1);
+                for (var i = 0, o = Math.min(t.length - n, 4); i < o; ++i) t[n + i] = e >>> 8 * (r ? i : 3 - i) & 255
+            }
+
+            function N(t, e, n, r, i, o) {
+                if (n + r > t.length) throw new RangeError("Index out of range");
+                if (n < 0) throw new RangeError("Index out of range")
+            }
+
+            function L(t, e, n, r, o) {
+                return o || N(t, 0, n, 4), i.write(t, e, n, r, 23, 4), n + 4
+            }
+
+            function D(t, e, n, r, o) {
+                return o || N(t, 0, n, 8), i.write(t, e, n, r, 52, 8), n + 8
+            }
+            s.prototype.slice = function(t, e) {
+                var n, r = this.length;
+                if (t = ~~t, e = void 0 === e ? r : ~~e, t < 0 ? (t += r) < 0 && (t = 0) : t > r && (t = r), e < 0 ? (e += r) < 0 && (e = 0) : e > r && (e = r), e < t && (e = t), s.TYPED_ARRAY_SUPPORT)(n = this.subarray(t, e)).__proto__ = s.prototype;
+                else {
+                    var i = e - t;
+                    n = new s(i, void 0);
+                    for (var o = 0; o < i; ++o) n[o] = this[o + t]
+                }
+                return n
+            }, s.prototype.readUIntLE = function(t, e, n) {
+                t |= 0, e |= 0, n || T(t, e, this.length);
+                for (var r = this[t], i = 1, o = 0; ++o < e && (i *= 256);) r += this[t + o] * i;
+                return r
+            }, s.prototype.readUIntBE = function(t, e, n) {
+                t |= 0, e |= 0, n || T(t, e, this.length);
+                for (var r = this[t + --e], i = 1; e > 0 && (i *= 256);) r += this[t + --e] * i;
+                return r
+            }, s.prototype.readUInt8 = function(t, e) {
+                return e || T(t, 1, this.length), this[t]
+            }, s.prototype.readUInt16LE = function(t, e) {
+                return e || T(t, 2, this.length), this[t] | this[t + 1] << 8
+            }, s.prototype.readUInt16BE = function(t, e) {
+                return e || T(t, 2, this.length), this[t] << 8 | this[t + 1]
+            }, s.prototype.readUInt32LE = function(t, e) {
+                return e || T(t, 4, this.length), (this[t] | this[t + 1] << 8 | this[t + 2] << 16) + 16777216 * this[t + 3]
+            }, s.prototype.readUInt32BE = function(t, e) {
+                return e || T(t, 4, this.length), 16777216 * this[t] + (this[t + 1] << 16 | this[t + 2] << 8 | this[t + 3])
+            }, s.prototype.readIntLE = function(t, e, n) {
+                t |= 0, e |= 0, n || T(t, e, this.length);
+                for (var r = this[t], i = 1, o = 0; ++o < e && (i *= 256);) r += this[t + o] * i;
+                return r >= (i *= 128) && (r -= Math.pow(2, 8 * e)), r
+            }, s.prototype.readIntBE = function(t, e, n) {
+                t |= 0, e |= 0, n || T(t, e, this.length);
+                for (var r = e, i = 1, o = this[t + --r]; r > 0 && (i *= 256);) o += this[t + --r] * i;
+                return o >= (i *= 128) && (o -= Math.pow(2, 8 * e)), o
+            }, s.prototype.readInt8 = function(t, e) {
+                return e || T(t, 1, this.length), 128 & this[t] ? -1 * (255 - this[t] + 1) : this[t]
+            }, s.prototype.readInt16LE = function(t, e) {
+                e || T(t, 2, this.length);
+                var n = this[t] | this[t + 1] << 8;
+                return 32768 & n ? 4294901760 | n : n
+            }, s.prototype.readInt16BE = function(t, e) {
+                e || T(t, 2, this.length);
+                var n = this[t + 1] | this[t] << 8;
+                return 32768 & n ? 4294901760 | n : n
+            }, s.prototype.readInt32LE = function(t, e) {
+                return e || T(t, 4, this.length), this[t] | this[t + 1] << 8 | this[t + 2] << 16 | this[t + 3] << 24
+            }, s.prototype.readInt32BE = function(t, e) {
+                return e || T(t, 4, this.length), this[t] << 24 | this[t + 1] << 16 | this[t + 2] << 8 | this[t + 3]
+            }, s.prototype.readFloatLE = function(t, e) {
+                return e || T(t, 4, this.length), i.read(this, t, !0, 23, 4)
+            }, s.prototype.readFloatBE = function(t, e) {
+                return e || T(t, 4, this.length), i.read(this, t, !1, 23, 4)
+            }, s.prototype.readDoubleLE = function(t, e) {
+                return e || T(t, 8, this.length), i.read(this, t, !0, 52, 8)
+            }, s.prototype.readDoubleBE = function(t, e) {
+                return e || T(t, 8, this.length), i.read(this, t, !1, 52, 8)
+            }, s.prototype.writeUIntLE = function(t, e, n, r) {
+                (t = +t, e |= 0, n |= 0, r) || C(this, t, e, n, Math.pow(2, 8 * n) - 1, 0);
+                var i = 1,
+                    o = 0;
+                for (this[e] = 255 & t; ++o < n && (i *= 256);) this[e + o] = t / i & 255;
+                return e + n
+            }, s.prototype.writeUIntBE = function(t, e, n, r) {
+                (t = +t, e |= 0, n |= 0, r) || C(this, t, e, n, Math.pow(2, 8 * n) - 1, 0);
+                var i = n - 1,
+                    o = 1;
+                for (this[e + i] = 255 & t; --i >= 0 && (o *= 256);) this[e + i] = t / o & 255;
+                return e + n
+            }, s.prototype.writeUInt8 = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 1, 255, 0), s.TYPED_ARRAY_SUPPORT || (t = Math.floor(t)), this[e] = 255 & t, e + 1
+            }, s.prototype.writeUInt16LE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 2, 65535, 0), s.TYPED_ARRAY_SUPPORT ? (this[e] = 255 & t, this[e + 1] = t >>> 8) : P(this, t, e, !0), e + 2
+            }, s.prototype.writeUInt16BE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 2, 65535, 0), s.TYPED_ARRAY_SUPPORT ? (this[e] = t >>> 8, this[e + 1] = 255 & t) : P(this, t, e, !1), e + 2
+            }, s.prototype.writeUInt32LE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 4, 4294967295, 0), s.TYPED_ARRAY_SUPPORT ? (this[e + 3] = t >>> 24, this[e + 2] = t >>> 16, this[e + 1] = t >>> 8, this[e] = 255 & t) : R(this, t, e, !0), e + 4
+            }, s.prototype.writeUInt32BE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 4, 4294967295, 0), s.TYPED_ARRAY_SUPPORT ? (this[e] = t >>> 24, this[e + 1] = t >>> 16, this[e + 2] = t >>> 8, this[e + 3] = 255 & t) : R(this, t, e, !1), e + 4
+            }, s.prototype.writeIntLE = function(t, e, n, r) {
+                if (t = +t, e |= 0, !r) {
+                    var i = Math.pow(2, 8 * n - 1);
+                    C(this, t, e, n, i - 1, -i)
+                }
+                var o = 0,
+                    u = 1,
+                    a = 0;
+                for (this[e] = 255 & t; ++o < n && (u *= 256);) t < 0 && 0 === a && 0 !== this[e + o - 1] && (a = 1), this[e + o] = (t / u >> 0) - a & 255;
+                return e + n
+            }, s.prototype.writeIntBE = function(t, e, n, r) {
+                if (t = +t, e |= 0, !r) {
+                    var i = Math.pow(2, 8 * n - 1);
+                    C(this, t, e, n, i - 1, -i)
+                }
+                var o = n - 1,
+                    u = 1,
+                    a = 0;
+                for (this[e + o] = 255 & t; --o >= 0 && (u *= 256);) t < 0 && 0 === a && 0 !== this[e + o + 1] && (a = 1), this[e + o] = (t / u >> 0) - a & 255;
+                return e + n
+            }, s.prototype.writeInt8 = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 1, 127, -128), s.TYPED_ARRAY_SUPPORT || (t = Math.floor(t)), t < 0 && (t = 255 + t + 1), this[e] = 255 & t, e + 1
+            }, s.prototype.writeInt16LE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 2, 32767, -32768), s.TYPED_ARRAY_SUPPORT ? (this[e] = 255 & t, this[e + 1] = t >>> 8) : P(this, t, e, !0), e + 2
+            }, s.prototype.writeInt16BE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 2, 32767, -32768), s.TYPED_ARRAY_SUPPORT ? (this[e] = t >>> 8, this[e + 1] = 255 & t) : P(this, t, e, !1), e + 2
+            }, s.prototype.writeInt32LE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 4, 2147483647, -2147483648), s.TYPED_ARRAY_SUPPORT ? (this[e] = 255 & t, this[e + 1] = t >>> 8, this[e + 2] = t >>> 16, this[e + 3] = t >>> 24) : R(this, t, e, !0), e + 4
+            }, s.prototype.writeInt32BE = function(t, e, n) {
+                return t = +t, e |= 0, n || C(this, t, e, 4, 2147483647, -2147483648), t < 0 && (t = 4294967295 + t + 1), s.TYPED_ARRAY_SUPPORT ? (this[e] = t >>> 24, this[e + 1] = t >>> 16, this[e + 2] = t >>> 8, this[e + 3] = 255 & t) : R(this, t, e, !1), e + 4
+            }, s.prototype.writeFloatLE = function(t, e, n) {
+                return L(this, t, e, !0, n)
+            }, s.prototype.writeFloatBE = function(t, e, n) {
+                return L(this, t, e, !1, n)
+            }, s.prototype.writeDoubleLE = function(t, e, n) {
+                return D(this, t, e, !0, n)
+            }, s.prototype.writeDoubleBE = function(t, e, n) {
+                return D(this, t, e, !1, n)
+            }, s.prototype.copy = function(t, e, n, r) {
+                if (n || (n = 0), r || 0 === r || (r = this.length), e >= t.length && (e = t.length), e || (e = 0), r > 0 && r < n && (r = n), r === n) return 0;
+                if (0 === t.length || 0 === this.length) return 0;
+                if (e < 0) throw new RangeError("targetStart out of bounds");
+                if (n < 0 || n >= this.length) throw new RangeError("sourceStart out of bounds");
+                if (r < 0) throw new RangeError("sourceEnd out of bounds");
+                r > this.length && (r = this.length), t.length - e < r - n && (r = t.length - e + n);
+                var i, o = r - n;
+                if (this === t && n < e && e < r)
+                    for (i = o - 1; i >= 0; --i) t[i + e] = this[i + n];
+                else if (o < 1e3 || !s.TYPED_ARRAY_SUPPORT)
+                    for (i = 0; i < o; ++i) t[i + e] = this[i + n];
+                else Uint8Array.prototype.set.call(t, this.subarray(n, n + o), e);
+                return o
+            }, s.prototype.fill = function(t, e, n, r) {
+                if ("string" == typeof t) {
+                    if ("string" == typeof e ? (r = e, e = 0, n = this.length) : "string" == typeof n && (r = n, n = this.length), 1 === t.length) {
+                        var i = t.charCodeAt(0);
+                        i < 256 && (t = i)
+                    }
+                    if (void 0 !== r && "string" != typeof r) throw new TypeError("encoding must be a string");
+                    if ("string" == typeof r && !s.isEncoding(r)) throw new TypeError("Unknown encoding: " + r)
+                } else "number" == typeof t && (t &= 255);
+                if (e < 0 || this.length < e || this.length < n) throw new RangeError("Out of range index");
+                if (n <= e) return this;
+                var o;
+                if (e >>>= 0, n = void 0 === n ? this.length : n >>> 0, t || (t = 0), "number" == typeof t)
+                    for (o = e; o < n; ++o) this[o] = t;
+                else {
+                    var u = s.isBuffer(t) ? t : U(new s(t, r).toString()),
+                        a = u.length;
+                    for (o = 0; o < n - e; ++o) this[o + e] = u[o % a]
+                }
+                return this
+            };
+            var z = /[^+\/0-9A-Za-z-_]/g;
+
+            function F(t) {
+                return t < 16 ? "0" + t.toString(16) : t.toString(16)
+            }
+
+            function U(t, e) {
+                var n;
+                e = e || 1 / 0;
+                for (var r = t.length, i = null, o = [], u = 0; u < r; ++u) {
+                    if ((n = t.charCodeAt(u)) > 55295 && n < 57344) {
+                        if (!i) {
+                            if (n > 56319) {
+                                (e -= 3) > -1 && o.push(239, 191, 189);
+                                continue
+                            }
+                            if (u + 1 === r) {
+                                (e -= 3) > -1 && o.push(239, 191, 189);
+                                continue
+                            }
+                            i = n;
+                            continue
+                        }
+                        if (n < 56320) {
+                            (e -= 3) > -1 && o.push(239, 191, 189), i = n;
+                            continue
+                        }
+                        n = 65536 + (i - 55296 << 10 | n - 56320)
+                    } else i && (e -= 3) > -1 && o.push(239, 191, 189);
+                    if (i = null, n < 128) {
+                        if ((e -= 1) < 0) break;
+                        o.push(n)
+                    } else if (n < 2048) {
+                        if ((e -= 2) < 0) break;
+                        o.push(n >> 6 | 192, 63 & n | 128)
+                    } else if (n < 65536) {
+                        if ((e -= 3) < 0) break;
+                        o.push(n >> 12 | 224, n >> 6 & 63 | 128, 63 & n | 128)
+                    } else {
+                        if (!(n < 1114112)) throw new Error("Invalid code point");
+                        if ((e -= 4) < 0) break;
+                        o.push(n >> 18 | 240, n >> 12 & 63 | 128, n >> 6 & 63 | 128, 63 & n | 128)
+                    }
+                }
+                return o
+            }
+
+            function B(t) {
+                return r.toByteArray(function(t) {
+                    if ((t = function(t) {
+                        return t.trim ? t.trim() : t.replace(/^\s+|\s+$/g, "")
+                    }(t).replace(z, "")).length < 2) return "";
+                    for (; t.length % 4 != 0;) t += "=";
+                    return t
+                }(t))
+            }
+
+            function q(t, e, n, r) {
+                for (var i = 0; i < r && !(i + n >= e.length || i >= t.length); ++i) e[i + n] = t[i];
+                return i
+            }
+        }).call(e, n(11))
+    }, function(t, e, n) {
+        var r = n(7).Symbol;
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(48),
+            i = n(49),
+            o = "[object Symbol]";
+        t.exports = function(t) {
+            return "symbol" == typeof t || i(t) && r(t) == o
+        }
+    }, function(t, e, n) {
+        var r = n(33)(Object, "create");
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(373),
+            i = n(374),
+            o = n(375),
+            u = n(376),
+            a = n(377);
+
+        function s(t) {
+            var e = -1,
+                n = null == t ? 0 : t.length;
+            for (this.clear(); ++e < n;) {
+                var r = t[e];
+                this.set(r[0], r[1])
+            }
+        }
+        s.prototype.clear = r, s.prototype.delete = i, s.prototype.get = o, s.prototype.has = u, s.prototype.set = a, t.exports = s
+    }, function(t, e, n) {
+        var r = n(74);
+        t.exports = function(t, e) {
+            for (var n = t.length; n--;)
+                if (r(t[n][0], e)) return n;
+            return -1
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return t === e || t != t && e != e
+        }
+    }, function(t, e, n) {
+        var r = n(379);
+        t.exports = function(t, e) {
+            var n = t.__data__;
+            return r(e) ? n["string" == typeof e ? "string" : "hash"] : n.map
+        }
+    }, function(t, e, n) {
+        var r = n(408),
+            i = n(415),
+            o = n(77);
+        t.exports = function(t) {
+            return o(t) ? r(t) : i(t)
+        }
+    }, function(t, e, n) {
+        var r = n(173),
+            i = n(113);
+        t.exports = function(t) {
+            return null != t && i(t.length) && !r(t)
+        }
+    }, function(t, e, n) {
+        var r = n(70),
+            i = 1 / 0;
+        t.exports = function(t) {
+            if ("string" == typeof t || r(t)) return t;
+            var e = t + "";
+            return "0" == e && 1 / t == -i ? "-0" : e
+        }
+    }, function(t, e, n) {
+        "use strict";
+        (function(e) {
+            !e.version || 0 === e.version.indexOf("v0.") || 0 === e.version.indexOf("v1.") && 0 !== e.version.indexOf("v1.8.") ? t.exports = {
+                nextTick: function(t, n, r, i) {
+                    if ("function" != typeof t) throw new TypeError('"callback" argument must be a function');
+                    var o, u, a = arguments.length;
+                    switch (a) {
+                        case 0:
+                        case 1:
+                            return e.nextTick(t);
+                        case 2:
+                            return e.nextTick(function() {
+                                t.call(null, n)
+                            });
+                        case 3:
+                            return e.nextTick(function() {
+                                t.call(null, n, r)
+                            });
+                        case 4:
+                            return e.nextTick(function() {
+                                t.call(null, n, r, i)
+                            });
+                        default:
+                            for (o = new Array(a - 1), u = 0; u < o.length;) o[u++] = arguments[u];
+                            return e.nextTick(function() {
+                                t.apply(null, o)
+                            })
+                    }
+                }
+            } : t.exports = e
+        }).call(e, n(34))
+    }, function(t, e, n) {
+        var r = n(68),
+            i = r.Buffer;
+
+        function o(t, e) {
+            for (var n in t) e[n] = t[n]
+        }
+
+        function u(t, e, n) {
+            return i(t, e, n)
+        }
+        i.from && i.alloc && i.allocUnsafe && i.allocUnsafeSlow ? t.exports = r : (o(r, e), e.Buffer = u), o(i, u), u.from = function(t, e, n) {
+            if ("number" == typeof t) throw new TypeError("Argument must not be a number");
+            return i(t, e, n)
+        }, u.alloc = function(t, e, n) {
+            if ("number" != typeof t) throw new TypeError("Argument must be a number");
+            var r = i(t);
+            return void 0 !== e ? "string" == typeof n ? r.fill(e, n) : r.fill(e) : r.fill(0), r
+        }, u.allocUnsafe = function(t) {
+            if ("number" != typeof t) throw new TypeError("Argument must be a number");
+            return i(t)
+        }, u.allocUnsafeSlow = function(t) {
+            if ("number" != typeof t) throw new TypeError("Argument must be a number");
+            return r.SlowBuffer(t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(469)("forEach")
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(196),
+            i = n(193),
+            o = n(118),
+            u = n(478);
+        (t.exports = function(t, e) {
+            var n, o, a, s, c;
+            return arguments.length < 2 || "string" != typeof t ? (s = e, e = t, t = null) : s = arguments[2], null == t ? (n = a = !0, o = !1) : (n = u.call(t, "c"), o = u.call(t, "e"), a = u.call(t, "w")), c = {
+                value: e,
+                configurable: n,
+                enumerable: o,
+                writable: a
+            }, s ? r(i(s), c) : c
+        }).gs = function(t, e, n) {
+            var a, s, c, f;
+            return "string" != typeof t ? (c = n, n = e, e = t, t = null) : c = arguments[3], null == e ? e = void 0 : o(e) ? null == n ? n = void 0 : o(n) || (c = n, n = void 0) : (c = e, e = n = void 0), null == t ? (a = !0, s = !1) : (a = u.call(t, "c"), s = u.call(t, "e")), f = {
+                get: e,
+                set: n,
+                configurable: a,
+                enumerable: s
+            }, c ? r(i(c), f) : f
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(38);
+        t.exports = r.DEFAULT = new r({
+            include: [n(53)],
+            explicit: [n(537), n(538), n(539)]
+        })
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(208),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(209);
+        for (var r = n(9), i = n(26), o = n(39), u = n(6)("toStringTag"), a = "CSSRuleList,CSSStyleDeclaration,CSSValueList,ClientRectList,DOMRectList,DOMStringList,DOMTokenList,DataTransferItemList,FileList,HTMLAllCollection,HTMLCollection,HTMLFormElement,HTMLSelectElement,MediaList,MimeTypeArray,NamedNodeMap,NodeList,PaintRequestList,Plugin,PluginArray,SVGLengthList,SVGNumberList,SVGPathSegList,SVGPointList,SVGStringList,SVGTransformList,SourceBufferList,StyleSheetList,TextTrackCueList,TextTrackList,TouchList".split(","), s = 0; s < a.length; s++) {
+            var c = a[s],
+                f = r[c],
+                l = f && f.prototype;
+            l && !l[u] && i(l, u, c), o[c] = o.Array
+        }
+    }, function(t, e) {
+        var n = {}.toString;
+        t.exports = function(t) {
+            return n.call(t).slice(8, -1)
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            if (void 0 == t) throw TypeError("Can't call method on  " + t);
+            return t
+        }
+    }, function(t, e) {
+        t.exports = !0
+    }, function(t, e, n) {
+        var r = n(28);
+        t.exports = function(t, e) {
+            if (!r(t)) return t;
+            var n, i;
+            if (e && "function" == typeof(n = t.toString) && !r(i = n.call(t))) return i;
+            if ("function" == typeof(n = t.valueOf) && !r(i = n.call(t))) return i;
+            if (!e && "function" == typeof(n = t.toString) && !r(i = n.call(t))) return i;
+            throw TypeError("Can't convert object to primitive value")
+        }
+    }, function(t, e, n) {
+        var r = n(27),
+            i = n(214),
+            o = n(94),
+            u = n(92)("IE_PROTO"),
+            a = function() {},
+            s = function() {
+                var t, e = n(128)("iframe"),
+                    r = o.length;
+                for (e.style.display = "none", n(218).appendChild(e), e.src = "javascript:", (t = e.contentWindow.document).open(), t.write("<script>document.F=Object<\/script>"), t.close(), s = t.F; r--;) delete s.prototype[o[r]];
+                return s()
+            };
+        t.exports = Object.create || function(t, e) {
+            var n;
+            return null !== t ? (a.prototype = r(t), n = new a, a.prototype = null, n[u] = t) : n = s(), void 0 === e ? n : i(n, e)
+        }
+    }, function(t, e) {
+        var n = Math.ceil,
+            r = Math.floor;
+        t.exports = function(t) {
+            return isNaN(t = +t) ? 0 : (t > 0 ? r : n)(t)
+        }
+    }, function(t, e, n) {
+        var r = n(93)("keys"),
+            i = n(55);
+        t.exports = function(t) {
+            return r[t] || (r[t] = i(t))
+        }
+    }, function(t, e, n) {
+        var r = n(9),
+            i = r["__core-js_shared__"] || (r["__core-js_shared__"] = {});
+        t.exports = function(t) {
+            return i[t] || (i[t] = {})
+        }
+    }, function(t, e) {
+        t.exports = "constructor,hasOwnProperty,isPrototypeOf,propertyIsEnumerable,toLocaleString,toString,valueOf".split(",")
+    }, function(t, e, n) {
+        var r = n(14).f,
+            i = n(16),
+            o = n(6)("toStringTag");
+        t.exports = function(t, e, n) {
+            t && !i(t = n ? t : t.prototype, o) && r(t, o, {
+                configurable: !0,
+                value: e
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(219)(!0);
+        n(125)(String, "String", function(t) {
+            this._t = String(t), this._i = 0
+        }, function() {
+            var t, e = this._t,
+                n = this._i;
+            return n >= e.length ? {
+                value: void 0,
+                done: !0
+            } : (t = r(e, n), this._i += t.length, {
+                value: t,
+                done: !1
+            })
+        })
+    }, function(t, e, n) {
+        var r = n(41),
+            i = n(1)("toStringTag"),
+            o = "Arguments" == r(function() {
+                return arguments
+            }());
+        t.exports = function(t) {
+            var e, n, u;
+            return void 0 === t ? "Undefined" : null === t ? "Null" : "string" == typeof(n = function(t, e) {
+                try {
+                    return t[e]
+                } catch (t) {}
+            }(e = Object(t), i)) ? n : o ? r(e) : "Object" == (u = r(e)) && "function" == typeof e.callee ? "Arguments" : u
+        }
+    }, function(t, e) {
+        var n = 0,
+            r = Math.random();
+        t.exports = function(t) {
+            return "Symbol(".concat(void 0 === t ? "" : t, ")_", (++n + r).toString(36))
+        }
+    }, function(t, e, n) {
+        var r = n(31),
+            i = n(5).document,
+            o = r(i) && r(i.createElement);
+        t.exports = function(t) {
+            return o ? i.createElement(t) : {}
+        }
+    }, function(t, e, n) {
+        var r = n(133)("keys"),
+            i = n(98);
+        t.exports = function(t) {
+            return r[t] || (r[t] = i(t))
+        }
+    }, function(t, e, n) {
+        var r = n(57).f,
+            i = n(58),
+            o = n(1)("toStringTag");
+        t.exports = function(t, e, n) {
+            t && !i(t = n ? t : t.prototype, o) && r(t, o, {
+                configurable: !0,
+                value: e
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(61);
+        t.exports.f = function(t) {
+            return new function(t) {
+                var e, n;
+                this.promise = new t(function(t, r) {
+                    if (void 0 !== e || void 0 !== n) throw TypeError("Bad Promise constructor");
+                    e = t, n = r
+                }), this.resolve = r(e), this.reject = r(n)
+            }(t)
+        }
+    }, function(t, e, n) {
+        var r = n(147),
+            i = n(10);
+        t.exports = function(t, e, n) {
+            if (r(e)) throw TypeError("String#" + n + " doesn't accept regex!");
+            return String(i(t))
+        }
+    }, function(t, e, n) {
+        var r = n(1)("match");
+        t.exports = function(t) {
+            var e = /./;
+            try {
+                "/./" [t](e)
+            } catch (n) {
+                try {
+                    return e[r] = !1, !"/./" [t](e)
+                } catch (t) {}
+            }
+            return !0
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0;
+        var r = u(n(286)),
+            i = u(n(288)),
+            o = "function" == typeof i.default && "symbol" == typeof r.default ? function(t) {
+                return typeof t
+            } : function(t) {
+                return t && "function" == typeof i.default && t.constructor === i.default && t !== i.default.prototype ? "symbol" : typeof t
+            };
+
+        function u(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        e.default = "function" == typeof i.default && "symbol" === o(r.default) ? function(t) {
+            return void 0 === t ? "undefined" : o(t)
+        } : function(t) {
+            return t && "function" == typeof i.default && t.constructor === i.default && t !== i.default.prototype ? "symbol" : void 0 === t ? "undefined" : o(t)
+        }
+    }, function(t, e, n) {
+        e.f = n(6)
+    }, function(t, e, n) {
+        var r = n(9),
+            i = n(2),
+            o = n(88),
+            u = n(106),
+            a = n(14).f;
+        t.exports = function(t) {
+            var e = i.Symbol || (i.Symbol = o ? {} : r.Symbol || {});
+            "_" == t.charAt(0) || t in e || a(e, t, {
+                value: u.f(t)
+            })
+        }
+    }, function(t, e) {
+        e.f = Object.getOwnPropertySymbols
+    }, function(t, e, n) {
+        var r = n(362),
+            i = n(378),
+            o = n(380),
+            u = n(381),
+            a = n(382);
+
+        function s(t) {
+            var e = -1,
+                n = null == t ? 0 : t.length;
+            for (this.clear(); ++e < n;) {
+                var r = t[e];
+                this.set(r[0], r[1])
+            }
+        }
+        s.prototype.clear = r, s.prototype.delete = i, s.prototype.get = o, s.prototype.has = u, s.prototype.set = a, t.exports = s
+    }, function(t, e, n) {
+        var r = n(33)(n(7), "Map");
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(385),
+            i = n(425),
+            o = n(432),
+            u = n(8),
+            a = n(433);
+        t.exports = function(t) {
+            return "function" == typeof t ? t : null == t ? o : "object" == typeof t ? u(t) ? i(t[0], t[1]) : r(t) : a(t)
+        }
+    }, function(t, e) {
+        var n = 9007199254740991,
+            r = /^(?:0|[1-9]\d*)$/;
+        t.exports = function(t, e) {
+            var i = typeof t;
+            return !!(e = null == e ? n : e) && ("number" == i || "symbol" != i && r.test(t)) && t > -1 && t % 1 == 0 && t < e
+        }
+    }, function(t, e) {
+        var n = 9007199254740991;
+        t.exports = function(t) {
+            return "number" == typeof t && t > -1 && t % 1 == 0 && t <= n
+        }
+    }, function(t, e, n) {
+        var r = n(8),
+            i = n(70),
+            o = /\.|\[(?:[^[\]]*|(["'])(?:(?!\1)[^\\]|\\.)*?\1)\]/,
+            u = /^\w*$/;
+        t.exports = function(t, e) {
+            if (r(t)) return !1;
+            var n = typeof t;
+            return !("number" != n && "symbol" != n && "boolean" != n && null != t && !i(t)) || u.test(t) || !o.test(t) || null != e && t in Object(e)
+        }
+    }, function(t, e) {
+        function n() {
+            this._events = this._events || {}, this._maxListeners = this._maxListeners || void 0
+        }
+
+        function r(t) {
+            return "function" == typeof t
+        }
+
+        function i(t) {
+            return "object" == typeof t && null !== t
+        }
+
+        function o(t) {
+            return void 0 === t
+        }
+        t.exports = n, n.EventEmitter = n, n.prototype._events = void 0, n.prototype._maxListeners = void 0, n.defaultMaxListeners = 10, n.prototype.setMaxListeners = function(t) {
+            if ("number" != typeof t || t < 0 || isNaN(t)) throw TypeError("n must be a positive number");
+            return this._maxListeners = t, this
+        }, n.prototype.emit = function(t) {
+            var e, n, u, a, s, c;
+            if (this._events || (this._events = {}), "error" === t && (!this._events.error || i(this._events.error) && !this._events.error.length)) {
+                if ((e = arguments[1]) instanceof Error) throw e;
+                var f = new Error('Uncaught, unspecified "error" event. (' + e + ")");
+                throw f.context = e, f
+            }
+            if (o(n = this._events[t])) return !1;
+            if (r(n)) switch (arguments.length) {
+                case 1:
+                    n.call(this);
+                    break;
+                case 2:
+                    n.call(this, arguments[1]);
+                    break;
+                case 3:
+                    n.call(this, arguments[1], arguments[2]);
+                    break;
+                default:
+                    a = Array.prototype.slice.call(arguments, 1), n.apply(this, a)
+            } else if (i(n))
+                for (a = Array.prototype.slice.call(arguments, 1), u = (c = n.slice()).length, s = 0; s < u; s++) c[s].apply(this, a);
+            return !0
+        }, n.prototype.addListener = function(t, e) {
+            var u;
+            if (!r(e)) throw TypeError("listener must be a function");
+            return this._events || (this._events = {}), this._events.newListener && this.emit("newListener", t, r(e.listener) ? e.listener : e), this._events[t] ? i(this._events[t]) ? this._events[t].push(e) : this._events[t] = [this._events[t], e] : this._events[t] = e, i(this._events[t]) && !this._events[t].warned && (u = o(this._maxListeners) ? n.defaultMaxListeners : this._maxListeners) && u > 0 && this._events[t].length > u && (this._events[t].warned = !0, console.error("(node) warning: possible EventEmitter memory leak detected. %d listeners added. Use emitter.setMaxListeners() to increase limit.", this._events[t].length), "function" == typeof console.trace && console.trace()), this
+        }, n.prototype.on = n.prototype.addListener, n.prototype.once = function(t, e) {
+            if (!r(e)) throw TypeError("listener must be a function");
+            var n = !1;
+
+            function i() {
+                this.removeListener(t, i), n || (n = !0, e.apply(this, arguments))
+            }
+            return i.listener = e, this.on(t, i), this
+        }, n.prototype.removeListener = function(t, e) {
+            var n, o, u, a;
+            if (!r(e)) throw TypeError("listener must be a function");
+            if (!this._events || !this._events[t]) return this;
+            if (u = (n = this._events[t]).length, o = -1, n === e || r(n.listener) && n.listener === e) delete this._events[t], this._events.removeListener && this.emit("removeListener", t, e);
+            else if (i(n)) {
+                for (a = u; a-- > 0;)
+                    if (n[a] === e || n[a].listener && n[a].listener === e) {
+                        o = a;
+                        break
+                    }
+                if (o < 0) return this;
+                1 === n.length ? (n.length = 0, delete this._events[t]) : n.splice(o, 1), this._events.removeListener && this.emit("removeListener", t, e)
+            }
+            return this
+        }, n.prototype.removeAllListeners = function(t) {
+            var e, n;
+            if (!this._events) return this;
+            if (!this._events.removeListener) return 0 === arguments.length ? this._events = {} : this._events[t] && delete this._events[t], this;
+            if (0 === arguments.length) {
+                for (e in this._events) "removeListener" !== e && this.removeAllListeners(e);
+                return this.removeAllListeners("removeListener"), this._events = {}, this
+            }
+            if (r(n = this._events[t])) this.removeListener(t, n);
+            else if (n)
+                for (; n.length;) this.removeListener(t, n[n.length - 1]);
+            return delete this._events[t], this
+        }, n.prototype.listeners = function(t) {
+            return this._events && this._events[t] ? r(this._events[t]) ? [this._events[t]] : this._events[t].slice() : []
+        }, n.prototype.listenerCount = function(t) {
+            if (this._events) {
+                var e = this._events[t];
+                if (r(e)) return 1;
+                if (e) return e.length
+            }
+            return 0
+        }, n.listenerCount = function(t, e) {
+            return t.listenerCount(e)
+        }
+    }, function(t, e, n) {
+        (e = t.exports = n(187)).Stream = e, e.Readable = e, e.Writable = n(117), e.Duplex = n(21), e.Transform = n(192), e.PassThrough = n(458)
+    }, function(t, e, n) {
+        "use strict";
+        (function(e, r, i) {
+            var o = n(79);
+
+            function u(t) {
+                var e = this;
+                this.next = null, this.entry = null, this.finish = function() {
+                    ! function(t, e, n) {
+                        var r = t.entry;
+                        t.entry = null;
+                        for (; r;) {
+                            var i = r.callback;
+                            e.pendingcb--, i(n), r = r.next
+                        }
+                        e.corkedRequestsFree ? e.corkedRequestsFree.next = t : e.corkedRequestsFree = t
+                    }(e, t)
+                }
+            }
+            t.exports = m;
+            var a, s = !e.browser && ["v0.10", "v0.9."].indexOf(e.version.slice(0, 5)) > -1 ? r : o.nextTick;
+            m.WritableState = g;
+            var c = n(51);
+            c.inherits = n(35);
+            var f = {
+                    deprecate: n(457)
+                },
+                l = n(188),
+                p = n(80).Buffer,
+                h = i.Uint8Array || function() {};
+            var d, v = n(189);
+
+            function y() {}
+
+            function g(t, e) {
+                a = a || n(21), t = t || {};
+                var r = e instanceof a;
+                this.objectMode = !!t.objectMode, r && (this.objectMode = this.objectMode || !!t.writableObjectMode);
+                var i = t.highWaterMark,
+                    c = t.writableHighWaterMark,
+                    f = this.objectMode ? 16 : 16384;
+                this.highWaterMark = i || 0 === i ? i : r && (c || 0 === c) ? c : f, this.highWaterMark = Math.floor(this.highWaterMark), this.finalCalled = !1, this.needDrain = !1, this.ending = !1, this.ended = !1, this.finished = !1, this.destroyed = !1;
+                var l = !1 === t.decodeStrings;
+                this.decodeStrings = !l, this.defaultEncoding = t.defaultEncoding || "utf8", this.length = 0, this.writing = !1, this.corked = 0, this.sync = !0, this.bufferProcessing = !1, this.onwrite = function(t) {
+                    ! function(t, e) {
+                        var n = t._writableState,
+                            r = n.sync,
+                            i = n.writecb;
+                        if (function(t) {
+                            t.writing = !1, t.writecb = null, t.length -= t.writelen, t.writelen = 0
+                        }(n), e) ! function(t, e, n, r, i) {
+                            --e.pendingcb, n ? (o.nextTick(i, r), o.nextTick(E, t, e), t._writableState.errorEmitted = !0, t.emit("error", r)) : (i(r), t._writableState.errorEmitted = !0, t.emit("error", r), E(t, e))
+                        }(t, n, r, e, i);
+                        else {
+                            var u = x(n);
+                            u || n.corked || n.bufferProcessing || !n.bufferedRequest || w(t, n), r ? s(b, t, n, u, i) : b(t, n, u, i)
+                        }
+                    }(e, t)
+                }, this.writecb = null, this.writelen = 0, this.bufferedRequest = null, this.lastBufferedRequest = null, this.pendingcb = 0, this.prefinished = !1, this.errorEmitted = !1, this.bufferedRequestCount = 0, this.corkedRequestsFree = new u(this)
+            }
+
+            function m(t) {
+                if (a = a || n(21), !(d.call(m, this) || this instanceof a)) return new m(t);
+                this._writableState = new g(t, this), this.writable = !0, t && ("function" == typeof t.write && (this._write = t.write), "function" == typeof t.writev && (this._writev = t.writev), "function" == typeof t.destroy && (this._destroy = t.destroy), "function" == typeof t.final && (this._final = t.final)), l.call(this)
+            }
+
+            function _(t, e, n, r, i, o, u) {
+                e.writelen = r, e.writecb = u, e.writing = !0, e.sync = !0, n ? t._writev(i, e.onwrite) : t._write(i, o, e.onwrite), e.sync = !1
+            }
+
+            function b(t, e, n, r) {
+                n || function(t, e) {
+                    0 === e.length && e.needDrain && (e.needDrain = !1, t.emit("drain"))
+                }(t, e), e.pendingcb--, r(), E(t, e)
+            }
+
+            function w(t, e) {
+                e.bufferProcessing = !0;
+                var n = e.bufferedRequest;
+                if (t._writev && n && n.next) {
+                    var r = e.bufferedRequestCount,
+                        i = new Array(r),
+                        o = e.corkedRequestsFree;
+                    o.entry = n;
+                    for (var a = 0, s = !0; n;) i[a] = n, n.isBuf || (s = !1), n = n.next, a += 1;
+                    i.allBuffers = s, _(t, e, !0, e.length, i, "", o.finish), e.pendingcb++, e.lastBufferedRequest = null, o.next ? (e.corkedRequestsFree = o.next, o.next = null) : e.corkedRequestsFree = new u(e), e.bufferedRequestCount = 0
+                } else {
+                    for (; n;) {
+                        var c = n.chunk,
+                            f = n.encoding,
+                            l = n.callback;
+                        if (_(t, e, !1, e.objectMode ? 1 : c.length, c, f, l), n = n.next, e.bufferedRequestCount--, e.writing) break
+                    }
+                    null === n && (e.lastBufferedRequest = null)
+                }
+                e.bufferedRequest = n, e.bufferProcessing = !1
+            }
+
+            function x(t) {
+                return t.ending && 0 === t.length && null === t.bufferedRequest && !t.finished && !t.writing
+            }
+
+            function S(t, e) {
+                t._final(function(n) {
+                    e.pendingcb--, n && t.emit("error", n), e.prefinished = !0, t.emit("prefinish"), E(t, e)
+                })
+            }
+
+            function E(t, e) {
+                var n = x(e);
+                return n && (! function(t, e) {
+                    e.prefinished || e.finalCalled || ("function" == typeof t._final ? (e.pendingcb++, e.finalCalled = !0, o.nextTick(S, t, e)) : (e.prefinished = !0, t.emit("prefinish")))
+                }(t, e), 0 === e.pendingcb && (e.finished = !0, t.emit("finish"))), n
+            }
+            c.inherits(m, l), g.prototype.getBuffer = function() {
+                for (var t = this.bufferedRequest, e = []; t;) e.push(t), t = t.next;
+                return e
+            },
+                function() {
+                    try {
+                        Object.defineProperty(g.prototype, "buffer", {
+                            get: f.deprecate(function() {
+                                return this.getBuffer()
+                            }, "_writableState.buffer is deprecated. Use _writableState.getBuffer instead.", "DEP0003")
+                        })
+                    } catch (t) {}
+                }(), "function" == typeof Symbol && Symbol.hasInstance && "function" == typeof Function.prototype[Symbol.hasInstance] ? (d = Function.prototype[Symbol.hasInstance], Object.defineProperty(m, Symbol.hasInstance, {
+                value: function(t) {
+                    return !!d.call(this, t) || this === m && (t && t._writableState instanceof g)
+                }
+            })) : d = function(t) {
+                return t instanceof this
+            }, m.prototype.pipe = function() {
+                this.emit("error", new Error("Cannot pipe, not readable"))
+            }, m.prototype.write = function(t, e, n) {
+                var r, i = this._writableState,
+                    u = !1,
+                    a = !i.objectMode && (r = t, p.isBuffer(r) || r instanceof h);
+                return a && !p.isBuffer(t) && (t = function(t) {
+                    return p.from(t)
+                }(t)), "function" == typeof e && (n = e, e = null), a ? e = "buffer" : e || (e = i.defaultEncoding), "function" != typeof n && (n = y), i.ended ? function(t, e) {
+                    var n = new Error("write after end");
+                    t.emit("error", n), o.nextTick(e, n)
+                }(this, n) : (a || function(t, e, n, r) {
+                    var i = !0,
+                        u = !1;
+                    return null === n ? u = new TypeError("May not write null values to stream") : "string" == typeof n || void 0 === n || e.objectMode || (u = new TypeError("Invalid non-string/buffer chunk")), u && (t.emit("error", u), o.nextTick(r, u), i = !1), i
+                }(this, i, t, n)) && (i.pendingcb++, u = function(t, e, n, r, i, o) {
+                    if (!n) {
+                        var u = function(t, e, n) {
+                            t.objectMode || !1 === t.decodeStrings || "string" != typeof e || (e = p.from(e, n));
+                            return e
+                        }(e, r, i);
+                        r !== u && (n = !0, i = "buffer", r = u)
+                    }
+                    var a = e.objectMode ? 1 : r.length;
+                    e.length += a;
+                    var s = e.length < e.highWaterMark;
+                    s || (e.needDrain = !0);
+                    if (e.writing || e.corked) {
+                        var c = e.lastBufferedRequest;
+                        e.lastBufferedRequest = {
+                            chunk: r,
+                            encoding: i,
+                            isBuf: n,
+                            callback: o,
+                            next: null
+                        }, c ? c.next = e.lastBufferedRequest : e.bufferedRequest = e.lastBufferedRequest, e.bufferedRequestCount += 1
+                    } else _(t, e, !1, a, r, i, o);
+                    return s
+                }(this, i, a, t, e, n)), u
+            }, m.prototype.cork = function() {
+                this._writableState.corked++
+            }, m.prototype.uncork = function() {
+                var t = this._writableState;
+                t.corked && (t.corked--, t.writing || t.corked || t.finished || t.bufferProcessing || !t.bufferedRequest || w(this, t))
+            }, m.prototype.setDefaultEncoding = function(t) {
+                if ("string" == typeof t && (t = t.toLowerCase()), !(["hex", "utf8", "utf-8", "ascii", "binary", "base64", "ucs2", "ucs-2", "utf16le", "utf-16le", "raw"].indexOf((t + "").toLowerCase()) > -1)) throw new TypeError("Unknown encoding: " + t);
+                return this._writableState.defaultEncoding = t, this
+            }, Object.defineProperty(m.prototype, "writableHighWaterMark", {
+                enumerable: !1,
+                get: function() {
+                    return this._writableState.highWaterMark
+                }
+            }), m.prototype._write = function(t, e, n) {
+                n(new Error("_write() is not implemented"))
+            }, m.prototype._writev = null, m.prototype.end = function(t, e, n) {
+                var r = this._writableState;
+                "function" == typeof t ? (n = t, t = null, e = null) : "function" == typeof e && (n = e, e = null), null !== t && void 0 !== t && this.write(t, e), r.corked && (r.corked = 1, this.uncork()), r.ending || r.finished || function(t, e, n) {
+                    e.ending = !0, E(t, e), n && (e.finished ? o.nextTick(n) : t.once("finish", n));
+                    e.ended = !0, t.writable = !1
+                }(this, r, n)
+            }, Object.defineProperty(m.prototype, "destroyed", {
+                get: function() {
+                    return void 0 !== this._writableState && this._writableState.destroyed
+                },
+                set: function(t) {
+                    this._writableState && (this._writableState.destroyed = t)
+                }
+            }), m.prototype.destroy = v.destroy, m.prototype._undestroy = v.undestroy, m.prototype._destroy = function(t, e) {
+                this.end(), e(t)
+            }
+        }).call(e, n(34), n(190).setImmediate, n(11))
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            return "function" == typeof t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(484)() ? Array.from : n(485)
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(498),
+            i = n(23),
+            o = n(36),
+            u = Array.prototype.indexOf,
+            a = Object.prototype.hasOwnProperty,
+            s = Math.abs,
+            c = Math.floor;
+        t.exports = function(t) {
+            var e, n, f, l;
+            if (!r(t)) return u.apply(this, arguments);
+            for (n = i(o(this).length), f = arguments[1], e = f = isNaN(f) ? 0 : f >= 0 ? c(f) : i(this.length) - c(s(f)); e < n; ++e)
+                if (a.call(this, e) && (l = this[e], r(l))) return e;
+            return -1
+        }
+    }, function(t, e, n) {
+        "use strict";
+        (function(e, n) {
+            var r, i;
+            r = function(t) {
+                if ("function" != typeof t) throw new TypeError(t + " is not a function");
+                return t
+            }, i = function(t) {
+                var e, n, i = document.createTextNode(""),
+                    o = 0;
+                return new t(function() {
+                    var t;
+                    if (e) n && (e = n.concat(e));
+                    else {
+                        if (!n) return;
+                        e = n
+                    }
+                    if (n = e, e = null, "function" == typeof n) return t = n, n = null, void t();
+                    for (i.data = o = ++o % 2; n;) t = n.shift(), n.length || (n = null), t()
+                }).observe(i, {
+                    characterData: !0
+                }),
+                    function(t) {
+                        r(t), e ? "function" == typeof e ? e = [e, t] : e.push(t) : (e = t, i.data = o = ++o % 2)
+                    }
+            }, t.exports = function() {
+                if ("object" == typeof e && e && "function" == typeof e.nextTick) return e.nextTick;
+                if ("object" == typeof document && document) {
+                    if ("function" == typeof MutationObserver) return i(MutationObserver);
+                    if ("function" == typeof WebKitMutationObserver) return i(WebKitMutationObserver)
+                }
+                return "function" == typeof n ? function(t) {
+                    n(r(t))
+                } : "function" == typeof setTimeout || "object" == typeof setTimeout ? function(t) {
+                    setTimeout(r(t), 0)
+                } : null
+            }()
+        }).call(e, n(34), n(190).setImmediate)
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(38);
+        t.exports = new r({
+            explicit: [n(524), n(525), n(526)]
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r, i = n(84),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            };
+        t.exports = function() {
+            var t = {
+                location: {},
+                history: {},
+                open: function() {},
+                close: function() {},
+                File: function() {}
+            };
+            if ("undefined" == typeof window) return t;
+            try {
+                t = window;
+                var e = !0,
+                    n = !1,
+                    r = void 0;
+                try {
+                    for (var i, u = (0, o.default)(["File", "Blob", "FormData"]); !(e = (i = u.next()).done); e = !0) {
+                        var a = i.value;
+                        a in window && (t[a] = window[a])
+                    }
+                } catch (t) {
+                    n = !0, r = t
+                } finally {
+                    try {
+                        !e && u.return && u.return()
+                    } finally {
+                        if (n) throw r
+                    }
+                }
+            } catch (t) {
+                console.error(t)
+            }
+            return t
+        }()
+    }, function(t, e, n) {
+        var r = n(86);
+        t.exports = Object("z").propertyIsEnumerable(0) ? Object : function(t) {
+            return "String" == r(t) ? t.split("") : Object(t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(88),
+            i = n(13),
+            o = n(129),
+            u = n(26),
+            a = n(39),
+            s = n(213),
+            c = n(95),
+            f = n(131),
+            l = n(6)("iterator"),
+            p = !([].keys && "next" in [].keys()),
+            h = function() {
+                return this
+            };
+        t.exports = function(t, e, n, d, v, y, g) {
+            s(n, e, d);
+            var m, _, b, w = function(t) {
+                    if (!p && t in A) return A[t];
+                    switch (t) {
+                        case "keys":
+                        case "values":
+                            return function() {
+                                return new n(this, t)
+                            }
+                    }
+                    return function() {
+                        return new n(this, t)
+                    }
+                },
+                x = e + " Iterator",
+                S = "values" == v,
+                E = !1,
+                A = t.prototype,
+                O = A[l] || A["@@iterator"] || v && A[v],
+                j = O || w(v),
+                k = v ? S ? w("entries") : j : void 0,
+                I = "Array" == e && A.entries || O;
+            if (I && (b = f(I.call(new t))) !== Object.prototype && b.next && (c(b, x, !0), r || "function" == typeof b[l] || u(b, l, h)), S && O && "values" !== O.name && (E = !0, j = function() {
+                return O.call(this)
+            }), r && !g || !p && !E && A[l] || u(A, l, j), a[e] = j, a[x] = h, v)
+                if (m = {
+                    values: S ? j : w("values"),
+                    keys: y ? j : w("keys"),
+                    entries: k
+                }, g)
+                    for (_ in m) _ in A || o(A, _, m[_]);
+                else i(i.P + i.F * (p || E), e, m);
+            return m
+        }
+    }, function(t, e, n) {
+        var r = n(212);
+        t.exports = function(t, e, n) {
+            if (r(t), void 0 === e) return t;
+            switch (n) {
+                case 1:
+                    return function(n) {
+                        return t.call(e, n)
+                    };
+                case 2:
+                    return function(n, r) {
+                        return t.call(e, n, r)
+                    };
+                case 3:
+                    return function(n, r, i) {
+                        return t.call(e, n, r, i)
+                    }
+            }
+            return function() {
+                return t.apply(e, arguments)
+            }
+        }
+    }, function(t, e, n) {
+        t.exports = !n(15) && !n(29)(function() {
+            return 7 != Object.defineProperty(n(128)("div"), "a", {
+                get: function() {
+                    return 7
+                }
+            }).a
+        })
+    }, function(t, e, n) {
+        var r = n(28),
+            i = n(9).document,
+            o = r(i) && r(i.createElement);
+        t.exports = function(t) {
+            return o ? i.createElement(t) : {}
+        }
+    }, function(t, e, n) {
+        t.exports = n(26)
+    }, function(t, e, n) {
+        var r = n(16),
+            i = n(25),
+            o = n(215)(!1),
+            u = n(92)("IE_PROTO");
+        t.exports = function(t, e) {
+            var n, a = i(t),
+                s = 0,
+                c = [];
+            for (n in a) n != u && r(a, n) && c.push(n);
+            for (; e.length > s;) r(a, n = e[s++]) && (~o(c, n) || c.push(n));
+            return c
+        }
+    }, function(t, e, n) {
+        var r = n(16),
+            i = n(56),
+            o = n(92)("IE_PROTO"),
+            u = Object.prototype;
+        t.exports = Object.getPrototypeOf || function(t) {
+            return t = i(t), r(t, o) ? t[o] : "function" == typeof t.constructor && t instanceof t.constructor ? t.constructor.prototype : t instanceof Object ? u : null
+        }
+    }, function(t, e, n) {
+        var r = n(86),
+            i = n(6)("toStringTag"),
+            o = "Arguments" == r(function() {
+                return arguments
+            }());
+        t.exports = function(t) {
+            var e, n, u;
+            return void 0 === t ? "Undefined" : null === t ? "Null" : "string" == typeof(n = function(t, e) {
+                try {
+                    return t[e]
+                } catch (t) {}
+            }(e = Object(t), i)) ? n : o ? r(e) : "Object" == (u = r(e)) && "function" == typeof e.callee ? "Arguments" : u
+        }
+    }, function(t, e, n) {
+        var r = n(5),
+            i = r["__core-js_shared__"] || (r["__core-js_shared__"] = {});
+        t.exports = function(t) {
+            return i[t] || (i[t] = {})
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return {
+                enumerable: !(1 & t),
+                configurable: !(2 & t),
+                writable: !(4 & t),
+                value: e
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(136)(!0);
+        n(137)(String, "String", function(t) {
+            this._t = String(t), this._i = 0
+        }, function() {
+            var t, e = this._t,
+                n = this._i;
+            return n >= e.length ? {
+                value: void 0,
+                done: !0
+            } : (t = r(e, n), this._i += t.length, {
+                value: t,
+                done: !1
+            })
+        })
+    }, function(t, e, n) {
+        var r = n(59),
+            i = n(10);
+        t.exports = function(t) {
+            return function(e, n) {
+                var o, u, a = String(i(e)),
+                    s = r(n),
+                    c = a.length;
+                return s < 0 || s >= c ? t ? "" : void 0 : (o = a.charCodeAt(s)) < 55296 || o > 56319 || s + 1 === c || (u = a.charCodeAt(s + 1)) < 56320 || u > 57343 ? t ? a.charAt(s) : o : t ? a.slice(s, s + 2) : u - 56320 + (o - 55296 << 10) + 65536
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(138),
+            i = n(3),
+            o = n(30),
+            u = n(17),
+            a = n(44),
+            s = n(226),
+            c = n(101),
+            f = n(232),
+            l = n(1)("iterator"),
+            p = !([].keys && "next" in [].keys()),
+            h = function() {
+                return this
+            };
+        t.exports = function(t, e, n, d, v, y, g) {
+            s(n, e, d);
+            var m, _, b, w = function(t) {
+                    if (!p && t in A) return A[t];
+                    switch (t) {
+                        case "keys":
+                        case "values":
+                            return function() {
+                                return new n(this, t)
+                            }
+                    }
+                    return function() {
+                        return new n(this, t)
+                    }
+                },
+                x = e + " Iterator",
+                S = "values" == v,
+                E = !1,
+                A = t.prototype,
+                O = A[l] || A["@@iterator"] || v && A[v],
+                j = O || w(v),
+                k = v ? S ? w("entries") : j : void 0,
+                I = "Array" == e && A.entries || O;
+            if (I && (b = f(I.call(new t))) !== Object.prototype && b.next && (c(b, x, !0), r || "function" == typeof b[l] || u(b, l, h)), S && O && "values" !== O.name && (E = !0, j = function() {
+                return O.call(this)
+            }), r && !g || !p && !E && A[l] || u(A, l, j), a[e] = j, a[x] = h, v)
+                if (m = {
+                    values: S ? j : w("values"),
+                    keys: y ? j : w("keys"),
+                    entries: k
+                }, g)
+                    for (_ in m) _ in A || o(A, _, m[_]);
+                else i(i.P + i.F * (p || E), e, m);
+            return m
+        }
+    }, function(t, e) {
+        t.exports = !1
+    }, function(t, e, n) {
+        var r = n(229),
+            i = n(141);
+        t.exports = Object.keys || function(t) {
+            return r(t, i)
+        }
+    }, function(t, e, n) {
+        var r = n(59),
+            i = Math.max,
+            o = Math.min;
+        t.exports = function(t, e) {
+            return (t = r(t)) < 0 ? i(t + e, 0) : o(t, e)
+        }
+    }, function(t, e) {
+        t.exports = "constructor,hasOwnProperty,isPrototypeOf,propertyIsEnumerable,toLocaleString,toString,valueOf".split(",")
+    }, function(t, e, n) {
+        var r = n(5).document;
+        t.exports = r && r.documentElement
+    }, function(t, e, n) {
+        var r = n(18),
+            i = n(61),
+            o = n(1)("species");
+        t.exports = function(t, e) {
+            var n, u = r(t).constructor;
+            return void 0 === u || void 0 == (n = r(u)[o]) ? e : i(n)
+        }
+    }, function(t, e, n) {
+        var r, i, o, u = n(60),
+            a = n(244),
+            s = n(142),
+            c = n(99),
+            f = n(5),
+            l = f.process,
+            p = f.setImmediate,
+            h = f.clearImmediate,
+            d = f.MessageChannel,
+            v = f.Dispatch,
+            y = 0,
+            g = {},
+            m = function() {
+                var t = +this;
+                if (g.hasOwnProperty(t)) {
+                    var e = g[t];
+                    delete g[t], e()
+                }
+            },
+            _ = function(t) {
+                m.call(t.data)
+            };
+        p && h || (p = function(t) {
+            for (var e = [], n = 1; arguments.length > n;) e.push(arguments[n++]);
+            return g[++y] = function() {
+                a("function" == typeof t ? t : Function(t), e)
+            }, r(y), y
+        }, h = function(t) {
+            delete g[t]
+        }, "process" == n(41)(l) ? r = function(t) {
+            l.nextTick(u(m, t, 1))
+        } : v && v.now ? r = function(t) {
+            v.now(u(m, t, 1))
+        } : d ? (o = (i = new d).port2, i.port1.onmessage = _, r = u(o.postMessage, o, 1)) : f.addEventListener && "function" == typeof postMessage && !f.importScripts ? (r = function(t) {
+            f.postMessage(t + "", "*")
+        }, f.addEventListener("message", _, !1)) : r = "onreadystatechange" in c("script") ? function(t) {
+            s.appendChild(c("script")).onreadystatechange = function() {
+                s.removeChild(this), m.call(t)
+            }
+        } : function(t) {
+            setTimeout(u(m, t, 1), 0)
+        }), t.exports = {
+            set: p,
+            clear: h
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            try {
+                return {
+                    e: !1,
+                    v: t()
+                }
+            } catch (t) {
+                return {
+                    e: !0,
+                    v: t
+                }
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(18),
+            i = n(31),
+            o = n(102);
+        t.exports = function(t, e) {
+            if (r(t), i(e) && e.constructor === t) return e;
+            var n = o.f(t);
+            return (0, n.resolve)(e), n.promise
+        }
+    }, function(t, e, n) {
+        var r = n(31),
+            i = n(41),
+            o = n(1)("match");
+        t.exports = function(t) {
+            var e;
+            return r(t) && (void 0 !== (e = t[o]) ? !!e : "RegExp" == i(t))
+        }
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(282),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        var r = n(13),
+            i = n(2),
+            o = n(29);
+        t.exports = function(t, e) {
+            var n = (i.Object || {})[t] || Object[t],
+                u = {};
+            u[t] = e(n), r(r.S + r.F * o(function() {
+                n(1)
+            }), "Object", u)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0, e.default = function(t, e) {
+            if (!(t instanceof e)) throw new TypeError("Cannot call a class as a function")
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0;
+        var r, i = n(152),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            };
+        e.default = function() {
+            function t(t, e) {
+                for (var n = 0; n < e.length; n++) {
+                    var r = e[n];
+                    r.enumerable = r.enumerable || !1, r.configurable = !0, "value" in r && (r.writable = !0), (0, o.default)(t, r.key, r)
+                }
+            }
+            return function(e, n, r) {
+                return n && t(e.prototype, n), r && t(e, r), e
+            }
+        }()
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(284),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0;
+        var r, i = n(105),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            };
+        e.default = function(t, e) {
+            if (!t) throw new ReferenceError("this hasn't been initialised - super() hasn't been called");
+            return !e || "object" !== (void 0 === e ? "undefined" : (0, o.default)(e)) && "function" != typeof e ? t : e
+        }
+    }, function(t, e, n) {
+        var r = n(130),
+            i = n(94).concat("length", "prototype");
+        e.f = Object.getOwnPropertyNames || function(t) {
+            return r(t, i)
+        }
+    }, function(t, e, n) {
+        var r = n(64),
+            i = n(54),
+            o = n(25),
+            u = n(89),
+            a = n(16),
+            s = n(127),
+            c = Object.getOwnPropertyDescriptor;
+        e.f = n(15) ? c : function(t, e) {
+            if (t = o(t), e = u(e, !0), s) try {
+                return c(t, e)
+            } catch (t) {}
+            if (a(t, e)) return i(!r.f.call(t, e), t[e])
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0;
+        var r = u(n(298)),
+            i = u(n(302)),
+            o = u(n(105));
+
+        function u(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        e.default = function(t, e) {
+            if ("function" != typeof e && null !== e) throw new TypeError("Super expression must either be null or a function, not " + (void 0 === e ? "undefined" : (0, o.default)(e)));
+            t.prototype = (0, i.default)(e && e.prototype, {
+                constructor: {
+                    value: t,
+                    enumerable: !1,
+                    writable: !0,
+                    configurable: !0
+                }
+            }), e && (r.default ? (0, r.default)(t, e) : t.__proto__ = e)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(305)
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(65),
+            i = n(46),
+            o = n(159),
+            u = (n(160), n(161));
+        n(20), n(306);
+
+        function a(t, e, n) {
+            this.props = t, this.context = e, this.refs = u, this.updater = n || o
+        }
+
+        function s(t, e, n) {
+            this.props = t, this.context = e, this.refs = u, this.updater = n || o
+        }
+
+        function c() {}
+        a.prototype.isReactComponent = {}, a.prototype.setState = function(t, e) {
+            "object" != typeof t && "function" != typeof t && null != t && r("85"), this.updater.enqueueSetState(this, t), e && this.updater.enqueueCallback(this, e, "setState")
+        }, a.prototype.forceUpdate = function(t) {
+            this.updater.enqueueForceUpdate(this), t && this.updater.enqueueCallback(this, t, "forceUpdate")
+        }, c.prototype = a.prototype, s.prototype = new c, s.prototype.constructor = s, i(s.prototype, a.prototype), s.prototype.isPureReactComponent = !0, t.exports = {
+            Component: a,
+            PureComponent: s
+        }
+    }, function(t, e, n) {
+        "use strict";
+        n(66);
+        var r = {
+            isMounted: function(t) {
+                return !1
+            },
+            enqueueCallback: function(t, e) {},
+            enqueueForceUpdate: function(t) {},
+            enqueueReplaceState: function(t, e) {},
+            enqueueSetState: function(t, e) {}
+        };
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        var r = !1;
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        var r = {};
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = {
+            current: null
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = "function" == typeof Symbol && Symbol.for && Symbol.for("react.element") || 60103;
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = "SECRET_DO_NOT_PASS_THIS_OR_YOU_WILL_BE_FIRED"
+    }, function(t, e, n) {
+        t.exports = n(321)()
+    }, function(t, e, n) {
+        "use strict";
+        (function(t) {
+            Object.defineProperty(e, "__esModule", {
+                value: !0
+            }), e.getCommonExtensions = e.getExtensions = e.escapeDeepLinkPath = e.createDeepLinkPath = e.shallowEqualKeys = e.buildFormData = e.sorters = e.btoa = e.serializeSearch = e.parseSearch = e.getSampleSchema = e.validateParam = e.validatePattern = e.validateMinLength = e.validateMaxLength = e.validateGuid = e.validateDateTime = e.validateString = e.validateBoolean = e.validateFile = e.validateInteger = e.validateNumber = e.validateMinimum = e.validateMaximum = e.propChecker = e.memoize = e.isImmutable = void 0;
+            var r = b(n(327)),
+                i = b(n(329)),
+                o = b(n(84)),
+                u = b(n(333)),
+                a = b(n(337)),
+                s = b(n(105));
+            e.isJSONObject = function(t) {
+                try {
+                    var e = JSON.parse(t);
+                    if (e && "object" === (void 0 === e ? "undefined" : (0, s.default)(e))) return e
+                } catch (t) {}
+                return !1
+            }, e.objectify = function(t) {
+                return E(t) ? x(t) ? t.toJS() : t : {}
+            }, e.arrayify = function(t) {
+                return t ? t.toArray ? t.toArray() : S(t) : []
+            }, e.fromJSOrdered = function t(e) {
+                if (x(e)) return e;
+                if (e instanceof m.default.File) return e;
+                return E(e) ? Array.isArray(e) ? c.default.Seq(e).map(t).toList() : c.default.OrderedMap(e).map(t) : e
+            }, e.bindToState = function(t, e) {
+                var n = {};
+                return (0, a.default)(t).filter(function(e) {
+                    return "function" == typeof t[e]
+                }).forEach(function(r) {
+                    return n[r] = t[r].bind(null, e)
+                }), n
+            }, e.normalizeArray = S, e.isFn = function(t) {
+                return "function" == typeof t
+            }, e.isObject = E, e.isFunc = function(t) {
+                return "function" == typeof t
+            }, e.isArray = function(t) {
+                return Array.isArray(t)
+            }, e.objMap = function(t, e) {
+                return (0, a.default)(t).reduce(function(n, r) {
+                    return n[r] = e(t[r], r), n
+                }, {})
+            }, e.objReduce = function(t, e) {
+                return (0, a.default)(t).reduce(function(n, r) {
+                    var i = e(t[r], r);
+                    return i && "object" === (void 0 === i ? "undefined" : (0, s.default)(i)) && (0, u.default)(n, i), n
+                }, {})
+            }, e.systemThunkMiddleware = function(t) {
+                return function(e) {
+                    e.dispatch, e.getState;
+                    return function(e) {
+                        return function(n) {
+                            return "function" == typeof n ? n(t()) : e(n)
+                        }
+                    }
+                }
+            }, e.defaultStatusCode = function(t) {
+                var e = t.keySeq();
+                return e.contains(w) ? w : e.filter(function(t) {
+                    return "2" === (t + "")[0]
+                }).sort().first()
+            }, e.getList = function(t, e) {
+                if (!c.default.Iterable.isIterable(t)) return c.default.List();
+                var n = t.getIn(Array.isArray(e) ? e : [e]);
+                return c.default.List.isList(n) ? n : c.default.List()
+            }, e.highlight = function(t) {
+                var e = document;
+                if (!t) return "";
+                if (t.textContent.length > 5e3) return t.textContent;
+                return function(t) {
+                    for (var n, r, i, o, u, a = t.textContent, s = 0, c = a[0], f = 1, l = t.innerHTML = "", p = 0; r = n, n = p < 7 && "\\" == n ? 1 : f;) {
+                        if (f = c, c = a[++s], o = l.length > 1, !f || p > 8 && "\n" == f || [/\S/.test(f), 1, 1, !/[$\w]/.test(f), ("/" == n || "\n" == n) && o, '"' == n && o, "'" == n && o, a[s - 4] + r + n == "--\x3e", r + n == "*/"][p])
+                            for (l && (t.appendChild(u = e.createElement("span")).setAttribute("style", ["color: #555; font-weight: bold;", "", "", "color: #555;", ""][p ? p < 3 ? 2 : p > 6 ? 4 : p > 3 ? 3 : +/^(a(bstract|lias|nd|rguments|rray|s(m|sert)?|uto)|b(ase|egin|ool(ean)?|reak|yte)|c(ase|atch|har|hecked|lass|lone|ompl|onst|ontinue)|de(bugger|cimal|clare|f(ault|er)?|init|l(egate|ete)?)|do|double|e(cho|ls?if|lse(if)?|nd|nsure|num|vent|x(cept|ec|p(licit|ort)|te(nds|nsion|rn)))|f(allthrough|alse|inal(ly)?|ixed|loat|or(each)?|riend|rom|unc(tion)?)|global|goto|guard|i(f|mp(lements|licit|ort)|n(it|clude(_once)?|line|out|stanceof|t(erface|ernal)?)?|s)|l(ambda|et|ock|ong)|m(icrolight|odule|utable)|NaN|n(amespace|ative|ext|ew|il|ot|ull)|o(bject|perator|r|ut|verride)|p(ackage|arams|rivate|rotected|rotocol|ublic)|r(aise|e(adonly|do|f|gister|peat|quire(_once)?|scue|strict|try|turn))|s(byte|ealed|elf|hort|igned|izeof|tatic|tring|truct|ubscript|uper|ynchronized|witch)|t(emplate|hen|his|hrows?|ransient|rue|ry|ype(alias|def|id|name|of))|u(n(checked|def(ined)?|ion|less|signed|til)|se|sing)|v(ar|irtual|oid|olatile)|w(char_t|hen|here|hile|ith)|xor|yield)$/.test(l) : 0]), u.appendChild(e.createTextNode(l))), i = p && p < 7 ? p : i, l = "", p = 11; ![1, /[\/{}[(\-+*=<>:;|\\.,?!&@~]/.test(f), /[\])]/.test(f), /[$\w]/.test(f), "/" == f && i < 2 && "<" != n, '"' == f, "'" == f, f + c + a[s + 1] + a[s + 2] == "\x3c!--", f + c == "/*", f + c == "//", "#" == f][--p];);
+                        l += f
+                    }
+                }(t)
+            }, e.mapToList = function t(e) {
+                var n = arguments.length > 1 && void 0 !== arguments[1] ? arguments[1] : "key";
+                var r = arguments.length > 2 && void 0 !== arguments[2] ? arguments[2] : c.default.Map();
+                if (!c.default.Map.isMap(e) || !e.size) return c.default.List();
+                Array.isArray(n) || (n = [n]);
+                if (n.length < 1) return e.merge(r);
+                var u = c.default.List();
+                var a = n[0];
+                var s = !0;
+                var f = !1;
+                var l = void 0;
+                try {
+                    for (var p, h = (0, o.default)(e.entries()); !(s = (p = h.next()).done); s = !0) {
+                        var d = p.value,
+                            v = (0, i.default)(d, 2),
+                            y = v[0],
+                            g = v[1],
+                            m = t(g, n.slice(1), r.set(a, y));
+                        u = c.default.List.isList(m) ? u.concat(m) : u.push(m)
+                    }
+                } catch (t) {
+                    f = !0, l = t
+                } finally {
+                    try {
+                        !s && h.return && h.return()
+                    } finally {
+                        if (f) throw l
+                    }
+                }
+                return u
+            }, e.extractFileNameFromContentDispositionHeader = function(t) {
+                var e = /filename="([^;]*);?"/i.exec(t);
+                null === e && (e = /filename=([^;]*);?/i.exec(t));
+                if (null !== e && e.length > 1) return e[1];
+                return null
+            }, e.pascalCase = A, e.pascalCaseFilename = function(t) {
+                return A(t.replace(/\.[^./]*$/, ""))
+            }, e.sanitizeUrl = function(t) {
+                if ("string" != typeof t || "" === t) return "";
+                return (0, f.sanitizeUrl)(t)
+            }, e.getAcceptControllingResponse = function(t) {
+                if (!c.default.OrderedMap.isOrderedMap(t)) return null;
+                if (!t.size) return null;
+                var e = t.find(function(t, e) {
+                        return e.startsWith("2") && (0, a.default)(t.get("content") || {}).length > 0
+                    }),
+                    n = t.get("default") || c.default.OrderedMap(),
+                    r = (n.get("content") || c.default.OrderedMap()).keySeq().toJS().length ? n : null;
+                return e || r
+            }, e.deeplyStripKey = function t(e, n) {
+                var r = arguments.length > 2 && void 0 !== arguments[2] ? arguments[2] : function() {
+                    return !0
+                };
+                if ("object" !== (void 0 === e ? "undefined" : (0, s.default)(e)) || Array.isArray(e) || null === e || !n) return e;
+                var i = (0, u.default)({}, e);
+                (0, a.default)(i).forEach(function(e) {
+                    e === n && r(i[e], e) ? delete i[e] : i[e] = t(i[e], n, r)
+                });
+                return i
+            }, e.stringify = function(t) {
+                if ("string" == typeof t) return t;
+                t.toJS && (t = t.toJS());
+                if ("object" === (void 0 === t ? "undefined" : (0, s.default)(t)) && null !== t) try {
+                    return (0, r.default)(t, null, 2)
+                } catch (e) {
+                    return String(t)
+                }
+                return t.toString()
+            }, e.numberToString = function(t) {
+                if ("number" == typeof t) return t.toString();
+                return t
+            };
+            var c = b(n(168)),
+                f = n(340),
+                l = b(n(341)),
+                p = b(n(170)),
+                h = b(n(172)),
+                d = b(n(383)),
+                v = b(n(441)),
+                y = b(n(74)),
+                g = n(449),
+                m = b(n(123)),
+                _ = b(n(517));
+
+            function b(t) {
+                return t && t.__esModule ? t : {
+                    default: t
+                }
+            }
+            var w = "default",
+                x = e.isImmutable = function(t) {
+                    return c.default.Iterable.isIterable(t)
+                };
+
+            function S(t) {
+                return Array.isArray(t) ? t : [t]
+            }
+
+            function E(t) {
+                return !!t && "object" === (void 0 === t ? "undefined" : (0, s.default)(t))
+            }
+            e.memoize = h.default;
+
+            function A(t) {
+                return (0, p.default)((0, l.default)(t))
+            }
+            e.propChecker = function(t, e) {
+                var n = arguments.length > 2 && void 0 !== arguments[2] ? arguments[2] : [],
+                    r = arguments.length > 3 && void 0 !== arguments[3] ? arguments[3] : [];
+                return (0, a.default)(t).length !== (0, a.default)(e).length || ((0, v.default)(t, function(t, n) {
+                    if (r.includes(n)) return !1;
+                    var i = e[n];
+                    return c.default.Iterable.isIterable(t) ? !c.default.is(t, i) : ("object" !== (void 0 === t ? "undefined" : (0, s.default)(t)) || "object" !== (void 0 === i ? "undefined" : (0, s.default)(i))) && t !== i
+                }) || n.some(function(n) {
+                    return !(0, y.default)(t[n], e[n])
+                }))
+            };
+            var O = e.validateMaximum = function(t, e) {
+                    if (t > e) return "Value must be less than Maximum"
+                },
+                j = e.validateMinimum = function(t, e) {
+                    if (t < e) return "Value must be greater than Minimum"
+                },
+                k = e.validateNumber = function(t) {
+                    if (!/^-?\d+(\.?\d+)?$/.test(t)) return "Value must be a number"
+                },
+                I = e.validateInteger = function(t) {
+                    if (!/^-?\d+$/.test(t)) return "Value must be an integer"
+                },
+                M = e.validateFile = function(t) {
+                    if (t && !(t instanceof m.default.File)) return "Value must be a file"
+                },
+                T = e.validateBoolean = function(t) {
+                    if ("true" !== t && "false" !== t && !0 !== t && !1 !== t) return "Value must be a boolean"
+                },
+                C = e.validateString = function(t) {
+                    if (t && "string" != typeof t) return "Value must be a string"
+                },
+                P = e.validateDateTime = function(t) {
+                    if (isNaN(Date.parse(t))) return "Value must be a DateTime"
+                },
+                R = e.validateGuid = function(t) {
+                    if (t = t.toString().toLowerCase(), !/^[{(]?[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}[)}]?$/.test(t)) return "Value must be a Guid"
+                },
+                N = e.validateMaxLength = function(t, e) {
+                    if (t.length > e) return "Value must be less than MaxLength"
+                },
+                L = e.validateMinLength = function(t, e) {
+                    if (t.length < e) return "Value must be greater than MinLength"
+                },
+                D = e.validatePattern = function(t, e) {
+                    if (!new RegExp(e).test(t)) return "Value must follow pattern " + e
+                };
+            e.validateParam = function(t, e) {
+                var n = arguments.length > 2 && void 0 !== arguments[2] && arguments[2],
+                    r = [],
+                    i = e && "body" === t.get("in") ? t.get("value_xml") : t.get("value"),
+                    o = t.get("required"),
+                    u = n ? t.get("schema") : t;
+                if (!u) return r;
+                var a = u.get("maximum"),
+                    f = u.get("minimum"),
+                    l = u.get("type"),
+                    p = u.get("format"),
+                    h = u.get("maxLength"),
+                    d = u.get("minLength"),
+                    v = u.get("pattern");
+                if (l && (o || i)) {
+                    var y = "string" === l && i,
+                        g = "array" === l && Array.isArray(i) && i.length,
+                        _ = "array" === l && c.default.List.isList(i) && i.count(),
+                        b = "file" === l && i instanceof m.default.File,
+                        w = "boolean" === l && (i || !1 === i),
+                        x = "number" === l && (i || 0 === i),
+                        S = "integer" === l && (i || 0 === i),
+                        E = !1;
+                    if (n && "object" === l)
+                        if ("object" === (void 0 === i ? "undefined" : (0, s.default)(i))) E = !0;
+                        else if ("string" == typeof i) try {
+                            JSON.parse(i), E = !0
+                        } catch (t) {
+                            return r.push("Parameter string value must be valid JSON"), r
+                        }
+                    var A = [y, g, _, b, w, x, S, E].some(function(t) {
+                        return !!t
+                    });
+                    if (o && !A) return r.push("Required field is not provided"), r;
+                    if (v) {
+                        var z = D(i, v);
+                        z && r.push(z)
+                    }
+                    if (h || 0 === h) {
+                        var F = N(i, h);
+                        F && r.push(F)
+                    }
+                    if (d) {
+                        var U = L(i, d);
+                        U && r.push(U)
+                    }
+                    if (a || 0 === a) {
+                        var B = O(i, a);
+                        B && r.push(B)
+                    }
+                    if (f || 0 === f) {
+                        var q = j(i, f);
+                        q && r.push(q)
+                    }
+                    if ("string" === l) {
+                        var Y = void 0;
+                        if (!(Y = "date-time" === p ? P(i) : "uuid" === p ? R(i) : C(i))) return r;
+                        r.push(Y)
+                    } else if ("boolean" === l) {
+                        var W = T(i);
+                        if (!W) return r;
+                        r.push(W)
+                    } else if ("number" === l) {
+                        var V = k(i);
+                        if (!V) return r;
+                        r.push(V)
+                    } else if ("integer" === l) {
+                        var K = I(i);
+                        if (!K) return r;
+                        r.push(K)
+                    } else if ("array" === l) {
+                        var G;
+                        if (!_ || !i.count()) return r;
+                        G = u.getIn(["items", "type"]), i.forEach(function(t, e) {
+                            var n = void 0;
+                            "number" === G ? n = k(t) : "integer" === G ? n = I(t) : "string" === G && (n = C(t)), n && r.push({
+                                index: e,
+                                error: n
+                            })
+                        })
+                    } else if ("file" === l) {
+                        var $ = M(i);
+                        if (!$) return r;
+                        r.push($)
+                    }
+                }
+                return r
+            }, e.getSampleSchema = function(t) {
+                var e = arguments.length > 1 && void 0 !== arguments[1] ? arguments[1] : "",
+                    n = arguments.length > 2 && void 0 !== arguments[2] ? arguments[2] : {};
+                if (/xml/.test(e)) {
+                    if (!t.xml || !t.xml.name) {
+                        if (t.xml = t.xml || {}, !t.$$ref) return t.type || t.items || t.properties || t.additionalProperties ? '<?xml version="1.0" encoding="UTF-8"?>\n\x3c!-- XML example cannot be generated --\x3e' : null;
+                        var i = t.$$ref.match(/\S*\/(\S+)$/);
+                        t.xml.name = i[1]
+                    }
+                    return (0, g.memoizedCreateXMLExample)(t, n)
+                }
+                var o = (0, g.memoizedSampleFromSchema)(t, n);
+                return "object" === (void 0 === o ? "undefined" : (0, s.default)(o)) ? (0, r.default)(o, null, 2) : o
+            }, e.parseSearch = function() {
+                var t = {},
+                    e = m.default.location.search;
+                if (!e) return {};
+                if ("" != e) {
+                    var n = e.substr(1).split("&");
+                    for (var r in n) n.hasOwnProperty(r) && (r = n[r].split("="), t[decodeURIComponent(r[0])] = r[1] && decodeURIComponent(r[1]) || "")
+                }
+                return t
+            }, e.serializeSearch = function(t) {
+                return (0, a.default)(t).map(function(e) {
+                    return encodeURIComponent(e) + "=" + encodeURIComponent(t[e])
+                }).join("&")
+            }, e.btoa = function(e) {
+                return (e instanceof t ? e : new t(e.toString(), "utf-8")).toString("base64")
+            }, e.sorters = {
+                operationsSorter: {
+                    alpha: function(t, e) {
+                        return t.get("path").localeCompare(e.get("path"))
+                    },
+                    method: function(t, e) {
+                        return t.get("method").localeCompare(e.get("method"))
+                    }
+                },
+                tagsSorter: {
+                    alpha: function(t, e) {
+                        return t.localeCompare(e)
+                    }
+                }
+            }, e.buildFormData = function(t) {
+                var e = [];
+                for (var n in t) {
+                    var r = t[n];
+                    void 0 !== r && "" !== r && e.push([n, "=", encodeURIComponent(r).replace(/%20/g, "+")].join(""))
+                }
+                return e.join("&")
+            }, e.shallowEqualKeys = function(t, e, n) {
+                return !!(0, d.default)(n, function(n) {
+                    return (0, y.default)(t[n], e[n])
+                })
+            };
+            var z = e.createDeepLinkPath = function(t) {
+                return "string" == typeof t || t instanceof String ? t.trim().replace(/\s/g, "_") : ""
+            };
+            e.escapeDeepLinkPath = function(t) {
+                return (0, _.default)(z(t))
+            }, e.getExtensions = function(t) {
+                return t.filter(function(t, e) {
+                    return /^x-/.test(e)
+                })
+            }, e.getCommonExtensions = function(t) {
+                return t.filter(function(t, e) {
+                    return /^pattern|maxLength|minLength|maximum|minimum/.test(e)
+                })
+            }
+        }).call(e, n(68).Buffer)
+    }, function(t, e) {
+        var n = {}.toString;
+        t.exports = Array.isArray || function(t) {
+            return "[object Array]" == n.call(t)
+        }
+    }, function(t, e, n) {
+        var r;
+        r = function() {
+            "use strict";
+            var t = Array.prototype.slice;
+
+            function e(t, e) {
+                e && (t.prototype = Object.create(e.prototype)), t.prototype.constructor = t
+            }
+
+            function n(t) {
+                return u(t) ? t : K(t)
+            }
+
+            function r(t) {
+                return a(t) ? t : G(t)
+            }
+
+            function i(t) {
+                return s(t) ? t : $(t)
+            }
+
+            function o(t) {
+                return u(t) && !c(t) ? t : H(t)
+            }
+
+            function u(t) {
+                return !(!t || !t[l])
+            }
+
+            function a(t) {
+                return !(!t || !t[p])
+            }
+
+            function s(t) {
+                return !(!t || !t[h])
+            }
+
+            function c(t) {
+                return a(t) || s(t)
+            }
+
+            function f(t) {
+                return !(!t || !t[d])
+            }
+            e(r, n), e(i, n), e(o, n), n.isIterable = u, n.isKeyed = a, n.isIndexed = s, n.isAssociative = c, n.isOrdered = f, n.Keyed = r, n.Indexed = i, n.Set = o;
+            var l = "@@__IMMUTABLE_ITERABLE__@@",
+                p = "@@__IMMUTABLE_KEYED__@@",
+                h = "@@__IMMUTABLE_INDEXED__@@",
+                d = "@@__IMMUTABLE_ORDERED__@@",
+                v = 5,
+                y = 1 << v,
+                g = y - 1,
+                m = {},
+                _ = {
+                    value: !1
+                },
+                b = {
+                    value: !1
+                };
+
+            function w(t) {
+                return t.value = !1, t
+            }
+
+            function x(t) {
+                t && (t.value = !0)
+            }
+
+            function S() {}
+
+            function E(t, e) {
+                e = e || 0;
+                for (var n = Math.max(0, t.length - e), r = new Array(n), i = 0; i < n; i++) r[i] = t[i + e];
+                return r
+            }
+
+            function A(t) {
+                return void 0 === t.size && (t.size = t.__iterate(j)), t.size
+            }
+
+            function O(t, e) {
+                if ("number" != typeof e) {
+                    var n = e >>> 0;
+                    if ("" + n !== e || 4294967295 === n) return NaN;
+                    e = n
+                }
+                return e < 0 ? A(t) + e : e
+            }
+
+            function j() {
+                return !0
+            }
+
+            function k(t, e, n) {
+                return (0 === t || void 0 !== n && t <= -n) && (void 0 === e || void 0 !== n && e >= n)
+            }
+
+            function I(t, e) {
+                return T(t, e, 0)
+            }
+
+            function M(t, e) {
+                return T(t, e, e)
+            }
+
+            function T(t, e, n) {
+                return void 0 === t ? n : t < 0 ? Math.max(0, e + t) : void 0 === e ? t : Math.min(e, t)
+            }
+            var C = 0,
+                P = 1,
+                R = 2,
+                N = "function" == typeof Symbol && Symbol.iterator,
+                L = "@@iterator",
+                D = N || L;
+
+            function z(t) {
+                this.next = t
+            }
+
+            function F(t, e, n, r) {
+                var i = 0 === t ? e : 1 === t ? n : [e, n];
+                return r ? r.value = i : r = {
+                    value: i,
+                    done: !1
+                }, r
+            }
+
+            function U() {
+                return {
+                    value: void 0,
+                    done: !0
+                }
+            }
+
+            function B(t) {
+                return !!W(t)
+            }
+
+            function q(t) {
+                return t && "function" == typeof t.next
+            }
+
+            function Y(t) {
+                var e = W(t);
+                return e && e.call(t)
+            }
+
+            function W(t) {
+                var e = t && (N && t[N] || t[L]);
+                if ("function" == typeof e) return e
+            }
+
+            function V(t) {
+                return t && "number" == typeof t.length
+            }
+
+            function K(t) {
+                return null === t || void 0 === t ? ot() : u(t) ? t.toSeq() : function(t) {
+                    var e = st(t) || "object" == typeof t && new et(t);
+                    if (!e) throw new TypeError("Expected Array or iterable object of values, or keyed object: " + t);
+                    return e
+                }(t)
+            }
+
+            function G(t) {
+                return null === t || void 0 === t ? ot().toKeyedSeq() : u(t) ? a(t) ? t.toSeq() : t.fromEntrySeq() : ut(t)
+            }
+
+            function $(t) {
+                return null === t || void 0 === t ? ot() : u(t) ? a(t) ? t.entrySeq() : t.toIndexedSeq() : at(t)
+            }
+
+            function H(t) {
+                return (null === t || void 0 === t ? ot() : u(t) ? a(t) ? t.entrySeq() : t : at(t)).toSetSeq()
+            }
+            z.prototype.toString = function() {
+                return "[Iterator]"
+            }, z.KEYS = C, z.VALUES = P, z.ENTRIES = R, z.prototype.inspect = z.prototype.toSource = function() {
+                return this.toString()
+            }, z.prototype[D] = function() {
+                return this
+            }, e(K, n), K.of = function() {
+                return K(arguments)
+            }, K.prototype.toSeq = function() {
+                return this
+            }, K.prototype.toString = function() {
+                return this.__toString("Seq {", "}")
+            }, K.prototype.cacheResult = function() {
+                return !this._cache && this.__iterateUncached && (this._cache = this.entrySeq().toArray(), this.size = this._cache.length), this
+            }, K.prototype.__iterate = function(t, e) {
+                return ct(this, t, e, !0)
+            }, K.prototype.__iterator = function(t, e) {
+                return ft(this, t, e, !0)
+            }, e(G, K), G.prototype.toKeyedSeq = function() {
+                return this
+            }, e($, K), $.of = function() {
+                return $(arguments)
+            }, $.prototype.toIndexedSeq = function() {
+                return this
+            }, $.prototype.toString = function() {
+                return this.__toString("Seq [", "]")
+            }, $.prototype.__iterate = function(t, e) {
+                return ct(this, t, e, !1)
+            }, $.prototype.__iterator = function(t, e) {
+                return ft(this, t, e, !1)
+            }, e(H, K), H.of = function() {
+                return H(arguments)
+            }, H.prototype.toSetSeq = function() {
+                return this
+            }, K.isSeq = it, K.Keyed = G, K.Set = H, K.Indexed = $;
+            var J, Z, X, Q = "@@__IMMUTABLE_SEQ__@@";
+
+            function tt(t) {
+                this._array = t, this.size = t.length
+            }
+
+            function et(t) {
+                var e = Object.keys(t);
+                this._object = t, this._keys = e, this.size = e.length
+            }
+
+            function nt(t) {
+                this._iterable = t, this.size = t.length || t.size
+            }
+
+            function rt(t) {
+                this._iterator = t, this._iteratorCache = []
+            }
+
+            function it(t) {
+                return !(!t || !t[Q])
+            }
+
+            function ot() {
+                return J || (J = new tt([]))
+            }
+
+            function ut(t) {
+                var e = Array.isArray(t) ? new tt(t).fromEntrySeq() : q(t) ? new rt(t).fromEntrySeq() : B(t) ? new nt(t).fromEntrySeq() : "object" == typeof t ? new et(t) : void 0;
+                if (!e) throw new TypeError("Expected Array or iterable object of [k, v] entries, or keyed object: " + t);
+                return e
+            }
+
+            function at(t) {
+                var e = st(t);
+                if (!e) throw new TypeError("Expected Array or iterable object of values: " + t);
+                return e
+            }
+
+            function st(t) {
+                return V(t) ? new tt(t) : q(t) ? new rt(t) : B(t) ? new nt(t) : void 0
+            }
+
+            function ct(t, e, n, r) {
+                var i = t._cache;
+                if (i) {
+                    for (var o = i.length - 1, u = 0; u <= o; u++) {
+                        var a = i[n ? o - u : u];
+                        if (!1 === e(a[1], r ? a[0] : u, t)) return u + 1
+                    }
+                    return u
+                }
+                return t.__iterateUncached(e, n)
+            }
+
+            function ft(t, e, n, r) {
+                var i = t._cache;
+                if (i) {
+                    var o = i.length - 1,
+                        u = 0;
+                    return new z(function() {
+                        var t = i[n ? o - u : u];
+                        return u++ > o ? {
+                            value: void 0,
+                            done: !0
+                        } : F(e, r ? t[0] : u - 1, t[1])
+                    })
+                }
+                return t.__iteratorUncached(e, n)
+            }
+
+            function lt(t, e) {
+                return e ? function t(e, n, r, i) {
+                    if (Array.isArray(n)) return e.call(i, r, $(n).map(function(r, i) {
+                        return t(e, r, i, n)
+                    }));
+                    if (ht(n)) return e.call(i, r, G(n).map(function(r, i) {
+                        return t(e, r, i, n)
+                    }));
+                    return n
+                }(e, t, "", {
+                    "": t
+                }) : pt(t)
+            }
+
+            function pt(t) {
+                return Array.isArray(t) ? $(t).map(pt).toList() : ht(t) ? G(t).map(pt).toMap() : t
+            }
+
+            function ht(t) {
+                return t && (t.constructor === Object || void 0 === t.constructor)
+            }
+
+            function dt(t, e) {
+                if (t === e || t != t && e != e) return !0;
+                if (!t || !e) return !1;
+                if ("function" == typeof t.valueOf && "function" == typeof e.valueOf) {
+                    if ((t = t.valueOf()) === (e = e.valueOf()) || t != t && e != e) return !0;
+                    if (!t || !e) return !1
+                }
+                return !("function" != typeof t.equals || "function" != typeof e.equals || !t.equals(e))
+            }
+
+            function vt(t, e) {
+                if (t === e) return !0;
+                if (!u(e) || void 0 !== t.size && void 0 !== e.size && t.size !== e.size || void 0 !== t.__hash && void 0 !== e.__hash && t.__hash !== e.__hash || a(t) !== a(e) || s(t) !== s(e) || f(t) !== f(e)) return !1;
+                if (0 === t.size && 0 === e.size) return !0;
+                var n = !c(t);
+                if (f(t)) {
+                    var r = t.entries();
+                    return e.every(function(t, e) {
+                        var i = r.next().value;
+                        return i && dt(i[1], t) && (n || dt(i[0], e))
+                    }) && r.next().done
+                }
+                var i = !1;
+                if (void 0 === t.size)
+                    if (void 0 === e.size) "function" == typeof t.cacheResult && t.cacheResult();
+                    else {
+                        i = !0;
+                        var o = t;
+                        t = e, e = o
+                    }
+                var l = !0,
+                    p = e.__iterate(function(e, r) {
+                        if (n ? !t.has(e) : i ? !dt(e, t.get(r, m)) : !dt(t.get(r, m), e)) return l = !1, !1
+                    });
+                return l && t.size === p
+            }
+
+            function yt(t, e) {
+                if (!(this instanceof yt)) return new yt(t, e);
+                if (this._value = t, this.size = void 0 === e ? 1 / 0 : Math.max(0, e), 0 === this.size) {
+                    if (Z) return Z;
+                    Z = this
+                }
+            }
+
+            function gt(t, e) {
+                if (!t) throw new Error(e)
+            }
+
+            function mt(t, e, n) {
+                if (!(this instanceof mt)) return new mt(t, e, n);
+                if (gt(0 !== n, "Cannot step a Range by 0"), t = t || 0, void 0 === e && (e = 1 / 0), n = void 0 === n ? 1 : Math.abs(n), e < t && (n = -n), this._start = t, this._end = e, this._step = n, this.size = Math.max(0, Math.ceil((e - t) / n - 1) + 1), 0 === this.size) {
+                    if (X) return X;
+                    X = this
+                }
+            }
+
+            function _t() {
+                throw TypeError("Abstract")
+            }
+
+            function bt() {}
+
+            function wt() {}
+
+            function xt() {}
+            K.prototype[Q] = !0, e(tt, $), tt.prototype.get = function(t, e) {
+                return this.has(t) ? this._array[O(this, t)] : e
+            }, tt.prototype.__iterate = function(t, e) {
+                for (var n = this._array, r = n.length - 1, i = 0; i <= r; i++)
+                    if (!1 === t(n[e ? r - i : i], i, this)) return i + 1;
+                return i
+            }, tt.prototype.__iterator = function(t, e) {
+                var n = this._array,
+                    r = n.length - 1,
+                    i = 0;
+                return new z(function() {
+                    return i > r ? {
+                        value: void 0,
+                        done: !0
+                    } : F(t, i, n[e ? r - i++ : i++])
+                })
+            }, e(et, G), et.prototype.get = function(t, e) {
+                return void 0 === e || this.has(t) ? this._object[t] : e
+            }, et.prototype.has = function(t) {
+                return this._object.hasOwnProperty(t)
+            }, et.prototype.__iterate = function(t, e) {
+                for (var n = this._object, r = this._keys, i = r.length - 1, o = 0; o <= i; o++) {
+                    var u = r[e ? i - o : o];
+                    if (!1 === t(n[u], u, this)) return o + 1
+                }
+                return o
+            }, et.prototype.__iterator = function(t, e) {
+                var n = this._object,
+                    r = this._keys,
+                    i = r.length - 1,
+                    o = 0;
+                return new z(function() {
+                    var u = r[e ? i - o : o];
+                    return o++ > i ? {
+                        value: void 0,
+                        done: !0
+                    } : F(t, u, n[u])
+                })
+            }, et.prototype[d] = !0, e(nt, $), nt.prototype.__iterateUncached = function(t, e) {
+                if (e) return this.cacheResult().__iterate(t, e);
+                var n = Y(this._iterable),
+                    r = 0;
+                if (q(n))
+                    for (var i; !(i = n.next()).done && !1 !== t(i.value, r++, this););
+                return r
+            }, nt.prototype.__iteratorUncached = function(t, e) {
+                if (e) return this.cacheResult().__iterator(t, e);
+                var n = Y(this._iterable);
+                if (!q(n)) return new z(U);
+                var r = 0;
+                return new z(function() {
+                    var e = n.next();
+                    return e.done ? e : F(t, r++, e.value)
+                })
+            }, e(rt, $), rt.prototype.__iterateUncached = function(t, e) {
+                if (e) return this.cacheResult().__iterate(t, e);
+                for (var n, r = this._iterator, i = this._iteratorCache, o = 0; o < i.length;)
+                    if (!1 === t(i[o], o++, this)) return o;
+                for (; !(n = r.next()).done;) {
+                    var u = n.value;
+                    if (i[o] = u, !1 === t(u, o++, this)) break
+                }
+                return o
+            }, rt.prototype.__iteratorUncached = function(t, e) {
+                if (e) return this.cacheResult().__iterator(t, e);
+                var n = this._iterator,
+                    r = this._iteratorCache,
+                    i = 0;
+                return new z(function() {
+                    if (i >= r.length) {
+                        var e = n.next();
+                        if (e.done) return e;
+                        r[i] = e.value
+                    }
+                    return F(t, i, r[i++])
+                })
+            }, e(yt, $), yt.prototype.toString = function() {
+                return 0 === this.size ? "Repeat []" : "Repeat [ " + this._value + " " + this.size + " times ]"
+            }, yt.prototype.get = function(t, e) {
+                return this.has(t) ? this._value : e
+            }, yt.prototype.includes = function(t) {
+                return dt(this._value, t)
+            }, yt.prototype.slice = function(t, e) {
+                var n = this.size;
+                return k(t, e, n) ? this : new yt(this._value, M(e, n) - I(t, n))
+            }, yt.prototype.reverse = function() {
+                return this
+            }, yt.prototype.indexOf = function(t) {
+                return dt(this._value, t) ? 0 : -1
+            }, yt.prototype.lastIndexOf = function(t) {
+                return dt(this._value, t) ? this.size : -1
+            }, yt.prototype.__iterate = function(t, e) {
+                for (var n = 0; n < this.size; n++)
+                    if (!1 === t(this._value, n, this)) return n + 1;
+                return n
+            }, yt.prototype.__iterator = function(t, e) {
+                var n = this,
+                    r = 0;
+                return new z(function() {
+                    return r < n.size ? F(t, r++, n._value) : {
+                        value: void 0,
+                        done: !0
+                    }
+                })
+            }, yt.prototype.equals = function(t) {
+                return t instanceof yt ? dt(this._value, t._value) : vt(t)
+            }, e(mt, $), mt.prototype.toString = function() {
+                return 0 === this.size ? "Range []" : "Range [ " + this._start + "..." + this._end + (1 !== this._step ? " by " + this._step : "") + " ]"
+            }, mt.prototype.get = function(t, e) {
+                return this.has(t) ? this._start + O(this, t) * this._step : e
+            }, mt.prototype.includes = function(t) {
+                var e = (t - this._start) / this._step;
+                return e >= 0 && e < this.size && e === Math.floor(e)
+            }, mt.prototype.slice = function(t, e) {
+                return k(t, e, this.size) ? this : (t = I(t, this.size), (e = M(e, this.size)) <= t ? new mt(0, 0) : new mt(this.get(t, this._end), this.get(e, this._end), this._step))
+            }, mt.prototype.indexOf = function(t) {
+                var e = t - this._start;
+                if (e % this._step == 0) {
+                    var n = e / this._step;
+                    if (n >= 0 && n < this.size) return n
+                }
+                return -1
+            }, mt.prototype.lastIndexOf = function(t) {
+                return this.indexOf(t)
+            }, mt.prototype.__iterate = function(t, e) {
+                for (var n = this.size - 1, r = this._step, i = e ? this._start + n * r : this._start, o = 0; o <= n; o++) {
+                    if (!1 === t(i, o, this)) return o + 1;
+                    i += e ? -r : r
+                }
+                return o
+            }, mt.prototype.__iterator = function(t, e) {
+                var n = this.size - 1,
+                    r = this._step,
+                    i = e ? this._start + n * r : this._start,
+                    o = 0;
+                return new z(function() {
+                    var u = i;
+                    return i += e ? -r : r, o > n ? {
+                        value: void 0,
+                        done: !0
+                    } : F(t, o++, u)
+                })
+            }, mt.prototype.equals = function(t) {
+                return t instanceof mt ? this._start === t._start && this._end === t._end && this._step === t._step : vt(this, t)
+            }, e(_t, n), e(bt, _t), e(wt, _t), e(xt, _t), _t.Keyed = bt, _t.Indexed = wt, _t.Set = xt;
+            var St = "function" == typeof Math.imul && -2 === Math.imul(4294967295, 2) ? Math.imul : function(t, e) {
+                var n = 65535 & (t |= 0),
+                    r = 65535 & (e |= 0);
+                return n * r + ((t >>> 16) * r + n * (e >>> 16) << 16 >>> 0) | 0
+            };
+
+            function Et(t) {
+                return t >>> 1 & 1073741824 | 3221225471 & t
+            }
+
+            function At(t) {
+                if (!1 === t || null === t || void 0 === t) return 0;
+                if ("function" == typeof t.valueOf && (!1 === (t = t.valueOf()) || null === t || void 0 === t)) return 0;
+                if (!0 === t) return 1;
+                var e = typeof t;
+                if ("number" === e) {
+                    if (t != t || t === 1 / 0) return 0;
+                    var n = 0 | t;
+                    for (n !== t && (n ^= 4294967295 * t); t > 4294967295;) n ^= t /= 4294967295;
+                    return Et(n)
+                }
+                if ("string" === e) return t.length > Pt ? function(t) {
+                    var e = Lt[t];
+                    void 0 === e && (e = Ot(t), Nt === Rt && (Nt = 0, Lt = {}), Nt++, Lt[t] = e);
+                    return e
+                }(t) : Ot(t);
+                if ("function" == typeof t.hashCode) return t.hashCode();
+                if ("object" === e) return function(t) {
+                    var e;
+                    if (Mt && void 0 !== (e = It.get(t))) return e;
+                    if (void 0 !== (e = t[Ct])) return e;
+                    if (!kt) {
+                        if (void 0 !== (e = t.propertyIsEnumerable && t.propertyIsEnumerable[Ct])) return e;
+                        if (void 0 !== (e = function(t) {
+                            if (t && t.nodeType > 0) switch (t.nodeType) {
+                                case 1:
+                                    return t.uniqueID;
+                                case 9:
+                                    return t.documentElement && t.documentElement.uniqueID
+                            }
+                        }(t))) return e
+                    }
+                    e = ++Tt, 1073741824 & Tt && (Tt = 0);
+                    if (Mt) It.set(t, e);
+                    else {
+                        if (void 0 !== jt && !1 === jt(t)) throw new Error("Non-extensible objects are not allowed as keys.");
+                        if (kt) Object.defineProperty(t, Ct, {
+                            enumerable: !1,
+                            configurable: !1,
+                            writable: !1,
+                            value: e
+                        });
+                        else if (void 0 !== t.propertyIsEnumerable && t.propertyIsEnumerable === t.constructor.prototype.propertyIsEnumerable) t.propertyIsEnumerable = function() {
+                            return this.constructor.prototype.propertyIsEnumerable.apply(this, arguments)
+                        }, t.propertyIsEnumerable[Ct] = e;
+                        else {
+                            if (void 0 === t.nodeType) throw new Error("Unable to set a non-enumerable property on object.");
+                            t[Ct] = e
+                        }
+                    }
+                    return e
+                }(t);
+                if ("function" == typeof t.toString) return Ot(t.toString());
+                throw new Error("Value type " + e + " cannot be hashed.")
+            }
+
+            function Ot(t) {
+                for (var e = 0, n = 0; n < t.length; n++) e = 31 * e + t.charCodeAt(n) | 0;
+                return Et(e)
+            }
+            var jt = Object.isExtensible,
+                kt = function() {
+                    try {
+                        return Object.defineProperty({}, "@", {}), !0
+                    } catch (t) {
+                        return !1
+                    }
+                }();
+            var It, Mt = "function" == typeof WeakMap;
+            Mt && (It = new WeakMap);
+            var Tt = 0,
+                Ct = "__immutablehash__";
+            "function" == typeof Symbol && (Ct = Symbol(Ct));
+            var Pt = 16,
+                Rt = 255,
+                Nt = 0,
+                Lt = {};
+
+            function Dt(t) {
+                gt(t !== 1 / 0, "Cannot perform this action with an infinite size.")
+            }
+
+            function zt(t) {
+                return null === t || void 0 === t ? Xt() : Ft(t) && !f(t) ? t : Xt().withMutations(function(e) {
+                    var n = r(t);
+                    Dt(n.size), n.forEach(function(t, n) {
+                        return e.set(n, t)
+                    })
+                })
+            }
+
+            function Ft(t) {
+                return !(!t || !t[Bt])
+            }
+            e(zt, bt), zt.of = function() {
+                var e = t.call(arguments, 0);
+                return Xt().withMutations(function(t) {
+                    for (var n = 0; n < e.length; n += 2) {
+                        if (n + 1 >= e.length) throw new Error("Missing value for key: " + e[n]);
+                        t.set(e[n], e[n + 1])
+                    }
+                })
+            }, zt.prototype.toString = function() {
+                return this.__toString("Map {", "}")
+            }, zt.prototype.get = function(t, e) {
+                return this._root ? this._root.get(0, void 0, t, e) : e
+            }, zt.prototype.set = function(t, e) {
+                return Qt(this, t, e)
+            }, zt.prototype.setIn = function(t, e) {
+                return this.updateIn(t, m, function() {
+                    return e
+                })
+            }, zt.prototype.remove = function(t) {
+                return Qt(this, t, m)
+            }, zt.prototype.deleteIn = function(t) {
+                return this.updateIn(t, function() {
+                    return m
+                })
+            }, zt.prototype.update = function(t, e, n) {
+                return 1 === arguments.length ? t(this) : this.updateIn([t], e, n)
+            }, zt.prototype.updateIn = function(t, e, n) {
+                n || (n = e, e = void 0);
+                var r = function t(e, n, r, i) {
+                    var o = e === m;
+                    var u = n.next();
+                    if (u.done) {
+                        var a = o ? r : e,
+                            s = i(a);
+                        return s === a ? e : s
+                    }
+                    gt(o || e && e.set, "invalid keyPath");
+                    var c = u.value;
+                    var f = o ? m : e.get(c, m);
+                    var l = t(f, n, r, i);
+                    return l === f ? e : l === m ? e.remove(c) : (o ? Xt() : e).set(c, l)
+                }(this, nn(t), e, n);
+                return r === m ? void 0 : r
+            }, zt.prototype.clear = function() {
+                return 0 === this.size ? this : this.__ownerID ? (this.size = 0, this._root = null, this.__hash = void 0, this.__altered = !0, this) : Xt()
+            }, zt.prototype.merge = function() {
+                return re(this, void 0, arguments)
+            }, zt.prototype.mergeWith = function(e) {
+                return re(this, e, t.call(arguments, 1))
+            }, zt.prototype.mergeIn = function(e) {
+                var n = t.call(arguments, 1);
+                return this.updateIn(e, Xt(), function(t) {
+                    return "function" == typeof t.merge ? t.merge.apply(t, n) : n[n.length - 1]
+                })
+            }, zt.prototype.mergeDeep = function() {
+                return re(this, ie, arguments)
+            }, zt.prototype.mergeDeepWith = function(e) {
+                var n = t.call(arguments, 1);
+                return re(this, oe(e), n)
+            }, zt.prototype.mergeDeepIn = function(e) {
+                var n = t.call(arguments, 1);
+                return this.updateIn(e, Xt(), function(t) {
+                    return "function" == typeof t.mergeDeep ? t.mergeDeep.apply(t, n) : n[n.length - 1]
+                })
+            }, zt.prototype.sort = function(t) {
+                return Ie(Ve(this, t))
+            }, zt.prototype.sortBy = function(t, e) {
+                return Ie(Ve(this, e, t))
+            }, zt.prototype.withMutations = function(t) {
+                var e = this.asMutable();
+                return t(e), e.wasAltered() ? e.__ensureOwner(this.__ownerID) : this
+            }, zt.prototype.asMutable = function() {
+                return this.__ownerID ? this : this.__ensureOwner(new S)
+            }, zt.prototype.asImmutable = function() {
+                return this.__ensureOwner()
+            }, zt.prototype.wasAltered = function() {
+                return this.__altered
+            }, zt.prototype.__iterator = function(t, e) {
+                return new $t(this, t, e)
+            }, zt.prototype.__iterate = function(t, e) {
+                var n = this,
+                    r = 0;
+                return this._root && this._root.iterate(function(e) {
+                    return r++, t(e[1], e[0], n)
+                }, e), r
+            }, zt.prototype.__ensureOwner = function(t) {
+                return t === this.__ownerID ? this : t ? Zt(this.size, this._root, t, this.__hash) : (this.__ownerID = t, this.__altered = !1, this)
+            }, zt.isMap = Ft;
+            var Ut, Bt = "@@__IMMUTABLE_MAP__@@",
+                qt = zt.prototype;
+
+            function Yt(t, e) {
+                this.ownerID = t, this.entries = e
+            }
+
+            function Wt(t, e, n) {
+                this.ownerID = t, this.bitmap = e, this.nodes = n
+            }
+
+            function Vt(t, e, n) {
+                this.ownerID = t, this.count = e, this.nodes = n
+            }
+
+            function Kt(t, e, n) {
+                this.ownerID = t, this.keyHash = e, this.entries = n
+            }
+
+            function Gt(t, e, n) {
+                this.ownerID = t, this.keyHash = e, this.entry = n
+            }
+
+            function $t(t, e, n) {
+                this._type = e, this._reverse = n, this._stack = t._root && Jt(t._root)
+            }
+
+            function Ht(t, e) {
+                return F(t, e[0], e[1])
+            }
+
+            function Jt(t, e) {
+                return {
+                    node: t,
+                    index: 0,
+                    __prev: e
+                }
+            }
+
+            function Zt(t, e, n, r) {
+                var i = Object.create(qt);
+                return i.size = t, i._root = e, i.__ownerID = n, i.__hash = r, i.__altered = !1, i
+            }
+
+            function Xt() {
+                return Ut || (Ut = Zt(0))
+            }
+
+            function Qt(t, e, n) {
+                var r, i;
+                if (t._root) {
+                    var o = w(_),
+                        u = w(b);
+                    if (r = te(t._root, t.__ownerID, 0, void 0, e, n, o, u), !u.value) return t;
+                    i = t.size + (o.value ? n === m ? -1 : 1 : 0)
+                } else {
+                    if (n === m) return t;
+                    i = 1, r = new Yt(t.__ownerID, [
+                        [e, n]
+                    ])
+                }
+                return t.__ownerID ? (t.size = i, t._root = r, t.__hash = void 0, t.__altered = !0, t) : r ? Zt(i, r) : Xt()
+            }
+
+            function te(t, e, n, r, i, o, u, a) {
+                return t ? t.update(e, n, r, i, o, u, a) : o === m ? t : (x(a), x(u), new Gt(e, r, [i, o]))
+            }
+
+            function ee(t) {
+                return t.constructor === Gt || t.constructor === Kt
+            }
+
+            function ne(t, e, n, r, i) {
+                if (t.keyHash === r) return new Kt(e, r, [t.entry, i]);
+                var o, u = (0 === n ? t.keyHash : t.keyHash >>> n) & g,
+                    a = (0 === n ? r : r >>> n) & g;
+                return new Wt(e, 1 << u | 1 << a, u === a ? [ne(t, e, n + v, r, i)] : (o = new Gt(e, r, i), u < a ? [t, o] : [o, t]))
+            }
+
+            function re(t, e, n) {
+                for (var i = [], o = 0; o < n.length; o++) {
+                    var a = n[o],
+                        s = r(a);
+                    u(a) || (s = s.map(function(t) {
+                        return lt(t)
+                    })), i.push(s)
+                }
+                return ue(t, e, i)
+            }
+
+            function ie(t, e, n) {
+                return t && t.mergeDeep && u(e) ? t.mergeDeep(e) : dt(t, e) ? t : e
+            }
+
+            function oe(t) {
+                return function(e, n, r) {
+                    if (e && e.mergeDeepWith && u(n)) return e.mergeDeepWith(t, n);
+                    var i = t(e, n, r);
+                    return dt(e, i) ? e : i
+                }
+            }
+
+            function ue(t, e, n) {
+                return 0 === (n = n.filter(function(t) {
+                    return 0 !== t.size
+                })).length ? t : 0 !== t.size || t.__ownerID || 1 !== n.length ? t.withMutations(function(t) {
+                    for (var r = e ? function(n, r) {
+                        t.update(r, m, function(t) {
+                            return t === m ? n : e(t, n, r)
+                        })
+                    } : function(e, n) {
+                        t.set(n, e)
+                    }, i = 0; i < n.length; i++) n[i].forEach(r)
+                }) : t.constructor(n[0])
+            }
+
+            function ae(t) {
+                return t = (t = (858993459 & (t -= t >> 1 & 1431655765)) + (t >> 2 & 858993459)) + (t >> 4) & 252645135, t += t >> 8, 127 & (t += t >> 16)
+            }
+
+            function se(t, e, n, r) {
+                var i = r ? t : E(t);
+                return i[e] = n, i
+            }
+            qt[Bt] = !0, qt.delete = qt.remove, qt.removeIn = qt.deleteIn, Yt.prototype.get = function(t, e, n, r) {
+                for (var i = this.entries, o = 0, u = i.length; o < u; o++)
+                    if (dt(n, i[o][0])) return i[o][1];
+                return r
+            }, Yt.prototype.update = function(t, e, n, r, i, o, u) {
+                for (var a = i === m, s = this.entries, c = 0, f = s.length; c < f && !dt(r, s[c][0]); c++);
+                var l = c < f;
+                if (l ? s[c][1] === i : a) return this;
+                if (x(u), (a || !l) && x(o), !a || 1 !== s.length) {
+                    if (!l && !a && s.length >= ce) return function(t, e, n, r) {
+                        t || (t = new S);
+                        for (var i = new Gt(t, At(n), [n, r]), o = 0; o < e.length; o++) {
+                            var u = e[o];
+                            i = i.update(t, 0, void 0, u[0], u[1])
+                        }
+                        return i
+                    }(t, s, r, i);
+                    var p = t && t === this.ownerID,
+                        h = p ? s : E(s);
+                    return l ? a ? c === f - 1 ? h.pop() : h[c] = h.pop() : h[c] = [r, i] : h.push([r, i]), p ? (this.entries = h, this) : new Yt(t, h)
+                }
+            }, Wt.prototype.get = function(t, e, n, r) {
+                void 0 === e && (e = At(n));
+                var i = 1 << ((0 === t ? e : e >>> t) & g),
+                    o = this.bitmap;
+                return 0 == (o & i) ? r : this.nodes[ae(o & i - 1)].get(t + v, e, n, r)
+            }, Wt.prototype.update = function(t, e, n, r, i, o, u) {
+                void 0 === n && (n = At(r));
+                var a = (0 === e ? n : n >>> e) & g,
+                    s = 1 << a,
+                    c = this.bitmap,
+                    f = 0 != (c & s);
+                if (!f && i === m) return this;
+                var l = ae(c & s - 1),
+                    p = this.nodes,
+                    h = f ? p[l] : void 0,
+                    d = te(h, t, e + v, n, r, i, o, u);
+                if (d === h) return this;
+                if (!f && d && p.length >= fe) return function(t, e, n, r, i) {
+                    for (var o = 0, u = new Array(y), a = 0; 0 !== n; a++, n >>>= 1) u[a] = 1 & n ? e[o++] : void 0;
+                    return u[r] = i, new Vt(t, o + 1, u)
+                }(t, p, c, a, d);
+                if (f && !d && 2 === p.length && ee(p[1 ^ l])) return p[1 ^ l];
+                if (f && d && 1 === p.length && ee(d)) return d;
+                var _ = t && t === this.ownerID,
+                    b = f ? d ? c : c ^ s : c | s,
+                    w = f ? d ? se(p, l, d, _) : function(t, e, n) {
+                        var r = t.length - 1;
+                        if (n && e === r) return t.pop(), t;
+                        for (var i = new Array(r), o = 0, u = 0; u < r; u++) u === e && (o = 1), i[u] = t[u + o];
+                        return i
+                    }(p, l, _) : function(t, e, n, r) {
+                        var i = t.length + 1;
+                        if (r && e + 1 === i) return t[e] = n, t;
+                        for (var o = new Array(i), u = 0, a = 0; a < i; a++) a === e ? (o[a] = n, u = -1) : o[a] = t[a + u];
+                        return o
+                    }(p, l, d, _);
+                return _ ? (this.bitmap = b, this.nodes = w, this) : new Wt(t, b, w)
+            }, Vt.prototype.get = function(t, e, n, r) {
+                void 0 === e && (e = At(n));
+                var i = (0 === t ? e : e >>> t) & g,
+                    o = this.nodes[i];
+                return o ? o.get(t + v, e, n, r) : r
+            }, Vt.prototype.update = function(t, e, n, r, i, o, u) {
+                void 0 === n && (n = At(r));
+                var a = (0 === e ? n : n >>> e) & g,
+                    s = i === m,
+                    c = this.nodes,
+                    f = c[a];
+                if (s && !f) return this;
+                var l = te(f, t, e + v, n, r, i, o, u);
+                if (l === f) return this;
+                var p = this.count;
+                if (f) {
+                    if (!l && --p < le) return function(t, e, n, r) {
+                        for (var i = 0, o = 0, u = new Array(n), a = 0, s = 1, c = e.length; a < c; a++, s <<= 1) {
+                            var f = e[a];
+                            void 0 !== f && a !== r && (i |= s, u[o++] = f)
+                        }
+                        return new Wt(t, i, u)
+                    }(t, c, p, a)
+                } else p++;
+                var h = t && t === this.ownerID,
+                    d = se(c, a, l, h);
+                return h ? (this.count = p, this.nodes = d, this) : new Vt(t, p, d)
+            }, Kt.prototype.get = function(t, e, n, r) {
+                for (var i = this.entries, o = 0, u = i.length; o < u; o++)
+                    if (dt(n, i[o][0])) return i[o][1];
+                return r
+            }, Kt.prototype.update = function(t, e, n, r, i, o, u) {
+                void 0 === n && (n = At(r));
+                var a = i === m;
+                if (n !== this.keyHash) return a ? this : (x(u), x(o), ne(this, t, e, n, [r, i]));
+                for (var s = this.entries, c = 0, f = s.length; c < f && !dt(r, s[c][0]); c++);
+                var l = c < f;
+                if (l ? s[c][1] === i : a) return this;
+                if (x(u), (a || !l) && x(o), a && 2 === f) return new Gt(t, this.keyHash, s[1 ^ c]);
+                var p = t && t === this.ownerID,
+                    h = p ? s : E(s);
+                return l ? a ? c === f - 1 ? h.pop() : h[c] = h.pop() : h[c] = [r, i] : h.push([r, i]), p ? (this.entries = h, this) : new Kt(t, this.keyHash, h)
+            }, Gt.prototype.get = function(t, e, n, r) {
+                return dt(n, this.entry[0]) ? this.entry[1] : r
+            }, Gt.prototype.update = function(t, e, n, r, i, o, u) {
+                var a = i === m,
+                    s = dt(r, this.entry[0]);
+                return (s ? i === this.entry[1] : a) ? this : (x(u), a ? void x(o) : s ? t && t === this.ownerID ? (this.entry[1] = i, this) : new Gt(t, this.keyHash, [r, i]) : (x(o), ne(this, t, e, At(r), [r, i])))
+            }, Yt.prototype.iterate = Kt.prototype.iterate = function(t, e) {
+                for (var n = this.entries, r = 0, i = n.length - 1; r <= i; r++)
+                    if (!1 === t(n[e ? i - r : r])) return !1
+            }, Wt.prototype.iterate = Vt.prototype.iterate = function(t, e) {
+                for (var n = this.nodes, r = 0, i = n.length - 1; r <= i; r++) {
+                    var o = n[e ? i - r : r];
+                    if (o && !1 === o.iterate(t, e)) return !1
+                }
+            }, Gt.prototype.iterate = function(t, e) {
+                return t(this.entry)
+            }, e($t, z), $t.prototype.next = function() {
+                for (var t = this._type, e = this._stack; e;) {
+                    var n, r = e.node,
+                        i = e.index++;
+                    if (r.entry) {
+                        if (0 === i) return Ht(t, r.entry)
+                    } else if (r.entries) {
+                        if (i <= (n = r.entries.length - 1)) return Ht(t, r.entries[this._reverse ? n - i : i])
+                    } else if (i <= (n = r.nodes.length - 1)) {
+                        var o = r.nodes[this._reverse ? n - i : i];
+                        if (o) {
+                            if (o.entry) return Ht(t, o.entry);
+                            e = this._stack = Jt(o, e)
+                        }
+                        continue
+                    }
+                    e = this._stack = this._stack.__prev
+                }
+                return {
+                    value: void 0,
+                    done: !0
+                }
+            };
+            var ce = y / 4,
+                fe = y / 2,
+                le = y / 4;
+
+            function pe(t) {
+                var e = xe();
+                if (null === t || void 0 === t) return e;
+                if (he(t)) return t;
+                var n = i(t),
+                    r = n.size;
+                return 0 === r ? e : (Dt(r), r > 0 && r < y ? we(0, r, v, null, new ye(n.toArray())) : e.withMutations(function(t) {
+                    t.setSize(r), n.forEach(function(e, n) {
+                        return t.set(n, e)
+                    })
+                }))
+            }
+
+            function he(t) {
+                return !(!t || !t[de])
+            }
+            e(pe, wt), pe.of = function() {
+                return this(arguments)
+            }, pe.prototype.toString = function() {
+                return this.__toString("List [", "]")
+            }, pe.prototype.get = function(t, e) {
+                if ((t = O(this, t)) >= 0 && t < this.size) {
+                    var n = Ae(this, t += this._origin);
+                    return n && n.array[t & g]
+                }
+                return e
+            }, pe.prototype.set = function(t, e) {
+                return function(t, e, n) {
+                    if ((e = O(t, e)) != e) return t;
+                    if (e >= t.size || e < 0) return t.withMutations(function(t) {
+                        e < 0 ? Oe(t, e).set(0, n) : Oe(t, 0, e + 1).set(e, n)
+                    });
+                    e += t._origin;
+                    var r = t._tail,
+                        i = t._root,
+                        o = w(b);
+                    e >= ke(t._capacity) ? r = Se(r, t.__ownerID, 0, e, n, o) : i = Se(i, t.__ownerID, t._level, e, n, o);
+                    if (!o.value) return t;
+                    if (t.__ownerID) return t._root = i, t._tail = r, t.__hash = void 0, t.__altered = !0, t;
+                    return we(t._origin, t._capacity, t._level, i, r)
+                }(this, t, e)
+            }, pe.prototype.remove = function(t) {
+                return this.has(t) ? 0 === t ? this.shift() : t === this.size - 1 ? this.pop() : this.splice(t, 1) : this
+            }, pe.prototype.insert = function(t, e) {
+                return this.splice(t, 0, e)
+            }, pe.prototype.clear = function() {
+                return 0 === this.size ? this : this.__ownerID ? (this.size = this._origin = this._capacity = 0, this._level = v, this._root = this._tail = null, this.__hash = void 0, this.__altered = !0, this) : xe()
+            }, pe.prototype.push = function() {
+                var t = arguments,
+                    e = this.size;
+                return this.withMutations(function(n) {
+                    Oe(n, 0, e + t.length);
+                    for (var r = 0; r < t.length; r++) n.set(e + r, t[r])
+                })
+            }, pe.prototype.pop = function() {
+                return Oe(this, 0, -1)
+            }, pe.prototype.unshift = function() {
+                var t = arguments;
+                return this.withMutations(function(e) {
+                    Oe(e, -t.length);
+                    for (var n = 0; n < t.length; n++) e.set(n, t[n])
+                })
+            }, pe.prototype.shift = function() {
+                return Oe(this, 1)
+            }, pe.prototype.merge = function() {
+                return je(this, void 0, arguments)
+            }, pe.prototype.mergeWith = function(e) {
+                return je(this, e, t.call(arguments, 1))
+            }, pe.prototype.mergeDeep = function() {
+                return je(this, ie, arguments)
+            }, pe.prototype.mergeDeepWith = function(e) {
+                var n = t.call(arguments, 1);
+                return je(this, oe(e), n)
+            }, pe.prototype.setSize = function(t) {
+                return Oe(this, 0, t)
+            }, pe.prototype.slice = function(t, e) {
+                var n = this.size;
+                return k(t, e, n) ? this : Oe(this, I(t, n), M(e, n))
+            }, pe.prototype.__iterator = function(t, e) {
+                var n = 0,
+                    r = be(this, e);
+                return new z(function() {
+                    var e = r();
+                    return e === _e ? {
+                        value: void 0,
+                        done: !0
+                    } : F(t, n++, e)
+                })
+            }, pe.prototype.__iterate = function(t, e) {
+                for (var n, r = 0, i = be(this, e);
+                     (n = i()) !== _e && !1 !== t(n, r++, this););
+                return r
+            }, pe.prototype.__ensureOwner = function(t) {
+                return t === this.__ownerID ? this : t ? we(this._origin, this._capacity, this._level, this._root, this._tail, t, this.__hash) : (this.__ownerID = t, this)
+            }, pe.isList = he;
+            var de = "@@__IMMUTABLE_LIST__@@",
+                ve = pe.prototype;
+
+            function ye(t, e) {
+                this.array = t, this.ownerID = e
+            }
+            ve[de] = !0, ve.delete = ve.remove, ve.setIn = qt.setIn, ve.deleteIn = ve.removeIn = qt.removeIn, ve.update = qt.update, ve.updateIn = qt.updateIn, ve.mergeIn = qt.mergeIn, ve.mergeDeepIn = qt.mergeDeepIn, ve.withMutations = qt.withMutations, ve.asMutable = qt.asMutable, ve.asImmutable = qt.asImmutable, ve.wasAltered = qt.wasAltered, ye.prototype.removeBefore = function(t, e, n) {
+                if (n === e ? 1 << e : 0 === this.array.length) return this;
+                var r = n >>> e & g;
+                if (r >= this.array.length) return new ye([], t);
+                var i, o = 0 === r;
+                if (e > 0) {
+                    var u = this.array[r];
+                    if ((i = u && u.removeBefore(t, e - v, n)) === u && o) return this
+                }
+                if (o && !i) return this;
+                var a = Ee(this, t);
+                if (!o)
+                    for (var s = 0; s < r; s++) a.array[s] = void 0;
+                return i && (a.array[r] = i), a
+            }, ye.prototype.removeAfter = function(t, e, n) {
+                if (n === (e ? 1 << e : 0) || 0 === this.array.length) return this;
+                var r, i = n - 1 >>> e & g;
+                if (i >= this.array.length) return this;
+                if (e > 0) {
+                    var o = this.array[i];
+                    if ((r = o && o.removeAfter(t, e - v, n)) === o && i === this.array.length - 1) return this
+                }
+                var u = Ee(this, t);
+                return u.array.splice(i + 1), r && (u.array[i] = r), u
+            };
+            var ge, me, _e = {};
+
+            function be(t, e) {
+                var n = t._origin,
+                    r = t._capacity,
+                    i = ke(r),
+                    o = t._tail;
+                return u(t._root, t._level, 0);
+
+                function u(t, a, s) {
+                    return 0 === a ? function(t, u) {
+                        var a = u === i ? o && o.array : t && t.array,
+                            s = u > n ? 0 : n - u,
+                            c = r - u;
+                        c > y && (c = y);
+                        return function() {
+                            if (s === c) return _e;
+                            var t = e ? --c : s++;
+                            return a && a[t]
+                        }
+                    }(t, s) : function(t, i, o) {
+                        var a, s = t && t.array,
+                            c = o > n ? 0 : n - o >> i,
+                            f = 1 + (r - o >> i);
+                        f > y && (f = y);
+                        return function() {
+                            for (;;) {
+                                if (a) {
+                                    var t = a();
+                                    if (t !== _e) return t;
+                                    a = null
+                                }
+                                if (c === f) return _e;
+                                var n = e ? --f : c++;
+                                a = u(s && s[n], i - v, o + (n << i))
+                            }
+                        }
+                    }(t, a, s)
+                }
+            }
+
+            function we(t, e, n, r, i, o, u) {
+                var a = Object.create(ve);
+                return a.size = e - t, a._origin = t, a._capacity = e, a._level = n, a._root = r, a._tail = i, a.__ownerID = o, a.__hash = u, a.__altered = !1, a
+            }
+
+            function xe() {
+                return ge || (ge = we(0, 0, v))
+            }
+
+            function Se(t, e, n, r, i, o) {
+                var u, a = r >>> n & g,
+                    s = t && a < t.array.length;
+                if (!s && void 0 === i) return t;
+                if (n > 0) {
+                    var c = t && t.array[a],
+                        f = Se(c, e, n - v, r, i, o);
+                    return f === c ? t : ((u = Ee(t, e)).array[a] = f, u)
+                }
+                return s && t.array[a] === i ? t : (x(o), u = Ee(t, e), void 0 === i && a === u.array.length - 1 ? u.array.pop() : u.array[a] = i, u)
+            }
+
+            function Ee(t, e) {
+                return e && t && e === t.ownerID ? t : new ye(t ? t.array.slice() : [], e)
+            }
+
+            function Ae(t, e) {
+                if (e >= ke(t._capacity)) return t._tail;
+                if (e < 1 << t._level + v) {
+                    for (var n = t._root, r = t._level; n && r > 0;) n = n.array[e >>> r & g], r -= v;
+                    return n
+                }
+            }
+
+            function Oe(t, e, n) {
+                void 0 !== e && (e |= 0), void 0 !== n && (n |= 0);
+                var r = t.__ownerID || new S,
+                    i = t._origin,
+                    o = t._capacity,
+                    u = i + e,
+                    a = void 0 === n ? o : n < 0 ? o + n : i + n;
+                if (u === i && a === o) return t;
+                if (u >= a) return t.clear();
+                for (var s = t._level, c = t._root, f = 0; u + f < 0;) c = new ye(c && c.array.length ? [void 0, c] : [], r), f += 1 << (s += v);
+                f && (u += f, i += f, a += f, o += f);
+                for (var l = ke(o), p = ke(a); p >= 1 << s + v;) c = new ye(c && c.array.length ? [c] : [], r), s += v;
+                var h = t._tail,
+                    d = p < l ? Ae(t, a - 1) : p > l ? new ye([], r) : h;
+                if (h && p > l && u < o && h.array.length) {
+                    for (var y = c = Ee(c, r), m = s; m > v; m -= v) {
+                        var _ = l >>> m & g;
+                        y = y.array[_] = Ee(y.array[_], r)
+                    }
+                    y.array[l >>> v & g] = h
+                }
+                if (a < o && (d = d && d.removeAfter(r, 0, a)), u >= p) u -= p, a -= p, s = v, c = null, d = d && d.removeBefore(r, 0, u);
+                else if (u > i || p < l) {
+                    for (f = 0; c;) {
+                        var b = u >>> s & g;
+                        if (b !== p >>> s & g) break;
+                        b && (f += (1 << s) * b), s -= v, c = c.array[b]
+                    }
+                    c && u > i && (c = c.removeBefore(r, s, u - f)), c && p < l && (c = c.removeAfter(r, s, p - f)), f && (u -= f, a -= f)
+                }
+                return t.__ownerID ? (t.size = a - u, t._origin = u, t._capacity = a, t._level = s, t._root = c, t._tail = d, t.__hash = void 0, t.__altered = !0, t) : we(u, a, s, c, d)
+            }
+
+            function je(t, e, n) {
+                for (var r = [], o = 0, a = 0; a < n.length; a++) {
+                    var s = n[a],
+                        c = i(s);
+                    c.size > o && (o = c.size), u(s) || (c = c.map(function(t) {
+                        return lt(t)
+                    })), r.push(c)
+                }
+                return o > t.size && (t = t.setSize(o)), ue(t, e, r)
+            }
+
+            function ke(t) {
+                return t < y ? 0 : t - 1 >>> v << v
+            }
+
+            function Ie(t) {
+                return null === t || void 0 === t ? Ce() : Me(t) ? t : Ce().withMutations(function(e) {
+                    var n = r(t);
+                    Dt(n.size), n.forEach(function(t, n) {
+                        return e.set(n, t)
+                    })
+                })
+            }
+
+            function Me(t) {
+                return Ft(t) && f(t)
+            }
+
+            function Te(t, e, n, r) {
+                var i = Object.create(Ie.prototype);
+                return i.size = t ? t.size : 0, i._map = t, i._list = e, i.__ownerID = n, i.__hash = r, i
+            }
+
+            function Ce() {
+                return me || (me = Te(Xt(), xe()))
+            }
+
+            function Pe(t, e, n) {
+                var r, i, o = t._map,
+                    u = t._list,
+                    a = o.get(e),
+                    s = void 0 !== a;
+                if (n === m) {
+                    if (!s) return t;
+                    u.size >= y && u.size >= 2 * o.size ? (r = (i = u.filter(function(t, e) {
+                        return void 0 !== t && a !== e
+                    })).toKeyedSeq().map(function(t) {
+                        return t[0]
+                    }).flip().toMap(), t.__ownerID && (r.__ownerID = i.__ownerID = t.__ownerID)) : (r = o.remove(e), i = a === u.size - 1 ? u.pop() : u.set(a, void 0))
+                } else if (s) {
+                    if (n === u.get(a)[1]) return t;
+                    r = o, i = u.set(a, [e, n])
+                } else r = o.set(e, u.size), i = u.set(u.size, [e, n]);
+                return t.__ownerID ? (t.size = r.size, t._map = r, t._list = i, t.__hash = void 0, t) : Te(r, i)
+            }
+
+            function Re(t, e) {
+                this._iter = t, this._useKeys = e, this.size = t.size
+            }
+
+            function Ne(t) {
+                this._iter = t, this.size = t.size
+            }
+
+            function Le(t) {
+                this._iter = t, this.size = t.size
+            }
+
+            function De(t) {
+                this._iter = t, this.size = t.size
+            }
+
+            function ze(t) {
+                var e = Qe(t);
+                return e._iter = t, e.size = t.size, e.flip = function() {
+                    return t
+                }, e.reverse = function() {
+                    var e = t.reverse.apply(this);
+                    return e.flip = function() {
+                        return t.reverse()
+                    }, e
+                }, e.has = function(e) {
+                    return t.includes(e)
+                }, e.includes = function(e) {
+                    return t.has(e)
+                }, e.cacheResult = tn, e.__iterateUncached = function(e, n) {
+                    var r = this;
+                    return t.__iterate(function(t, n) {
+                        return !1 !== e(n, t, r)
+                    }, n)
+                }, e.__iteratorUncached = function(e, n) {
+                    if (e === R) {
+                        var r = t.__iterator(e, n);
+                        return new z(function() {
+                            var t = r.next();
+                            if (!t.done) {
+                                var e = t.value[0];
+                                t.value[0] = t.value[1], t.value[1] = e
+                            }
+                            return t
+                        })
+                    }
+                    return t.__iterator(e === P ? C : P, n)
+                }, e
+            }
+
+            function Fe(t, e, n) {
+                var r = Qe(t);
+                return r.size = t.size, r.has = function(e) {
+                    return t.has(e)
+                }, r.get = function(r, i) {
+                    var o = t.get(r, m);
+                    return o === m ? i : e.call(n, o, r, t)
+                }, r.__iterateUncached = function(r, i) {
+                    var o = this;
+                    return t.__iterate(function(t, i, u) {
+                        return !1 !== r(e.call(n, t, i, u), i, o)
+                    }, i)
+                }, r.__iteratorUncached = function(r, i) {
+                    var o = t.__iterator(R, i);
+                    return new z(function() {
+                        var i = o.next();
+                        if (i.done) return i;
+                        var u = i.value,
+                            a = u[0];
+                        return F(r, a, e.call(n, u[1], a, t), i)
+                    })
+                }, r
+            }
+
+            function Ue(t, e) {
+                var n = Qe(t);
+                return n._iter = t, n.size = t.size, n.reverse = function() {
+                    return t
+                }, t.flip && (n.flip = function() {
+                    var e = ze(t);
+                    return e.reverse = function() {
+                        return t.flip()
+                    }, e
+                }), n.get = function(n, r) {
+                    return t.get(e ? n : -1 - n, r)
+                }, n.has = function(n) {
+                    return t.has(e ? n : -1 - n)
+                }, n.includes = function(e) {
+                    return t.includes(e)
+                }, n.cacheResult = tn, n.__iterate = function(e, n) {
+                    var r = this;
+                    return t.__iterate(function(t, n) {
+                        return e(t, n, r)
+                    }, !n)
+                }, n.__iterator = function(e, n) {
+                    return t.__iterator(e, !n)
+                }, n
+            }
+
+            function Be(t, e, n, r) {
+                var i = Qe(t);
+                return r && (i.has = function(r) {
+                    var i = t.get(r, m);
+                    return i !== m && !!e.call(n, i, r, t)
+                }, i.get = function(r, i) {
+                    var o = t.get(r, m);
+                    return o !== m && e.call(n, o, r, t) ? o : i
+                }), i.__iterateUncached = function(i, o) {
+                    var u = this,
+                        a = 0;
+                    return t.__iterate(function(t, o, s) {
+                        if (e.call(n, t, o, s)) return a++, i(t, r ? o : a - 1, u)
+                    }, o), a
+                }, i.__iteratorUncached = function(i, o) {
+                    var u = t.__iterator(R, o),
+                        a = 0;
+                    return new z(function() {
+                        for (;;) {
+                            var o = u.next();
+                            if (o.done) return o;
+                            var s = o.value,
+                                c = s[0],
+                                f = s[1];
+                            if (e.call(n, f, c, t)) return F(i, r ? c : a++, f, o)
+                        }
+                    })
+                }, i
+            }
+
+            function qe(t, e, n, r) {
+                var i = t.size;
+                if (void 0 !== e && (e |= 0), void 0 !== n && (n === 1 / 0 ? n = i : n |= 0), k(e, n, i)) return t;
+                var o = I(e, i),
+                    u = M(n, i);
+                if (o != o || u != u) return qe(t.toSeq().cacheResult(), e, n, r);
+                var a, s = u - o;
+                s == s && (a = s < 0 ? 0 : s);
+                var c = Qe(t);
+                return c.size = 0 === a ? a : t.size && a || void 0, !r && it(t) && a >= 0 && (c.get = function(e, n) {
+                    return (e = O(this, e)) >= 0 && e < a ? t.get(e + o, n) : n
+                }), c.__iterateUncached = function(e, n) {
+                    var i = this;
+                    if (0 === a) return 0;
+                    if (n) return this.cacheResult().__iterate(e, n);
+                    var u = 0,
+                        s = !0,
+                        c = 0;
+                    return t.__iterate(function(t, n) {
+                        if (!s || !(s = u++ < o)) return c++, !1 !== e(t, r ? n : c - 1, i) && c !== a
+                    }), c
+                }, c.__iteratorUncached = function(e, n) {
+                    if (0 !== a && n) return this.cacheResult().__iterator(e, n);
+                    var i = 0 !== a && t.__iterator(e, n),
+                        u = 0,
+                        s = 0;
+                    return new z(function() {
+                        for (; u++ < o;) i.next();
+                        if (++s > a) return {
+                            value: void 0,
+                            done: !0
+                        };
+                        var t = i.next();
+                        return r || e === P ? t : F(e, s - 1, e === C ? void 0 : t.value[1], t)
+                    })
+                }, c
+            }
+
+            function Ye(t, e, n, r) {
+                var i = Qe(t);
+                return i.__iterateUncached = function(i, o) {
+                    var u = this;
+                    if (o) return this.cacheResult().__iterate(i, o);
+                    var a = !0,
+                        s = 0;
+                    return t.__iterate(function(t, o, c) {
+                        if (!a || !(a = e.call(n, t, o, c))) return s++, i(t, r ? o : s - 1, u)
+                    }), s
+                }, i.__iteratorUncached = function(i, o) {
+                    var u = this;
+                    if (o) return this.cacheResult().__iterator(i, o);
+                    var a = t.__iterator(R, o),
+                        s = !0,
+                        c = 0;
+                    return new z(function() {
+                        var t, o, f;
+                        do {
+                            if ((t = a.next()).done) return r || i === P ? t : F(i, c++, i === C ? void 0 : t.value[1], t);
+                            var l = t.value;
+                            o = l[0], f = l[1], s && (s = e.call(n, f, o, u))
+                        } while (s);
+                        return i === R ? t : F(i, o, f, t)
+                    })
+                }, i
+            }
+
+            function We(t, e, n) {
+                var r = Qe(t);
+                return r.__iterateUncached = function(r, i) {
+                    var o = 0,
+                        a = !1;
+                    return function t(s, c) {
+                        var f = this;
+                        s.__iterate(function(i, s) {
+                            return (!e || c < e) && u(i) ? t(i, c + 1) : !1 === r(i, n ? s : o++, f) && (a = !0), !a
+                        }, i)
+                    }(t, 0), o
+                }, r.__iteratorUncached = function(r, i) {
+                    var o = t.__iterator(r, i),
+                        a = [],
+                        s = 0;
+                    return new z(function() {
+                        for (; o;) {
+                            var t = o.next();
+                            if (!1 === t.done) {
+                                var c = t.value;
+                                if (r === R && (c = c[1]), e && !(a.length < e) || !u(c)) return n ? t : F(r, s++, c, t);
+                                a.push(o), o = c.__iterator(r, i)
+                            } else o = a.pop()
+                        }
+                        return {
+                            value: void 0,
+                            done: !0
+                        }
+                    })
+                }, r
+            }
+
+            function Ve(t, e, n) {
+                e || (e = en);
+                var r = a(t),
+                    i = 0,
+                    o = t.toSeq().map(function(e, r) {
+                        return [r, e, i++, n ? n(e, r, t) : e]
+                    }).toArray();
+                return o.sort(function(t, n) {
+                    return e(t[3], n[3]) || t[2] - n[2]
+                }).forEach(r ? function(t, e) {
+                    o[e].length = 2
+                } : function(t, e) {
+                    o[e] = t[1]
+                }), r ? G(o) : s(t) ? $(o) : H(o)
+            }
+
+            function Ke(t, e, n) {
+                if (e || (e = en), n) {
+                    var r = t.toSeq().map(function(e, r) {
+                        return [e, n(e, r, t)]
+                    }).reduce(function(t, n) {
+                        return Ge(e, t[1], n[1]) ? n : t
+                    });
+                    return r && r[0]
+                }
+                return t.reduce(function(t, n) {
+                    return Ge(e, t, n) ? n : t
+                })
+            }
+
+            function Ge(t, e, n) {
+                var r = t(n, e);
+                return 0 === r && n !== e && (void 0 === n || null === n || n != n) || r > 0
+            }
+
+            function $e(t, e, r) {
+                var i = Qe(t);
+                return i.size = new tt(r).map(function(t) {
+                    return t.size
+                }).min(), i.__iterate = function(t, e) {
+                    for (var n, r = this.__iterator(P, e), i = 0; !(n = r.next()).done && !1 !== t(n.value, i++, this););
+                    return i
+                }, i.__iteratorUncached = function(t, i) {
+                    var o = r.map(function(t) {
+                            return t = n(t), Y(i ? t.reverse() : t)
+                        }),
+                        u = 0,
+                        a = !1;
+                    return new z(function() {
+                        var n;
+                        return a || (n = o.map(function(t) {
+                            return t.next()
+                        }), a = n.some(function(t) {
+                            return t.done
+                        })), a ? {
+                            value: void 0,
+                            done: !0
+                        } : F(t, u++, e.apply(null, n.map(function(t) {
+                            return t.value
+                        })))
+                    })
+                }, i
+            }
+
+            function He(t, e) {
+                return it(t) ? e : t.constructor(e)
+            }
+
+            function Je(t) {
+                if (t !== Object(t)) throw new TypeError("Expected [K, V] tuple: " + t)
+            }
+
+            function Ze(t) {
+                return Dt(t.size), A(t)
+            }
+
+            function Xe(t) {
+                return a(t) ? r : s(t) ? i : o
+            }
+
+            function Qe(t) {
+                return Object.create((a(t) ? G : s(t) ? $ : H).prototype)
+            }
+
+            function tn() {
+                return this._iter.cacheResult ? (this._iter.cacheResult(), this.size = this._iter.size, this) : K.prototype.cacheResult.call(this)
+            }
+
+            function en(t, e) {
+                return t > e ? 1 : t < e ? -1 : 0
+            }
+
+            function nn(t) {
+                var e = Y(t);
+                if (!e) {
+                    if (!V(t)) throw new TypeError("Expected iterable or array-like: " + t);
+                    e = Y(n(t))
+                }
+                return e
+            }
+
+            function rn(t, e) {
+                var n, r = function(o) {
+                        if (o instanceof r) return o;
+                        if (!(this instanceof r)) return new r(o);
+                        if (!n) {
+                            n = !0;
+                            var u = Object.keys(t);
+                            ! function(t, e) {
+                                try {
+                                    e.forEach(function(t, e) {
+                                        Object.defineProperty(t, e, {
+                                            get: function() {
+                                                return this.get(e)
+                                            },
+                                            set: function(t) {
+                                                gt(this.__ownerID, "Cannot set on an immutable record."), this.set(e, t)
+                                            }
+                                        })
+                                    }.bind(void 0, t))
+                                } catch (t) {}
+                            }(i, u), i.size = u.length, i._name = e, i._keys = u, i._defaultValues = t
+                        }
+                        this._map = zt(o)
+                    },
+                    i = r.prototype = Object.create(on);
+                return i.constructor = r, r
+            }
+            e(Ie, zt), Ie.of = function() {
+                return this(arguments)
+            }, Ie.prototype.toString = function() {
+                return this.__toString("OrderedMap {", "}")
+            }, Ie.prototype.get = function(t, e) {
+                var n = this._map.get(t);
+                return void 0 !== n ? this._list.get(n)[1] : e
+            }, Ie.prototype.clear = function() {
+                return 0 === this.size ? this : this.__ownerID ? (this.size = 0, this._map.clear(), this._list.clear(), this) : Ce()
+            }, Ie.prototype.set = function(t, e) {
+                return Pe(this, t, e)
+            }, Ie.prototype.remove = function(t) {
+                return Pe(this, t, m)
+            }, Ie.prototype.wasAltered = function() {
+                return this._map.wasAltered() || this._list.wasAltered()
+            }, Ie.prototype.__iterate = function(t, e) {
+                var n = this;
+                return this._list.__iterate(function(e) {
+                    return e && t(e[1], e[0], n)
+                }, e)
+            }, Ie.prototype.__iterator = function(t, e) {
+                return this._list.fromEntrySeq().__iterator(t, e)
+            }, Ie.prototype.__ensureOwner = function(t) {
+                if (t === this.__ownerID) return this;
+                var e = this._map.__ensureOwner(t),
+                    n = this._list.__ensureOwner(t);
+                return t ? Te(e, n, t, this.__hash) : (this.__ownerID = t, this._map = e, this._list = n, this)
+            }, Ie.isOrderedMap = Me, Ie.prototype[d] = !0, Ie.prototype.delete = Ie.prototype.remove, e(Re, G), Re.prototype.get = function(t, e) {
+                return this._iter.get(t, e)
+            }, Re.prototype.has = function(t) {
+                return this._iter.has(t)
+            }, Re.prototype.valueSeq = function() {
+                return this._iter.valueSeq()
+            }, Re.prototype.reverse = function() {
+                var t = this,
+                    e = Ue(this, !0);
+                return this._useKeys || (e.valueSeq = function() {
+                    return t._iter.toSeq().reverse()
+                }), e
+            }, Re.prototype.map = function(t, e) {
+                var n = this,
+                    r = Fe(this, t, e);
+                return this._useKeys || (r.valueSeq = function() {
+                    return n._iter.toSeq().map(t, e)
+                }), r
+            }, Re.prototype.__iterate = function(t, e) {
+                var n, r = this;
+                return this._iter.__iterate(this._useKeys ? function(e, n) {
+                    return t(e, n, r)
+                } : (n = e ? Ze(this) : 0, function(i) {
+                    return t(i, e ? --n : n++, r)
+                }), e)
+            }, Re.prototype.__iterator = function(t, e) {
+                if (this._useKeys) return this._iter.__iterator(t, e);
+                var n = this._iter.__iterator(P, e),
+                    r = e ? Ze(this) : 0;
+                return new z(function() {
+                    var i = n.next();
+                    return i.done ? i : F(t, e ? --r : r++, i.value, i)
+                })
+            }, Re.prototype[d] = !0, e(Ne, $), Ne.prototype.includes = function(t) {
+                return this._iter.includes(t)
+            }, Ne.prototype.__iterate = function(t, e) {
+                var n = this,
+                    r = 0;
+                return this._iter.__iterate(function(e) {
+                    return t(e, r++, n)
+                }, e)
+            }, Ne.prototype.__iterator = function(t, e) {
+                var n = this._iter.__iterator(P, e),
+                    r = 0;
+                return new z(function() {
+                    var e = n.next();
+                    return e.done ? e : F(t, r++, e.value, e)
+                })
+            }, e(Le, H), Le.prototype.has = function(t) {
+                return this._iter.includes(t)
+            }, Le.prototype.__iterate = function(t, e) {
+                var n = this;
+                return this._iter.__iterate(function(e) {
+                    return t(e, e, n)
+                }, e)
+            }, Le.prototype.__iterator = function(t, e) {
+                var n = this._iter.__iterator(P, e);
+                return new z(function() {
+                    var e = n.next();
+                    return e.done ? e : F(t, e.value, e.value, e)
+                })
+            }, e(De, G), De.prototype.entrySeq = function() {
+                return this._iter.toSeq()
+            }, De.prototype.__iterate = function(t, e) {
+                var n = this;
+                return this._iter.__iterate(function(e) {
+                    if (e) {
+                        Je(e);
+                        var r = u(e);
+                        return t(r ? e.get(1) : e[1], r ? e.get(0) : e[0], n)
+                    }
+                }, e)
+            }, De.prototype.__iterator = function(t, e) {
+                var n = this._iter.__iterator(P, e);
+                return new z(function() {
+                    for (;;) {
+                        var e = n.next();
+                        if (e.done) return e;
+                        var r = e.value;
+                        if (r) {
+                            Je(r);
+                            var i = u(r);
+                            return F(t, i ? r.get(0) : r[0], i ? r.get(1) : r[1], e)
+                        }
+                    }
+                })
+            }, Ne.prototype.cacheResult = Re.prototype.cacheResult = Le.prototype.cacheResult = De.prototype.cacheResult = tn, e(rn, bt), rn.prototype.toString = function() {
+                return this.__toString(an(this) + " {", "}")
+            }, rn.prototype.has = function(t) {
+                return this._defaultValues.hasOwnProperty(t)
+            }, rn.prototype.get = function(t, e) {
+                if (!this.has(t)) return e;
+                var n = this._defaultValues[t];
+                return this._map ? this._map.get(t, n) : n
+            }, rn.prototype.clear = function() {
+                if (this.__ownerID) return this._map && this._map.clear(), this;
+                var t = this.constructor;
+                return t._empty || (t._empty = un(this, Xt()))
+            }, rn.prototype.set = function(t, e) {
+                if (!this.has(t)) throw new Error('Cannot set unknown key "' + t + '" on ' + an(this));
+                if (this._map && !this._map.has(t) && e === this._defaultValues[t]) return this;
+                var n = this._map && this._map.set(t, e);
+                return this.__ownerID || n === this._map ? this : un(this, n)
+            }, rn.prototype.remove = function(t) {
+                if (!this.has(t)) return this;
+                var e = this._map && this._map.remove(t);
+                return this.__ownerID || e === this._map ? this : un(this, e)
+            }, rn.prototype.wasAltered = function() {
+                return this._map.wasAltered()
+            }, rn.prototype.__iterator = function(t, e) {
+                var n = this;
+                return r(this._defaultValues).map(function(t, e) {
+                    return n.get(e)
+                }).__iterator(t, e)
+            }, rn.prototype.__iterate = function(t, e) {
+                var n = this;
+                return r(this._defaultValues).map(function(t, e) {
+                    return n.get(e)
+                }).__iterate(t, e)
+            }, rn.prototype.__ensureOwner = function(t) {
+                if (t === this.__ownerID) return this;
+                var e = this._map && this._map.__ensureOwner(t);
+                return t ? un(this, e, t) : (this.__ownerID = t, this._map = e, this)
+            };
+            var on = rn.prototype;
+
+            function un(t, e, n) {
+                var r = Object.create(Object.getPrototypeOf(t));
+                return r._map = e, r.__ownerID = n, r
+            }
+
+            function an(t) {
+                return t._name || t.constructor.name || "Record"
+            }
+
+            function sn(t) {
+                return null === t || void 0 === t ? vn() : cn(t) && !f(t) ? t : vn().withMutations(function(e) {
+                    var n = o(t);
+                    Dt(n.size), n.forEach(function(t) {
+                        return e.add(t)
+                    })
+                })
+            }
+
+            function cn(t) {
+                return !(!t || !t[ln])
+            }
+            on.delete = on.remove, on.deleteIn = on.removeIn = qt.removeIn, on.merge = qt.merge, on.mergeWith = qt.mergeWith, on.mergeIn = qt.mergeIn, on.mergeDeep = qt.mergeDeep, on.mergeDeepWith = qt.mergeDeepWith, on.mergeDeepIn = qt.mergeDeepIn, on.setIn = qt.setIn, on.update = qt.update, on.updateIn = qt.updateIn, on.withMutations = qt.withMutations, on.asMutable = qt.asMutable, on.asImmutable = qt.asImmutable, e(sn, xt), sn.of = function() {
+                return this(arguments)
+            }, sn.fromKeys = function(t) {
+                return this(r(t).keySeq())
+            }, sn.prototype.toString = function() {
+                return this.__toString("Set {", "}")
+            }, sn.prototype.has = function(t) {
+                return this._map.has(t)
+            }, sn.prototype.add = function(t) {
+                return hn(this, this._map.set(t, !0))
+            }, sn.prototype.remove = function(t) {
+                return hn(this, this._map.remove(t))
+            }, sn.prototype.clear = function() {
+                return hn(this, this._map.clear())
+            }, sn.prototype.union = function() {
+                var e = t.call(arguments, 0);
+                return 0 === (e = e.filter(function(t) {
+                    return 0 !== t.size
+                })).length ? this : 0 !== this.size || this.__ownerID || 1 !== e.length ? this.withMutations(function(t) {
+                    for (var n = 0; n < e.length; n++) o(e[n]).forEach(function(e) {
+                        return t.add(e)
+                    })
+                }) : this.constructor(e[0])
+            }, sn.prototype.intersect = function() {
+                var e = t.call(arguments, 0);
+                if (0 === e.length) return this;
+                e = e.map(function(t) {
+                    return o(t)
+                });
+                var n = this;
+                return this.withMutations(function(t) {
+                    n.forEach(function(n) {
+                        e.every(function(t) {
+                            return t.includes(n)
+                        }) || t.remove(n)
+                    })
+                })
+            }, sn.prototype.subtract = function() {
+                var e = t.call(arguments, 0);
+                if (0 === e.length) return this;
+                e = e.map(function(t) {
+                    return o(t)
+                });
+                var n = this;
+                return this.withMutations(function(t) {
+                    n.forEach(function(n) {
+                        e.some(function(t) {
+                            return t.includes(n)
+                        }) && t.remove(n)
+                    })
+                })
+            }, sn.prototype.merge = function() {
+                return this.union.apply(this, arguments)
+            }, sn.prototype.mergeWith = function(e) {
+                var n = t.call(arguments, 1);
+                return this.union.apply(this, n)
+            }, sn.prototype.sort = function(t) {
+                return yn(Ve(this, t))
+            }, sn.prototype.sortBy = function(t, e) {
+                return yn(Ve(this, e, t))
+            }, sn.prototype.wasAltered = function() {
+                return this._map.wasAltered()
+            }, sn.prototype.__iterate = function(t, e) {
+                var n = this;
+                return this._map.__iterate(function(e, r) {
+                    return t(r, r, n)
+                }, e)
+            }, sn.prototype.__iterator = function(t, e) {
+                return this._map.map(function(t, e) {
+                    return e
+                }).__iterator(t, e)
+            }, sn.prototype.__ensureOwner = function(t) {
+                if (t === this.__ownerID) return this;
+                var e = this._map.__ensureOwner(t);
+                return t ? this.__make(e, t) : (this.__ownerID = t, this._map = e, this)
+            }, sn.isSet = cn;
+            var fn, ln = "@@__IMMUTABLE_SET__@@",
+                pn = sn.prototype;
+
+            function hn(t, e) {
+                return t.__ownerID ? (t.size = e.size, t._map = e, t) : e === t._map ? t : 0 === e.size ? t.__empty() : t.__make(e)
+            }
+
+            function dn(t, e) {
+                var n = Object.create(pn);
+                return n.size = t ? t.size : 0, n._map = t, n.__ownerID = e, n
+            }
+
+            function vn() {
+                return fn || (fn = dn(Xt()))
+            }
+
+            function yn(t) {
+                return null === t || void 0 === t ? wn() : gn(t) ? t : wn().withMutations(function(e) {
+                    var n = o(t);
+                    Dt(n.size), n.forEach(function(t) {
+                        return e.add(t)
+                    })
+                })
+            }
+
+            function gn(t) {
+                return cn(t) && f(t)
+            }
+            pn[ln] = !0, pn.delete = pn.remove, pn.mergeDeep = pn.merge, pn.mergeDeepWith = pn.mergeWith, pn.withMutations = qt.withMutations, pn.asMutable = qt.asMutable, pn.asImmutable = qt.asImmutable, pn.__empty = vn, pn.__make = dn, e(yn, sn), yn.of = function() {
+                return this(arguments)
+            }, yn.fromKeys = function(t) {
+                return this(r(t).keySeq())
+            }, yn.prototype.toString = function() {
+                return this.__toString("OrderedSet {", "}")
+            }, yn.isOrderedSet = gn;
+            var mn, _n = yn.prototype;
+
+            function bn(t, e) {
+                var n = Object.create(_n);
+                return n.size = t ? t.size : 0, n._map = t, n.__ownerID = e, n
+            }
+
+            function wn() {
+                return mn || (mn = bn(Ce()))
+            }
+
+            function xn(t) {
+                return null === t || void 0 === t ? kn() : Sn(t) ? t : kn().unshiftAll(t)
+            }
+
+            function Sn(t) {
+                return !(!t || !t[An])
+            }
+            _n[d] = !0, _n.__empty = wn, _n.__make = bn, e(xn, wt), xn.of = function() {
+                return this(arguments)
+            }, xn.prototype.toString = function() {
+                return this.__toString("Stack [", "]")
+            }, xn.prototype.get = function(t, e) {
+                var n = this._head;
+                for (t = O(this, t); n && t--;) n = n.next;
+                return n ? n.value : e
+            }, xn.prototype.peek = function() {
+                return this._head && this._head.value
+            }, xn.prototype.push = function() {
+                if (0 === arguments.length) return this;
+                for (var t = this.size + arguments.length, e = this._head, n = arguments.length - 1; n >= 0; n--) e = {
+                    value: arguments[n],
+                    next: e
+                };
+                return this.__ownerID ? (this.size = t, this._head = e, this.__hash = void 0, this.__altered = !0, this) : jn(t, e)
+            }, xn.prototype.pushAll = function(t) {
+                if (0 === (t = i(t)).size) return this;
+                Dt(t.size);
+                var e = this.size,
+                    n = this._head;
+                return t.reverse().forEach(function(t) {
+                    e++, n = {
+                        value: t,
+                        next: n
+                    }
+                }), this.__ownerID ? (this.size = e, this._head = n, this.__hash = void 0, this.__altered = !0, this) : jn(e, n)
+            }, xn.prototype.pop = function() {
+                return this.slice(1)
+            }, xn.prototype.unshift = function() {
+                return this.push.apply(this, arguments)
+            }, xn.prototype.unshiftAll = function(t) {
+                return this.pushAll(t)
+            }, xn.prototype.shift = function() {
+                return this.pop.apply(this, arguments)
+            }, xn.prototype.clear = function() {
+                return 0 === this.size ? this : this.__ownerID ? (this.size = 0, this._head = void 0, this.__hash = void 0, this.__altered = !0, this) : kn()
+            }, xn.prototype.slice = function(t, e) {
+                if (k(t, e, this.size)) return this;
+                var n = I(t, this.size);
+                if (M(e, this.size) !== this.size) return wt.prototype.slice.call(this, t, e);
+                for (var r = this.size - n, i = this._head; n--;) i = i.next;
+                return this.__ownerID ? (this.size = r, this._head = i, this.__hash = void 0, this.__altered = !0, this) : jn(r, i)
+            }, xn.prototype.__ensureOwner = function(t) {
+                return t === this.__ownerID ? this : t ? jn(this.size, this._head, t, this.__hash) : (this.__ownerID = t, this.__altered = !1, this)
+            }, xn.prototype.__iterate = function(t, e) {
+                if (e) return this.reverse().__iterate(t);
+                for (var n = 0, r = this._head; r && !1 !== t(r.value, n++, this);) r = r.next;
+                return n
+            }, xn.prototype.__iterator = function(t, e) {
+                if (e) return this.reverse().__iterator(t);
+                var n = 0,
+                    r = this._head;
+                return new z(function() {
+                    if (r) {
+                        var e = r.value;
+                        return r = r.next, F(t, n++, e)
+                    }
+                    return {
+                        value: void 0,
+                        done: !0
+                    }
+                })
+            }, xn.isStack = Sn;
+            var En, An = "@@__IMMUTABLE_STACK__@@",
+                On = xn.prototype;
+
+            function jn(t, e, n, r) {
+                var i = Object.create(On);
+                return i.size = t, i._head = e, i.__ownerID = n, i.__hash = r, i.__altered = !1, i
+            }
+
+            function kn() {
+                return En || (En = jn(0))
+            }
+
+            function In(t, e) {
+                var n = function(n) {
+                    t.prototype[n] = e[n]
+                };
+                return Object.keys(e).forEach(n), Object.getOwnPropertySymbols && Object.getOwnPropertySymbols(e).forEach(n), t
+            }
+            On[An] = !0, On.withMutations = qt.withMutations, On.asMutable = qt.asMutable, On.asImmutable = qt.asImmutable, On.wasAltered = qt.wasAltered, n.Iterator = z, In(n, {
+                toArray: function() {
+                    Dt(this.size);
+                    var t = new Array(this.size || 0);
+                    return this.valueSeq().__iterate(function(e, n) {
+                        t[n] = e
+                    }), t
+                },
+                toIndexedSeq: function() {
+                    return new Ne(this)
+                },
+                toJS: function() {
+                    return this.toSeq().map(function(t) {
+                        return t && "function" == typeof t.toJS ? t.toJS() : t
+                    }).__toJS()
+                },
+                toJSON: function() {
+                    return this.toSeq().map(function(t) {
+                        return t && "function" == typeof t.toJSON ? t.toJSON() : t
+                    }).__toJS()
+                },
+                toKeyedSeq: function() {
+                    return new Re(this, !0)
+                },
+                toMap: function() {
+                    return zt(this.toKeyedSeq())
+                },
+                toObject: function() {
+                    Dt(this.size);
+                    var t = {};
+                    return this.__iterate(function(e, n) {
+                        t[n] = e
+                    }), t
+                },
+                toOrderedMap: function() {
+                    return Ie(this.toKeyedSeq())
+                },
+                toOrderedSet: function() {
+                    return yn(a(this) ? this.valueSeq() : this)
+                },
+                toSet: function() {
+                    return sn(a(this) ? this.valueSeq() : this)
+                },
+                toSetSeq: function() {
+                    return new Le(this)
+                },
+                toSeq: function() {
+                    return s(this) ? this.toIndexedSeq() : a(this) ? this.toKeyedSeq() : this.toSetSeq()
+                },
+                toStack: function() {
+                    return xn(a(this) ? this.valueSeq() : this)
+                },
+                toList: function() {
+                    return pe(a(this) ? this.valueSeq() : this)
+                },
+                toString: function() {
+                    return "[Iterable]"
+                },
+                __toString: function(t, e) {
+                    return 0 === this.size ? t + e : t + " " + this.toSeq().map(this.__toStringMapper).join(", ") + " " + e
+                },
+                concat: function() {
+                    return He(this, function(t, e) {
+                        var n = a(t),
+                            i = [t].concat(e).map(function(t) {
+                                return u(t) ? n && (t = r(t)) : t = n ? ut(t) : at(Array.isArray(t) ? t : [t]), t
+                            }).filter(function(t) {
+                                return 0 !== t.size
+                            });
+                        if (0 === i.length) return t;
+                        if (1 === i.length) {
+                            var o = i[0];
+                            if (o === t || n && a(o) || s(t) && s(o)) return o
+                        }
+                        var c = new tt(i);
+                        return n ? c = c.toKeyedSeq() : s(t) || (c = c.toSetSeq()), (c = c.flatten(!0)).size = i.reduce(function(t, e) {
+                            if (void 0 !== t) {
+                                var n = e.size;
+                                if (void 0 !== n) return t + n
+                            }
+                        }, 0), c
+                    }(this, t.call(arguments, 0)))
+                },
+                includes: function(t) {
+                    return this.some(function(e) {
+                        return dt(e, t)
+                    })
+                },
+                entries: function() {
+                    return this.__iterator(R)
+                },
+                every: function(t, e) {
+                    Dt(this.size);
+                    var n = !0;
+                    return this.__iterate(function(r, i, o) {
+                        if (!t.call(e, r, i, o)) return n = !1, !1
+                    }), n
+                },
+                filter: function(t, e) {
+                    return He(this, Be(this, t, e, !0))
+                },
+                find: function(t, e, n) {
+                    var r = this.findEntry(t, e);
+                    return r ? r[1] : n
+                },
+                forEach: function(t, e) {
+                    return Dt(this.size), this.__iterate(e ? t.bind(e) : t)
+                },
+                join: function(t) {
+                    Dt(this.size), t = void 0 !== t ? "" + t : ",";
+                    var e = "",
+                        n = !0;
+                    return this.__iterate(function(r) {
+                        n ? n = !1 : e += t, e += null !== r && void 0 !== r ? r.toString() : ""
+                    }), e
+                },
+                keys: function() {
+                    return this.__iterator(C)
+                },
+                map: function(t, e) {
+                    return He(this, Fe(this, t, e))
+                },
+                reduce: function(t, e, n) {
+                    var r, i;
+                    return Dt(this.size), arguments.length < 2 ? i = !0 : r = e, this.__iterate(function(e, o, u) {
+                        i ? (i = !1, r = e) : r = t.call(n, r, e, o, u)
+                    }), r
+                },
+                reduceRight: function(t, e, n) {
+                    var r = this.toKeyedSeq().reverse();
+                    return r.reduce.apply(r, arguments)
+                },
+                reverse: function() {
+                    return He(this, Ue(this, !0))
+                },
+                slice: function(t, e) {
+                    return He(this, qe(this, t, e, !0))
+                },
+                some: function(t, e) {
+                    return !this.every(Rn(t), e)
+                },
+                sort: function(t) {
+                    return He(this, Ve(this, t))
+                },
+                values: function() {
+                    return this.__iterator(P)
+                },
+                butLast: function() {
+                    return this.slice(0, -1)
+                },
+                isEmpty: function() {
+                    return void 0 !== this.size ? 0 === this.size : !this.some(function() {
+                        return !0
+                    })
+                },
+                count: function(t, e) {
+                    return A(t ? this.toSeq().filter(t, e) : this)
+                },
+                countBy: function(t, e) {
+                    return function(t, e, n) {
+                        var r = zt().asMutable();
+                        return t.__iterate(function(i, o) {
+                            r.update(e.call(n, i, o, t), 0, function(t) {
+                                return t + 1
+                            })
+                        }), r.asImmutable()
+                    }(this, t, e)
+                },
+                equals: function(t) {
+                    return vt(this, t)
+                },
+                entrySeq: function() {
+                    var t = this;
+                    if (t._cache) return new tt(t._cache);
+                    var e = t.toSeq().map(Pn).toIndexedSeq();
+                    return e.fromEntrySeq = function() {
+                        return t.toSeq()
+                    }, e
+                },
+                filterNot: function(t, e) {
+                    return this.filter(Rn(t), e)
+                },
+                findEntry: function(t, e, n) {
+                    var r = n;
+                    return this.__iterate(function(n, i, o) {
+                        if (t.call(e, n, i, o)) return r = [i, n], !1
+                    }), r
+                },
+                findKey: function(t, e) {
+                    var n = this.findEntry(t, e);
+                    return n && n[0]
+                },
+                findLast: function(t, e, n) {
+                    return this.toKeyedSeq().reverse().find(t, e, n)
+                },
+                findLastEntry: function(t, e, n) {
+                    return this.toKeyedSeq().reverse().findEntry(t, e, n)
+                },
+                findLastKey: function(t, e) {
+                    return this.toKeyedSeq().reverse().findKey(t, e)
+                },
+                first: function() {
+                    return this.find(j)
+                },
+                flatMap: function(t, e) {
+                    return He(this, function(t, e, n) {
+                        var r = Xe(t);
+                        return t.toSeq().map(function(i, o) {
+                            return r(e.call(n, i, o, t))
+                        }).flatten(!0)
+                    }(this, t, e))
+                },
+                flatten: function(t) {
+                    return He(this, We(this, t, !0))
+                },
+                fromEntrySeq: function() {
+                    return new De(this)
+                },
+                get: function(t, e) {
+                    return this.find(function(e, n) {
+                        return dt(n, t)
+                    }, void 0, e)
+                },
+                getIn: function(t, e) {
+                    for (var n, r = this, i = nn(t); !(n = i.next()).done;) {
+                        var o = n.value;
+                        if ((r = r && r.get ? r.get(o, m) : m) === m) return e
+                    }
+                    return r
+                },
+                groupBy: function(t, e) {
+                    return function(t, e, n) {
+                        var r = a(t),
+                            i = (f(t) ? Ie() : zt()).asMutable();
+                        t.__iterate(function(o, u) {
+                            i.update(e.call(n, o, u, t), function(t) {
+                                return (t = t || []).push(r ? [u, o] : o), t
+                            })
+                        });
+                        var o = Xe(t);
+                        return i.map(function(e) {
+                            return He(t, o(e))
+                        })
+                    }(this, t, e)
+                },
+                has: function(t) {
+                    return this.get(t, m) !== m
+                },
+                hasIn: function(t) {
+                    return this.getIn(t, m) !== m
+                },
+                isSubset: function(t) {
+                    return t = "function" == typeof t.includes ? t : n(t), this.every(function(e) {
+                        return t.includes(e)
+                    })
+                },
+                isSuperset: function(t) {
+                    return (t = "function" == typeof t.isSubset ? t : n(t)).isSubset(this)
+                },
+                keyOf: function(t) {
+                    return this.findKey(function(e) {
+                        return dt(e, t)
+                    })
+                },
+                keySeq: function() {
+                    return this.toSeq().map(Cn).toIndexedSeq()
+                },
+                last: function() {
+                    return this.toSeq().reverse().first()
+                },
+                lastKeyOf: function(t) {
+                    return this.toKeyedSeq().reverse().keyOf(t)
+                },
+                max: function(t) {
+                    return Ke(this, t)
+                },
+                maxBy: function(t, e) {
+                    return Ke(this, e, t)
+                },
+                min: function(t) {
+                    return Ke(this, t ? Nn(t) : zn)
+                },
+                minBy: function(t, e) {
+                    return Ke(this, e ? Nn(e) : zn, t)
+                },
+                rest: function() {
+                    return this.slice(1)
+                },
+                skip: function(t) {
+                    return this.slice(Math.max(0, t))
+                },
+                skipLast: function(t) {
+                    return He(this, this.toSeq().reverse().skip(t).reverse())
+                },
+                skipWhile: function(t, e) {
+                    return He(this, Ye(this, t, e, !0))
+                },
+                skipUntil: function(t, e) {
+                    return this.skipWhile(Rn(t), e)
+                },
+                sortBy: function(t, e) {
+                    return He(this, Ve(this, e, t))
+                },
+                take: function(t) {
+                    return this.slice(0, Math.max(0, t))
+                },
+                takeLast: function(t) {
+                    return He(this, this.toSeq().reverse().take(t).reverse())
+                },
+                takeWhile: function(t, e) {
+                    return He(this, function(t, e, n) {
+                        var r = Qe(t);
+                        return r.__iterateUncached = function(r, i) {
+                            var o = this;
+                            if (i) return this.cacheResult().__iterate(r, i);
+                            var u = 0;
+                            return t.__iterate(function(t, i, a) {
+                                return e.call(n, t, i, a) && ++u && r(t, i, o)
+                            }), u
+                        }, r.__iteratorUncached = function(r, i) {
+                            var o = this;
+                            if (i) return this.cacheResult().__iterator(r, i);
+                            var u = t.__iterator(R, i),
+                                a = !0;
+                            return new z(function() {
+                                if (!a) return {
+                                    value: void 0,
+                                    done: !0
+                                };
+                                var t = u.next();
+                                if (t.done) return t;
+                                var i = t.value,
+                                    s = i[0],
+                                    c = i[1];
+                                return e.call(n, c, s, o) ? r === R ? t : F(r, s, c, t) : (a = !1, {
+                                    value: void 0,
+                                    done: !0
+                                })
+                            })
+                        }, r
+                    }(this, t, e))
+                },
+                takeUntil: function(t, e) {
+                    return this.takeWhile(Rn(t), e)
+                },
+                valueSeq: function() {
+                    return this.toIndexedSeq()
+                },
+                hashCode: function() {
+                    return this.__hash || (this.__hash = function(t) {
+                        if (t.size === 1 / 0) return 0;
+                        var e = f(t),
+                            n = a(t),
+                            r = e ? 1 : 0;
+                        return function(t, e) {
+                            return e = St(e, 3432918353), e = St(e << 15 | e >>> -15, 461845907), e = St(e << 13 | e >>> -13, 5), e = St((e = (e + 3864292196 | 0) ^ t) ^ e >>> 16, 2246822507), e = Et((e = St(e ^ e >>> 13, 3266489909)) ^ e >>> 16)
+                        }(t.__iterate(n ? e ? function(t, e) {
+                            r = 31 * r + Fn(At(t), At(e)) | 0
+                        } : function(t, e) {
+                            r = r + Fn(At(t), At(e)) | 0
+                        } : e ? function(t) {
+                            r = 31 * r + At(t) | 0
+                        } : function(t) {
+                            r = r + At(t) | 0
+                        }), r)
+                    }(this))
+                }
+            });
+            var Mn = n.prototype;
+            Mn[l] = !0, Mn[D] = Mn.values, Mn.__toJS = Mn.toArray, Mn.__toStringMapper = Ln, Mn.inspect = Mn.toSource = function() {
+                return this.toString()
+            }, Mn.chain = Mn.flatMap, Mn.contains = Mn.includes, In(r, {
+                flip: function() {
+                    return He(this, ze(this))
+                },
+                mapEntries: function(t, e) {
+                    var n = this,
+                        r = 0;
+                    return He(this, this.toSeq().map(function(i, o) {
+                        return t.call(e, [o, i], r++, n)
+                    }).fromEntrySeq())
+                },
+                mapKeys: function(t, e) {
+                    var n = this;
+                    return He(this, this.toSeq().flip().map(function(r, i) {
+                        return t.call(e, r, i, n)
+                    }).flip())
+                }
+            });
+            var Tn = r.prototype;
+
+            function Cn(t, e) {
+                return e
+            }
+
+            function Pn(t, e) {
+                return [e, t]
+            }
+
+            function Rn(t) {
+                return function() {
+                    return !t.apply(this, arguments)
+                }
+            }
+
+            function Nn(t) {
+                return function() {
+                    return -t.apply(this, arguments)
+                }
+            }
+
+            function Ln(t) {
+                return "string" == typeof t ? JSON.stringify(t) : String(t)
+            }
+
+            function Dn() {
+                return E(arguments)
+            }
+
+            function zn(t, e) {
+                return t < e ? 1 : t > e ? -1 : 0
+            }
+
+            function Fn(t, e) {
+                return t ^ e + 2654435769 + (t << 6) + (t >> 2) | 0
+            }
+            return Tn[p] = !0, Tn[D] = Mn.entries, Tn.__toJS = Mn.toObject, Tn.__toStringMapper = function(t, e) {
+                return JSON.stringify(e) + ": " + Ln(t)
+            }, In(i, {
+                toKeyedSeq: function() {
+                    return new Re(this, !1)
+                },
+                filter: function(t, e) {
+                    return He(this, Be(this, t, e, !1))
+                },
+                findIndex: function(t, e) {
+                    var n = this.findEntry(t, e);
+                    return n ? n[0] : -1
+                },
+                indexOf: function(t) {
+                    var e = this.keyOf(t);
+                    return void 0 === e ? -1 : e
+                },
+                lastIndexOf: function(t) {
+                    var e = this.lastKeyOf(t);
+                    return void 0 === e ? -1 : e
+                },
+                reverse: function() {
+                    return He(this, Ue(this, !1))
+                },
+                slice: function(t, e) {
+                    return He(this, qe(this, t, e, !1))
+                },
+                splice: function(t, e) {
+                    var n = arguments.length;
+                    if (e = Math.max(0 | e, 0), 0 === n || 2 === n && !e) return this;
+                    t = I(t, t < 0 ? this.count() : this.size);
+                    var r = this.slice(0, t);
+                    return He(this, 1 === n ? r : r.concat(E(arguments, 2), this.slice(t + e)))
+                },
+                findLastIndex: function(t, e) {
+                    var n = this.findLastEntry(t, e);
+                    return n ? n[0] : -1
+                },
+                first: function() {
+                    return this.get(0)
+                },
+                flatten: function(t) {
+                    return He(this, We(this, t, !1))
+                },
+                get: function(t, e) {
+                    return (t = O(this, t)) < 0 || this.size === 1 / 0 || void 0 !== this.size && t > this.size ? e : this.find(function(e, n) {
+                        return n === t
+                    }, void 0, e)
+                },
+                has: function(t) {
+                    return (t = O(this, t)) >= 0 && (void 0 !== this.size ? this.size === 1 / 0 || t < this.size : -1 !== this.indexOf(t))
+                },
+                interpose: function(t) {
+                    return He(this, function(t, e) {
+                        var n = Qe(t);
+                        return n.size = t.size && 2 * t.size - 1, n.__iterateUncached = function(n, r) {
+                            var i = this,
+                                o = 0;
+                            return t.__iterate(function(t, r) {
+                                return (!o || !1 !== n(e, o++, i)) && !1 !== n(t, o++, i)
+                            }, r), o
+                        }, n.__iteratorUncached = function(n, r) {
+                            var i, o = t.__iterator(P, r),
+                                u = 0;
+                            return new z(function() {
+                                return (!i || u % 2) && (i = o.next()).done ? i : u % 2 ? F(n, u++, e) : F(n, u++, i.value, i)
+                            })
+                        }, n
+                    }(this, t))
+                },
+                interleave: function() {
+                    var t = [this].concat(E(arguments)),
+                        e = $e(this.toSeq(), $.of, t),
+                        n = e.flatten(!0);
+                    return e.size && (n.size = e.size * t.length), He(this, n)
+                },
+                keySeq: function() {
+                    return mt(0, this.size)
+                },
+                last: function() {
+                    return this.get(-1)
+                },
+                skipWhile: function(t, e) {
+                    return He(this, Ye(this, t, e, !1))
+                },
+                zip: function() {
+                    return He(this, $e(this, Dn, [this].concat(E(arguments))))
+                },
+                zipWith: function(t) {
+                    var e = E(arguments);
+                    return e[0] = this, He(this, $e(this, t, e))
+                }
+            }), i.prototype[h] = !0, i.prototype[d] = !0, In(o, {
+                get: function(t, e) {
+                    return this.has(t) ? t : e
+                },
+                includes: function(t) {
+                    return this.has(t)
+                },
+                keySeq: function() {
+                    return this.valueSeq()
+                }
+            }), o.prototype.has = Mn.includes, o.prototype.contains = o.prototype.includes, In(G, r.prototype), In($, i.prototype), In(H, o.prototype), In(bt, r.prototype), In(wt, i.prototype), In(xt, o.prototype), {
+                Iterable: n,
+                Seq: K,
+                Collection: _t,
+                Map: zt,
+                OrderedMap: Ie,
+                List: pe,
+                Stack: xn,
+                Set: sn,
+                OrderedSet: yn,
+                Record: rn,
+                Range: mt,
+                Repeat: yt,
+                is: dt,
+                fromJS: lt
+            }
+        }, t.exports = r()
+    }, function(t, e, n) {
+        (function(e) {
+            var n = "object" == typeof e && e && e.Object === Object && e;
+            t.exports = n
+        }).call(e, n(11))
+    }, function(t, e, n) {
+        var r = n(347)("toUpperCase");
+        t.exports = r
+    }, function(t, e) {
+        var n = RegExp("[\\u200d\\ud800-\\udfff\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff\\ufe0e\\ufe0f]");
+        t.exports = function(t) {
+            return n.test(t)
+        }
+    }, function(t, e, n) {
+        var r = n(109),
+            i = "Expected a function";
+
+        function o(t, e) {
+            if ("function" != typeof t || null != e && "function" != typeof e) throw new TypeError(i);
+            var n = function() {
+                var r = arguments,
+                    i = e ? e.apply(this, r) : r[0],
+                    o = n.cache;
+                if (o.has(i)) return o.get(i);
+                var u = t.apply(this, r);
+                return n.cache = o.set(i, u) || o, u
+            };
+            return n.cache = new(o.Cache || r), n
+        }
+        o.Cache = r, t.exports = o
+    }, function(t, e, n) {
+        var r = n(48),
+            i = n(50),
+            o = "[object AsyncFunction]",
+            u = "[object Function]",
+            a = "[object GeneratorFunction]",
+            s = "[object Proxy]";
+        t.exports = function(t) {
+            if (!i(t)) return !1;
+            var e = r(t);
+            return e == u || e == a || e == o || e == s
+        }
+    }, function(t, e) {
+        var n = Function.prototype.toString;
+        t.exports = function(t) {
+            if (null != t) {
+                try {
+                    return n.call(t)
+                } catch (t) {}
+                try {
+                    return t + ""
+                } catch (t) {}
+            }
+            return ""
+        }
+    }, function(t, e, n) {
+        var r = n(72),
+            i = n(387),
+            o = n(388),
+            u = n(389),
+            a = n(390),
+            s = n(391);
+
+        function c(t) {
+            var e = this.__data__ = new r(t);
+            this.size = e.size
+        }
+        c.prototype.clear = i, c.prototype.delete = o, c.prototype.get = u, c.prototype.has = a, c.prototype.set = s, t.exports = c
+    }, function(t, e, n) {
+        var r = n(392),
+            i = n(49);
+        t.exports = function t(e, n, o, u, a) {
+            return e === n || (null == e || null == n || !i(e) && !i(n) ? e != e && n != n : r(e, n, o, u, t, a))
+        }
+    }, function(t, e, n) {
+        var r = n(393),
+            i = n(178),
+            o = n(396),
+            u = 1,
+            a = 2;
+        t.exports = function(t, e, n, s, c, f) {
+            var l = n & u,
+                p = t.length,
+                h = e.length;
+            if (p != h && !(l && h > p)) return !1;
+            var d = f.get(t);
+            if (d && f.get(e)) return d == e;
+            var v = -1,
+                y = !0,
+                g = n & a ? new r : void 0;
+            for (f.set(t, e), f.set(e, t); ++v < p;) {
+                var m = t[v],
+                    _ = e[v];
+                if (s) var b = l ? s(_, m, v, e, t, f) : s(m, _, v, t, e, f);
+                if (void 0 !== b) {
+                    if (b) continue;
+                    y = !1;
+                    break
+                }
+                if (g) {
+                    if (!i(e, function(t, e) {
+                        if (!o(g, e) && (m === t || c(m, t, n, s, f))) return g.push(e)
+                    })) {
+                        y = !1;
+                        break
+                    }
+                } else if (m !== _ && !c(m, _, n, s, f)) {
+                    y = !1;
+                    break
+                }
+            }
+            return f.delete(t), f.delete(e), y
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            for (var n = -1, r = null == t ? 0 : t.length; ++n < r;)
+                if (e(t[n], n, t)) return !0;
+            return !1
+        }
+    }, function(t, e, n) {
+        var r = n(410),
+            i = n(49),
+            o = Object.prototype,
+            u = o.hasOwnProperty,
+            a = o.propertyIsEnumerable,
+            s = r(function() {
+                return arguments
+            }()) ? r : function(t) {
+                return i(t) && u.call(t, "callee") && !a.call(t, "callee")
+            };
+        t.exports = s
+    }, function(t, e, n) {
+        (function(t) {
+            var r = n(7),
+                i = n(411),
+                o = "object" == typeof e && e && !e.nodeType && e,
+                u = o && "object" == typeof t && t && !t.nodeType && t,
+                a = u && u.exports === o ? r.Buffer : void 0,
+                s = (a ? a.isBuffer : void 0) || i;
+            t.exports = s
+        }).call(e, n(181)(t))
+    }, function(t, e) {
+        t.exports = function(t) {
+            return t.webpackPolyfill || (t.deprecate = function() {}, t.paths = [], t.children || (t.children = []), Object.defineProperty(t, "loaded", {
+                enumerable: !0,
+                get: function() {
+                    return t.l
+                }
+            }), Object.defineProperty(t, "id", {
+                enumerable: !0,
+                get: function() {
+                    return t.i
+                }
+            }), t.webpackPolyfill = 1), t
+        }
+    }, function(t, e, n) {
+        var r = n(412),
+            i = n(413),
+            o = n(414),
+            u = o && o.isTypedArray,
+            a = u ? i(u) : r;
+        t.exports = a
+    }, function(t, e, n) {
+        var r = n(50);
+        t.exports = function(t) {
+            return t == t && !r(t)
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return function(n) {
+                return null != n && n[t] === e && (void 0 !== e || t in Object(n))
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(186),
+            i = n(78);
+        t.exports = function(t, e) {
+            for (var n = 0, o = (e = r(e, t)).length; null != t && n < o;) t = t[i(e[n++])];
+            return n && n == o ? t : void 0
+        }
+    }, function(t, e, n) {
+        var r = n(8),
+            i = n(114),
+            o = n(427),
+            u = n(47);
+        t.exports = function(t, e) {
+            return r(t) ? t : i(t, e) ? [t] : o(u(t))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        (function(e, r) {
+            var i = n(79);
+            t.exports = _;
+            var o, u = n(167);
+            _.ReadableState = m;
+            n(115).EventEmitter;
+            var a = function(t, e) {
+                    return t.listeners(e).length
+                },
+                s = n(188),
+                c = n(80).Buffer,
+                f = e.Uint8Array || function() {};
+            var l = n(51);
+            l.inherits = n(35);
+            var p = n(453),
+                h = void 0;
+            h = p && p.debuglog ? p.debuglog("stream") : function() {};
+            var d, v = n(454),
+                y = n(189);
+            l.inherits(_, s);
+            var g = ["error", "close", "destroy", "pause", "resume"];
+
+            function m(t, e) {
+                o = o || n(21), t = t || {};
+                var r = e instanceof o;
+                this.objectMode = !!t.objectMode, r && (this.objectMode = this.objectMode || !!t.readableObjectMode);
+                var i = t.highWaterMark,
+                    u = t.readableHighWaterMark,
+                    a = this.objectMode ? 16 : 16384;
+                this.highWaterMark = i || 0 === i ? i : r && (u || 0 === u) ? u : a, this.highWaterMark = Math.floor(this.highWaterMark), this.buffer = new v, this.length = 0, this.pipes = null, this.pipesCount = 0, this.flowing = null, this.ended = !1, this.endEmitted = !1, this.reading = !1, this.sync = !0, this.needReadable = !1, this.emittedReadable = !1, this.readableListening = !1, this.resumeScheduled = !1, this.destroyed = !1, this.defaultEncoding = t.defaultEncoding || "utf8", this.awaitDrain = 0, this.readingMore = !1, this.decoder = null, this.encoding = null, t.encoding && (d || (d = n(191).StringDecoder), this.decoder = new d(t.encoding), this.encoding = t.encoding)
+            }
+
+            function _(t) {
+                if (o = o || n(21), !(this instanceof _)) return new _(t);
+                this._readableState = new m(t, this), this.readable = !0, t && ("function" == typeof t.read && (this._read = t.read), "function" == typeof t.destroy && (this._destroy = t.destroy)), s.call(this)
+            }
+
+            function b(t, e, n, r, i) {
+                var o, u = t._readableState;
+                null === e ? (u.reading = !1, function(t, e) {
+                    if (e.ended) return;
+                    if (e.decoder) {
+                        var n = e.decoder.end();
+                        n && n.length && (e.buffer.push(n), e.length += e.objectMode ? 1 : n.length)
+                    }
+                    e.ended = !0, E(t)
+                }(t, u)) : (i || (o = function(t, e) {
+                    var n;
+                    r = e, c.isBuffer(r) || r instanceof f || "string" == typeof e || void 0 === e || t.objectMode || (n = new TypeError("Invalid non-string/buffer chunk"));
+                    var r;
+                    return n
+                }(u, e)), o ? t.emit("error", o) : u.objectMode || e && e.length > 0 ? ("string" == typeof e || u.objectMode || Object.getPrototypeOf(e) === c.prototype || (e = function(t) {
+                    return c.from(t)
+                }(e)), r ? u.endEmitted ? t.emit("error", new Error("stream.unshift() after end event")) : w(t, u, e, !0) : u.ended ? t.emit("error", new Error("stream.push() after EOF")) : (u.reading = !1, u.decoder && !n ? (e = u.decoder.write(e), u.objectMode || 0 !== e.length ? w(t, u, e, !1) : O(t, u)) : w(t, u, e, !1))) : r || (u.reading = !1));
+                return function(t) {
+                    return !t.ended && (t.needReadable || t.length < t.highWaterMark || 0 === t.length)
+                }(u)
+            }
+
+            function w(t, e, n, r) {
+                e.flowing && 0 === e.length && !e.sync ? (t.emit("data", n), t.read(0)) : (e.length += e.objectMode ? 1 : n.length, r ? e.buffer.unshift(n) : e.buffer.push(n), e.needReadable && E(t)), O(t, e)
+            }
+            Object.defineProperty(_.prototype, "destroyed", {
+                get: function() {
+                    return void 0 !== this._readableState && this._readableState.destroyed
+                },
+                set: function(t) {
+                    this._readableState && (this._readableState.destroyed = t)
+                }
+            }), _.prototype.destroy = y.destroy, _.prototype._undestroy = y.undestroy, _.prototype._destroy = function(t, e) {
+                this.push(null), e(t)
+            }, _.prototype.push = function(t, e) {
+                var n, r = this._readableState;
+                return r.objectMode ? n = !0 : "string" == typeof t && ((e = e || r.defaultEncoding) !== r.encoding && (t = c.from(t, e), e = ""), n = !0), b(this, t, e, !1, n)
+            }, _.prototype.unshift = function(t) {
+                return b(this, t, null, !0, !1)
+            }, _.prototype.isPaused = function() {
+                return !1 === this._readableState.flowing
+            }, _.prototype.setEncoding = function(t) {
+                return d || (d = n(191).StringDecoder), this._readableState.decoder = new d(t), this._readableState.encoding = t, this
+            };
+            var x = 8388608;
+
+            function S(t, e) {
+                return t <= 0 || 0 === e.length && e.ended ? 0 : e.objectMode ? 1 : t != t ? e.flowing && e.length ? e.buffer.head.data.length : e.length : (t > e.highWaterMark && (e.highWaterMark = function(t) {
+                    return t >= x ? t = x : (t--, t |= t >>> 1, t |= t >>> 2, t |= t >>> 4, t |= t >>> 8, t |= t >>> 16, t++), t
+                }(t)), t <= e.length ? t : e.ended ? e.length : (e.needReadable = !0, 0))
+            }
+
+            function E(t) {
+                var e = t._readableState;
+                e.needReadable = !1, e.emittedReadable || (h("emitReadable", e.flowing), e.emittedReadable = !0, e.sync ? i.nextTick(A, t) : A(t))
+            }
+
+            function A(t) {
+                h("emit readable"), t.emit("readable"), M(t)
+            }
+
+            function O(t, e) {
+                e.readingMore || (e.readingMore = !0, i.nextTick(j, t, e))
+            }
+
+            function j(t, e) {
+                for (var n = e.length; !e.reading && !e.flowing && !e.ended && e.length < e.highWaterMark && (h("maybeReadMore read 0"), t.read(0), n !== e.length);) n = e.length;
+                e.readingMore = !1
+            }
+
+            function k(t) {
+                h("readable nexttick read 0"), t.read(0)
+            }
+
+            function I(t, e) {
+                e.reading || (h("resume read 0"), t.read(0)), e.resumeScheduled = !1, e.awaitDrain = 0, t.emit("resume"), M(t), e.flowing && !e.reading && t.read(0)
+            }
+
+            function M(t) {
+                var e = t._readableState;
+                for (h("flow", e.flowing); e.flowing && null !== t.read(););
+            }
+
+            function T(t, e) {
+                return 0 === e.length ? null : (e.objectMode ? n = e.buffer.shift() : !t || t >= e.length ? (n = e.decoder ? e.buffer.join("") : 1 === e.buffer.length ? e.buffer.head.data : e.buffer.concat(e.length), e.buffer.clear()) : n = function(t, e, n) {
+                    var r;
+                    t < e.head.data.length ? (r = e.head.data.slice(0, t), e.head.data = e.head.data.slice(t)) : r = t === e.head.data.length ? e.shift() : n ? function(t, e) {
+                        var n = e.head,
+                            r = 1,
+                            i = n.data;
+                        t -= i.length;
+                        for (; n = n.next;) {
+                            var o = n.data,
+                                u = t > o.length ? o.length : t;
+                            if (u === o.length ? i += o : i += o.slice(0, t), 0 === (t -= u)) {
+                                u === o.length ? (++r, n.next ? e.head = n.next : e.head = e.tail = null) : (e.head = n, n.data = o.slice(u));
+                                break
+                            }++r
+                        }
+                        return e.length -= r, i
+                    }(t, e) : function(t, e) {
+                        var n = c.allocUnsafe(t),
+                            r = e.head,
+                            i = 1;
+                        r.data.copy(n), t -= r.data.length;
+                        for (; r = r.next;) {
+                            var o = r.data,
+                                u = t > o.length ? o.length : t;
+                            if (o.copy(n, n.length - t, 0, u), 0 === (t -= u)) {
+                                u === o.length ? (++i, r.next ? e.head = r.next : e.head = e.tail = null) : (e.head = r, r.data = o.slice(u));
+                                break
+                            }++i
+                        }
+                        return e.length -= i, n
+                    }(t, e);
+                    return r
+                }(t, e.buffer, e.decoder), n);
+                var n
+            }
+
+            function C(t) {
+                var e = t._readableState;
+                if (e.length > 0) throw new Error('"endReadable()" called on non-empty stream');
+                e.endEmitted || (e.ended = !0, i.nextTick(P, e, t))
+            }
+
+            function P(t, e) {
+                t.endEmitted || 0 !== t.length || (t.endEmitted = !0, e.readable = !1, e.emit("end"))
+            }
+
+            function R(t, e) {
+                for (var n = 0, r = t.length; n < r; n++)
+                    if (t[n] === e) return n;
+                return -1
+            }
+            _.prototype.read = function(t) {
+                h("read", t), t = parseInt(t, 10);
+                var e = this._readableState,
+                    n = t;
+                if (0 !== t && (e.emittedReadable = !1), 0 === t && e.needReadable && (e.length >= e.highWaterMark || e.ended)) return h("read: emitReadable", e.length, e.ended), 0 === e.length && e.ended ? C(this) : E(this), null;
+                if (0 === (t = S(t, e)) && e.ended) return 0 === e.length && C(this), null;
+                var r, i = e.needReadable;
+                return h("need readable", i), (0 === e.length || e.length - t < e.highWaterMark) && h("length less than watermark", i = !0), e.ended || e.reading ? h("reading or ended", i = !1) : i && (h("do read"), e.reading = !0, e.sync = !0, 0 === e.length && (e.needReadable = !0), this._read(e.highWaterMark), e.sync = !1, e.reading || (t = S(n, e))), null === (r = t > 0 ? T(t, e) : null) ? (e.needReadable = !0, t = 0) : e.length -= t, 0 === e.length && (e.ended || (e.needReadable = !0), n !== t && e.ended && C(this)), null !== r && this.emit("data", r), r
+            }, _.prototype._read = function(t) {
+                this.emit("error", new Error("_read() is not implemented"))
+            }, _.prototype.pipe = function(t, e) {
+                var n = this,
+                    o = this._readableState;
+                switch (o.pipesCount) {
+                    case 0:
+                        o.pipes = t;
+                        break;
+                    case 1:
+                        o.pipes = [o.pipes, t];
+                        break;
+                    default:
+                        o.pipes.push(t)
+                }
+                o.pipesCount += 1, h("pipe count=%d opts=%j", o.pipesCount, e);
+                var s = (!e || !1 !== e.end) && t !== r.stdout && t !== r.stderr ? f : _;
+
+                function c(e, r) {
+                    h("onunpipe"), e === n && r && !1 === r.hasUnpiped && (r.hasUnpiped = !0, h("cleanup"), t.removeListener("close", g), t.removeListener("finish", m), t.removeListener("drain", l), t.removeListener("error", y), t.removeListener("unpipe", c), n.removeListener("end", f), n.removeListener("end", _), n.removeListener("data", v), p = !0, !o.awaitDrain || t._writableState && !t._writableState.needDrain || l())
+                }
+
+                function f() {
+                    h("onend"), t.end()
+                }
+                o.endEmitted ? i.nextTick(s) : n.once("end", s), t.on("unpipe", c);
+                var l = function(t) {
+                    return function() {
+                        var e = t._readableState;
+                        h("pipeOnDrain", e.awaitDrain), e.awaitDrain && e.awaitDrain--, 0 === e.awaitDrain && a(t, "data") && (e.flowing = !0, M(t))
+                    }
+                }(n);
+                t.on("drain", l);
+                var p = !1;
+                var d = !1;
+
+                function v(e) {
+                    h("ondata"), d = !1, !1 !== t.write(e) || d || ((1 === o.pipesCount && o.pipes === t || o.pipesCount > 1 && -1 !== R(o.pipes, t)) && !p && (h("false write response, pause", n._readableState.awaitDrain), n._readableState.awaitDrain++, d = !0), n.pause())
+                }
+
+                function y(e) {
+                    h("onerror", e), _(), t.removeListener("error", y), 0 === a(t, "error") && t.emit("error", e)
+                }
+
+                function g() {
+                    t.removeListener("finish", m), _()
+                }
+
+                function m() {
+                    h("onfinish"), t.removeListener("close", g), _()
+                }
+
+                function _() {
+                    h("unpipe"), n.unpipe(t)
+                }
+                return n.on("data", v),
+                    function(t, e, n) {
+                        if ("function" == typeof t.prependListener) return t.prependListener(e, n);
+                        t._events && t._events[e] ? u(t._events[e]) ? t._events[e].unshift(n) : t._events[e] = [n, t._events[e]] : t.on(e, n)
+                    }(t, "error", y), t.once("close", g), t.once("finish", m), t.emit("pipe", n), o.flowing || (h("pipe resume"), n.resume()), t
+            }, _.prototype.unpipe = function(t) {
+                var e = this._readableState,
+                    n = {
+                        hasUnpiped: !1
+                    };
+                if (0 === e.pipesCount) return this;
+                if (1 === e.pipesCount) return t && t !== e.pipes ? this : (t || (t = e.pipes), e.pipes = null, e.pipesCount = 0, e.flowing = !1, t && t.emit("unpipe", this, n), this);
+                if (!t) {
+                    var r = e.pipes,
+                        i = e.pipesCount;
+                    e.pipes = null, e.pipesCount = 0, e.flowing = !1;
+                    for (var o = 0; o < i; o++) r[o].emit("unpipe", this, n);
+                    return this
+                }
+                var u = R(e.pipes, t);
+                return -1 === u ? this : (e.pipes.splice(u, 1), e.pipesCount -= 1, 1 === e.pipesCount && (e.pipes = e.pipes[0]), t.emit("unpipe", this, n), this)
+            }, _.prototype.on = function(t, e) {
+                var n = s.prototype.on.call(this, t, e);
+                if ("data" === t) !1 !== this._readableState.flowing && this.resume();
+                else if ("readable" === t) {
+                    var r = this._readableState;
+                    r.endEmitted || r.readableListening || (r.readableListening = r.needReadable = !0, r.emittedReadable = !1, r.reading ? r.length && E(this) : i.nextTick(k, this))
+                }
+                return n
+            }, _.prototype.addListener = _.prototype.on, _.prototype.resume = function() {
+                var t = this._readableState;
+                return t.flowing || (h("resume"), t.flowing = !0, function(t, e) {
+                    e.resumeScheduled || (e.resumeScheduled = !0, i.nextTick(I, t, e))
+                }(this, t)), this
+            }, _.prototype.pause = function() {
+                return h("call pause flowing=%j", this._readableState.flowing), !1 !== this._readableState.flowing && (h("pause"), this._readableState.flowing = !1, this.emit("pause")), this
+            }, _.prototype.wrap = function(t) {
+                var e = this,
+                    n = this._readableState,
+                    r = !1;
+                for (var i in t.on("end", function() {
+                    if (h("wrapped end"), n.decoder && !n.ended) {
+                        var t = n.decoder.end();
+                        t && t.length && e.push(t)
+                    }
+                    e.push(null)
+                }), t.on("data", function(i) {
+                    (h("wrapped data"), n.decoder && (i = n.decoder.write(i)), !n.objectMode || null !== i && void 0 !== i) && ((n.objectMode || i && i.length) && (e.push(i) || (r = !0, t.pause())))
+                }), t) void 0 === this[i] && "function" == typeof t[i] && (this[i] = function(e) {
+                    return function() {
+                        return t[e].apply(t, arguments)
+                    }
+                }(i));
+                for (var o = 0; o < g.length; o++) t.on(g[o], this.emit.bind(this, g[o]));
+                return this._read = function(e) {
+                    h("wrapped _read", e), r && (r = !1, t.resume())
+                }, this
+            }, Object.defineProperty(_.prototype, "readableHighWaterMark", {
+                enumerable: !1,
+                get: function() {
+                    return this._readableState.highWaterMark
+                }
+            }), _._fromList = T
+        }).call(e, n(11), n(34))
+    }, function(t, e, n) {
+        t.exports = n(115).EventEmitter
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(79);
+
+        function i(t, e) {
+            t.emit("error", e)
+        }
+        t.exports = {
+            destroy: function(t, e) {
+                var n = this,
+                    o = this._readableState && this._readableState.destroyed,
+                    u = this._writableState && this._writableState.destroyed;
+                return o || u ? (e ? e(t) : !t || this._writableState && this._writableState.errorEmitted || r.nextTick(i, this, t), this) : (this._readableState && (this._readableState.destroyed = !0), this._writableState && (this._writableState.destroyed = !0), this._destroy(t || null, function(t) {
+                    !e && t ? (r.nextTick(i, n, t), n._writableState && (n._writableState.errorEmitted = !0)) : e && e(t)
+                }), this)
+            },
+            undestroy: function() {
+                this._readableState && (this._readableState.destroyed = !1, this._readableState.reading = !1, this._readableState.ended = !1, this._readableState.endEmitted = !1), this._writableState && (this._writableState.destroyed = !1, this._writableState.ended = !1, this._writableState.ending = !1, this._writableState.finished = !1, this._writableState.errorEmitted = !1)
+            }
+        }
+    }, function(t, e, n) {
+        (function(t) {
+            var r = void 0 !== t && t || "undefined" != typeof self && self || window,
+                i = Function.prototype.apply;
+
+            function o(t, e) {
+                this._id = t, this._clearFn = e
+            }
+            e.setTimeout = function() {
+                return new o(i.call(setTimeout, r, arguments), clearTimeout)
+            }, e.setInterval = function() {
+                return new o(i.call(setInterval, r, arguments), clearInterval)
+            }, e.clearTimeout = e.clearInterval = function(t) {
+                t && t.close()
+            }, o.prototype.unref = o.prototype.ref = function() {}, o.prototype.close = function() {
+                this._clearFn.call(r, this._id)
+            }, e.enroll = function(t, e) {
+                clearTimeout(t._idleTimeoutId), t._idleTimeout = e
+            }, e.unenroll = function(t) {
+                clearTimeout(t._idleTimeoutId), t._idleTimeout = -1
+            }, e._unrefActive = e.active = function(t) {
+                clearTimeout(t._idleTimeoutId);
+                var e = t._idleTimeout;
+                e >= 0 && (t._idleTimeoutId = setTimeout(function() {
+                    t._onTimeout && t._onTimeout()
+                }, e))
+            }, n(456), e.setImmediate = "undefined" != typeof self && self.setImmediate || void 0 !== t && t.setImmediate || this && this.setImmediate, e.clearImmediate = "undefined" != typeof self && self.clearImmediate || void 0 !== t && t.clearImmediate || this && this.clearImmediate
+        }).call(e, n(11))
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(80).Buffer,
+            i = r.isEncoding || function(t) {
+                switch ((t = "" + t) && t.toLowerCase()) {
+                    case "hex":
+                    case "utf8":
+                    case "utf-8":
+                    case "ascii":
+                    case "binary":
+                    case "base64":
+                    case "ucs2":
+                    case "ucs-2":
+                    case "utf16le":
+                    case "utf-16le":
+                    case "raw":
+                        return !0;
+                    default:
+                        return !1
+                }
+            };
+
+        function o(t) {
+            var e;
+            switch (this.encoding = function(t) {
+                var e = function(t) {
+                    if (!t) return "utf8";
+                    for (var e;;) switch (t) {
+                        case "utf8":
+                        case "utf-8":
+                            return "utf8";
+                        case "ucs2":
+                        case "ucs-2":
+                        case "utf16le":
+                        case "utf-16le":
+                            return "utf16le";
+                        case "latin1":
+                        case "binary":
+                            return "latin1";
+                        case "base64":
+                        case "ascii":
+                        case "hex":
+                            return t;
+                        default:
+                            if (e) return;
+                            t = ("" + t).toLowerCase(), e = !0
+                    }
+                }(t);
+                if ("string" != typeof e && (r.isEncoding === i || !i(t))) throw new Error("Unknown encoding: " + t);
+                return e || t
+            }(t), this.encoding) {
+                case "utf16le":
+                    this.text = s, this.end = c, e = 4;
+                    break;
+                case "utf8":
+                    this.fillLast = a, e = 4;
+                    break;
+                case "base64":
+                    this.text = f, this.end = l, e = 3;
+                    break;
+                default:
+                    return this.write = p, void(this.end = h)
+            }
+            this.lastNeed = 0, this.lastTotal = 0, this.lastChar = r.allocUnsafe(e)
+        }
+
+        function u(t) {
+            return t <= 127 ? 0 : t >> 5 == 6 ? 2 : t >> 4 == 14 ? 3 : t >> 3 == 30 ? 4 : t >> 6 == 2 ? -1 : -2
+        }
+
+        function a(t) {
+            var e = this.lastTotal - this.lastNeed,
+                n = function(t, e, n) {
+                    if (128 != (192 & e[0])) return t.lastNeed = 0, "�";
+                    if (t.lastNeed > 1 && e.length > 1) {
+                        if (128 != (192 & e[1])) return t.lastNeed = 1, "�";
+                        if (t.lastNeed > 2 && e.length > 2 && 128 != (192 & e[2])) return t.lastNeed = 2, "�"
+                    }
+                }(this, t);
+            return void 0 !== n ? n : this.lastNeed <= t.length ? (t.copy(this.lastChar, e, 0, this.lastNeed), this.lastChar.toString(this.encoding, 0, this.lastTotal)) : (t.copy(this.lastChar, e, 0, t.length), void(this.lastNeed -= t.length))
+        }
+
+        function s(t, e) {
+            if ((t.length - e) % 2 == 0) {
+                var n = t.toString("utf16le", e);
+                if (n) {
+                    var r = n.charCodeAt(n.length - 1);
+                    if (r >= 55296 && r <= 56319) return this.lastNeed = 2, this.lastTotal = 4, this.lastChar[0] = t[t.length - 2], this.lastChar[1] = t[t.length - 1], n.slice(0, -1)
+                }
+                return n
+            }
+            return this.lastNeed = 1, this.lastTotal = 2, this.lastChar[0] = t[t.length - 1], t.toString("utf16le", e, t.length - 1)
+        }
+
+        function c(t) {
+            var e = t && t.length ? this.write(t) : "";
+            if (this.lastNeed) {
+                var n = this.lastTotal - this.lastNeed;
+                return e + this.lastChar.toString("utf16le", 0, n)
+            }
+            return e
+        }
+
+        function f(t, e) {
+            var n = (t.length - e) % 3;
+            return 0 === n ? t.toString("base64", e) : (this.lastNeed = 3 - n, this.lastTotal = 3, 1 === n ? this.lastChar[0] = t[t.length - 1] : (this.lastChar[0] = t[t.length - 2], this.lastChar[1] = t[t.length - 1]), t.toString("base64", e, t.length - n))
+        }
+
+        function l(t) {
+            var e = t && t.length ? this.write(t) : "";
+            return this.lastNeed ? e + this.lastChar.toString("base64", 0, 3 - this.lastNeed) : e
+        }
+
+        function p(t) {
+            return t.toString(this.encoding)
+        }
+
+        function h(t) {
+            return t && t.length ? this.write(t) : ""
+        }
+        e.StringDecoder = o, o.prototype.write = function(t) {
+            if (0 === t.length) return "";
+            var e, n;
+            if (this.lastNeed) {
+                if (void 0 === (e = this.fillLast(t))) return "";
+                n = this.lastNeed, this.lastNeed = 0
+            } else n = 0;
+            return n < t.length ? e ? e + this.text(t, n) : this.text(t, n) : e || ""
+        }, o.prototype.end = function(t) {
+            var e = t && t.length ? this.write(t) : "";
+            return this.lastNeed ? e + "�" : e
+        }, o.prototype.text = function(t, e) {
+            var n = function(t, e, n) {
+                var r = e.length - 1;
+                if (r < n) return 0;
+                var i = u(e[r]);
+                if (i >= 0) return i > 0 && (t.lastNeed = i - 1), i;
+                if (--r < n || -2 === i) return 0;
+                if ((i = u(e[r])) >= 0) return i > 0 && (t.lastNeed = i - 2), i;
+                if (--r < n || -2 === i) return 0;
+                if ((i = u(e[r])) >= 0) return i > 0 && (2 === i ? i = 0 : t.lastNeed = i - 3), i;
+                return 0
+            }(this, t, e);
+            if (!this.lastNeed) return t.toString("utf8", e);
+            this.lastTotal = n;
+            var r = t.length - (n - this.lastNeed);
+            return t.copy(this.lastChar, 0, r), t.toString("utf8", e, r)
+        }, o.prototype.fillLast = function(t) {
+            if (this.lastNeed <= t.length) return t.copy(this.lastChar, this.lastTotal - this.lastNeed, 0, this.lastNeed), this.lastChar.toString(this.encoding, 0, this.lastTotal);
+            t.copy(this.lastChar, this.lastTotal - this.lastNeed, 0, t.length), this.lastNeed -= t.length
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = o;
+        var r = n(21),
+            i = n(51);
+
+        function o(t) {
+            if (!(this instanceof o)) return new o(t);
+            r.call(this, t), this._transformState = {
+                afterTransform: function(t, e) {
+                    var n = this._transformState;
+                    n.transforming = !1;
+                    var r = n.writecb;
+                    if (!r) return this.emit("error", new Error("write callback called multiple times"));
+                    n.writechunk = null, n.writecb = null, null != e && this.push(e), r(t);
+                    var i = this._readableState;
+                    i.reading = !1, (i.needReadable || i.length < i.highWaterMark) && this._read(i.highWaterMark)
+                }.bind(this),
+                needTransform: !1,
+                transforming: !1,
+                writecb: null,
+                writechunk: null,
+                writeencoding: null
+            }, this._readableState.needReadable = !0, this._readableState.sync = !1, t && ("function" == typeof t.transform && (this._transform = t.transform), "function" == typeof t.flush && (this._flush = t.flush)), this.on("prefinish", u)
+        }
+
+        function u() {
+            var t = this;
+            "function" == typeof this._flush ? this._flush(function(e, n) {
+                a(t, e, n)
+            }) : a(this, null, null)
+        }
+
+        function a(t, e, n) {
+            if (e) return t.emit("error", e);
+            if (null != n && t.push(n), t._writableState.length) throw new Error("Calling transform done when ws.length != 0");
+            if (t._transformState.transforming) throw new Error("Calling transform done when still transforming");
+            return t.push(null)
+        }
+        i.inherits = n(35), i.inherits(o, r), o.prototype.push = function(t, e) {
+            return this._transformState.needTransform = !1, r.prototype.push.call(this, t, e)
+        }, o.prototype._transform = function(t, e, n) {
+            throw new Error("_transform() is not implemented")
+        }, o.prototype._write = function(t, e, n) {
+            var r = this._transformState;
+            if (r.writecb = n, r.writechunk = t, r.writeencoding = e, !r.transforming) {
+                var i = this._readableState;
+                (r.needTransform || i.needReadable || i.length < i.highWaterMark) && this._read(i.highWaterMark)
+            }
+        }, o.prototype._read = function(t) {
+            var e = this._transformState;
+            null !== e.writechunk && e.writecb && !e.transforming ? (e.transforming = !0, this._transform(e.writechunk, e.writeencoding, e.afterTransform)) : e.needTransform = !0
+        }, o.prototype._destroy = function(t, e) {
+            var n = this;
+            r.prototype._destroy.call(this, t, function(t) {
+                e(t), n.emit("close")
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(22),
+            i = Array.prototype.forEach,
+            o = Object.create;
+        t.exports = function(t) {
+            var e = o(null);
+            return i.call(arguments, function(t) {
+                r(t) && function(t, e) {
+                    var n;
+                    for (n in t) e[n] = t[n]
+                }(Object(t), e)
+            }), e
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function() {}
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(23);
+        t.exports = function(t, e, n) {
+            var i;
+            return isNaN(t) ? (i = e) >= 0 ? n && i ? i - 1 : i : 1 : !1 !== t && r(t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(472)() ? Object.assign : n(473)
+    }, function(t, e, n) {
+        "use strict";
+        var r, i, o, u, a, s = n(23),
+            c = function(t, e) {
+                return e
+            };
+        try {
+            Object.defineProperty(c, "length", {
+                configurable: !0,
+                writable: !1,
+                enumerable: !1,
+                value: 1
+            })
+        } catch (t) {}
+        1 === c.length ? (r = {
+            configurable: !0,
+            writable: !1,
+            enumerable: !1
+        }, i = Object.defineProperty, t.exports = function(t, e) {
+            return e = s(e), t.length === e ? t : (r.value = e, i(t, "length", r))
+        }) : (u = n(198), a = [], o = function(t) {
+            var e, n = 0;
+            if (a[t]) return a[t];
+            for (e = []; t--;) e.push("a" + (++n).toString(36));
+            return new Function("fn", "return function (" + e.join(", ") + ") { return fn.apply(this, arguments); };")
+        }, t.exports = function(t, e) {
+            var n;
+            if (e = s(e), t.length === e) return t;
+            n = o(e)(t);
+            try {
+                u(n, t)
+            } catch (t) {}
+            return n
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(36),
+            i = Object.defineProperty,
+            o = Object.getOwnPropertyDescriptor,
+            u = Object.getOwnPropertyNames,
+            a = Object.getOwnPropertySymbols;
+        t.exports = function(t, e) {
+            var n, s = Object(r(e));
+            if (t = Object(r(t)), u(s).forEach(function(r) {
+                try {
+                    i(t, r, o(e, r))
+                } catch (t) {
+                    n = t
+                }
+            }), "function" == typeof a && a(s).forEach(function(r) {
+                try {
+                    i(t, r, o(e, r))
+                } catch (t) {
+                    n = t
+                }
+            }), void 0 !== n) throw n;
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(12),
+            i = n(81),
+            o = Function.prototype.call;
+        t.exports = function(t, e) {
+            var n = {},
+                u = arguments[2];
+            return r(e), i(t, function(t, r, i, a) {
+                n[r] = o.call(e, u, t, r, i, a)
+            }), n
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return !!t && ("object" == typeof t || "function" == typeof t) && "function" == typeof t.then
+        }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        }), e.parseYamlConfig = void 0;
+        var r, i = n(520),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            };
+        e.parseYamlConfig = function(t, e) {
+            try {
+                return o.default.safeLoad(t)
+            } catch (t) {
+                return e && e.errActions.newThrownErr(new Error(t)), {}
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(38);
+        t.exports = new r({
+            include: [n(203)]
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(38);
+        t.exports = new r({
+            include: [n(122)],
+            implicit: [n(527), n(528), n(529), n(530)]
+        })
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        }), e.loaded = e.TOGGLE_CONFIGS = e.UPDATE_CONFIGS = void 0;
+        var r, i = n(205),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            };
+        e.update = function(t, e) {
+            return {
+                type: u,
+                payload: (0, o.default)({}, t, e)
+            }
+        }, e.toggle = function(t) {
+            return {
+                type: a,
+                payload: t
+            }
+        };
+        var u = e.UPDATE_CONFIGS = "configs_update",
+            a = e.TOGGLE_CONFIGS = "configs_toggle";
+        e.loaded = function() {
+            return function() {}
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0;
+        var r, i = n(152),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            };
+        e.default = function(t, e, n) {
+            return e in t ? (0, o.default)(t, e, {
+                value: n,
+                enumerable: !0,
+                configurable: !0,
+                writable: !0
+            }) : t[e] = n, t
+        }
+    }, function(t, e, n) {
+        n(207), t.exports = n(280)
+    }, function(t, e, n) {
+        "use strict";
+        var r, i = n(123);
+        void 0 === ((r = i) && r.__esModule ? r : {
+            default: r
+        }).default.Promise && n(222), String.prototype.startsWith || n(251)
+    }, function(t, e, n) {
+        n(85), n(96), t.exports = n(220)
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(210),
+            i = n(211),
+            o = n(39),
+            u = n(25);
+        t.exports = n(125)(Array, "Array", function(t, e) {
+            this._t = u(t), this._i = 0, this._k = e
+        }, function() {
+            var t = this._t,
+                e = this._k,
+                n = this._i++;
+            return !t || n >= t.length ? (this._t = void 0, i(1)) : i(0, "keys" == e ? n : "values" == e ? t[n] : [n, t[n]])
+        }, "values"), o.Arguments = o.Array, r("keys"), r("values"), r("entries")
+    }, function(t, e) {
+        t.exports = function() {}
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return {
+                value: e,
+                done: !!t
+            }
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            if ("function" != typeof t) throw TypeError(t + " is not a function!");
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(90),
+            i = n(54),
+            o = n(95),
+            u = {};
+        n(26)(u, n(6)("iterator"), function() {
+            return this
+        }), t.exports = function(t, e, n) {
+            t.prototype = r(u, {
+                next: i(1, n)
+            }), o(t, e + " Iterator")
+        }
+    }, function(t, e, n) {
+        var r = n(14),
+            i = n(27),
+            o = n(40);
+        t.exports = n(15) ? Object.defineProperties : function(t, e) {
+            i(t);
+            for (var n, u = o(e), a = u.length, s = 0; a > s;) r.f(t, n = u[s++], e[n]);
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(25),
+            i = n(216),
+            o = n(217);
+        t.exports = function(t) {
+            return function(e, n, u) {
+                var a, s = r(e),
+                    c = i(s.length),
+                    f = o(u, c);
+                if (t && n != n) {
+                    for (; c > f;)
+                        if ((a = s[f++]) != a) return !0
+                } else
+                    for (; c > f; f++)
+                        if ((t || f in s) && s[f] === n) return t || f || 0; return !t && -1
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(91),
+            i = Math.min;
+        t.exports = function(t) {
+            return t > 0 ? i(r(t), 9007199254740991) : 0
+        }
+    }, function(t, e, n) {
+        var r = n(91),
+            i = Math.max,
+            o = Math.min;
+        t.exports = function(t, e) {
+            return (t = r(t)) < 0 ? i(t + e, 0) : o(t, e)
+        }
+    }, function(t, e, n) {
+        var r = n(9).document;
+        t.exports = r && r.documentElement
+    }, function(t, e, n) {
+        var r = n(91),
+            i = n(87);
+        t.exports = function(t) {
+            return function(e, n) {
+                var o, u, a = String(i(e)),
+                    s = r(n),
+                    c = a.length;
+                return s < 0 || s >= c ? t ? "" : void 0 : (o = a.charCodeAt(s)) < 55296 || o > 56319 || s + 1 === c || (u = a.charCodeAt(s + 1)) < 56320 || u > 57343 ? t ? a.charAt(s) : o : t ? a.slice(s, s + 2) : u - 56320 + (o - 55296 << 10) + 65536
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(27),
+            i = n(221);
+        t.exports = n(2).getIterator = function(t) {
+            var e = i(t);
+            if ("function" != typeof e) throw TypeError(t + " is not iterable!");
+            return r(e.call(t))
+        }
+    }, function(t, e, n) {
+        var r = n(132),
+            i = n(6)("iterator"),
+            o = n(39);
+        t.exports = n(2).getIteratorMethod = function(t) {
+            if (void 0 != t) return t[i] || t["@@iterator"] || o[r(t)]
+        }
+    }, function(t, e, n) {
+        n(223), n(135), n(234), n(238), n(249), n(250), t.exports = n(19).Promise
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(97),
+            i = {};
+        i[n(1)("toStringTag")] = "z", i + "" != "[object z]" && n(30)(Object.prototype, "toString", function() {
+            return "[object " + r(this) + "]"
+        }, !0)
+    }, function(t, e, n) {
+        t.exports = !n(42) && !n(43)(function() {
+            return 7 != Object.defineProperty(n(99)("div"), "a", {
+                get: function() {
+                    return 7
+                }
+            }).a
+        })
+    }, function(t, e, n) {
+        var r = n(31);
+        t.exports = function(t, e) {
+            if (!r(t)) return t;
+            var n, i;
+            if (e && "function" == typeof(n = t.toString) && !r(i = n.call(t))) return i;
+            if ("function" == typeof(n = t.valueOf) && !r(i = n.call(t))) return i;
+            if (!e && "function" == typeof(n = t.toString) && !r(i = n.call(t))) return i;
+            throw TypeError("Can't convert object to primitive value")
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(227),
+            i = n(134),
+            o = n(101),
+            u = {};
+        n(17)(u, n(1)("iterator"), function() {
+            return this
+        }), t.exports = function(t, e, n) {
+            t.prototype = r(u, {
+                next: i(1, n)
+            }), o(t, e + " Iterator")
+        }
+    }, function(t, e, n) {
+        var r = n(18),
+            i = n(228),
+            o = n(141),
+            u = n(100)("IE_PROTO"),
+            a = function() {},
+            s = function() {
+                var t, e = n(99)("iframe"),
+                    r = o.length;
+                for (e.style.display = "none", n(142).appendChild(e), e.src = "javascript:", (t = e.contentWindow.document).open(), t.write("<script>document.F=Object<\/script>"), t.close(), s = t.F; r--;) delete s.prototype[o[r]];
+                return s()
+            };
+        t.exports = Object.create || function(t, e) {
+            var n;
+            return null !== t ? (a.prototype = r(t), n = new a, a.prototype = null, n[u] = t) : n = s(), void 0 === e ? n : i(n, e)
+        }
+    }, function(t, e, n) {
+        var r = n(57),
+            i = n(18),
+            o = n(139);
+        t.exports = n(42) ? Object.defineProperties : function(t, e) {
+            i(t);
+            for (var n, u = o(e), a = u.length, s = 0; a > s;) r.f(t, n = u[s++], e[n]);
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(58),
+            i = n(62),
+            o = n(231)(!1),
+            u = n(100)("IE_PROTO");
+        t.exports = function(t, e) {
+            var n, a = i(t),
+                s = 0,
+                c = [];
+            for (n in a) n != u && r(a, n) && c.push(n);
+            for (; e.length > s;) r(a, n = e[s++]) && (~o(c, n) || c.push(n));
+            return c
+        }
+    }, function(t, e, n) {
+        var r = n(41);
+        t.exports = Object("z").propertyIsEnumerable(0) ? Object : function(t) {
+            return "String" == r(t) ? t.split("") : Object(t)
+        }
+    }, function(t, e, n) {
+        var r = n(62),
+            i = n(45),
+            o = n(140);
+        t.exports = function(t) {
+            return function(e, n, u) {
+                var a, s = r(e),
+                    c = i(s.length),
+                    f = o(u, c);
+                if (t && n != n) {
+                    for (; c > f;)
+                        if ((a = s[f++]) != a) return !0
+                } else
+                    for (; c > f; f++)
+                        if ((t || f in s) && s[f] === n) return t || f || 0; return !t && -1
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(58),
+            i = n(233),
+            o = n(100)("IE_PROTO"),
+            u = Object.prototype;
+        t.exports = Object.getPrototypeOf || function(t) {
+            return t = i(t), r(t, o) ? t[o] : "function" == typeof t.constructor && t instanceof t.constructor ? t.constructor.prototype : t instanceof Object ? u : null
+        }
+    }, function(t, e, n) {
+        var r = n(10);
+        t.exports = function(t) {
+            return Object(r(t))
+        }
+    }, function(t, e, n) {
+        for (var r = n(235), i = n(139), o = n(30), u = n(5), a = n(17), s = n(44), c = n(1), f = c("iterator"), l = c("toStringTag"), p = s.Array, h = {
+            CSSRuleList: !0,
+            CSSStyleDeclaration: !1,
+            CSSValueList: !1,
+            ClientRectList: !1,
+            DOMRectList: !1,
+            DOMStringList: !1,
+            DOMTokenList: !0,
+            DataTransferItemList: !1,
+            FileList: !1,
+            HTMLAllCollection: !1,
+            HTMLCollection: !1,
+            HTMLFormElement: !1,
+            HTMLSelectElement: !1,
+            MediaList: !0,
+            MimeTypeArray: !1,
+            NamedNodeMap: !1,
+            NodeList: !0,
+            PaintRequestList: !1,
+            Plugin: !1,
+            PluginArray: !1,
+            SVGLengthList: !1,
+            SVGNumberList: !1,
+            SVGPathSegList: !1,
+            SVGPointList: !1,
+            SVGStringList: !1,
+            SVGTransformList: !1,
+            SourceBufferList: !1,
+            StyleSheetList: !0,
+            TextTrackCueList: !1,
+            TextTrackList: !1,
+            TouchList: !1
+        }, d = i(h), v = 0; v < d.length; v++) {
+            var y, g = d[v],
+                m = h[g],
+                _ = u[g],
+                b = _ && _.prototype;
+            if (b && (b[f] || a(b, f, p), b[l] || a(b, l, g), s[g] = p, m))
+                for (y in r) b[y] || o(b, y, r[y], !0)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(236),
+            i = n(237),
+            o = n(44),
+            u = n(62);
+        t.exports = n(137)(Array, "Array", function(t, e) {
+            this._t = u(t), this._i = 0, this._k = e
+        }, function() {
+            var t = this._t,
+                e = this._k,
+                n = this._i++;
+            return !t || n >= t.length ? (this._t = void 0, i(1)) : i(0, "keys" == e ? n : "values" == e ? t[n] : [n, t[n]])
+        }, "values"), o.Arguments = o.Array, r("keys"), r("values"), r("entries")
+    }, function(t, e, n) {
+        var r = n(1)("unscopables"),
+            i = Array.prototype;
+        void 0 == i[r] && n(17)(i, r, {}), t.exports = function(t) {
+            i[r][t] = !0
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return {
+                value: e,
+                done: !!t
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r, i, o, u, a = n(138),
+            s = n(5),
+            c = n(60),
+            f = n(97),
+            l = n(3),
+            p = n(31),
+            h = n(61),
+            d = n(239),
+            v = n(240),
+            y = n(143),
+            g = n(144).set,
+            m = n(245)(),
+            _ = n(102),
+            b = n(145),
+            w = n(146),
+            x = s.TypeError,
+            S = s.process,
+            E = s.Promise,
+            A = "process" == f(S),
+            O = function() {},
+            j = i = _.f,
+            k = !! function() {
+                try {
+                    var t = E.resolve(1),
+                        e = (t.constructor = {})[n(1)("species")] = function(t) {
+                            t(O, O)
+                        };
+                    return (A || "function" == typeof PromiseRejectionEvent) && t.then(O) instanceof e
+                } catch (t) {}
+            }(),
+            I = function(t) {
+                var e;
+                return !(!p(t) || "function" != typeof(e = t.then)) && e
+            },
+            M = function(t, e) {
+                if (!t._n) {
+                    t._n = !0;
+                    var n = t._c;
+                    m(function() {
+                        for (var r = t._v, i = 1 == t._s, o = 0, u = function(e) {
+                            var n, o, u, a = i ? e.ok : e.fail,
+                                s = e.resolve,
+                                c = e.reject,
+                                f = e.domain;
+                            try {
+                                a ? (i || (2 == t._h && P(t), t._h = 1), !0 === a ? n = r : (f && f.enter(), n = a(r), f && (f.exit(), u = !0)), n === e.promise ? c(x("Promise-chain cycle")) : (o = I(n)) ? o.call(n, s, c) : s(n)) : c(r)
+                            } catch (t) {
+                                f && !u && f.exit(), c(t)
+                            }
+                        }; n.length > o;) u(n[o++]);
+                        t._c = [], t._n = !1, e && !t._h && T(t)
+                    })
+                }
+            },
+            T = function(t) {
+                g.call(s, function() {
+                    var e, n, r, i = t._v,
+                        o = C(t);
+                    if (o && (e = b(function() {
+                        A ? S.emit("unhandledRejection", i, t) : (n = s.onunhandledrejection) ? n({
+                            promise: t,
+                            reason: i
+                        }) : (r = s.console) && r.error && r.error("Unhandled promise rejection", i)
+                    }), t._h = A || C(t) ? 2 : 1), t._a = void 0, o && e.e) throw e.v
+                })
+            },
+            C = function(t) {
+                return 1 !== t._h && 0 === (t._a || t._c).length
+            },
+            P = function(t) {
+                g.call(s, function() {
+                    var e;
+                    A ? S.emit("rejectionHandled", t) : (e = s.onrejectionhandled) && e({
+                        promise: t,
+                        reason: t._v
+                    })
+                })
+            },
+            R = function(t) {
+                var e = this;
+                e._d || (e._d = !0, (e = e._w || e)._v = t, e._s = 2, e._a || (e._a = e._c.slice()), M(e, !0))
+            },
+            N = function(t) {
+                var e, n = this;
+                if (!n._d) {
+                    n._d = !0, n = n._w || n;
+                    try {
+                        if (n === t) throw x("Promise can't be resolved itself");
+                        (e = I(t)) ? m(function() {
+                            var r = {
+                                _w: n,
+                                _d: !1
+                            };
+                            try {
+                                e.call(t, c(N, r, 1), c(R, r, 1))
+                            } catch (t) {
+                                R.call(r, t)
+                            }
+                        }): (n._v = t, n._s = 1, M(n, !1))
+                    } catch (t) {
+                        R.call({
+                            _w: n,
+                            _d: !1
+                        }, t)
+                    }
+                }
+            };
+        k || (E = function(t) {
+            d(this, E, "Promise", "_h"), h(t), r.call(this);
+            try {
+                t(c(N, this, 1), c(R, this, 1))
+            } catch (t) {
+                R.call(this, t)
+            }
+        }, (r = function(t) {
+            this._c = [], this._a = void 0, this._s = 0, this._d = !1, this._v = void 0, this._h = 0, this._n = !1
+        }).prototype = n(246)(E.prototype, {
+            then: function(t, e) {
+                var n = j(y(this, E));
+                return n.ok = "function" != typeof t || t, n.fail = "function" == typeof e && e, n.domain = A ? S.domain : void 0, this._c.push(n), this._a && this._a.push(n), this._s && M(this, !1), n.promise
+            },
+            catch: function(t) {
+                return this.then(void 0, t)
+            }
+        }), o = function() {
+            var t = new r;
+            this.promise = t, this.resolve = c(N, t, 1), this.reject = c(R, t, 1)
+        }, _.f = j = function(t) {
+            return t === E || t === u ? new o(t) : i(t)
+        }), l(l.G + l.W + l.F * !k, {
+            Promise: E
+        }), n(101)(E, "Promise"), n(247)("Promise"), u = n(19).Promise, l(l.S + l.F * !k, "Promise", {
+            reject: function(t) {
+                var e = j(this);
+                return (0, e.reject)(t), e.promise
+            }
+        }), l(l.S + l.F * (a || !k), "Promise", {
+            resolve: function(t) {
+                return w(a && this === u ? E : this, t)
+            }
+        }), l(l.S + l.F * !(k && n(248)(function(t) {
+            E.all(t).catch(O)
+        })), "Promise", {
+            all: function(t) {
+                var e = this,
+                    n = j(e),
+                    r = n.resolve,
+                    i = n.reject,
+                    o = b(function() {
+                        var n = [],
+                            o = 0,
+                            u = 1;
+                        v(t, !1, function(t) {
+                            var a = o++,
+                                s = !1;
+                            n.push(void 0), u++, e.resolve(t).then(function(t) {
+                                s || (s = !0, n[a] = t, --u || r(n))
+                            }, i)
+                        }), --u || r(n)
+                    });
+                return o.e && i(o.v), n.promise
+            },
+            race: function(t) {
+                var e = this,
+                    n = j(e),
+                    r = n.reject,
+                    i = b(function() {
+                        v(t, !1, function(t) {
+                            e.resolve(t).then(n.resolve, r)
+                        })
+                    });
+                return i.e && r(i.v), n.promise
+            }
+        })
+    }, function(t, e) {
+        t.exports = function(t, e, n, r) {
+            if (!(t instanceof e) || void 0 !== r && r in t) throw TypeError(n + ": incorrect invocation!");
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(60),
+            i = n(241),
+            o = n(242),
+            u = n(18),
+            a = n(45),
+            s = n(243),
+            c = {},
+            f = {};
+        (e = t.exports = function(t, e, n, l, p) {
+            var h, d, v, y, g = p ? function() {
+                    return t
+                } : s(t),
+                m = r(n, l, e ? 2 : 1),
+                _ = 0;
+            if ("function" != typeof g) throw TypeError(t + " is not iterable!");
+            if (o(g)) {
+                for (h = a(t.length); h > _; _++)
+                    if ((y = e ? m(u(d = t[_])[0], d[1]) : m(t[_])) === c || y === f) return y
+            } else
+                for (v = g.call(t); !(d = v.next()).done;)
+                    if ((y = i(v, m, d.value, e)) === c || y === f) return y
+        }).BREAK = c, e.RETURN = f
+    }, function(t, e, n) {
+        var r = n(18);
+        t.exports = function(t, e, n, i) {
+            try {
+                return i ? e(r(n)[0], n[1]) : e(n)
+            } catch (e) {
+                var o = t.return;
+                throw void 0 !== o && r(o.call(t)), e
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(44),
+            i = n(1)("iterator"),
+            o = Array.prototype;
+        t.exports = function(t) {
+            return void 0 !== t && (r.Array === t || o[i] === t)
+        }
+    }, function(t, e, n) {
+        var r = n(97),
+            i = n(1)("iterator"),
+            o = n(44);
+        t.exports = n(19).getIteratorMethod = function(t) {
+            if (void 0 != t) return t[i] || t["@@iterator"] || o[r(t)]
+        }
+    }, function(t, e) {
+        t.exports = function(t, e, n) {
+            var r = void 0 === n;
+            switch (e.length) {
+                case 0:
+                    return r ? t() : t.call(n);
+                case 1:
+                    return r ? t(e[0]) : t.call(n, e[0]);
+                case 2:
+                    return r ? t(e[0], e[1]) : t.call(n, e[0], e[1]);
+                case 3:
+                    return r ? t(e[0], e[1], e[2]) : t.call(n, e[0], e[1], e[2]);
+                case 4:
+                    return r ? t(e[0], e[1], e[2], e[3]) : t.call(n, e[0], e[1], e[2], e[3])
+            }
+            return t.apply(n, e)
+        }
+    }, function(t, e, n) {
+        var r = n(5),
+            i = n(144).set,
+            o = r.MutationObserver || r.WebKitMutationObserver,
+            u = r.process,
+            a = r.Promise,
+            s = "process" == n(41)(u);
+        t.exports = function() {
+            var t, e, n, c = function() {
+                var r, i;
+                for (s && (r = u.domain) && r.exit(); t;) {
+                    i = t.fn, t = t.next;
+                    try {
+                        i()
+                    } catch (r) {
+                        throw t ? n() : e = void 0, r
+                    }
+                }
+                e = void 0, r && r.enter()
+            };
+            if (s) n = function() {
+                u.nextTick(c)
+            };
+            else if (!o || r.navigator && r.navigator.standalone)
+                if (a && a.resolve) {
+                    var f = a.resolve();
+                    n = function() {
+                        f.then(c)
+                    }
+                } else n = function() {
+                    i.call(r, c)
+                };
+            else {
+                var l = !0,
+                    p = document.createTextNode("");
+                new o(c).observe(p, {
+                    characterData: !0
+                }), n = function() {
+                    p.data = l = !l
+                }
+            }
+            return function(r) {
+                var i = {
+                    fn: r,
+                    next: void 0
+                };
+                e && (e.next = i), t || (t = i, n()), e = i
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(30);
+        t.exports = function(t, e, n) {
+            for (var i in e) r(t, i, e[i], n);
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(5),
+            i = n(57),
+            o = n(42),
+            u = n(1)("species");
+        t.exports = function(t) {
+            var e = r[t];
+            o && e && !e[u] && i.f(e, u, {
+                configurable: !0,
+                get: function() {
+                    return this
+                }
+            })
+        }
+    }, function(t, e, n) {
+        var r = n(1)("iterator"),
+            i = !1;
+        try {
+            var o = [7][r]();
+            o.return = function() {
+                i = !0
+            }, Array.from(o, function() {
+                throw 2
+            })
+        } catch (t) {}
+        t.exports = function(t, e) {
+            if (!e && !i) return !1;
+            var n = !1;
+            try {
+                var o = [7],
+                    u = o[r]();
+                u.next = function() {
+                    return {
+                        done: n = !0
+                    }
+                }, o[r] = function() {
+                    return u
+                }, t(o)
+            } catch (t) {}
+            return n
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(3),
+            i = n(19),
+            o = n(5),
+            u = n(143),
+            a = n(146);
+        r(r.P + r.R, "Promise", {
+            finally: function(t) {
+                var e = u(this, i.Promise || o.Promise),
+                    n = "function" == typeof t;
+                return this.then(n ? function(n) {
+                    return a(e, t()).then(function() {
+                        return n
+                    })
+                } : t, n ? function(n) {
+                    return a(e, t()).then(function() {
+                        throw n
+                    })
+                } : t)
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(3),
+            i = n(102),
+            o = n(145);
+        r(r.S, "Promise", {
+            try: function(t) {
+                var e = i.f(this),
+                    n = o(t);
+                return (n.e ? e.reject : e.resolve)(n.v), e.promise
+            }
+        })
+    }, function(t, e, n) {
+        n(252), n(253), n(254), n(135), n(257), n(258), n(259), n(260), n(262), n(263), n(264), n(265), n(266), n(267), n(268), n(269), n(270), n(271), n(272), n(273), n(274), n(275), n(276), n(277), n(278), n(279), t.exports = n(19).String
+    }, function(t, e, n) {
+        var r = n(3),
+            i = n(140),
+            o = String.fromCharCode,
+            u = String.fromCodePoint;
+        r(r.S + r.F * (!!u && 1 != u.length), "String", {
+            fromCodePoint: function(t) {
+                for (var e, n = [], r = arguments.length, u = 0; r > u;) {
+                    if (e = +arguments[u++], i(e, 1114111) !== e) throw RangeError(e + " is not a valid code point");
+                    n.push(e < 65536 ? o(e) : o(55296 + ((e -= 65536) >> 10), e % 1024 + 56320))
+                }
+                return n.join("")
+            }
+        })
+    }, function(t, e, n) {
+        var r = n(3),
+            i = n(62),
+            o = n(45);
+        r(r.S, "String", {
+            raw: function(t) {
+                for (var e = i(t.raw), n = o(e.length), r = arguments.length, u = [], a = 0; n > a;) u.push(String(e[a++])), a < r && u.push(String(arguments[a]));
+                return u.join("")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(255)("trim", function(t) {
+            return function() {
+                return t(this, 3)
+            }
+        })
+    }, function(t, e, n) {
+        var r = n(3),
+            i = n(10),
+            o = n(43),
+            u = n(256),
+            a = "[" + u + "]",
+            s = RegExp("^" + a + a + "*"),
+            c = RegExp(a + a + "*$"),
+            f = function(t, e, n) {
+                var i = {},
+                    a = o(function() {
+                        return !!u[t]() || "​" != "​" [t]()
+                    }),
+                    s = i[t] = a ? e(l) : u[t];
+                n && (i[n] = s), r(r.P + r.F * a, "String", i)
+            },
+            l = f.trim = function(t, e) {
+                return t = String(i(t)), 1 & e && (t = t.replace(s, "")), 2 & e && (t = t.replace(c, "")), t
+            };
+        t.exports = f
+    }, function(t, e) {
+        t.exports = "\t\n\v\f\r   ᠎             　\u2028\u2029\ufeff"
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(3),
+            i = n(136)(!1);
+        r(r.P, "String", {
+            codePointAt: function(t) {
+                return i(this, t)
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(3),
+            i = n(45),
+            o = n(103),
+            u = "".endsWith;
+        r(r.P + r.F * n(104)("endsWith"), "String", {
+            endsWith: function(t) {
+                var e = o(this, t, "endsWith"),
+                    n = arguments.length > 1 ? arguments[1] : void 0,
+                    r = i(e.length),
+                    a = void 0 === n ? r : Math.min(i(n), r),
+                    s = String(t);
+                return u ? u.call(e, s, a) : e.slice(a - s.length, a) === s
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(3),
+            i = n(103);
+        r(r.P + r.F * n(104)("includes"), "String", {
+            includes: function(t) {
+                return !!~i(this, t, "includes").indexOf(t, arguments.length > 1 ? arguments[1] : void 0)
+            }
+        })
+    }, function(t, e, n) {
+        var r = n(3);
+        r(r.P, "String", {
+            repeat: n(261)
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(59),
+            i = n(10);
+        t.exports = function(t) {
+            var e = String(i(this)),
+                n = "",
+                o = r(t);
+            if (o < 0 || o == 1 / 0) throw RangeError("Count can't be negative");
+            for (; o > 0;
+                   (o >>>= 1) && (e += e)) 1 & o && (n += e);
+            return n
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(3),
+            i = n(45),
+            o = n(103),
+            u = "".startsWith;
+        r(r.P + r.F * n(104)("startsWith"), "String", {
+            startsWith: function(t) {
+                var e = o(this, t, "startsWith"),
+                    n = i(Math.min(arguments.length > 1 ? arguments[1] : void 0, e.length)),
+                    r = String(t);
+                return u ? u.call(e, r, n) : e.slice(n, n + r.length) === r
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("anchor", function(t) {
+            return function(e) {
+                return t(this, "a", "name", e)
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("big", function(t) {
+            return function() {
+                return t(this, "big", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("blink", function(t) {
+            return function() {
+                return t(this, "blink", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("bold", function(t) {
+            return function() {
+                return t(this, "b", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("fixed", function(t) {
+            return function() {
+                return t(this, "tt", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("fontcolor", function(t) {
+            return function(e) {
+                return t(this, "font", "color", e)
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("fontsize", function(t) {
+            return function(e) {
+                return t(this, "font", "size", e)
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("italics", function(t) {
+            return function() {
+                return t(this, "i", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("link", function(t) {
+            return function(e) {
+                return t(this, "a", "href", e)
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("small", function(t) {
+            return function() {
+                return t(this, "small", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("strike", function(t) {
+            return function() {
+                return t(this, "strike", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("sub", function(t) {
+            return function() {
+                return t(this, "sub", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        n(4)("sup", function(t) {
+            return function() {
+                return t(this, "sup", "", "")
+            }
+        })
+    }, function(t, e, n) {
+        n(63)("match", 1, function(t, e, n) {
+            return [function(n) {
+                "use strict";
+                var r = t(this),
+                    i = void 0 == n ? void 0 : n[e];
+                return void 0 !== i ? i.call(n, r) : new RegExp(n)[e](String(r))
+            }, n]
+        })
+    }, function(t, e, n) {
+        n(63)("replace", 2, function(t, e, n) {
+            return [function(r, i) {
+                "use strict";
+                var o = t(this),
+                    u = void 0 == r ? void 0 : r[e];
+                return void 0 !== u ? u.call(r, o, i) : n.call(String(o), r, i)
+            }, n]
+        })
+    }, function(t, e, n) {
+        n(63)("search", 1, function(t, e, n) {
+            return [function(n) {
+                "use strict";
+                var r = t(this),
+                    i = void 0 == n ? void 0 : n[e];
+                return void 0 !== i ? i.call(n, r) : new RegExp(n)[e](String(r))
+            }, n]
+        })
+    }, function(t, e, n) {
+        n(63)("split", 2, function(t, e, r) {
+            "use strict";
+            var i = n(147),
+                o = r,
+                u = [].push;
+            if ("c" == "abbc".split(/(b)*/)[1] || 4 != "test".split(/(?:)/, -1).length || 2 != "ab".split(/(?:ab)*/).length || 4 != ".".split(/(.?)(.?)/).length || ".".split(/()()/).length > 1 || "".split(/.?/).length) {
+                var a = void 0 === /()??/.exec("")[1];
+                r = function(t, e) {
+                    var n = String(this);
+                    if (void 0 === t && 0 === e) return [];
+                    if (!i(t)) return o.call(n, t, e);
+                    var r, s, c, f, l, p = [],
+                        h = (t.ignoreCase ? "i" : "") + (t.multiline ? "m" : "") + (t.unicode ? "u" : "") + (t.sticky ? "y" : ""),
+                        d = 0,
+                        v = void 0 === e ? 4294967295 : e >>> 0,
+                        y = new RegExp(t.source, h + "g");
+                    for (a || (r = new RegExp("^" + y.source + "$(?!\\s)", h));
+                         (s = y.exec(n)) && !((c = s.index + s[0].length) > d && (p.push(n.slice(d, s.index)), !a && s.length > 1 && s[0].replace(r, function() {
+                             for (l = 1; l < arguments.length - 2; l++) void 0 === arguments[l] && (s[l] = void 0)
+                         }), s.length > 1 && s.index < n.length && u.apply(p, s.slice(1)), f = s[0].length, d = c, p.length >= v));) y.lastIndex === s.index && y.lastIndex++;
+                    return d === n.length ? !f && y.test("") || p.push("") : p.push(n.slice(d)), p.length > v ? p.slice(0, v) : p
+                }
+            } else "0".split(void 0, 0).length && (r = function(t, e) {
+                return void 0 === t && 0 === e ? [] : o.call(this, t, e)
+            });
+            return [function(n, i) {
+                var o = t(this),
+                    u = void 0 == n ? void 0 : n[e];
+                return void 0 !== u ? u.call(n, o, i) : r.call(String(o), n, i)
+            }, r]
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = u(n(281)),
+            i = u(n(322)),
+            o = u(n(518));
+
+        function u(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        var a = [i.default, o.default, function() {
+            return {
+                components: {
+                    StandaloneLayout: r.default
+                }
+            }
+        }];
+        t.exports = a
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        });
+        var r = c(n(148)),
+            i = c(n(150)),
+            o = c(n(151)),
+            u = c(n(153)),
+            a = c(n(156)),
+            s = c(n(157));
+        c(n(165));
+
+        function c(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        var f = function(t) {
+            function e() {
+                return (0, i.default)(this, e), (0, u.default)(this, (e.__proto__ || (0, r.default)(e)).apply(this, arguments))
+            }
+            return (0, a.default)(e, t), (0, o.default)(e, [{
+                key: "render",
+                value: function() {
+                    var t = this.props,
+                        e = t.getComponent,
+                        n = t.specSelectors,
+                        r = t.errSelectors,
+                        i = e("Container"),
+                        o = e("Row"),
+                        u = e("Col"),
+                        a = e("errors", !0),
+                        c = e("Topbar", !0),
+                        f = e("BaseLayout", !0),
+                        l = e("onlineValidatorBadge", !0),
+                        p = n.loadingStatus(),
+                        h = r.lastError(),
+                        d = h ? h.get("message") : "";
+                    return s.default.createElement(i, {
+                        className: "swagger-ui"
+                    }, c ? s.default.createElement(c, null) : null, "loading" === p && s.default.createElement("div", {
+                        className: "info"
+                    }, s.default.createElement("div", {
+                        className: "loading-container"
+                    }, s.default.createElement("div", {
+                        className: "loading"
+                    }))), "failed" === p && s.default.createElement("div", {
+                        className: "info"
+                    }, s.default.createElement("div", {
+                        className: "loading-container"
+                    }, s.default.createElement("h4", {
+                        className: "title"
+                    }, "Failed to load API definition."), s.default.createElement(a, null))), "failedConfig" === p && s.default.createElement("div", {
+                        className: "info",
+                        style: {
+                            maxWidth: "880px",
+                            marginLeft: "auto",
+                            marginRight: "auto",
+                            textAlign: "center"
+                        }
+                    }, s.default.createElement("div", {
+                        className: "loading-container"
+                    }, s.default.createElement("h4", {
+                        className: "title"
+                    }, "Failed to load remote configuration."), s.default.createElement("p", null, d))), !p || "success" === p && s.default.createElement(f, null), s.default.createElement(o, null, s.default.createElement(u, null, s.default.createElement(l, null))))
+                }
+            }]), e
+        }(s.default.Component);
+        e.default = f
+    }, function(t, e, n) {
+        n(283), t.exports = n(2).Object.getPrototypeOf
+    }, function(t, e, n) {
+        var r = n(56),
+            i = n(131);
+        n(149)("getPrototypeOf", function() {
+            return function(t) {
+                return i(r(t))
+            }
+        })
+    }, function(t, e, n) {
+        n(285);
+        var r = n(2).Object;
+        t.exports = function(t, e, n) {
+            return r.defineProperty(t, e, n)
+        }
+    }, function(t, e, n) {
+        var r = n(13);
+        r(r.S + r.F * !n(15), "Object", {
+            defineProperty: n(14).f
+        })
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(287),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(96), n(85), t.exports = n(106).f("iterator")
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(289),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(290), n(295), n(296), n(297), t.exports = n(2).Symbol
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(9),
+            i = n(16),
+            o = n(15),
+            u = n(13),
+            a = n(129),
+            s = n(291).KEY,
+            c = n(29),
+            f = n(93),
+            l = n(95),
+            p = n(55),
+            h = n(6),
+            d = n(106),
+            v = n(107),
+            y = n(292),
+            g = n(293),
+            m = n(27),
+            _ = n(28),
+            b = n(25),
+            w = n(89),
+            x = n(54),
+            S = n(90),
+            E = n(294),
+            A = n(155),
+            O = n(14),
+            j = n(40),
+            k = A.f,
+            I = O.f,
+            M = E.f,
+            T = r.Symbol,
+            C = r.JSON,
+            P = C && C.stringify,
+            R = h("_hidden"),
+            N = h("toPrimitive"),
+            L = {}.propertyIsEnumerable,
+            D = f("symbol-registry"),
+            z = f("symbols"),
+            F = f("op-symbols"),
+            U = Object.prototype,
+            B = "function" == typeof T,
+            q = r.QObject,
+            Y = !q || !q.prototype || !q.prototype.findChild,
+            W = o && c(function() {
+                return 7 != S(I({}, "a", {
+                    get: function() {
+                        return I(this, "a", {
+                            value: 7
+                        }).a
+                    }
+                })).a
+            }) ? function(t, e, n) {
+                var r = k(U, e);
+                r && delete U[e], I(t, e, n), r && t !== U && I(U, e, r)
+            } : I,
+            V = function(t) {
+                var e = z[t] = S(T.prototype);
+                return e._k = t, e
+            },
+            K = B && "symbol" == typeof T.iterator ? function(t) {
+                return "symbol" == typeof t
+            } : function(t) {
+                return t instanceof T
+            },
+            G = function(t, e, n) {
+                return t === U && G(F, e, n), m(t), e = w(e, !0), m(n), i(z, e) ? (n.enumerable ? (i(t, R) && t[R][e] && (t[R][e] = !1), n = S(n, {
+                    enumerable: x(0, !1)
+                })) : (i(t, R) || I(t, R, x(1, {})), t[R][e] = !0), W(t, e, n)) : I(t, e, n)
+            },
+            $ = function(t, e) {
+                m(t);
+                for (var n, r = y(e = b(e)), i = 0, o = r.length; o > i;) G(t, n = r[i++], e[n]);
+                return t
+            },
+            H = function(t) {
+                var e = L.call(this, t = w(t, !0));
+                return !(this === U && i(z, t) && !i(F, t)) && (!(e || !i(this, t) || !i(z, t) || i(this, R) && this[R][t]) || e)
+            },
+            J = function(t, e) {
+                if (t = b(t), e = w(e, !0), t !== U || !i(z, e) || i(F, e)) {
+                    var n = k(t, e);
+                    return !n || !i(z, e) || i(t, R) && t[R][e] || (n.enumerable = !0), n
+                }
+            },
+            Z = function(t) {
+                for (var e, n = M(b(t)), r = [], o = 0; n.length > o;) i(z, e = n[o++]) || e == R || e == s || r.push(e);
+                return r
+            },
+            X = function(t) {
+                for (var e, n = t === U, r = M(n ? F : b(t)), o = [], u = 0; r.length > u;) !i(z, e = r[u++]) || n && !i(U, e) || o.push(z[e]);
+                return o
+            };
+        B || (a((T = function() {
+            if (this instanceof T) throw TypeError("Symbol is not a constructor!");
+            var t = p(arguments.length > 0 ? arguments[0] : void 0),
+                e = function(n) {
+                    this === U && e.call(F, n), i(this, R) && i(this[R], t) && (this[R][t] = !1), W(this, t, x(1, n))
+                };
+            return o && Y && W(U, t, {
+                configurable: !0,
+                set: e
+            }), V(t)
+        }).prototype, "toString", function() {
+            return this._k
+        }), A.f = J, O.f = G, n(154).f = E.f = Z, n(64).f = H, n(108).f = X, o && !n(88) && a(U, "propertyIsEnumerable", H, !0), d.f = function(t) {
+            return V(h(t))
+        }), u(u.G + u.W + u.F * !B, {
+            Symbol: T
+        });
+        for (var Q = "hasInstance,isConcatSpreadable,iterator,match,replace,search,species,split,toPrimitive,toStringTag,unscopables".split(","), tt = 0; Q.length > tt;) h(Q[tt++]);
+        for (var et = j(h.store), nt = 0; et.length > nt;) v(et[nt++]);
+        u(u.S + u.F * !B, "Symbol", {
+            for: function(t) {
+                return i(D, t += "") ? D[t] : D[t] = T(t)
+            },
+            keyFor: function(t) {
+                if (!K(t)) throw TypeError(t + " is not a symbol!");
+                for (var e in D)
+                    if (D[e] === t) return e
+            },
+            useSetter: function() {
+                Y = !0
+            },
+            useSimple: function() {
+                Y = !1
+            }
+        }), u(u.S + u.F * !B, "Object", {
+            create: function(t, e) {
+                return void 0 === e ? S(t) : $(S(t), e)
+            },
+            defineProperty: G,
+            defineProperties: $,
+            getOwnPropertyDescriptor: J,
+            getOwnPropertyNames: Z,
+            getOwnPropertySymbols: X
+        }), C && u(u.S + u.F * (!B || c(function() {
+            var t = T();
+            return "[null]" != P([t]) || "{}" != P({
+                a: t
+            }) || "{}" != P(Object(t))
+        })), "JSON", {
+            stringify: function(t) {
+                for (var e, n, r = [t], i = 1; arguments.length > i;) r.push(arguments[i++]);
+                if (n = e = r[1], (_(e) || void 0 !== t) && !K(t)) return g(e) || (e = function(t, e) {
+                    if ("function" == typeof n && (e = n.call(this, t, e)), !K(e)) return e
+                }), r[1] = e, P.apply(C, r)
+            }
+        }), T.prototype[N] || n(26)(T.prototype, N, T.prototype.valueOf), l(T, "Symbol"), l(Math, "Math", !0), l(r.JSON, "JSON", !0)
+    }, function(t, e, n) {
+        var r = n(55)("meta"),
+            i = n(28),
+            o = n(16),
+            u = n(14).f,
+            a = 0,
+            s = Object.isExtensible || function() {
+                return !0
+            },
+            c = !n(29)(function() {
+                return s(Object.preventExtensions({}))
+            }),
+            f = function(t) {
+                u(t, r, {
+                    value: {
+                        i: "O" + ++a,
+                        w: {}
+                    }
+                })
+            },
+            l = t.exports = {
+                KEY: r,
+                NEED: !1,
+                fastKey: function(t, e) {
+                    if (!i(t)) return "symbol" == typeof t ? t : ("string" == typeof t ? "S" : "P") + t;
+                    if (!o(t, r)) {
+                        if (!s(t)) return "F";
+                        if (!e) return "E";
+                        f(t)
+                    }
+                    return t[r].i
+                },
+                getWeak: function(t, e) {
+                    if (!o(t, r)) {
+                        if (!s(t)) return !0;
+                        if (!e) return !1;
+                        f(t)
+                    }
+                    return t[r].w
+                },
+                onFreeze: function(t) {
+                    return c && l.NEED && s(t) && !o(t, r) && f(t), t
+                }
+            }
+    }, function(t, e, n) {
+        var r = n(40),
+            i = n(108),
+            o = n(64);
+        t.exports = function(t) {
+            var e = r(t),
+                n = i.f;
+            if (n)
+                for (var u, a = n(t), s = o.f, c = 0; a.length > c;) s.call(t, u = a[c++]) && e.push(u);
+            return e
+        }
+    }, function(t, e, n) {
+        var r = n(86);
+        t.exports = Array.isArray || function(t) {
+            return "Array" == r(t)
+        }
+    }, function(t, e, n) {
+        var r = n(25),
+            i = n(154).f,
+            o = {}.toString,
+            u = "object" == typeof window && window && Object.getOwnPropertyNames ? Object.getOwnPropertyNames(window) : [];
+        t.exports.f = function(t) {
+            return u && "[object Window]" == o.call(t) ? function(t) {
+                try {
+                    return i(t)
+                } catch (t) {
+                    return u.slice()
+                }
+            }(t) : i(r(t))
+        }
+    }, function(t, e) {}, function(t, e, n) {
+        n(107)("asyncIterator")
+    }, function(t, e, n) {
+        n(107)("observable")
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(299),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(300), t.exports = n(2).Object.setPrototypeOf
+    }, function(t, e, n) {
+        var r = n(13);
+        r(r.S, "Object", {
+            setPrototypeOf: n(301).set
+        })
+    }, function(t, e, n) {
+        var r = n(28),
+            i = n(27),
+            o = function(t, e) {
+                if (i(t), !r(e) && null !== e) throw TypeError(e + ": can't set as prototype!")
+            };
+        t.exports = {
+            set: Object.setPrototypeOf || ("__proto__" in {} ? function(t, e, r) {
+                try {
+                    (r = n(126)(Function.call, n(155).f(Object.prototype, "__proto__").set, 2))(t, []), e = !(t instanceof Array)
+                } catch (t) {
+                    e = !0
+                }
+                return function(t, n) {
+                    return o(t, n), e ? t.__proto__ = n : r(t, n), t
+                }
+            }({}, !1) : void 0),
+            check: o
+        }
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(303),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(304);
+        var r = n(2).Object;
+        t.exports = function(t, e) {
+            return r.create(t, e)
+        }
+    }, function(t, e, n) {
+        var r = n(13);
+        r(r.S, "Object", {
+            create: n(90)
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(46),
+            i = n(158),
+            o = n(307),
+            u = n(312),
+            a = n(32),
+            s = n(313),
+            c = n(317),
+            f = n(318),
+            l = n(320),
+            p = a.createElement,
+            h = a.createFactory,
+            d = a.cloneElement,
+            v = r,
+            y = function(t) {
+                return t
+            },
+            g = {
+                Children: {
+                    map: o.map,
+                    forEach: o.forEach,
+                    count: o.count,
+                    toArray: o.toArray,
+                    only: l
+                },
+                Component: i.Component,
+                PureComponent: i.PureComponent,
+                createElement: p,
+                cloneElement: d,
+                isValidElement: a.isValidElement,
+                PropTypes: s,
+                createClass: f,
+                createFactory: h,
+                createMixin: y,
+                DOM: u,
+                version: c,
+                __spread: v
+            };
+        t.exports = g
+    }, function(t, e, n) {
+        "use strict";
+        var r = function() {};
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(308),
+            i = n(32),
+            o = n(67),
+            u = n(309),
+            a = r.twoArgumentPooler,
+            s = r.fourArgumentPooler,
+            c = /\/+/g;
+
+        function f(t) {
+            return ("" + t).replace(c, "$&/")
+        }
+
+        function l(t, e) {
+            this.func = t, this.context = e, this.count = 0
+        }
+
+        function p(t, e, n) {
+            var r = t.func,
+                i = t.context;
+            r.call(i, e, t.count++)
+        }
+
+        function h(t, e, n, r) {
+            this.result = t, this.keyPrefix = e, this.func = n, this.context = r, this.count = 0
+        }
+
+        function d(t, e, n) {
+            var r = t.result,
+                u = t.keyPrefix,
+                a = t.func,
+                s = t.context,
+                c = a.call(s, e, t.count++);
+            Array.isArray(c) ? v(c, r, n, o.thatReturnsArgument) : null != c && (i.isValidElement(c) && (c = i.cloneAndReplaceKey(c, u + (!c.key || e && e.key === c.key ? "" : f(c.key) + "/") + n)), r.push(c))
+        }
+
+        function v(t, e, n, r, i) {
+            var o = "";
+            null != n && (o = f(n) + "/");
+            var a = h.getPooled(e, o, r, i);
+            u(t, d, a), h.release(a)
+        }
+
+        function y(t, e, n) {
+            return null
+        }
+        l.prototype.destructor = function() {
+            this.func = null, this.context = null, this.count = 0
+        }, r.addPoolingTo(l, a), h.prototype.destructor = function() {
+            this.result = null, this.keyPrefix = null, this.func = null, this.context = null, this.count = 0
+        }, r.addPoolingTo(h, s);
+        var g = {
+            forEach: function(t, e, n) {
+                if (null == t) return t;
+                var r = l.getPooled(e, n);
+                u(t, p, r), l.release(r)
+            },
+            map: function(t, e, n) {
+                if (null == t) return t;
+                var r = [];
+                return v(t, r, null, e, n), r
+            },
+            mapIntoWithKeyPrefixInternal: v,
+            count: function(t, e) {
+                return u(t, y, null)
+            },
+            toArray: function(t) {
+                var e = [];
+                return v(t, e, null, o.thatReturnsArgument), e
+            }
+        };
+        t.exports = g
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(65),
+            i = (n(20), function(t) {
+                if (this.instancePool.length) {
+                    var e = this.instancePool.pop();
+                    return this.call(e, t), e
+                }
+                return new this(t)
+            }),
+            o = function(t) {
+                t instanceof this || r("25"), t.destructor(), this.instancePool.length < this.poolSize && this.instancePool.push(t)
+            },
+            u = i,
+            a = {
+                addPoolingTo: function(t, e) {
+                    var n = t;
+                    return n.instancePool = [], n.getPooled = e || u, n.poolSize || (n.poolSize = 10), n.release = o, n
+                },
+                oneArgumentPooler: i,
+                twoArgumentPooler: function(t, e) {
+                    if (this.instancePool.length) {
+                        var n = this.instancePool.pop();
+                        return this.call(n, t, e), n
+                    }
+                    return new this(t, e)
+                },
+                threeArgumentPooler: function(t, e, n) {
+                    if (this.instancePool.length) {
+                        var r = this.instancePool.pop();
+                        return this.call(r, t, e, n), r
+                    }
+                    return new this(t, e, n)
+                },
+                fourArgumentPooler: function(t, e, n, r) {
+                    if (this.instancePool.length) {
+                        var i = this.instancePool.pop();
+                        return this.call(i, t, e, n, r), i
+                    }
+                    return new this(t, e, n, r)
+                }
+            };
+        t.exports = a
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(65),
+            i = (n(162), n(163)),
+            o = n(310),
+            u = (n(20), n(311)),
+            a = (n(66), "."),
+            s = ":";
+
+        function c(t, e) {
+            return t && "object" == typeof t && null != t.key ? u.escape(t.key) : e.toString(36)
+        }
+        t.exports = function(t, e, n) {
+            return null == t ? 0 : function t(e, n, f, l) {
+                var p, h = typeof e;
+                if ("undefined" !== h && "boolean" !== h || (e = null), null === e || "string" === h || "number" === h || "object" === h && e.$$typeof === i) return f(l, e, "" === n ? a + c(e, 0) : n), 1;
+                var d = 0,
+                    v = "" === n ? a : n + s;
+                if (Array.isArray(e))
+                    for (var y = 0; y < e.length; y++) d += t(p = e[y], v + c(p, y), f, l);
+                else {
+                    var g = o(e);
+                    if (g) {
+                        var m, _ = g.call(e);
+                        if (g !== e.entries)
+                            for (var b = 0; !(m = _.next()).done;) d += t(p = m.value, v + c(p, b++), f, l);
+                        else
+                            for (; !(m = _.next()).done;) {
+                                var w = m.value;
+                                w && (d += t(p = w[1], v + u.escape(w[0]) + s + c(p, 0), f, l))
+                            }
+                    } else if ("object" === h) {
+                        var x = "",
+                            S = String(e);
+                        r("31", "[object Object]" === S ? "object with keys {" + Object.keys(e).join(", ") + "}" : S, x)
+                    }
+                }
+                return d
+            }(t, "", e, n)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = "function" == typeof Symbol && Symbol.iterator,
+            i = "@@iterator";
+        t.exports = function(t) {
+            var e = t && (r && t[r] || t[i]);
+            if ("function" == typeof e) return e
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = {
+            escape: function(t) {
+                var e = {
+                    "=": "=0",
+                    ":": "=2"
+                };
+                return "$" + ("" + t).replace(/[=:]/g, function(t) {
+                    return e[t]
+                })
+            },
+            unescape: function(t) {
+                var e = {
+                    "=0": "=",
+                    "=2": ":"
+                };
+                return ("" + ("." === t[0] && "$" === t[1] ? t.substring(2) : t.substring(1))).replace(/(=0|=2)/g, function(t) {
+                    return e[t]
+                })
+            }
+        };
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(32).createFactory,
+            i = {
+                a: r("a"),
+                abbr: r("abbr"),
+                address: r("address"),
+                area: r("area"),
+                article: r("article"),
+                aside: r("aside"),
+                audio: r("audio"),
+                b: r("b"),
+                base: r("base"),
+                bdi: r("bdi"),
+                bdo: r("bdo"),
+                big: r("big"),
+                blockquote: r("blockquote"),
+                body: r("body"),
+                br: r("br"),
+                button: r("button"),
+                canvas: r("canvas"),
+                caption: r("caption"),
+                cite: r("cite"),
+                code: r("code"),
+                col: r("col"),
+                colgroup: r("colgroup"),
+                data: r("data"),
+                datalist: r("datalist"),
+                dd: r("dd"),
+                del: r("del"),
+                details: r("details"),
+                dfn: r("dfn"),
+                dialog: r("dialog"),
+                div: r("div"),
+                dl: r("dl"),
+                dt: r("dt"),
+                em: r("em"),
+                embed: r("embed"),
+                fieldset: r("fieldset"),
+                figcaption: r("figcaption"),
+                figure: r("figure"),
+                footer: r("footer"),
+                form: r("form"),
+                h1: r("h1"),
+                h2: r("h2"),
+                h3: r("h3"),
+                h4: r("h4"),
+                h5: r("h5"),
+                h6: r("h6"),
+                head: r("head"),
+                header: r("header"),
+                hgroup: r("hgroup"),
+                hr: r("hr"),
+                html: r("html"),
+                i: r("i"),
+                iframe: r("iframe"),
+                img: r("img"),
+                input: r("input"),
+                ins: r("ins"),
+                kbd: r("kbd"),
+                keygen: r("keygen"),
+                label: r("label"),
+                legend: r("legend"),
+                li: r("li"),
+                link: r("link"),
+                main: r("main"),
+                map: r("map"),
+                mark: r("mark"),
+                menu: r("menu"),
+                menuitem: r("menuitem"),
+                meta: r("meta"),
+                meter: r("meter"),
+                nav: r("nav"),
+                noscript: r("noscript"),
+                object: r("object"),
+                ol: r("ol"),
+                optgroup: r("optgroup"),
+                option: r("option"),
+                output: r("output"),
+                p: r("p"),
+                param: r("param"),
+                picture: r("picture"),
+                pre: r("pre"),
+                progress: r("progress"),
+                q: r("q"),
+                rp: r("rp"),
+                rt: r("rt"),
+                ruby: r("ruby"),
+                s: r("s"),
+                samp: r("samp"),
+                script: r("script"),
+                section: r("section"),
+                select: r("select"),
+                small: r("small"),
+                source: r("source"),
+                span: r("span"),
+                strong: r("strong"),
+                style: r("style"),
+                sub: r("sub"),
+                summary: r("summary"),
+                sup: r("sup"),
+                table: r("table"),
+                tbody: r("tbody"),
+                td: r("td"),
+                textarea: r("textarea"),
+                tfoot: r("tfoot"),
+                th: r("th"),
+                thead: r("thead"),
+                time: r("time"),
+                title: r("title"),
+                tr: r("tr"),
+                track: r("track"),
+                u: r("u"),
+                ul: r("ul"),
+                var: r("var"),
+                video: r("video"),
+                wbr: r("wbr"),
+                circle: r("circle"),
+                clipPath: r("clipPath"),
+                defs: r("defs"),
+                ellipse: r("ellipse"),
+                g: r("g"),
+                image: r("image"),
+                line: r("line"),
+                linearGradient: r("linearGradient"),
+                mask: r("mask"),
+                path: r("path"),
+                pattern: r("pattern"),
+                polygon: r("polygon"),
+                polyline: r("polyline"),
+                radialGradient: r("radialGradient"),
+                rect: r("rect"),
+                stop: r("stop"),
+                svg: r("svg"),
+                text: r("text"),
+                tspan: r("tspan")
+            };
+        t.exports = i
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(32).isValidElement,
+            i = n(314);
+        t.exports = i(r)
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(315);
+        t.exports = function(t) {
+            return r(t, !1)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(67),
+            i = n(20),
+            o = n(66),
+            u = n(46),
+            a = n(164),
+            s = n(316);
+        t.exports = function(t, e) {
+            var n = "function" == typeof Symbol && Symbol.iterator,
+                c = "@@iterator";
+            var f = "<<anonymous>>",
+                l = {
+                    array: v("array"),
+                    bool: v("boolean"),
+                    func: v("function"),
+                    number: v("number"),
+                    object: v("object"),
+                    string: v("string"),
+                    symbol: v("symbol"),
+                    any: d(r.thatReturnsNull),
+                    arrayOf: function(t) {
+                        return d(function(e, n, r, i, o) {
+                            if ("function" != typeof t) return new h("Property `" + o + "` of component `" + r + "` has invalid PropType notation inside arrayOf.");
+                            var u = e[n];
+                            if (!Array.isArray(u)) {
+                                var s = g(u);
+                                return new h("Invalid " + i + " `" + o + "` of type `" + s + "` supplied to `" + r + "`, expected an array.")
+                            }
+                            for (var c = 0; c < u.length; c++) {
+                                var f = t(u, c, r, i, o + "[" + c + "]", a);
+                                if (f instanceof Error) return f
+                            }
+                            return null
+                        })
+                    },
+                    element: function() {
+                        return d(function(e, n, r, i, o) {
+                            var u = e[n];
+                            if (!t(u)) {
+                                var a = g(u);
+                                return new h("Invalid " + i + " `" + o + "` of type `" + a + "` supplied to `" + r + "`, expected a single ReactElement.")
+                            }
+                            return null
+                        })
+                    }(),
+                    instanceOf: function(t) {
+                        return d(function(e, n, r, i, o) {
+                            if (!(e[n] instanceof t)) {
+                                var u = t.name || f,
+                                    a = function(t) {
+                                        if (!t.constructor || !t.constructor.name) return f;
+                                        return t.constructor.name
+                                    }(e[n]);
+                                return new h("Invalid " + i + " `" + o + "` of type `" + a + "` supplied to `" + r + "`, expected instance of `" + u + "`.")
+                            }
+                            return null
+                        })
+                    },
+                    node: function() {
+                        return d(function(t, e, n, r, i) {
+                            if (!y(t[e])) return new h("Invalid " + r + " `" + i + "` supplied to `" + n + "`, expected a ReactNode.");
+                            return null
+                        })
+                    }(),
+                    objectOf: function(t) {
+                        return d(function(e, n, r, i, o) {
+                            if ("function" != typeof t) return new h("Property `" + o + "` of component `" + r + "` has invalid PropType notation inside objectOf.");
+                            var u = e[n],
+                                s = g(u);
+                            if ("object" !== s) return new h("Invalid " + i + " `" + o + "` of type `" + s + "` supplied to `" + r + "`, expected an object.");
+                            for (var c in u)
+                                if (u.hasOwnProperty(c)) {
+                                    var f = t(u, c, r, i, o + "." + c, a);
+                                    if (f instanceof Error) return f
+                                }
+                            return null
+                        })
+                    },
+                    oneOf: function(t) {
+                        if (!Array.isArray(t)) return r.thatReturnsNull;
+                        return d(function(e, n, r, i, o) {
+                            for (var u = e[n], a = 0; a < t.length; a++)
+                                if (p(u, t[a])) return null;
+                            var s = JSON.stringify(t);
+                            return new h("Invalid " + i + " `" + o + "` of value `" + u + "` supplied to `" + r + "`, expected one of " + s + ".")
+                        })
+                    },
+                    oneOfType: function(t) {
+                        if (!Array.isArray(t)) return r.thatReturnsNull;
+                        for (var e = 0; e < t.length; e++) {
+                            var n = t[e];
+                            if ("function" != typeof n) return o(!1, "Invalid argument supplied to oneOfType. Expected an array of check functions, but received %s at index %s.", _(n), e), r.thatReturnsNull
+                        }
+                        return d(function(e, n, r, i, o) {
+                            for (var u = 0; u < t.length; u++) {
+                                var s = t[u];
+                                if (null == s(e, n, r, i, o, a)) return null
+                            }
+                            return new h("Invalid " + i + " `" + o + "` supplied to `" + r + "`.")
+                        })
+                    },
+                    shape: function(t) {
+                        return d(function(e, n, r, i, o) {
+                            var u = e[n],
+                                s = g(u);
+                            if ("object" !== s) return new h("Invalid " + i + " `" + o + "` of type `" + s + "` supplied to `" + r + "`, expected `object`.");
+                            for (var c in t) {
+                                var f = t[c];
+                                if (f) {
+                                    var l = f(u, c, r, i, o + "." + c, a);
+                                    if (l) return l
+                                }
+                            }
+                            return null
+                        })
+                    },
+                    exact: function(t) {
+                        return d(function(e, n, r, i, o) {
+                            var s = e[n],
+                                c = g(s);
+                            if ("object" !== c) return new h("Invalid " + i + " `" + o + "` of type `" + c + "` supplied to `" + r + "`, expected `object`.");
+                            var f = u({}, e[n], t);
+                            for (var l in f) {
+                                var p = t[l];
+                                if (!p) return new h("Invalid " + i + " `" + o + "` key `" + l + "` supplied to `" + r + "`.\nBad object: " + JSON.stringify(e[n], null, "  ") + "\nValid keys: " + JSON.stringify(Object.keys(t), null, "  "));
+                                var d = p(s, l, r, i, o + "." + l, a);
+                                if (d) return d
+                            }
+                            return null
+                        })
+                    }
+                };
+
+            function p(t, e) {
+                return t === e ? 0 !== t || 1 / t == 1 / e : t != t && e != e
+            }
+
+            function h(t) {
+                this.message = t, this.stack = ""
+            }
+
+            function d(t) {
+                function n(n, r, o, u, s, c, l) {
+                    (u = u || f, c = c || o, l !== a) && (e && i(!1, "Calling PropTypes validators directly is not supported by the `prop-types` package. Use `PropTypes.checkPropTypes()` to call them. Read more at http://fb.me/use-check-prop-types"));
+                    return null == r[o] ? n ? null === r[o] ? new h("The " + s + " `" + c + "` is marked as required in `" + u + "`, but its value is `null`.") : new h("The " + s + " `" + c + "` is marked as required in `" + u + "`, but its value is `undefined`.") : null : t(r, o, u, s, c)
+                }
+                var r = n.bind(null, !1);
+                return r.isRequired = n.bind(null, !0), r
+            }
+
+            function v(t) {
+                return d(function(e, n, r, i, o, u) {
+                    var a = e[n];
+                    return g(a) !== t ? new h("Invalid " + i + " `" + o + "` of type `" + m(a) + "` supplied to `" + r + "`, expected `" + t + "`.") : null
+                })
+            }
+
+            function y(e) {
+                switch (typeof e) {
+                    case "number":
+                    case "string":
+                    case "undefined":
+                        return !0;
+                    case "boolean":
+                        return !e;
+                    case "object":
+                        if (Array.isArray(e)) return e.every(y);
+                        if (null === e || t(e)) return !0;
+                        var r = function(t) {
+                            var e = t && (n && t[n] || t[c]);
+                            if ("function" == typeof e) return e
+                        }(e);
+                        if (!r) return !1;
+                        var i, o = r.call(e);
+                        if (r !== e.entries) {
+                            for (; !(i = o.next()).done;)
+                                if (!y(i.value)) return !1
+                        } else
+                            for (; !(i = o.next()).done;) {
+                                var u = i.value;
+                                if (u && !y(u[1])) return !1
+                            }
+                        return !0;
+                    default:
+                        return !1
+                }
+            }
+
+            function g(t) {
+                var e = typeof t;
+                return Array.isArray(t) ? "array" : t instanceof RegExp ? "object" : function(t, e) {
+                    return "symbol" === t || "Symbol" === e["@@toStringTag"] || "function" == typeof Symbol && e instanceof Symbol
+                }(e, t) ? "symbol" : e
+            }
+
+            function m(t) {
+                if (void 0 === t || null === t) return "" + t;
+                var e = g(t);
+                if ("object" === e) {
+                    if (t instanceof Date) return "date";
+                    if (t instanceof RegExp) return "regexp"
+                }
+                return e
+            }
+
+            function _(t) {
+                var e = m(t);
+                switch (e) {
+                    case "array":
+                    case "object":
+                        return "an " + e;
+                    case "boolean":
+                    case "date":
+                    case "regexp":
+                        return "a " + e;
+                    default:
+                        return e
+                }
+            }
+            return h.prototype = Error.prototype, l.checkPropTypes = s, l.PropTypes = l, l
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t, e, n, r, i) {}
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = "15.6.2"
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(158).Component,
+            i = n(32).isValidElement,
+            o = n(159),
+            u = n(319);
+        t.exports = u(r, i, o)
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(46),
+            i = n(161),
+            o = n(20),
+            u = "mixins";
+        t.exports = function(t, e, n) {
+            var a = [],
+                s = {
+                    mixins: "DEFINE_MANY",
+                    statics: "DEFINE_MANY",
+                    propTypes: "DEFINE_MANY",
+                    contextTypes: "DEFINE_MANY",
+                    childContextTypes: "DEFINE_MANY",
+                    getDefaultProps: "DEFINE_MANY_MERGED",
+                    getInitialState: "DEFINE_MANY_MERGED",
+                    getChildContext: "DEFINE_MANY_MERGED",
+                    render: "DEFINE_ONCE",
+                    componentWillMount: "DEFINE_MANY",
+                    componentDidMount: "DEFINE_MANY",
+                    componentWillReceiveProps: "DEFINE_MANY",
+                    shouldComponentUpdate: "DEFINE_ONCE",
+                    componentWillUpdate: "DEFINE_MANY",
+                    componentDidUpdate: "DEFINE_MANY",
+                    componentWillUnmount: "DEFINE_MANY",
+                    UNSAFE_componentWillMount: "DEFINE_MANY",
+                    UNSAFE_componentWillReceiveProps: "DEFINE_MANY",
+                    UNSAFE_componentWillUpdate: "DEFINE_MANY",
+                    updateComponent: "OVERRIDE_BASE"
+                },
+                c = {
+                    getDerivedStateFromProps: "DEFINE_MANY_MERGED"
+                },
+                f = {
+                    displayName: function(t, e) {
+                        t.displayName = e
+                    },
+                    mixins: function(t, e) {
+                        if (e)
+                            for (var n = 0; n < e.length; n++) p(t, e[n])
+                    },
+                    childContextTypes: function(t, e) {
+                        t.childContextTypes = r({}, t.childContextTypes, e)
+                    },
+                    contextTypes: function(t, e) {
+                        t.contextTypes = r({}, t.contextTypes, e)
+                    },
+                    getDefaultProps: function(t, e) {
+                        t.getDefaultProps ? t.getDefaultProps = d(t.getDefaultProps, e) : t.getDefaultProps = e
+                    },
+                    propTypes: function(t, e) {
+                        t.propTypes = r({}, t.propTypes, e)
+                    },
+                    statics: function(t, e) {
+                        ! function(t, e) {
+                            if (e)
+                                for (var n in e) {
+                                    var r = e[n];
+                                    if (e.hasOwnProperty(n)) {
+                                        var i = n in f;
+                                        o(!i, 'ReactClass: You are attempting to define a reserved property, `%s`, that shouldn\'t be on the "statics" key. Define it as an instance property instead; it will still be accessible on the constructor.', n);
+                                        var u = n in t;
+                                        if (u) {
+                                            var a = c.hasOwnProperty(n) ? c[n] : null;
+                                            return o("DEFINE_MANY_MERGED" === a, "ReactClass: You are attempting to define `%s` on your component more than once. This conflict may be due to a mixin.", n), void(t[n] = d(t[n], r))
+                                        }
+                                        t[n] = r
+                                    }
+                                }
+                        }(t, e)
+                    },
+                    autobind: function() {}
+                };
+
+            function l(t, e) {
+                var n = s.hasOwnProperty(e) ? s[e] : null;
+                _.hasOwnProperty(e) && o("OVERRIDE_BASE" === n, "ReactClassInterface: You are attempting to override `%s` from your class specification. Ensure that your method names do not overlap with React methods.", e), t && o("DEFINE_MANY" === n || "DEFINE_MANY_MERGED" === n, "ReactClassInterface: You are attempting to define `%s` on your component more than once. This conflict may be due to a mixin.", e)
+            }
+
+            function p(t, n) {
+                if (n) {
+                    o("function" != typeof n, "ReactClass: You're attempting to use a component class or function as a mixin. Instead, just use a regular object."), o(!e(n), "ReactClass: You're attempting to use a component as a mixin. Instead, just use a regular object.");
+                    var r = t.prototype,
+                        i = r.__reactAutoBindPairs;
+                    for (var a in n.hasOwnProperty(u) && f.mixins(t, n.mixins), n)
+                        if (n.hasOwnProperty(a) && a !== u) {
+                            var c = n[a],
+                                p = r.hasOwnProperty(a);
+                            if (l(p, a), f.hasOwnProperty(a)) f[a](t, c);
+                            else {
+                                var h = s.hasOwnProperty(a);
+                                if ("function" != typeof c || h || p || !1 === n.autobind)
+                                    if (p) {
+                                        var y = s[a];
+                                        o(h && ("DEFINE_MANY_MERGED" === y || "DEFINE_MANY" === y), "ReactClass: Unexpected spec policy %s for key %s when mixing in component specs.", y, a), "DEFINE_MANY_MERGED" === y ? r[a] = d(r[a], c) : "DEFINE_MANY" === y && (r[a] = v(r[a], c))
+                                    } else r[a] = c;
+                                else i.push(a, c), r[a] = c
+                            }
+                        }
+                }
+            }
+
+            function h(t, e) {
+                for (var n in o(t && e && "object" == typeof t && "object" == typeof e, "mergeIntoWithNoDuplicateKeys(): Cannot merge non-objects."), e) e.hasOwnProperty(n) && (o(void 0 === t[n], "mergeIntoWithNoDuplicateKeys(): Tried to merge two objects with the same key: `%s`. This conflict may be due to a mixin; in particular, this may be caused by two getInitialState() or getDefaultProps() methods returning objects with clashing keys.", n), t[n] = e[n]);
+                return t
+            }
+
+            function d(t, e) {
+                return function() {
+                    var n = t.apply(this, arguments),
+                        r = e.apply(this, arguments);
+                    if (null == n) return r;
+                    if (null == r) return n;
+                    var i = {};
+                    return h(i, n), h(i, r), i
+                }
+            }
+
+            function v(t, e) {
+                return function() {
+                    t.apply(this, arguments), e.apply(this, arguments)
+                }
+            }
+
+            function y(t, e) {
+                var n = e.bind(t);
+                return n
+            }
+            var g = {
+                    componentDidMount: function() {
+                        this.__isMounted = !0
+                    }
+                },
+                m = {
+                    componentWillUnmount: function() {
+                        this.__isMounted = !1
+                    }
+                },
+                _ = {
+                    replaceState: function(t, e) {
+                        this.updater.enqueueReplaceState(this, t, e)
+                    },
+                    isMounted: function() {
+                        return !!this.__isMounted
+                    }
+                },
+                b = function() {};
+            return r(b.prototype, t.prototype, _),
+                function(t) {
+                    var e = function(t, r, u) {
+                        this.__reactAutoBindPairs.length && function(t) {
+                            for (var e = t.__reactAutoBindPairs, n = 0; n < e.length; n += 2) {
+                                var r = e[n],
+                                    i = e[n + 1];
+                                t[r] = y(t, i)
+                            }
+                        }(this), this.props = t, this.context = r, this.refs = i, this.updater = u || n, this.state = null;
+                        var a = this.getInitialState ? this.getInitialState() : null;
+                        o("object" == typeof a && !Array.isArray(a), "%s.getInitialState(): must return an object or null", e.displayName || "ReactCompositeComponent"), this.state = a
+                    };
+                    for (var r in e.prototype = new b, e.prototype.constructor = e, e.prototype.__reactAutoBindPairs = [], a.forEach(p.bind(null, e)), p(e, g), p(e, t), p(e, m), e.getDefaultProps && (e.defaultProps = e.getDefaultProps()), o(e.prototype.render, "createClass(...): Class specification must implement a `render` method."), s) e.prototype[r] || (e.prototype[r] = null);
+                    return e
+                }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(65),
+            i = n(32);
+        n(20);
+        t.exports = function(t) {
+            return i.isValidElement(t) || r("143"), t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(67),
+            i = n(20),
+            o = n(164);
+        t.exports = function() {
+            function t(t, e, n, r, u, a) {
+                a !== o && i(!1, "Calling PropTypes validators directly is not supported by the `prop-types` package. Use PropTypes.checkPropTypes() to call them. Read more at http://fb.me/use-check-prop-types")
+            }
+
+            function e() {
+                return t
+            }
+            t.isRequired = t;
+            var n = {
+                array: t,
+                bool: t,
+                func: t,
+                number: t,
+                object: t,
+                string: t,
+                symbol: t,
+                any: t,
+                arrayOf: e,
+                element: t,
+                instanceOf: e,
+                node: t,
+                objectOf: e,
+                oneOf: e,
+                oneOfType: e,
+                shape: e,
+                exact: e
+            };
+            return n.checkPropTypes = r, n.PropTypes = n, n
+        }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        }), e.default = function() {
+            return {
+                components: {
+                    Topbar: o.default
+                }
+            }
+        };
+        var r, i = n(323),
+            o = (r = i) && r.__esModule ? r : {
+                default: r
+            }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        });
+        var r = p(n(148)),
+            i = p(n(150)),
+            o = p(n(151)),
+            u = p(n(153)),
+            a = p(n(156)),
+            s = n(157),
+            c = p(s),
+            f = (p(n(165)), p(n(324))),
+            l = n(166);
+
+        function p(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        var h = function(t) {
+            function e(t, n) {
+                (0, i.default)(this, e);
+                var o = (0, u.default)(this, (e.__proto__ || (0, r.default)(e)).call(this, t, n));
+                return o.onUrlChange = function(t) {
+                    var e = t.target.value;
+                    o.setState({
+                        url: e
+                    })
+                }, o.loadSpec = function(t) {
+                    o.props.specActions.updateUrl(t), o.props.specActions.download(t)
+                }, o.onUrlSelect = function(t) {
+                    var e = t.target.value || t.target.href;
+                    o.loadSpec(e), o.setSelectedUrl(e), t.preventDefault()
+                }, o.downloadUrl = function(t) {
+                    o.loadSpec(o.state.url), t.preventDefault()
+                }, o.setSearch = function(t) {
+                    var e = (0, l.parseSearch)();
+                    e["urls.primaryName"] = t.name;
+                    var n = window.location.protocol + "//" + window.location.host + window.location.pathname;
+                    window && window.history && window.history.pushState && window.history.replaceState(null, "", n + "?" + (0, l.serializeSearch)(e))
+                }, o.setSelectedUrl = function(t) {
+                    var e = o.props.getConfigs().urls || [];
+                    e && e.length && t && e.forEach(function(e, n) {
+                        e.url === t && (o.setState({
+                            selectedIndex: n
+                        }), o.setSearch(e))
+                    })
+                }, o.onFilterChange = function(t) {
+                    var e = t.target.value;
+                    o.props.layoutActions.updateFilter(e)
+                }, o.state = {
+                    url: t.specSelectors.url(),
+                    selectedIndex: 0
+                }, o
+            }
+            return (0, a.default)(e, t), (0, o.default)(e, [{
+                key: "componentWillReceiveProps",
+                value: function(t) {
+                    this.setState({
+                        url: t.specSelectors.url()
+                    })
+                }
+            }, {
+                key: "componentWillMount",
+                value: function() {
+                    var t = this,
+                        e = this.props.getConfigs(),
+                        n = e.urls || [];
+                    if (n && n.length) {
+                        var r = e["urls.primaryName"];
+                        r && n.forEach(function(e, n) {
+                            e.name === r && t.setState({
+                                selectedIndex: n
+                            })
+                        })
+                    }
+                }
+            }, {
+                key: "componentDidMount",
+                value: function() {
+                    var t = this.props.getConfigs().urls || [];
+                    t && t.length && this.loadSpec(t[this.state.selectedIndex].url)
+                }
+            }, {
+                key: "render",
+                value: function() {
+                    var t = this.props,
+                        e = t.getComponent,
+                        n = t.specSelectors,
+                        r = t.getConfigs,
+                        i = e("Button"),
+                        o = e("Link"),
+                        u = "loading" === n.loadingStatus(),
+                        a = {};
+                    "failed" === n.loadingStatus() && (a.color = "red"), u && (a.color = "#aaa");
+                    var l = r().urls,
+                        p = [],
+                        h = null;
+                    if (l) {
+                        var d = [];
+                        l.forEach(function(t, e) {
+                            d.push(c.default.createElement("option", {
+                                key: e,
+                                value: t.url
+                            }, t.name))
+                        }), p.push(c.default.createElement("label", {
+                            className: "select-label",
+                            htmlFor: "select"
+                        }, c.default.createElement("span", null, "Select a spec"), c.default.createElement("select", {
+                            id: "select",
+                            disabled: u,
+                            onChange: this.onUrlSelect,
+                            value: l[this.state.selectedIndex].url
+                        }, d)))
+                    } else h = this.downloadUrl, p.push(c.default.createElement("input", {
+                        className: "download-url-input",
+                        type: "text",
+                        onChange: this.onUrlChange,
+                        value: this.state.url,
+                        disabled: u,
+                        style: a
+                    })), p.push(c.default.createElement(i, {
+                        className: "download-url-button",
+                        onClick: this.downloadUrl
+                    }, "Explore"));
+                    return c.default.createElement("div", {
+                        className: "topbar"
+                    }, c.default.createElement("div", {
+                        className: "wrapper"
+                    }, c.default.createElement("div", {
+                        className: "topbar-wrapper"
+                    }, c.default.createElement(o, null, c.default.createElement("img", {
+                        height: "30",
+                        width: "30",
+                        src: f.default,
+                        alt: "Swagger UI"
+                    }), c.default.createElement("span", null, "swagger")), c.default.createElement("form", {
+                        className: "download-url-wrapper",
+                        onSubmit: h
+                    }, p.map(function(t, e) {
+                        return (0, s.cloneElement)(t, {
+                            key: e
+                        })
+                    })))))
+                }
+            }]), e
+        }(c.default.Component);
+        e.default = h
+    }, function(t, e) {
+        t.exports = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAB4AAAAeCAMAAAAM7l6QAAAAYFBMVEUAAABUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwBUfwB0lzB/n0BfhxBpjyC0x4////+qv4CJp1D09++ft3C/z5/K16/U379UfwDf58/q79+Ur2D2RCk9AAAAHXRSTlMAEEAwn9//z3Agv4/vYID/////////////////UMeji1kAAAD8SURBVHgBlZMFAoQwDATRxbXB7f+vPKnlXAZn6k2cf3A9z/PfOC8IIYni5FmmABM8FMhwT17c9hnhiZL1CwvEL1tmPD0qSKq6gaStW/kMXanVmAVRDUlH1OvuuTINo6k90Sxf8qsOtF6g4ff1osP3OnMcV7d4pzdIUtu1oA4V0DZoKmxmlEYvtDUjjS3tmKmqB+pYy8pD1VPf7jPE0I40HHcaBwnue6fGzgyS5tXIU96PV7rkDWHNLV0DK4FkoKmFpN5oUnvi8KoeA2/JXsmXQuokx0siR1G8tLkN6eB9sLwJp/yymcyaP/TrP+RPmbMMixcJVgTR1aUZ93oGXsgXQAaG6EwAAAAASUVORK5CYII="
+    }, function(t, e, n) {
+        "use strict";
+        e.byteLength = function(t) {
+            var e = c(t),
+                n = e[0],
+                r = e[1];
+            return 3 * (n + r) / 4 - r
+        }, e.toByteArray = function(t) {
+            for (var e, n = c(t), r = n[0], u = n[1], a = new o(function(t, e, n) {
+                return 3 * (e + n) / 4 - n
+            }(0, r, u)), s = 0, f = u > 0 ? r - 4 : r, l = 0; l < f; l += 4) e = i[t.charCodeAt(l)] << 18 | i[t.charCodeAt(l + 1)] << 12 | i[t.charCodeAt(l + 2)] << 6 | i[t.charCodeAt(l + 3)], a[s++] = e >> 16 & 255, a[s++] = e >> 8 & 255, a[s++] = 255 & e;
+            2 === u && (e = i[t.charCodeAt(l)] << 2 | i[t.charCodeAt(l + 1)] >> 4, a[s++] = 255 & e);
+            1 === u && (e = i[t.charCodeAt(l)] << 10 | i[t.charCodeAt(l + 1)] << 4 | i[t.charCodeAt(l + 2)] >> 2, a[s++] = e >> 8 & 255, a[s++] = 255 & e);
+            return a
+        }, e.fromByteArray = function(t) {
+            for (var e, n = t.length, i = n % 3, o = [], u = 0, a = n - i; u < a; u += 16383) o.push(f(t, u, u + 16383 > a ? a : u + 16383));
+            1 === i ? (e = t[n - 1], o.push(r[e >> 2] + r[e << 4 & 63] + "==")) : 2 === i && (e = (t[n - 2] << 8) + t[n - 1], o.push(r[e >> 10] + r[e >> 4 & 63] + r[e << 2 & 63] + "="));
+            return o.join("")
+        };
+        for (var r = [], i = [], o = "undefined" != typeof Uint8Array ? Uint8Array : Array, u = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/", a = 0, s = u.length; a < s; ++a) r[a] = u[a], i[u.charCodeAt(a)] = a;
+
+        function c(t) {
+            var e = t.length;
+            if (e % 4 > 0) throw new Error("Invalid string. Length must be a multiple of 4");
+            var n = t.indexOf("=");
+            return -1 === n && (n = e), [n, n === e ? 0 : 4 - n % 4]
+        }
+
+        function f(t, e, n) {
+            for (var i, o, u = [], a = e; a < n; a += 3) i = (t[a] << 16 & 16711680) + (t[a + 1] << 8 & 65280) + (255 & t[a + 2]), u.push(r[(o = i) >> 18 & 63] + r[o >> 12 & 63] + r[o >> 6 & 63] + r[63 & o]);
+            return u.join("")
+        }
+        i["-".charCodeAt(0)] = 62, i["_".charCodeAt(0)] = 63
+    }, function(t, e) {
+        e.read = function(t, e, n, r, i) {
+            var o, u, a = 8 * i - r - 1,
+                s = (1 << a) - 1,
+                c = s >> 1,
+                f = -7,
+                l = n ? i - 1 : 0,
+                p = n ? -1 : 1,
+                h = t[e + l];
+            for (l += p, o = h & (1 << -f) - 1, h >>= -f, f += a; f > 0; o = 256 * o + t[e + l], l += p, f -= 8);
+            for (u = o & (1 << -f) - 1, o >>= -f, f += r; f > 0; u = 256 * u + t[e + l], l += p, f -= 8);
+            if (0 === o) o = 1 - c;
+            else {
+                if (o === s) return u ? NaN : 1 / 0 * (h ? -1 : 1);
+                u += Math.pow(2, r), o -= c
+            }
+            return (h ? -1 : 1) * u * Math.pow(2, o - r)
+        }, e.write = function(t, e, n, r, i, o) {
+            var u, a, s, c = 8 * o - i - 1,
+                f = (1 << c) - 1,
+                l = f >> 1,
+                p = 23 === i ? Math.pow(2, -24) - Math.pow(2, -77) : 0,
+                h = r ? 0 : o - 1,
+                d = r ? 1 : -1,
+                v = e < 0 || 0 === e && 1 / e < 0 ? 1 : 0;
+            for (e = Math.abs(e), isNaN(e) || e === 1 / 0 ? (a = isNaN(e) ? 1 : 0, u = f) : (u = Math.floor(Math.log(e) / Math.LN2), e * (s = Math.pow(2, -u)) < 1 && (u--, s *= 2), (e += u + l >= 1 ? p / s : p * Math.pow(2, 1 - l)) * s >= 2 && (u++, s /= 2), u + l >= f ? (a = 0, u = f) : u + l >= 1 ? (a = (e * s - 1) * Math.pow(2, i), u += l) : (a = e * Math.pow(2, l - 1) * Math.pow(2, i), u = 0)); i >= 8; t[n + h] = 255 & a, h += d, a /= 256, i -= 8);
+            for (u = u << i | a, c += i; c > 0; t[n + h] = 255 & u, h += d, u /= 256, c -= 8);
+            t[n + h - d] |= 128 * v
+        }
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(328),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        var r = n(2),
+            i = r.JSON || (r.JSON = {
+                stringify: JSON.stringify
+            });
+        t.exports = function(t) {
+            return i.stringify.apply(i, arguments)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        e.__esModule = !0;
+        var r = o(n(330)),
+            i = o(n(84));
+
+        function o(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        e.default = function() {
+            return function(t, e) {
+                if (Array.isArray(t)) return t;
+                if ((0, r.default)(Object(t))) return function(t, e) {
+                    var n = [],
+                        r = !0,
+                        o = !1,
+                        u = void 0;
+                    try {
+                        for (var a, s = (0, i.default)(t); !(r = (a = s.next()).done) && (n.push(a.value), !e || n.length !== e); r = !0);
+                    } catch (t) {
+                        o = !0, u = t
+                    } finally {
+                        try {
+                            !r && s.return && s.return()
+                        } finally {
+                            if (o) throw u
+                        }
+                    }
+                    return n
+                }(t, e);
+                throw new TypeError("Invalid attempt to destructure non-iterable instance")
+            }
+        }()
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(331),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(85), n(96), t.exports = n(332)
+    }, function(t, e, n) {
+        var r = n(132),
+            i = n(6)("iterator"),
+            o = n(39);
+        t.exports = n(2).isIterable = function(t) {
+            var e = Object(t);
+            return void 0 !== e[i] || "@@iterator" in e || o.hasOwnProperty(r(e))
+        }
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(334),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(335), t.exports = n(2).Object.assign
+    }, function(t, e, n) {
+        var r = n(13);
+        r(r.S + r.F, "Object", {
+            assign: n(336)
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(40),
+            i = n(108),
+            o = n(64),
+            u = n(56),
+            a = n(124),
+            s = Object.assign;
+        t.exports = !s || n(29)(function() {
+            var t = {},
+                e = {},
+                n = Symbol(),
+                r = "abcdefghijklmnopqrst";
+            return t[n] = 7, r.split("").forEach(function(t) {
+                e[t] = t
+            }), 7 != s({}, t)[n] || Object.keys(s({}, e)).join("") != r
+        }) ? function(t, e) {
+            for (var n = u(t), s = arguments.length, c = 1, f = i.f, l = o.f; s > c;)
+                for (var p, h = a(arguments[c++]), d = f ? r(h).concat(f(h)) : r(h), v = d.length, y = 0; v > y;) l.call(h, p = d[y++]) && (n[p] = h[p]);
+            return n
+        } : s
+    }, function(t, e, n) {
+        t.exports = {
+            default: n(338),
+            __esModule: !0
+        }
+    }, function(t, e, n) {
+        n(339), t.exports = n(2).Object.keys
+    }, function(t, e, n) {
+        var r = n(56),
+            i = n(40);
+        n(149)("keys", function() {
+            return function(t) {
+                return i(r(t))
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = /^(%20|\s)*(javascript|data)/im,
+            i = /[^\x20-\x7E]/gim,
+            o = /^([^:]+):/gm,
+            u = [".", "/"];
+        t.exports = {
+            sanitizeUrl: function(t) {
+                var e, n, a = t.replace(i, "");
+                return function(t) {
+                    return u.indexOf(t[0]) > -1
+                }(a) ? a : (n = a.match(o)) ? (e = n[0], r.test(e) ? "about:blank" : a) : "about:blank"
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(342),
+            i = n(353)(function(t, e, n) {
+                return e = e.toLowerCase(), t + (n ? r(e) : e)
+            });
+        t.exports = i
+    }, function(t, e, n) {
+        var r = n(47),
+            i = n(170);
+        t.exports = function(t) {
+            return i(r(t).toLowerCase())
+        }
+    }, function(t, e, n) {
+        var r = n(69),
+            i = n(344),
+            o = n(8),
+            u = n(70),
+            a = 1 / 0,
+            s = r ? r.prototype : void 0,
+            c = s ? s.toString : void 0;
+        t.exports = function t(e) {
+            if ("string" == typeof e) return e;
+            if (o(e)) return i(e, t) + "";
+            if (u(e)) return c ? c.call(e) : "";
+            var n = e + "";
+            return "0" == n && 1 / e == -a ? "-0" : n
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            for (var n = -1, r = null == t ? 0 : t.length, i = Array(r); ++n < r;) i[n] = e(t[n], n, t);
+            return i
+        }
+    }, function(t, e, n) {
+        var r = n(69),
+            i = Object.prototype,
+            o = i.hasOwnProperty,
+            u = i.toString,
+            a = r ? r.toStringTag : void 0;
+        t.exports = function(t) {
+            var e = o.call(t, a),
+                n = t[a];
+            try {
+                t[a] = void 0;
+                var r = !0
+            } catch (t) {}
+            var i = u.call(t);
+            return r && (e ? t[a] = n : delete t[a]), i
+        }
+    }, function(t, e) {
+        var n = Object.prototype.toString;
+        t.exports = function(t) {
+            return n.call(t)
+        }
+    }, function(t, e, n) {
+        var r = n(348),
+            i = n(171),
+            o = n(350),
+            u = n(47);
+        t.exports = function(t) {
+            return function(e) {
+                e = u(e);
+                var n = i(e) ? o(e) : void 0,
+                    a = n ? n[0] : e.charAt(0),
+                    s = n ? r(n, 1).join("") : e.slice(1);
+                return a[t]() + s
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(349);
+        t.exports = function(t, e, n) {
+            var i = t.length;
+            return n = void 0 === n ? i : n, !e && n >= i ? t : r(t, e, n)
+        }
+    }, function(t, e) {
+        t.exports = function(t, e, n) {
+            var r = -1,
+                i = t.length;
+            e < 0 && (e = -e > i ? 0 : i + e), (n = n > i ? i : n) < 0 && (n += i), i = e > n ? 0 : n - e >>> 0, e >>>= 0;
+            for (var o = Array(i); ++r < i;) o[r] = t[r + e];
+            return o
+        }
+    }, function(t, e, n) {
+        var r = n(351),
+            i = n(171),
+            o = n(352);
+        t.exports = function(t) {
+            return i(t) ? o(t) : r(t)
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return t.split("")
+        }
+    }, function(t, e) {
+        var n = "[\\ud800-\\udfff]",
+            r = "[\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff]",
+            i = "\\ud83c[\\udffb-\\udfff]",
+            o = "[^\\ud800-\\udfff]",
+            u = "(?:\\ud83c[\\udde6-\\uddff]){2}",
+            a = "[\\ud800-\\udbff][\\udc00-\\udfff]",
+            s = "(?:" + r + "|" + i + ")" + "?",
+            c = "[\\ufe0e\\ufe0f]?" + s + ("(?:\\u200d(?:" + [o, u, a].join("|") + ")[\\ufe0e\\ufe0f]?" + s + ")*"),
+            f = "(?:" + [o + r + "?", r, u, a, n].join("|") + ")",
+            l = RegExp(i + "(?=" + i + ")|" + f + c, "g");
+        t.exports = function(t) {
+            return t.match(l) || []
+        }
+    }, function(t, e, n) {
+        var r = n(354),
+            i = n(355),
+            o = n(358),
+            u = RegExp("['’]", "g");
+        t.exports = function(t) {
+            return function(e) {
+                return r(o(i(e).replace(u, "")), t, "")
+            }
+        }
+    }, function(t, e) {
+        t.exports = function(t, e, n, r) {
+            var i = -1,
+                o = null == t ? 0 : t.length;
+            for (r && o && (n = t[++i]); ++i < o;) n = e(n, t[i], i, t);
+            return n
+        }
+    }, function(t, e, n) {
+        var r = n(356),
+            i = n(47),
+            o = /[\xc0-\xd6\xd8-\xf6\xf8-\xff\u0100-\u017f]/g,
+            u = RegExp("[\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff]", "g");
+        t.exports = function(t) {
+            return (t = i(t)) && t.replace(o, r).replace(u, "")
+        }
+    }, function(t, e, n) {
+        var r = n(357)({
+            "À": "A",
+            "Á": "A",
+            "Â": "A",
+            "Ã": "A",
+            "Ä": "A",
+            "Å": "A",
+            "à": "a",
+            "á": "a",
+            "â": "a",
+            "ã": "a",
+            "ä": "a",
+            "å": "a",
+            "Ç": "C",
+            "ç": "c",
+            "Ð": "D",
+            "ð": "d",
+            "È": "E",
+            "É": "E",
+            "Ê": "E",
+            "Ë": "E",
+            "è": "e",
+            "é": "e",
+            "ê": "e",
+            "ë": "e",
+            "Ì": "I",
+            "Í": "I",
+            "Î": "I",
+            "Ï": "I",
+            "ì": "i",
+            "í": "i",
+            "î": "i",
+            "ï": "i",
+            "Ñ": "N",
+            "ñ": "n",
+            "Ò": "O",
+            "Ó": "O",
+            "Ô": "O",
+            "Õ": "O",
+            "Ö": "O",
+            "Ø": "O",
+            "ò": "o",
+            "ó": "o",
+            "ô": "o",
+            "õ": "o",
+            "ö": "o",
+            "ø": "o",
+            "Ù": "U",
+            "Ú": "U",
+            "Û": "U",
+            "Ü": "U",
+            "ù": "u",
+            "ú": "u",
+            "û": "u",
+            "ü": "u",
+            "Ý": "Y",
+            "ý": "y",
+            "ÿ": "y",
+            "Æ": "Ae",
+            "æ": "ae",
+            "Þ": "Th",
+            "þ": "th",
+            "ß": "ss",
+            "Ā": "A",
+            "Ă": "A",
+            "Ą": "A",
+            "ā": "a",
+            "ă": "a",
+            "ą": "a",
+            "Ć": "C",
+            "Ĉ": "C",
+            "Ċ": "C",
+            "Č": "C",
+            "ć": "c",
+            "ĉ": "c",
+            "ċ": "c",
+            "č": "c",
+            "Ď": "D",
+            "Đ": "D",
+            "ď": "d",
+            "đ": "d",
+            "Ē": "E",
+            "Ĕ": "E",
+            "Ė": "E",
+            "Ę": "E",
+            "Ě": "E",
+            "ē": "e",
+            "ĕ": "e",
+            "ė": "e",
+            "ę": "e",
+            "ě": "e",
+            "Ĝ": "G",
+            "Ğ": "G",
+            "Ġ": "G",
+            "Ģ": "G",
+            "ĝ": "g",
+            "ğ": "g",
+            "ġ": "g",
+            "ģ": "g",
+            "Ĥ": "H",
+            "Ħ": "H",
+            "ĥ": "h",
+            "ħ": "h",
+            "Ĩ": "I",
+            "Ī": "I",
+            "Ĭ": "I",
+            "Į": "I",
+            "İ": "I",
+            "ĩ": "i",
+            "ī": "i",
+            "ĭ": "i",
+            "į": "i",
+            "ı": "i",
+            "Ĵ": "J",
+            "ĵ": "j",
+            "Ķ": "K",
+            "ķ": "k",
+            "ĸ": "k",
+            "Ĺ": "L",
+            "Ļ": "L",
+            "Ľ": "L",
+            "Ŀ": "L",
+            "Ł": "L",
+            "ĺ": "l",
+            "ļ": "l",
+            "ľ": "l",
+            "ŀ": "l",
+            "ł": "l",
+            "Ń": "N",
+            "Ņ": "N",
+            "Ň": "N",
+            "Ŋ": "N",
+            "ń": "n",
+            "ņ": "n",
+            "ň": "n",
+            "ŋ": "n",
+            "Ō": "O",
+            "Ŏ": "O",
+            "Ő": "O",
+            "ō": "o",
+            "ŏ": "o",
+            "ő": "o",
+            "Ŕ": "R",
+            "Ŗ": "R",
+            "Ř": "R",
+            "ŕ": "r",
+            "ŗ": "r",
+            "ř": "r",
+            "Ś": "S",
+            "Ŝ": "S",
+            "Ş": "S",
+            "Š": "S",
+            "ś": "s",
+            "ŝ": "s",
+            "ş": "s",
+            "š": "s",
+            "Ţ": "T",
+            "Ť": "T",
+            "Ŧ": "T",
+            "ţ": "t",
+            "ť": "t",
+            "ŧ": "t",
+            "Ũ": "U",
+            "Ū": "U",
+            "Ŭ": "U",
+            "Ů": "U",
+            "Ű": "U",
+            "Ų": "U",
+            "ũ": "u",
+            "ū": "u",
+            "ŭ": "u",
+            "ů": "u",
+            "ű": "u",
+            "ų": "u",
+            "Ŵ": "W",
+            "ŵ": "w",
+            "Ŷ": "Y",
+            "ŷ": "y",
+            "Ÿ": "Y",
+            "Ź": "Z",
+            "Ż": "Z",
+            "Ž": "Z",
+            "ź": "z",
+            "ż": "z",
+            "ž": "z",
+            "Ĳ": "IJ",
+            "ĳ": "ij",
+            "Œ": "Oe",
+            "œ": "oe",
+            "ŉ": "'n",
+            "ſ": "s"
+        });
+        t.exports = r
+    }, function(t, e) {
+        t.exports = function(t) {
+            return function(e) {
+                return null == t ? void 0 : t[e]
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(359),
+            i = n(360),
+            o = n(47),
+            u = n(361);
+        t.exports = function(t, e, n) {
+            return t = o(t), void 0 === (e = n ? void 0 : e) ? i(t) ? u(t) : r(t) : t.match(e) || []
+        }
+    }, function(t, e) {
+        var n = /[^\x00-\x2f\x3a-\x40\x5b-\x60\x7b-\x7f]+/g;
+        t.exports = function(t) {
+            return t.match(n) || []
+        }
+    }, function(t, e) {
+        var n = /[a-z][A-Z]|[A-Z]{2,}[a-z]|[0-9][a-zA-Z]|[a-zA-Z][0-9]|[^a-zA-Z0-9 ]/;
+        t.exports = function(t) {
+            return n.test(t)
+        }
+    }, function(t, e) {
+        var n = "\\xac\\xb1\\xd7\\xf7\\x00-\\x2f\\x3a-\\x40\\x5b-\\x60\\x7b-\\xbf\\u2000-\\u206f \\t\\x0b\\f\\xa0\\ufeff\\n\\r\\u2028\\u2029\\u1680\\u180e\\u2000\\u2001\\u2002\\u2003\\u2004\\u2005\\u2006\\u2007\\u2008\\u2009\\u200a\\u202f\\u205f\\u3000",
+            r = "[" + n + "]",
+            i = "\\d+",
+            o = "[\\u2700-\\u27bf]",
+            u = "[a-z\\xdf-\\xf6\\xf8-\\xff]",
+            a = "[^\\ud800-\\udfff" + n + i + "\\u2700-\\u27bfa-z\\xdf-\\xf6\\xf8-\\xffA-Z\\xc0-\\xd6\\xd8-\\xde]",
+            s = "(?:\\ud83c[\\udde6-\\uddff]){2}",
+            c = "[\\ud800-\\udbff][\\udc00-\\udfff]",
+            f = "[A-Z\\xc0-\\xd6\\xd8-\\xde]",
+            l = "(?:" + u + "|" + a + ")",
+            p = "(?:" + f + "|" + a + ")",
+            h = "(?:[\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff]|\\ud83c[\\udffb-\\udfff])?",
+            d = "[\\ufe0e\\ufe0f]?" + h + ("(?:\\u200d(?:" + ["[^\\ud800-\\udfff]", s, c].join("|") + ")[\\ufe0e\\ufe0f]?" + h + ")*"),
+            v = "(?:" + [o, s, c].join("|") + ")" + d,
+            y = RegExp([f + "?" + u + "+(?:['’](?:d|ll|m|re|s|t|ve))?(?=" + [r, f, "$"].join("|") + ")", p + "+(?:['’](?:D|LL|M|RE|S|T|VE))?(?=" + [r, f + l, "$"].join("|") + ")", f + "?" + l + "+(?:['’](?:d|ll|m|re|s|t|ve))?", f + "+(?:['’](?:D|LL|M|RE|S|T|VE))?", "\\d*(?:1ST|2ND|3RD|(?![123])\\dTH)(?=\\b|[a-z_])", "\\d*(?:1st|2nd|3rd|(?![123])\\dth)(?=\\b|[A-Z_])", i, v].join("|"), "g");
+        t.exports = function(t) {
+            return t.match(y) || []
+        }
+    }, function(t, e, n) {
+        var r = n(363),
+            i = n(72),
+            o = n(110);
+        t.exports = function() {
+            this.size = 0, this.__data__ = {
+                hash: new r,
+                map: new(o || i),
+                string: new r
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(364),
+            i = n(369),
+            o = n(370),
+            u = n(371),
+            a = n(372);
+
+        function s(t) {
+            var e = -1,
+                n = null == t ? 0 : t.length;
+            for (this.clear(); ++e < n;) {
+                var r = t[e];
+                this.set(r[0], r[1])
+            }
+        }
+        s.prototype.clear = r, s.prototype.delete = i, s.prototype.get = o, s.prototype.has = u, s.prototype.set = a, t.exports = s
+    }, function(t, e, n) {
+        var r = n(71);
+        t.exports = function() {
+            this.__data__ = r ? r(null) : {}, this.size = 0
+        }
+    }, function(t, e, n) {
+        var r = n(173),
+            i = n(366),
+            o = n(50),
+            u = n(174),
+            a = /^\[object .+?Constructor\]$/,
+            s = Function.prototype,
+            c = Object.prototype,
+            f = s.toString,
+            l = c.hasOwnProperty,
+            p = RegExp("^" + f.call(l).replace(/[\\^$.*+?()[\]{}|]/g, "\\$&").replace(/hasOwnProperty|(function).*?(?=\\\()| for .+?(?=\\\])/g, "$1.*?") + "$");
+        t.exports = function(t) {
+            return !(!o(t) || i(t)) && (r(t) ? p : a).test(u(t))
+        }
+    }, function(t, e, n) {
+        var r, i = n(367),
+            o = (r = /[^.]+$/.exec(i && i.keys && i.keys.IE_PROTO || "")) ? "Symbol(src)_1." + r : "";
+        t.exports = function(t) {
+            return !!o && o in t
+        }
+    }, function(t, e, n) {
+        var r = n(7)["__core-js_shared__"];
+        t.exports = r
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return null == t ? void 0 : t[e]
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            var e = this.has(t) && delete this.__data__[t];
+            return this.size -= e ? 1 : 0, e
+        }
+    }, function(t, e, n) {
+        var r = n(71),
+            i = "__lodash_hash_undefined__",
+            o = Object.prototype.hasOwnProperty;
+        t.exports = function(t) {
+            var e = this.__data__;
+            if (r) {
+                var n = e[t];
+                return n === i ? void 0 : n
+            }
+            return o.call(e, t) ? e[t] : void 0
+        }
+    }, function(t, e, n) {
+        var r = n(71),
+            i = Object.prototype.hasOwnProperty;
+        t.exports = function(t) {
+            var e = this.__data__;
+            return r ? void 0 !== e[t] : i.call(e, t)
+        }
+    }, function(t, e, n) {
+        var r = n(71),
+            i = "__lodash_hash_undefined__";
+        t.exports = function(t, e) {
+            var n = this.__data__;
+            return this.size += this.has(t) ? 0 : 1, n[t] = r && void 0 === e ? i : e, this
+        }
+    }, function(t, e) {
+        t.exports = function() {
+            this.__data__ = [], this.size = 0
+        }
+    }, function(t, e, n) {
+        var r = n(73),
+            i = Array.prototype.splice;
+        t.exports = function(t) {
+            var e = this.__data__,
+                n = r(e, t);
+            return !(n < 0 || (n == e.length - 1 ? e.pop() : i.call(e, n, 1), --this.size, 0))
+        }
+    }, function(t, e, n) {
+        var r = n(73);
+        t.exports = function(t) {
+            var e = this.__data__,
+                n = r(e, t);
+            return n < 0 ? void 0 : e[n][1]
+        }
+    }, function(t, e, n) {
+        var r = n(73);
+        t.exports = function(t) {
+            return r(this.__data__, t) > -1
+        }
+    }, function(t, e, n) {
+        var r = n(73);
+        t.exports = function(t, e) {
+            var n = this.__data__,
+                i = r(n, t);
+            return i < 0 ? (++this.size, n.push([t, e])) : n[i][1] = e, this
+        }
+    }, function(t, e, n) {
+        var r = n(75);
+        t.exports = function(t) {
+            var e = r(this, t).delete(t);
+            return this.size -= e ? 1 : 0, e
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            var e = typeof t;
+            return "string" == e || "number" == e || "symbol" == e || "boolean" == e ? "__proto__" !== t : null === t
+        }
+    }, function(t, e, n) {
+        var r = n(75);
+        t.exports = function(t) {
+            return r(this, t).get(t)
+        }
+    }, function(t, e, n) {
+        var r = n(75);
+        t.exports = function(t) {
+            return r(this, t).has(t)
+        }
+    }, function(t, e, n) {
+        var r = n(75);
+        t.exports = function(t, e) {
+            var n = r(this, t),
+                i = n.size;
+            return n.set(t, e), this.size += n.size == i ? 0 : 1, this
+        }
+    }, function(t, e, n) {
+        var r = n(384)(n(436));
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(111),
+            i = n(77),
+            o = n(76);
+        t.exports = function(t) {
+            return function(e, n, u) {
+                var a = Object(e);
+                if (!i(e)) {
+                    var s = r(n, 3);
+                    e = o(e), n = function(t) {
+                        return s(a[t], t, a)
+                    }
+                }
+                var c = t(e, n, u);
+                return c > -1 ? a[s ? e[c] : c] : void 0
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(386),
+            i = n(424),
+            o = n(184);
+        t.exports = function(t) {
+            var e = i(t);
+            return 1 == e.length && e[0][2] ? o(e[0][0], e[0][1]) : function(n) {
+                return n === t || r(n, t, e)
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(175),
+            i = n(176),
+            o = 1,
+            u = 2;
+        t.exports = function(t, e, n, a) {
+            var s = n.length,
+                c = s,
+                f = !a;
+            if (null == t) return !c;
+            for (t = Object(t); s--;) {
+                var l = n[s];
+                if (f && l[2] ? l[1] !== t[l[0]] : !(l[0] in t)) return !1
+            }
+            for (; ++s < c;) {
+                var p = (l = n[s])[0],
+                    h = t[p],
+                    d = l[1];
+                if (f && l[2]) {
+                    if (void 0 === h && !(p in t)) return !1
+                } else {
+                    var v = new r;
+                    if (a) var y = a(h, d, p, t, e, v);
+                    if (!(void 0 === y ? i(d, h, o | u, a, v) : y)) return !1
+                }
+            }
+            return !0
+        }
+    }, function(t, e, n) {
+        var r = n(72);
+        t.exports = function() {
+            this.__data__ = new r, this.size = 0
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            var e = this.__data__,
+                n = e.delete(t);
+            return this.size = e.size, n
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return this.__data__.get(t)
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return this.__data__.has(t)
+        }
+    }, function(t, e, n) {
+        var r = n(72),
+            i = n(110),
+            o = n(109),
+            u = 200;
+        t.exports = function(t, e) {
+            var n = this.__data__;
+            if (n instanceof r) {
+                var a = n.__data__;
+                if (!i || a.length < u - 1) return a.push([t, e]), this.size = ++n.size, this;
+                n = this.__data__ = new o(a)
+            }
+            return n.set(t, e), this.size = n.size, this
+        }
+    }, function(t, e, n) {
+        var r = n(175),
+            i = n(177),
+            o = n(397),
+            u = n(401),
+            a = n(419),
+            s = n(8),
+            c = n(180),
+            f = n(182),
+            l = 1,
+            p = "[object Arguments]",
+            h = "[object Array]",
+            d = "[object Object]",
+            v = Object.prototype.hasOwnProperty;
+        t.exports = function(t, e, n, y, g, m) {
+            var _ = s(t),
+                b = s(e),
+                w = _ ? h : a(t),
+                x = b ? h : a(e),
+                S = (w = w == p ? d : w) == d,
+                E = (x = x == p ? d : x) == d,
+                A = w == x;
+            if (A && c(t)) {
+                if (!c(e)) return !1;
+                _ = !0, S = !1
+            }
+            if (A && !S) return m || (m = new r), _ || f(t) ? i(t, e, n, y, g, m) : o(t, e, w, n, y, g, m);
+            if (!(n & l)) {
+                var O = S && v.call(t, "__wrapped__"),
+                    j = E && v.call(e, "__wrapped__");
+                if (O || j) {
+                    var k = O ? t.value() : t,
+                        I = j ? e.value() : e;
+                    return m || (m = new r), g(k, I, n, y, m)
+                }
+            }
+            return !!A && (m || (m = new r), u(t, e, n, y, g, m))
+        }
+    }, function(t, e, n) {
+        var r = n(109),
+            i = n(394),
+            o = n(395);
+
+        function u(t) {
+            var e = -1,
+                n = null == t ? 0 : t.length;
+            for (this.__data__ = new r; ++e < n;) this.add(t[e])
+        }
+        u.prototype.add = u.prototype.push = i, u.prototype.has = o, t.exports = u
+    }, function(t, e) {
+        var n = "__lodash_hash_undefined__";
+        t.exports = function(t) {
+            return this.__data__.set(t, n), this
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return this.__data__.has(t)
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return t.has(e)
+        }
+    }, function(t, e, n) {
+        var r = n(69),
+            i = n(398),
+            o = n(74),
+            u = n(177),
+            a = n(399),
+            s = n(400),
+            c = 1,
+            f = 2,
+            l = "[object Boolean]",
+            p = "[object Date]",
+            h = "[object Error]",
+            d = "[object Map]",
+            v = "[object Number]",
+            y = "[object RegExp]",
+            g = "[object Set]",
+            m = "[object String]",
+            _ = "[object Symbol]",
+            b = "[object ArrayBuffer]",
+            w = "[object DataView]",
+            x = r ? r.prototype : void 0,
+            S = x ? x.valueOf : void 0;
+        t.exports = function(t, e, n, r, x, E, A) {
+            switch (n) {
+                case w:
+                    if (t.byteLength != e.byteLength || t.byteOffset != e.byteOffset) return !1;
+                    t = t.buffer, e = e.buffer;
+                case b:
+                    return !(t.byteLength != e.byteLength || !E(new i(t), new i(e)));
+                case l:
+                case p:
+                case v:
+                    return o(+t, +e);
+                case h:
+                    return t.name == e.name && t.message == e.message;
+                case y:
+                case m:
+                    return t == e + "";
+                case d:
+                    var O = a;
+                case g:
+                    var j = r & c;
+                    if (O || (O = s), t.size != e.size && !j) return !1;
+                    var k = A.get(t);
+                    if (k) return k == e;
+                    r |= f, A.set(t, e);
+                    var I = u(O(t), O(e), r, x, E, A);
+                    return A.delete(t), I;
+                case _:
+                    if (S) return S.call(t) == S.call(e)
+            }
+            return !1
+        }
+    }, function(t, e, n) {
+        var r = n(7).Uint8Array;
+        t.exports = r
+    }, function(t, e) {
+        t.exports = function(t) {
+            var e = -1,
+                n = Array(t.size);
+            return t.forEach(function(t, r) {
+                n[++e] = [r, t]
+            }), n
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            var e = -1,
+                n = Array(t.size);
+            return t.forEach(function(t) {
+                n[++e] = t
+            }), n
+        }
+    }, function(t, e, n) {
+        var r = n(402),
+            i = 1,
+            o = Object.prototype.hasOwnProperty;
+        t.exports = function(t, e, n, u, a, s) {
+            var c = n & i,
+                f = r(t),
+                l = f.length;
+            if (l != r(e).length && !c) return !1;
+            for (var p = l; p--;) {
+                var h = f[p];
+                if (!(c ? h in e : o.call(e, h))) return !1
+            }
+            var d = s.get(t);
+            if (d && s.get(e)) return d == e;
+            var v = !0;
+            s.set(t, e), s.set(e, t);
+            for (var y = c; ++p < l;) {
+                var g = t[h = f[p]],
+                    m = e[h];
+                if (u) var _ = c ? u(m, g, h, e, t, s) : u(g, m, h, t, e, s);
+                if (!(void 0 === _ ? g === m || a(g, m, n, u, s) : _)) {
+                    v = !1;
+                    break
+                }
+                y || (y = "constructor" == h)
+            }
+            if (v && !y) {
+                var b = t.constructor,
+                    w = e.constructor;
+                b != w && "constructor" in t && "constructor" in e && !("function" == typeof b && b instanceof b && "function" == typeof w && w instanceof w) && (v = !1)
+            }
+            return s.delete(t), s.delete(e), v
+        }
+    }, function(t, e, n) {
+        var r = n(403),
+            i = n(405),
+            o = n(76);
+        t.exports = function(t) {
+            return r(t, o, i)
+        }
+    }, function(t, e, n) {
+        var r = n(404),
+            i = n(8);
+        t.exports = function(t, e, n) {
+            var o = e(t);
+            return i(t) ? o : r(o, n(t))
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            for (var n = -1, r = e.length, i = t.length; ++n < r;) t[i + n] = e[n];
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(406),
+            i = n(407),
+            o = Object.prototype.propertyIsEnumerable,
+            u = Object.getOwnPropertySymbols,
+            a = u ? function(t) {
+                return null == t ? [] : (t = Object(t), r(u(t), function(e) {
+                    return o.call(t, e)
+                }))
+            } : i;
+        t.exports = a
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            for (var n = -1, r = null == t ? 0 : t.length, i = 0, o = []; ++n < r;) {
+                var u = t[n];
+                e(u, n, t) && (o[i++] = u)
+            }
+            return o
+        }
+    }, function(t, e) {
+        t.exports = function() {
+            return []
+        }
+    }, function(t, e, n) {
+        var r = n(409),
+            i = n(179),
+            o = n(8),
+            u = n(180),
+            a = n(112),
+            s = n(182),
+            c = Object.prototype.hasOwnProperty;
+        t.exports = function(t, e) {
+            var n = o(t),
+                f = !n && i(t),
+                l = !n && !f && u(t),
+                p = !n && !f && !l && s(t),
+                h = n || f || l || p,
+                d = h ? r(t.length, String) : [],
+                v = d.length;
+            for (var y in t) !e && !c.call(t, y) || h && ("length" == y || l && ("offset" == y || "parent" == y) || p && ("buffer" == y || "byteLength" == y || "byteOffset" == y) || a(y, v)) || d.push(y);
+            return d
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            for (var n = -1, r = Array(t); ++n < t;) r[n] = e(n);
+            return r
+        }
+    }, function(t, e, n) {
+        var r = n(48),
+            i = n(49),
+            o = "[object Arguments]";
+        t.exports = function(t) {
+            return i(t) && r(t) == o
+        }
+    }, function(t, e) {
+        t.exports = function() {
+            return !1
+        }
+    }, function(t, e, n) {
+        var r = n(48),
+            i = n(113),
+            o = n(49),
+            u = {};
+        u["[object Float32Array]"] = u["[object Float64Array]"] = u["[object Int8Array]"] = u["[object Int16Array]"] = u["[object Int32Array]"] = u["[object Uint8Array]"] = u["[object Uint8ClampedArray]"] = u["[object Uint16Array]"] = u["[object Uint32Array]"] = !0, u["[object Arguments]"] = u["[object Array]"] = u["[object ArrayBuffer]"] = u["[object Boolean]"] = u["[object DataView]"] = u["[object Date]"] = u["[object Error]"] = u["[object Function]"] = u["[object Map]"] = u["[object Number]"] = u["[object Object]"] = u["[object RegExp]"] = u["[object Set]"] = u["[object String]"] = u["[object WeakMap]"] = !1, t.exports = function(t) {
+            return o(t) && i(t.length) && !!u[r(t)]
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return function(e) {
+                return t(e)
+            }
+        }
+    }, function(t, e, n) {
+        (function(t) {
+            var r = n(169),
+                i = "object" == typeof e && e && !e.nodeType && e,
+                o = i && "object" == typeof t && t && !t.nodeType && t,
+                u = o && o.exports === i && r.process,
+                a = function() {
+                    try {
+                        var t = o && o.require && o.require("util").types;
+                        return t || u && u.binding && u.binding("util")
+                    } catch (t) {}
+                }();
+            t.exports = a
+        }).call(e, n(181)(t))
+    }, function(t, e, n) {
+        var r = n(416),
+            i = n(417),
+            o = Object.prototype.hasOwnProperty;
+        t.exports = function(t) {
+            if (!r(t)) return i(t);
+            var e = [];
+            for (var n in Object(t)) o.call(t, n) && "constructor" != n && e.push(n);
+            return e
+        }
+    }, function(t, e) {
+        var n = Object.prototype;
+        t.exports = function(t) {
+            var e = t && t.constructor;
+            return t === ("function" == typeof e && e.prototype || n)
+        }
+    }, function(t, e, n) {
+        var r = n(418)(Object.keys, Object);
+        t.exports = r
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return function(n) {
+                return t(e(n))
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(420),
+            i = n(110),
+            o = n(421),
+            u = n(422),
+            a = n(423),
+            s = n(48),
+            c = n(174),
+            f = c(r),
+            l = c(i),
+            p = c(o),
+            h = c(u),
+            d = c(a),
+            v = s;
+        (r && "[object DataView]" != v(new r(new ArrayBuffer(1))) || i && "[object Map]" != v(new i) || o && "[object Promise]" != v(o.resolve()) || u && "[object Set]" != v(new u) || a && "[object WeakMap]" != v(new a)) && (v = function(t) {
+            var e = s(t),
+                n = "[object Object]" == e ? t.constructor : void 0,
+                r = n ? c(n) : "";
+            if (r) switch (r) {
+                case f:
+                    return "[object DataView]";
+                case l:
+                    return "[object Map]";
+                case p:
+                    return "[object Promise]";
+                case h:
+                    return "[object Set]";
+                case d:
+                    return "[object WeakMap]"
+            }
+            return e
+        }), t.exports = v
+    }, function(t, e, n) {
+        var r = n(33)(n(7), "DataView");
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(33)(n(7), "Promise");
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(33)(n(7), "Set");
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(33)(n(7), "WeakMap");
+        t.exports = r
+    }, function(t, e, n) {
+        var r = n(183),
+            i = n(76);
+        t.exports = function(t) {
+            for (var e = i(t), n = e.length; n--;) {
+                var o = e[n],
+                    u = t[o];
+                e[n] = [o, u, r(u)]
+            }
+            return e
+        }
+    }, function(t, e, n) {
+        var r = n(176),
+            i = n(426),
+            o = n(429),
+            u = n(114),
+            a = n(183),
+            s = n(184),
+            c = n(78),
+            f = 1,
+            l = 2;
+        t.exports = function(t, e) {
+            return u(t) && a(e) ? s(c(t), e) : function(n) {
+                var u = i(n, t);
+                return void 0 === u && u === e ? o(n, t) : r(e, u, f | l)
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(185);
+        t.exports = function(t, e, n) {
+            var i = null == t ? void 0 : r(t, e);
+            return void 0 === i ? n : i
+        }
+    }, function(t, e, n) {
+        var r = /[^.[\]]+|\[(?:(-?\d+(?:\.\d+)?)|(["'])((?:(?!\2)[^\\]|\\.)*?)\2)\]|(?=(?:\.|\[\])(?:\.|\[\]|$))/g,
+            i = /\\(\\)?/g,
+            o = n(428)(function(t) {
+                var e = [];
+                return 46 === t.charCodeAt(0) && e.push(""), t.replace(r, function(t, n, r, o) {
+                    e.push(r ? o.replace(i, "$1") : n || t)
+                }), e
+            });
+        t.exports = o
+    }, function(t, e, n) {
+        var r = n(172),
+            i = 500;
+        t.exports = function(t) {
+            var e = r(t, function(t) {
+                    return n.size === i && n.clear(), t
+                }),
+                n = e.cache;
+            return e
+        }
+    }, function(t, e, n) {
+        var r = n(430),
+            i = n(431);
+        t.exports = function(t, e) {
+            return null != t && i(t, e, r)
+        }
+    }, function(t, e) {
+        t.exports = function(t, e) {
+            return null != t && e in Object(t)
+        }
+    }, function(t, e, n) {
+        var r = n(186),
+            i = n(179),
+            o = n(8),
+            u = n(112),
+            a = n(113),
+            s = n(78);
+        t.exports = function(t, e, n) {
+            for (var c = -1, f = (e = r(e, t)).length, l = !1; ++c < f;) {
+                var p = s(e[c]);
+                if (!(l = null != t && n(t, p))) break;
+                t = t[p]
+            }
+            return l || ++c != f ? l : !!(f = null == t ? 0 : t.length) && a(f) && u(p, f) && (o(t) || i(t))
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return t
+        }
+    }, function(t, e, n) {
+        var r = n(434),
+            i = n(435),
+            o = n(114),
+            u = n(78);
+        t.exports = function(t) {
+            return o(t) ? r(u(t)) : i(t)
+        }
+    }, function(t, e) {
+        t.exports = function(t) {
+            return function(e) {
+                return null == e ? void 0 : e[t]
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(185);
+        t.exports = function(t) {
+            return function(e) {
+                return r(e, t)
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(437),
+            i = n(111),
+            o = n(438),
+            u = Math.max;
+        t.exports = function(t, e, n) {
+            var a = null == t ? 0 : t.length;
+            if (!a) return -1;
+            var s = null == n ? 0 : o(n);
+            return s < 0 && (s = u(a + s, 0)), r(t, i(e, 3), s)
+        }
+    }, function(t, e) {
+        t.exports = function(t, e, n, r) {
+            for (var i = t.length, o = n + (r ? 1 : -1); r ? o-- : ++o < i;)
+                if (e(t[o], o, t)) return o;
+            return -1
+        }
+    }, function(t, e, n) {
+        var r = n(439);
+        t.exports = function(t) {
+            var e = r(t),
+                n = e % 1;
+            return e == e ? n ? e - n : e : 0
+        }
+    }, function(t, e, n) {
+        var r = n(440),
+            i = 1 / 0,
+            o = 1.7976931348623157e308;
+        t.exports = function(t) {
+            return t ? (t = r(t)) === i || t === -i ? (t < 0 ? -1 : 1) * o : t == t ? t : 0 : 0 === t ? t : 0
+        }
+    }, function(t, e, n) {
+        var r = n(50),
+            i = n(70),
+            o = NaN,
+            u = /^\s+|\s+$/g,
+            a = /^[-+]0x[0-9a-f]+$/i,
+            s = /^0b[01]+$/i,
+            c = /^0o[0-7]+$/i,
+            f = parseInt;
+        t.exports = function(t) {
+            if ("number" == typeof t) return t;
+            if (i(t)) return o;
+            if (r(t)) {
+                var e = "function" == typeof t.valueOf ? t.valueOf() : t;
+                t = r(e) ? e + "" : e
+            }
+            if ("string" != typeof t) return 0 === t ? t : +t;
+            t = t.replace(u, "");
+            var n = s.test(t);
+            return n || c.test(t) ? f(t.slice(2), n ? 2 : 8) : a.test(t) ? o : +t
+        }
+    }, function(t, e, n) {
+        var r = n(178),
+            i = n(111),
+            o = n(442),
+            u = n(8),
+            a = n(448);
+        t.exports = function(t, e, n) {
+            var s = u(t) ? r : o;
+            return n && a(t, e, n) && (e = void 0), s(t, i(e, 3))
+        }
+    }, function(t, e, n) {
+        var r = n(443);
+        t.exports = function(t, e) {
+            var n;
+            return r(t, function(t, r, i) {
+                return !(n = e(t, r, i))
+            }), !!n
+        }
+    }, function(t, e, n) {
+        var r = n(444),
+            i = n(447)(r);
+        t.exports = i
+    }, function(t, e, n) {
+        var r = n(445),
+            i = n(76);
+        t.exports = function(t, e) {
+            return t && r(t, e, i)
+        }
+    }, function(t, e, n) {
+        var r = n(446)();
+        t.exports = r
+    }, function(t, e) {
+        t.exports = function(t) {
+            return function(e, n, r) {
+                for (var i = -1, o = Object(e), u = r(e), a = u.length; a--;) {
+                    var s = u[t ? a : ++i];
+                    if (!1 === n(o[s], s, o)) break
+                }
+                return e
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(77);
+        t.exports = function(t, e) {
+            return function(n, i) {
+                if (null == n) return n;
+                if (!r(n)) return t(n, i);
+                for (var o = n.length, u = e ? o : -1, a = Object(n);
+                     (e ? u-- : ++u < o) && !1 !== i(a[u], u, a););
+                return n
+            }
+        }
+    }, function(t, e, n) {
+        var r = n(74),
+            i = n(77),
+            o = n(112),
+            u = n(50);
+        t.exports = function(t, e, n) {
+            if (!u(n)) return !1;
+            var a = typeof e;
+            return !!("number" == a ? i(n) && o(e, n.length) : "string" == a && e in n) && r(n[e], t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        }), e.memoizedSampleFromSchema = e.memoizedCreateXMLExample = e.sampleXmlFromSchema = e.inferSchema = e.sampleFromSchema = void 0, e.createXMLExample = l;
+        var r = n(166),
+            i = u(n(450)),
+            o = u(n(463));
+
+        function u(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        var a = {
+                string: function() {
+                    return "string"
+                },
+                string_email: function() {
+                    return "user@example.com"
+                },
+                "string_date-time": function() {
+                    return (new Date).toISOString()
+                },
+                number: function() {
+                    return 0
+                },
+                number_float: function() {
+                    return 0
+                },
+                integer: function() {
+                    return 0
+                },
+                boolean: function(t) {
+                    return "boolean" != typeof t.default || t.default
+                }
+            },
+            s = function(t) {
+                var e = t = (0, r.objectify)(t),
+                    n = e.type,
+                    i = e.format,
+                    o = a[n + "_" + i] || a[n];
+                return (0, r.isFunc)(o) ? o(t) : "Unknown Type: " + t.type
+            },
+            c = e.sampleFromSchema = function t(e) {
+                var n = arguments.length > 1 && void 0 !== arguments[1] ? arguments[1] : {},
+                    i = (0, r.objectify)(e),
+                    o = i.type,
+                    u = i.example,
+                    a = i.properties,
+                    c = i.additionalProperties,
+                    f = i.items,
+                    l = n.includeReadOnly,
+                    p = n.includeWriteOnly;
+                if (void 0 !== u) return (0, r.deeplyStripKey)(u, "$$ref", function(t) {
+                    return "string" == typeof t && t.indexOf("#") > -1
+                });
+                if (!o)
+                    if (a) o = "object";
+                    else {
+                        if (!f) return;
+                        o = "array"
+                    }
+                if ("object" === o) {
+                    var h = (0, r.objectify)(a),
+                        d = {};
+                    for (var v in h) h[v] && h[v].deprecated || h[v] && h[v].readOnly && !l || h[v] && h[v].writeOnly && !p || (d[v] = t(h[v], n));
+                    if (!0 === c) d.additionalProp1 = {};
+                    else if (c)
+                        for (var y = (0, r.objectify)(c), g = t(y, n), m = 1; m < 4; m++) d["additionalProp" + m] = g;
+                    return d
+                }
+                return "array" === o ? Array.isArray(f.anyOf) ? f.anyOf.map(function(e) {
+                    return t(e, n)
+                }) : Array.isArray(f.oneOf) ? f.oneOf.map(function(e) {
+                    return t(e, n)
+                }) : [t(f, n)] : e.enum ? e.default ? e.default : (0, r.normalizeArray)(e.enum)[0] : "file" !== o ? s(e) : void 0
+            },
+            f = (e.inferSchema = function(t) {
+                return t.schema && (t = t.schema), t.properties && (t.type = "object"), t
+            }, e.sampleXmlFromSchema = function t(e) {
+                var n, i = arguments.length > 1 && void 0 !== arguments[1] ? arguments[1] : {},
+                    o = (0, r.objectify)(e),
+                    u = o.type,
+                    a = o.properties,
+                    c = o.additionalProperties,
+                    f = o.items,
+                    l = o.example,
+                    p = i.includeReadOnly,
+                    h = i.includeWriteOnly,
+                    d = o.default,
+                    v = {},
+                    y = {},
+                    g = e.xml,
+                    m = g.name,
+                    _ = g.prefix,
+                    b = g.namespace,
+                    w = o.enum,
+                    x = void 0;
+                if (!u)
+                    if (a || c) u = "object";
+                    else {
+                        if (!f) return;
+                        u = "array"
+                    }(m = m || "notagname", n = (_ ? _ + ":" : "") + m, b) && (y[_ ? "xmlns:" + _ : "xmlns"] = b);
+                if ("array" === u && f) {
+                    if (f.xml = f.xml || g || {}, f.xml.name = f.xml.name || g.name, g.wrapped) return v[n] = [], Array.isArray(l) ? l.forEach(function(e) {
+                        f.example = e, v[n].push(t(f, i))
+                    }) : Array.isArray(d) ? d.forEach(function(e) {
+                        f.default = e, v[n].push(t(f, i))
+                    }) : v[n] = [t(f, i)], y && v[n].push({
+                        _attr: y
+                    }), v;
+                    var S = [];
+                    return Array.isArray(l) ? (l.forEach(function(e) {
+                        f.example = e, S.push(t(f, i))
+                    }), S) : Array.isArray(d) ? (d.forEach(function(e) {
+                        f.default = e, S.push(t(f, i))
+                    }), S) : t(f, i)
+                }
+                if ("object" === u) {
+                    var E = (0, r.objectify)(a);
+                    for (var A in v[n] = [], l = l || {}, E)
+                        if (E.hasOwnProperty(A) && (!E[A].readOnly || p) && (!E[A].writeOnly || h))
+                            if (E[A].xml = E[A].xml || {}, E[A].xml.attribute) {
+                                var O = Array.isArray(E[A].enum) && E[A].enum[0],
+                                    j = E[A].example,
+                                    k = E[A].default;
+                                y[E[A].xml.name || A] = void 0 !== j && j || void 0 !== l[A] && l[A] || void 0 !== k && k || O || s(E[A])
+                            } else {
+                                E[A].xml.name = E[A].xml.name || A, void 0 === E[A].example && void 0 !== l[A] && (E[A].example = l[A]);
+                                var I = t(E[A]);
+                                Array.isArray(I) ? v[n] = v[n].concat(I) : v[n].push(I)
+                            }
+                    return !0 === c ? v[n].push({
+                        additionalProp: "Anything can be here"
+                    }) : c && v[n].push({
+                        additionalProp: s(c)
+                    }), y && v[n].push({
+                        _attr: y
+                    }), v
+                }
+                return x = void 0 !== l ? l : void 0 !== d ? d : Array.isArray(w) ? w[0] : s(e), v[n] = y ? [{
+                    _attr: y
+                }, x] : x, v
+            });
+
+        function l(t, e) {
+            var n = f(t, e);
+            if (n) return (0, i.default)(n, {
+                declaration: !0,
+                indent: "\t"
+            })
+        }
+        e.memoizedCreateXMLExample = (0, o.default)(l), e.memoizedSampleFromSchema = (0, o.default)(c)
+    }, function(t, e, n) {
+        (function(e) {
+            var r = n(451),
+                i = n(452).Stream,
+                o = "    ";
+
+            function u(t, e, n) {
+                n = n || 0;
+                var i, o, a = (i = e, new Array(n || 0).join(i || "")),
+                    s = t;
+                if ("object" == typeof t && ((s = t[o = Object.keys(t)[0]]) && s._elem)) return s._elem.name = o, s._elem.icount = n, s._elem.indent = e, s._elem.indents = a, s._elem.interrupt = s, s._elem;
+                var c, f = [],
+                    l = [];
+
+                function p(t) {
+                    Object.keys(t).forEach(function(e) {
+                        f.push(function(t, e) {
+                            return t + '="' + r(e) + '"'
+                        }(e, t[e]))
+                    })
+                }
+                switch (typeof s) {
+                    case "object":
+                        if (null === s) break;
+                        s._attr && p(s._attr), s._cdata && l.push(("<![CDATA[" + s._cdata).replace(/\]\]>/g, "]]]]><![CDATA[>") + "]]>"), s.forEach && (c = !1, l.push(""), s.forEach(function(t) {
+                            "object" == typeof t ? "_attr" == Object.keys(t)[0] ? p(t._attr) : l.push(u(t, e, n + 1)) : (l.pop(), c = !0, l.push(r(t)))
+                        }), c || l.push(""));
+                        break;
+                    default:
+                        l.push(r(s))
+                }
+                return {
+                    name: o,
+                    interrupt: !1,
+                    attributes: f,
+                    content: l,
+                    icount: n,
+                    indents: a,
+                    indent: e
+                }
+            }
+
+            function a(t, e, n) {
+                if ("object" != typeof e) return t(!1, e);
+                var r = e.interrupt ? 1 : e.content.length;
+
+                function i() {
+                    for (; e.content.length;) {
+                        var i = e.content.shift();
+                        if (void 0 !== i) {
+                            if (o(i)) return;
+                            a(t, i)
+                        }
+                    }
+                    t(!1, (r > 1 ? e.indents : "") + (e.name ? "</" + e.name + ">" : "") + (e.indent && !n ? "\n" : "")), n && n()
+                }
+
+                function o(e) {
+                    return !!e.interrupt && (e.interrupt.append = t, e.interrupt.end = i, e.interrupt = !1, t(!0), !0)
+                }
+                if (t(!1, e.indents + (e.name ? "<" + e.name : "") + (e.attributes.length ? " " + e.attributes.join(" ") : "") + (r ? e.name ? ">" : "" : e.name ? "/>" : "") + (e.indent && r > 1 ? "\n" : "")), !r) return t(!1, e.indent ? "\n" : "");
+                o(e) || i()
+            }
+            t.exports = function(t, n) {
+                "object" != typeof n && (n = {
+                    indent: n
+                });
+                var r, s, c = n.stream ? new i : null,
+                    f = "",
+                    l = !1,
+                    p = n.indent ? !0 === n.indent ? o : n.indent : "",
+                    h = !0;
+
+                function d(t) {
+                    h ? e.nextTick(t) : t()
+                }
+
+                function v(t, e) {
+                    if (void 0 !== e && (f += e), t && !l && (c = c || new i, l = !0), t && l) {
+                        var n = f;
+                        d(function() {
+                            c.emit("data", n)
+                        }), f = ""
+                    }
+                }
+
+                function y(t, e) {
+                    a(v, u(t, p, p ? 1 : 0), e)
+                }
+
+                function g() {
+                    if (c) {
+                        var t = f;
+                        d(function() {
+                            c.emit("data", t), c.emit("end"), c.readable = !1, c.emit("close")
+                        })
+                    }
+                }
+                return d(function() {
+                    h = !1
+                }), n.declaration && (r = n.declaration, s = {
+                    version: "1.0",
+                    encoding: r.encoding || "UTF-8"
+                }, r.standalone && (s.standalone = r.standalone), y({
+                    "?xml": {
+                        _attr: s
+                    }
+                }), f = f.replace("/>", "?>")), t && t.forEach ? t.forEach(function(e, n) {
+                    var r;
+                    n + 1 === t.length && (r = g), y(e, r)
+                }) : y(t, g), c ? (c.readable = !0, c) : f
+            }, t.exports.element = t.exports.Element = function() {
+                var t = {
+                    _elem: u(Array.prototype.slice.call(arguments)),
+                    push: function(t) {
+                        if (!this.append) throw new Error("not assigned to a parent!");
+                        var e = this,
+                            n = this._elem.indent;
+                        a(this.append, u(t, n, this._elem.icount + (n ? 1 : 0)), function() {
+                            e.append(!0)
+                        })
+                    },
+                    close: function(t) {
+                        void 0 !== t && this.push(t), this.end && this.end()
+                    }
+                };
+                return t
+            }
+        }).call(e, n(34))
+    }, function(t, e) {
+        var n = {
+            "&": "&amp;",
+            '"': "&quot;",
+            "'": "&apos;",
+            "<": "&lt;",
+            ">": "&gt;"
+        };
+        t.exports = function(t) {
+            return t && t.replace ? t.replace(/([&"<>'])/g, function(t, e) {
+                return n[e]
+            }) : t
+        }
+    }, function(t, e, n) {
+        t.exports = i;
+        var r = n(115).EventEmitter;
+
+        function i() {
+            r.call(this)
+        }
+        n(35)(i, r), i.Readable = n(116), i.Writable = n(459), i.Duplex = n(460), i.Transform = n(461), i.PassThrough = n(462), i.Stream = i, i.prototype.pipe = function(t, e) {
+            var n = this;
+
+            function i(e) {
+                t.writable && !1 === t.write(e) && n.pause && n.pause()
+            }
+
+            function o() {
+                n.readable && n.resume && n.resume()
+            }
+            n.on("data", i), t.on("drain", o), t._isStdio || e && !1 === e.end || (n.on("end", a), n.on("close", s));
+            var u = !1;
+
+            function a() {
+                u || (u = !0, t.end())
+            }
+
+            function s() {
+                u || (u = !0, "function" == typeof t.destroy && t.destroy())
+            }
+
+            function c(t) {
+                if (f(), 0 === r.listenerCount(this, "error")) throw t
+            }
+
+            function f() {
+                n.removeListener("data", i), t.removeListener("drain", o), n.removeListener("end", a), n.removeListener("close", s), n.removeListener("error", c), t.removeListener("error", c), n.removeListener("end", f), n.removeListener("close", f), t.removeListener("close", f)
+            }
+            return n.on("error", c), t.on("error", c), n.on("end", f), n.on("close", f), t.on("close", f), t.emit("pipe", n), t
+        }
+    }, function(t, e) {}, function(t, e, n) {
+        "use strict";
+        var r = n(80).Buffer,
+            i = n(455);
+        t.exports = function() {
+            function t() {
+                ! function(t, e) {
+                    if (!(t instanceof e)) throw new TypeError("Cannot call a class as a function")
+                }(this, t), this.head = null, this.tail = null, this.length = 0
+            }
+            return t.prototype.push = function(t) {
+                var e = {
+                    data: t,
+                    next: null
+                };
+                this.length > 0 ? this.tail.next = e : this.head = e, this.tail = e, ++this.length
+            }, t.prototype.unshift = function(t) {
+                var e = {
+                    data: t,
+                    next: this.head
+                };
+                0 === this.length && (this.tail = e), this.head = e, ++this.length
+            }, t.prototype.shift = function() {
+                if (0 !== this.length) {
+                    var t = this.head.data;
+                    return 1 === this.length ? this.head = this.tail = null : this.head = this.head.next, --this.length, t
+                }
+            }, t.prototype.clear = function() {
+                this.head = this.tail = null, this.length = 0
+            }, t.prototype.join = function(t) {
+                if (0 === this.length) return "";
+                for (var e = this.head, n = "" + e.data; e = e.next;) n += t + e.data;
+                return n
+            }, t.prototype.concat = function(t) {
+                if (0 === this.length) return r.alloc(0);
+                if (1 === this.length) return this.head.data;
+                for (var e, n, i, o = r.allocUnsafe(t >>> 0), u = this.head, a = 0; u;) e = u.data, n = o, i = a, e.copy(n, i), a += u.data.length, u = u.next;
+                return o
+            }, t
+        }(), i && i.inspect && i.inspect.custom && (t.exports.prototype[i.inspect.custom] = function() {
+            var t = i.inspect({
+                length: this.length
+            });
+            return this.constructor.name + " " + t
+        })
+    }, function(t, e) {}, function(t, e, n) {
+        (function(t, e) {
+            ! function(t, n) {
+                "use strict";
+                if (!t.setImmediate) {
+                    var r, i, o, u, a, s = 1,
+                        c = {},
+                        f = !1,
+                        l = t.document,
+                        p = Object.getPrototypeOf && Object.getPrototypeOf(t);
+                    p = p && p.setTimeout ? p : t, "[object process]" === {}.toString.call(t.process) ? r = function(t) {
+                        e.nextTick(function() {
+                            d(t)
+                        })
+                    } : ! function() {
+                        if (t.postMessage && !t.importScripts) {
+                            var e = !0,
+                                n = t.onmessage;
+                            return t.onmessage = function() {
+                                e = !1
+                            }, t.postMessage("", "*"), t.onmessage = n, e
+                        }
+                    }() ? t.MessageChannel ? ((o = new MessageChannel).port1.onmessage = function(t) {
+                        d(t.data)
+                    }, r = function(t) {
+                        o.port2.postMessage(t)
+                    }) : l && "onreadystatechange" in l.createElement("script") ? (i = l.documentElement, r = function(t) {
+                        var e = l.createElement("script");
+                        e.onreadystatechange = function() {
+                            d(t), e.onreadystatechange = null, i.removeChild(e), e = null
+                        }, i.appendChild(e)
+                    }) : r = function(t) {
+                        setTimeout(d, 0, t)
+                    } : (u = "setImmediate$" + Math.random() + "$", a = function(e) {
+                        e.source === t && "string" == typeof e.data && 0 === e.data.indexOf(u) && d(+e.data.slice(u.length))
+                    }, t.addEventListener ? t.addEventListener("message", a, !1) : t.attachEvent("onmessage", a), r = function(e) {
+                        t.postMessage(u + e, "*")
+                    }), p.setImmediate = function(t) {
+                        "function" != typeof t && (t = new Function("" + t));
+                        for (var e = new Array(arguments.length - 1), n = 0; n < e.length; n++) e[n] = arguments[n + 1];
+                        var i = {
+                            callback: t,
+                            args: e
+                        };
+                        return c[s] = i, r(s), s++
+                    }, p.clearImmediate = h
+                }
+
+                function h(t) {
+                    delete c[t]
+                }
+
+                function d(t) {
+                    if (f) setTimeout(d, 0, t);
+                    else {
+                        var e = c[t];
+                        if (e) {
+                            f = !0;
+                            try {
+                                ! function(t) {
+                                    var e = t.callback,
+                                        r = t.args;
+                                    switch (r.length) {
+                                        case 0:
+                                            e();
+                                            break;
+                                        case 1:
+                                            e(r[0]);
+                                            break;
+                                        case 2:
+                                            e(r[0], r[1]);
+                                            break;
+                                        case 3:
+                                            e(r[0], r[1], r[2]);
+                                            break;
+                                        default:
+                                            e.apply(n, r)
+                                    }
+                                }(e)
+                            } finally {
+                                h(t), f = !1
+                            }
+                        }
+                    }
+                }
+            }("undefined" == typeof self ? void 0 === t ? this : t : self)
+        }).call(e, n(11), n(34))
+    }, function(t, e, n) {
+        (function(e) {
+            function n(t) {
+                try {
+                    if (!e.localStorage) return !1
+                } catch (t) {
+                    return !1
+                }
+                var n = e.localStorage[t];
+                return null != n && "true" === String(n).toLowerCase()
+            }
+            t.exports = function(t, e) {
+                if (n("noDeprecation")) return t;
+                var r = !1;
+                return function() {
+                    if (!r) {
+                        if (n("throwDeprecation")) throw new Error(e);
+                        n("traceDeprecation") ? console.trace(e) : console.warn(e), r = !0
+                    }
+                    return t.apply(this, arguments)
+                }
+            }
+        }).call(e, n(11))
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = o;
+        var r = n(192),
+            i = n(51);
+
+        function o(t) {
+            if (!(this instanceof o)) return new o(t);
+            r.call(this, t)
+        }
+        i.inherits = n(35), i.inherits(o, r), o.prototype._transform = function(t, e, n) {
+            n(null, t)
+        }
+    }, function(t, e, n) {
+        t.exports = n(117)
+    }, function(t, e, n) {
+        t.exports = n(21)
+    }, function(t, e, n) {
+        t.exports = n(116).Transform
+    }, function(t, e, n) {
+        t.exports = n(116).PassThrough
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(193),
+            i = n(195),
+            o = n(468);
+        t.exports = function(t) {
+            var e, u = r(arguments[1]);
+            return u.normalizer || 0 !== (e = u.length = i(u.length, t.length, u.async)) && (u.primitive ? !1 === e ? u.normalizer = n(495) : e > 1 && (u.normalizer = n(496)(e)) : u.normalizer = !1 === e ? n(497)() : 1 === e ? n(501)() : n(502)(e)), u.async && n(503), u.promise && n(504), u.dispose && n(510), u.maxAge && n(511), u.max && n(514), u.refCounter && n(516), o(t, u)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(465),
+            i = Math.abs,
+            o = Math.floor;
+        t.exports = function(t) {
+            return isNaN(t) ? 0 : 0 !== (t = Number(t)) && isFinite(t) ? r(t) * o(i(t)) : t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(466)() ? Math.sign : n(467)
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function() {
+            var t = Math.sign;
+            return "function" == typeof t && (1 === t(10) && -1 === t(-20))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            return t = Number(t), isNaN(t) || 0 === t ? t : t > 0 ? 1 : -1
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(12),
+            i = n(81),
+            o = n(24),
+            u = n(470),
+            a = n(195);
+        t.exports = function t(e) {
+            var n, s, c;
+            if (r(e), (n = Object(arguments[1])).async && n.promise) throw new Error("Options 'async' and 'promise' cannot be used together");
+            return hasOwnProperty.call(e, "__memoized__") && !n.force ? e : (s = a(n.length, e.length, n.async && o.async), c = u(e, s, n), i(o, function(t, e) {
+                n[e] && t(n[e], c, n)
+            }), t.__profiler__ && t.__profiler__(c), c.updateEnv(), c.memoized)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(12),
+            i = n(36),
+            o = Function.prototype.bind,
+            u = Function.prototype.call,
+            a = Object.keys,
+            s = Object.prototype.propertyIsEnumerable;
+        t.exports = function(t, e) {
+            return function(n, c) {
+                var f, l = arguments[2],
+                    p = arguments[3];
+                return n = Object(i(n)), r(c), f = a(n), p && f.sort("function" == typeof p ? o.call(p, n) : void 0), "function" != typeof t && (t = f[t]), u.call(t, f, function(t, r) {
+                    return s.call(n, t) ? u.call(c, l, n[t], t, n, r) : e
+                })
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(471),
+            i = n(197),
+            o = n(82),
+            u = n(481).methods,
+            a = n(482),
+            s = n(494),
+            c = Function.prototype.apply,
+            f = Function.prototype.call,
+            l = Object.create,
+            p = Object.defineProperties,
+            h = u.on,
+            d = u.emit;
+        t.exports = function(t, e, n) {
+            var u, v, y, g, m, _, b, w, x, S, E, A, O, j, k, I = l(null);
+            return v = !1 !== e ? e : isNaN(t.length) ? 1 : t.length, n.normalizer && (S = s(n.normalizer), y = S.get, g = S.set, m = S.delete, _ = S.clear), null != n.resolvers && (k = a(n.resolvers)), j = y ? i(function(e) {
+                var n, i, o = arguments;
+                if (k && (o = k(o)), null !== (n = y(o)) && hasOwnProperty.call(I, n)) return E && u.emit("get", n, o, this), I[n];
+                if (i = 1 === o.length ? f.call(t, this, o[0]) : c.call(t, this, o), null === n) {
+                    if (null !== (n = y(o))) throw r("Circular invocation", "CIRCULAR_INVOCATION");
+                    n = g(o)
+                } else if (hasOwnProperty.call(I, n)) throw r("Circular invocation", "CIRCULAR_INVOCATION");
+                return I[n] = i, A && u.emit("set", n, null, i), i
+            }, v) : 0 === e ? function() {
+                var e;
+                if (hasOwnProperty.call(I, "data")) return E && u.emit("get", "data", arguments, this), I.data;
+                if (e = arguments.length ? c.call(t, this, arguments) : f.call(t, this), hasOwnProperty.call(I, "data")) throw r("Circular invocation", "CIRCULAR_INVOCATION");
+                return I.data = e, A && u.emit("set", "data", null, e), e
+            } : function(e) {
+                var n, i, o = arguments;
+                if (k && (o = k(arguments)), i = String(o[0]), hasOwnProperty.call(I, i)) return E && u.emit("get", i, o, this), I[i];
+                if (n = 1 === o.length ? f.call(t, this, o[0]) : c.call(t, this, o), hasOwnProperty.call(I, i)) throw r("Circular invocation", "CIRCULAR_INVOCATION");
+                return I[i] = n, A && u.emit("set", i, null, n), n
+            }, u = {
+                original: t,
+                memoized: j,
+                profileName: n.profileName,
+                get: function(t) {
+                    return k && (t = k(t)), y ? y(t) : String(t[0])
+                },
+                has: function(t) {
+                    return hasOwnProperty.call(I, t)
+                },
+                delete: function(t) {
+                    var e;
+                    hasOwnProperty.call(I, t) && (m && m(t), e = I[t], delete I[t], O && u.emit("delete", t, e))
+                },
+                clear: function() {
+                    var t = I;
+                    _ && _(), I = l(null), u.emit("clear", t)
+                },
+                on: function(t, e) {
+                    return "get" === t ? E = !0 : "set" === t ? A = !0 : "delete" === t && (O = !0), h.call(this, t, e)
+                },
+                emit: d,
+                updateEnv: function() {
+                    t = u.original
+                }
+            }, b = y ? i(function(t) {
+                var e, n = arguments;
+                k && (n = k(n)), null !== (e = y(n)) && u.delete(e)
+            }, v) : 0 === e ? function() {
+                return u.delete("data")
+            } : function(t) {
+                return k && (t = k(arguments)[0]), u.delete(t)
+            }, w = i(function() {
+                var t, n = arguments;
+                return 0 === e ? I.data : (k && (n = k(n)), t = y ? y(n) : String(n[0]), I[t])
+            }), x = i(function() {
+                var t, n = arguments;
+                return 0 === e ? u.has("data") : (k && (n = k(n)), null !== (t = y ? y(n) : String(n[0])) && u.has(t))
+            }), p(j, {
+                __memoized__: o(!0),
+                delete: o(b),
+                clear: o(u.clear),
+                _get: o(w),
+                _has: o(x)
+            }), u
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(196),
+            i = n(477),
+            o = n(22),
+            u = Error.captureStackTrace;
+        e = t.exports = function(t) {
+            var n = new Error(t),
+                a = arguments[1],
+                s = arguments[2];
+            return o(s) || i(a) && (s = a, a = null), o(s) && r(n, s), o(a) && (n.code = a), u && u(n, e), n
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function() {
+            var t, e = Object.assign;
+            return "function" == typeof e && (e(t = {
+                foo: "raz"
+            }, {
+                bar: "dwa"
+            }, {
+                trzy: "trzy"
+            }), t.foo + t.bar + t.trzy === "razdwatrzy")
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(474),
+            i = n(36),
+            o = Math.max;
+        t.exports = function(t, e) {
+            var n, u, a, s = o(arguments.length, 2);
+            for (t = Object(i(t)), a = function(r) {
+                try {
+                    t[r] = e[r]
+                } catch (t) {
+                    n || (n = t)
+                }
+            }, u = 1; u < s; ++u) e = arguments[u], r(e).forEach(a);
+            if (void 0 !== n) throw n;
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(475)() ? Object.keys : n(476)
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function() {
+            try {
+                return Object.keys("primitive"), !0
+            } catch (t) {
+                return !1
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(22),
+            i = Object.keys;
+        t.exports = function(t) {
+            return i(r(t) ? Object(t) : t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(22),
+            i = {
+                function: !0,
+                object: !0
+            };
+        t.exports = function(t) {
+            return r(t) && i[typeof t] || !1
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(479)() ? String.prototype.contains : n(480)
+    }, function(t, e, n) {
+        "use strict";
+        var r = "razdwatrzy";
+        t.exports = function() {
+            return "function" == typeof r.contains && (!0 === r.contains("dwa") && !1 === r.contains("foo"))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = String.prototype.indexOf;
+        t.exports = function(t) {
+            return r.call(this, t, arguments[1]) > -1
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r, i, o, u, a, s, c, f = n(82),
+            l = n(12),
+            p = Function.prototype.apply,
+            h = Function.prototype.call,
+            d = Object.create,
+            v = Object.defineProperty,
+            y = Object.defineProperties,
+            g = Object.prototype.hasOwnProperty,
+            m = {
+                configurable: !0,
+                enumerable: !1,
+                writable: !0
+            };
+        a = {
+            on: r = function(t, e) {
+                var n;
+                return l(e), g.call(this, "__ee__") ? n = this.__ee__ : (n = m.value = d(null), v(this, "__ee__", m), m.value = null), n[t] ? "object" == typeof n[t] ? n[t].push(e) : n[t] = [n[t], e] : n[t] = e, this
+            },
+            once: i = function(t, e) {
+                var n, i;
+                return l(e), i = this, r.call(this, t, n = function() {
+                    o.call(i, t, n), p.call(e, this, arguments)
+                }), n.__eeOnceListener__ = e, this
+            },
+            off: o = function(t, e) {
+                var n, r, i, o;
+                if (l(e), !g.call(this, "__ee__")) return this;
+                if (!(n = this.__ee__)[t]) return this;
+                if ("object" == typeof(r = n[t]))
+                    for (o = 0; i = r[o]; ++o) i !== e && i.__eeOnceListener__ !== e || (2 === r.length ? n[t] = r[o ? 0 : 1] : r.splice(o, 1));
+                else r !== e && r.__eeOnceListener__ !== e || delete n[t];
+                return this
+            },
+            emit: u = function(t) {
+                var e, n, r, i, o;
+                if (g.call(this, "__ee__") && (i = this.__ee__[t]))
+                    if ("object" == typeof i) {
+                        for (n = arguments.length, o = new Array(n - 1), e = 1; e < n; ++e) o[e - 1] = arguments[e];
+                        for (i = i.slice(), e = 0; r = i[e]; ++e) p.call(r, this, o)
+                    } else switch (arguments.length) {
+                        case 1:
+                            h.call(i, this);
+                            break;
+                        case 2:
+                            h.call(i, this, arguments[1]);
+                            break;
+                        case 3:
+                            h.call(i, this, arguments[1], arguments[2]);
+                            break;
+                        default:
+                            for (n = arguments.length, o = new Array(n - 1), e = 1; e < n; ++e) o[e - 1] = arguments[e];
+                            p.call(i, this, o)
+                    }
+            }
+        }, s = {
+            on: f(r),
+            once: f(i),
+            off: f(o),
+            emit: f(u)
+        }, c = y({}, s), t.exports = e = function(t) {
+            return null == t ? d(c) : y(Object(t), s)
+        }, e.methods = a
+    }, function(t, e, n) {
+        "use strict";
+        var r, i = n(483),
+            o = n(22),
+            u = n(12),
+            a = Array.prototype.slice;
+        r = function(t) {
+            return this.map(function(e, n) {
+                return e ? e(t[n]) : t[n]
+            }).concat(a.call(t, this.length))
+        }, t.exports = function(t) {
+            return (t = i(t)).forEach(function(t) {
+                o(t) && u(t)
+            }), r.bind(t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(119),
+            i = Array.isArray;
+        t.exports = function(t) {
+            return i(t) ? t : r(t)
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function() {
+            var t, e, n = Array.from;
+            return "function" == typeof n && (e = n(t = ["raz", "dwa"]), Boolean(e && e !== t && "dwa" === e[1]))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(486).iterator,
+            i = n(491),
+            o = n(492),
+            u = n(23),
+            a = n(12),
+            s = n(36),
+            c = n(22),
+            f = n(493),
+            l = Array.isArray,
+            p = Function.prototype.call,
+            h = {
+                configurable: !0,
+                enumerable: !0,
+                writable: !0,
+                value: null
+            },
+            d = Object.defineProperty;
+        t.exports = function(t) {
+            var e, n, v, y, g, m, _, b, w, x, S = arguments[1],
+                E = arguments[2];
+            if (t = Object(s(t)), c(S) && a(S), this && this !== Array && o(this)) e = this;
+            else {
+                if (!S) {
+                    if (i(t)) return 1 !== (g = t.length) ? Array.apply(null, t) : ((y = new Array(1))[0] = t[0], y);
+                    if (l(t)) {
+                        for (y = new Array(g = t.length), n = 0; n < g; ++n) y[n] = t[n];
+                        return y
+                    }
+                }
+                y = []
+            }
+            if (!l(t))
+                if (void 0 !== (w = t[r])) {
+                    for (_ = a(w).call(t), e && (y = new e), b = _.next(), n = 0; !b.done;) x = S ? p.call(S, E, b.value, n) : b.value, e ? (h.value = x, d(y, n, h)) : y[n] = x, b = _.next(), ++n;
+                    g = n
+                } else if (f(t)) {
+                    for (g = t.length, e && (y = new e), n = 0, v = 0; n < g; ++n) x = t[n], n + 1 < g && (m = x.charCodeAt(0)) >= 55296 && m <= 56319 && (x += t[++n]), x = S ? p.call(S, E, x, v) : x, e ? (h.value = x, d(y, v, h)) : y[v] = x, ++v;
+                    g = v
+                }
+            if (void 0 === g)
+                for (g = u(t.length), e && (y = new e(g)), n = 0; n < g; ++n) x = S ? p.call(S, E, t[n], n) : t[n], e ? (h.value = x, d(y, n, h)) : y[n] = x;
+            return e && (h.value = null, y.length = g), y
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(487)() ? Symbol : n(488)
+    }, function(t, e, n) {
+        "use strict";
+        var r = {
+            object: !0,
+            symbol: !0
+        };
+        t.exports = function() {
+            var t;
+            if ("function" != typeof Symbol) return !1;
+            t = Symbol("test symbol");
+            try {
+                String(t)
+            } catch (t) {
+                return !1
+            }
+            return !!r[typeof Symbol.iterator] && (!!r[typeof Symbol.toPrimitive] && !!r[typeof Symbol.toStringTag])
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r, i, o, u, a = n(82),
+            s = n(489),
+            c = Object.create,
+            f = Object.defineProperties,
+            l = Object.defineProperty,
+            p = Object.prototype,
+            h = c(null);
+        if ("function" == typeof Symbol) {
+            r = Symbol;
+            try {
+                String(r()), u = !0
+            } catch (t) {}
+        }
+        var d, v = (d = c(null), function(t) {
+            for (var e, n, r = 0; d[t + (r || "")];) ++r;
+            return d[t += r || ""] = !0, l(p, e = "@@" + t, a.gs(null, function(t) {
+                n || (n = !0, l(this, e, a(t)), n = !1)
+            })), e
+        });
+        o = function(t) {
+            if (this instanceof o) throw new TypeError("Symbol is not a constructor");
+            return i(t)
+        }, t.exports = i = function t(e) {
+            var n;
+            if (this instanceof t) throw new TypeError("Symbol is not a constructor");
+            return u ? r(e) : (n = c(o.prototype), e = void 0 === e ? "" : String(e), f(n, {
+                __description__: a("", e),
+                __name__: a("", v(e))
+            }))
+        }, f(i, {
+            for: a(function(t) {
+                return h[t] ? h[t] : h[t] = i(String(t))
+            }),
+            keyFor: a(function(t) {
+                var e;
+                for (e in s(t), h)
+                    if (h[e] === t) return e
+            }),
+            hasInstance: a("", r && r.hasInstance || i("hasInstance")),
+            isConcatSpreadable: a("", r && r.isConcatSpreadable || i("isConcatSpreadable")),
+            iterator: a("", r && r.iterator || i("iterator")),
+            match: a("", r && r.match || i("match")),
+            replace: a("", r && r.replace || i("replace")),
+            search: a("", r && r.search || i("search")),
+            species: a("", r && r.species || i("species")),
+            split: a("", r && r.split || i("split")),
+            toPrimitive: a("", r && r.toPrimitive || i("toPrimitive")),
+            toStringTag: a("", r && r.toStringTag || i("toStringTag")),
+            unscopables: a("", r && r.unscopables || i("unscopables"))
+        }), f(o.prototype, {
+            constructor: a(i),
+            toString: a("", function() {
+                return this.__name__
+            })
+        }), f(i.prototype, {
+            toString: a(function() {
+                return "Symbol (" + s(this).__description__ + ")"
+            }),
+            valueOf: a(function() {
+                return s(this)
+            })
+        }), l(i.prototype, i.toPrimitive, a("", function() {
+            var t = s(this);
+            return "symbol" == typeof t ? t : t.toString()
+        })), l(i.prototype, i.toStringTag, a("c", "Symbol")), l(o.prototype, i.toStringTag, a("c", i.prototype[i.toStringTag])), l(o.prototype, i.toPrimitive, a("c", i.prototype[i.toPrimitive]))
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(490);
+        t.exports = function(t) {
+            if (!r(t)) throw new TypeError(t + " is not a symbol");
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            return !!t && ("symbol" == typeof t || !!t.constructor && ("Symbol" === t.constructor.name && "Symbol" === t[t.constructor.toStringTag]))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = Object.prototype.toString,
+            i = r.call(function() {
+                return arguments
+            }());
+        t.exports = function(t) {
+            return r.call(t) === i
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = Object.prototype.toString,
+            i = r.call(n(194));
+        t.exports = function(t) {
+            return "function" == typeof t && r.call(t) === i
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = Object.prototype.toString,
+            i = r.call("");
+        t.exports = function(t) {
+            return "string" == typeof t || t && "object" == typeof t && (t instanceof String || r.call(t) === i) || !1
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(12);
+        t.exports = function(t) {
+            var e;
+            return "function" == typeof t ? {
+                set: t,
+                get: t
+            } : (e = {
+                get: r(t.get)
+            }, void 0 !== t.set ? (e.set = r(t.set), t.delete && (e.delete = r(t.delete)), t.clear && (e.clear = r(t.clear)), e) : (e.set = e.get, e))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            var e, n, r = t.length;
+            if (!r) return "";
+            for (e = String(t[n = 0]); --r;) e += "" + t[++n];
+            return e
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            return t ? function(e) {
+                for (var n = String(e[0]), r = 0, i = t; --i;) n += "" + e[++r];
+                return n
+            } : function() {
+                return ""
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(120),
+            i = Object.create;
+        t.exports = function() {
+            var t = 0,
+                e = [],
+                n = i(null);
+            return {
+                get: function(t) {
+                    var n, i = 0,
+                        o = e,
+                        u = t.length;
+                    if (0 === u) return o[u] || null;
+                    if (o = o[u]) {
+                        for (; i < u - 1;) {
+                            if (-1 === (n = r.call(o[0], t[i]))) return null;
+                            o = o[1][n], ++i
+                        }
+                        return -1 === (n = r.call(o[0], t[i])) ? null : o[1][n] || null
+                    }
+                    return null
+                },
+                set: function(i) {
+                    var o, u = 0,
+                        a = e,
+                        s = i.length;
+                    if (0 === s) a[s] = ++t;
+                    else {
+                        for (a[s] || (a[s] = [
+                            [],
+                            []
+                        ]), a = a[s]; u < s - 1;) - 1 === (o = r.call(a[0], i[u])) && (o = a[0].push(i[u]) - 1, a[1].push([
+                            [],
+                            []
+                        ])), a = a[1][o], ++u; - 1 === (o = r.call(a[0], i[u])) && (o = a[0].push(i[u]) - 1), a[1][o] = ++t
+                    }
+                    return n[t] = i, t
+                },
+                delete: function(t) {
+                    var i, o = 0,
+                        u = e,
+                        a = n[t],
+                        s = a.length,
+                        c = [];
+                    if (0 === s) delete u[s];
+                    else if (u = u[s]) {
+                        for (; o < s - 1;) {
+                            if (-1 === (i = r.call(u[0], a[o]))) return;
+                            c.push(u, i), u = u[1][i], ++o
+                        }
+                        if (-1 === (i = r.call(u[0], a[o]))) return;
+                        for (t = u[1][i], u[0].splice(i, 1), u[1].splice(i, 1); !u[0].length && c.length;) i = c.pop(), (u = c.pop())[0].splice(i, 1), u[1].splice(i, 1)
+                    }
+                    delete n[t]
+                },
+                clear: function() {
+                    e = [], n = i(null)
+                }
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = n(499)() ? Number.isNaN : n(500)
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function() {
+            var t = Number.isNaN;
+            return "function" == typeof t && (!t({}) && t(NaN) && !t(34))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = function(t) {
+            return t != t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(120);
+        t.exports = function() {
+            var t = 0,
+                e = [],
+                n = [];
+            return {
+                get: function(t) {
+                    var i = r.call(e, t[0]);
+                    return -1 === i ? null : n[i]
+                },
+                set: function(r) {
+                    return e.push(r[0]), n.push(++t), t
+                },
+                delete: function(t) {
+                    var i = r.call(n, t); - 1 !== i && (e.splice(i, 1), n.splice(i, 1))
+                },
+                clear: function() {
+                    e = [], n = []
+                }
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(120),
+            i = Object.create;
+        t.exports = function(t) {
+            var e = 0,
+                n = [
+                    [],
+                    []
+                ],
+                o = i(null);
+            return {
+                get: function(e) {
+                    for (var i, o = 0, u = n; o < t - 1;) {
+                        if (-1 === (i = r.call(u[0], e[o]))) return null;
+                        u = u[1][i], ++o
+                    }
+                    return -1 === (i = r.call(u[0], e[o])) ? null : u[1][i] || null
+                },
+                set: function(i) {
+                    for (var u, a = 0, s = n; a < t - 1;) - 1 === (u = r.call(s[0], i[a])) && (u = s[0].push(i[a]) - 1, s[1].push([
+                        [],
+                        []
+                    ])), s = s[1][u], ++a;
+                    return -1 === (u = r.call(s[0], i[a])) && (u = s[0].push(i[a]) - 1), s[1][u] = ++e, o[e] = i, e
+                },
+                delete: function(e) {
+                    for (var i, u = 0, a = n, s = [], c = o[e]; u < t - 1;) {
+                        if (-1 === (i = r.call(a[0], c[u]))) return;
+                        s.push(a, i), a = a[1][i], ++u
+                    }
+                    if (-1 !== (i = r.call(a[0], c[u]))) {
+                        for (e = a[1][i], a[0].splice(i, 1), a[1].splice(i, 1); !a[0].length && s.length;) i = s.pop(), (a = s.pop())[0].splice(i, 1), a[1].splice(i, 1);
+                        delete o[e]
+                    }
+                },
+                clear: function() {
+                    n = [
+                        [],
+                        []
+                    ], o = i(null)
+                }
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(119),
+            i = n(199),
+            o = n(198),
+            u = n(197),
+            a = n(121),
+            s = Array.prototype.slice,
+            c = Function.prototype.apply,
+            f = Object.create;
+        n(24).async = function(t, e) {
+            var n, l, p, h = f(null),
+                d = f(null),
+                v = e.memoized,
+                y = e.original;
+            e.memoized = u(function(t) {
+                var e = arguments,
+                    r = e[e.length - 1];
+                return "function" == typeof r && (n = r, e = s.call(e, 0, -1)), v.apply(l = this, p = e)
+            }, v);
+            try {
+                o(e.memoized, v)
+            } catch (t) {}
+            e.on("get", function(t) {
+                var r, i, o;
+                if (n) {
+                    if (h[t]) return "function" == typeof h[t] ? h[t] = [h[t], n] : h[t].push(n), void(n = null);
+                    r = n, i = l, o = p, n = l = p = null, a(function() {
+                        var u;
+                        hasOwnProperty.call(d, t) ? (u = d[t], e.emit("getasync", t, o, i), c.call(r, u.context, u.args)) : (n = r, l = i, p = o, v.apply(i, o))
+                    })
+                }
+            }), e.original = function() {
+                var t, i, o, u;
+                return n ? (t = r(arguments), i = function t(n) {
+                    var i, o, s = t.id;
+                    if (null != s) {
+                        if (delete t.id, i = h[s], delete h[s], i) return o = r(arguments), e.has(s) && (n ? e.delete(s) : (d[s] = {
+                            context: this,
+                            args: o
+                        }, e.emit("setasync", s, "function" == typeof i ? 1 : i.length))), "function" == typeof i ? u = c.call(i, this, o) : i.forEach(function(t) {
+                            u = c.call(t, this, o)
+                        }, this), u
+                    } else a(c.bind(t, this, arguments))
+                }, o = n, n = l = p = null, t.push(i), u = c.call(y, this, t), i.cb = o, n = i, u) : c.call(y, this, arguments)
+            }, e.on("set", function(t) {
+                n ? (h[t] ? "function" == typeof h[t] ? h[t] = [h[t], n.cb] : h[t].push(n.cb) : h[t] = n.cb, delete n.cb, n.id = t, n = null) : e.delete(t)
+            }), e.on("delete", function(t) {
+                var n;
+                hasOwnProperty.call(h, t) || d[t] && (n = d[t], delete d[t], e.emit("deleteasync", t, s.call(n.args, 1)))
+            }), e.on("clear", function() {
+                var t = d;
+                d = f(null), e.emit("clearasync", i(t, function(t) {
+                    return s.call(t.args, 1)
+                }))
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(199),
+            i = n(505),
+            o = n(506),
+            u = n(508),
+            a = n(200),
+            s = n(121),
+            c = Object.create,
+            f = i("then", "then:finally", "done", "done:finally");
+        n(24).promise = function(t, e) {
+            var n = c(null),
+                i = c(null),
+                l = c(null);
+            if (!0 === t) t = null;
+            else if (t = o(t), !f[t]) throw new TypeError("'" + u(t) + "' is not valid promise mode");
+            e.on("set", function(r, o, u) {
+                var c = !1;
+                if (!a(u)) return i[r] = u, void e.emit("setasync", r, 1);
+                n[r] = 1, l[r] = u;
+                var f = function(t) {
+                        var o = n[r];
+                        if (c) throw new Error("Memoizee error: Detected unordered then|done & finally resolution, which in turn makes proper detection of success/failure impossible (when in 'done:finally' mode)\nConsider to rely on 'then' or 'done' mode instead.");
+                        o && (delete n[r], i[r] = t, e.emit("setasync", r, o))
+                    },
+                    p = function() {
+                        c = !0, n[r] && (delete n[r], delete l[r], e.delete(r))
+                    },
+                    h = t;
+                if (h || (h = "then"), "then" === h) u.then(function(t) {
+                    s(f.bind(this, t))
+                }, function() {
+                    s(p)
+                });
+                else if ("done" === h) {
+                    if ("function" != typeof u.done) throw new Error("Memoizee error: Retrieved promise does not implement 'done' in 'done' mode");
+                    u.done(f, p)
+                } else if ("done:finally" === h) {
+                    if ("function" != typeof u.done) throw new Error("Memoizee error: Retrieved promise does not implement 'done' in 'done:finally' mode");
+                    if ("function" != typeof u.finally) throw new Error("Memoizee error: Retrieved promise does not implement 'finally' in 'done:finally' mode");
+                    u.done(f), u.finally(p)
+                }
+            }), e.on("get", function(t, r, i) {
+                var o;
+                if (n[t]) ++n[t];
+                else {
+                    o = l[t];
+                    var u = function() {
+                        e.emit("getasync", t, r, i)
+                    };
+                    a(o) ? "function" == typeof o.done ? o.done(u) : o.then(function() {
+                        s(u)
+                    }) : u()
+                }
+            }), e.on("delete", function(t) {
+                if (delete l[t], n[t]) delete n[t];
+                else if (hasOwnProperty.call(i, t)) {
+                    var r = i[t];
+                    delete i[t], e.emit("deleteasync", t, [r])
+                }
+            }), e.on("clear", function() {
+                var t = i;
+                i = c(null), n = c(null), l = c(null), e.emit("clearasync", r(t, function(t) {
+                    return [t]
+                }))
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = Array.prototype.forEach,
+            i = Object.create;
+        t.exports = function(t) {
+            var e = i(null);
+            return r.call(arguments, function(t) {
+                e[t] = !0
+            }), e
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(36),
+            i = n(507);
+        t.exports = function(t) {
+            return i(r(t))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(118);
+        t.exports = function(t) {
+            try {
+                return t && r(t.toString) ? t.toString() : String(t)
+            } catch (t) {
+                throw new TypeError("Passed argument cannot be stringifed")
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(509),
+            i = /[\n\r\u2028\u2029]/g;
+        t.exports = function(t) {
+            var e = r(t);
+            return e.length > 100 && (e = e.slice(0, 99) + "…"), e = e.replace(i, function(t) {
+                return JSON.stringify(t).slice(1, -1)
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(118);
+        t.exports = function(t) {
+            try {
+                return t && r(t.toString) ? t.toString() : String(t)
+            } catch (t) {
+                return "[Non-coercible (to string) value]"
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(12),
+            i = n(81),
+            o = n(24),
+            u = Function.prototype.apply;
+        o.dispose = function(t, e, n) {
+            var a;
+            if (r(t), n.async && o.async || n.promise && o.promise) return e.on("deleteasync", a = function(e, n) {
+                u.call(t, null, n)
+            }), void e.on("clearasync", function(t) {
+                i(t, function(t, e) {
+                    a(e, t)
+                })
+            });
+            e.on("delete", a = function(e, n) {
+                t(n)
+            }), e.on("clear", function(t) {
+                i(t, function(t, e) {
+                    a(e, t)
+                })
+            })
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(119),
+            i = n(81),
+            o = n(121),
+            u = n(200),
+            a = n(512),
+            s = n(24),
+            c = Function.prototype,
+            f = Math.max,
+            l = Math.min,
+            p = Object.create;
+        s.maxAge = function(t, e, n) {
+            var h, d, v, y;
+            (t = a(t)) && (h = p(null), d = n.async && s.async || n.promise && s.promise ? "async" : "", e.on("set" + d, function(n) {
+                h[n] = setTimeout(function() {
+                    e.delete(n)
+                }, t), "function" == typeof h[n].unref && h[n].unref(), y && (y[n] && "nextTick" !== y[n] && clearTimeout(y[n]), y[n] = setTimeout(function() {
+                    delete y[n]
+                }, v), "function" == typeof y[n].unref && y[n].unref())
+            }), e.on("delete" + d, function(t) {
+                clearTimeout(h[t]), delete h[t], y && ("nextTick" !== y[t] && clearTimeout(y[t]), delete y[t])
+            }), n.preFetch && (v = !0 === n.preFetch || isNaN(n.preFetch) ? .333 : f(l(Number(n.preFetch), 1), 0)) && (y = {}, v = (1 - v) * t, e.on("get" + d, function(t, i, a) {
+                y[t] || (y[t] = "nextTick", o(function() {
+                    var o;
+                    "nextTick" === y[t] && (delete y[t], e.delete(t), n.async && (i = r(i)).push(c), o = e.memoized.apply(a, i), n.promise && u(o) && ("function" == typeof o.done ? o.done(c, c) : o.then(c, c)))
+                }))
+            })), e.on("clear" + d, function() {
+                i(h, function(t) {
+                    clearTimeout(t)
+                }), h = {}, y && (i(y, function(t) {
+                    "nextTick" !== t && clearTimeout(t)
+                }), y = {})
+            }))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(23),
+            i = n(513);
+        t.exports = function(t) {
+            if ((t = r(t)) > i) throw new TypeError(t + " exceeds maximum possible timeout");
+            return t
+        }
+    }, function(t, e, n) {
+        "use strict";
+        t.exports = 2147483647
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(23),
+            i = n(515),
+            o = n(24);
+        o.max = function(t, e, n) {
+            var u, a, s;
+            (t = r(t)) && (a = i(t), u = n.async && o.async || n.promise && o.promise ? "async" : "", e.on("set" + u, s = function(t) {
+                void 0 !== (t = a.hit(t)) && e.delete(t)
+            }), e.on("get" + u, s), e.on("delete" + u, a.delete), e.on("clear" + u, a.clear))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(23),
+            i = Object.create,
+            o = Object.prototype.hasOwnProperty;
+        t.exports = function(t) {
+            var e, n = 0,
+                u = 1,
+                a = i(null),
+                s = i(null),
+                c = 0;
+            return t = r(t), {
+                hit: function(r) {
+                    var i = s[r],
+                        f = ++c;
+                    if (a[f] = r, s[r] = f, !i) {
+                        if (++n <= t) return;
+                        return r = a[u], e(r), r
+                    }
+                    if (delete a[i], u === i)
+                        for (; !o.call(a, ++u);) continue
+                },
+                delete: e = function(t) {
+                    var e = s[t];
+                    if (e && (delete a[e], delete s[t], --n, u === e)) {
+                        if (!n) return c = 0, void(u = 1);
+                        for (; !o.call(a, ++u);) continue
+                    }
+                },
+                clear: function() {
+                    n = 0, u = 1, a = i(null), s = i(null), c = 0
+                }
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(82),
+            i = n(24),
+            o = Object.create,
+            u = Object.defineProperties;
+        i.refCounter = function(t, e, n) {
+            var a, s;
+            a = o(null), s = n.async && i.async || n.promise && i.promise ? "async" : "", e.on("set" + s, function(t, e) {
+                a[t] = e || 1
+            }), e.on("get" + s, function(t) {
+                ++a[t]
+            }), e.on("delete" + s, function(t) {
+                delete a[t]
+            }), e.on("clear" + s, function() {
+                a = {}
+            }), u(e.memoized, {
+                deleteRef: r(function() {
+                    var t = e.get(arguments);
+                    return null === t ? null : a[t] ? !--a[t] && (e.delete(t), !0) : null
+                }),
+                getRefCount: r(function() {
+                    var t = e.get(arguments);
+                    return null === t ? 0 : a[t] ? a[t] : 0
+                })
+            })
+        }
+    }, function(t, e, n) {
+        (function(e) {
+            var n, r;
+            n = void 0 !== e ? e : this, r = function(t) {
+                if (t.CSS && t.CSS.escape) return t.CSS.escape;
+                var e = function(t) {
+                    if (0 == arguments.length) throw new TypeError("`CSS.escape` requires an argument.");
+                    for (var e, n = String(t), r = n.length, i = -1, o = "", u = n.charCodeAt(0); ++i < r;) 0 != (e = n.charCodeAt(i)) ? o += e >= 1 && e <= 31 || 127 == e || 0 == i && e >= 48 && e <= 57 || 1 == i && e >= 48 && e <= 57 && 45 == u ? "\\" + e.toString(16) + " " : (0 != i || 1 != r || 45 != e) && (e >= 128 || 45 == e || 95 == e || e >= 48 && e <= 57 || e >= 65 && e <= 90 || e >= 97 && e <= 122) ? n.charAt(i) : "\\" + n.charAt(i) : o += "�";
+                    return o
+                };
+                return t.CSS || (t.CSS = {}), t.CSS.escape = e, e
+            }, t.exports = r(n)
+        }).call(e, n(11))
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        }), e.default = function() {
+            return {
+                statePlugins: {
+                    spec: {
+                        actions: u,
+                        selectors: l
+                    },
+                    configs: {
+                        reducers: s.default,
+                        actions: o,
+                        selectors: a
+                    }
+                }
+            }
+        };
+        var r = f(n(519)),
+            i = n(201),
+            o = c(n(204)),
+            u = c(n(541)),
+            a = c(n(542)),
+            s = f(n(543));
+
+        function c(t) {
+            if (t && t.__esModule) return t;
+            var e = {};
+            if (null != t)
+                for (var n in t) Object.prototype.hasOwnProperty.call(t, n) && (e[n] = t[n]);
+            return e.default = t, e
+        }
+
+        function f(t) {
+            return t && t.__esModule ? t : {
+                default: t
+            }
+        }
+        var l = {
+            getLocalConfig: function() {
+                return (0, i.parseYamlConfig)(r.default)
+            }
+        }
+    }, function(t, e) {
+        t.exports = '---\nurl: "https://petstore.swagger.io/v2/swagger.json"\ndom_id: "#swagger-ui"\nvalidatorUrl: "https://online.swagger.io/validator"\noauth2RedirectUrl: "http://localhost:3200/oauth2-redirect.html"\n'
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(521);
+        t.exports = r
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(522),
+            i = n(540);
+
+        function o(t) {
+            return function() {
+                throw new Error("Function " + t + " is deprecated and cannot be used.")
+            }
+        }
+        t.exports.Type = n(0), t.exports.Schema = n(38), t.exports.FAILSAFE_SCHEMA = n(122), t.exports.JSON_SCHEMA = n(203), t.exports.CORE_SCHEMA = n(202), t.exports.DEFAULT_SAFE_SCHEMA = n(53), t.exports.DEFAULT_FULL_SCHEMA = n(83), t.exports.load = r.load, t.exports.loadAll = r.loadAll, t.exports.safeLoad = r.safeLoad, t.exports.safeLoadAll = r.safeLoadAll, t.exports.dump = i.dump, t.exports.safeDump = i.safeDump, t.exports.YAMLException = n(52), t.exports.MINIMAL_SCHEMA = n(122), t.exports.SAFE_SCHEMA = n(53), t.exports.DEFAULT_SCHEMA = n(83), t.exports.scan = o("scan"), t.exports.parse = o("parse"), t.exports.compose = o("compose"), t.exports.addConstructor = o("addConstructor")
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(37),
+            i = n(52),
+            o = n(523),
+            u = n(53),
+            a = n(83),
+            s = Object.prototype.hasOwnProperty,
+            c = 1,
+            f = 2,
+            l = 3,
+            p = 4,
+            h = 1,
+            d = 2,
+            v = 3,
+            y = /[\x00-\x08\x0B\x0C\x0E-\x1F\x7F-\x84\x86-\x9F\uFFFE\uFFFF]|[\uD800-\uDBFF](?![\uDC00-\uDFFF])|(?:[^\uD800-\uDBFF]|^)[\uDC00-\uDFFF]/,
+            g = /[\x85\u2028\u2029]/,
+            m = /[,\[\]\{\}]/,
+            _ = /^(?:!|!!|![a-z\-]+!)$/i,
+            b = /^(?:!|[^,\[\]\{\}])(?:%[0-9a-f]{2}|[0-9a-z\-#;\/\?:@&=\+\$,_\.!~\*'\(\)\[\]])*$/i;
+
+        function w(t) {
+            return 10 === t || 13 === t
+        }
+
+        function x(t) {
+            return 9 === t || 32 === t
+        }
+
+        function S(t) {
+            return 9 === t || 32 === t || 10 === t || 13 === t
+        }
+
+        function E(t) {
+            return 44 === t || 91 === t || 93 === t || 123 === t || 125 === t
+        }
+
+        function A(t) {
+            var e;
+            return 48 <= t && t <= 57 ? t - 48 : 97 <= (e = 32 | t) && e <= 102 ? e - 97 + 10 : -1
+        }
+
+        function O(t) {
+            return 48 === t ? "\0" : 97 === t ? "" : 98 === t ? "\b" : 116 === t ? "\t" : 9 === t ? "\t" : 110 === t ? "\n" : 118 === t ? "\v" : 102 === t ? "\f" : 114 === t ? "\r" : 101 === t ? "" : 32 === t ? " " : 34 === t ? '"' : 47 === t ? "/" : 92 === t ? "\\" : 78 === t ? "" : 95 === t ? " " : 76 === t ? "\u2028" : 80 === t ? "\u2029" : ""
+        }
+
+        function j(t) {
+            return t <= 65535 ? String.fromCharCode(t) : String.fromCharCode(55296 + (t - 65536 >> 10), 56320 + (t - 65536 & 1023))
+        }
+        for (var k = new Array(256), I = new Array(256), M = 0; M < 256; M++) k[M] = O(M) ? 1 : 0, I[M] = O(M);
+
+        function T(t, e) {
+            return new i(e, new o(t.filename, t.input, t.position, t.line, t.position - t.lineStart))
+        }
+
+        function C(t, e) {
+            throw T(t, e)
+        }
+
+        function P(t, e) {
+            t.onWarning && t.onWarning.call(null, T(t, e))
+        }
+        var R = {
+            YAML: function(t, e, n) {
+                var r, i, o;
+                null !== t.version && C(t, "duplication of %YAML directive"), 1 !== n.length && C(t, "YAML directive accepts exactly one argument"), null === (r = /^([0-9]+)\.([0-9]+)$/.exec(n[0])) && C(t, "ill-formed argument of the YAML directive"), i = parseInt(r[1], 10), o = parseInt(r[2], 10), 1 !== i && C(t, "unacceptable YAML version of the document"), t.version = n[0], t.checkLineBreaks = o < 2, 1 !== o && 2 !== o && P(t, "unsupported YAML version of the document")
+            },
+            TAG: function(t, e, n) {
+                var r, i;
+                2 !== n.length && C(t, "TAG directive accepts exactly two arguments"), r = n[0], i = n[1], _.test(r) || C(t, "ill-formed tag handle (first argument) of the TAG directive"), s.call(t.tagMap, r) && C(t, 'there is a previously declared suffix for "' + r + '" tag handle'), b.test(i) || C(t, "ill-formed tag prefix (second argument) of the TAG directive"), t.tagMap[r] = i
+            }
+        };
+
+        function N(t, e, n, r) {
+            var i, o, u, a;
+            if (e < n) {
+                if (a = t.input.slice(e, n), r)
+                    for (i = 0, o = a.length; i < o; i += 1) 9 === (u = a.charCodeAt(i)) || 32 <= u && u <= 1114111 || C(t, "expected valid JSON character");
+                else y.test(a) && C(t, "the stream contains non-printable characters");
+                t.result += a
+            }
+        }
+
+        function L(t, e, n, i) {
+            var o, u, a, c;
+            for (r.isObject(n) || C(t, "cannot merge mappings; the provided source object is unacceptable"), a = 0, c = (o = Object.keys(n)).length; a < c; a += 1) u = o[a], s.call(e, u) || (e[u] = n[u], i[u] = !0)
+        }
+
+        function D(t, e, n, r, i, o, u, a) {
+            var c, f;
+            if (i = String(i), null === e && (e = {}), "tag:yaml.org,2002:merge" === r)
+                if (Array.isArray(o))
+                    for (c = 0, f = o.length; c < f; c += 1) L(t, e, o[c], n);
+                else L(t, e, o, n);
+            else t.json || s.call(n, i) || !s.call(e, i) || (t.line = u || t.line, t.position = a || t.position, C(t, "duplicated mapping key")), e[i] = o, delete n[i];
+            return e
+        }
+
+        function z(t) {
+            var e;
+            10 === (e = t.input.charCodeAt(t.position)) ? t.position++ : 13 === e ? (t.position++, 10 === t.input.charCodeAt(t.position) && t.position++) : C(t, "a line break is expected"), t.line += 1, t.lineStart = t.position
+        }
+
+        function F(t, e, n) {
+            for (var r = 0, i = t.input.charCodeAt(t.position); 0 !== i;) {
+                for (; x(i);) i = t.input.charCodeAt(++t.position);
+                if (e && 35 === i)
+                    do {
+                        i = t.input.charCodeAt(++t.position)
+                    } while (10 !== i && 13 !== i && 0 !== i);
+                if (!w(i)) break;
+                for (z(t), i = t.input.charCodeAt(t.position), r++, t.lineIndent = 0; 32 === i;) t.lineIndent++, i = t.input.charCodeAt(++t.position)
+            }
+            return -1 !== n && 0 !== r && t.lineIndent < n && P(t, "deficient indentation"), r
+        }
+
+        function U(t) {
+            var e, n = t.position;
+            return !(45 !== (e = t.input.charCodeAt(n)) && 46 !== e || e !== t.input.charCodeAt(n + 1) || e !== t.input.charCodeAt(n + 2) || (n += 3, 0 !== (e = t.input.charCodeAt(n)) && !S(e)))
+        }
+
+        function B(t, e) {
+            1 === e ? t.result += " " : e > 1 && (t.result += r.repeat("\n", e - 1))
+        }
+
+        function q(t, e) {
+            var n, r, i = t.tag,
+                o = t.anchor,
+                u = [],
+                a = !1;
+            for (null !== t.anchor && (t.anchorMap[t.anchor] = u), r = t.input.charCodeAt(t.position); 0 !== r && 45 === r && S(t.input.charCodeAt(t.position + 1));)
+                if (a = !0, t.position++, F(t, !0, -1) && t.lineIndent <= e) u.push(null), r = t.input.charCodeAt(t.position);
+                else if (n = t.line, V(t, e, l, !1, !0), u.push(t.result), F(t, !0, -1), r = t.input.charCodeAt(t.position), (t.line === n || t.lineIndent > e) && 0 !== r) C(t, "bad indentation of a sequence entry");
+                else if (t.lineIndent < e) break;
+            return !!a && (t.tag = i, t.anchor = o, t.kind = "sequence", t.result = u, !0)
+        }
+
+        function Y(t) {
+            var e, n, r, i, o = !1,
+                u = !1;
+            if (33 !== (i = t.input.charCodeAt(t.position))) return !1;
+            if (null !== t.tag && C(t, "duplication of a tag property"), 60 === (i = t.input.charCodeAt(++t.position)) ? (o = !0, i = t.input.charCodeAt(++t.position)) : 33 === i ? (u = !0, n = "!!", i = t.input.charCodeAt(++t.position)) : n = "!", e = t.position, o) {
+                do {
+                    i = t.input.charCodeAt(++t.position)
+                } while (0 !== i && 62 !== i);
+                t.position < t.length ? (r = t.input.slice(e, t.position), i = t.input.charCodeAt(++t.position)) : C(t, "unexpected end of the stream within a verbatim tag")
+            } else {
+                for (; 0 !== i && !S(i);) 33 === i && (u ? C(t, "tag suffix cannot contain exclamation marks") : (n = t.input.slice(e - 1, t.position + 1), _.test(n) || C(t, "named tag handle cannot contain such characters"), u = !0, e = t.position + 1)), i = t.input.charCodeAt(++t.position);
+                r = t.input.slice(e, t.position), m.test(r) && C(t, "tag suffix cannot contain flow indicator characters")
+            }
+            return r && !b.test(r) && C(t, "tag name cannot contain such characters: " + r), o ? t.tag = r : s.call(t.tagMap, n) ? t.tag = t.tagMap[n] + r : "!" === n ? t.tag = "!" + r : "!!" === n ? t.tag = "tag:yaml.org,2002:" + r : C(t, 'undeclared tag handle "' + n + '"'), !0
+        }
+
+        function W(t) {
+            var e, n;
+            if (38 !== (n = t.input.charCodeAt(t.position))) return !1;
+            for (null !== t.anchor && C(t, "duplication of an anchor property"), n = t.input.charCodeAt(++t.position), e = t.position; 0 !== n && !S(n) && !E(n);) n = t.input.charCodeAt(++t.position);
+            return t.position === e && C(t, "name of an anchor node must contain at least one character"), t.anchor = t.input.slice(e, t.position), !0
+        }
+
+        function V(t, e, n, i, o) {
+            var u, a, y, g, m, _, b, O, M = 1,
+                T = !1,
+                P = !1;
+            if (null !== t.listener && t.listener("open", t), t.tag = null, t.anchor = null, t.kind = null, t.result = null, u = a = y = p === n || l === n, i && F(t, !0, -1) && (T = !0, t.lineIndent > e ? M = 1 : t.lineIndent === e ? M = 0 : t.lineIndent < e && (M = -1)), 1 === M)
+                for (; Y(t) || W(t);) F(t, !0, -1) ? (T = !0, y = u, t.lineIndent > e ? M = 1 : t.lineIndent === e ? M = 0 : t.lineIndent < e && (M = -1)) : y = !1;
+            if (y && (y = T || o), 1 !== M && p !== n || (b = c === n || f === n ? e : e + 1, O = t.position - t.lineStart, 1 === M ? y && (q(t, O) || function(t, e, n) {
+                var r, i, o, u, a, s = t.tag,
+                    c = t.anchor,
+                    l = {},
+                    h = {},
+                    d = null,
+                    v = null,
+                    y = null,
+                    g = !1,
+                    m = !1;
+                for (null !== t.anchor && (t.anchorMap[t.anchor] = l), a = t.input.charCodeAt(t.position); 0 !== a;) {
+                    if (r = t.input.charCodeAt(t.position + 1), o = t.line, u = t.position, 63 !== a && 58 !== a || !S(r)) {
+                        if (!V(t, n, f, !1, !0)) break;
+                        if (t.line === o) {
+                            for (a = t.input.charCodeAt(t.position); x(a);) a = t.input.charCodeAt(++t.position);
+                            if (58 === a) S(a = t.input.charCodeAt(++t.position)) || C(t, "a whitespace character is expected after the key-value separator within a block mapping"), g && (D(t, l, h, d, v, null), d = v = y = null), m = !0, g = !1, i = !1, d = t.tag, v = t.result;
+                            else {
+                                if (!m) return t.tag = s, t.anchor = c, !0;
+                                C(t, "can not read an implicit mapping pair; a colon is missed")
+                            }
+                        } else {
+                            if (!m) return t.tag = s, t.anchor = c, !0;
+                            C(t, "can not read a block mapping entry; a multiline key may not be an implicit key")
+                        }
+                    } else 63 === a ? (g && (D(t, l, h, d, v, null), d = v = y = null), m = !0, g = !0, i = !0) : g ? (g = !1, i = !0) : C(t, "incomplete explicit mapping pair; a key node is missed; or followed by a non-tabulated empty line"), t.position += 1, a = r;
+                    if ((t.line === o || t.lineIndent > e) && (V(t, e, p, !0, i) && (g ? v = t.result : y = t.result), g || (D(t, l, h, d, v, y, o, u), d = v = y = null), F(t, !0, -1), a = t.input.charCodeAt(t.position)), t.lineIndent > e && 0 !== a) C(t, "bad indentation of a mapping entry");
+                    else if (t.lineIndent < e) break
+                }
+                return g && D(t, l, h, d, v, null), m && (t.tag = s, t.anchor = c, t.kind = "mapping", t.result = l), m
+            }(t, O, b)) || function(t, e) {
+                var n, r, i, o, u, a, s, f, l, p, h = !0,
+                    d = t.tag,
+                    v = t.anchor,
+                    y = {};
+                if (91 === (p = t.input.charCodeAt(t.position))) i = 93, a = !1, r = [];
+                else {
+                    if (123 !== p) return !1;
+                    i = 125, a = !0, r = {}
+                }
+                for (null !== t.anchor && (t.anchorMap[t.anchor] = r), p = t.input.charCodeAt(++t.position); 0 !== p;) {
+                    if (F(t, !0, e), (p = t.input.charCodeAt(t.position)) === i) return t.position++, t.tag = d, t.anchor = v, t.kind = a ? "mapping" : "sequence", t.result = r, !0;
+                    h || C(t, "missed comma between flow collection entries"), f = s = l = null, o = u = !1, 63 === p && S(t.input.charCodeAt(t.position + 1)) && (o = u = !0, t.position++, F(t, !0, e)), n = t.line, V(t, e, c, !1, !0), f = t.tag, s = t.result, F(t, !0, e), p = t.input.charCodeAt(t.position), !u && t.line !== n || 58 !== p || (o = !0, p = t.input.charCodeAt(++t.position), F(t, !0, e), V(t, e, c, !1, !0), l = t.result), a ? D(t, r, y, f, s, l) : o ? r.push(D(t, null, y, f, s, l)) : r.push(s), F(t, !0, e), 44 === (p = t.input.charCodeAt(t.position)) ? (h = !0, p = t.input.charCodeAt(++t.position)) : h = !1
+                }
+                C(t, "unexpected end of the stream within a flow collection")
+            }(t, b) ? P = !0 : (a && function(t, e) {
+                var n, i, o, u, a, s = h,
+                    c = !1,
+                    f = !1,
+                    l = e,
+                    p = 0,
+                    y = !1;
+                if (124 === (u = t.input.charCodeAt(t.position))) i = !1;
+                else {
+                    if (62 !== u) return !1;
+                    i = !0
+                }
+                for (t.kind = "scalar", t.result = ""; 0 !== u;)
+                    if (43 === (u = t.input.charCodeAt(++t.position)) || 45 === u) h === s ? s = 43 === u ? v : d : C(t, "repeat of a chomping mode identifier");
+                    else {
+                        if (!((o = 48 <= (a = u) && a <= 57 ? a - 48 : -1) >= 0)) break;
+                        0 === o ? C(t, "bad explicit indentation width of a block scalar; it cannot be less than one") : f ? C(t, "repeat of an indentation width identifier") : (l = e + o - 1, f = !0)
+                    }
+                if (x(u)) {
+                    do {
+                        u = t.input.charCodeAt(++t.position)
+                    } while (x(u));
+                    if (35 === u)
+                        do {
+                            u = t.input.charCodeAt(++t.position)
+                        } while (!w(u) && 0 !== u)
+                }
+                for (; 0 !== u;) {
+                    for (z(t), t.lineIndent = 0, u = t.input.charCodeAt(t.position);
+                         (!f || t.lineIndent < l) && 32 === u;) t.lineIndent++, u = t.input.charCodeAt(++t.position);
+                    if (!f && t.lineIndent > l && (l = t.lineIndent), w(u)) p++;
+                    else {
+                        if (t.lineIndent < l) {
+                            s === v ? t.result += r.repeat("\n", c ? 1 + p : p) : s === h && c && (t.result += "\n");
+                            break
+                        }
+                        for (i ? x(u) ? (y = !0, t.result += r.repeat("\n", c ? 1 + p : p)) : y ? (y = !1, t.result += r.repeat("\n", p + 1)) : 0 === p ? c && (t.result += " ") : t.result += r.repeat("\n", p) : t.result += r.repeat("\n", c ? 1 + p : p), c = !0, f = !0, p = 0, n = t.position; !w(u) && 0 !== u;) u = t.input.charCodeAt(++t.position);
+                        N(t, n, t.position, !1)
+                    }
+                }
+                return !0
+            }(t, b) || function(t, e) {
+                var n, r, i;
+                if (39 !== (n = t.input.charCodeAt(t.position))) return !1;
+                for (t.kind = "scalar", t.result = "", t.position++, r = i = t.position; 0 !== (n = t.input.charCodeAt(t.position));)
+                    if (39 === n) {
+                        if (N(t, r, t.position, !0), 39 !== (n = t.input.charCodeAt(++t.position))) return !0;
+                        r = t.position, t.position++, i = t.position
+                    } else w(n) ? (N(t, r, i, !0), B(t, F(t, !1, e)), r = i = t.position) : t.position === t.lineStart && U(t) ? C(t, "unexpected end of the document within a single quoted scalar") : (t.position++, i = t.position);
+                C(t, "unexpected end of the stream within a single quoted scalar")
+            }(t, b) || function(t, e) {
+                var n, r, i, o, u, a, s;
+                if (34 !== (a = t.input.charCodeAt(t.position))) return !1;
+                for (t.kind = "scalar", t.result = "", t.position++, n = r = t.position; 0 !== (a = t.input.charCodeAt(t.position));) {
+                    if (34 === a) return N(t, n, t.position, !0), t.position++, !0;
+                    if (92 === a) {
+                        if (N(t, n, t.position, !0), w(a = t.input.charCodeAt(++t.position))) F(t, !1, e);
+                        else if (a < 256 && k[a]) t.result += I[a], t.position++;
+                        else if ((u = 120 === (s = a) ? 2 : 117 === s ? 4 : 85 === s ? 8 : 0) > 0) {
+                            for (i = u, o = 0; i > 0; i--)(u = A(a = t.input.charCodeAt(++t.position))) >= 0 ? o = (o << 4) + u : C(t, "expected hexadecimal character");
+                            t.result += j(o), t.position++
+                        } else C(t, "unknown escape sequence");
+                        n = r = t.position
+                    } else w(a) ? (N(t, n, r, !0), B(t, F(t, !1, e)), n = r = t.position) : t.position === t.lineStart && U(t) ? C(t, "unexpected end of the document within a double quoted scalar") : (t.position++, r = t.position)
+                }
+                C(t, "unexpected end of the stream within a double quoted scalar")
+            }(t, b) ? P = !0 : ! function(t) {
+                var e, n, r;
+                if (42 !== (r = t.input.charCodeAt(t.position))) return !1;
+                for (r = t.input.charCodeAt(++t.position), e = t.position; 0 !== r && !S(r) && !E(r);) r = t.input.charCodeAt(++t.position);
+                return t.position === e && C(t, "name of an alias node must contain at least one character"), n = t.input.slice(e, t.position), t.anchorMap.hasOwnProperty(n) || C(t, 'unidentified alias "' + n + '"'), t.result = t.anchorMap[n], F(t, !0, -1), !0
+            }(t) ? function(t, e, n) {
+                var r, i, o, u, a, s, c, f, l = t.kind,
+                    p = t.result;
+                if (S(f = t.input.charCodeAt(t.position)) || E(f) || 35 === f || 38 === f || 42 === f || 33 === f || 124 === f || 62 === f || 39 === f || 34 === f || 37 === f || 64 === f || 96 === f) return !1;
+                if ((63 === f || 45 === f) && (S(r = t.input.charCodeAt(t.position + 1)) || n && E(r))) return !1;
+                for (t.kind = "scalar", t.result = "", i = o = t.position, u = !1; 0 !== f;) {
+                    if (58 === f) {
+                        if (S(r = t.input.charCodeAt(t.position + 1)) || n && E(r)) break
+                    } else if (35 === f) {
+                        if (S(t.input.charCodeAt(t.position - 1))) break
+                    } else {
+                        if (t.position === t.lineStart && U(t) || n && E(f)) break;
+                        if (w(f)) {
+                            if (a = t.line, s = t.lineStart, c = t.lineIndent, F(t, !1, -1), t.lineIndent >= e) {
+                                u = !0, f = t.input.charCodeAt(t.position);
+                                continue
+                            }
+                            t.position = o, t.line = a, t.lineStart = s, t.lineIndent = c;
+                            break
+                        }
+                    }
+                    u && (N(t, i, o, !1), B(t, t.line - a), i = o = t.position, u = !1), x(f) || (o = t.position + 1), f = t.input.charCodeAt(++t.position)
+                }
+                return N(t, i, o, !1), !!t.result || (t.kind = l, t.result = p, !1)
+            }(t, b, c === n) && (P = !0, null === t.tag && (t.tag = "?")) : (P = !0, null === t.tag && null === t.anchor || C(t, "alias node should not have any properties")), null !== t.anchor && (t.anchorMap[t.anchor] = t.result)) : 0 === M && (P = y && q(t, O))), null !== t.tag && "!" !== t.tag)
+                if ("?" === t.tag) {
+                    for (g = 0, m = t.implicitTypes.length; g < m; g += 1)
+                        if ((_ = t.implicitTypes[g]).resolve(t.result)) {
+                            t.result = _.construct(t.result), t.tag = _.tag, null !== t.anchor && (t.anchorMap[t.anchor] = t.result);
+                            break
+                        }
+                } else s.call(t.typeMap[t.kind || "fallback"], t.tag) ? (_ = t.typeMap[t.kind || "fallback"][t.tag], null !== t.result && _.kind !== t.kind && C(t, "unacceptable node kind for !<" + t.tag + '> tag; it should be "' + _.kind + '", not "' + t.kind + '"'), _.resolve(t.result) ? (t.result = _.construct(t.result), null !== t.anchor && (t.anchorMap[t.anchor] = t.result)) : C(t, "cannot resolve a node with !<" + t.tag + "> explicit tag")) : C(t, "unknown tag !<" + t.tag + ">");
+            return null !== t.listener && t.listener("close", t), null !== t.tag || null !== t.anchor || P
+        }
+
+        function K(t) {
+            var e, n, r, i, o = t.position,
+                u = !1;
+            for (t.version = null, t.checkLineBreaks = t.legacy, t.tagMap = {}, t.anchorMap = {}; 0 !== (i = t.input.charCodeAt(t.position)) && (F(t, !0, -1), i = t.input.charCodeAt(t.position), !(t.lineIndent > 0 || 37 !== i));) {
+                for (u = !0, i = t.input.charCodeAt(++t.position), e = t.position; 0 !== i && !S(i);) i = t.input.charCodeAt(++t.position);
+                for (r = [], (n = t.input.slice(e, t.position)).length < 1 && C(t, "directive name must not be less than one character in length"); 0 !== i;) {
+                    for (; x(i);) i = t.input.charCodeAt(++t.position);
+                    if (35 === i) {
+                        do {
+                            i = t.input.charCodeAt(++t.position)
+                        } while (0 !== i && !w(i));
+                        break
+                    }
+                    if (w(i)) break;
+                    for (e = t.position; 0 !== i && !S(i);) i = t.input.charCodeAt(++t.position);
+                    r.push(t.input.slice(e, t.position))
+                }
+                0 !== i && z(t), s.call(R, n) ? R[n](t, n, r) : P(t, 'unknown document directive "' + n + '"')
+            }
+            F(t, !0, -1), 0 === t.lineIndent && 45 === t.input.charCodeAt(t.position) && 45 === t.input.charCodeAt(t.position + 1) && 45 === t.input.charCodeAt(t.position + 2) ? (t.position += 3, F(t, !0, -1)) : u && C(t, "directives end mark is expected"), V(t, t.lineIndent - 1, p, !1, !0), F(t, !0, -1), t.checkLineBreaks && g.test(t.input.slice(o, t.position)) && P(t, "non-ASCII line breaks are interpreted as content"), t.documents.push(t.result), t.position === t.lineStart && U(t) ? 46 === t.input.charCodeAt(t.position) && (t.position += 3, F(t, !0, -1)) : t.position < t.length - 1 && C(t, "end of the stream or a document separator is expected")
+        }
+
+        function G(t, e) {
+            t = String(t), e = e || {}, 0 !== t.length && (10 !== t.charCodeAt(t.length - 1) && 13 !== t.charCodeAt(t.length - 1) && (t += "\n"), 65279 === t.charCodeAt(0) && (t = t.slice(1)));
+            var n = new function(t, e) {
+                this.input = t, this.filename = e.filename || null, this.schema = e.schema || a, this.onWarning = e.onWarning || null, this.legacy = e.legacy || !1, this.json = e.json || !1, this.listener = e.listener || null, this.implicitTypes = this.schema.compiledImplicit, this.typeMap = this.schema.compiledTypeMap, this.length = t.length, this.position = 0, this.line = 0, this.lineStart = 0, this.lineIndent = 0, this.documents = []
+            }(t, e);
+            for (n.input += "\0"; 32 === n.input.charCodeAt(n.position);) n.lineIndent += 1, n.position += 1;
+            for (; n.position < n.length - 1;) K(n);
+            return n.documents
+        }
+
+        function $(t, e, n) {
+            var r, i, o = G(t, n);
+            if ("function" != typeof e) return o;
+            for (r = 0, i = o.length; r < i; r += 1) e(o[r])
+        }
+
+        function H(t, e) {
+            var n = G(t, e);
+            if (0 !== n.length) {
+                if (1 === n.length) return n[0];
+                throw new i("expected a single document in the stream, but found more")
+            }
+        }
+        t.exports.loadAll = $, t.exports.load = H, t.exports.safeLoadAll = function(t, e, n) {
+            if ("function" != typeof e) return $(t, r.extend({
+                schema: u
+            }, n));
+            $(t, e, r.extend({
+                schema: u
+            }, n))
+        }, t.exports.safeLoad = function(t, e) {
+            return H(t, r.extend({
+                schema: u
+            }, e))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(37);
+
+        function i(t, e, n, r, i) {
+            this.name = t, this.buffer = e, this.position = n, this.line = r, this.column = i
+        }
+        i.prototype.getSnippet = function(t, e) {
+            var n, i, o, u, a;
+            if (!this.buffer) return null;
+            for (t = t || 4, e = e || 75, n = "", i = this.position; i > 0 && -1 === "\0\r\n\u2028\u2029".indexOf(this.buffer.charAt(i - 1));)
+                if (i -= 1, this.position - i > e / 2 - 1) {
+                    n = " ... ", i += 5;
+                    break
+                }
+            for (o = "", u = this.position; u < this.buffer.length && -1 === "\0\r\n\u2028\u2029".indexOf(this.buffer.charAt(u));)
+                if ((u += 1) - this.position > e / 2 - 1) {
+                    o = " ... ", u -= 5;
+                    break
+                }
+            return a = this.buffer.slice(i, u), r.repeat(" ", t) + n + a + o + "\n" + r.repeat(" ", t + this.position - i + n.length) + "^"
+        }, i.prototype.toString = function(t) {
+            var e, n = "";
+            return this.name && (n += 'in "' + this.name + '" '), n += "at line " + (this.line + 1) + ", column " + (this.column + 1), t || (e = this.getSnippet()) && (n += ":\n" + e), n
+        }, t.exports = i
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:str", {
+            kind: "scalar",
+            construct: function(t) {
+                return null !== t ? t : ""
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:seq", {
+            kind: "sequence",
+            construct: function(t) {
+                return null !== t ? t : []
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:map", {
+            kind: "mapping",
+            construct: function(t) {
+                return null !== t ? t : {}
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:null", {
+            kind: "scalar",
+            resolve: function(t) {
+                if (null === t) return !0;
+                var e = t.length;
+                return 1 === e && "~" === t || 4 === e && ("null" === t || "Null" === t || "NULL" === t)
+            },
+            construct: function() {
+                return null
+            },
+            predicate: function(t) {
+                return null === t
+            },
+            represent: {
+                canonical: function() {
+                    return "~"
+                },
+                lowercase: function() {
+                    return "null"
+                },
+                uppercase: function() {
+                    return "NULL"
+                },
+                camelcase: function() {
+                    return "Null"
+                }
+            },
+            defaultStyle: "lowercase"
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:bool", {
+            kind: "scalar",
+            resolve: function(t) {
+                if (null === t) return !1;
+                var e = t.length;
+                return 4 === e && ("true" === t || "True" === t || "TRUE" === t) || 5 === e && ("false" === t || "False" === t || "FALSE" === t)
+            },
+            construct: function(t) {
+                return "true" === t || "True" === t || "TRUE" === t
+            },
+            predicate: function(t) {
+                return "[object Boolean]" === Object.prototype.toString.call(t)
+            },
+            represent: {
+                lowercase: function(t) {
+                    return t ? "true" : "false"
+                },
+                uppercase: function(t) {
+                    return t ? "TRUE" : "FALSE"
+                },
+                camelcase: function(t) {
+                    return t ? "True" : "False"
+                }
+            },
+            defaultStyle: "lowercase"
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(37),
+            i = n(0);
+
+        function o(t) {
+            return 48 <= t && t <= 55
+        }
+
+        function u(t) {
+            return 48 <= t && t <= 57
+        }
+        t.exports = new i("tag:yaml.org,2002:int", {
+            kind: "scalar",
+            resolve: function(t) {
+                if (null === t) return !1;
+                var e, n, r = t.length,
+                    i = 0,
+                    a = !1;
+                if (!r) return !1;
+                if ("-" !== (e = t[i]) && "+" !== e || (e = t[++i]), "0" === e) {
+                    if (i + 1 === r) return !0;
+                    if ("b" === (e = t[++i])) {
+                        for (i++; i < r; i++)
+                            if ("_" !== (e = t[i])) {
+                                if ("0" !== e && "1" !== e) return !1;
+                                a = !0
+                            }
+                        return a && "_" !== e
+                    }
+                    if ("x" === e) {
+                        for (i++; i < r; i++)
+                            if ("_" !== (e = t[i])) {
+                                if (!(48 <= (n = t.charCodeAt(i)) && n <= 57 || 65 <= n && n <= 70 || 97 <= n && n <= 102)) return !1;
+                                a = !0
+                            }
+                        return a && "_" !== e
+                    }
+                    for (; i < r; i++)
+                        if ("_" !== (e = t[i])) {
+                            if (!o(t.charCodeAt(i))) return !1;
+                            a = !0
+                        }
+                    return a && "_" !== e
+                }
+                if ("_" === e) return !1;
+                for (; i < r; i++)
+                    if ("_" !== (e = t[i])) {
+                        if (":" === e) break;
+                        if (!u(t.charCodeAt(i))) return !1;
+                        a = !0
+                    }
+                return !(!a || "_" === e) && (":" !== e || /^(:[0-5]?[0-9])+$/.test(t.slice(i)))
+            },
+            construct: function(t) {
+                var e, n, r = t,
+                    i = 1,
+                    o = [];
+                return -1 !== r.indexOf("_") && (r = r.replace(/_/g, "")), "-" !== (e = r[0]) && "+" !== e || ("-" === e && (i = -1), e = (r = r.slice(1))[0]), "0" === r ? 0 : "0" === e ? "b" === r[1] ? i * parseInt(r.slice(2), 2) : "x" === r[1] ? i * parseInt(r, 16) : i * parseInt(r, 8) : -1 !== r.indexOf(":") ? (r.split(":").forEach(function(t) {
+                    o.unshift(parseInt(t, 10))
+                }), r = 0, n = 1, o.forEach(function(t) {
+                    r += t * n, n *= 60
+                }), i * r) : i * parseInt(r, 10)
+            },
+            predicate: function(t) {
+                return "[object Number]" === Object.prototype.toString.call(t) && t % 1 == 0 && !r.isNegativeZero(t)
+            },
+            represent: {
+                binary: function(t) {
+                    return t >= 0 ? "0b" + t.toString(2) : "-0b" + t.toString(2).slice(1)
+                },
+                octal: function(t) {
+                    return t >= 0 ? "0" + t.toString(8) : "-0" + t.toString(8).slice(1)
+                },
+                decimal: function(t) {
+                    return t.toString(10)
+                },
+                hexadecimal: function(t) {
+                    return t >= 0 ? "0x" + t.toString(16).toUpperCase() : "-0x" + t.toString(16).toUpperCase().slice(1)
+                }
+            },
+            defaultStyle: "decimal",
+            styleAliases: {
+                binary: [2, "bin"],
+                octal: [8, "oct"],
+                decimal: [10, "dec"],
+                hexadecimal: [16, "hex"]
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(37),
+            i = n(0),
+            o = new RegExp("^(?:[-+]?(?:0|[1-9][0-9_]*)(?:\\.[0-9_]*)?(?:[eE][-+]?[0-9]+)?|\\.[0-9_]+(?:[eE][-+]?[0-9]+)?|[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+\\.[0-9_]*|[-+]?\\.(?:inf|Inf|INF)|\\.(?:nan|NaN|NAN))$");
+        var u = /^[-+]?[0-9]+e/;
+        t.exports = new i("tag:yaml.org,2002:float", {
+            kind: "scalar",
+            resolve: function(t) {
+                return null !== t && !(!o.test(t) || "_" === t[t.length - 1])
+            },
+            construct: function(t) {
+                var e, n, r, i;
+                return n = "-" === (e = t.replace(/_/g, "").toLowerCase())[0] ? -1 : 1, i = [], "+-".indexOf(e[0]) >= 0 && (e = e.slice(1)), ".inf" === e ? 1 === n ? Number.POSITIVE_INFINITY : Number.NEGATIVE_INFINITY : ".nan" === e ? NaN : e.indexOf(":") >= 0 ? (e.split(":").forEach(function(t) {
+                    i.unshift(parseFloat(t, 10))
+                }), e = 0, r = 1, i.forEach(function(t) {
+                    e += t * r, r *= 60
+                }), n * e) : n * parseFloat(e, 10)
+            },
+            predicate: function(t) {
+                return "[object Number]" === Object.prototype.toString.call(t) && (t % 1 != 0 || r.isNegativeZero(t))
+            },
+            represent: function(t, e) {
+                var n;
+                if (isNaN(t)) switch (e) {
+                    case "lowercase":
+                        return ".nan";
+                    case "uppercase":
+                        return ".NAN";
+                    case "camelcase":
+                        return ".NaN"
+                } else if (Number.POSITIVE_INFINITY === t) switch (e) {
+                    case "lowercase":
+                        return ".inf";
+                    case "uppercase":
+                        return ".INF";
+                    case "camelcase":
+                        return ".Inf"
+                } else if (Number.NEGATIVE_INFINITY === t) switch (e) {
+                    case "lowercase":
+                        return "-.inf";
+                    case "uppercase":
+                        return "-.INF";
+                    case "camelcase":
+                        return "-.Inf"
+                } else if (r.isNegativeZero(t)) return "-0.0";
+                return n = t.toString(10), u.test(n) ? n.replace("e", ".e") : n
+            },
+            defaultStyle: "lowercase"
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0),
+            i = new RegExp("^([0-9][0-9][0-9][0-9])-([0-9][0-9])-([0-9][0-9])$"),
+            o = new RegExp("^([0-9][0-9][0-9][0-9])-([0-9][0-9]?)-([0-9][0-9]?)(?:[Tt]|[ \\t]+)([0-9][0-9]?):([0-9][0-9]):([0-9][0-9])(?:\\.([0-9]*))?(?:[ \\t]*(Z|([-+])([0-9][0-9]?)(?::([0-9][0-9]))?))?$");
+        t.exports = new r("tag:yaml.org,2002:timestamp", {
+            kind: "scalar",
+            resolve: function(t) {
+                return null !== t && (null !== i.exec(t) || null !== o.exec(t))
+            },
+            construct: function(t) {
+                var e, n, r, u, a, s, c, f, l = 0,
+                    p = null;
+                if (null === (e = i.exec(t)) && (e = o.exec(t)), null === e) throw new Error("Date resolve error");
+                if (n = +e[1], r = +e[2] - 1, u = +e[3], !e[4]) return new Date(Date.UTC(n, r, u));
+                if (a = +e[4], s = +e[5], c = +e[6], e[7]) {
+                    for (l = e[7].slice(0, 3); l.length < 3;) l += "0";
+                    l = +l
+                }
+                return e[9] && (p = 6e4 * (60 * +e[10] + +(e[11] || 0)), "-" === e[9] && (p = -p)), f = new Date(Date.UTC(n, r, u, a, s, c, l)), p && f.setTime(f.getTime() - p), f
+            },
+            instanceOf: Date,
+            represent: function(t) {
+                return t.toISOString()
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:merge", {
+            kind: "scalar",
+            resolve: function(t) {
+                return "<<" === t || null === t
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r;
+        try {
+            r = n(68).Buffer
+        } catch (t) {}
+        var i = n(0),
+            o = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=\n\r";
+        t.exports = new i("tag:yaml.org,2002:binary", {
+            kind: "scalar",
+            resolve: function(t) {
+                if (null === t) return !1;
+                var e, n, r = 0,
+                    i = t.length,
+                    u = o;
+                for (n = 0; n < i; n++)
+                    if (!((e = u.indexOf(t.charAt(n))) > 64)) {
+                        if (e < 0) return !1;
+                        r += 6
+                    }
+                return r % 8 == 0
+            },
+            construct: function(t) {
+                var e, n, i = t.replace(/[\r\n=]/g, ""),
+                    u = i.length,
+                    a = o,
+                    s = 0,
+                    c = [];
+                for (e = 0; e < u; e++) e % 4 == 0 && e && (c.push(s >> 16 & 255), c.push(s >> 8 & 255), c.push(255 & s)), s = s << 6 | a.indexOf(i.charAt(e));
+                return 0 == (n = u % 4 * 6) ? (c.push(s >> 16 & 255), c.push(s >> 8 & 255), c.push(255 & s)) : 18 === n ? (c.push(s >> 10 & 255), c.push(s >> 2 & 255)) : 12 === n && c.push(s >> 4 & 255), r ? r.from ? r.from(c) : new r(c) : c
+            },
+            predicate: function(t) {
+                return r && r.isBuffer(t)
+            },
+            represent: function(t) {
+                var e, n, r = "",
+                    i = 0,
+                    u = t.length,
+                    a = o;
+                for (e = 0; e < u; e++) e % 3 == 0 && e && (r += a[i >> 18 & 63], r += a[i >> 12 & 63], r += a[i >> 6 & 63], r += a[63 & i]), i = (i << 8) + t[e];
+                return 0 == (n = u % 3) ? (r += a[i >> 18 & 63], r += a[i >> 12 & 63], r += a[i >> 6 & 63], r += a[63 & i]) : 2 === n ? (r += a[i >> 10 & 63], r += a[i >> 4 & 63], r += a[i << 2 & 63], r += a[64]) : 1 === n && (r += a[i >> 2 & 63], r += a[i << 4 & 63], r += a[64], r += a[64]), r
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0),
+            i = Object.prototype.hasOwnProperty,
+            o = Object.prototype.toString;
+        t.exports = new r("tag:yaml.org,2002:omap", {
+            kind: "sequence",
+            resolve: function(t) {
+                if (null === t) return !0;
+                var e, n, r, u, a, s = [],
+                    c = t;
+                for (e = 0, n = c.length; e < n; e += 1) {
+                    if (r = c[e], a = !1, "[object Object]" !== o.call(r)) return !1;
+                    for (u in r)
+                        if (i.call(r, u)) {
+                            if (a) return !1;
+                            a = !0
+                        }
+                    if (!a) return !1;
+                    if (-1 !== s.indexOf(u)) return !1;
+                    s.push(u)
+                }
+                return !0
+            },
+            construct: function(t) {
+                return null !== t ? t : []
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0),
+            i = Object.prototype.toString;
+        t.exports = new r("tag:yaml.org,2002:pairs", {
+            kind: "sequence",
+            resolve: function(t) {
+                if (null === t) return !0;
+                var e, n, r, o, u, a = t;
+                for (u = new Array(a.length), e = 0, n = a.length; e < n; e += 1) {
+                    if (r = a[e], "[object Object]" !== i.call(r)) return !1;
+                    if (1 !== (o = Object.keys(r)).length) return !1;
+                    u[e] = [o[0], r[o[0]]]
+                }
+                return !0
+            },
+            construct: function(t) {
+                if (null === t) return [];
+                var e, n, r, i, o, u = t;
+                for (o = new Array(u.length), e = 0, n = u.length; e < n; e += 1) r = u[e], i = Object.keys(r), o[e] = [i[0], r[i[0]]];
+                return o
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0),
+            i = Object.prototype.hasOwnProperty;
+        t.exports = new r("tag:yaml.org,2002:set", {
+            kind: "mapping",
+            resolve: function(t) {
+                if (null === t) return !0;
+                var e, n = t;
+                for (e in n)
+                    if (i.call(n, e) && null !== n[e]) return !1;
+                return !0
+            },
+            construct: function(t) {
+                return null !== t ? t : {}
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:js/undefined", {
+            kind: "scalar",
+            resolve: function() {
+                return !0
+            },
+            construct: function() {},
+            predicate: function(t) {
+                return void 0 === t
+            },
+            represent: function() {
+                return ""
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(0);
+        t.exports = new r("tag:yaml.org,2002:js/regexp", {
+            kind: "scalar",
+            resolve: function(t) {
+                if (null === t) return !1;
+                if (0 === t.length) return !1;
+                var e = t,
+                    n = /\/([gim]*)$/.exec(t),
+                    r = "";
+                if ("/" === e[0]) {
+                    if (n && (r = n[1]), r.length > 3) return !1;
+                    if ("/" !== e[e.length - r.length - 1]) return !1
+                }
+                return !0
+            },
+            construct: function(t) {
+                var e = t,
+                    n = /\/([gim]*)$/.exec(t),
+                    r = "";
+                return "/" === e[0] && (n && (r = n[1]), e = e.slice(1, e.length - r.length - 1)), new RegExp(e, r)
+            },
+            predicate: function(t) {
+                return "[object RegExp]" === Object.prototype.toString.call(t)
+            },
+            represent: function(t) {
+                var e = "/" + t.source + "/";
+                return t.global && (e += "g"), t.multiline && (e += "m"), t.ignoreCase && (e += "i"), e
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r;
+        "undefined" != typeof window && (r = window.esprima);
+        var i = n(0);
+        t.exports = new i("tag:yaml.org,2002:js/function", {
+            kind: "scalar",
+            resolve: function(t) {
+                if (null === t) return !1;
+                try {
+                    var e = "(" + t + ")",
+                        n = r.parse(e, {
+                            range: !0
+                        });
+                    return "Program" === n.type && 1 === n.body.length && "ExpressionStatement" === n.body[0].type && ("ArrowFunctionExpression" === n.body[0].expression.type || "FunctionExpression" === n.body[0].expression.type)
+                } catch (t) {
+                    return !1
+                }
+            },
+            construct: function(t) {
+                var e, n = "(" + t + ")",
+                    i = r.parse(n, {
+                        range: !0
+                    }),
+                    o = [];
+                if ("Program" !== i.type || 1 !== i.body.length || "ExpressionStatement" !== i.body[0].type || "ArrowFunctionExpression" !== i.body[0].expression.type && "FunctionExpression" !== i.body[0].expression.type) throw new Error("Failed to resolve function");
+                return i.body[0].expression.params.forEach(function(t) {
+                    o.push(t.name)
+                }), e = i.body[0].expression.body.range, "BlockStatement" === i.body[0].expression.body.type ? new Function(o, n.slice(e[0] + 1, e[1] - 1)) : new Function(o, "return " + n.slice(e[0], e[1]))
+            },
+            predicate: function(t) {
+                return "[object Function]" === Object.prototype.toString.call(t)
+            },
+            represent: function(t) {
+                return t.toString()
+            }
+        })
+    }, function(t, e, n) {
+        "use strict";
+        var r = n(37),
+            i = n(52),
+            o = n(83),
+            u = n(53),
+            a = Object.prototype.toString,
+            s = Object.prototype.hasOwnProperty,
+            c = 9,
+            f = 10,
+            l = 32,
+            p = 33,
+            h = 34,
+            d = 35,
+            v = 37,
+            y = 38,
+            g = 39,
+            m = 42,
+            _ = 44,
+            b = 45,
+            w = 58,
+            x = 62,
+            S = 63,
+            E = 64,
+            A = 91,
+            O = 93,
+            j = 96,
+            k = 123,
+            I = 124,
+            M = 125,
+            T = {
+                0: "\\0",
+                7: "\\a",
+                8: "\\b",
+                9: "\\t",
+                10: "\\n",
+                11: "\\v",
+                12: "\\f",
+                13: "\\r",
+                27: "\\e",
+                34: '\\"',
+                92: "\\\\",
+                133: "\\N",
+                160: "\\_",
+                8232: "\\L",
+                8233: "\\P"
+            },
+            C = ["y", "Y", "yes", "Yes", "YES", "on", "On", "ON", "n", "N", "no", "No", "NO", "off", "Off", "OFF"];
+
+        function P(t) {
+            var e, n, o;
+            if (e = t.toString(16).toUpperCase(), t <= 255) n = "x", o = 2;
+            else if (t <= 65535) n = "u", o = 4;
+            else {
+                if (!(t <= 4294967295)) throw new i("code point within a string may not be greater than 0xFFFFFFFF");
+                n = "U", o = 8
+            }
+            return "\\" + n + r.repeat("0", o - e.length) + e
+        }
+
+        function R(t) {
+            this.schema = t.schema || o, this.indent = Math.max(1, t.indent || 2), this.skipInvalid = t.skipInvalid || !1, this.flowLevel = r.isNothing(t.flowLevel) ? -1 : t.flowLevel, this.styleMap = function(t, e) {
+                var n, r, i, o, u, a, c;
+                if (null === e) return {};
+                for (n = {}, i = 0, o = (r = Object.keys(e)).length; i < o; i += 1) u = r[i], a = String(e[u]), "!!" === u.slice(0, 2) && (u = "tag:yaml.org,2002:" + u.slice(2)), (c = t.compiledTypeMap.fallback[u]) && s.call(c.styleAliases, a) && (a = c.styleAliases[a]), n[u] = a;
+                return n
+            }(this.schema, t.styles || null), this.sortKeys = t.sortKeys || !1, this.lineWidth = t.lineWidth || 80, this.noRefs = t.noRefs || !1, this.noCompatMode = t.noCompatMode || !1, this.condenseFlow = t.condenseFlow || !1, this.implicitTypes = this.schema.compiledImplicit, this.explicitTypes = this.schema.compiledExplicit, this.tag = null, this.result = "", this.duplicates = [], this.usedDuplicates = null
+        }
+
+        function N(t, e) {
+            for (var n, i = r.repeat(" ", e), o = 0, u = -1, a = "", s = t.length; o < s;) - 1 === (u = t.indexOf("\n", o)) ? (n = t.slice(o), o = s) : (n = t.slice(o, u + 1), o = u + 1), n.length && "\n" !== n && (a += i), a += n;
+            return a
+        }
+
+        function L(t, e) {
+            return "\n" + r.repeat(" ", t.indent * e)
+        }
+
+        function D(t) {
+            return t === l || t === c
+        }
+
+        function z(t) {
+            return 32 <= t && t <= 126 || 161 <= t && t <= 55295 && 8232 !== t && 8233 !== t || 57344 <= t && t <= 65533 && 65279 !== t || 65536 <= t && t <= 1114111
+        }
+
+        function F(t) {
+            return z(t) && 65279 !== t && t !== _ && t !== A && t !== O && t !== k && t !== M && t !== w && t !== d
+        }
+
+        function U(t) {
+            return /^\n* /.test(t)
+        }
+        var B = 1,
+            q = 2,
+            Y = 3,
+            W = 4,
+            V = 5;
+
+        function K(t, e, n, r, i) {
+            var o, u, a, s = !1,
+                c = !1,
+                l = -1 !== r,
+                T = -1,
+                C = z(a = t.charCodeAt(0)) && 65279 !== a && !D(a) && a !== b && a !== S && a !== w && a !== _ && a !== A && a !== O && a !== k && a !== M && a !== d && a !== y && a !== m && a !== p && a !== I && a !== x && a !== g && a !== h && a !== v && a !== E && a !== j && !D(t.charCodeAt(t.length - 1));
+            if (e)
+                for (o = 0; o < t.length; o++) {
+                    if (!z(u = t.charCodeAt(o))) return V;
+                    C = C && F(u)
+                } else {
+                for (o = 0; o < t.length; o++) {
+                    if ((u = t.charCodeAt(o)) === f) s = !0, l && (c = c || o - T - 1 > r && " " !== t[T + 1], T = o);
+                    else if (!z(u)) return V;
+                    C = C && F(u)
+                }
+                c = c || l && o - T - 1 > r && " " !== t[T + 1]
+            }
+            return s || c ? n > 9 && U(t) ? V : c ? W : Y : C && !i(t) ? B : q
+        }
+
+        function G(t, e, n, r) {
+            t.dump = function() {
+                if (0 === e.length) return "''";
+                if (!t.noCompatMode && -1 !== C.indexOf(e)) return "'" + e + "'";
+                var o = t.indent * Math.max(1, n),
+                    u = -1 === t.lineWidth ? -1 : Math.max(Math.min(t.lineWidth, 40), t.lineWidth - o),
+                    a = r || t.flowLevel > -1 && n >= t.flowLevel;
+                switch (K(e, a, t.indent, u, function(e) {
+                    return function(t, e) {
+                        var n, r;
+                        for (n = 0, r = t.implicitTypes.length; n < r; n += 1)
+                            if (t.implicitTypes[n].resolve(e)) return !0;
+                        return !1
+                    }(t, e)
+                })) {
+                    case B:
+                        return e;
+                    case q:
+                        return "'" + e.replace(/'/g, "''") + "'";
+                    case Y:
+                        return "|" + $(e, t.indent) + H(N(e, o));
+                    case W:
+                        return ">" + $(e, t.indent) + H(N(function(t, e) {
+                            var n, r, i = /(\n+)([^\n]*)/g,
+                                o = (a = t.indexOf("\n"), a = -1 !== a ? a : t.length, i.lastIndex = a, J(t.slice(0, a), e)),
+                                u = "\n" === t[0] || " " === t[0];
+                            var a;
+                            for (; r = i.exec(t);) {
+                                var s = r[1],
+                                    c = r[2];
+                                n = " " === c[0], o += s + (u || n || "" === c ? "" : "\n") + J(c, e), u = n
+                            }
+                            return o
+                        }(e, u), o));
+                    case V:
+                        return '"' + function(t) {
+                            for (var e, n, r, i = "", o = 0; o < t.length; o++)(e = t.charCodeAt(o)) >= 55296 && e <= 56319 && (n = t.charCodeAt(o + 1)) >= 56320 && n <= 57343 ? (i += P(1024 * (e - 55296) + n - 56320 + 65536), o++) : (r = T[e], i += !r && z(e) ? t[o] : r || P(e));
+                            return i
+                        }(e) + '"';
+                    default:
+                        throw new i("impossible error: invalid scalar style")
+                }
+            }()
+        }
+
+        function $(t, e) {
+            var n = U(t) ? String(e) : "",
+                r = "\n" === t[t.length - 1];
+            return n + (r && ("\n" === t[t.length - 2] || "\n" === t) ? "+" : r ? "" : "-") + "\n"
+        }
+
+        function H(t) {
+            return "\n" === t[t.length - 1] ? t.slice(0, -1) : t
+        }
+
+        function J(t, e) {
+            if ("" === t || " " === t[0]) return t;
+            for (var n, r, i = / [^ ]/g, o = 0, u = 0, a = 0, s = ""; n = i.exec(t);)(a = n.index) - o > e && (r = u > o ? u : a, s += "\n" + t.slice(o, r), o = r + 1), u = a;
+            return s += "\n", t.length - o > e && u > o ? s += t.slice(o, u) + "\n" + t.slice(u + 1) : s += t.slice(o), s.slice(1)
+        }
+
+        function Z(t, e, n) {
+            var r, o, u, c, f, l;
+            for (u = 0, c = (o = n ? t.explicitTypes : t.implicitTypes).length; u < c; u += 1)
+                if (((f = o[u]).instanceOf || f.predicate) && (!f.instanceOf || "object" == typeof e && e instanceof f.instanceOf) && (!f.predicate || f.predicate(e))) {
+                    if (t.tag = n ? f.tag : "?", f.represent) {
+                        if (l = t.styleMap[f.tag] || f.defaultStyle, "[object Function]" === a.call(f.represent)) r = f.represent(e, l);
+                        else {
+                            if (!s.call(f.represent, l)) throw new i("!<" + f.tag + '> tag resolver accepts not "' + l + '" style');
+                            r = f.represent[l](e, l)
+                        }
+                        t.dump = r
+                    }
+                    return !0
+                }
+            return !1
+        }
+
+        function X(t, e, n, r, o, u) {
+            t.tag = null, t.dump = n, Z(t, n, !1) || Z(t, n, !0);
+            var s = a.call(t.dump);
+            r && (r = t.flowLevel < 0 || t.flowLevel > e);
+            var c, l, p = "[object Object]" === s || "[object Array]" === s;
+            if (p && (l = -1 !== (c = t.duplicates.indexOf(n))), (null !== t.tag && "?" !== t.tag || l || 2 !== t.indent && e > 0) && (o = !1), l && t.usedDuplicates[c]) t.dump = "*ref_" + c;
+            else {
+                if (p && l && !t.usedDuplicates[c] && (t.usedDuplicates[c] = !0), "[object Object]" === s) r && 0 !== Object.keys(t.dump).length ? (! function(t, e, n, r) {
+                    var o, u, a, s, c, l, p = "",
+                        h = t.tag,
+                        d = Object.keys(n);
+                    if (!0 === t.sortKeys) d.sort();
+                    else if ("function" == typeof t.sortKeys) d.sort(t.sortKeys);
+                    else if (t.sortKeys) throw new i("sortKeys must be a boolean or a function");
+                    for (o = 0, u = d.length; o < u; o += 1) l = "", r && 0 === o || (l += L(t, e)), s = n[a = d[o]], X(t, e + 1, a, !0, !0, !0) && ((c = null !== t.tag && "?" !== t.tag || t.dump && t.dump.length > 1024) && (t.dump && f === t.dump.charCodeAt(0) ? l += "?" : l += "? "), l += t.dump, c && (l += L(t, e)), X(t, e + 1, s, !0, c) && (t.dump && f === t.dump.charCodeAt(0) ? l += ":" : l += ": ", p += l += t.dump));
+                    t.tag = h, t.dump = p || "{}"
+                }(t, e, t.dump, o), l && (t.dump = "&ref_" + c + t.dump)) : (! function(t, e, n) {
+                    var r, i, o, u, a, s = "",
+                        c = t.tag,
+                        f = Object.keys(n);
+                    for (r = 0, i = f.length; r < i; r += 1) a = t.condenseFlow ? '"' : "", 0 !== r && (a += ", "), u = n[o = f[r]], X(t, e, o, !1, !1) && (t.dump.length > 1024 && (a += "? "), a += t.dump + (t.condenseFlow ? '"' : "") + ":" + (t.condenseFlow ? "" : " "), X(t, e, u, !1, !1) && (s += a += t.dump));
+                    t.tag = c, t.dump = "{" + s + "}"
+                }(t, e, t.dump), l && (t.dump = "&ref_" + c + " " + t.dump));
+                else if ("[object Array]" === s) r && 0 !== t.dump.length ? (! function(t, e, n, r) {
+                    var i, o, u = "",
+                        a = t.tag;
+                    for (i = 0, o = n.length; i < o; i += 1) X(t, e + 1, n[i], !0, !0) && (r && 0 === i || (u += L(t, e)), t.dump && f === t.dump.charCodeAt(0) ? u += "-" : u += "- ", u += t.dump);
+                    t.tag = a, t.dump = u || "[]"
+                }(t, e, t.dump, o), l && (t.dump = "&ref_" + c + t.dump)) : (! function(t, e, n) {
+                    var r, i, o = "",
+                        u = t.tag;
+                    for (r = 0, i = n.length; r < i; r += 1) X(t, e, n[r], !1, !1) && (0 !== r && (o += "," + (t.condenseFlow ? "" : " ")), o += t.dump);
+                    t.tag = u, t.dump = "[" + o + "]"
+                }(t, e, t.dump), l && (t.dump = "&ref_" + c + " " + t.dump));
+                else {
+                    if ("[object String]" !== s) {
+                        if (t.skipInvalid) return !1;
+                        throw new i("unacceptable kind of an object to dump " + s)
+                    }
+                    "?" !== t.tag && G(t, t.dump, e, u)
+                }
+                null !== t.tag && "?" !== t.tag && (t.dump = "!<" + t.tag + "> " + t.dump)
+            }
+            return !0
+        }
+
+        function Q(t, e) {
+            var n, r, i = [],
+                o = [];
+            for (function t(e, n, r) {
+                var i, o, u;
+                if (null !== e && "object" == typeof e)
+                    if (-1 !== (o = n.indexOf(e))) - 1 === r.indexOf(o) && r.push(o);
+                    else if (n.push(e), Array.isArray(e))
+                        for (o = 0, u = e.length; o < u; o += 1) t(e[o], n, r);
+                    else
+                        for (i = Object.keys(e), o = 0, u = i.length; o < u; o += 1) t(e[i[o]], n, r)
+            }(t, i, o), n = 0, r = o.length; n < r; n += 1) e.duplicates.push(i[o[n]]);
+            e.usedDuplicates = new Array(r)
+        }
+
+        function tt(t, e) {
+            var n = new R(e = e || {});
+            return n.noRefs || Q(t, n), X(n, 0, t, !0, !0) ? n.dump + "\n" : ""
+        }
+        t.exports.dump = tt, t.exports.safeDump = function(t, e) {
+            return tt(t, r.extend({
+                schema: u
+            }, e))
+        }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        }), e.getConfigByUrl = e.downloadConfig = void 0;
+        var r = n(201);
+        e.downloadConfig = function(t) {
+            return function(e) {
+                return (0, e.fn.fetch)(t)
+            }
+        }, e.getConfigByUrl = function(t, e) {
+            return function(n) {
+                var i = n.specActions;
+                if (t) return i.downloadConfig(t).then(o, o);
+
+                function o(n) {
+                    n instanceof Error || n.status >= 400 ? (i.updateLoadingStatus("failedConfig"), i.updateLoadingStatus("failedConfig"), i.updateUrl(""), console.error(n.statusText + " " + t.url), e(null)) : e((0, r.parseYamlConfig)(n.text))
+                }
+            }
+        }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        });
+        e.get = function(t, e) {
+            return t.getIn(Array.isArray(e) ? e : [e])
+        }
+    }, function(t, e, n) {
+        "use strict";
+        Object.defineProperty(e, "__esModule", {
+            value: !0
+        });
+        var r, i, o = n(205),
+            u = (r = o) && r.__esModule ? r : {
+                default: r
+            },
+            a = n(168),
+            s = n(204);
+        e.default = (i = {}, (0, u.default)(i, s.UPDATE_CONFIGS, function(t, e) {
+            return t.merge((0, a.fromJS)(e.payload))
+        }), (0, u.default)(i, s.TOGGLE_CONFIGS, function(t, e) {
+            var n = e.payload,
+                r = t.get(n);
+            return t.set(n, !r)
+        }), i)
+    }])
+});
+//# sourceMappingURL=swagger-ui-standalone-preset.js.map
\ No newline at end of file
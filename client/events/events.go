@@ -0,0 +1,238 @@
+// Package events serves a WebSocket event gateway on the API server at /events/ws.
+//
+// Clients subscribe to typed streams of events instead of subscribing to raw ostracon
+// events and decoding the ABCI events themselves. A subscription is a JSON message:
+//
+//	{"type": "subscribe", "subscription": {"id": "1", "stream": "transfer",
+//	  "address": "link1...", "filter": "amount > 1000", "from_height": 100}}
+//
+// The streams are:
+//
+//	transfer       the transfers to the address
+//	wasm           the events emitted by the contract
+//	gov            the status changes of governance proposals
+//	validator_set  the changes of the validator set
+//
+// The filter is an ostracon query, e.g. "sender = 'link1...' AND amount > 1000", which
+// is matched against the attributes of the events of the stream (see Event).
+//
+// The events are read block by block from the block results of the node, starting at
+// from_height, or at the next block if it is not given. Every event is sent before the
+// checkpoint of its height, so a client that resumes from the height after its last
+// checkpoint receives every event at least once. Events are identified by their height
+// and index for de-duplication.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/pubsub/query"
+
+	sdk "github.com/line/lbm-sdk/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+)
+
+// The streams that can be subscribed to.
+const (
+	StreamTransfer     = "transfer"
+	StreamWasm         = "wasm"
+	StreamGov          = "gov"
+	StreamValidatorSet = "validator_set"
+)
+
+// The statuses of the gov stream.
+const (
+	GovStatusDepositPeriod = "deposit_period"
+	GovStatusVotingPeriod  = "voting_period"
+	GovStatusPassed        = "passed"
+	GovStatusRejected      = "rejected"
+	GovStatusFailed        = "failed"
+	GovStatusDropped       = "dropped"
+)
+
+// Subscription is a subscription to a stream.
+type Subscription struct {
+	// ID identifies the subscription in the messages of the connection.
+	ID     string `json:"id"`
+	Stream string `json:"stream"`
+	// Address is the recipient of the transfer stream.
+	Address string `json:"address,omitempty"`
+	// Contract is the contract of the wasm stream.
+	Contract string `json:"contract,omitempty"`
+	// Filter is an ostracon query on the attributes of the events.
+	Filter string `json:"filter,omitempty"`
+	// FromHeight is the height to start from; the next block if it is 0.
+	FromHeight int64 `json:"from_height,omitempty"`
+
+	query *query.Query
+}
+
+// Validate checks the subscription and parses its filter.
+func (s *Subscription) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("subscription id cannot be empty")
+	}
+	switch s.Stream {
+	case StreamTransfer:
+		if err := sdk.ValidateAccAddress(s.Address); err != nil {
+			return fmt.Errorf("invalid address of the transfer stream: %w", err)
+		}
+	case StreamWasm:
+		if err := sdk.ValidateAccAddress(s.Contract); err != nil {
+			return fmt.Errorf("invalid contract of the wasm stream: %w", err)
+		}
+	case StreamGov, StreamValidatorSet:
+	default:
+		return fmt.Errorf("unknown stream %q", s.Stream)
+	}
+	if s.FromHeight < 0 {
+		return fmt.Errorf("from_height cannot be negative")
+	}
+	if s.Filter != "" {
+		q, err := query.New(s.Filter)
+		if err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+		s.query = q
+	}
+	return nil
+}
+
+// Event is an event of a stream.
+type Event struct {
+	Stream string `json:"stream"`
+	Height int64  `json:"height"`
+	// Index is the position of the event in the events of the block, which
+	// identifies the event together with the height.
+	Index  int    `json:"index"`
+	TxHash string `json:"tx_hash,omitempty"`
+	// Attributes are the attributes the filter is matched against:
+	//
+	//	transfer       sender, recipient, amount
+	//	wasm           contract_address and the attributes of the contract
+	//	gov            proposal_id, status
+	//	validator_set  address, power
+	Attributes map[string]string `json:"attributes"`
+	// Payload is the decoded event, with the messages encoded as proto JSON.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// TransferPayload is the payload of the transfer stream.
+type TransferPayload struct {
+	Sender    string    `json:"sender,omitempty"`
+	Recipient string    `json:"recipient"`
+	Amount    sdk.Coins `json:"amount"`
+}
+
+// WasmPayload is the payload of the wasm stream.
+type WasmPayload struct {
+	Contract   string          `json:"contract"`
+	Attributes []sdk.Attribute `json:"attributes"`
+}
+
+// GovPayload is the payload of the gov stream.
+type GovPayload struct {
+	ProposalID uint64 `json:"proposal_id"`
+	Status     string `json:"status"`
+	// Proposal is the proposal at the height of the event, or before it if it was deleted.
+	Proposal json.RawMessage `json:"proposal,omitempty"`
+}
+
+// ValidatorPayload is the payload of the validator_set stream.
+type ValidatorPayload struct {
+	Address string          `json:"address"`
+	PubKey  json.RawMessage `json:"pub_key"`
+	// Power is 0 if the validator is removed from the set.
+	Power int64 `json:"power"`
+}
+
+// blockEvent is an ABCI event of a block with its position.
+type blockEvent struct {
+	abci.Event
+	index int
+	// tx is the index of the tx of the event, or -1 for begin and end block events.
+	tx int
+}
+
+// attributes returns the attributes of the event by key.
+func attributes(ev abci.Event) map[string]string {
+	attrs := make(map[string]string, len(ev.Attributes))
+	for _, attr := range ev.Attributes {
+		attrs[string(attr.Key)] = string(attr.Value)
+	}
+	return attrs
+}
+
+// transferAttributes returns the attributes of the transfer event if it is one to the address.
+func transferAttributes(ev abci.Event, address string) (map[string]string, bool) {
+	if ev.Type != banktypes.EventTypeTransfer {
+		return nil, false
+	}
+	attrs := attributes(ev)
+	return attrs, attrs[banktypes.AttributeKeyRecipient] == address
+}
+
+// wasmAttributes returns the attributes of the wasm event if it is one of the contract.
+func wasmAttributes(ev abci.Event, contract string) (map[string]string, bool) {
+	if ev.Type != wasmtypes.CustomEventType {
+		return nil, false
+	}
+	attrs := attributes(ev)
+	return attrs, attrs[wasmtypes.AttributeKeyContractAddr] == contract
+}
+
+// govStatus returns the proposal and its new status if the event changes the status of a proposal.
+func govStatus(ev abci.Event) (uint64, string, bool) {
+	attrs := attributes(ev)
+	var id, status string
+	switch ev.Type {
+	case govtypes.EventTypeSubmitProposal, govtypes.EventTypeProposalDeposit:
+		// the proposal is submitted, or its voting period starts
+		if votingID, ok := attrs[govtypes.AttributeKeyVotingPeriodStart]; ok {
+			id, status = votingID, GovStatusVotingPeriod
+		} else if submittedID, ok := attrs[govtypes.AttributeKeyProposalID]; ok && ev.Type == govtypes.EventTypeSubmitProposal {
+			id, status = submittedID, GovStatusDepositPeriod
+		} else {
+			return 0, "", false
+		}
+	case govtypes.EventTypeActiveProposal, govtypes.EventTypeInactiveProposal:
+		id = attrs[govtypes.AttributeKeyProposalID]
+		switch attrs[govtypes.AttributeKeyProposalResult] {
+		case govtypes.AttributeValueProposalPassed:
+			status = GovStatusPassed
+		case govtypes.AttributeValueProposalRejected:
+			status = GovStatusRejected
+		case govtypes.AttributeValueProposalFailed:
+			status = GovStatusFailed
+		case govtypes.AttributeValueProposalDropped:
+			status = GovStatusDropped
+		default:
+			return 0, "", false
+		}
+	default:
+		return 0, "", false
+	}
+	proposalID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return proposalID, status, true
+}
+
+// matches returns whether the attributes match the filter of the subscription.
+func (s *Subscription) matches(attrs map[string]string) bool {
+	if s.query == nil {
+		return true
+	}
+	events := make(map[string][]string, len(attrs))
+	for k, v := range attrs {
+		events[k] = []string{v}
+	}
+	ok, err := s.query.Matches(events)
+	return err == nil && ok
+}
@@ -0,0 +1,423 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	ctypes "github.com/line/ostracon/rpc/core/types"
+
+	"github.com/line/lbm-sdk/client"
+	codectypes "github.com/line/lbm-sdk/codec/types"
+	cryptocodec "github.com/line/lbm-sdk/crypto/codec"
+	sdk "github.com/line/lbm-sdk/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+)
+
+// The types of the messages of the gateway.
+const (
+	MessageSubscribe    = "subscribe"
+	MessageUnsubscribe  = "unsubscribe"
+	MessageSubscribed   = "subscribed"
+	MessageUnsubscribed = "unsubscribed"
+	MessageEvent        = "event"
+	MessageCheckpoint   = "checkpoint"
+	MessageError        = "error"
+)
+
+const (
+	// maxSubscriptions is the maximum number of subscriptions of a connection.
+	maxSubscriptions = 16
+	// maxResultsRetries is the number of times the block results of the latest height are
+	// read again, one poll interval apart, before the stream fails.
+	maxResultsRetries = 3
+	pingInterval      = 30 * time.Second
+	pongWait          = 2 * pingInterval
+	writeWait         = 10 * time.Second
+)
+
+// pollInterval is the interval at which the latest height is polled once a subscription is
+// caught up.
+var pollInterval = time.Second
+
+// ClientMessage is a message from a client: a subscribe or unsubscribe request.
+type ClientMessage struct {
+	Type         string       `json:"type"`
+	Subscription Subscription `json:"subscription"`
+}
+
+// ServerMessage is a message to a client. A subscribed message carries the height the
+// subscription starts from, and a checkpoint message the height whose events have all
+// been sent.
+type ServerMessage struct {
+	Type         string `json:"type"`
+	Subscription string `json:"subscription,omitempty"`
+	Height       int64  `json:"height,omitempty"`
+	Event        *Event `json:"event,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RegisterRoutes registers the /events/ws route. allowAllOrigins allows cross-origin
+// connections, e.g. if enabled-unsafe-cors is set for the API server.
+func RegisterRoutes(clientCtx client.Context, r *mux.Router, allowAllOrigins bool) {
+	upgrader := websocket.Upgrader{}
+	if allowAllOrigins {
+		upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	}
+	r.HandleFunc("/events/ws", func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		c := &connection{clientCtx: clientCtx, conn: conn, subscriptions: map[string]*context.CancelFunc{}}
+		c.serve(req.Context())
+	}).Methods("GET")
+}
+
+// connection serves the subscriptions of a client.
+type connection struct {
+	clientCtx client.Context
+	conn      *websocket.Conn
+	writeMtx  sync.Mutex
+
+	mtx sync.Mutex
+	// subscriptions holds the cancel functions of the streams of the subscriptions by id
+	subscriptions map[string]*context.CancelFunc
+}
+
+// serve reads the requests of the client until the connection is closed.
+func (c *connection) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer c.conn.Close()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait)) // nolint: errcheck
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	go c.ping(ctx)
+
+	for {
+		_, bz, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg ClientMessage
+		if err := json.Unmarshal(bz, &msg); err != nil {
+			c.write(ServerMessage{Type: MessageError, Error: fmt.Sprintf("invalid message: %s", err)}) // nolint: errcheck
+			continue
+		}
+		switch msg.Type {
+		case MessageSubscribe:
+			c.subscribe(ctx, msg.Subscription)
+		case MessageUnsubscribe:
+			c.unsubscribe(msg.Subscription.ID)
+		default:
+			c.write(ServerMessage{Type: MessageError, Error: fmt.Sprintf("unknown message type %q", msg.Type)}) // nolint: errcheck
+		}
+	}
+}
+
+func (c *connection) ping(ctx context.Context) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.writeMtx.Lock()
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			c.writeMtx.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *connection) write(msg ServerMessage) error {
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *connection) subscribe(ctx context.Context, sub Subscription) {
+	if err := sub.Validate(); err != nil {
+		c.write(ServerMessage{Type: MessageError, Subscription: sub.ID, Error: err.Error()}) // nolint: errcheck
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.subscriptions[sub.ID]; ok {
+		c.write(ServerMessage{Type: MessageError, Subscription: sub.ID, Error: "subscription already exists"}) // nolint: errcheck
+		return
+	}
+	if len(c.subscriptions) >= maxSubscriptions {
+		c.write(ServerMessage{Type: MessageError, Subscription: sub.ID, // nolint: errcheck
+			Error: fmt.Sprintf("at most %d subscriptions are allowed", maxSubscriptions)})
+		return
+	}
+	subCtx, cancel := context.WithCancel(ctx)
+	c.subscriptions[sub.ID] = &cancel
+	go func() {
+		if err := c.stream(subCtx, sub); err != nil && subCtx.Err() == nil {
+			c.write(ServerMessage{Type: MessageError, Subscription: sub.ID, Error: err.Error()}) // nolint: errcheck
+		}
+		c.mtx.Lock()
+		// the id may have been unsubscribed and subscribed again meanwhile
+		if c.subscriptions[sub.ID] == &cancel {
+			delete(c.subscriptions, sub.ID)
+		}
+		c.mtx.Unlock()
+		cancel()
+	}()
+}
+
+func (c *connection) unsubscribe(id string) {
+	c.mtx.Lock()
+	cancel, ok := c.subscriptions[id]
+	delete(c.subscriptions, id)
+	c.mtx.Unlock()
+	if !ok {
+		c.write(ServerMessage{Type: MessageError, Subscription: id, Error: "subscription not found"}) // nolint: errcheck
+		return
+	}
+	(*cancel)()
+	c.write(ServerMessage{Type: MessageUnsubscribed, Subscription: id}) // nolint: errcheck
+}
+
+// stream sends the events of the subscription block by block until the context is done.
+func (c *connection) stream(ctx context.Context, sub Subscription) error {
+	node, err := c.clientCtx.GetNode()
+	if err != nil {
+		return err
+	}
+	status, err := node.Status(ctx)
+	if err != nil {
+		return err
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+	height := sub.FromHeight
+	if height == 0 {
+		height = latest + 1
+	}
+	if err := c.write(ServerMessage{Type: MessageSubscribed, Subscription: sub.ID, Height: height}); err != nil {
+		return err
+	}
+
+	retries := 0
+	for {
+		for height > latest {
+			if err := sleep(ctx, pollInterval); err != nil {
+				return err
+			}
+			if status, err = node.Status(ctx); err != nil {
+				return err
+			}
+			latest = status.SyncInfo.LatestBlockHeight
+		}
+
+		res, err := node.BlockResults(ctx, &height)
+		if err != nil {
+			// the results of the latest block may not be saved yet
+			if height == latest && retries < maxResultsRetries {
+				retries++
+				if err := sleep(ctx, pollInterval); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("failed to read the block results at height %d: %w", height, err)
+		}
+		retries = 0
+		events, err := c.blockEvents(ctx, sub, res)
+		if err != nil {
+			return fmt.Errorf("failed to read the events at height %d: %w", height, err)
+		}
+		for i := range events {
+			if err := c.write(ServerMessage{Type: MessageEvent, Subscription: sub.ID, Event: &events[i]}); err != nil {
+				return err
+			}
+		}
+		if err := c.write(ServerMessage{Type: MessageCheckpoint, Subscription: sub.ID, Height: height}); err != nil {
+			return err
+		}
+		height++
+	}
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// blockEvents returns the events of the stream of the subscription in the block results
+// that match its filter.
+func (c *connection) blockEvents(ctx context.Context, sub Subscription, res *ctypes.ResultBlockResults) ([]Event, error) {
+	var all []blockEvent
+	for _, ev := range res.BeginBlockEvents {
+		all = append(all, blockEvent{Event: ev, index: len(all), tx: -1})
+	}
+	for i, tx := range res.TxsResults {
+		for _, ev := range tx.Events {
+			all = append(all, blockEvent{Event: ev, index: len(all), tx: i})
+		}
+	}
+	for _, ev := range res.EndBlockEvents {
+		all = append(all, blockEvent{Event: ev, index: len(all), tx: -1})
+	}
+
+	b := &blockEvents{connection: c, ctx: ctx, sub: sub, height: res.Height}
+	switch sub.Stream {
+	case StreamTransfer:
+		for _, ev := range all {
+			attrs, ok := transferAttributes(ev.Event, sub.Address)
+			if !ok {
+				continue
+			}
+			amount, err := sdk.ParseCoinsNormalized(attrs[sdk.AttributeKeyAmount])
+			if err != nil {
+				return nil, err
+			}
+			b.add(ev.index, ev.tx, attrs, TransferPayload{
+				Sender:    attrs[banktypes.AttributeKeySender],
+				Recipient: attrs[banktypes.AttributeKeyRecipient],
+				Amount:    amount,
+			})
+		}
+
+	case StreamWasm:
+		for _, ev := range all {
+			attrs, ok := wasmAttributes(ev.Event, sub.Contract)
+			if !ok {
+				continue
+			}
+			payload := WasmPayload{Contract: sub.Contract, Attributes: []sdk.Attribute{}}
+			for _, attr := range ev.Attributes {
+				if string(attr.Key) != wasmtypes.AttributeKeyContractAddr {
+					payload.Attributes = append(payload.Attributes, sdk.NewAttribute(string(attr.Key), string(attr.Value)))
+				}
+			}
+			b.add(ev.index, ev.tx, attrs, payload)
+		}
+
+	case StreamGov:
+		for _, ev := range all {
+			id, status, ok := govStatus(ev.Event)
+			if !ok {
+				continue
+			}
+			attrs := map[string]string{
+				govtypes.AttributeKeyProposalID: fmt.Sprintf("%d", id),
+				"status":                        status,
+			}
+			b.add(ev.index, ev.tx, attrs, GovPayload{ProposalID: id, Status: status, Proposal: c.proposal(ctx, res.Height, id, status)})
+		}
+
+	case StreamValidatorSet:
+		for i, update := range res.ValidatorUpdates {
+			pk, err := cryptocodec.FromOcProtoPublicKey(update.PubKey)
+			if err != nil {
+				return nil, err
+			}
+			any, err := codectypes.NewAnyWithValue(pk)
+			if err != nil {
+				return nil, err
+			}
+			pkJSON, err := c.clientCtx.JSONMarshaler.MarshalJSON(any)
+			if err != nil {
+				return nil, err
+			}
+			address := sdk.BytesToConsAddress(pk.Address()).String()
+			attrs := map[string]string{
+				"address": address,
+				"power":   fmt.Sprintf("%d", update.Power),
+			}
+			b.add(len(all)+i, -1, attrs, ValidatorPayload{Address: address, PubKey: pkJSON, Power: update.Power})
+		}
+	}
+	return b.events, b.err
+}
+
+// proposal returns the proposal as proto JSON, read at the height of the event or, for a
+// dropped proposal, which is deleted, before it. It returns nil if the proposal is not found.
+func (c *connection) proposal(ctx context.Context, height int64, id uint64, status string) json.RawMessage {
+	if status == GovStatusDropped {
+		height--
+	}
+	res, err := govtypes.NewQueryClient(c.clientCtx.WithHeight(height)).Proposal(ctx, &govtypes.QueryProposalRequest{ProposalId: id})
+	if err != nil {
+		return nil
+	}
+	bz, err := c.clientCtx.JSONMarshaler.MarshalJSON(&res.Proposal)
+	if err != nil {
+		return nil
+	}
+	return bz
+}
+
+// blockEvents collects the events of a block that match the filter of a subscription.
+type blockEvents struct {
+	*connection
+	ctx    context.Context
+	sub    Subscription
+	height int64
+	// txHashes are the hashes of the txs of the block, read when the first tx event matches.
+	txHashes []string
+	events   []Event
+	err      error
+}
+
+func (b *blockEvents) add(index, tx int, attrs map[string]string, payload interface{}) {
+	if b.err != nil || !b.sub.matches(attrs) {
+		return
+	}
+	ev := Event{Stream: b.sub.Stream, Height: b.height, Index: index, Attributes: attrs}
+	if ev.Payload, b.err = json.Marshal(payload); b.err != nil {
+		return
+	}
+	if tx >= 0 {
+		if b.txHashes == nil {
+			if b.txHashes, b.err = b.readTxHashes(); b.err != nil {
+				return
+			}
+		}
+		if tx < len(b.txHashes) {
+			ev.TxHash = b.txHashes[tx]
+		}
+	}
+	b.events = append(b.events, ev)
+}
+
+func (b *blockEvents) readTxHashes() ([]string, error) {
+	node, err := b.clientCtx.GetNode()
+	if err != nil {
+		return nil, err
+	}
+	block, err := node.Block(b.ctx, &b.height)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(block.Block.Txs))
+	for i, tx := range block.Block.Txs {
+		hashes[i] = fmt.Sprintf("%X", tx.Hash())
+	}
+	return hashes, nil
+}
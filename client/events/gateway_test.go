@@ -0,0 +1,380 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/bytes"
+	rpcclient "github.com/line/ostracon/rpc/client"
+	ctypes "github.com/line/ostracon/rpc/core/types"
+	octypes "github.com/line/ostracon/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client"
+	cryptocodec "github.com/line/lbm-sdk/crypto/codec"
+	"github.com/line/lbm-sdk/crypto/keys/ed25519"
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	"github.com/line/lbm-sdk/simapp/params"
+	"github.com/line/lbm-sdk/std"
+	sdk "github.com/line/lbm-sdk/types"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+)
+
+func init() {
+	pollInterval = 10 * time.Millisecond
+}
+
+func newAddress() string {
+	return sdk.BytesToAccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+}
+
+func event(typ string, kvs ...string) abci.Event {
+	ev := abci.Event{Type: typ}
+	for i := 0; i < len(kvs); i += 2 {
+		ev.Attributes = append(ev.Attributes, abci.EventAttribute{Key: []byte(kvs[i]), Value: []byte(kvs[i+1])})
+	}
+	return ev
+}
+
+func transfer(sender, recipient, amount string) abci.Event {
+	return event(banktypes.EventTypeTransfer,
+		banktypes.AttributeKeyRecipient, recipient, banktypes.AttributeKeySender, sender, sdk.AttributeKeyAmount, amount)
+}
+
+// mockNode is a node whose blocks are added by the test.
+type mockNode struct {
+	rpcclient.Client
+	cdc *params.EncodingConfig
+
+	mtx     sync.Mutex
+	results map[int64]*ctypes.ResultBlockResults
+	txs     map[int64]octypes.Txs
+	latest  int64
+	// failures is the number of times reading the results of a height fails
+	failures map[int64]int
+	// proposalHeights are the heights the proposals are queried at
+	proposalHeights []int64
+}
+
+func newMockNode(cdc *params.EncodingConfig) *mockNode {
+	return &mockNode{cdc: cdc, results: map[int64]*ctypes.ResultBlockResults{}, txs: map[int64]octypes.Txs{}, failures: map[int64]int{}}
+}
+
+// addBlock adds the next block with the results, whose txs have the events.
+func (n *mockNode) addBlock(res ctypes.ResultBlockResults, txEvents ...[]abci.Event) int64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.latest++
+	res.Height = n.latest
+	for i, events := range txEvents {
+		res.TxsResults = append(res.TxsResults, &abci.ResponseDeliverTx{Events: events})
+		n.txs[n.latest] = append(n.txs[n.latest], octypes.Tx(fmt.Sprintf("tx %d %d", n.latest, i)))
+	}
+	n.results[n.latest] = &res
+	return n.latest
+}
+
+func (n *mockNode) Status(context.Context) (*ctypes.ResultStatus, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: n.latest}}, nil
+}
+
+func (n *mockNode) BlockResults(_ context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if n.failures[*height] > 0 {
+		n.failures[*height]--
+		return nil, fmt.Errorf("no results at height %d", *height)
+	}
+	res, ok := n.results[*height]
+	if !ok {
+		return nil, fmt.Errorf("height %d must be less than or equal to the current blockchain height %d", *height, n.latest)
+	}
+	return res, nil
+}
+
+func (n *mockNode) Block(_ context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return &ctypes.ResultBlock{Block: &octypes.Block{Data: octypes.Data{Txs: n.txs[*height]}}}, nil
+}
+
+func (n *mockNode) ABCIQueryWithOptions(_ context.Context, _ string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	n.mtx.Lock()
+	n.proposalHeights = append(n.proposalHeights, opts.Height)
+	n.mtx.Unlock()
+
+	var req govtypes.QueryProposalRequest
+	if err := n.cdc.Marshaler.UnmarshalBinaryBare(data, &req); err != nil {
+		return nil, err
+	}
+	proposal, err := govtypes.NewProposal(govtypes.NewTextProposal("title", "description"), req.ProposalId, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	bz, err := n.cdc.Marshaler.MarshalBinaryBare(&govtypes.QueryProposalResponse{Proposal: proposal})
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: bz, Height: opts.Height}}, nil
+}
+
+// subscribe subscribes to the gateway served with the node on a new connection.
+func subscribe(t *testing.T, node *mockNode, sub Subscription) *websocket.Conn {
+	clientCtx := client.Context{}.
+		WithClient(node).
+		WithJSONMarshaler(node.cdc.Marshaler).
+		WithInterfaceRegistry(node.cdc.InterfaceRegistry)
+	r := mux.NewRouter()
+	RegisterRoutes(clientCtx, r, false)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/events/ws", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.WriteJSON(ClientMessage{Type: MessageSubscribe, Subscription: sub}))
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) ServerMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg ServerMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+// readEvents reads the events up to the checkpoint of the height.
+func readEvents(t *testing.T, conn *websocket.Conn, height int64) []Event {
+	var events []Event
+	for {
+		msg := read(t, conn)
+		switch msg.Type {
+		case MessageEvent:
+			events = append(events, *msg.Event)
+		case MessageCheckpoint:
+			if msg.Height == height {
+				return events
+			}
+		default:
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	}
+}
+
+func newTestNode() *mockNode {
+	cdc := params.MakeTestEncodingConfig()
+	std.RegisterInterfaces(cdc.InterfaceRegistry)
+	govtypes.RegisterInterfaces(cdc.InterfaceRegistry)
+	return newMockNode(&cdc)
+}
+
+func TestTransferStream(t *testing.T) {
+	node := newTestNode()
+	alice, bob := newAddress(), newAddress()
+
+	node.addBlock(ctypes.ResultBlockResults{BeginBlockEvents: []abci.Event{transfer(bob, alice, "5stake")}})
+	node.addBlock(ctypes.ResultBlockResults{},
+		[]abci.Event{transfer(bob, alice, "100stake")},
+		[]abci.Event{transfer(alice, bob, "7stake"), transfer(bob, alice, "2000stake,1ucony")})
+
+	conn := subscribe(t, node, Subscription{ID: "1", Stream: StreamTransfer, Address: alice, FromHeight: 1})
+	require.Equal(t, ServerMessage{Type: MessageSubscribed, Subscription: "1", Height: 1}, read(t, conn))
+	events := readEvents(t, conn, 2)
+	require.Len(t, events, 3)
+	require.Equal(t, int64(1), events[0].Height)
+	require.Empty(t, events[0].TxHash)
+	require.Equal(t, int64(2), events[1].Height)
+	require.Equal(t, fmt.Sprintf("%X", octypes.Tx("tx 2 0").Hash()), events[1].TxHash)
+	require.Equal(t, fmt.Sprintf("%X", octypes.Tx("tx 2 1").Hash()), events[2].TxHash)
+	require.Equal(t, []int{0, 0, 2}, []int{events[0].Index, events[1].Index, events[2].Index})
+
+	var payload TransferPayload
+	require.NoError(t, json.Unmarshal(events[2].Payload, &payload))
+	require.Equal(t, TransferPayload{Sender: bob, Recipient: alice,
+		Amount: sdk.NewCoins(sdk.NewInt64Coin("stake", 2000), sdk.NewInt64Coin("ucony", 1))}, payload)
+
+	// the blocks added later are streamed
+	node.addBlock(ctypes.ResultBlockResults{EndBlockEvents: []abci.Event{transfer(bob, alice, "1stake")}})
+	events = readEvents(t, conn, 3)
+	require.Len(t, events, 1)
+	require.Equal(t, "1stake", events[0].Attributes[sdk.AttributeKeyAmount])
+}
+
+func TestStreamFilters(t *testing.T) {
+	node := newTestNode()
+	alice, bob, carol, contract := newAddress(), newAddress(), newAddress(), newAddress()
+	valPubKey := ed25519.GenPrivKey().PubKey()
+	valUpdate, err := cryptocodec.ToOcProtoPublicKey(valPubKey)
+	require.NoError(t, err)
+
+	node.addBlock(ctypes.ResultBlockResults{})
+	node.addBlock(ctypes.ResultBlockResults{
+		BeginBlockEvents: []abci.Event{transfer(carol, alice, "1stake")},
+		EndBlockEvents: []abci.Event{
+			event(govtypes.EventTypeActiveProposal,
+				govtypes.AttributeKeyProposalID, "3", govtypes.AttributeKeyProposalResult, govtypes.AttributeValueProposalPassed),
+			event(govtypes.EventTypeInactiveProposal,
+				govtypes.AttributeKeyProposalID, "4", govtypes.AttributeKeyProposalResult, govtypes.AttributeValueProposalDropped),
+		},
+		ValidatorUpdates: []abci.ValidatorUpdate{{PubKey: valUpdate, Power: 10}},
+	},
+		[]abci.Event{transfer(bob, alice, "100stake"), transfer(bob, alice, "1000stake")},
+		[]abci.Event{
+			event(wasmtypes.CustomEventType, wasmtypes.AttributeKeyContractAddr, contract, "action", "enqueue"),
+			event(wasmtypes.CustomEventType, wasmtypes.AttributeKeyContractAddr, newAddress(), "action", "enqueue"),
+			event(wasmtypes.CustomEventType, wasmtypes.AttributeKeyContractAddr, contract, "action", "dequeue"),
+		},
+		[]abci.Event{event(govtypes.EventTypeSubmitProposal, govtypes.AttributeKeyProposalID, "5")},
+	)
+
+	for _, tc := range []struct {
+		sub        Subscription
+		attributes []map[string]string
+	}{
+		{
+			Subscription{Stream: StreamTransfer, Address: alice, Filter: fmt.Sprintf("sender = '%s' AND amount > 500", bob)},
+			[]map[string]string{{"sender": bob, "recipient": alice, "amount": "1000stake"}},
+		},
+		{
+			Subscription{Stream: StreamWasm, Contract: contract, Filter: "action = 'dequeue'"},
+			[]map[string]string{{wasmtypes.AttributeKeyContractAddr: contract, "action": "dequeue"}},
+		},
+		{
+			Subscription{Stream: StreamGov},
+			[]map[string]string{
+				{"proposal_id": "5", "status": GovStatusDepositPeriod},
+				{"proposal_id": "3", "status": GovStatusPassed},
+				{"proposal_id": "4", "status": GovStatusDropped},
+			},
+		},
+		{
+			Subscription{Stream: StreamGov, Filter: "status = 'dropped'"},
+			[]map[string]string{{"proposal_id": "4", "status": GovStatusDropped}},
+		},
+		{
+			Subscription{Stream: StreamValidatorSet, Filter: "power > 0"},
+			[]map[string]string{{"address": sdk.BytesToConsAddress(valPubKey.Address()).String(), "power": "10"}},
+		},
+		{
+			Subscription{Stream: StreamValidatorSet, Filter: "power = 0"},
+			nil,
+		},
+	} {
+		tc.sub.ID, tc.sub.FromHeight = "1", 2
+		conn := subscribe(t, node, tc.sub)
+		require.Equal(t, MessageSubscribed, read(t, conn).Type)
+		var attributes []map[string]string
+		for _, ev := range readEvents(t, conn, 2) {
+			require.Equal(t, tc.sub.Stream, ev.Stream)
+			attributes = append(attributes, ev.Attributes)
+		}
+		require.Equal(t, tc.attributes, attributes, "%+v", tc.sub)
+	}
+
+	// the dropped proposal, which is deleted at its height, is read before it
+	conn := subscribe(t, node, Subscription{ID: "1", Stream: StreamGov, Filter: "proposal_id = 4", FromHeight: 2})
+	require.Equal(t, MessageSubscribed, read(t, conn).Type)
+	events := readEvents(t, conn, 2)
+	require.Len(t, events, 1)
+	var payload GovPayload
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	require.Contains(t, string(payload.Proposal), `"proposal_id":"4"`)
+	node.mtx.Lock()
+	require.Equal(t, int64(1), node.proposalHeights[len(node.proposalHeights)-1])
+	node.mtx.Unlock()
+}
+
+func TestResume(t *testing.T) {
+	node := newTestNode()
+	alice, bob := newAddress(), newAddress()
+	for i := 1; i <= 4; i++ {
+		node.addBlock(ctypes.ResultBlockResults{}, []abci.Event{transfer(bob, alice, fmt.Sprintf("%dstake", i))})
+	}
+
+	// a client resumes from the height after its last checkpoint
+	conn := subscribe(t, node, Subscription{ID: "1", Stream: StreamTransfer, Address: alice, FromHeight: 3})
+	require.Equal(t, ServerMessage{Type: MessageSubscribed, Subscription: "1", Height: 3}, read(t, conn))
+	var heights []int64
+	for _, ev := range readEvents(t, conn, 4) {
+		heights = append(heights, ev.Height)
+	}
+	require.Equal(t, []int64{3, 4}, heights)
+
+	// without a height, the subscription starts at the next block
+	conn = subscribe(t, node, Subscription{ID: "2", Stream: StreamTransfer, Address: alice})
+	require.Equal(t, ServerMessage{Type: MessageSubscribed, Subscription: "2", Height: 5}, read(t, conn))
+	node.addBlock(ctypes.ResultBlockResults{}, []abci.Event{transfer(bob, alice, "5stake")})
+	events := readEvents(t, conn, 5)
+	require.Len(t, events, 1)
+	require.Equal(t, int64(5), events[0].Height)
+}
+
+func TestLatestResultsRetries(t *testing.T) {
+	node := newTestNode()
+	alice, bob := newAddress(), newAddress()
+	node.addBlock(ctypes.ResultBlockResults{}, []abci.Event{transfer(bob, alice, "1stake")})
+
+	// the results of the latest block are saved after a while
+	node.failures[1] = maxResultsRetries
+	conn := subscribe(t, node, Subscription{ID: "1", Stream: StreamTransfer, Address: alice, FromHeight: 1})
+	require.Equal(t, MessageSubscribed, read(t, conn).Type)
+	require.Len(t, readEvents(t, conn, 1), 1)
+
+	// but not forever
+	node.failures[2] = maxResultsRetries + 1
+	node.addBlock(ctypes.ResultBlockResults{}, []abci.Event{transfer(bob, alice, "2stake")})
+	msg := read(t, conn)
+	require.Equal(t, MessageError, msg.Type)
+	require.Equal(t, "1", msg.Subscription)
+	require.Contains(t, msg.Error, "height 2")
+
+	// the results below the latest height are not retried
+	node.addBlock(ctypes.ResultBlockResults{})
+	node.failures[2] = 1
+	conn = subscribe(t, node, Subscription{ID: "1", Stream: StreamTransfer, Address: alice, FromHeight: 2})
+	require.Equal(t, MessageSubscribed, read(t, conn).Type)
+	msg = read(t, conn)
+	require.Equal(t, MessageError, msg.Type)
+	require.Contains(t, msg.Error, "height 2")
+}
+
+func TestSubscriptionErrors(t *testing.T) {
+	node := newTestNode()
+	alice := newAddress()
+	conn := subscribe(t, node, Subscription{ID: "1", Stream: StreamGov})
+	require.Equal(t, MessageSubscribed, read(t, conn).Type)
+
+	for _, msg := range []ClientMessage{
+		{Type: MessageSubscribe, Subscription: Subscription{ID: "1", Stream: StreamGov}},
+		{Type: MessageSubscribe, Subscription: Subscription{ID: "2", Stream: "blocks"}},
+		{Type: MessageSubscribe, Subscription: Subscription{Stream: StreamGov}},
+		{Type: MessageSubscribe, Subscription: Subscription{ID: "2", Stream: StreamTransfer, Address: "link1invalid"}},
+		{Type: MessageSubscribe, Subscription: Subscription{ID: "2", Stream: StreamWasm}},
+		{Type: MessageSubscribe, Subscription: Subscription{ID: "2", Stream: StreamTransfer, Address: alice, Filter: "amount >"}},
+		{Type: MessageSubscribe, Subscription: Subscription{ID: "2", Stream: StreamGov, FromHeight: -1}},
+		{Type: MessageUnsubscribe, Subscription: Subscription{ID: "2"}},
+		{Type: "publish"},
+	} {
+		require.NoError(t, conn.WriteJSON(msg))
+		require.Equal(t, MessageError, read(t, conn).Type, "%+v", msg)
+	}
+
+	require.NoError(t, conn.WriteJSON(ClientMessage{Type: MessageUnsubscribe, Subscription: Subscription{ID: "1"}}))
+	require.Equal(t, ServerMessage{Type: MessageUnsubscribed, Subscription: "1"}, read(t, conn))
+	// the id can be used again
+	require.NoError(t, conn.WriteJSON(ClientMessage{Type: MessageSubscribe, Subscription: Subscription{ID: "1", Stream: StreamGov}}))
+	require.Equal(t, MessageSubscribed, read(t, conn).Type)
+}
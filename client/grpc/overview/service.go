@@ -0,0 +1,184 @@
+package overview
+
+import (
+	"context"
+
+	gogogrpc "github.com/gogo/protobuf/grpc"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	codectypes "github.com/line/lbm-sdk/codec/types"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/query"
+	authtypes "github.com/line/lbm-sdk/x/auth/types"
+	vestexported "github.com/line/lbm-sdk/x/auth/vesting/exported"
+	distrtypes "github.com/line/lbm-sdk/x/distribution/types"
+	stakingtypes "github.com/line/lbm-sdk/x/staking/types"
+)
+
+// AccountKeeper defines the account keeper the overview reads the accounts from.
+type AccountKeeper interface {
+	GetAccount(ctx sdk.Context, addr sdk.AccAddress) authtypes.AccountI
+}
+
+// BankKeeper defines the bank keeper the overview reads the balances from.
+type BankKeeper interface {
+	GetAllBalances(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
+}
+
+// queryServer combines the keepers and queriers of the modules. As the service is registered
+// on the gRPC query router of the app, all the queries of a request are made on
+// the same context, and so at the same height.
+type queryServer struct {
+	accountKeeper AccountKeeper
+	bankKeeper    BankKeeper
+	staking       stakingtypes.QueryServer
+	distr         distrtypes.QueryServer
+}
+
+var _ QueryServer = queryServer{}
+var _ codectypes.UnpackInterfacesMessage = &QueryAccountOverviewResponse{}
+
+// NewQueryServer creates a new account overview query server.
+func NewQueryServer(
+	accountKeeper AccountKeeper,
+	bankKeeper BankKeeper,
+	staking stakingtypes.QueryServer,
+	distr distrtypes.QueryServer,
+) QueryServer {
+	return queryServer{
+		accountKeeper: accountKeeper,
+		bankKeeper:    bankKeeper,
+		staking:       staking,
+		distr:         distr,
+	}
+}
+
+// AccountOverview implements QueryServer.AccountOverview
+func (s queryServer) AccountOverview(c context.Context, req *QueryAccountOverviewRequest) (*QueryAccountOverviewResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "address cannot be empty")
+	}
+	if err := sdk.ValidateAccAddress(req.Address); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	addr := sdk.AccAddress(req.Address)
+	overview := &AccountOverview{
+		Height:  ctx.BlockHeight(),
+		Address: req.Address,
+	}
+
+	overview.Balances = s.bankKeeper.GetAllBalances(ctx, addr)
+	if acc := s.accountKeeper.GetAccount(ctx, addr); acc != nil {
+		var err error
+		if overview.Account, err = codectypes.NewAnyWithValue(acc); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		overview.Sequence = acc.GetSequence()
+		if vacc, ok := acc.(vestexported.VestingAccount); ok {
+			overview.Vesting = vestingOverview(vacc, overview.Balances, ctx)
+		}
+	}
+
+	delegations, err := s.staking.DelegatorDelegations(c, &stakingtypes.QueryDelegatorDelegationsRequest{
+		DelegatorAddr: req.Address,
+		Pagination:    req.Pagination,
+	})
+	// the staking querier fails with NotFound for a delegator without delegations
+	switch {
+	case status.Code(err) == codes.NotFound:
+	case err != nil:
+		return nil, err
+	default:
+		overview.Delegations = delegations.DelegationResponses
+		overview.DelegationsPagination = delegations.Pagination
+	}
+
+	if overview.UnbondingDelegations, err = s.unbondingDelegations(c, req.Address); err != nil {
+		return nil, err
+	}
+
+	rewards, err := s.distr.DelegationTotalRewards(c, &distrtypes.QueryDelegationTotalRewardsRequest{
+		DelegatorAddress: req.Address,
+	})
+	if err != nil {
+		return nil, err
+	}
+	overview.Rewards = rewards.Rewards
+	overview.TotalRewards = rewards.Total
+
+	return &QueryAccountOverviewResponse{Overview: overview}, nil
+}
+
+// unbondingDelegations returns all the unbonding delegations of the delegator.
+func (s queryServer) unbondingDelegations(c context.Context, delegator string) ([]stakingtypes.UnbondingDelegation, error) {
+	var unbondings []stakingtypes.UnbondingDelegation
+	pageReq := &query.PageRequest{}
+	for {
+		res, err := s.staking.DelegatorUnbondingDelegations(c, &stakingtypes.QueryDelegatorUnbondingDelegationsRequest{
+			DelegatorAddr: delegator,
+			Pagination:    pageReq,
+		})
+		if err != nil {
+			return nil, err
+		}
+		unbondings = append(unbondings, res.UnbondingResponses...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return unbondings, nil
+		}
+		pageReq = &query.PageRequest{Key: res.Pagination.NextKey}
+	}
+}
+
+// vestingOverview returns the vesting details of the account at the block time of the context.
+func vestingOverview(acc vestexported.VestingAccount, balances sdk.Coins, ctx sdk.Context) *VestingOverview {
+	blockTime := ctx.BlockTime()
+	locked := acc.LockedCoins(blockTime)
+	spendable, hasNeg := balances.SafeSub(locked)
+	if hasNeg {
+		spendable = sdk.NewCoins()
+	}
+	return &VestingOverview{
+		OriginalVesting:  acc.GetOriginalVesting(),
+		DelegatedFree:    acc.GetDelegatedFree(),
+		DelegatedVesting: acc.GetDelegatedVesting(),
+		Vested:           acc.GetVestedCoins(blockTime),
+		Vesting:          acc.GetVestingCoins(blockTime),
+		Locked:           locked,
+		Spendable:        spendable,
+		StartTime:        acc.GetStartTime(),
+		EndTime:          acc.GetEndTime(),
+	}
+}
+
+// UnpackInterfaces implements UnpackInterfacesMessage.UnpackInterfaces
+func (m *QueryAccountOverviewResponse) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error {
+	if m.Overview == nil || m.Overview.Account == nil {
+		return nil
+	}
+	var acc authtypes.AccountI
+	return unpacker.UnpackAny(m.Overview.Account, &acc)
+}
+
+// RegisterAccountOverviewService registers the account overview queries on the gRPC query router.
+func RegisterAccountOverviewService(
+	qrt gogogrpc.Server,
+	accountKeeper AccountKeeper,
+	bankKeeper BankKeeper,
+	staking stakingtypes.QueryServer,
+	distr distrtypes.QueryServer,
+) {
+	RegisterQueryServer(qrt, NewQueryServer(accountKeeper, bankKeeper, staking, distr))
+}
+
+// RegisterGRPCGatewayRoutes mounts the account overview service's GRPC-gateway routes on the
+// given Mux.
+func RegisterGRPCGatewayRoutes(clientConn gogogrpc.ClientConn, mux *runtime.ServeMux) {
+	RegisterQueryHandlerClient(context.Background(), mux, NewQueryClient(clientConn))
+}
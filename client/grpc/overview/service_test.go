@@ -0,0 +1,191 @@
+package overview
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/line/lbm-sdk/baseapp"
+	"github.com/line/lbm-sdk/simapp"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/query"
+	authtypes "github.com/line/lbm-sdk/x/auth/types"
+	vestingtypes "github.com/line/lbm-sdk/x/auth/vesting/types"
+	distrtypes "github.com/line/lbm-sdk/x/distribution/types"
+	stakingkeeper "github.com/line/lbm-sdk/x/staking/keeper"
+	"github.com/line/lbm-sdk/x/staking/teststaking"
+)
+
+func coins(amount int64) sdk.Coins {
+	return sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, amount))
+}
+
+// testChain has two bonded validators operated by the first two accounts, and a third
+// account vesting 60M of its 100M tokens over 100 hours, which delegates 20M to the first
+// validator and undelegates 5M of them. The second operator also delegates to the first
+// validator, which earns rewards in the next block.
+type testChain struct {
+	app       *simapp.SimApp
+	ctx       sdk.Context
+	start     time.Time
+	addrs     []sdk.AccAddress
+	valAddrs  []sdk.ValAddress
+	vestingTo time.Time
+}
+
+func newTestChain(t *testing.T) *testChain {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	app := simapp.Setup(false)
+	ctx := app.BaseApp.NewContext(false, ocproto.Header{Height: 10, Time: start})
+	addrs := simapp.AddTestAddrsIncremental(app, ctx, 3, sdk.NewInt(100_000_000))
+	valAddrs := simapp.ConvertAddrsToValAddrs(addrs)
+
+	vestingTo := start.Add(100 * time.Hour)
+	base := app.AccountKeeper.GetAccount(ctx, addrs[2]).(*authtypes.BaseAccount)
+	app.AccountKeeper.SetAccount(ctx, vestingtypes.NewContinuousVestingAccount(base, coins(60_000_000), start.Unix(), vestingTo.Unix()))
+
+	helper := teststaking.NewHelper(t, ctx, app.StakingKeeper)
+	for i, pk := range simapp.CreateTestPubKeys(2) {
+		helper.CreateValidator(valAddrs[i], pk, sdk.NewInt(50_000_000), true)
+	}
+	ctx = helper.TurnBlock(start)
+	helper.Delegate(addrs[2], valAddrs[0], sdk.NewInt(20_000_000))
+	// the bank keeper of the SDK does not save the delegation it tracks on a vesting account
+	vacc := app.AccountKeeper.GetAccount(ctx, addrs[2]).(*vestingtypes.ContinuousVestingAccount)
+	vacc.TrackDelegation(start, coins(100_000_000), coins(20_000_000))
+	app.AccountKeeper.SetAccount(ctx, vacc)
+	helper.Undelegate(addrs[2], valAddrs[0], sdk.NewInt(5_000_000), true)
+	helper.Delegate(addrs[1], valAddrs[0], sdk.NewInt(10_000_000))
+
+	// a delegation earns no rewards in the block it starts
+	ctx = ctx.WithBlockHeight(11)
+	val, found := app.StakingKeeper.GetValidator(ctx, valAddrs[0])
+	require.True(t, found)
+	app.DistrKeeper.AllocateTokensToValidator(ctx, val, sdk.NewDecCoins(sdk.NewInt64DecCoin(sdk.DefaultBondDenom, 1_000)))
+
+	return &testChain{app: app, ctx: ctx, start: start, addrs: addrs, valAddrs: valAddrs, vestingTo: vestingTo}
+}
+
+// client returns a client of the overview service registered on the query router of the
+// simapp, querying at the given block time.
+func (c *testChain) client(blockTime time.Time) QueryClient {
+	helper := baseapp.NewQueryServerTestHelper(c.ctx.WithBlockTime(blockTime), c.app.InterfaceRegistry())
+	RegisterAccountOverviewService(helper, c.app.AccountKeeper, c.app.BankKeeper,
+		stakingkeeper.Querier{Keeper: c.app.StakingKeeper}, c.app.DistrKeeper)
+	return NewQueryClient(helper)
+}
+
+func (c *testChain) totalRewards(t *testing.T, delegator sdk.AccAddress) *distrtypes.QueryDelegationTotalRewardsResponse {
+	rewards, err := c.app.DistrKeeper.DelegationTotalRewards(sdk.WrapSDKContext(c.ctx),
+		&distrtypes.QueryDelegationTotalRewardsRequest{DelegatorAddress: delegator.String()})
+	require.NoError(t, err)
+	return rewards
+}
+
+func TestAccountOverview(t *testing.T) {
+	c := newTestChain(t)
+	res, err := c.client(c.start).AccountOverview(context.Background(), &QueryAccountOverviewRequest{Address: c.addrs[1].String()})
+	require.NoError(t, err)
+	overview := res.Overview
+	require.Equal(t, int64(11), overview.Height)
+	require.Equal(t, c.addrs[1].String(), overview.Address)
+	require.Equal(t, coins(40_000_000), overview.Balances)
+	acc, ok := overview.Account.GetCachedValue().(*authtypes.BaseAccount)
+	require.True(t, ok)
+	require.Equal(t, c.addrs[1].String(), acc.Address)
+	require.Equal(t, acc.Sequence, overview.Sequence)
+	require.Nil(t, overview.Vesting)
+
+	require.Len(t, overview.Delegations, 2)
+	delegated := map[string]sdk.Int{}
+	for _, d := range overview.Delegations {
+		require.Equal(t, c.addrs[1].String(), d.Delegation.DelegatorAddress)
+		delegated[d.Delegation.ValidatorAddress] = d.Balance.Amount
+	}
+	require.Equal(t, map[string]sdk.Int{
+		c.valAddrs[0].String(): sdk.NewInt(10_000_000),
+		c.valAddrs[1].String(): sdk.NewInt(50_000_000),
+	}, delegated)
+	require.Empty(t, overview.UnbondingDelegations)
+
+	// the rewards are those of the delegation on the first validator
+	rewards := c.totalRewards(t, c.addrs[1])
+	require.False(t, rewards.Total.IsZero())
+	require.Equal(t, rewards.Rewards, overview.Rewards)
+	require.Equal(t, rewards.Total, overview.TotalRewards)
+
+	// the delegations are paginated
+	res, err = c.client(c.start).AccountOverview(context.Background(), &QueryAccountOverviewRequest{
+		Address:    c.addrs[1].String(),
+		Pagination: &query.PageRequest{Limit: 1, CountTotal: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Overview.Delegations, 1)
+	require.Equal(t, uint64(2), res.Overview.DelegationsPagination.Total)
+	require.NotEmpty(t, res.Overview.DelegationsPagination.NextKey)
+}
+
+func TestVestingAccountOverview(t *testing.T) {
+	c := newTestChain(t)
+	addr := c.addrs[2]
+
+	// half of the vesting coins are vested, and the delegated vesting coins are not locked
+	res, err := c.client(c.start.Add(50*time.Hour)).AccountOverview(context.Background(), &QueryAccountOverviewRequest{Address: addr.String()})
+	require.NoError(t, err)
+	overview := res.Overview
+	require.Equal(t, coins(80_000_000), overview.Balances)
+	_, ok := overview.Account.GetCachedValue().(*vestingtypes.ContinuousVestingAccount)
+	require.True(t, ok)
+	require.Equal(t, &VestingOverview{
+		OriginalVesting:  coins(60_000_000),
+		DelegatedFree:    nil,
+		DelegatedVesting: coins(20_000_000),
+		Vested:           coins(30_000_000),
+		Vesting:          coins(30_000_000),
+		Locked:           coins(10_000_000),
+		Spendable:        coins(70_000_000),
+		StartTime:        c.start.Unix(),
+		EndTime:          c.vestingTo.Unix(),
+	}, overview.Vesting)
+
+	require.Len(t, overview.Delegations, 1)
+	require.Equal(t, c.valAddrs[0].String(), overview.Delegations[0].Delegation.ValidatorAddress)
+	require.Equal(t, sdk.NewInt(15_000_000), overview.Delegations[0].Balance.Amount)
+	require.Len(t, overview.UnbondingDelegations, 1)
+	require.Equal(t, c.valAddrs[0].String(), overview.UnbondingDelegations[0].ValidatorAddress)
+	require.Len(t, overview.UnbondingDelegations[0].Entries, 1)
+	require.Equal(t, sdk.NewInt(5_000_000), overview.UnbondingDelegations[0].Entries[0].Balance)
+	require.False(t, overview.TotalRewards.IsZero())
+	require.Equal(t, c.totalRewards(t, addr).Total, overview.TotalRewards)
+
+	// every coin is spendable once vested
+	res, err = c.client(c.vestingTo).AccountOverview(context.Background(), &QueryAccountOverviewRequest{Address: addr.String()})
+	require.NoError(t, err)
+	require.Equal(t, coins(60_000_000), res.Overview.Vesting.Vested)
+	require.True(t, res.Overview.Vesting.Locked.IsZero())
+	require.Equal(t, coins(80_000_000), res.Overview.Vesting.Spendable)
+}
+
+func TestUnknownAccountOverview(t *testing.T) {
+	c := newTestChain(t)
+	client := c.client(c.start)
+
+	addr := sdk.BytesToAccAddress([]byte("unknown-account-addr"))
+	res, err := client.AccountOverview(context.Background(), &QueryAccountOverviewRequest{Address: addr.String()})
+	require.NoError(t, err)
+	require.Nil(t, res.Overview.Account)
+	require.Empty(t, res.Overview.Balances)
+	require.Empty(t, res.Overview.Delegations)
+	require.Empty(t, res.Overview.UnbondingDelegations)
+	require.Empty(t, res.Overview.Rewards)
+
+	for _, address := range []string{"", "link1invalid", c.valAddrs[0].String()} {
+		_, err := client.AccountOverview(context.Background(), &QueryAccountOverviewRequest{Address: address})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), address)
+	}
+}
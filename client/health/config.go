@@ -0,0 +1,66 @@
+package health
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/line/lbm-sdk/server"
+	servertypes "github.com/line/lbm-sdk/server/types"
+)
+
+// The keys of the thresholds in the [health] section of app.toml.
+const (
+	FlagMaxBlockAge      = "health.max-block-age"
+	FlagMinPeers         = "health.min-peers"
+	FlagHaltHeightMargin = "health.halt-height-margin"
+)
+
+// Config are the thresholds of the readiness checks.
+type Config struct {
+	// MaxBlockAge is the maximum time since the latest block of a ready node.
+	MaxBlockAge time.Duration
+	// MinPeers is the minimum number of peers of a ready node.
+	MinPeers int
+	// HaltHeightMargin is the number of blocks before the halt height
+	// from which on the node is not ready anymore.
+	HaltHeightMargin int64
+	// HaltHeight is the halt-height of app.toml; 0 if the node does not halt.
+	HaltHeight int64
+}
+
+// DefaultConfig returns the default thresholds.
+func DefaultConfig() Config {
+	return Config{
+		MaxBlockAge:      time.Minute,
+		MinPeers:         0,
+		HaltHeightMargin: 100,
+	}
+}
+
+// ReadConfig reads the thresholds from app.toml, falling back to the defaults.
+func ReadConfig(opts servertypes.AppOptions) (Config, error) {
+	cfg := DefaultConfig()
+	var err error
+	if v := opts.Get(FlagMaxBlockAge); v != nil {
+		if cfg.MaxBlockAge, err = cast.ToDurationE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagMinPeers); v != nil {
+		if cfg.MinPeers, err = cast.ToIntE(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(FlagHaltHeightMargin); v != nil {
+		if cfg.HaltHeightMargin, err = cast.ToInt64E(v); err != nil {
+			return cfg, err
+		}
+	}
+	if v := opts.Get(server.FlagHaltHeight); v != nil {
+		if cfg.HaltHeight, err = cast.ToInt64E(v); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
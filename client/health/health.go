@@ -0,0 +1,203 @@
+// Package health serves the /health and /ready endpoints of the API server for load
+// balancers and orchestrators to probe.
+//
+// /health reports whether the node is alive: its RPC responds and the wasm VM cache has
+// been initialized. /ready additionally requires the node to be caught up, to have a
+// recent latest block, enough peers, and not to be close to its halt height. Both
+// respond with 200 if the checks pass and 503 otherwise, with a JSON report.
+//
+// The thresholds are read from the [health] section of app.toml:
+//
+//	[health]
+//	# the maximum time since the latest block of a ready node
+//	max-block-age = "1m"
+//	# the minimum number of peers of a ready node
+//	min-peers = 0
+//	# the number of blocks before halt-height from which on the node is not ready
+//	halt-height-margin = 100
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	ctypes "github.com/line/ostracon/rpc/core/types"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/version"
+)
+
+// The statuses of a report.
+const (
+	StatusOK       = "ok"
+	StatusNotReady = "not_ready"
+	StatusFailing  = "failing"
+)
+
+// Check is the result of a single check.
+type Check struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// Version is the version of the binary and the protocol versions of the node.
+type Version struct {
+	App         string `json:"app"`
+	Commit      string `json:"commit"`
+	Ostracon    string `json:"ostracon,omitempty"`
+	AppProtocol uint64 `json:"app_protocol,omitempty"`
+}
+
+// Report is the JSON response of the endpoints.
+type Report struct {
+	Status          string    `json:"status"`
+	Version         Version   `json:"version"`
+	Height          int64     `json:"height,omitempty"`
+	LatestBlockTime time.Time `json:"latest_block_time,omitempty"`
+	LatestBlockAge  string    `json:"latest_block_age,omitempty"`
+	CatchingUp      bool      `json:"catching_up"`
+	Peers           int       `json:"peers"`
+	HaltHeight      int64     `json:"halt_height,omitempty"`
+	WasmVMCache     bool      `json:"wasm_vm_cache"`
+	Checks          []Check   `json:"checks"`
+}
+
+// OK returns whether all checks passed.
+func (r Report) OK() bool {
+	for _, c := range r.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) check(name string, ok bool, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, Check{Name: name, OK: ok, Message: fmt.Sprintf(format, args...)})
+}
+
+// NodeInfo is the state of the node the checks are evaluated on.
+type NodeInfo struct {
+	// Status is nil if the RPC of the node did not respond.
+	Status *ctypes.ResultStatus
+	// StatusErr is the error of the RPC, if any.
+	StatusErr error
+	Peers     int
+	// WasmVMCache is whether the wasm VM cache has been initialized.
+	WasmVMCache bool
+}
+
+// Health evaluates whether the node is alive at the given time.
+func (c Config) Health(info NodeInfo, now time.Time) Report {
+	r := newReport(c, info, now)
+	if info.Status == nil {
+		r.check("rpc", false, "the RPC of the node does not respond: %s", info.StatusErr)
+	} else {
+		r.check("rpc", true, "")
+	}
+	r.check("wasm_vm_cache", info.WasmVMCache, "%s", wasmMessage(info.WasmVMCache))
+	r.Status = StatusOK
+	if !r.OK() {
+		r.Status = StatusFailing
+	}
+	return r
+}
+
+// Ready evaluates whether the node is ready to serve requests at the given time.
+func (c Config) Ready(info NodeInfo, now time.Time) Report {
+	r := c.Health(info, now)
+	if r.Status != StatusOK {
+		return r
+	}
+
+	sync := info.Status.SyncInfo
+	r.check("catching_up", !sync.CatchingUp, "%s", catchingUpMessage(sync.CatchingUp))
+	age := now.Sub(sync.LatestBlockTime)
+	r.check("latest_block_age", age <= c.MaxBlockAge, "the latest block is %s old; the maximum is %s",
+		age.Round(time.Millisecond), c.MaxBlockAge)
+	r.check("peers", info.Peers >= c.MinPeers, "%d peers; the minimum is %d", info.Peers, c.MinPeers)
+	if c.HaltHeight > 0 {
+		remaining := c.HaltHeight - sync.LatestBlockHeight
+		r.check("halt_height", remaining > c.HaltHeightMargin, "%d blocks to the halt height %d; the margin is %d",
+			remaining, c.HaltHeight, c.HaltHeightMargin)
+	}
+	if !r.OK() {
+		r.Status = StatusNotReady
+	}
+	return r
+}
+
+func newReport(c Config, info NodeInfo, now time.Time) Report {
+	r := Report{
+		Version:     Version{App: version.Version, Commit: version.Commit},
+		Peers:       info.Peers,
+		HaltHeight:  c.HaltHeight,
+		WasmVMCache: info.WasmVMCache,
+	}
+	if info.Status != nil {
+		r.Version.Ostracon = info.Status.NodeInfo.Version
+		r.Version.AppProtocol = info.Status.NodeInfo.ProtocolVersion.App
+		r.Height = info.Status.SyncInfo.LatestBlockHeight
+		r.LatestBlockTime = info.Status.SyncInfo.LatestBlockTime
+		r.LatestBlockAge = now.Sub(r.LatestBlockTime).Round(time.Millisecond).String()
+		r.CatchingUp = info.Status.SyncInfo.CatchingUp
+	}
+	return r
+}
+
+func wasmMessage(ok bool) string {
+	if ok {
+		return ""
+	}
+	return "the wasm VM cache has not been initialized"
+}
+
+func catchingUpMessage(catchingUp bool) string {
+	if catchingUp {
+		return "the node is catching up"
+	}
+	return ""
+}
+
+// RegisterRoutes registers the /health and /ready routes. wasmVMCache reports
+// whether the wasm VM cache of the app has been initialized.
+func RegisterRoutes(clientCtx client.Context, r *mux.Router, cfg Config, wasmVMCache func() bool) {
+	r.HandleFunc("/health", func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, cfg.Health(nodeInfo(req.Context(), clientCtx, wasmVMCache), time.Now()))
+	}).Methods("GET")
+	r.HandleFunc("/ready", func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, cfg.Ready(nodeInfo(req.Context(), clientCtx, wasmVMCache), time.Now()))
+	}).Methods("GET")
+}
+
+// nodeInfo queries the status and the peers of the node over its RPC.
+func nodeInfo(ctx context.Context, clientCtx client.Context, wasmVMCache func() bool) NodeInfo {
+	info := NodeInfo{WasmVMCache: wasmVMCache()}
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		info.StatusErr = err
+		return info
+	}
+	if info.Status, info.StatusErr = node.Status(ctx); info.StatusErr != nil {
+		return info
+	}
+	if netInfo, err := node.NetInfo(ctx); err == nil {
+		info.Peers = netInfo.NPeers
+	}
+	return info
+}
+
+func writeReport(w http.ResponseWriter, r Report) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Status == StatusOK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(r)
+}
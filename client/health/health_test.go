@@ -0,0 +1,105 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	ctypes "github.com/line/ostracon/rpc/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client"
+)
+
+func TestHealth(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	status := &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: 100, LatestBlockTime: now.Add(-time.Hour)}}
+	cfg := DefaultConfig()
+
+	// a node catching up far behind is alive
+	r := cfg.Health(NodeInfo{Status: status, WasmVMCache: true}, now)
+	require.Equal(t, StatusOK, r.Status)
+	require.True(t, r.OK())
+	require.Equal(t, int64(100), r.Height)
+	require.Equal(t, "1h0m0s", r.LatestBlockAge)
+
+	r = cfg.Health(NodeInfo{StatusErr: errors.New("connection refused"), WasmVMCache: true}, now)
+	require.Equal(t, StatusFailing, r.Status)
+	require.Equal(t, Check{Name: "rpc", Message: "the RPC of the node does not respond: connection refused"}, r.Checks[0])
+	require.Zero(t, r.Height)
+
+	r = cfg.Health(NodeInfo{Status: status}, now)
+	require.Equal(t, StatusFailing, r.Status)
+	require.Equal(t, []Check{{Name: "rpc", OK: true}, {Name: "wasm_vm_cache", Message: wasmMessage(false)}}, r.Checks)
+}
+
+func TestReady(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	cfg := Config{MaxBlockAge: time.Minute, MinPeers: 2, HaltHeightMargin: 10}
+	info := func(height int64, age time.Duration, catchingUp bool, peers int) NodeInfo {
+		return NodeInfo{
+			Status: &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{
+				LatestBlockHeight: height, LatestBlockTime: now.Add(-age), CatchingUp: catchingUp,
+			}},
+			Peers:       peers,
+			WasmVMCache: true,
+		}
+	}
+	// failed returns the names of the failed checks
+	failed := func(r Report) []string {
+		names := []string{}
+		for _, c := range r.Checks {
+			if !c.OK {
+				names = append(names, c.Name)
+			}
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		name       string
+		haltHeight int64
+		info       NodeInfo
+		status     string
+		failed     []string
+	}{
+		{"ready", 0, info(100, time.Minute, false, 2), StatusOK, []string{}},
+		{"catching up", 0, info(100, time.Second, true, 2), StatusNotReady, []string{"catching_up"}},
+		{"old block", 0, info(100, time.Minute+time.Millisecond, false, 2), StatusNotReady, []string{"latest_block_age"}},
+		{"few peers", 0, info(100, time.Second, false, 1), StatusNotReady, []string{"peers"}},
+		{"far from the halt height", 111, info(100, time.Second, false, 2), StatusOK, []string{}},
+		{"close to the halt height", 110, info(100, time.Second, false, 2), StatusNotReady, []string{"halt_height"}},
+		{"halted", 100, info(100, time.Hour, false, 2), StatusNotReady, []string{"latest_block_age", "halt_height"}},
+		// a node that is not alive is failing, and not checked further
+		{"no rpc", 0, NodeInfo{StatusErr: errors.New("timeout"), WasmVMCache: true}, StatusFailing, []string{"rpc"}},
+		{"no wasm vm cache", 0, NodeInfo{Status: info(100, time.Hour, true, 0).Status}, StatusFailing, []string{"wasm_vm_cache"}},
+	} {
+		cfg.HaltHeight = tc.haltHeight
+		r := cfg.Ready(tc.info, now)
+		require.Equal(t, tc.status, r.Status, tc.name)
+		require.Equal(t, tc.failed, failed(r), tc.name)
+		require.Equal(t, tc.status == StatusOK, r.OK(), tc.name)
+		require.Equal(t, tc.haltHeight, r.HaltHeight, tc.name)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	r := mux.NewRouter()
+	// the client has no node to query
+	RegisterRoutes(client.Context{}, r, DefaultConfig(), func() bool { return true })
+
+	for _, path := range []string{"/health", "/ready"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"), path)
+		var report Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report), path)
+		require.Equal(t, StatusFailing, report.Status, path)
+		require.True(t, report.WasmVMCache, path)
+	}
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ostcfg "github.com/line/ostracon/config"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/line/tm-db/v2/metadb"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/server"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/version"
+)
+
+const (
+	flagFrom = "from"
+	flagTo   = "to"
+
+	appDBName = "application"
+
+	// migrateBatchSize is the number of keys written to the target database in a single batch.
+	migrateBatchSize = 10000
+	// migrateProgressInterval is the number of keys after which the migration progress is reported.
+	migrateProgressInterval = 100000
+)
+
+// ostraconDBNames are the databases of ostracon under the data directory.
+var ostraconDBNames = []string{"blockstore", "state", "evidence", "tx_index"}
+
+// nodeDB is one of the databases of a node.
+type nodeDB struct {
+	Name    string
+	Dir     string
+	Backend metadb.BackendType
+}
+
+func (d nodeDB) Path() string {
+	return filepath.Join(d.Dir, d.Name+".db")
+}
+
+func (d nodeDB) Open() (dbm.DB, error) {
+	return metadb.NewDB(d.Name, d.Backend, d.Dir)
+}
+
+// nodeDBs returns the existing application and ostracon databases of the node.
+func nodeDBs(config *ostcfg.Config, appBackend, ostraconBackend metadb.BackendType) []nodeDB {
+	dbs := []nodeDB{{Name: appDBName, Dir: filepath.Join(config.RootDir, "data"), Backend: appBackend}}
+	for _, name := range ostraconDBNames {
+		dbs = append(dbs, nodeDB{Name: name, Dir: config.DBDir(), Backend: ostraconBackend})
+	}
+
+	existing := dbs[:0]
+	for _, db := range dbs {
+		if _, err := os.Stat(db.Path()); err == nil {
+			existing = append(existing, db)
+		}
+	}
+	return existing
+}
+
+// appDBBackend returns the backend of the application database this binary was built with.
+func appDBBackend() metadb.BackendType {
+	if sdk.DBBackend != "" {
+		return metadb.BackendType(sdk.DBBackend)
+	}
+	return metadb.GoLevelDBBackend
+}
+
+// dbCmd returns the commands to inspect and migrate the databases of a stopped node.
+func dbCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and migrate the databases of a node offline",
+		RunE:  client.ValidateCmd,
+	}
+	cmd.AddCommand(
+		dbMigrateCmd(),
+		dbStatsCmd(),
+	)
+	return cmd
+}
+
+func dbMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy the databases of the node to another database backend",
+		Long: fmt.Sprintf(`Copy application.db and the ostracon databases (%s) key by key
+to another database backend, and verify the copies with a checksum over all keys and values.
+
+The copies are written under data/migrate-<to> first. Once every database is verified,
+the original databases are moved to data/backup-<from> and the copies take their place,
+and db_backend of config.toml is set to the new backend. If a database can't be moved,
+the databases moved before it are moved back. The binary must be built with both
+backends, e.g. 'make build DB_BACKEND=rocksdb', to use the new application database.
+
+The node must not be running while this command is used.`, strings.Join(ostraconDBNames, ", ")),
+		Example: fmt.Sprintf("$ %s db migrate --from goleveldb --to rocksdb", version.AppName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			fromFlag, _ := cmd.Flags().GetString(flagFrom)
+			toFlag, _ := cmd.Flags().GetString(flagTo)
+			from, to := metadb.BackendType(fromFlag), metadb.BackendType(toFlag)
+			if from == to {
+				return fmt.Errorf("the source and target backends are the same: %s", from)
+			}
+			for _, backend := range []metadb.BackendType{from, to} {
+				if !backendAvailable(backend) {
+					return fmt.Errorf("backend %s is not available in this binary; available: %v", backend, metadb.AvailableDBBackends())
+				}
+			}
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+			dataDir := filepath.Join(config.RootDir, "data")
+			migrateDir := filepath.Join(dataDir, "migrate-"+string(to))
+			backupDir := filepath.Join(dataDir, "backup-"+string(from))
+			for _, dir := range []string{migrateDir, backupDir} {
+				if _, err := os.Stat(dir); err == nil {
+					return fmt.Errorf("%s already exists; remove it to migrate again", dir)
+				}
+			}
+
+			dbs := nodeDBs(config, from, from)
+			if len(dbs) == 0 {
+				return fmt.Errorf("no databases found under %s", dataDir)
+			}
+			for _, src := range dbs {
+				dst := nodeDB{Name: src.Name, Dir: migrateDir, Backend: to}
+				cmd.Printf("migrating %s from %s to %s\n", src.Name, from, to)
+				keys, err := migrateDB(cmd, src, dst)
+				if err != nil {
+					return fmt.Errorf("failed to migrate %s: %w", src.Name, err)
+				}
+				cmd.Printf("migrated %s: %d keys, checksum verified\n", src.Name, keys)
+			}
+
+			if err := os.MkdirAll(backupDir, 0755); err != nil {
+				return err
+			}
+			if err := swapDBs(dbs, migrateDir, backupDir); err != nil {
+				return err
+			}
+			if err := os.Remove(migrateDir); err != nil {
+				return err
+			}
+
+			config.DBBackend = string(to)
+			ostcfg.WriteConfigFile(filepath.Join(config.RootDir, "config", "config.toml"), config)
+			cmd.Printf("the original databases are moved to %s and db_backend of config.toml is set to %s\n", backupDir, to)
+			if appDBBackend() != to {
+				cmd.Printf("note: this binary opens application.db with %s; rebuild it with DB_BACKEND=%s to start the node\n",
+					appDBBackend(), to)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(flagFrom, string(metadb.GoLevelDBBackend), "The backend of the current databases")
+	cmd.Flags().String(flagTo, string(metadb.RocksDBBackend), "The backend to migrate the databases to")
+	return cmd
+}
+
+func dbStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Report the key counts and sizes of the databases of the node",
+		Long: `Report the key counts and sizes of application.db and the ostracon databases.
+The keys of application.db are broken down by the module store they belong to.
+
+The node must not be running while this command is used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+			dbs := nodeDBs(config, appDBBackend(), metadb.BackendType(config.DBBackend))
+			for _, d := range dbs {
+				size, err := dirSize(d.Path())
+				if err != nil {
+					return err
+				}
+				db, err := d.Open()
+				if err != nil {
+					return err
+				}
+				stats, err := collectDBStats(db, d.Name == appDBName)
+				db.Close()
+				if err != nil {
+					return err
+				}
+
+				cmd.Printf("%s (%s, %d bytes on disk)\n", d.Name, d.Backend, size)
+				for _, s := range stats {
+					cmd.Printf("  %-20s keys: %-10d key bytes: %-12d value bytes: %d\n", s.Store, s.Keys, s.KeyBytes, s.ValueBytes)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func backendAvailable(backend metadb.BackendType) bool {
+	for _, b := range metadb.AvailableDBBackends() {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateDB copies every key of src to dst and verifies dst against the checksum of src.
+func migrateDB(cmd *cobra.Command, src, dst nodeDB) (int64, error) {
+	srcDB, err := src.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer srcDB.Close()
+	if err := os.MkdirAll(dst.Dir, 0755); err != nil {
+		return 0, err
+	}
+	dstDB, err := dst.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer dstDB.Close()
+
+	it, err := srcDB.Iterator(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	checksum := sha256.New()
+	batch := dstDB.NewBatch()
+	var count int64
+	for ; it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		writeChecksum(checksum, key, value)
+		if err := batch.Set(key, value); err != nil {
+			return count, err
+		}
+		count++
+		if count%migrateBatchSize == 0 {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch.Close()
+			batch = dstDB.NewBatch()
+		}
+		if count%migrateProgressInterval == 0 {
+			cmd.Printf("  %d keys copied\n", count)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return count, err
+	}
+	if err := batch.WriteSync(); err != nil {
+		return count, err
+	}
+	batch.Close()
+
+	dstCount, dstChecksum, err := checksumDB(dstDB)
+	if err != nil {
+		return count, err
+	}
+	if dstCount != count || !bytes.Equal(dstChecksum, checksum.Sum(nil)) {
+		return count, fmt.Errorf("checksum mismatch: copied %d keys with checksum %X, the target has %d keys with checksum %X",
+			count, checksum.Sum(nil), dstCount, dstChecksum)
+	}
+	return count, nil
+}
+
+// swapDBs moves the databases to backupDir and their copies in migrateDir to their places.
+// If a move fails, the moves done before it are undone so that the node keeps its original
+// databases; if they can't all be undone, the error tells which moves are left to undo.
+func swapDBs(dbs []nodeDB, migrateDir, backupDir string) error {
+	type move struct{ from, to string }
+	var done []move
+	for _, db := range dbs {
+		for _, m := range []move{
+			{db.Path(), filepath.Join(backupDir, db.Name+".db")},
+			{filepath.Join(migrateDir, db.Name+".db"), db.Path()},
+		} {
+			err := os.Rename(m.from, m.to)
+			if err == nil {
+				done = append(done, m)
+				continue
+			}
+			err = fmt.Errorf("failed to swap %s: %w", db.Name, err)
+			for i := len(done) - 1; i >= 0; i-- {
+				if undoErr := os.Rename(done[i].to, done[i].from); undoErr != nil {
+					left := make([]string, 0, i+1)
+					for j := i; j >= 0; j-- {
+						left = append(left, fmt.Sprintf("%s to %s", done[j].to, done[j].from))
+					}
+					return fmt.Errorf("%w; failed to undo the swap (%s), move back %s", err, undoErr, strings.Join(left, ", then "))
+				}
+			}
+			return fmt.Errorf("%w; the original databases are restored", err)
+		}
+	}
+	return nil
+}
+
+// checksumDB returns the number of keys and the checksum over all keys and values of the database.
+func checksumDB(db dbm.DB) (int64, []byte, error) {
+	it, err := db.Iterator(nil, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer it.Close()
+
+	checksum := sha256.New()
+	var count int64
+	for ; it.Valid(); it.Next() {
+		writeChecksum(checksum, it.Key(), it.Value())
+		count++
+	}
+	return count, checksum.Sum(nil), it.Error()
+}
+
+// writeChecksum adds a length-prefixed key and value to the checksum.
+func writeChecksum(h hash.Hash, key, value []byte) {
+	var length [binary.MaxVarintLen64]byte
+	h.Write(length[:binary.PutUvarint(length[:], uint64(len(key)))])
+	h.Write(key)
+	h.Write(length[:binary.PutUvarint(length[:], uint64(len(value)))])
+	h.Write(value)
+}
+
+// dbStats are the key counts and sizes of a store in a database.
+type dbStats struct {
+	Store      string
+	Keys       int64
+	KeyBytes   int64
+	ValueBytes int64
+}
+
+// collectDBStats iterates over the database and sums the keys and sizes. If byModule
+// is set, the keys of the module stores of the multistore are reported per store.
+func collectDBStats(db dbm.DB, byModule bool) ([]dbStats, error) {
+	it, err := db.Iterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	stats := map[string]*dbStats{}
+	for ; it.Valid(); it.Next() {
+		store := "all"
+		if byModule {
+			store = moduleStoreOf(it.Key())
+		}
+		s, ok := stats[store]
+		if !ok {
+			s = &dbStats{Store: store}
+			stats[store] = s
+		}
+		s.Keys++
+		s.KeyBytes += int64(len(it.Key()))
+		s.ValueBytes += int64(len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	result := make([]dbStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Store < result[j].Store })
+	return result, nil
+}
+
+// moduleStoreOf returns the name of the module store of a key of the multistore,
+// which prefixes the keys of each mounted store with "s/k:<name>/".
+func moduleStoreOf(key []byte) string {
+	const prefix = "s/k:"
+	if !bytes.HasPrefix(key, []byte(prefix)) {
+		return "(metadata)"
+	}
+	name := key[len(prefix):]
+	if i := bytes.IndexByte(name, '/'); i >= 0 {
+		return string(name[:i])
+	}
+	return "(metadata)"
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/line/tm-db/v2/metadb"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestNodeDB creates a goleveldb database with the given number of keys.
+func newTestNodeDB(t *testing.T, dir, name string, keys int) nodeDB {
+	d := nodeDB{Name: name, Dir: dir, Backend: metadb.GoLevelDBBackend}
+	db, err := d.Open()
+	require.NoError(t, err)
+	for i := 0; i < keys; i++ {
+		require.NoError(t, db.Set([]byte(fmt.Sprintf("key%06d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	require.NoError(t, db.Close())
+	return d
+}
+
+func TestMigrateDB(t *testing.T) {
+	src := newTestNodeDB(t, t.TempDir(), "state", migrateBatchSize+10)
+	dst := nodeDB{Name: "state", Dir: filepath.Join(t.TempDir(), "migrate-goleveldb"), Backend: metadb.GoLevelDBBackend}
+
+	keys, err := migrateDB(&cobra.Command{}, src, dst)
+	require.NoError(t, err)
+	require.Equal(t, int64(migrateBatchSize+10), keys)
+
+	srcDB, err := src.Open()
+	require.NoError(t, err)
+	defer srcDB.Close()
+	dstDB, err := dst.Open()
+	require.NoError(t, err)
+	defer dstDB.Close()
+	srcKeys, srcChecksum, err := checksumDB(srcDB)
+	require.NoError(t, err)
+	dstKeys, dstChecksum, err := checksumDB(dstDB)
+	require.NoError(t, err)
+	require.Equal(t, keys, srcKeys)
+	require.Equal(t, srcKeys, dstKeys)
+	require.Equal(t, srcChecksum, dstChecksum)
+}
+
+func TestChecksumDB(t *testing.T) {
+	checksum := func(pairs ...string) []byte {
+		db := memdb.NewDB()
+		for i := 0; i < len(pairs); i += 2 {
+			require.NoError(t, db.Set([]byte(pairs[i]), []byte(pairs[i+1])))
+		}
+		keys, sum, err := checksumDB(db)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(pairs)/2), keys)
+		return sum
+	}
+
+	require.Equal(t, checksum("a", "1", "b", "2"), checksum("b", "2", "a", "1"))
+	require.NotEqual(t, checksum("a", "1", "b", "2"), checksum("a", "1", "b", "3"))
+	require.NotEqual(t, checksum("a", "1"), checksum("a", "1", "b", "2"))
+	// the keys and values are length-prefixed
+	require.NotEqual(t, checksum("ab", "c"), checksum("a", "bc"))
+	require.Equal(t, checksum(), checksum())
+}
+
+func TestCollectDBStats(t *testing.T) {
+	db := memdb.NewDB()
+	require.NoError(t, db.Set([]byte("s/latest"), []byte{1, 2}))
+	require.NoError(t, db.Set([]byte("s/k:bank/a"), []byte{1}))
+	require.NoError(t, db.Set([]byte("s/k:bank/bb"), []byte{1, 2, 3}))
+	require.NoError(t, db.Set([]byte("s/k:mint/c"), []byte{}))
+
+	stats, err := collectDBStats(db, true)
+	require.NoError(t, err)
+	require.Equal(t, []dbStats{
+		{Store: "(metadata)", Keys: 1, KeyBytes: 8, ValueBytes: 2},
+		{Store: "bank", Keys: 2, KeyBytes: 21, ValueBytes: 4},
+		{Store: "mint", Keys: 1, KeyBytes: 10, ValueBytes: 0},
+	}, stats)
+
+	stats, err = collectDBStats(db, false)
+	require.NoError(t, err)
+	require.Equal(t, []dbStats{{Store: "all", Keys: 4, KeyBytes: 39, ValueBytes: 6}}, stats)
+}
+
+func TestSwapDBs(t *testing.T) {
+	dataDir := t.TempDir()
+	migrateDir := filepath.Join(dataDir, "migrate-goleveldb")
+	backupDir := filepath.Join(dataDir, "backup-goleveldb")
+	require.NoError(t, os.MkdirAll(backupDir, 0755))
+	dbs := []nodeDB{newTestNodeDB(t, dataDir, "blockstore", 1), newTestNodeDB(t, dataDir, "state", 2)}
+	for _, d := range dbs {
+		newTestNodeDB(t, migrateDir, d.Name, 3)
+	}
+
+	keysOf := func(d nodeDB) int64 {
+		db, err := d.Open()
+		require.NoError(t, err)
+		defer db.Close()
+		keys, _, err := checksumDB(db)
+		require.NoError(t, err)
+		return keys
+	}
+
+	// the copy of the second database is missing, so the first one is swapped back
+	require.NoError(t, os.RemoveAll(filepath.Join(migrateDir, "state.db")))
+	err := swapDBs(dbs, migrateDir, backupDir)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to swap state")
+	require.Contains(t, err.Error(), "the original databases are restored")
+	require.Equal(t, int64(1), keysOf(dbs[0]))
+	require.Equal(t, int64(2), keysOf(dbs[1]))
+	require.DirExists(t, filepath.Join(migrateDir, "blockstore.db"))
+	require.NoDirExists(t, filepath.Join(backupDir, "blockstore.db"))
+	require.NoDirExists(t, filepath.Join(backupDir, "state.db"))
+
+	newTestNodeDB(t, migrateDir, "state", 3)
+	require.NoError(t, swapDBs(dbs, migrateDir, backupDir))
+	for _, d := range dbs {
+		require.Equal(t, int64(3), keysOf(d))
+		require.NoDirExists(t, filepath.Join(migrateDir, d.Name+".db"))
+	}
+	require.Equal(t, int64(1), keysOf(nodeDB{Name: "blockstore", Dir: backupDir, Backend: metadb.GoLevelDBBackend}))
+	require.Equal(t, int64(2), keysOf(nodeDB{Name: "state", Dir: backupDir, Backend: metadb.GoLevelDBBackend}))
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client/debug"
+	"github.com/line/lbm-sdk/codec/legacy"
+	"github.com/line/lbm-sdk/types/bech32"
+	"github.com/line/lbm-sdk/version"
+
+	lfbtypes "github.com/line/lfb/types"
+)
+
+// debugCmd returns the debug command of the SDK extended with LFB specific tools.
+func debugCmd() *cobra.Command {
+	cmd := debug.Cmd()
+	for _, c := range cmd.Commands() {
+		if c.Name() == "addr" {
+			c.AddCommand(addrConvertCmd())
+		}
+	}
+	cmd.AddCommand(
+		storeCmd(),
+		bisectCmd(),
+		replayCmd(),
+		traceCmd(),
+	)
+	return cmd
+}
+
+func addrConvertCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "convert [address]",
+		Short: "Explain an address or public key and convert it to every form on the link and tlink networks",
+		Long: fmt.Sprintf(`Explain which form a bech32 address or public key is, and convert it to the
+account, validator operator and consensus forms of the mainnet and testnet
+prefixes as well as of the network of the current configuration.
+A hex string is taken as raw address bytes.
+
+Example:
+$ %s debug addr convert tlink1s6fpzuw6nk8x4wtekl6zmpqdgq7mfq8xnr472s
+			`, version.AppName),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			current, err := networkFromFlags()
+			if err != nil {
+				return err
+			}
+
+			input := strings.TrimSpace(args[0])
+			var parsed lfbtypes.ParsedAddress
+			if bz, err := hex.DecodeString(input); err == nil {
+				parsed = lfbtypes.ParsedAddress{Kind: lfbtypes.KindAccAddr, Bytes: bz}
+				cmd.Printf("Input form: raw address bytes (hex)\n")
+			} else {
+				if parsed, err = lfbtypes.ParseAddress(input, current); err != nil {
+					return err
+				}
+				cmd.Printf("Input form: %s\n", parsed)
+			}
+
+			networks := []lfbtypes.Network{lfbtypes.Mainnet, lfbtypes.Testnet}
+			if current.Bech32Prefix != lfbtypes.Mainnet.Bech32Prefix && current.Bech32Prefix != lfbtypes.Testnet.Bech32Prefix {
+				networks = append(networks, current)
+			}
+
+			addrBytes := parsed.Bytes
+			if parsed.Kind.IsPubKey() {
+				pubKey, err := legacy.PubKeyFromBytes(parsed.Bytes)
+				if err != nil {
+					return fmt.Errorf("failed to decode public key: %w", err)
+				}
+				addrBytes = pubKey.Address()
+				cmd.Printf("Public key (hex): %X\n", pubKey.Bytes())
+			}
+			cmd.Printf("Address (hex): %X\n", addrBytes)
+
+			for _, network := range networks {
+				cmd.Printf("\n%s (%s):\n", network.Name, network.Bech32Prefix)
+				kinds := []lfbtypes.AddressKind{lfbtypes.KindAccAddr, lfbtypes.KindValAddr, lfbtypes.KindConsAddr}
+				if parsed.Kind.IsPubKey() {
+					kinds = append([]lfbtypes.AddressKind{lfbtypes.KindAccPub, lfbtypes.KindValPub, lfbtypes.KindConsPub}, kinds...)
+				}
+				for _, kind := range kinds {
+					bz := addrBytes
+					if kind.IsPubKey() {
+						bz = parsed.Bytes
+					}
+					converted, err := bech32.ConvertAndEncode(network.PrefixOf(kind), bz)
+					if err != nil {
+						return err
+					}
+					cmd.Printf("  %-30s %s\n", strings.Title(kind.String())+":", converted) // nolint: staticcheck
+				}
+			}
+
+			if !parsed.Kind.IsPubKey() {
+				cmd.Println("\nNote: the consensus address of a validator is derived from its consensus public key,")
+				cmd.Println("not from its operator address. Use the consensus public key to get the real one.")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	ostcstore "github.com/line/ostracon/store"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/server"
+	storeiavl "github.com/line/lbm-sdk/store/iavl"
+	"github.com/line/lbm-sdk/store/rootmulti"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/app"
+)
+
+const (
+	flagFromHeight = "from-height"
+	flagToHeight   = "to-height"
+)
+
+func bisectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bisect",
+		Short: "Find the first height and the stores where the app hashes of two nodes diverge",
+		Long: `Compare the application databases of this node and the node at --other-home, bisect
+the heights to find the first height whose app hashes differ, compare the commit hashes
+of every module store at that height, and diff the keys of the divergent stores with the
+store decoders of the modules. The result is printed as a markdown report.
+
+The app hashes must be equal at --from-height and differ at --to-height, which default
+to the earliest and the latest heights both databases have. Pruned heights are skipped.
+
+The nodes must not be running while this command is used.`,
+		Example: fmt.Sprintf("$ %s debug bisect --other-home /path/to/other/node > report.md", version.AppName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			otherHome, _ := cmd.Flags().GetString(flagOtherHome)
+			from, _ := cmd.Flags().GetInt64(flagFromHeight)
+			to, _ := cmd.Flags().GetInt64(flagToHeight)
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			home := serverCtx.Config.RootDir
+			if otherHome == "" || filepath.Clean(otherHome) == filepath.Clean(home) {
+				return fmt.Errorf("--%s must be the home directory of another node", flagOtherHome)
+			}
+
+			linkApp := newOfflineApp(home)
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			otherDB, err := openAppDB(otherHome)
+			if err != nil {
+				return err
+			}
+			defer otherDB.Close()
+
+			b := &bisector{linkApp: linkApp, a: db, b: otherDB}
+			if from == 0 || to == 0 {
+				earliest, latest, err := b.commonHeights()
+				if err != nil {
+					return err
+				}
+				if from == 0 {
+					from = earliest
+				}
+				if to == 0 {
+					to = latest
+				}
+			}
+			lastEqual, height, err := b.bisect(from, to)
+			if err != nil {
+				return err
+			}
+
+			blockStoreDB, err := openOstraconDB(serverCtx.Config, "blockstore")
+			if err != nil {
+				return err
+			}
+			defer blockStoreDB.Close()
+			return b.report(cmd.OutOrStdout(), home, otherHome, lastEqual, height, ostcstore.NewBlockStore(blockStoreDB))
+		},
+	}
+	cmd.Flags().String(flagOtherHome, "", "The home directory of the other node")
+	cmd.Flags().Int64(flagFromHeight, 0, "A height where the app hashes are equal; defaults to the earliest common height")
+	cmd.Flags().Int64(flagToHeight, 0, "A height where the app hashes differ; defaults to the latest common height")
+	return cmd
+}
+
+// bisector compares the multistores of two application databases.
+type bisector struct {
+	linkApp *app.LinkApp
+	a, b    dbm.DB
+}
+
+// commonHeights returns the earliest and the latest heights both databases keep.
+func (bs *bisector) commonHeights() (int64, int64, error) {
+	var earliest, latest int64
+	for i, db := range []dbm.DB{bs.a, bs.b} {
+		cms, err := loadMultiStore(db, bs.linkApp.GetKeys(), 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		last := cms.LastCommitID().Version
+		first := int64(1)
+		for first < last && !bs.versionExists(cms, first) {
+			first++
+		}
+		if i == 0 || first > earliest {
+			earliest = first
+		}
+		if i == 0 || last < latest {
+			latest = last
+		}
+	}
+	if earliest > latest {
+		return 0, 0, fmt.Errorf("the databases have no heights in common")
+	}
+	return earliest, latest, nil
+}
+
+func (bs *bisector) versionExists(cms *rootmulti.Store, height int64) bool {
+	for _, key := range bs.linkApp.GetKeys() {
+		if !cms.GetCommitKVStore(key).(*storeiavl.Store).VersionExists(height) {
+			return false
+		}
+	}
+	return true
+}
+
+// load loads both multistores at the height.
+func (bs *bisector) load(height int64) (*rootmulti.Store, *rootmulti.Store, error) {
+	return loadMultiStorePair(bs.linkApp, bs.a, height, bs.b, height)
+}
+
+// equal returns whether the app hashes are equal at the height. It returns an error
+// if the height is not available in one of the databases.
+func (bs *bisector) equal(height int64) (bool, error) {
+	a, b, err := bs.load(height)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(a.LastCommitID().Hash, b.LastCommitID().Hash), nil
+}
+
+// bisect returns the last height whose app hashes are equal and the first height after it
+// whose app hashes differ. The heights in between have been pruned.
+func (bs *bisector) bisect(from, to int64) (int64, int64, error) {
+	if eq, err := bs.equal(from); err != nil {
+		return 0, 0, fmt.Errorf("height %d: %w", from, err)
+	} else if !eq {
+		return 0, 0, fmt.Errorf("the app hashes already differ at height %d; use a lower --%s", from, flagFromHeight)
+	}
+	if eq, err := bs.equal(to); err != nil {
+		return 0, 0, fmt.Errorf("height %d: %w", to, err)
+	} else if eq {
+		return 0, 0, fmt.Errorf("the app hashes are equal at height %d; use a higher --%s", to, flagToHeight)
+	}
+
+	// invariant: equal at from, different at to
+	for to-from > 1 {
+		mid, eq, err := bs.probe(from, to)
+		if err != nil {
+			return 0, 0, err
+		}
+		if mid == 0 {
+			// every height in between has been pruned
+			break
+		}
+		if eq {
+			from = mid
+		} else {
+			to = mid
+		}
+	}
+	return from, to, nil
+}
+
+// probe compares the app hashes at the available height closest to the middle of from and to.
+func (bs *bisector) probe(from, to int64) (int64, bool, error) {
+	mid := from + (to-from)/2
+	for offset := int64(0); mid-offset > from || mid+offset < to; offset++ {
+		for _, h := range []int64{mid - offset, mid + offset} {
+			if h <= from || h >= to {
+				continue
+			}
+			eq, err := bs.equal(h)
+			if err == nil {
+				return h, eq, nil
+			}
+		}
+	}
+	return 0, false, nil
+}
+
+// report writes the incident report of the divergence at the height, whose app hashes were
+// last equal at lastEqual.
+func (bs *bisector) report(w io.Writer, home, otherHome string, lastEqual, height int64, blockStore *ostcstore.BlockStore) error {
+	a, b, err := bs.load(height)
+	if err != nil {
+		return err
+	}
+	prevA, _, err := bs.load(lastEqual)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "# App hash divergence at height %d\n\n", height)
+	fmt.Fprintf(w, "- node A: `%s`\n- node B: `%s`\n", home, otherHome)
+	fmt.Fprintf(w, "- last equal height: %d (app hash `%X`)\n", lastEqual, prevA.LastCommitID().Hash)
+	if lastEqual < height-1 {
+		fmt.Fprintf(w, "- heights %d to %d have been pruned; the divergence is at or before height %d\n", lastEqual+1, height-1, height)
+	}
+	fmt.Fprintf(w, "- app hash of node A at height %d: `%X`\n", height, a.LastCommitID().Hash)
+	fmt.Fprintf(w, "- app hash of node B at height %d: `%X`\n", height, b.LastCommitID().Hash)
+
+	if block := blockStore.LoadBlock(height); block != nil {
+		fmt.Fprintf(w, "\n## Block %d (node A)\n\n", height)
+		fmt.Fprintf(w, "- hash: `%X`\n- time: %s\n- proposer: `%s`\n- txs: %d\n",
+			block.Hash(), block.Time, block.ProposerAddress, len(block.Txs))
+		for _, tx := range block.Txs {
+			fmt.Fprintf(w, "  - `%X`\n", tx.Hash())
+		}
+	}
+
+	fmt.Fprintf(w, "\n## Store commit hashes at height %d\n\n", height)
+	fmt.Fprintf(w, "| store | node A | node B |\n|---|---|---|\n")
+	for _, name := range storeNames(bs.linkApp) {
+		key := bs.linkApp.GetKey(name)
+		hashA, hashB := storeCommitHash(a, key), storeCommitHash(b, key)
+		mark := ""
+		if !bytes.Equal(hashA, hashB) {
+			mark = " **differs**"
+		}
+		fmt.Fprintf(w, "| %s%s | `%X` | `%X` |\n", name, mark, hashA, hashB)
+	}
+
+	for _, name := range divergentStores(bs.linkApp, a, b) {
+		fmt.Fprintf(w, "\n## Diff of store %s at height %d\n\n```\n", name, height)
+		for _, d := range diffStores(bs.linkApp, name, a, b, nil) {
+			fmt.Fprintln(w, d)
+		}
+		fmt.Fprintf(w, "```\n")
+	}
+	return nil
+}
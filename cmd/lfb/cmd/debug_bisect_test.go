@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	ostcstore "github.com/line/ostracon/store"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/simapp"
+	sdk "github.com/line/lbm-sdk/types"
+	minttypes "github.com/line/lbm-sdk/x/mint/types"
+
+	"github.com/line/lfb/app"
+)
+
+// newDivergingChain builds a chain of the given length keeping every height, whose minter
+// inflation is changed from the diverge height if it is positive.
+func newDivergingChain(t *testing.T, length, diverge int64) dbm.DB {
+	db := memdb.NewDB()
+	encodingConfig := app.MakeEncodingConfig()
+	linkApp := app.NewLinkApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, t.TempDir(), 0, encodingConfig, simapp.EmptyAppOptions{}, nil)
+	genesis, err := json.Marshal(app.ModuleBasics.DefaultGenesis(encodingConfig.Marshaler))
+	require.NoError(t, err)
+	linkApp.InitChain(abci.RequestInitChain{ChainId: "bisect-test", AppStateBytes: genesis})
+	for height := int64(1); height <= length; height++ {
+		header := ocproto.Header{ChainID: "bisect-test", Height: height}
+		linkApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+		if height == diverge {
+			ctx := linkApp.BaseApp.NewContext(false, header)
+			linkApp.MintKeeper.SetMinter(ctx, minttypes.NewMinter(sdk.NewDecWithPrec(42, 2), sdk.ZeroDec()))
+		}
+		linkApp.EndBlock(abci.RequestEndBlock{Height: height})
+		linkApp.Commit()
+	}
+	return db
+}
+
+func TestBisect(t *testing.T) {
+	const latest, diverge = 20, 13
+	b := &bisector{
+		linkApp: newOfflineApp(t.TempDir()),
+		a:       newDivergingChain(t, latest, 0),
+		b:       newDivergingChain(t, latest, diverge),
+	}
+	from, to, err := b.commonHeights()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), from)
+	require.Equal(t, int64(latest), to)
+
+	lastEqual, height, err := b.bisect(from, to)
+	require.NoError(t, err)
+	require.Equal(t, int64(diverge-1), lastEqual)
+	require.Equal(t, int64(diverge), height)
+
+	a, other, err := b.load(height)
+	require.NoError(t, err)
+	require.Equal(t, []string{minttypes.StoreKey}, divergentStores(b.linkApp, a, other))
+
+	var report bytes.Buffer
+	require.NoError(t, b.report(&report, "a", "b", lastEqual, height, ostcstore.NewBlockStore(memdb.NewDB())))
+	require.Contains(t, report.String(), "# App hash divergence at height 13\n")
+	require.Contains(t, report.String(), "- last equal height: 12 (app hash")
+	require.Contains(t, report.String(), "| mint **differs** |")
+	require.Contains(t, report.String(), "## Diff of store mint at height 13")
+
+	// the bounds must be equal and differ
+	_, _, err = b.bisect(diverge, latest)
+	require.Error(t, err)
+	_, _, err = b.bisect(from, diverge-1)
+	require.Error(t, err)
+}
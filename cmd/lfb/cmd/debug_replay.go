@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	tmstate "github.com/line/ostracon/proto/ostracon/state"
+	sm "github.com/line/ostracon/state"
+	ostcstore "github.com/line/ostracon/store"
+	octypes "github.com/line/ostracon/types"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/baseapp"
+	"github.com/line/lbm-sdk/server"
+	servertypes "github.com/line/lbm-sdk/server/types"
+	storetypes "github.com/line/lbm-sdk/store/types"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/app"
+	"github.com/line/lfb/app/indexer"
+	"github.com/line/lfb/app/streaming"
+	"github.com/line/lfb/app/tracing"
+)
+
+const flagTraceStore = "trace-store"
+
+// replayCmd returns the command to re-execute stored blocks against the application state offline.
+func replayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Re-execute stored blocks against the application state and compare the results",
+		Long: `Load the application state at --from, re-execute the blocks after it up to --to from
+the block store through BeginBlock, DeliverTx, EndBlock and Commit, and compare the
+results with the recorded ones: the deterministic fields of every tx result (code, data,
+gas wanted and gas used) and the app hash after every block.
+
+The replay runs on an in-memory overlay of the application database, so the state of
+the node is not modified. With --trace-store, every read and write of the multistore is
+written to the file as JSON, e.g. for 'debug trace analyze'.
+
+The replay stops at the first app hash mismatch and prints the diff of the stores that
+diverge.
+
+The node must not be running while this command is used.`,
+		Example: fmt.Sprintf("$ %s debug replay --from 100 --to 110 --trace-store trace.json", version.AppName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			from, _ := cmd.Flags().GetInt64(flagFrom)
+			to, _ := cmd.Flags().GetInt64(flagTo)
+			tracePath, _ := cmd.Flags().GetString(flagTraceStore)
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+			home := config.RootDir
+
+			blockStoreDB, err := openOstraconDB(config, "blockstore")
+			if err != nil {
+				return err
+			}
+			defer blockStoreDB.Close()
+			stateDB, err := openOstraconDB(config, "state")
+			if err != nil {
+				return err
+			}
+			defer stateDB.Close()
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			blockStore := ostcstore.NewBlockStore(blockStoreDB)
+			stateStore := sm.NewStore(stateDB)
+			state, err := stateStore.Load()
+			if err != nil {
+				return err
+			}
+			if state.IsEmpty() {
+				return fmt.Errorf("no ostracon state found")
+			}
+			if to == 0 {
+				to = state.LastBlockHeight
+			}
+			if from < 1 || from >= to {
+				return fmt.Errorf("--%s must be positive and below --%s", flagFrom, flagTo)
+			}
+			if to > state.LastBlockHeight {
+				return fmt.Errorf("--%s %d is above the state height %d", flagTo, to, state.LastBlockHeight)
+			}
+			if from+1 < blockStore.Base() {
+				return fmt.Errorf("block %d has been pruned; the blocks are available from height %d", from+1, blockStore.Base())
+			}
+
+			// hide the versions after --from from the stores, in the overlay only
+			keys := newOfflineApp(home).GetKeys()
+			overlay := newOverlayDB(db)
+			if err := rollbackMultiStore(overlay, keys, from); err != nil {
+				return fmt.Errorf("failed to load the application state at height %d: %w", from, err)
+			}
+
+			linkApp := app.NewLinkApp(log.NewNopLogger(), overlay, nil, false, map[int64]bool{}, home, 0,
+				app.MakeEncodingConfig(), replayAppOptions{serverCtx.Viper}, nil, baseapp.SetPruning(storetypes.PruneNothing))
+			if err := linkApp.LoadHeight(from); err != nil {
+				return fmt.Errorf("failed to load the application state at height %d: %w", from, err)
+			}
+			if tracePath != "" {
+				traceFile, err := os.OpenFile(tracePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+				if err != nil {
+					return err
+				}
+				defer traceFile.Close()
+				linkApp.SetCommitMultiStoreTracer(traceFile)
+			}
+
+			r := &replayer{
+				linkApp:    linkApp,
+				db:         db,
+				overlay:    overlay,
+				blockStore: blockStore,
+				stateStore: stateStore,
+				state:      state,
+				out:        cmd.OutOrStdout(),
+			}
+			mismatches, err := r.replay(from+1, to)
+			if err != nil {
+				return err
+			}
+			if mismatches > 0 {
+				return fmt.Errorf("%d mismatches found", mismatches)
+			}
+			cmd.Printf("replayed blocks %d to %d; the results and app hashes match\n", from+1, to)
+			return nil
+		},
+	}
+	cmd.Flags().Int64(flagFrom, 0, "The height of the application state to start from")
+	cmd.Flags().Int64(flagTo, 0, "The last height to re-execute; defaults to the state height")
+	cmd.Flags().String(flagTraceStore, "", "Write the KV store operations of the replay to the file as JSON")
+	cmd.MarkFlagRequired(flagFrom) // nolint: errcheck
+	return cmd
+}
+
+// replayAppOptions are the app options of the replayed app. Only the wasm config of the node
+// is kept, and streaming, indexing, tracing and telemetry are off, as they would write the
+// re-executed blocks outside the state overlay: to the streaming files and the indexer
+// database of the node, and to its trace collector.
+type replayAppOptions struct {
+	servertypes.AppOptions
+}
+
+func (o replayAppOptions) Get(key string) interface{} {
+	switch key {
+	case streaming.FlagEnable, indexer.FlagEnable, tracing.FlagEnable, "telemetry.enabled":
+		return false
+	}
+	if strings.HasPrefix(key, "wasm.") {
+		return o.AppOptions.Get(key)
+	}
+	return nil
+}
+
+// replayer re-executes the stored blocks on an app loaded from an overlay of the application database.
+type replayer struct {
+	linkApp    *app.LinkApp
+	db         dbm.DB
+	overlay    *overlayDB
+	blockStore *ostcstore.BlockStore
+	stateStore sm.Store
+	state      sm.State
+	out        io.Writer
+}
+
+// replay executes the blocks from..to and returns the number of mismatches found.
+func (r *replayer) replay(from, to int64) (int, error) {
+	mismatches := 0
+	for height := from; height <= to; height++ {
+		block := r.blockStore.LoadBlock(height)
+		if block == nil {
+			return mismatches, fmt.Errorf("block %d not found", height)
+		}
+		responses, appHash, err := r.execute(block)
+		if err != nil {
+			return mismatches, fmt.Errorf("block %d: %w", height, err)
+		}
+
+		expectedResults, expectedAppHash := r.expected(height)
+		if resultsHash := octypes.NewResults(responses.DeliverTxs).Hash(); !bytes.Equal(resultsHash, expectedResults) {
+			mismatches++
+			fmt.Fprintf(r.out, "height %d: results hash %X, expected %X\n", height, resultsHash, expectedResults)
+			r.reportResults(height, responses)
+		}
+		if !bytes.Equal(appHash, expectedAppHash) {
+			mismatches++
+			fmt.Fprintf(r.out, "height %d: app hash %X, expected %X\n", height, appHash, expectedAppHash)
+			r.reportStores(height)
+			return mismatches, nil
+		}
+		fmt.Fprintf(r.out, "height %d: %d txs, app hash %X\n", height, len(block.Txs), appHash)
+	}
+	return mismatches, nil
+}
+
+// execute runs the block through the app the same way ostracon does and commits it.
+func (r *replayer) execute(block *octypes.Block) (*tmstate.ABCIResponses, []byte, error) {
+	commitInfo, err := r.lastCommitInfo(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	byzVals := make([]abci.Evidence, 0)
+	for _, evidence := range block.Evidence.Evidence {
+		byzVals = append(byzVals, evidence.ABCI()...)
+	}
+
+	responses := &tmstate.ABCIResponses{DeliverTxs: make([]*abci.ResponseDeliverTx, len(block.Txs))}
+	beginBlock := r.linkApp.BeginBlock(abci.RequestBeginBlock{
+		Hash:                block.Hash(),
+		Header:              *block.Header.ToProto(),
+		LastCommitInfo:      commitInfo,
+		ByzantineValidators: byzVals,
+	})
+	responses.BeginBlock = &beginBlock
+	for i, tx := range block.Txs {
+		res := r.linkApp.DeliverTx(abci.RequestDeliverTx{Tx: tx})
+		responses.DeliverTxs[i] = &res
+	}
+	endBlock := r.linkApp.EndBlock(abci.RequestEndBlock{Height: block.Height})
+	responses.EndBlock = &endBlock
+	commit := r.linkApp.Commit()
+	return responses, commit.Data, nil
+}
+
+// lastCommitInfo returns the votes of the last commit of the block with the voters that cast them.
+func (r *replayer) lastCommitInfo(block *octypes.Block) (abci.LastCommitInfo, error) {
+	votes := make([]abci.VoteInfo, block.LastCommit.Size())
+	if block.Height > r.state.InitialHeight {
+		voters, err := r.stateStore.LoadVoters(block.Height-1, r.state.VoterParams)
+		if err != nil {
+			return abci.LastCommitInfo{}, err
+		}
+		if voters.Size() != block.LastCommit.Size() {
+			return abci.LastCommitInfo{}, fmt.Errorf("the last commit has %d signatures, but there are %d voters",
+				block.LastCommit.Size(), voters.Size())
+		}
+		for i, voter := range voters.Voters {
+			votes[i] = abci.VoteInfo{
+				Validator:       octypes.OC2PB.Validator(voter),
+				SignedLastBlock: !block.LastCommit.Signatures[i].Absent(),
+			}
+		}
+	}
+	return abci.LastCommitInfo{Round: block.LastCommit.Round, Votes: votes}, nil
+}
+
+// expected returns the recorded results hash and app hash of the block at the height,
+// which are found in the header of the next block, or in the state for the last block.
+func (r *replayer) expected(height int64) (resultsHash, appHash []byte) {
+	if meta := r.blockStore.LoadBlockMeta(height + 1); meta != nil {
+		return meta.Header.LastResultsHash, meta.Header.AppHash
+	}
+	return r.state.LastResultsHash, r.state.AppHash
+}
+
+// reportResults prints the tx results that differ from the recorded ones, if they are still kept.
+func (r *replayer) reportResults(height int64, responses *tmstate.ABCIResponses) {
+	recorded, err := r.stateStore.LoadABCIResponses(height)
+	if err != nil {
+		fmt.Fprintf(r.out, "  the recorded tx results are not available: %s\n", err)
+		return
+	}
+	for i, res := range responses.DeliverTxs {
+		if i >= len(recorded.DeliverTxs) {
+			break
+		}
+		rec := recorded.DeliverTxs[i]
+		if res.Code == rec.Code && bytes.Equal(res.Data, rec.Data) && res.GasWanted == rec.GasWanted && res.GasUsed == rec.GasUsed {
+			continue
+		}
+		fmt.Fprintf(r.out, "  tx %d:\n", i)
+		fmt.Fprintf(r.out, "    replayed: code %d, gas %d/%d, data %X, log %s\n", res.Code, res.GasUsed, res.GasWanted, res.Data, res.Log)
+		fmt.Fprintf(r.out, "    recorded: code %d, gas %d/%d, data %X, log %s\n", rec.Code, rec.GasUsed, rec.GasWanted, rec.Data, rec.Log)
+	}
+}
+
+// reportStores prints the stores whose replayed commit hashes differ from the recorded ones.
+func (r *replayer) reportStores(height int64) {
+	recorded, replayed, err := loadMultiStorePair(r.linkApp, r.db, height, r.overlay, height)
+	if err != nil {
+		fmt.Fprintf(r.out, "  the recorded state at height %d is not available: %s\n", height, err)
+		return
+	}
+	for _, name := range divergentStores(r.linkApp, recorded, replayed) {
+		fmt.Fprintf(r.out, "  store %s differs (- recorded, + replayed):\n", name)
+		for _, d := range diffStores(r.linkApp, name, recorded, replayed, nil) {
+			fmt.Fprintf(r.out, "    %s\n", strings.ReplaceAll(d, "\n", "\n    "))
+		}
+	}
+}
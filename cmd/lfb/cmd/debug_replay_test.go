@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/line/ostracon/libs/log"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lfb/app"
+	"github.com/line/lfb/app/indexer"
+	"github.com/line/lfb/app/streaming"
+	"github.com/line/lfb/app/tracing"
+)
+
+func TestReplayAppOptions(t *testing.T) {
+	home := t.TempDir()
+	// the app.toml of a node with everything enabled
+	v := viper.New()
+	v.Set(streaming.FlagEnable, true)
+	v.Set(streaming.FlagDir, filepath.Join(home, "streaming"))
+	v.Set(indexer.FlagEnable, true)
+	v.Set(indexer.FlagPath, filepath.Join(home, "indexer.db"))
+	v.Set(tracing.FlagEnable, true)
+	v.Set(tracing.FlagExporter, "file")
+	v.Set(tracing.FlagFile, filepath.Join(home, "traces.json"))
+	v.Set("telemetry.enabled", true)
+	v.Set("wasm.query_gas_limit", 1234)
+
+	opts := replayAppOptions{v}
+	for _, key := range []string{streaming.FlagEnable, indexer.FlagEnable, tracing.FlagEnable, "telemetry.enabled"} {
+		require.Equal(t, false, opts.Get(key), key)
+	}
+	require.Nil(t, opts.Get(streaming.FlagDir))
+	require.Nil(t, opts.Get(tracing.FlagFile))
+	require.Equal(t, 1234, opts.Get("wasm.query_gas_limit"))
+
+	// the replayed app writes nothing outside its database
+	app.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, false, map[int64]bool{}, home, 0,
+		app.MakeEncodingConfig(), opts, nil)
+	for _, name := range []string{"streaming", "indexer.db", "traces.json"} {
+		_, err := os.Stat(filepath.Join(home, name))
+		require.True(t, os.IsNotExist(err), name)
+	}
+}
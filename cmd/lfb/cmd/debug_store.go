@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	dbm "github.com/line/tm-db/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/server"
+	storeiavl "github.com/line/lbm-sdk/store/iavl"
+	"github.com/line/lbm-sdk/store/rootmulti"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/kv"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/app"
+)
+
+const (
+	flagModule      = "module"
+	flagPrefix      = "prefix"
+	flagLimit       = "limit"
+	flagOtherHeight = "other-height"
+	flagOtherHome   = "other-home"
+)
+
+// storeCmd returns the debug commands to inspect the module stores of the application database.
+func storeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Inspect the module stores of the application database offline",
+		RunE:  client.ValidateCmd,
+	}
+	cmd.AddCommand(
+		storeDumpCmd(),
+		storeDiffCmd(),
+	)
+	cmd.PersistentFlags().String(flagModule, "", "The store key of the module, e.g. bank or acc")
+	cmd.PersistentFlags().String(flagPrefix, "", "Only the keys starting with the hex encoded prefix")
+	cmd.PersistentFlags().Int64(flags.FlagHeight, 0, "The height of the state; 0 is the latest height")
+	return cmd
+}
+
+func storeDumpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print the key/value pairs of a module store decoded with the store decoder of the module",
+		Long: `Print the key/value pairs of a module store as JSON, one pair per line. The values are
+decoded with the store decoder the module registers for simulation, if there is any.
+
+The node must not be running while this command is used.`,
+		Example: fmt.Sprintf("$ %s debug store dump --module bank --prefix 02 --height 100", version.AppName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			prefix, height, err := storeQueryFlags(cmd)
+			if err != nil {
+				return err
+			}
+			limit, _ := cmd.Flags().GetInt64(flagLimit)
+
+			home := server.GetServerContextFromCmd(cmd).Config.RootDir
+			linkApp := newOfflineApp(home)
+			name, key, err := moduleStoreKey(cmd, linkApp)
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				return fmt.Errorf("--%s is required", flagModule)
+			}
+
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			cms, err := loadMultiStore(db, linkApp.GetKeys(), height)
+			if err != nil {
+				return err
+			}
+
+			it := sdk.KVStorePrefixIterator(cms.GetKVStore(key), prefix)
+			defer it.Close()
+			var count int64
+			for ; it.Valid() && (limit == 0 || count < limit); it.Next() {
+				bz, err := json.Marshal(newStorePair(linkApp, name, kv.Pair{Key: it.Key(), Value: it.Value()}))
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(bz))
+				count++
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int64(flagLimit, 0, "The maximum number of pairs to print; 0 prints all")
+	return cmd
+}
+
+func storeDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the module stores at two heights or of two nodes",
+		Long: `Compare the module stores of the application database at --height with the stores at
+--other-height, or with the stores of the node at --other-home at the same height.
+The commit hashes of all the stores are compared first, then the keys of the stores
+which differ, or of the store given by --module, are listed with the decoded values.
+
+The nodes must not be running while this command is used.`,
+		Example: fmt.Sprintf(`$ %[1]s debug store diff --height 100 --other-height 101
+$ %[1]s debug store diff --height 100 --other-home /path/to/other/node --module staking`, version.AppName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			prefix, height, err := storeQueryFlags(cmd)
+			if err != nil {
+				return err
+			}
+			otherHeight, _ := cmd.Flags().GetInt64(flagOtherHeight)
+			otherHome, _ := cmd.Flags().GetString(flagOtherHome)
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			home := serverCtx.Config.RootDir
+			if otherHome != "" && filepath.Clean(otherHome) == filepath.Clean(home) {
+				otherHome = ""
+			}
+			if otherHome == "" && otherHeight == 0 {
+				return fmt.Errorf("either --%s or --%s is required", flagOtherHeight, flagOtherHome)
+			}
+			if otherHeight == 0 {
+				otherHeight = height
+			}
+
+			linkApp := newOfflineApp(home)
+			name, _, err := moduleStoreKey(cmd, linkApp)
+			if err != nil {
+				return err
+			}
+
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			otherDB := db
+			if otherHome != "" {
+				if otherDB, err = openAppDB(otherHome); err != nil {
+					return err
+				}
+				defer otherDB.Close()
+			}
+
+			a, b, err := loadMultiStorePair(linkApp, db, height, otherDB, otherHeight)
+			if err != nil {
+				return err
+			}
+			names := []string{name}
+			if name == "" {
+				names = divergentStores(linkApp, a, b)
+			}
+			if len(names) == 0 {
+				cmd.Println("all the stores are equal")
+				return nil
+			}
+			for _, name := range names {
+				cmd.Printf("store %s:\n", name)
+				for _, d := range diffStores(linkApp, name, a, b, prefix) {
+					cmd.Println(d)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int64(flagOtherHeight, 0, "The height to compare with; defaults to --height")
+	cmd.Flags().String(flagOtherHome, "", "The home directory of the other node to compare with")
+	return cmd
+}
+
+func storeQueryFlags(cmd *cobra.Command) (prefix []byte, height int64, err error) {
+	prefixStr, _ := cmd.Flags().GetString(flagPrefix)
+	if prefix, err = hex.DecodeString(prefixStr); err != nil {
+		return nil, 0, fmt.Errorf("invalid --%s: %w", flagPrefix, err)
+	}
+	height, _ = cmd.Flags().GetInt64(flags.FlagHeight)
+	return prefix, height, nil
+}
+
+// moduleStoreKey returns the store key given by --module, if any.
+func moduleStoreKey(cmd *cobra.Command, linkApp *app.LinkApp) (string, *sdk.KVStoreKey, error) {
+	name, _ := cmd.Flags().GetString(flagModule)
+	if name == "" {
+		return "", nil, nil
+	}
+	key := linkApp.GetKey(name)
+	if key == nil {
+		return "", nil, fmt.Errorf("unknown store %q; the stores are %s", name, strings.Join(storeNames(linkApp), ", "))
+	}
+	return name, key, nil
+}
+
+// storeNames returns the sorted names of the persistent stores of the app.
+func storeNames(linkApp *app.LinkApp) []string {
+	names := make([]string, 0, len(linkApp.GetKeys()))
+	for name := range linkApp.GetKeys() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadMultiStorePair loads the stores of two application databases, which may be the same.
+func loadMultiStorePair(linkApp *app.LinkApp, db dbm.DB, height int64, otherDB dbm.DB, otherHeight int64) (*rootmulti.Store, *rootmulti.Store, error) {
+	a, err := loadMultiStore(db, linkApp.GetKeys(), height)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := loadMultiStore(otherDB, linkApp.GetKeys(), otherHeight)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+// storeCommitHash returns the commit hash of a store at the loaded version.
+func storeCommitHash(cms *rootmulti.Store, key *sdk.KVStoreKey) []byte {
+	return cms.GetCommitKVStore(key).(*storeiavl.Store).LastCommitID().Hash
+}
+
+// divergentStores returns the sorted names of the stores whose commit hashes differ.
+func divergentStores(linkApp *app.LinkApp, a, b *rootmulti.Store) []string {
+	var names []string
+	for _, name := range storeNames(linkApp) {
+		key := linkApp.GetKey(name)
+		if !bytes.Equal(storeCommitHash(a, key), storeCommitHash(b, key)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// storePair is a key/value pair of a module store with its decoded value.
+type storePair struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Decoded string `json:"decoded,omitempty"`
+}
+
+func newStorePair(linkApp *app.LinkApp, storeName string, pair kv.Pair) storePair {
+	return storePair{
+		Key:     fmt.Sprintf("%X", pair.Key),
+		Value:   fmt.Sprintf("%X", pair.Value),
+		Decoded: decodePair(linkApp, storeName, pair),
+	}
+}
+
+// decodePair decodes the value of a pair with the store decoder of the module. The
+// decoders only compare two pairs, rendering the first one and then the second one
+// the same way, so the pair is rendered against itself and the lines of the first
+// rendering are returned. It returns an empty string if the pair can't be decoded.
+func decodePair(linkApp *app.LinkApp, storeName string, pair kv.Pair) string {
+	lines := strings.Split(strings.TrimSuffix(decodePairs(linkApp, storeName, pair, pair), "\n"), "\n")
+	if len(lines)%2 != 0 {
+		return ""
+	}
+	return strings.Join(lines[:len(lines)/2], "\n")
+}
+
+// decodePairs formats two pairs of the same key with the store decoder of the module.
+// The decoders panic on keys they don't know, which results in an empty string.
+func decodePairs(linkApp *app.LinkApp, storeName string, a, b kv.Pair) (decoded string) {
+	decoder, ok := linkApp.SimulationManager().StoreDecoders[storeName]
+	if !ok {
+		return ""
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			decoded = ""
+		}
+	}()
+	return decoder(a, b)
+}
+
+// diffStores lists the keys of the store which are missing or different in one of the multistores.
+func diffStores(linkApp *app.LinkApp, storeName string, a, b *rootmulti.Store, prefix []byte) []string {
+	key := linkApp.GetKey(storeName)
+	itA := sdk.KVStorePrefixIterator(a.GetKVStore(key), prefix)
+	defer itA.Close()
+	itB := sdk.KVStorePrefixIterator(b.GetKVStore(key), prefix)
+	defer itB.Close()
+
+	var diffs []string
+	for itA.Valid() || itB.Valid() {
+		var cmp int
+		switch {
+		case !itA.Valid():
+			cmp = 1
+		case !itB.Valid():
+			cmp = -1
+		default:
+			cmp = bytes.Compare(itA.Key(), itB.Key())
+		}
+
+		switch {
+		case cmp < 0:
+			pair := kv.Pair{Key: itA.Key(), Value: itA.Value()}
+			diffs = append(diffs, fmt.Sprintf("- %X only in A: %s", pair.Key, formatValue(linkApp, storeName, pair)))
+			itA.Next()
+		case cmp > 0:
+			pair := kv.Pair{Key: itB.Key(), Value: itB.Value()}
+			diffs = append(diffs, fmt.Sprintf("+ %X only in B: %s", pair.Key, formatValue(linkApp, storeName, pair)))
+			itB.Next()
+		default:
+			if !bytes.Equal(itA.Value(), itB.Value()) {
+				pairA := kv.Pair{Key: itA.Key(), Value: itA.Value()}
+				pairB := kv.Pair{Key: itB.Key(), Value: itB.Value()}
+				diffs = append(diffs, fmt.Sprintf("~ %X differs:\n  A: %s\n  B: %s", pairA.Key,
+					formatValue(linkApp, storeName, pairA), formatValue(linkApp, storeName, pairB)))
+			}
+			itA.Next()
+			itB.Next()
+		}
+	}
+	return diffs
+}
+
+// formatValue returns the decoded value of a pair, or the hex encoded value if it can't
+// be decoded. Multi-line values are indented to be listed in a diff.
+func formatValue(linkApp *app.LinkApp, storeName string, pair kv.Pair) string {
+	decoded := strings.TrimRight(decodePair(linkApp, storeName, pair), "\n")
+	if decoded == "" {
+		return fmt.Sprintf("%X", pair.Value)
+	}
+	return strings.ReplaceAll(decoded, "\n", "\n     ")
+}
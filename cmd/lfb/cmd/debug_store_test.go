@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/kv"
+	minttypes "github.com/line/lbm-sdk/x/mint/types"
+	slashingtypes "github.com/line/lbm-sdk/x/slashing/types"
+)
+
+func TestDecodePair(t *testing.T) {
+	linkApp := newOfflineApp(t.TempDir())
+	cdc := linkApp.AppCodec()
+
+	minter := minttypes.DefaultInitialMinter()
+	pair := kv.Pair{Key: minttypes.MinterKey, Value: cdc.MustMarshalBinaryBare(&minter)}
+	require.Equal(t, fmt.Sprintf("%v", minter), decodePair(linkApp, minttypes.StoreKey, pair))
+
+	// the decoder labels the two pairs it compares
+	key := slashingtypes.ValidatorMissedBlockBitArrayKey(sdk.ConsAddress("cons"), 1)
+	pair = kv.Pair{Key: key, Value: cdc.MustMarshalBinaryBare(&gogotypes.BoolValue{Value: true})}
+	require.Equal(t, "missedA: true", decodePair(linkApp, slashingtypes.StoreKey, pair))
+
+	// unknown keys and stores without a decoder are not decoded
+	require.Empty(t, decodePair(linkApp, minttypes.StoreKey, kv.Pair{Key: []byte{0xff}, Value: []byte{1}}))
+	require.Empty(t, decodePair(linkApp, "unknown", pair))
+}
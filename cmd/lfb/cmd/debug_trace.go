@@ -0,0 +1,508 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/server"
+	"github.com/line/lbm-sdk/store/rootmulti"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/bech32"
+	"github.com/line/lbm-sdk/types/kv"
+	"github.com/line/lbm-sdk/version"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+
+	"github.com/line/lfb/app"
+)
+
+const flagTop = "top"
+
+// unknownModule is the module of the keys no store decoder recognizes.
+const unknownModule = "(unknown)"
+
+// traceKeysLimit is the number of distinct keys whose operations are aggregated per key.
+const traceKeysLimit = 1 << 17
+
+// traceCmd returns the debug commands for the KV store traces written with --trace-store.
+func traceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Inspect the KV store traces written with --trace-store",
+		RunE:  client.ValidateCmd,
+	}
+	cmd.AddCommand(traceAnalyzeCmd())
+	return cmd
+}
+
+func traceAnalyzeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze [file]",
+		Short: "Summarize a KV store trace by module, key prefix, block, tx, contract and key",
+		Long: fmt.Sprintf(`Read a KV store trace written by 'start --trace-store' or 'debug replay --trace-store'
+and report the reads, writes, deletes and iterated keys, and the bytes read and written,
+per module, per key prefix, per block, per tx and per wasm contract, as well as the hottest
+keys decoded with the store decoders of the modules.
+
+The trace does not record the store of an operation, so the module of a key is inferred
+from the stores that hold the key in the latest state of the application database of the
+node, if it can be opened, and otherwise from the store decoders which accept the key and
+its value. A key that matches several stores is reported under all of them, e.g.
+"distribution|slashing". The keys of the contract storage of wasm are recognized by the
+contract address they start with.
+
+Every cache layer traces its operations: a write in a tx is traced when the tx is written
+to the block state, and again when the block is committed. The SDK does not clear the tx
+hash of the trace context after a tx, so the operations of EndBlock and the commit are
+counted for the last tx of the block.
+
+The operations of at most %d distinct keys are kept at a time. Beyond that, the keys with
+the fewest operations are counted in their modules, key prefixes and contracts and
+forgotten, so that a key whose operations are spread over a long trace may be missing from
+the hottest keys, or be ranked with part of its operations only.`, traceKeysLimit),
+		Example: fmt.Sprintf("$ %s debug trace analyze trace.json --top 50", version.AppName),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			top, _ := cmd.Flags().GetInt(flagTop)
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			home := server.GetServerContextFromCmd(cmd).Config.RootDir
+			a := newTraceAnalysis(newOfflineApp(home))
+			if db, err := openAppDB(home); err != nil {
+				cmd.PrintErrf("the modules are inferred from the store decoders only: %s\n", err)
+			} else {
+				defer db.Close()
+				if a.state, err = loadMultiStore(db, a.linkApp.GetKeys(), 0); err != nil {
+					return err
+				}
+			}
+			if err := a.read(f); err != nil {
+				return err
+			}
+			a.report(cmd.OutOrStdout(), top)
+			return nil
+		},
+	}
+	cmd.Flags().Int(flagTop, 20, "The number of rows of the key, tx and contract rankings")
+	return cmd
+}
+
+// traceOperation is an operation of the KV store trace written by tracekv.
+type traceOperation struct {
+	Operation string `json:"operation"`
+	Key       []byte `json:"key"`
+	Value     []byte `json:"value"`
+	Metadata  struct {
+		BlockHeight int64  `json:"blockHeight"`
+		TxHash      string `json:"txHash"`
+	} `json:"metadata"`
+}
+
+// traceStats are the operation counts and sizes of a group of operations.
+type traceStats struct {
+	Reads        int64
+	Writes       int64
+	Deletes      int64
+	Iterated     int64
+	BytesRead    int64
+	BytesWritten int64
+}
+
+func (s *traceStats) Ops() int64 {
+	return s.Reads + s.Writes + s.Deletes + s.Iterated
+}
+
+func (s *traceStats) add(op *traceOperation) {
+	switch op.Operation {
+	case "read":
+		s.Reads++
+		s.BytesRead += int64(len(op.Value))
+	case "write":
+		s.Writes++
+		s.BytesWritten += int64(len(op.Key) + len(op.Value))
+	case "delete":
+		s.Deletes++
+	case "iterKey":
+		s.Iterated++
+	case "iterValue":
+		s.BytesRead += int64(len(op.Value))
+	}
+}
+
+func (s *traceStats) merge(o *traceStats) {
+	s.Reads += o.Reads
+	s.Writes += o.Writes
+	s.Deletes += o.Deletes
+	s.Iterated += o.Iterated
+	s.BytesRead += o.BytesRead
+	s.BytesWritten += o.BytesWritten
+}
+
+type keyStats struct {
+	traceStats
+	// sample is a value of the key to find the store decoder of the key with
+	sample []byte
+}
+
+type txStats struct {
+	traceStats
+	Height int64
+}
+
+type blockStats struct {
+	traceStats
+	Txs map[string]bool
+}
+
+// traceAnalysis aggregates the operations of a KV store trace.
+type traceAnalysis struct {
+	linkApp *app.LinkApp
+	// state is the latest state of the node to find the stores of the keys in, if available
+	state  *rootmulti.Store
+	total  traceStats
+	blocks map[int64]*blockStats
+	txs    map[string]*txStats
+	// keys holds at most keysLimit keys; the keys with the fewest operations are moved to
+	// the stats of their modules, key prefixes and contracts when it is full
+	keys      map[string]*keyStats
+	keysLimit int
+	forgotten int
+	modules   map[string]*traceStats
+	prefixes  map[string]*traceStats
+	contracts map[string]*traceStats
+}
+
+func newTraceAnalysis(linkApp *app.LinkApp) *traceAnalysis {
+	return &traceAnalysis{
+		linkApp:   linkApp,
+		blocks:    map[int64]*blockStats{},
+		txs:       map[string]*txStats{},
+		keys:      map[string]*keyStats{},
+		keysLimit: traceKeysLimit,
+		modules:   map[string]*traceStats{},
+		prefixes:  map[string]*traceStats{},
+		contracts: map[string]*traceStats{},
+	}
+}
+
+// read aggregates the operations of the trace, one JSON object per line.
+func (a *traceAnalysis) read(r io.Reader) error {
+	reader := bufio.NewReaderSize(r, 1<<20)
+	// iterValue operations have no key; they belong to the preceding iterKey
+	var lastIterKey []byte
+	for line := 1; ; line++ {
+		bz, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(bz))) > 0 {
+			var op traceOperation
+			if err := json.Unmarshal(bz, &op); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			switch op.Operation {
+			case "iterKey":
+				lastIterKey = op.Key
+			case "iterValue":
+				op.Key = lastIterKey
+			}
+			a.add(&op)
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func (a *traceAnalysis) add(op *traceOperation) {
+	a.total.add(op)
+
+	block, ok := a.blocks[op.Metadata.BlockHeight]
+	if !ok {
+		block = &blockStats{Txs: map[string]bool{}}
+		a.blocks[op.Metadata.BlockHeight] = block
+	}
+	block.add(op)
+
+	// the tx hash stays in the trace context in the following blocks until the next tx
+	if hash := op.Metadata.TxHash; hash != "" {
+		tx, ok := a.txs[hash]
+		if !ok {
+			tx = &txStats{Height: op.Metadata.BlockHeight}
+			a.txs[hash] = tx
+			block.Txs[hash] = true
+		}
+		if tx.Height == op.Metadata.BlockHeight {
+			tx.add(op)
+		}
+	}
+
+	if len(op.Key) > 0 {
+		key, ok := a.keys[string(op.Key)]
+		if !ok {
+			if len(a.keys) == a.keysLimit {
+				a.forgetColdKeys()
+			}
+			key = &keyStats{}
+			a.keys[string(op.Key)] = key
+		}
+		key.add(op)
+		if key.sample == nil && len(op.Value) > 0 {
+			key.sample = op.Value
+		}
+	}
+}
+
+// forgetColdKeys moves the half of the keys with the fewest operations to the stats of their
+// modules, key prefixes and contracts.
+func (a *traceAnalysis) forgetColdKeys() {
+	keys := make([]string, 0, len(a.keys))
+	for key := range a.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if a.keys[keys[i]].Ops() != a.keys[keys[j]].Ops() {
+			return a.keys[keys[i]].Ops() < a.keys[keys[j]].Ops()
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys[:(len(keys)+1)/2] {
+		a.addKeyStats(a.modules, a.prefixes, a.contracts, key, a.keys[key])
+		delete(a.keys, key)
+		a.forgotten++
+	}
+}
+
+// addKeyStats adds the stats of the key to its module, key prefix and contract, and returns
+// its module and its value decoded with the store decoder of the module.
+func (a *traceAnalysis) addKeyStats(modules, prefixes, contracts map[string]*traceStats, key string, s *keyStats) (module, decoded string) {
+	module, decoded = a.keyModule([]byte(key), s.sample)
+	addStats(modules, module, &s.traceStats)
+	addStats(prefixes, fmt.Sprintf("%s %X", module, key[:1]), &s.traceStats)
+	if contract := contractOfKey([]byte(key)); contract != "" {
+		addStats(contracts, contract, &s.traceStats)
+	}
+	return module, decoded
+}
+
+// keyModule returns the module of a key and its value decoded with the store decoder
+// of the module. The module is the store that holds the key in the latest state, or
+// the stores whose decoders accept the key if the key is found in none or several of
+// them. The keys of the contract storage of wasm don't have a decoder, and are
+// recognized by the contract address they start with.
+func (a *traceAnalysis) keyModule(key []byte, sample []byte) (module, decoded string) {
+	if contract := contractOfKey(key); contract != "" {
+		return wasmtypes.StoreKey, ""
+	}
+
+	var modules []string
+	if a.state != nil {
+		for _, name := range storeNames(a.linkApp) {
+			if a.state.GetKVStore(a.linkApp.GetKey(name)).Has(key) {
+				modules = append(modules, name)
+			}
+		}
+	}
+	if len(modules) != 1 {
+		candidates := modules
+		if len(candidates) == 0 {
+			candidates = storeNames(a.linkApp)
+		}
+		modules = nil
+		for _, name := range candidates {
+			if decodePair(a.linkApp, name, kv.Pair{Key: key, Value: sample}) != "" {
+				modules = append(modules, name)
+			}
+		}
+	}
+
+	switch len(modules) {
+	case 0:
+		return unknownModule, ""
+	case 1:
+		return modules[0], decodePair(a.linkApp, modules[0], kv.Pair{Key: key, Value: sample})
+	default:
+		return strings.Join(modules, "|"), ""
+	}
+}
+
+// contractOfKey returns the bech32 address of the wasm contract of a key of the
+// contract storage, or an empty string if it is not a key of the contract storage.
+func contractOfKey(key []byte) string {
+	if len(key) == 0 || key[0] != wasmtypes.ContractStorePrefix[0] {
+		return ""
+	}
+	hrp := sdk.GetConfig().GetBech32AccountAddrPrefix()
+	// the 20 and 32 byte addresses are encoded to 38 and 58 characters with the checksum
+	for _, length := range []int{38, 58} {
+		end := 1 + len(hrp) + 1 + length
+		if len(key) < end {
+			continue
+		}
+		addr := string(key[1:end])
+		if prefix, _, err := bech32.DecodeAndConvert(addr); err == nil && prefix == hrp {
+			return addr
+		}
+	}
+	return ""
+}
+
+type namedStats struct {
+	Name string
+	traceStats
+}
+
+// sortedStats returns the stats sorted by the given order, with the names as the tie-breaker.
+func sortedStats(stats map[string]*traceStats, less func(a, b *traceStats) bool) []namedStats {
+	result := make([]namedStats, 0, len(stats))
+	for name, s := range stats {
+		result = append(result, namedStats{Name: name, traceStats: *s})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if less(&result[i].traceStats, &result[j].traceStats) {
+			return true
+		}
+		if less(&result[j].traceStats, &result[i].traceStats) {
+			return false
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func byBytesWritten(a, b *traceStats) bool { return a.BytesWritten > b.BytesWritten }
+func byOps(a, b *traceStats) bool          { return a.Ops() > b.Ops() }
+
+type hotKey struct {
+	key     string
+	module  string
+	decoded string
+	stats   traceStats
+}
+
+// keyStats returns the stats by module, key prefix and contract of every key, and the hot keys
+// sorted by operations.
+func (a *traceAnalysis) keyStats() (modules, prefixes, contracts map[string]*traceStats, hotKeys []hotKey) {
+	modules, prefixes, contracts = cloneStats(a.modules), cloneStats(a.prefixes), cloneStats(a.contracts)
+	hotKeys = make([]hotKey, 0, len(a.keys))
+	for key, s := range a.keys {
+		module, decoded := a.addKeyStats(modules, prefixes, contracts, key, s)
+		hotKeys = append(hotKeys, hotKey{key: key, module: module, decoded: decoded, stats: s.traceStats})
+	}
+	sort.Slice(hotKeys, func(i, j int) bool {
+		if hotKeys[i].stats.Ops() != hotKeys[j].stats.Ops() {
+			return hotKeys[i].stats.Ops() > hotKeys[j].stats.Ops()
+		}
+		return hotKeys[i].key < hotKeys[j].key
+	})
+	return modules, prefixes, contracts, hotKeys
+}
+
+// report writes the aggregated operations as text tables.
+func (a *traceAnalysis) report(w io.Writer, top int) {
+	modules, prefixes, contracts, hotKeys := a.keyStats()
+
+	if a.forgotten == 0 {
+		fmt.Fprintf(w, "%d operations in %d blocks and %d txs, %d distinct keys\n",
+			a.total.Ops(), len(a.blocks), len(a.txs), len(a.keys))
+	} else {
+		fmt.Fprintf(w, "%d operations in %d blocks and %d txs, more than %d distinct keys\n",
+			a.total.Ops(), len(a.blocks), len(a.txs), a.keysLimit)
+	}
+	writeStatsHeader(w, "module")
+	for _, s := range sortedStats(modules, byBytesWritten) {
+		writeStatsRow(w, s.Name, &s.traceStats)
+	}
+	writeStatsRow(w, "total", &a.total)
+
+	fmt.Fprintln(w)
+	writeStatsHeader(w, "key prefix")
+	for _, s := range sortedStats(prefixes, byBytesWritten) {
+		writeStatsRow(w, s.Name, &s.traceStats)
+	}
+
+	fmt.Fprintln(w)
+	writeStatsHeader(w, "block")
+	heights := make([]int64, 0, len(a.blocks))
+	for height := range a.blocks {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	for _, height := range heights {
+		block := a.blocks[height]
+		writeStatsRow(w, fmt.Sprintf("%d (%d txs)", height, len(block.Txs)), &block.traceStats)
+	}
+
+	if len(a.txs) > 0 {
+		fmt.Fprintf(w, "\ntop %d txs by bytes written\n", top)
+		txs := map[string]*traceStats{}
+		for hash, tx := range a.txs {
+			txs[fmt.Sprintf("%s@%d", hash, tx.Height)] = &tx.traceStats
+		}
+		writeStatsHeader(w, "tx")
+		for i, s := range sortedStats(txs, byBytesWritten) {
+			if i == top {
+				break
+			}
+			writeStatsRow(w, s.Name, &s.traceStats)
+		}
+	}
+
+	if len(contracts) > 0 {
+		fmt.Fprintf(w, "\ntop %d contracts by bytes written to the contract storage\n", top)
+		writeStatsHeader(w, "contract")
+		for i, s := range sortedStats(contracts, byBytesWritten) {
+			if i == top {
+				break
+			}
+			writeStatsRow(w, s.Name, &s.traceStats)
+		}
+	}
+
+	fmt.Fprintf(w, "\ntop %d keys by operations\n", top)
+	for i, k := range hotKeys {
+		if i == top {
+			break
+		}
+		fmt.Fprintf(w, "%8d ops (%d reads, %d writes, %d deletes, %d iterated) %s %X\n",
+			k.stats.Ops(), k.stats.Reads, k.stats.Writes, k.stats.Deletes, k.stats.Iterated, k.module, k.key)
+		if decoded := strings.TrimSpace(k.decoded); decoded != "" {
+			fmt.Fprintf(w, "         %s\n", strings.ReplaceAll(decoded, "\n", "\n         "))
+		}
+	}
+}
+
+func addStats(stats map[string]*traceStats, name string, s *traceStats) {
+	if _, ok := stats[name]; !ok {
+		stats[name] = &traceStats{}
+	}
+	stats[name].merge(s)
+}
+
+func cloneStats(stats map[string]*traceStats) map[string]*traceStats {
+	clone := make(map[string]*traceStats, len(stats))
+	for name, s := range stats {
+		clone[name] = &traceStats{}
+		clone[name].merge(s)
+	}
+	return clone
+}
+
+func writeStatsHeader(w io.Writer, name string) {
+	fmt.Fprintf(w, "%-40s %10s %10s %10s %10s %14s %14s\n", name, "reads", "writes", "deletes", "iterated", "bytes read", "bytes written")
+}
+
+func writeStatsRow(w io.Writer, name string, s *traceStats) {
+	fmt.Fprintf(w, "%-40s %10d %10d %10d %10d %14d %14d\n", name, s.Reads, s.Writes, s.Deletes, s.Iterated, s.BytesRead, s.BytesWritten)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	minttypes "github.com/line/lbm-sdk/x/mint/types"
+)
+
+// traceLine returns an operation of a KV store trace as tracekv writes it.
+func traceLine(t *testing.T, operation string, key, value []byte, height int64, txHash string) []byte {
+	op := traceOperation{Operation: operation, Key: key, Value: value}
+	op.Metadata.BlockHeight = height
+	op.Metadata.TxHash = txHash
+	bz, err := json.Marshal(op)
+	require.NoError(t, err)
+	return append(bz, '\n')
+}
+
+func TestTraceAnalysis(t *testing.T) {
+	a := newTraceAnalysis(newOfflineApp(t.TempDir()))
+	minter := minttypes.DefaultInitialMinter()
+	minterBz := a.linkApp.AppCodec().MustMarshalBinaryBare(&minter)
+	params := append([]byte{0xaa}, []byte("params")...)
+
+	var trace bytes.Buffer
+	trace.Write(traceLine(t, "read", minttypes.MinterKey, minterBz, 1, ""))
+	trace.Write(traceLine(t, "write", minttypes.MinterKey, minterBz, 1, ""))
+	trace.Write(traceLine(t, "read", minttypes.MinterKey, minterBz, 2, "AA"))
+	trace.Write(traceLine(t, "iterKey", params, nil, 2, "AA"))
+	trace.Write(traceLine(t, "iterValue", nil, []byte("value"), 2, "AA"))
+	trace.Write(traceLine(t, "delete", params, nil, 2, "BB"))
+	// the tx hash stays in the trace context in the next block
+	trace.Write(traceLine(t, "read", minttypes.MinterKey, minterBz, 3, "BB"))
+	require.NoError(t, a.read(&trace))
+
+	require.Equal(t, traceStats{Reads: 3, Writes: 1, Deletes: 1, Iterated: 1,
+		BytesRead: int64(3*len(minterBz) + 5), BytesWritten: int64(1 + len(minterBz))}, a.total)
+	require.Len(t, a.blocks, 3)
+	require.Equal(t, traceStats{Reads: 1, Writes: 1, BytesRead: int64(len(minterBz)), BytesWritten: int64(1 + len(minterBz))},
+		a.blocks[1].traceStats)
+	require.Empty(t, a.blocks[1].Txs)
+	require.Equal(t, map[string]bool{"AA": true, "BB": true}, a.blocks[2].Txs)
+	require.Equal(t, traceStats{Reads: 1, Iterated: 1, BytesRead: int64(len(minterBz) + 5)}, a.txs["AA"].traceStats)
+	require.Equal(t, traceStats{Deletes: 1}, a.txs["BB"].traceStats)
+	require.Equal(t, int64(2), a.txs["BB"].Height)
+
+	modules, _, _, hotKeys := a.keyStats()
+	require.Equal(t, &traceStats{Reads: 3, Writes: 1, BytesRead: int64(3 * len(minterBz)), BytesWritten: int64(1 + len(minterBz))},
+		modules[minttypes.StoreKey])
+	require.Equal(t, &traceStats{Deletes: 1, Iterated: 1, BytesRead: 5}, modules[unknownModule])
+	require.Len(t, hotKeys, 2)
+	require.Equal(t, string(minttypes.MinterKey), hotKeys[0].key)
+	require.Equal(t, minttypes.StoreKey, hotKeys[0].module)
+	require.Equal(t, fmt.Sprintf("%v", minter), hotKeys[0].decoded)
+
+	var report bytes.Buffer
+	a.report(&report, 1)
+	require.Contains(t, report.String(), "6 operations in 3 blocks and 2 txs, 2 distinct keys\n")
+	require.Contains(t, report.String(), "ops (3 reads, 1 writes, 0 deletes, 0 iterated) mint 00\n")
+}
+
+func TestTraceAnalysisKeysLimit(t *testing.T) {
+	a := newTraceAnalysis(newOfflineApp(t.TempDir()))
+	a.keysLimit = 4
+
+	var trace bytes.Buffer
+	for i := 0; i < 10; i++ {
+		key := []byte{0xaa, byte(i)}
+		for n := 0; n <= i; n++ {
+			trace.Write(traceLine(t, "read", key, nil, 1, ""))
+		}
+	}
+	require.NoError(t, a.read(&trace))
+	require.LessOrEqual(t, len(a.keys), 4)
+
+	// the forgotten keys are still counted in their module
+	modules, prefixes, _, hotKeys := a.keyStats()
+	require.Equal(t, &traceStats{Reads: 55}, modules[unknownModule])
+	require.Equal(t, &traceStats{Reads: 55}, prefixes[unknownModule+" AA"])
+	require.Equal(t, string([]byte{0xaa, 9}), hotKeys[0].key)
+	require.Equal(t, int64(10), hotKeys[0].stats.Reads)
+
+	var report bytes.Buffer
+	a.report(&report, 1)
+	require.Contains(t, report.String(), "55 operations in 1 blocks and 0 txs, more than 4 distinct keys\n")
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/line/lbm-sdk/client"
 	"github.com/line/lbm-sdk/client/flags"
-	"github.com/line/lbm-sdk/codec"
 	"github.com/line/lbm-sdk/crypto/keyring"
 	"github.com/line/lbm-sdk/server"
 	sdk "github.com/line/lbm-sdk/types"
@@ -19,6 +18,8 @@ import (
 	banktypes "github.com/line/lbm-sdk/x/bank/types"
 	"github.com/line/lbm-sdk/x/genutil"
 	genutiltypes "github.com/line/lbm-sdk/x/genutil/types"
+
+	lfbtypes "github.com/line/lfb/types"
 )
 
 const (
@@ -40,8 +41,10 @@ contain valid denominations. Accounts may optionally be supplied with vesting pa
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clientCtx := client.GetClientContextFromCmd(cmd)
-			depCdc := clientCtx.JSONMarshaler
-			cdc := depCdc.(codec.Marshaler) // nolint: errcheck
+			cdc, err := clientMarshaler(clientCtx)
+			if err != nil {
+				return err
+			}
 
 			serverCtx := server.GetServerContextFromCmd(cmd)
 			config := serverCtx.Config
@@ -49,8 +52,14 @@ contain valid denominations. Accounts may optionally be supplied with vesting pa
 			config.SetRoot(clientCtx.HomeDir)
 
 			var addr sdk.AccAddress
-			err := sdk.ValidateAccAddress(args[0])
-			if err != nil {
+			err = sdk.ValidateAccAddress(args[0])
+			if _, perr := lfbtypes.ParseAddress(args[0]); err != nil && perr == nil {
+				// a well-formed address of another network or kind
+				if err := validateAddress(cmd, args[0], lfbtypes.KindAccAddr); err != nil {
+					return err
+				}
+				addr = sdk.AccAddress(args[0])
+			} else if err != nil {
 				inBuf := bufio.NewReader(cmd.InOrStdin())
 				keyringBackend, err := cmd.Flags().GetString(flags.FlagKeyringBackend)
 				if err != nil {
@@ -163,7 +172,7 @@ contain valid denominations. Accounts may optionally be supplied with vesting pa
 
 			appState[authtypes.ModuleName] = authGenStateBz
 
-			bankGenState := banktypes.GetGenesisStateFromAppState(depCdc, appState)
+			bankGenState := banktypes.GetGenesisStateFromAppState(cdc, appState)
 			bankGenState.Balances = append(bankGenState.Balances, balances)
 			bankGenState.Balances = banktypes.SanitizeGenesisBalances(bankGenState.Balances)
 			bankGenState.Supply = bankGenState.Supply.Add(balances.Coins...)
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	ostcfg "github.com/line/ostracon/config"
+	"github.com/line/ostracon/libs/log"
+	"github.com/line/ostracon/node"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/line/tm-db/v2/memdb"
+
+	"github.com/line/lbm-sdk/simapp"
+	"github.com/line/lbm-sdk/snapshots"
+	"github.com/line/lbm-sdk/store/rootmulti"
+	storetypes "github.com/line/lbm-sdk/store/types"
+	sdk "github.com/line/lbm-sdk/types"
+
+	"github.com/line/lfb/app"
+)
+
+// newOfflineApp returns an app on an in-memory database without loading any state,
+// to get the store keys and the store decoders of the modules. It ignores the app options
+// of the node, so that it starts no streaming, indexer, tracing or telemetry.
+func newOfflineApp(home string) *app.LinkApp {
+	return app.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, false, map[int64]bool{}, home, 0,
+		app.MakeEncodingConfig(), simapp.EmptyAppOptions{}, nil)
+}
+
+// openAppDB opens the application database of the node home, the same way `start` does.
+func openAppDB(home string) (dbm.DB, error) {
+	return sdk.NewLevelDB("application", filepath.Join(home, "data"))
+}
+
+// openSnapshotStore opens the state-sync snapshot store under data/snapshots of the node home.
+// It returns the metadata database of the store too, which the caller must close once it is
+// done with the store.
+func openSnapshotStore(home string) (*snapshots.Store, dbm.DB, error) {
+	snapshotDir := filepath.Join(home, "data", "snapshots")
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		return nil, nil, err
+	}
+	snapshotDB, err := sdk.NewLevelDB("metadata", snapshotDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := snapshots.NewStore(snapshotDB, snapshotDir)
+	if err != nil {
+		snapshotDB.Close()
+		return nil, nil, err
+	}
+	return store, snapshotDB, nil
+}
+
+// openOstraconDB opens one of the ostracon databases (blockstore, state, ...) of the node.
+func openOstraconDB(config *ostcfg.Config, id string) (dbm.DB, error) {
+	return node.DefaultDBProvider(&node.DBContext{ID: id, Config: config})
+}
+
+// loadMultiStore loads the IAVL stores of the keys from the application database at
+// the given height, or at the latest height if it is 0.
+func loadMultiStore(db dbm.DB, keys map[string]*sdk.KVStoreKey, height int64) (*rootmulti.Store, error) {
+	cms := rootmulti.NewStore(db)
+	for _, key := range keys {
+		cms.MountStoreWithDB(key, storetypes.StoreTypeIAVL, nil)
+	}
+	if height == 0 {
+		return cms, cms.LoadLatestVersion()
+	}
+	return cms, cms.LoadVersion(height)
+}
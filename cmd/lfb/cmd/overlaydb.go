@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	dbm "github.com/line/tm-db/v2"
+)
+
+// overlayDB is a copy-on-write view of a database. Reads fall through to the base
+// database, while writes and deletes are kept in memory, so that the base database
+// is never modified.
+type overlayDB struct {
+	mtx  sync.RWMutex
+	base dbm.DB
+	// writes holds the written values; a nil value marks a deleted key
+	writes map[string][]byte
+}
+
+var _ dbm.DB = (*overlayDB)(nil)
+
+func newOverlayDB(base dbm.DB) *overlayDB {
+	return &overlayDB{base: base, writes: map[string][]byte{}}
+}
+
+func (db *overlayDB) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, dbm.ErrKeyEmpty
+	}
+	db.mtx.RLock()
+	value, ok := db.writes[string(key)]
+	db.mtx.RUnlock()
+	if ok {
+		return value, nil
+	}
+	return db.base.Get(key)
+}
+
+func (db *overlayDB) Has(key []byte) (bool, error) {
+	value, err := db.Get(key)
+	return value != nil, err
+}
+
+func (db *overlayDB) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return dbm.ErrKeyEmpty
+	}
+	if value == nil {
+		return dbm.ErrValueNil
+	}
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	db.writes[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (db *overlayDB) SetSync(key, value []byte) error {
+	return db.Set(key, value)
+}
+
+func (db *overlayDB) Delete(key []byte) error {
+	if len(key) == 0 {
+		return dbm.ErrKeyEmpty
+	}
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	db.writes[string(key)] = nil
+	return nil
+}
+
+func (db *overlayDB) DeleteSync(key []byte) error {
+	return db.Delete(key)
+}
+
+func (db *overlayDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, false)
+}
+
+func (db *overlayDB) PrefixIterator(prefix []byte) (dbm.Iterator, error) {
+	return db.Iterator(prefix, prefixEnd(prefix))
+}
+
+func (db *overlayDB) ReverseIterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, true)
+}
+
+func (db *overlayDB) ReversePrefixIterator(prefix []byte) (dbm.Iterator, error) {
+	return db.ReverseIterator(prefix, prefixEnd(prefix))
+}
+
+// Close does not close the base database, which is owned by the caller.
+func (db *overlayDB) Close() error {
+	return nil
+}
+
+func (db *overlayDB) NewBatch() dbm.Batch {
+	return &overlayBatch{db: db}
+}
+
+func (db *overlayDB) Print() error {
+	return db.base.Print()
+}
+
+func (db *overlayDB) Stats() map[string]string {
+	return db.base.Stats()
+}
+
+// newIterator merges an iterator of the base database with a snapshot of the
+// in-memory writes in the domain, which take precedence over the base values.
+func (db *overlayDB) newIterator(start, end []byte, reverse bool) (dbm.Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, dbm.ErrKeyEmpty
+	}
+
+	var base dbm.Iterator
+	var err error
+	if reverse {
+		base, err = db.base.ReverseIterator(start, end)
+	} else {
+		base, err = db.base.Iterator(start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	db.mtx.RLock()
+	writes := make([]overlayEntry, 0)
+	for key, value := range db.writes {
+		k := []byte(key)
+		if (start == nil || bytes.Compare(k, start) >= 0) && (end == nil || bytes.Compare(k, end) < 0) {
+			writes = append(writes, overlayEntry{key: k, value: value})
+		}
+	}
+	db.mtx.RUnlock()
+	sort.Slice(writes, func(i, j int) bool {
+		if reverse {
+			return bytes.Compare(writes[i].key, writes[j].key) > 0
+		}
+		return bytes.Compare(writes[i].key, writes[j].key) < 0
+	})
+
+	it := &overlayIterator{base: base, writes: writes, reverse: reverse}
+	it.skip()
+	return it, nil
+}
+
+// prefixEnd returns the end of the domain of the keys with the prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+type overlayEntry struct {
+	key, value []byte
+}
+
+// overlayIterator iterates over the base iterator and the sorted writes in order.
+type overlayIterator struct {
+	base    dbm.Iterator
+	writes  []overlayEntry
+	reverse bool
+	// fromWrites is whether the current position is in the writes
+	fromWrites bool
+}
+
+var _ dbm.Iterator = (*overlayIterator)(nil)
+
+// before returns whether a comes before b in the order of iteration.
+func (it *overlayIterator) before(a, b []byte) bool {
+	if it.reverse {
+		return bytes.Compare(a, b) > 0
+	}
+	return bytes.Compare(a, b) < 0
+}
+
+// skip moves past the base keys shadowed by the writes and the deleted keys,
+// and selects the source of the current position.
+func (it *overlayIterator) skip() {
+	for {
+		for it.base.Valid() && len(it.writes) > 0 && bytes.Equal(it.base.Key(), it.writes[0].key) {
+			it.base.Next()
+		}
+		switch {
+		case len(it.writes) == 0:
+			it.fromWrites = false
+			return
+		case it.base.Valid() && it.before(it.base.Key(), it.writes[0].key):
+			it.fromWrites = false
+			return
+		case it.writes[0].value == nil:
+			it.writes = it.writes[1:]
+		default:
+			it.fromWrites = true
+			return
+		}
+	}
+}
+
+func (it *overlayIterator) Valid() bool {
+	return it.fromWrites || it.base.Valid()
+}
+
+func (it *overlayIterator) Next() {
+	if !it.Valid() {
+		panic("overlayIterator is invalid")
+	}
+	if it.fromWrites {
+		it.writes = it.writes[1:]
+	} else {
+		it.base.Next()
+	}
+	it.skip()
+}
+
+func (it *overlayIterator) Key() []byte {
+	if !it.Valid() {
+		panic("overlayIterator is invalid")
+	}
+	if it.fromWrites {
+		return it.writes[0].key
+	}
+	return it.base.Key()
+}
+
+func (it *overlayIterator) Value() []byte {
+	if !it.Valid() {
+		panic("overlayIterator is invalid")
+	}
+	if it.fromWrites {
+		return it.writes[0].value
+	}
+	return it.base.Value()
+}
+
+func (it *overlayIterator) Error() error {
+	return it.base.Error()
+}
+
+func (it *overlayIterator) Close() error {
+	return it.base.Close()
+}
+
+// overlayBatch buffers the writes of a batch until it is written to the overlay.
+type overlayBatch struct {
+	db     *overlayDB
+	writes []overlayEntry
+	closed bool
+}
+
+var _ dbm.Batch = (*overlayBatch)(nil)
+
+func (b *overlayBatch) Set(key, value []byte) error {
+	if b.closed {
+		return dbm.ErrBatchClosed
+	}
+	if len(key) == 0 {
+		return dbm.ErrKeyEmpty
+	}
+	if value == nil {
+		return dbm.ErrValueNil
+	}
+	// the caller may reuse the key and the value before the batch is written
+	b.writes = append(b.writes, overlayEntry{key: append([]byte{}, key...), value: append([]byte{}, value...)})
+	return nil
+}
+
+func (b *overlayBatch) Delete(key []byte) error {
+	if b.closed {
+		return dbm.ErrBatchClosed
+	}
+	if len(key) == 0 {
+		return dbm.ErrKeyEmpty
+	}
+	b.writes = append(b.writes, overlayEntry{key: append([]byte{}, key...)})
+	return nil
+}
+
+func (b *overlayBatch) Write() error {
+	if b.closed {
+		return dbm.ErrBatchClosed
+	}
+	b.db.mtx.Lock()
+	for _, w := range b.writes {
+		b.db.writes[string(w.key)] = w.value
+	}
+	b.db.mtx.Unlock()
+	return b.Close()
+}
+
+func (b *overlayBatch) WriteSync() error {
+	return b.Write()
+}
+
+func (b *overlayBatch) Close() error {
+	b.closed = true
+	b.writes = nil
+	return nil
+}
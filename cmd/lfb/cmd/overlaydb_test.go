@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"testing"
+
+	dbm "github.com/line/tm-db/v2"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOverlay returns an overlay of a base with the keys a to f, where the overlay
+// deletes b and e, overwrites c and adds d and g.
+func newTestOverlay(t *testing.T) (*memdb.MemDB, *overlayDB) {
+	base := memdb.NewDB()
+	for _, key := range []string{"a", "b", "c", "e", "f"} {
+		require.NoError(t, base.Set([]byte(key), []byte("base "+key)))
+	}
+	overlay := newOverlayDB(base)
+	require.NoError(t, overlay.Delete([]byte("b")))
+	require.NoError(t, overlay.Set([]byte("c"), []byte("overlay c")))
+	require.NoError(t, overlay.Set([]byte("d"), []byte("overlay d")))
+	require.NoError(t, overlay.Delete([]byte("e")))
+	require.NoError(t, overlay.Set([]byte("g"), []byte("overlay g")))
+	return base, overlay
+}
+
+func iterate(t *testing.T, it dbm.Iterator, err error) []string {
+	require.NoError(t, err)
+	defer it.Close()
+	var kvs []string
+	for ; it.Valid(); it.Next() {
+		kvs = append(kvs, string(it.Key())+"="+string(it.Value()))
+	}
+	require.NoError(t, it.Error())
+	return kvs
+}
+
+func TestOverlayDBGet(t *testing.T) {
+	base, overlay := newTestOverlay(t)
+
+	for key, value := range map[string]string{
+		"a": "base a", "b": "", "c": "overlay c", "d": "overlay d", "e": "", "f": "base f", "g": "overlay g", "h": "",
+	} {
+		got, err := overlay.Get([]byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, string(got), key)
+		has, err := overlay.Has([]byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value != "", has, key)
+	}
+	_, err := overlay.Get(nil)
+	require.ErrorIs(t, err, dbm.ErrKeyEmpty)
+	require.ErrorIs(t, overlay.Set([]byte("a"), nil), dbm.ErrValueNil)
+
+	// the base is not modified
+	for key, value := range map[string]string{"b": "base b", "c": "base c", "d": "", "e": "base e"} {
+		got, err := base.Get([]byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, string(got), key)
+	}
+}
+
+func TestOverlayDBIterator(t *testing.T) {
+	_, overlay := newTestOverlay(t)
+
+	it, err := overlay.Iterator(nil, nil)
+	require.Equal(t, []string{"a=base a", "c=overlay c", "d=overlay d", "f=base f", "g=overlay g"}, iterate(t, it, err))
+	it, err = overlay.ReverseIterator(nil, nil)
+	require.Equal(t, []string{"g=overlay g", "f=base f", "d=overlay d", "c=overlay c", "a=base a"}, iterate(t, it, err))
+
+	it, err = overlay.Iterator([]byte("b"), []byte("f"))
+	require.Equal(t, []string{"c=overlay c", "d=overlay d"}, iterate(t, it, err))
+	it, err = overlay.ReverseIterator([]byte("b"), []byte("f"))
+	require.Equal(t, []string{"d=overlay d", "c=overlay c"}, iterate(t, it, err))
+	it, err = overlay.Iterator([]byte("e"), nil)
+	require.Equal(t, []string{"f=base f", "g=overlay g"}, iterate(t, it, err))
+	it, err = overlay.ReverseIterator(nil, []byte("c"))
+	require.Equal(t, []string{"a=base a"}, iterate(t, it, err))
+
+	// every key is deleted in the domain
+	it, err = overlay.Iterator([]byte("b"), []byte("c"))
+	require.Empty(t, iterate(t, it, err))
+	it, err = overlay.ReverseIterator([]byte("e"), []byte("f"))
+	require.Empty(t, iterate(t, it, err))
+
+	_, err = overlay.Iterator([]byte{}, nil)
+	require.ErrorIs(t, err, dbm.ErrKeyEmpty)
+	_, err = overlay.ReverseIterator(nil, []byte{})
+	require.ErrorIs(t, err, dbm.ErrKeyEmpty)
+}
+
+func TestOverlayDBPrefixIterator(t *testing.T) {
+	base := memdb.NewDB()
+	for _, key := range []string{"p", "p/1", "p/2", "q", string([]byte{'p', 0xff}), string([]byte{0xff, 0xff})} {
+		require.NoError(t, base.Set([]byte(key), []byte("base")))
+	}
+	overlay := newOverlayDB(base)
+	require.NoError(t, overlay.Delete([]byte("p/1")))
+	require.NoError(t, overlay.Set([]byte("p/3"), []byte("overlay")))
+
+	it, err := overlay.PrefixIterator([]byte("p/"))
+	require.Equal(t, []string{"p/2=base", "p/3=overlay"}, iterate(t, it, err))
+	it, err = overlay.ReversePrefixIterator([]byte("p"))
+	require.Equal(t, []string{"p\xff=base", "p/3=overlay", "p/2=base", "p=base"}, iterate(t, it, err))
+	it, err = overlay.PrefixIterator([]byte{0xff})
+	require.Equal(t, []string{"\xff\xff=base"}, iterate(t, it, err))
+
+	require.Equal(t, []byte("p0"), prefixEnd([]byte("p/")))
+	require.Equal(t, []byte("q"), prefixEnd([]byte{'p', 0xff}))
+	require.Nil(t, prefixEnd([]byte{0xff, 0xff}))
+}
+
+func TestOverlayBatch(t *testing.T) {
+	_, overlay := newTestOverlay(t)
+
+	batch := overlay.NewBatch()
+	// the batch keeps copies of the key and the value, which the caller may reuse
+	key, value := []byte("h"), []byte("batch h")
+	require.NoError(t, batch.Set(key, value))
+	key[0], value[0] = 'i', 'B'
+	key = []byte("a")
+	require.NoError(t, batch.Delete(key))
+	key[0] = 'f'
+	require.NoError(t, batch.Set([]byte("b"), []byte("batch b")))
+
+	// nothing is visible before the batch is written
+	it, err := overlay.Iterator(nil, nil)
+	require.Equal(t, []string{"a=base a", "c=overlay c", "d=overlay d", "f=base f", "g=overlay g"}, iterate(t, it, err))
+
+	require.NoError(t, batch.Write())
+	it, err = overlay.Iterator(nil, nil)
+	require.Equal(t, []string{"b=batch b", "c=overlay c", "d=overlay d", "f=base f", "g=overlay g", "h=batch h"}, iterate(t, it, err))
+	it, err = overlay.ReverseIterator(nil, nil)
+	require.Equal(t, []string{"h=batch h", "g=overlay g", "f=base f", "d=overlay d", "c=overlay c", "b=batch b"}, iterate(t, it, err))
+
+	require.ErrorIs(t, batch.Set([]byte("a"), []byte("a")), dbm.ErrBatchClosed)
+	require.ErrorIs(t, batch.Write(), dbm.ErrBatchClosed)
+	require.ErrorIs(t, overlay.NewBatch().Set(nil, []byte("a")), dbm.ErrKeyEmpty)
+}
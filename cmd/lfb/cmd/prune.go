@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	dbm "github.com/line/tm-db/v2"
+	"github.com/spf13/cobra"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/line/lbm-sdk/server"
+	"github.com/line/lbm-sdk/store/iavl"
+	storetypes "github.com/line/lbm-sdk/store/types"
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+// pruneBatchSize is the number of versions deleted from an IAVL store in a single batch.
+const pruneBatchSize = 100
+
+// errCompactionUnsupported is returned by compactDB for a database backend that cannot be compacted.
+var errCompactionUnsupported = errors.New("compaction is not supported by the database backend")
+
+// pruneCmd returns the command to prune the application database of a stopped node.
+func pruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Prune the application database offline and compact it",
+		Long: `Delete the IAVL versions of the application database which are not retained by
+the given pruning strategy, then compact the database and report the reclaimed space.
+The strategy is read from app.toml unless it is given by the flags, and follows the
+same rules as the pruning of a running node:
+default:    the last 100 heights are kept in addition to every 100th height
+nothing:    all heights are kept
+everything: only the latest height is kept
+custom:     --pruning-keep-recent and --pruning-keep-every are used
+
+The node must not be running while this command is used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			opts, err := server.GetPruningOptionsFromFlags(serverCtx.Viper)
+			if err != nil {
+				return err
+			}
+
+			home := serverCtx.Config.RootDir
+			dbDir := filepath.Join(home, "data", "application.db")
+			if _, err := os.Stat(dbDir); err != nil {
+				return err
+			}
+			sizeBefore, err := dirSize(dbDir)
+			if err != nil {
+				return err
+			}
+
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			pruned, err := pruneAppDB(db, newOfflineApp(home).GetKeys(), opts)
+			if err != nil {
+				return err
+			}
+			if len(pruned) == 0 {
+				cmd.Println("no heights to prune")
+			} else {
+				cmd.Printf("pruned %d heights from %d to %d\n", len(pruned), pruned[0], pruned[len(pruned)-1])
+			}
+
+			if err := compactDB(db); errors.Is(err, errCompactionUnsupported) {
+				cmd.Printf("skipping compaction: %s\n", err)
+			} else if err != nil {
+				return err
+			}
+			if err := db.Close(); err != nil {
+				return err
+			}
+			sizeAfter, err := dirSize(dbDir)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("database size: %d bytes -> %d bytes (reclaimed %d bytes)\n", sizeBefore, sizeAfter, sizeBefore-sizeAfter)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(server.FlagPruning, storetypes.PruningOptionDefault, "Pruning strategy (default|nothing|everything|custom)")
+	cmd.Flags().Uint64(server.FlagPruningKeepRecent, 0, "Number of recent heights to keep on disk (ignored if pruning is not 'custom')")
+	cmd.Flags().Uint64(server.FlagPruningKeepEvery, 0, "Offset heights to keep on disk after 'keep-every' (ignored if pruning is not 'custom')")
+	cmd.Flags().Uint64(server.FlagPruningInterval, 0, "Height interval at which pruned heights are removed from disk (ignored if pruning is not 'custom')")
+
+	return cmd
+}
+
+// pruneAppDB deletes the versions of the IAVL stores that the pruning options do
+// not retain, the same way the multistore of a running node does on commit.
+// It returns the pruned heights in ascending order.
+func pruneAppDB(db dbm.DB, keys map[string]*sdk.KVStoreKey, opts storetypes.PruningOptions) ([]int64, error) {
+	cms, err := loadMultiStore(db, keys, 0)
+	if err != nil {
+		return nil, err
+	}
+	latest := cms.LastCommitID().Version
+
+	batches := make(map[*iavl.Store][]int64, len(keys))
+	var pruned []int64
+	for height := int64(1); height < latest; height++ {
+		if !prunable(height, latest, opts) {
+			continue
+		}
+		exists := false
+		for _, key := range keys {
+			store := cms.GetCommitKVStore(key).(*iavl.Store)
+			if !store.VersionExists(height) {
+				continue
+			}
+			exists = true
+			batches[store] = append(batches[store], height)
+			if len(batches[store]) == pruneBatchSize {
+				if err := store.DeleteVersions(batches[store]...); err != nil {
+					return nil, err
+				}
+				batches[store] = batches[store][:0]
+			}
+		}
+		if exists {
+			pruned = append(pruned, height)
+		}
+	}
+	for store, batch := range batches {
+		if len(batch) > 0 {
+			if err := store.DeleteVersions(batch...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pruned, nil
+}
+
+// prunable returns true if the height is not retained at the latest height by the pruning options.
+func prunable(height, latest int64, opts storetypes.PruningOptions) bool {
+	if height >= latest-int64(opts.KeepRecent) {
+		return false
+	}
+	return opts.KeepEvery == 0 || height%int64(opts.KeepEvery) != 0
+}
+
+// compactDB compacts the whole key range of the database. It returns
+// errCompactionUnsupported if the backend is not goleveldb.
+func compactDB(db dbm.DB) error {
+	levelDB, ok := db.(interface{ DB() *leveldb.DB })
+	if !ok {
+		return errCompactionUnsupported
+	}
+	return levelDB.DB().CompactRange(util.Range{})
+}
+
+// dirSize returns the total size of the files under the directory.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/simapp"
+	storetypes "github.com/line/lbm-sdk/store/types"
+	minttypes "github.com/line/lbm-sdk/x/mint/types"
+
+	"github.com/line/lfb/app"
+)
+
+func TestPruneAppDB(t *testing.T) {
+	home := t.TempDir()
+	const latest = 30
+
+	// build a small chain keeping every height
+	db, err := openAppDB(home)
+	require.NoError(t, err)
+	encodingConfig := app.MakeEncodingConfig()
+	linkApp := app.NewLinkApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{}, nil)
+	genesis, err := json.Marshal(app.ModuleBasics.DefaultGenesis(encodingConfig.Marshaler))
+	require.NoError(t, err)
+	linkApp.InitChain(abci.RequestInitChain{ChainId: "prune-test", AppStateBytes: genesis})
+	for height := int64(1); height <= latest; height++ {
+		header := ocproto.Header{ChainID: "prune-test", Height: height}
+		linkApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+		linkApp.EndBlock(abci.RequestEndBlock{Height: height})
+		linkApp.Commit()
+	}
+	require.Equal(t, int64(latest), linkApp.LastBlockHeight())
+
+	// keep the last 5 heights and every 10th
+	opts := storetypes.NewPruningOptions(5, 10, 10)
+	pruned, err := pruneAppDB(db, linkApp.GetKeys(), opts)
+	require.NoError(t, err)
+	require.Len(t, pruned, 22)
+	require.Equal(t, int64(1), pruned[0])
+	require.Equal(t, int64(24), pruned[len(pruned)-1])
+	require.NoError(t, compactDB(db))
+	require.ErrorIs(t, compactDB(memdb.NewDB()), errCompactionUnsupported)
+
+	// pruning again has nothing left to delete
+	pruned, err = pruneAppDB(db, linkApp.GetKeys(), opts)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+
+	linkApp = app.NewLinkApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, home, 0, encodingConfig, simapp.EmptyAppOptions{}, nil)
+	require.Equal(t, int64(latest), linkApp.LastBlockHeight())
+	for height := int64(1); height <= latest; height++ {
+		res := linkApp.Query(abci.RequestQuery{Path: "/store/mint/key", Data: minttypes.MinterKey, Height: height})
+		require.True(t, res.IsOK(), res.Log)
+		if prunable(height, latest, opts) {
+			require.Empty(t, res.Value, "height %d should be pruned", height)
+		} else {
+			require.NotEmpty(t, res.Value, "height %d should be retained", height)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	ostcli "github.com/line/ostracon/libs/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/server"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/app/indexer"
+)
+
+// querySQLCmd returns the command to run the canned queries on the database of the indexer.
+func querySQLCmd() *cobra.Command {
+	var names []string
+	for _, q := range indexer.Queries {
+		names = append(names, q.Name)
+	}
+	cmd := &cobra.Command{
+		Use:   "sql [query] [param=value]...",
+		Short: "Run a canned query on the SQL indexer of the node",
+		Long: fmt.Sprintf(`Run a canned query on the database of the SQL indexer of the node at --home, which
+is enabled in the [indexer] section of app.toml. Without a query, list the queries and
+their parameters. The queries are: %s.
+
+The database is opened read-only, so the node may be running.`, strings.Join(names, ", ")),
+		Example: fmt.Sprintf(`$ %[1]s query sql
+$ %[1]s query sql sends denom=stake min_amount=1000000 from=2021-06-01T00:00:00Z to=2021-07-01T00:00:00Z`,
+			version.AppName),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(ostcli.OutputFlag)
+			if len(args) == 0 {
+				return printQueries(cmd, output)
+			}
+
+			q, ok := indexer.FindQuery(args[0])
+			if !ok {
+				return fmt.Errorf("unknown query %s; the queries are: %s", args[0], strings.Join(names, ", "))
+			}
+			params := map[string]string{}
+			for _, arg := range args[1:] {
+				kv := strings.SplitN(arg, "=", 2)
+				if len(kv) != 2 {
+					return fmt.Errorf("invalid parameter %q; expected param=value", arg)
+				}
+				params[kv[0]] = kv[1]
+			}
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			cfg, err := indexer.ReadConfig(serverCtx.Viper)
+			if err != nil {
+				return err
+			}
+			path := cfg.DBPath(serverCtx.Config.RootDir)
+			db, err := indexer.OpenReadOnly(path)
+			if err != nil {
+				return fmt.Errorf("failed to open the indexer database %s; is the indexer enabled in app.toml? %w", path, err)
+			}
+			defer db.Close()
+
+			res, err := q.Run(cmd.Context(), db, params)
+			if err != nil {
+				return err
+			}
+			if output == "json" {
+				bz, err := json.Marshal(res)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+				return nil
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, strings.ToUpper(strings.Join(res.Columns, "\t")))
+			for _, row := range res.Rows {
+				values := make([]string, len(row))
+				for i, v := range row {
+					values[i] = indexer.FormatValue(v)
+				}
+				fmt.Fprintln(w, strings.Join(values, "\t"))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "indexed up to height %d\n", res.Height)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(ostcli.OutputFlag, "o", "text", "Output format (text|json)")
+	return cmd
+}
+
+func printQueries(cmd *cobra.Command, output string) error {
+	if output == "json" {
+		bz, err := json.Marshal(indexer.Queries)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+		return nil
+	}
+	for _, q := range indexer.Queries {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n  %s\n", q.Name, q.Description)
+		for _, p := range q.Params {
+			required := ""
+			if p.Required {
+				required = ", required"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "    %s (%s%s): %s\n", p.Name, p.Kind, required, p.Description)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/line/iavl/v2"
+	"github.com/line/ostracon/crypto/vrf"
+	sm "github.com/line/ostracon/state"
+	ostcstore "github.com/line/ostracon/store"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/line/tm-db/v2/prefixdb"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/server"
+	storeiavl "github.com/line/lbm-sdk/store/iavl"
+	storetypes "github.com/line/lbm-sdk/store/types"
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+const flagHeights = "heights"
+
+// Neither the multistore nor the block store has an API to remove its latest versions, so
+// rollbackMultiStore and truncateBlockStore write the keys below directly. They mirror the
+// unexported key formats of lbm-sdk/store/rootmulti and ostracon/store, and must be kept in
+// sync with the versions of those modules in go.mod.
+const (
+	// latestVersionKey is the key of the latest version in the metadata of the multistore.
+	latestVersionKey = "s/latest"
+	// storePrefixFmt is the prefix of the IAVL store of a key in the multistore.
+	storePrefixFmt = "s/k:%s/"
+
+	blockMetaKeyFmt   = "H:%v"
+	blockPartKeyFmt   = "P:%v:%v"
+	blockCommitKeyFmt = "C:%v"
+	seenCommitKeyFmt  = "SC:%v"
+	blockHashKeyFmt   = "BH:%x"
+)
+
+// rollbackCmd returns the command to roll the application and ostracon state of a stopped node back.
+func rollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll the application state and the ostracon state back by one or more heights",
+		Long: `Roll the application state and the ostracon state back by the given number of heights,
+so that the node re-executes the following blocks on the next start, e.g. with a fixed
+binary after an app hash mismatch.
+
+The multistore is rewound to the target version, the ostracon state is rebuilt for the
+target height, and the blocks after the next one are removed from the block store.
+The command refuses to roll back if the target version has been pruned, or if the
+app hash of the target version does not match the app hash in the header of the next
+block.
+
+The blocks after the target are fetched from peers again; a validator does not sign the
+rolled back heights again, as priv_validator_state.json still protects them.
+
+The ostracon state is saved last, so an interrupted rollback can be run again with the
+same --heights.
+
+The node must not be running while this command is used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			heights, _ := cmd.Flags().GetInt64(flagHeights)
+			if heights < 1 {
+				return fmt.Errorf("--%s must be positive", flagHeights)
+			}
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+			home := config.RootDir
+
+			blockStoreDB, err := openOstraconDB(config, "blockstore")
+			if err != nil {
+				return err
+			}
+			defer blockStoreDB.Close()
+			stateDB, err := openOstraconDB(config, "state")
+			if err != nil {
+				return err
+			}
+			defer stateDB.Close()
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			blockStore := ostcstore.NewBlockStore(blockStoreDB)
+			stateStore := sm.NewStore(stateDB)
+			state, err := stateStore.Load()
+			if err != nil {
+				return err
+			}
+			if state.IsEmpty() {
+				return fmt.Errorf("no ostracon state found")
+			}
+			target := state.LastBlockHeight - heights
+			if target < state.InitialHeight || target < blockStore.Base() {
+				return fmt.Errorf("cannot roll back to height %d; the blocks are available from height %d", target, blockStore.Base())
+			}
+			// the block store is one block ahead of the state after a previous rollback, and
+			// may already be truncated to the target by an interrupted one
+			if h := blockStore.Height(); h > state.LastBlockHeight+1 || h < target+1 {
+				return fmt.Errorf("the block store height %d does not match the state height %d; the databases are inconsistent",
+					h, state.LastBlockHeight)
+			}
+
+			keys := newOfflineApp(home).GetKeys()
+			commitID, err := verifyRollbackTarget(db, keys, target, blockStore)
+			if err != nil {
+				return err
+			}
+			rolledBack, err := rollbackState(state, target, stateStore, blockStore)
+			if err != nil {
+				return err
+			}
+
+			// the state keeps the height to roll back from until the other databases are done
+			if err := rollbackMultiStore(db, keys, target); err != nil {
+				return fmt.Errorf("failed to roll back the application state: %w", err)
+			}
+			if err := truncateBlockStore(blockStoreDB, blockStore, target+1); err != nil {
+				return fmt.Errorf("failed to remove blocks from the block store: %w", err)
+			}
+			if err := stateStore.Save(rolledBack); err != nil {
+				return fmt.Errorf("failed to save the ostracon state: %w", err)
+			}
+
+			cmd.Printf("rolled back from height %d to height %d with app hash %X\n", state.LastBlockHeight, target, commitID.Hash)
+			return nil
+		},
+	}
+	cmd.Flags().Int64(flagHeights, 1, "The number of heights to roll back")
+	return cmd
+}
+
+// verifyRollbackTarget checks that every store keeps the target version, and that its
+// commit hash matches the app hash of the block header following the target height.
+func verifyRollbackTarget(db dbm.DB, keys map[string]*sdk.KVStoreKey, target int64, blockStore *ostcstore.BlockStore) (storetypes.CommitID, error) {
+	cms, err := loadMultiStore(db, keys, 0)
+	if err != nil {
+		return storetypes.CommitID{}, err
+	}
+	if latest := cms.LastCommitID().Version; latest < target {
+		return storetypes.CommitID{}, fmt.Errorf("the application state is at height %d, below the target height %d", latest, target)
+	}
+	for name, key := range keys {
+		if !cms.GetCommitKVStore(key).(*storeiavl.Store).VersionExists(target) {
+			return storetypes.CommitID{}, fmt.Errorf("version %d of store %s has been pruned", target, name)
+		}
+	}
+
+	if cms, err = loadMultiStore(db, keys, target); err != nil {
+		return storetypes.CommitID{}, err
+	}
+	commitID := cms.LastCommitID()
+
+	next := blockStore.LoadBlockMeta(target + 1)
+	if next == nil {
+		return storetypes.CommitID{}, fmt.Errorf("block %d not found", target+1)
+	}
+	if !bytes.Equal(commitID.Hash, next.Header.AppHash) {
+		return storetypes.CommitID{}, fmt.Errorf("app hash %X of version %d does not match the app hash %X in the header of block %d; roll back further",
+			commitID.Hash, target, next.Header.AppHash, target+1)
+	}
+	return commitID, nil
+}
+
+// rollbackState rebuilds the ostracon state after the target height from the
+// state store and the headers of the target block and the following one.
+func rollbackState(state sm.State, target int64, stateStore sm.Store, blockStore *ostcstore.BlockStore) (sm.State, error) {
+	meta := blockStore.LoadBlockMeta(target)
+	next := blockStore.LoadBlockMeta(target + 1)
+	if meta == nil || next == nil {
+		return sm.State{}, fmt.Errorf("blocks %d and %d are required to roll back", target, target+1)
+	}
+
+	validators, err := stateStore.LoadValidators(target + 1)
+	if err != nil {
+		return sm.State{}, err
+	}
+	nextValidators, err := stateStore.LoadValidators(target + 2)
+	if err != nil {
+		return sm.State{}, err
+	}
+	voters, err := stateStore.LoadVoters(target+1, state.VoterParams)
+	if err != nil {
+		return sm.State{}, err
+	}
+	lastVoters, err := stateStore.LoadVoters(target, state.VoterParams)
+	if err != nil {
+		return sm.State{}, err
+	}
+	params, err := stateStore.LoadConsensusParams(target + 1)
+	if err != nil {
+		return sm.State{}, err
+	}
+	proofHash, err := vrf.ProofToHash(meta.Header.Proof.Bytes())
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	// the heights of the last changes can only be approximated after the target height
+	valChangeHeight := state.LastHeightValidatorsChanged
+	if valChangeHeight > target {
+		valChangeHeight = target + 1
+	}
+	paramsChangeHeight := state.LastHeightConsensusParamsChanged
+	if paramsChangeHeight > target {
+		paramsChangeHeight = target + 1
+	}
+
+	version := state.Version
+	version.Consensus = next.Header.Version
+
+	return sm.State{
+		Version:                          version,
+		ChainID:                          state.ChainID,
+		InitialHeight:                    state.InitialHeight,
+		VoterParams:                      state.VoterParams,
+		LastBlockHeight:                  target,
+		LastBlockID:                      meta.BlockID,
+		LastBlockTime:                    meta.Header.Time,
+		LastProofHash:                    proofHash,
+		NextValidators:                   nextValidators,
+		Validators:                       validators,
+		Voters:                           voters,
+		LastVoters:                       lastVoters,
+		LastHeightValidatorsChanged:      valChangeHeight,
+		ConsensusParams:                  params,
+		LastHeightConsensusParamsChanged: paramsChangeHeight,
+		LastResultsHash:                  next.Header.LastResultsHash,
+		AppHash:                          next.Header.AppHash,
+	}, nil
+}
+
+// rollbackMultiStore sets the latest version of the multistore to the target and
+// deletes the versions after the target from every IAVL store. The latest version is
+// set first, so that the multistore still loads if some of the stores are not rolled
+// back yet.
+func rollbackMultiStore(db dbm.DB, keys map[string]*sdk.KVStoreKey, target int64) error {
+	bz, err := gogotypes.StdInt64Marshal(target)
+	if err != nil {
+		return err
+	}
+	if err := db.SetSync([]byte(latestVersionKey), bz); err != nil {
+		return err
+	}
+
+	for name := range keys {
+		tree, err := iavl.NewMutableTree(prefixdb.NewDB(db, []byte(fmt.Sprintf(storePrefixFmt, name))), 0)
+		if err != nil {
+			return err
+		}
+		if _, err := tree.LoadVersionForOverwriting(target); err != nil {
+			return fmt.Errorf("store %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// truncateBlockStore removes the blocks after the given height from the block store.
+func truncateBlockStore(db dbm.DB, blockStore *ostcstore.BlockStore, height int64) error {
+	batch := db.NewBatch()
+	defer batch.Close()
+	for h := blockStore.Height(); h > height; h-- {
+		meta := blockStore.LoadBlockMeta(h)
+		if meta == nil {
+			// the blocks were removed by an interrupted rollback before it saved the height
+			continue
+		}
+		for i := 0; i < int(meta.BlockID.PartSetHeader.Total); i++ {
+			if err := batch.Delete([]byte(fmt.Sprintf(blockPartKeyFmt, h, i))); err != nil {
+				return err
+			}
+		}
+		for _, key := range []string{
+			fmt.Sprintf(blockMetaKeyFmt, h),
+			fmt.Sprintf(blockCommitKeyFmt, h-1),
+			fmt.Sprintf(seenCommitKeyFmt, h),
+			fmt.Sprintf(blockHashKeyFmt, meta.BlockID.Hash),
+		} {
+			if err := batch.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+
+	blockStoreState := ostcstore.LoadBlockStoreState(db)
+	blockStoreState.Height = height
+	ostcstore.SaveBlockStoreState(&blockStoreState, db)
+	return nil
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ostcfg "github.com/line/ostracon/config"
+	"github.com/line/ostracon/libs/log"
+	"github.com/line/ostracon/node"
+	"github.com/line/ostracon/p2p"
+	"github.com/line/ostracon/privval"
+	"github.com/line/ostracon/proxy"
+	sm "github.com/line/ostracon/state"
+	ostcstore "github.com/line/ostracon/store"
+	dbm "github.com/line/tm-db/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/server"
+	"github.com/line/lbm-sdk/simapp"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+
+	"github.com/line/lfb/app"
+)
+
+// testNode is a single validator node of a testnet initialized by the testnet command,
+// whose databases are on disk.
+type testNode struct {
+	config *ostcfg.Config
+	dbs    []dbm.DB
+}
+
+func newTestNode(t *testing.T) *testNode {
+	dir := t.TempDir()
+	encodingConfig := app.MakeEncodingConfig()
+	clientCtx := client.Context{}.
+		WithJSONMarshaler(encodingConfig.Marshaler).
+		WithInterfaceRegistry(encodingConfig.InterfaceRegistry).
+		WithTxConfig(encodingConfig.TxConfig).
+		WithLegacyAmino(encodingConfig.Amino).
+		WithHomeDir(dir)
+	ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+	ctx = context.WithValue(ctx, server.ServerContextKey, server.NewDefaultContext())
+
+	cmd := testnetCmd(app.ModuleBasics, banktypes.GenesisBalancesIterator{})
+	cmd.SetArgs([]string{"--v", "1", "--output-dir", dir, "--keyring-backend", "test", "--chain-id", "rollback-test"})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	config := ostcfg.TestConfig()
+	config.SetRoot(filepath.Join(dir, "node0", "lfb"))
+	config.DBBackend = "goleveldb"
+	config.RPC.ListenAddress = ""
+	config.P2P.ListenAddress = "tcp://127.0.0.1:0"
+	return &testNode{config: config}
+}
+
+// dbProvider opens the ostracon databases and keeps them to be closed by close.
+func (n *testNode) dbProvider(ctx *node.DBContext) (dbm.DB, error) {
+	db, err := node.DefaultDBProvider(ctx)
+	if err == nil {
+		n.dbs = append(n.dbs, db)
+	}
+	return db, err
+}
+
+// newNode opens the databases, and replays the blocks that the app has not committed.
+func (n *testNode) newNode(t *testing.T) *node.Node {
+	db, err := openAppDB(n.config.RootDir)
+	require.NoError(t, err)
+	n.dbs = append(n.dbs, db)
+	linkApp := app.NewLinkApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, n.config.RootDir, 0,
+		app.MakeEncodingConfig(), simapp.EmptyAppOptions{}, nil)
+
+	nodeKey, err := p2p.LoadNodeKey(n.config.NodeKeyFile())
+	require.NoError(t, err)
+	pv := privval.LoadFilePV(n.config.PrivValidatorKeyFile(), n.config.PrivValidatorStateFile())
+	ostNode, err := node.NewNode(n.config, pv, nodeKey, proxy.NewLocalClientCreator(linkApp),
+		node.DefaultGenesisDocProviderFunc(n.config), n.dbProvider, node.DefaultMetricsProvider(n.config.Instrumentation),
+		log.NewNopLogger())
+	require.NoError(t, err)
+	return ostNode
+}
+
+func (n *testNode) close(t *testing.T) {
+	for _, db := range n.dbs {
+		require.NoError(t, db.Close())
+	}
+	n.dbs = nil
+}
+
+// run runs the node until it commits the height.
+func (n *testNode) run(t *testing.T, height int64) {
+	ostNode := n.newNode(t)
+	require.NoError(t, ostNode.Start())
+	require.Eventually(t, func() bool { return ostNode.BlockStore().Height() >= height }, time.Minute, 10*time.Millisecond)
+	require.NoError(t, ostNode.Stop())
+	ostNode.Wait()
+	n.close(t)
+}
+
+func (n *testNode) rollback(heights string) error {
+	serverCtx := server.NewDefaultContext()
+	serverCtx.Config = n.config
+	cmd := rollbackCmd()
+	cmd.SetArgs([]string{"--" + flagHeights, heights})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	return cmd.ExecuteContext(context.WithValue(context.Background(), server.ServerContextKey, serverCtx))
+}
+
+// heights returns the heights of the state, the block store and the application state.
+func (n *testNode) heights(t *testing.T) (int64, int64, int64) {
+	stateDB, err := openOstraconDB(n.config, "state")
+	require.NoError(t, err)
+	defer stateDB.Close()
+	state, err := sm.NewStore(stateDB).Load()
+	require.NoError(t, err)
+
+	blockStoreDB, err := openOstraconDB(n.config, "blockstore")
+	require.NoError(t, err)
+	defer blockStoreDB.Close()
+
+	db, err := openAppDB(n.config.RootDir)
+	require.NoError(t, err)
+	defer db.Close()
+	cms, err := loadMultiStore(db, newOfflineApp(n.config.RootDir).GetKeys(), 0)
+	require.NoError(t, err)
+
+	return state.LastBlockHeight, ostcstore.NewBlockStore(blockStoreDB).Height(), cms.LastCommitID().Version
+}
+
+func TestRollback(t *testing.T) {
+	n := newTestNode(t)
+	n.run(t, 6)
+	latest, blockHeight, appHeight := n.heights(t)
+	require.Equal(t, latest, blockHeight)
+	require.Equal(t, latest, appHeight)
+
+	require.Error(t, n.rollback("0"))
+	require.Error(t, n.rollback("100"))
+	require.NoError(t, n.rollback("2"))
+	stateHeight, blockHeight, appHeight := n.heights(t)
+	require.Equal(t, latest-2, stateHeight)
+	require.Equal(t, latest-1, blockHeight)
+	require.Equal(t, latest-2, appHeight)
+
+	// the node replays the next block on the handshake, and its app hash matches the header
+	// of the block after it
+	n.newNode(t)
+	n.close(t)
+	stateHeight, blockHeight, appHeight = n.heights(t)
+	require.Equal(t, latest-1, stateHeight)
+	require.Equal(t, latest-1, blockHeight)
+	require.Equal(t, latest-1, appHeight)
+
+	// a rollback is interrupted before it saves the state
+	latest--
+	target := latest - 2
+	db, err := openAppDB(n.config.RootDir)
+	require.NoError(t, err)
+	require.NoError(t, rollbackMultiStore(db, newOfflineApp(n.config.RootDir).GetKeys(), target))
+	require.NoError(t, db.Close())
+	blockStoreDB, err := openOstraconDB(n.config, "blockstore")
+	require.NoError(t, err)
+	require.NoError(t, truncateBlockStore(blockStoreDB, ostcstore.NewBlockStore(blockStoreDB), target+1))
+	require.NoError(t, blockStoreDB.Close())
+	stateHeight, blockHeight, appHeight = n.heights(t)
+	require.Equal(t, latest, stateHeight)
+	require.Equal(t, target+1, blockHeight)
+	require.Equal(t, target, appHeight)
+
+	// and is run again
+	require.NoError(t, n.rollback("2"))
+	stateHeight, blockHeight, appHeight = n.heights(t)
+	require.Equal(t, target, stateHeight)
+	require.Equal(t, target+1, blockHeight)
+	require.Equal(t, target, appHeight)
+
+	n.newNode(t)
+	n.close(t)
+	stateHeight, _, appHeight = n.heights(t)
+	require.Equal(t, target+1, stateHeight)
+	require.Equal(t, target+1, appHeight)
+}
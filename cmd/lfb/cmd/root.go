@@ -1,22 +1,23 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"text/template"
 
 	"github.com/line/lbm-sdk/baseapp"
 	"github.com/line/lbm-sdk/client"
-	"github.com/line/lbm-sdk/client/debug"
 	"github.com/line/lbm-sdk/client/flags"
 	"github.com/line/lbm-sdk/client/keys"
 	"github.com/line/lbm-sdk/client/rpc"
 	"github.com/line/lbm-sdk/codec"
 	"github.com/line/lbm-sdk/server"
+	srvconfig "github.com/line/lbm-sdk/server/config"
 	servertypes "github.com/line/lbm-sdk/server/types"
-	"github.com/line/lbm-sdk/snapshots"
 	"github.com/line/lbm-sdk/store"
 	sdk "github.com/line/lbm-sdk/types"
 	authclient "github.com/line/lbm-sdk/x/auth/client"
@@ -26,10 +27,14 @@ import (
 	banktypes "github.com/line/lbm-sdk/x/bank/types"
 	"github.com/line/lbm-sdk/x/crisis"
 	genutilcli "github.com/line/lbm-sdk/x/genutil/client/cli"
+	genutiltypes "github.com/line/lbm-sdk/x/genutil/types"
 	"github.com/line/lbm-sdk/x/wasm"
 	lfbtypes "github.com/line/lfb/types"
+	ostcfg "github.com/line/ostracon/config"
 	ostcli "github.com/line/ostracon/libs/cli"
 	"github.com/line/ostracon/libs/log"
+	sm "github.com/line/ostracon/state"
+	ostcstore "github.com/line/ostracon/store"
 	dbm "github.com/line/tm-db/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cast"
@@ -38,11 +43,12 @@ import (
 
 	wasmkeeper "github.com/line/lbm-sdk/x/wasm/keeper"
 	"github.com/line/lfb/app"
+	"github.com/line/lfb/app/indexer"
 	"github.com/line/lfb/app/params"
-)
-
-const (
-	flagTestnet = "testnet"
+	"github.com/line/lfb/app/streaming"
+	"github.com/line/lfb/app/tracing"
+	"github.com/line/lfb/client/config"
+	"github.com/line/lfb/client/health"
 )
 
 // NewRootCmd creates a new root command for simd. It is called once in the
@@ -62,14 +68,25 @@ func NewRootCmd() (*cobra.Command, params.EncodingConfig) {
 	rootCmd := &cobra.Command{
 		Use:   "lfb",
 		Short: "LINE Financial Blockchain (LFB) App",
-		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// fill in the flags not given on the command line from client.toml and the network profile
+			if err := config.ApplyClientConfig(cmd); err != nil {
+				return err
+			}
 			if err := client.SetCmdClientContextHandler(initClientCtx, cmd); err != nil {
 				return err
 			}
-			return lfbPreRunE(cmd)
+			if err := lfbPreRunE(cmd); err != nil {
+				return err
+			}
+			return validateTxArgs(cmd, args)
 		},
 	}
-	rootCmd.PersistentFlags().Bool(flagTestnet, false, "Run with testnet mode. The address prefix becomes tlink if this flag is set.")
+	rootCmd.PersistentFlags().Bool(config.FlagTestnet, false, "Run with testnet mode. The address prefix becomes tlink if this flag is set.")
+	rootCmd.PersistentFlags().String(config.FlagBech32Prefix, "", "Run with a custom network using the given bech32 address prefix instead of link or tlink")
+	rootCmd.PersistentFlags().Uint32(config.FlagCoinType, 0, "The HD coin type of the network; 0 uses the coin type of LINK")
+	rootCmd.PersistentFlags().Bool(flagSkipNetworkCheck, false, "Allow addresses of another network than the selected one in genesis and tx commands")
+	rootCmd.PersistentFlags().String(config.FlagProfile, "", "The network profile of client.toml to use (mainnet|testnet|local|<custom>)")
 
 	initRootCmd(rootCmd, encodingConfig)
 
@@ -81,16 +98,29 @@ func initRootCmd(rootCmd *cobra.Command, encodingConfig params.EncodingConfig) {
 
 	rootCmd.AddCommand(
 		genutilcli.InitCmd(app.ModuleBasics, app.DefaultNodeHome),
-		genutilcli.CollectGenTxsCmd(banktypes.GenesisBalancesIterator{}, app.DefaultNodeHome),
-		genutilcli.GenTxCmd(app.ModuleBasics, encodingConfig.TxConfig, banktypes.GenesisBalancesIterator{}, app.DefaultNodeHome),
+		withGenesisAddressCheck(genutilcli.CollectGenTxsCmd(banktypes.GenesisBalancesIterator{}, app.DefaultNodeHome)),
+		withGenesisAddressCheck(genutilcli.GenTxCmd(app.ModuleBasics, encodingConfig.TxConfig, banktypes.GenesisBalancesIterator{}, app.DefaultNodeHome)),
 		genutilcli.ValidateGenesisCmd(app.ModuleBasics),
 		AddGenesisAccountCmd(app.DefaultNodeHome),
 		ostcli.NewCompletionCmd(rootCmd, true),
 		testnetCmd(app.ModuleBasics, banktypes.GenesisBalancesIterator{}),
-		debug.Cmd(),
+		debugCmd(),
+		config.Cmd(),
+		snapshotsCmd(),
+		pruneCmd(),
+		dbCmd(),
+		rollbackCmd(),
+		signerCmd(),
+		validatorCmd(),
 	)
 
-	server.AddCommands(rootCmd, app.DefaultNodeHome, newApp, createSimappAndExport, addModuleInitFlags)
+	started := &startedApp{}
+	server.AddCommands(rootCmd, app.DefaultNodeHome, started.create, createSimappAndExport, addModuleInitFlags)
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == server.StartCmd(nil, "").Name() {
+			started.closeOnStop(cmd)
+		}
+	}
 
 	// add keybase, auxiliary RPC, query, and tx child commands
 	rootCmd.AddCommand(
@@ -102,6 +132,8 @@ func initRootCmd(rootCmd *cobra.Command, encodingConfig params.EncodingConfig) {
 }
 func addModuleInitFlags(startCmd *cobra.Command) {
 	crisis.AddModuleInitFlags(startCmd)
+	addSafetyCheckFlags(startCmd)
+	startCmd.Flags().Bool(flagSkipSafetyCheck, false, "Start without checking the last signed height of the validator against recent commits")
 }
 
 func queryCommand() *cobra.Command {
@@ -120,6 +152,7 @@ func queryCommand() *cobra.Command {
 		rpc.BlockCommand(),
 		authcmd.QueryTxsByEventsCmd(),
 		authcmd.QueryTxCmd(),
+		querySQLCmd(),
 	)
 
 	app.ModuleBasics.AddQueryCommands(cmd)
@@ -186,15 +219,8 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer, appOpts serverty
 		panic(err)
 	}
 
-	snapshotDir := filepath.Join(cast.ToString(appOpts.Get(flags.FlagHome)), "data", "snapshots")
-	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
-		panic(err)
-	}
-	snapshotDB, err := sdk.NewLevelDB("metadata", snapshotDir)
-	if err != nil {
-		panic(err)
-	}
-	snapshotStore, err := snapshots.NewStore(snapshotDB, snapshotDir)
+	// the metadata database of the snapshot store is used by the app until the process exits
+	snapshotStore, _, err := openSnapshotStore(cast.ToString(appOpts.Get(flags.FlagHome)))
 	if err != nil {
 		panic(err)
 	}
@@ -203,7 +229,7 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer, appOpts serverty
 		wasmOpts = append(wasmOpts, wasmkeeper.WithVMCacheMetrics(prometheus.DefaultRegisterer))
 	}
 
-	return app.NewLinkApp(
+	linkApp := app.NewLinkApp(
 		logger, db, traceStore, true, skipUpgradeHeights,
 		cast.ToString(appOpts.Get(flags.FlagHome)),
 		cast.ToUint(appOpts.Get(server.FlagInvCheckPeriod)),
@@ -223,6 +249,72 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer, appOpts serverty
 		baseapp.SetSnapshotInterval(cast.ToUint64(appOpts.Get(server.FlagStateSyncSnapshotInterval))),
 		baseapp.SetSnapshotKeepRecent(cast.ToUint32(appOpts.Get(server.FlagStateSyncSnapshotKeepRecent))),
 	)
+	if err := catchUpIndexer(linkApp, appOpts); err != nil {
+		logger.Error("failed to index the blocks committed but not indexed; the following blocks are not indexed",
+			"err", err)
+	}
+	return linkApp
+}
+
+// startedApp keeps the app created by the start command to close it once the node stops.
+type startedApp struct {
+	app *app.LinkApp
+}
+
+// create is the AppCreator of the start command.
+func (s *startedApp) create(logger log.Logger, db dbm.DB, traceStore io.Writer, appOpts servertypes.AppOptions) servertypes.Application {
+	linkApp := newApp(logger, db, traceStore, appOpts)
+	s.app, _ = linkApp.(*app.LinkApp)
+	return linkApp
+}
+
+// closeOnStop closes the app when the start command returns, after the SDK has stopped the
+// node and the servers on a quit signal.
+func (s *startedApp) closeOnStop(startCmd *cobra.Command) {
+	runE := startCmd.RunE
+	startCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runE(cmd, args)
+		if s.app != nil {
+			if cerr := s.app.Close(); cerr != nil {
+				server.GetServerContextFromCmd(cmd).Logger.Error("failed to close the app", "err", cerr)
+			}
+		}
+		return err
+	}
+}
+
+// indexerBlockStore is the block store and the state store of ostracon the indexer catches up from.
+type indexerBlockStore struct {
+	*ostcstore.BlockStore
+	sm.Store
+}
+
+// catchUpIndexer indexes the blocks committed but not indexed from the block store of the node,
+// which is not opened by ostracon yet.
+func catchUpIndexer(linkApp *app.LinkApp, appOpts servertypes.AppOptions) error {
+	if !linkApp.IndexerEnabled() {
+		return nil
+	}
+	config := ostcfg.DefaultConfig()
+	config.SetRoot(cast.ToString(appOpts.Get(flags.FlagHome)))
+	if backend := cast.ToString(appOpts.Get("db_backend")); backend != "" {
+		config.DBBackend = backend
+	}
+	if dir := cast.ToString(appOpts.Get("db_dir")); dir != "" {
+		config.DBPath = dir
+	}
+
+	blockStoreDB, err := openOstraconDB(config, "blockstore")
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	stateDB, err := openOstraconDB(config, "state")
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+	return linkApp.CatchUpIndexer(indexerBlockStore{ostcstore.NewBlockStore(blockStoreDB), sm.NewStore(stateDB)})
 }
 
 func createSimappAndExport(
@@ -248,31 +340,224 @@ func createSimappAndExport(
 	return linkApp.ExportAppStateAndValidators(forZeroHeight, jailAllowedAddrs)
 }
 
-func initConfig(testnet bool) {
+func initConfig(network lfbtypes.Network) {
 	config := sdk.GetConfig()
-	config.SetCoinType(lfbtypes.CoinType)
-	config.SetFullFundraiserPath(lfbtypes.FullFundraiserPath)
-	config.SetBech32PrefixForAccount(lfbtypes.Bech32PrefixAcc(testnet), lfbtypes.Bech32PrefixAccPub(testnet))
-	config.SetBech32PrefixForConsensusNode(lfbtypes.Bech32PrefixConsAddr(testnet), lfbtypes.Bech32PrefixConsPub(testnet))
-	config.SetBech32PrefixForValidator(lfbtypes.Bech32PrefixValAddr(testnet), lfbtypes.Bech32PrefixValPub(testnet))
-	config.GetCoinType()
+	network.SetConfig(config)
 	config.Seal()
 }
 
+// networkFromFlags returns the preset network selected by --testnet, overridden
+// by --bech32-prefix and --coin-type.
+func networkFromFlags() (lfbtypes.Network, error) {
+	network := lfbtypes.NetworkOf(viper.GetBool(config.FlagTestnet))
+	if prefix := viper.GetString(config.FlagBech32Prefix); prefix != "" && prefix != network.Bech32Prefix {
+		network.Name = "custom"
+		network.Bech32Prefix = prefix
+	}
+	// `keys add` shadows --coin-type with its own flag whose default is unassigned
+	if coinType := viper.GetUint32(config.FlagCoinType); coinType != 0 && coinType != keys.CoinTypeNotAssigned {
+		network.CoinType = coinType
+	}
+	return network, network.Validate()
+}
+
+// genesisNetwork checks the network against the prefix of the genesis accounts.
+// The prefix of the genesis is adopted if no network is given explicitly, and a
+// mismatch with an explicitly given network is refused, see networkConfigured.
+func genesisNetwork(cmd *cobra.Command, network lfbtypes.Network, genFile string) (lfbtypes.Network, error) {
+	if _, err := os.Stat(genFile); os.IsNotExist(err) {
+		return network, nil
+	}
+	appState, _, err := genutiltypes.GenesisStateFromGenFile(genFile)
+	if err != nil {
+		return network, fmt.Errorf("failed to unmarshal genesis state: %w", err)
+	}
+	cdc, err := clientMarshaler(client.GetClientContextFromCmd(cmd))
+	if err != nil {
+		return network, err
+	}
+	prefix, err := app.GenesisState(appState).Bech32Prefix(cdc)
+	if err != nil {
+		return network, err
+	}
+	if prefix == "" || prefix == network.Bech32Prefix {
+		return network, nil
+	}
+
+	if networkConfigured(cmd) {
+		return network, fmt.Errorf("the configured bech32 prefix %q does not match the prefix %q of the genesis accounts in %s",
+			network.Bech32Prefix, prefix, genFile)
+	}
+	for _, preset := range []lfbtypes.Network{lfbtypes.Mainnet, lfbtypes.Testnet} {
+		if preset.Bech32Prefix == prefix {
+			preset.CoinType = network.CoinType
+			return preset, nil
+		}
+	}
+	network.Name = "custom"
+	network.Bech32Prefix = prefix
+	return network, network.Validate()
+}
+
+// networkConfigured returns whether --testnet or --bech32-prefix is given explicitly: on the
+// command line, by the settings or the profile of client.toml, which fill in the flags, or by
+// an environment variable or a config file read by viper.
+func networkConfigured(cmd *cobra.Command) bool {
+	for _, name := range []string{config.FlagTestnet, config.FlagBech32Prefix} {
+		if cmd.Flags().Changed(name) || viper.IsSet(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// appConfigTemplate is the template of the sections of app.toml which the app reads in
+// addition to those of the SDK, whose template cannot be extended.
+var appConfigTemplate = template.Must(template.New("app.toml").Parse(`
+###############################################################################
+###                          Health Configuration                           ###
+###############################################################################
+
+[health]
+
+# The maximum time since the latest block of a ready node.
+max-block-age = "{{ .Health.MaxBlockAge }}"
+
+# The minimum number of peers of a ready node.
+min-peers = {{ .Health.MinPeers }}
+
+# The number of blocks before halt-height from which on the node is not ready.
+halt-height-margin = {{ .Health.HaltHeightMargin }}
+
+###############################################################################
+###                         Streaming Configuration                         ###
+###############################################################################
+
+[streaming]
+
+# Enable writes the state changes of every block to a file. It cannot be enabled
+# together with state sync snapshots.
+enable = {{ .Streaming.Enable }}
+
+# The directory of the files, relative to the home directory.
+dir = "{{ .Streaming.Dir }}"
+
+# The store keys of the streamed stores, e.g. ["bank", "wasm"]; all if empty.
+keys = [{{ range $i, $key := .Streaming.Keys }}{{ if $i }}, {{ end }}"{{ $key }}"{{ end }}]
+
+###############################################################################
+###                          Indexer Configuration                          ###
+###############################################################################
+
+[indexer]
+
+# Enable indexes the blocks into an SQLite database for 'query sql' and the
+# /lfb/indexer routes of the API server.
+enable = {{ .Indexer.Enable }}
+
+# The path of the database, relative to the home directory.
+path = "{{ .Indexer.Path }}"
+
+###############################################################################
+###                          Tracing Configuration                          ###
+###############################################################################
+
+[tracing]
+
+# Enable traces the execution of the blocks with OpenTelemetry.
+enable = {{ .Tracing.Enable }}
+
+# The exporter of the spans: otlp to an OTLP/HTTP collector, or file.
+exporter = "{{ .Tracing.Exporter }}"
+
+# The host and port of the OTLP/HTTP endpoint of the collector.
+otlp-endpoint = "{{ .Tracing.OTLPEndpoint }}"
+
+# Whether the collector is connected to without TLS.
+otlp-insecure = {{ .Tracing.OTLPInsecure }}
+
+# The file the spans are appended to as JSON lines, relative to the home directory.
+file = "{{ .Tracing.File }}"
+
+# The fraction of the blocks that are traced, from 0 to 1.
+sample-rate = {{ .Tracing.SampleRate }}
+`))
+
+// appConfig is the configuration of the sections of appConfigTemplate.
+type appConfig struct {
+	Health    health.Config
+	Streaming streaming.Config
+	Indexer   indexer.Config
+	Tracing   tracing.Config
+}
+
+func defaultAppConfig() appConfig {
+	return appConfig{
+		Health:    health.DefaultConfig(),
+		Streaming: streaming.DefaultConfig(),
+		Indexer:   indexer.DefaultConfig(),
+		Tracing:   tracing.DefaultConfig(),
+	}
+}
+
+// writeAppConfigFile writes app.toml with the configuration of the SDK and the default
+// configuration of the app.
+func writeAppConfigFile(path string, sdkConfig *srvconfig.Config) error {
+	srvconfig.WriteConfigFile(path, sdkConfig)
+	return appendAppConfig(path)
+}
+
+// appendAppConfig appends the default configuration of the app to an app.toml written by the SDK.
+func appendAppConfig(path string) error {
+	var buf bytes.Buffer
+	if err := appConfigTemplate.Execute(&buf, defaultAppConfig()); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func lfbPreRunE(cmd *cobra.Command) (err error) {
+	// the SDK writes app.toml if it does not exist yet, and the sections of the app are added to it
+	var appConfigPath string
+	if home, _ := cmd.Flags().GetString(flags.FlagHome); home != "" {
+		appConfigPath = filepath.Join(home, "config", "app.toml")
+		if _, serr := os.Stat(appConfigPath); !os.IsNotExist(serr) {
+			appConfigPath = ""
+		}
+	}
 	err = server.InterceptConfigsPreRunHandler(cmd)
+	if err == nil && appConfigPath != "" {
+		if err = appendAppConfig(appConfigPath); err != nil {
+			return err
+		}
+		if err = server.GetServerContextFromCmd(cmd).Viper.MergeInConfig(); err != nil {
+			return err
+		}
+	}
 
-	testnet := viper.GetBool(flagTestnet) // this should be called after initializing cmd
-	initConfig(testnet)
+	network, nerr := networkFromFlags() // this should be called after initializing cmd
+	if nerr != nil {
+		return nerr
+	}
 	ctx := server.GetServerContextFromCmd(cmd)
 	if cmd.Name() == server.StartCmd(nil, "").Name() {
-		var networkMode string
-		if testnet {
-			networkMode = "testnet"
-		} else {
-			networkMode = "mainnet"
+		if network, nerr = genesisNetwork(cmd, network, ctx.Config.GenesisFile()); nerr != nil {
+			return nerr
+		}
+		ctx.Logger.Info(fmt.Sprintf("Network mode is %s (bech32 prefix: %s, coin type: %d)",
+			network.Name, network.Bech32Prefix, network.CoinType))
+		if nerr = startSafetyCheck(cmd, ctx); nerr != nil {
+			return nerr
 		}
-		ctx.Logger.Info(fmt.Sprintf("Network mode is %s", networkMode))
 	}
+	initConfig(network)
 	return
 }
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	octypes "github.com/line/ostracon/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	srvconfig "github.com/line/lbm-sdk/server/config"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/bech32"
+	banktypes "github.com/line/lbm-sdk/x/bank/types"
+
+	"github.com/line/lfb/app"
+	"github.com/line/lfb/app/indexer"
+	"github.com/line/lfb/app/streaming"
+	"github.com/line/lfb/app/tracing"
+	"github.com/line/lfb/client/config"
+	"github.com/line/lfb/client/health"
+	lfbtypes "github.com/line/lfb/types"
+)
+
+func TestAppConfigTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.toml")
+	sdkConfig := srvconfig.DefaultConfig()
+	sdkConfig.MinGasPrices = "0.1stake"
+	require.NoError(t, writeAppConfigFile(path, sdkConfig))
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	require.Equal(t, "0.1stake", v.GetString("minimum-gas-prices"))
+
+	// the sections of the app are read back as their defaults
+	healthConfig, err := health.ReadConfig(v)
+	require.NoError(t, err)
+	require.Equal(t, health.DefaultConfig(), healthConfig)
+	streamingConfig, err := streaming.ReadConfig(v)
+	require.NoError(t, err)
+	require.Equal(t, streaming.DefaultConfig().Dir, streamingConfig.Dir)
+	require.Empty(t, streamingConfig.Keys)
+	indexerConfig, err := indexer.ReadConfig(v)
+	require.NoError(t, err)
+	require.Equal(t, indexer.DefaultConfig(), indexerConfig)
+	tracingConfig, err := tracing.ReadConfig(v)
+	require.NoError(t, err)
+	require.Equal(t, tracing.DefaultConfig(), tracingConfig)
+	for _, key := range []string{
+		health.FlagMaxBlockAge, health.FlagMinPeers, health.FlagHaltHeightMargin,
+		streaming.FlagEnable, streaming.FlagDir, streaming.FlagKeys,
+		indexer.FlagEnable, indexer.FlagPath,
+		tracing.FlagEnable, tracing.FlagExporter, tracing.FlagOTLPEndpoint, tracing.FlagOTLPInsecure,
+		tracing.FlagFile, tracing.FlagSampleRate,
+	} {
+		require.True(t, v.IsSet(key), key)
+	}
+
+	// the values other than the defaults are rendered too
+	config := defaultAppConfig()
+	config.Streaming.Keys = []string{"bank", "wasm"}
+	config.Tracing.SampleRate = 0.25
+	var buf bytes.Buffer
+	require.NoError(t, appConfigTemplate.Execute(&buf, config))
+	v = viper.New()
+	v.SetConfigType("toml")
+	require.NoError(t, v.ReadConfig(&buf))
+	streamingConfig, err = streaming.ReadConfig(v)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bank", "wasm"}, streamingConfig.Keys)
+	tracingConfig, err = tracing.ReadConfig(v)
+	require.NoError(t, err)
+	require.Equal(t, 0.25, tracingConfig.SampleRate)
+}
+
+func TestGenesisNetwork(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	cdc := encodingConfig.Marshaler
+
+	// the genesis accounts of a custom network
+	addr, err := bech32.ConvertAndEncode("cons", secp256k1.GenPrivKey().PubKey().Address())
+	require.NoError(t, err)
+	appState := app.ModuleBasics.DefaultGenesis(cdc)
+	bankGenesis := banktypes.DefaultGenesisState()
+	bankGenesis.Balances = []banktypes.Balance{{Address: addr, Coins: sdk.NewCoins(sdk.NewInt64Coin("stake", 1))}}
+	appState[banktypes.ModuleName] = cdc.MustMarshalJSON(bankGenesis)
+	appStateBz, err := json.Marshal(appState)
+	require.NoError(t, err)
+	genFile := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, (&octypes.GenesisDoc{ChainID: "cons-1", AppState: appStateBz}).SaveAs(genFile))
+
+	t.Cleanup(viper.Reset)
+	// check runs the network checks of start with the flags and client.toml of the home
+	check := func(home string, args ...string) (network lfbtypes.Network, err error) {
+		cmd := &cobra.Command{Use: "start", SilenceErrors: true, SilenceUsage: true}
+		cmd.Flags().String(flags.FlagHome, home, "")
+		cmd.Flags().String(config.FlagProfile, "", "")
+		cmd.Flags().Bool(config.FlagTestnet, false, "")
+		cmd.Flags().String(config.FlagBech32Prefix, "", "")
+		cmd.Flags().Uint32(config.FlagCoinType, 0, "")
+		cmd.RunE = func(cmd *cobra.Command, _ []string) error {
+			// as the executor of the root command binds the flags and the environment
+			viper.Reset()
+			viper.SetEnvPrefix("LFB")
+			viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+			viper.AutomaticEnv()
+			require.NoError(t, viper.BindPFlags(cmd.Flags()))
+
+			require.NoError(t, config.ApplyClientConfig(cmd))
+			network, err = networkFromFlags()
+			require.NoError(t, err)
+			network, err = genesisNetwork(cmd, network, genFile)
+			return nil
+		}
+		cmd.SetArgs(args)
+		clientCtx := client.Context{JSONMarshaler: cdc}
+		require.NoError(t, cmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)))
+		return network, err
+	}
+	homeWith := func(conf *config.ClientConfig) string {
+		home := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(home, "config"), 0755))
+		require.NoError(t, config.WriteConfigToFile(home, conf))
+		return home
+	}
+
+	// the prefix of the genesis is adopted if none is configured
+	network, err := check(t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "cons", network.Bech32Prefix)
+	network, err = check(homeWith(config.DefaultClientConfig()))
+	require.NoError(t, err)
+	require.Equal(t, "cons", network.Bech32Prefix)
+	network, err = check(t.TempDir(), "--"+config.FlagBech32Prefix, "cons")
+	require.NoError(t, err)
+	require.Equal(t, "cons", network.Bech32Prefix)
+
+	// a mismatching prefix is refused wherever it is configured
+	mismatches := map[string]func() (lfbtypes.Network, error){
+		"--testnet": func() (lfbtypes.Network, error) {
+			return check(t.TempDir(), "--"+config.FlagTestnet)
+		},
+		"--bech32-prefix": func() (lfbtypes.Network, error) {
+			return check(t.TempDir(), "--"+config.FlagBech32Prefix, "other")
+		},
+		"--profile": func() (lfbtypes.Network, error) {
+			return check(t.TempDir(), "--"+config.FlagProfile, config.ProfileLocal)
+		},
+		"profile of client.toml": func() (lfbtypes.Network, error) {
+			conf := config.DefaultClientConfig()
+			conf.Profile = config.ProfileTestnet
+			return check(homeWith(conf))
+		},
+		"settings of client.toml": func() (lfbtypes.Network, error) {
+			conf := config.DefaultClientConfig()
+			conf.Settings.Bech32Prefix = "other"
+			return check(homeWith(conf))
+		},
+		"environment": func() (lfbtypes.Network, error) {
+			require.NoError(t, os.Setenv("LFB_BECH32_PREFIX", "link"))
+			defer os.Unsetenv("LFB_BECH32_PREFIX")
+			return check(t.TempDir())
+		},
+	}
+	for name, mismatch := range mismatches {
+		_, err := mismatch()
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), `does not match the prefix "cons" of the genesis accounts`, name)
+	}
+}
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	ostcfg "github.com/line/ostracon/config"
+	"github.com/line/ostracon/crypto"
+	ostjson "github.com/line/ostracon/libs/json"
+	ostos "github.com/line/ostracon/libs/os"
+	"github.com/line/ostracon/privval"
+	osthttp "github.com/line/ostracon/rpc/client/http"
+	ostcstore "github.com/line/ostracon/store"
+	osttypes "github.com/line/ostracon/types"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/server"
+)
+
+const (
+	flagSkipSafetyCheck       = "skip-safety-check"
+	flagSafetyCheckRPCServers = "safety-check-rpc-servers"
+	flagSafetyCheckBlocks     = "safety-check-blocks"
+
+	// defaultSafetyCheckBlocks is the default number of recent blocks searched for signatures of the validator.
+	defaultSafetyCheckBlocks = 20
+	// safetyCheckTimeout is the timeout of querying the recent commits of a peer.
+	safetyCheckTimeout = 10 * time.Second
+
+	// stepPrecommit is the step of the precommits in the sign state, whose signatures the commits have.
+	stepPrecommit int8 = 3
+)
+
+// commitSignature is the latest signature of the validator in the commits of a source.
+type commitSignature struct {
+	Source string
+	Height int64
+	Round  int32
+}
+
+// safetyReport compares the last sign state of a validator with its latest signatures
+// in the commits of the local block store and of peers.
+type safetyReport struct {
+	// Skipped is the reason why the validator was not checked, if it was not.
+	Skipped string
+
+	Address          crypto.Address
+	LastSign         privval.FilePVLastSignState
+	BlockStoreHeight int64
+	// Signatures are the latest signatures of the validator at or after the last
+	// signed height, at most one per source.
+	Signatures []commitSignature
+	// PeersConfigured is the number of RPC servers of peers given to the check.
+	PeersConfigured int
+	// PeersQueried is the number of peers whose recent commits were searched.
+	PeersQueried int
+	// PeerErrors are the errors of the peers which could not be queried.
+	PeerErrors map[string]error
+}
+
+// Conflicts returns the signatures after the last signed height, round and step, which the
+// validator could sign again differently. The signatures of the commits are precommits, so a
+// last sign state at an earlier step of the same height and round is behind them.
+func (r safetyReport) Conflicts() []commitSignature {
+	var conflicts []commitSignature
+	last := r.LastSign
+	for _, sig := range r.Signatures {
+		if sig.Height > last.Height || (sig.Height == last.Height && sig.Round > last.Round) ||
+			(sig.Height == last.Height && sig.Round == last.Round && last.Step < stepPrecommit) {
+			conflicts = append(conflicts, sig)
+		}
+	}
+	return conflicts
+}
+
+// signed returns whether the validator signed anything according to its sign state or to the
+// block store.
+func (r safetyReport) signed() bool {
+	return r.LastSign.Height > 0 || len(r.Signatures) > 0
+}
+
+// Err returns an error describing the first conflict, if any, or that none of the configured
+// peers could be queried for the signatures of a validator that signed before: the block store
+// of a restored backup does not have the signatures made after the backup.
+func (r safetyReport) Err() error {
+	if conflicts := r.Conflicts(); len(conflicts) > 0 {
+		c := conflicts[0]
+		return fmt.Errorf("validator %s signed height %d round %d according to %s, but priv_validator_state.json "+
+			"was last signed at height %d round %d step %d; signing could conflict, e.g. after restoring a backup. "+
+			"Restore the latest priv_validator_state.json or wait for the chain to pass the height, "+
+			"and start with --%s only if the other signatures can never be repeated",
+			r.Address, c.Height, c.Round, c.Source, r.LastSign.Height, r.LastSign.Round, r.LastSign.Step, flagSkipSafetyCheck)
+	}
+	if r.PeersConfigured > 0 && r.PeersQueried == 0 && r.signed() {
+		return fmt.Errorf("none of the %d peers could be queried for the recent signatures of validator %s, so the "+
+			"signatures missing from the block store, e.g. after restoring a backup, can't be checked. "+
+			"Fix statesync.rpc_servers in config.toml or --%s, or start with --%s only if priv_validator_state.json "+
+			"is known to be up to date",
+			r.PeersConfigured, r.Address, flagSafetyCheckRPCServers, flagSkipSafetyCheck)
+	}
+	return nil
+}
+
+// Warning returns a warning if the validator signed before and no peer is configured, so
+// only the block store was searched for its signatures.
+func (r safetyReport) Warning() string {
+	if r.PeersConfigured == 0 && r.signed() {
+		return fmt.Sprintf("no peer is configured, so only the block store was searched for the signatures of the "+
+			"validator; set statesync.rpc_servers in config.toml or --%s to also detect the signatures missing "+
+			"from a restored backup", flagSafetyCheckRPCServers)
+	}
+	return ""
+}
+
+// checkSigningSafety loads the last sign state of the validator of the node and searches
+// its signatures in the last blocks of the block store and of the given RPC servers.
+func checkSigningSafety(config *ostcfg.Config, rpcServers []string, blocks int64) (*safetyReport, error) {
+	report := &safetyReport{PeerErrors: map[string]error{}}
+	if config.PrivValidatorListenAddr != "" {
+		report.Skipped = fmt.Sprintf("the node signs with a remote signer on %s, which keeps its own sign state", config.PrivValidatorListenAddr)
+		return report, nil
+	}
+	if !ostos.FileExists(config.PrivValidatorKeyFile()) {
+		report.Skipped = fmt.Sprintf("%s not found; the node has not signed anything", config.PrivValidatorKeyFile())
+		return report, nil
+	}
+
+	var key privval.FilePVKey
+	if err := readJSONFile(config.PrivValidatorKeyFile(), &key); err != nil {
+		return nil, err
+	}
+	report.Address = key.PubKey.Address()
+	// a missing state file is created empty on start
+	if ostos.FileExists(config.PrivValidatorStateFile()) {
+		if err := readJSONFile(config.PrivValidatorStateFile(), &report.LastSign); err != nil {
+			return nil, err
+		}
+	}
+
+	blockStoreDB, err := openOstraconDB(config, "blockstore")
+	if err != nil {
+		return nil, err
+	}
+	defer blockStoreDB.Close()
+	blockStore := ostcstore.NewBlockStore(blockStoreDB)
+	report.BlockStoreHeight = blockStore.Height()
+
+	sig, err := latestSignature(report.Address, blockStore.Height(), lowestHeight(blockStore.Height(), blocks, report.LastSign.Height),
+		func(height int64) (*osttypes.Commit, error) {
+			if height == blockStore.Height() {
+				return blockStore.LoadSeenCommit(height), nil
+			}
+			return blockStore.LoadBlockCommit(height), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if sig != nil {
+		sig.Source = "the block store"
+		report.Signatures = append(report.Signatures, *sig)
+	}
+
+	report.PeersConfigured = len(rpcServers)
+	for _, rpcServer := range rpcServers {
+		sig, err := peerLatestSignature(rpcServer, report.Address, blocks, report.LastSign.Height)
+		if err != nil {
+			report.PeerErrors[rpcServer] = err
+			continue
+		}
+		report.PeersQueried++
+		if sig != nil {
+			sig.Source = rpcServer
+			report.Signatures = append(report.Signatures, *sig)
+		}
+	}
+	return report, nil
+}
+
+// peerLatestSignature searches the signatures of the validator in the last blocks of the
+// chain served by the RPC server.
+func peerLatestSignature(rpcServer string, address crypto.Address, blocks, lastSignHeight int64) (*commitSignature, error) {
+	client, err := osthttp.New(rpcServer, "/websocket")
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), safetyCheckTimeout)
+	defer cancel()
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+	return latestSignature(address, latest, lowestHeight(latest, blocks, lastSignHeight), func(height int64) (*osttypes.Commit, error) {
+		res, err := client.Commit(ctx, &height)
+		if err != nil {
+			return nil, err
+		}
+		return res.Commit, nil
+	})
+}
+
+// lowestHeight returns the lowest height to search for signatures below the latest height:
+// signatures before the last signed height can't conflict.
+func lowestHeight(latest, blocks, lastSignHeight int64) int64 {
+	lowest := latest - blocks + 1
+	if lowest < lastSignHeight {
+		lowest = lastSignHeight
+	}
+	if lowest < 1 {
+		lowest = 1
+	}
+	return lowest
+}
+
+// latestSignature returns the signature of the validator in the commit of the highest
+// height from latest down to lowest, or nil if it signed none of them.
+func latestSignature(address crypto.Address, latest, lowest int64, loadCommit func(height int64) (*osttypes.Commit, error)) (*commitSignature, error) {
+	for height := latest; height >= lowest; height-- {
+		commit, err := loadCommit(height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the commit of height %d: %w", height, err)
+		}
+		if commit == nil {
+			continue
+		}
+		for _, sig := range commit.Signatures {
+			if sig.BlockIDFlag != osttypes.BlockIDFlagAbsent && bytes.Equal(sig.ValidatorAddress, address) {
+				return &commitSignature{Height: commit.Height, Round: commit.Round}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func readJSONFile(path string, v interface{}) error {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := ostjson.Unmarshal(bz, v); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// safetyCheckRPCServers returns the RPC servers of the flag, or the state sync RPC servers
+// of config.toml if the flag is not given.
+func safetyCheckRPCServers(cmd *cobra.Command, config *ostcfg.Config) []string {
+	servers := config.StateSync.RPCServers
+	if flag, _ := cmd.Flags().GetString(flagSafetyCheckRPCServers); flag != "" {
+		servers = strings.Split(flag, ",")
+	}
+	var rpcServers []string
+	for _, server := range servers {
+		if server = strings.TrimSpace(server); server != "" {
+			rpcServers = append(rpcServers, server)
+		}
+	}
+	return rpcServers
+}
+
+// addSafetyCheckFlags adds the flags of the signing safety check to the command.
+func addSafetyCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagSafetyCheckRPCServers, "", "Comma separated RPC servers of peers whose recent commits are searched for signatures of the validator (default: statesync.rpc_servers of config.toml)")
+	cmd.Flags().Int64(flagSafetyCheckBlocks, defaultSafetyCheckBlocks, "The number of recent blocks searched for signatures of the validator")
+}
+
+// startSafetyCheck refuses to start a validator node whose last sign state is behind
+// its signatures in recent commits.
+func startSafetyCheck(cmd *cobra.Command, serverCtx *server.Context) error {
+	if skip, _ := cmd.Flags().GetBool(flagSkipSafetyCheck); skip {
+		serverCtx.Logger.Info("Skipping the signing safety check")
+		return nil
+	}
+	blocks, _ := cmd.Flags().GetInt64(flagSafetyCheckBlocks)
+	report, err := checkSigningSafety(serverCtx.Config, safetyCheckRPCServers(cmd, serverCtx.Config), blocks)
+	if err != nil {
+		return fmt.Errorf("signing safety check failed: %w (use --%s to start anyway)", err, flagSkipSafetyCheck)
+	}
+	if report.Skipped != "" {
+		serverCtx.Logger.Info("Skipping the signing safety check", "reason", report.Skipped)
+		return nil
+	}
+	for rpcServer, err := range report.PeerErrors {
+		serverCtx.Logger.Error("Failed to query the recent commits of a peer for the signing safety check", "server", rpcServer, "err", err)
+	}
+	if err := report.Err(); err != nil {
+		return err
+	}
+	if warning := report.Warning(); warning != "" {
+		serverCtx.Logger.Error("Signing safety check is incomplete", "reason", warning)
+	}
+	serverCtx.Logger.Info("Signing safety check passed", "validator", report.Address,
+		"last_signed_height", report.LastSign.Height, "block_store_height", report.BlockStoreHeight,
+		"peers_queried", report.PeersQueried)
+	return nil
+}
+
+// validatorCmd returns the validator operation commands.
+func validatorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator",
+		Short: "Validator operation subcommands",
+	}
+	cmd.AddCommand(safetyCheckCmd())
+	return cmd
+}
+
+// safetyCheckCmd returns the command to check whether the validator of a stopped node can start signing safely.
+func safetyCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "safety-check",
+		Short: "Check that the validator can't double-sign on start",
+		Long: `Compare the last signed height, round and step in priv_validator_state.json with the
+signatures of the validator in the recent commits of the block store and of peers, e.g. after
+restoring a node from a backup. The check fails if the validator precommitted a later height
+or round, or the same height and round at a later step, than priv_validator_state.json
+records, as it could sign that height again differently. It also fails if the validator
+signed before and none of the configured peers could be queried, as the signatures made after
+a backup are not in its block store. Without any peer configured, only the block store is
+searched and a warning is printed.
+
+The same check runs before "lfb start", which refuses to start if it fails unless
+--skip-safety-check is given. A node with a remote signer is not checked.
+
+The node must not be running while this command is used.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+			blocks, _ := cmd.Flags().GetInt64(flagSafetyCheckBlocks)
+			report, err := checkSigningSafety(config, safetyCheckRPCServers(cmd, config), blocks)
+			if err != nil {
+				return err
+			}
+			if report.Skipped != "" {
+				cmd.Printf("skipped: %s\n", report.Skipped)
+				return nil
+			}
+
+			cmd.Printf("validator: %s\n", report.Address)
+			cmd.Printf("last signed in priv_validator_state.json: height %d round %d step %d\n",
+				report.LastSign.Height, report.LastSign.Round, report.LastSign.Step)
+			cmd.Printf("block store height: %d\n", report.BlockStoreHeight)
+			cmd.Printf("peers queried: %d\n", report.PeersQueried)
+			for _, sig := range report.Signatures {
+				cmd.Printf("latest signature in %s: height %d round %d\n", sig.Source, sig.Height, sig.Round)
+			}
+			for rpcServer, err := range report.PeerErrors {
+				cmd.Printf("failed to query %s: %s\n", rpcServer, err)
+			}
+			if err := report.Err(); err != nil {
+				return err
+			}
+			if warning := report.Warning(); warning != "" {
+				cmd.Printf("warning: %s\n", warning)
+			}
+			cmd.Println("ok: no signature after the last signed height, round and step")
+			return nil
+		},
+	}
+	addSafetyCheckFlags(cmd)
+	return cmd
+}
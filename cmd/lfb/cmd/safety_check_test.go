@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	ostcfg "github.com/line/ostracon/config"
+	"github.com/line/ostracon/crypto/ed25519"
+	ostos "github.com/line/ostracon/libs/os"
+	"github.com/line/ostracon/privval"
+	osttypes "github.com/line/ostracon/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestSignature(t *testing.T) {
+	address := ed25519.GenPrivKey().PubKey().Address()
+	other := ed25519.GenPrivKey().PubKey().Address()
+
+	// the validator signed every height up to 7, and was absent at 8 and 9
+	commits := map[int64]*osttypes.Commit{}
+	for height := int64(1); height <= 9; height++ {
+		sigs := []osttypes.CommitSig{{BlockIDFlag: osttypes.BlockIDFlagCommit, ValidatorAddress: other}}
+		if height <= 7 {
+			sigs = append(sigs, osttypes.CommitSig{BlockIDFlag: osttypes.BlockIDFlagCommit, ValidatorAddress: address})
+		} else {
+			sigs = append(sigs, osttypes.NewCommitSigAbsent())
+		}
+		commits[height] = &osttypes.Commit{Height: height, Round: int32(height % 2), Signatures: sigs}
+	}
+	load := func(height int64) (*osttypes.Commit, error) { return commits[height], nil }
+
+	sig, err := latestSignature(address, 9, 1, load)
+	require.NoError(t, err)
+	require.Equal(t, &commitSignature{Height: 7, Round: 1}, sig)
+
+	sig, err = latestSignature(address, 9, 8, load)
+	require.NoError(t, err)
+	require.Nil(t, sig)
+
+	require.Equal(t, int64(5), lowestHeight(9, 5, 0))
+	require.Equal(t, int64(7), lowestHeight(9, 5, 7))
+	require.Equal(t, int64(1), lowestHeight(3, 5, 0))
+}
+
+func TestSafetyReportConflicts(t *testing.T) {
+	report := safetyReport{
+		LastSign:     privval.FilePVLastSignState{Height: 10, Round: 1, Step: stepPrecommit},
+		PeersQueried: 1,
+		Signatures: []commitSignature{
+			{Source: "the block store", Height: 10, Round: 1},
+			{Source: "peer", Height: 9, Round: 3},
+		},
+	}
+	require.Empty(t, report.Conflicts())
+	require.NoError(t, report.Err())
+
+	report.Signatures = append(report.Signatures,
+		commitSignature{Source: "peer", Height: 10, Round: 2},
+		commitSignature{Source: "peer", Height: 11, Round: 0},
+	)
+	require.Len(t, report.Conflicts(), 2)
+	require.Error(t, report.Err())
+}
+
+func TestSafetyReportStep(t *testing.T) {
+	// the commit has the precommit of the validator at height 10 round 1
+	report := safetyReport{
+		Signatures:   []commitSignature{{Source: "peer", Height: 10, Round: 1}},
+		PeersQueried: 1,
+	}
+	for step, conflict := range map[int8]bool{0: true, 1: true, 2: true, stepPrecommit: false} {
+		report.LastSign = privval.FilePVLastSignState{Height: 10, Round: 1, Step: step}
+		require.Equal(t, conflict, len(report.Conflicts()) > 0, "step %d", step)
+		require.Equal(t, conflict, report.Err() != nil, "step %d", step)
+	}
+}
+
+func TestSafetyReportNoPeers(t *testing.T) {
+	// a validator that never signed has nothing to check
+	report := safetyReport{PeerErrors: map[string]error{}}
+	require.NoError(t, report.Err())
+	require.Empty(t, report.Warning())
+
+	// without any peer configured, a validator that signed is only checked against the block store
+	report.LastSign = privval.FilePVLastSignState{Height: 10, Round: 0, Step: stepPrecommit}
+	require.NoError(t, report.Err())
+	require.NotEmpty(t, report.Warning())
+	report.LastSign = privval.FilePVLastSignState{}
+	report.Signatures = []commitSignature{{Source: "the block store", Height: 10}}
+	require.Error(t, report.Err())
+
+	// the signatures after a backup are only known to the peers, so the configured ones must answer
+	report.LastSign = privval.FilePVLastSignState{Height: 10, Round: 0, Step: stepPrecommit}
+	report.PeersConfigured = 2
+	require.Error(t, report.Err())
+	report.PeersQueried = 1
+	require.NoError(t, report.Err())
+	require.Empty(t, report.Warning())
+
+	// with a peer, the signature in the block store still conflicts with the empty sign state
+	report.LastSign = privval.FilePVLastSignState{}
+	require.Error(t, report.Err())
+}
+
+func TestCheckSigningSafety(t *testing.T) {
+	config := ostcfg.TestConfig()
+	config.SetRoot(t.TempDir())
+
+	// nothing to check without a key
+	report, err := checkSigningSafety(config, nil, defaultSafetyCheckBlocks)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Skipped)
+
+	require.NoError(t, ostos.EnsureDir(filepath.Join(config.RootDir, "config"), 0755))
+	require.NoError(t, ostos.EnsureDir(filepath.Join(config.RootDir, "data"), 0755))
+	pv, err := privval.GenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile(), config.PrivKeyType)
+	require.NoError(t, err)
+	pv.Save()
+	report, err = checkSigningSafety(config, nil, defaultSafetyCheckBlocks)
+	require.NoError(t, err)
+	require.Empty(t, report.Skipped)
+	require.Equal(t, pv.GetAddress(), report.Address)
+	require.Zero(t, report.BlockStoreHeight)
+	require.NoError(t, report.Err())
+
+	// a validator that signed before restarts without any peer configured, e.g. a lone validator
+	pv.LastSignState.Height, pv.LastSignState.Step = 5, stepPrecommit
+	pv.Save()
+	report, err = checkSigningSafety(config, nil, defaultSafetyCheckBlocks)
+	require.NoError(t, err)
+	require.Zero(t, report.PeersConfigured)
+	require.NoError(t, report.Err())
+	require.NotEmpty(t, report.Warning())
+
+	// but can't be checked if none of the configured peers answers
+	report, err = checkSigningSafety(config, []string{"tcp://127.0.0.1:1"}, defaultSafetyCheckBlocks)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.PeersConfigured)
+	require.Zero(t, report.PeersQueried)
+	require.Len(t, report.PeerErrors, 1)
+	require.Error(t, report.Err())
+
+	// the remote signer keeps the sign state
+	config.PrivValidatorListenAddr = "tcp://127.0.0.1:26659"
+	report, err = checkSigningSafety(config, nil, defaultSafetyCheckBlocks)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Skipped)
+}
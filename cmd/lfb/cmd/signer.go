@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/server"
+
+	"github.com/line/lfb/signer"
+)
+
+const flagSignerAddr = "addr"
+
+// signerCmd returns the command to run a remote signer for a validator node.
+func signerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signer",
+		Short: "Run a remote signer for a validator node",
+		Long: `Run a remote signer which dials the priv_validator_laddr of a validator node and
+signs its votes and proposals over the privval socket protocol of ostracon.
+
+The signer uses config/priv_validator_key.json and data/priv_validator_state.json in its
+home directory, and refuses to sign conflicting votes and proposals for a height it has
+already signed. It redials the node whenever the connection is lost.
+
+Example:
+	lfb signer --home ./mytestnet/node0/signer --addr tcp://192.168.0.1:26659 --chain-id testchain
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+
+			addr, _ := cmd.Flags().GetString(flagSignerAddr)       // nolint: errcheck
+			chainID, _ := cmd.Flags().GetString(flags.FlagChainID) // nolint: errcheck
+			if addr == "" {
+				return fmt.Errorf("--%s is required", flagSignerAddr)
+			}
+
+			s, err := signer.New(serverCtx.Logger, addr, chainID, config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+			if err != nil {
+				return err
+			}
+			if err := s.Start(); err != nil {
+				return err
+			}
+
+			server.WaitForQuitSignals()
+			return s.Stop()
+		},
+	}
+
+	cmd.Flags().String(flagSignerAddr, "", "The priv_validator_laddr of the validator node to dial (tcp://<host>:<port> or unix://<path>)")
+	cmd.Flags().String(flags.FlagChainID, "", "The chain id of the network")
+	return cmd
+}
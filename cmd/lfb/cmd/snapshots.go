@@ -0,0 +1,474 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/light"
+	sm "github.com/line/ostracon/state"
+	"github.com/line/ostracon/statesync"
+	ostcstore "github.com/line/ostracon/store"
+	octypes "github.com/line/ostracon/types"
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/baseapp"
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/server"
+	"github.com/line/lbm-sdk/snapshots"
+	snapshottypes "github.com/line/lbm-sdk/snapshots/types"
+
+	"github.com/line/lfb/app"
+)
+
+const (
+	flagSnapshotFormat = "format"
+	flagAppOnly        = "app-only"
+
+	snapshotArchiveMetadata = "metadata"
+)
+
+// snapshotsCmd returns the command to manage the state-sync snapshots of a stopped node.
+func snapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "Manage the state-sync snapshots of a node offline",
+		Long: `Manage the state-sync snapshots stored under data/snapshots of the node home.
+The node must not be running while these commands are used.`,
+		RunE: client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		listSnapshotsCmd(),
+		exportSnapshotCmd(),
+		importSnapshotCmd(),
+		deleteSnapshotCmd(),
+		restoreSnapshotCmd(),
+	)
+	cmd.PersistentFlags().Uint32(flagSnapshotFormat, snapshottypes.CurrentFormat, "The snapshot format")
+
+	return cmd
+}
+
+func listSnapshotsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the local snapshots",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, snapshotDB, err := openSnapshotStore(client.GetClientContextFromCmd(cmd).HomeDir)
+			if err != nil {
+				return err
+			}
+			defer snapshotDB.Close()
+			list, err := store.List()
+			if err != nil {
+				return err
+			}
+			for _, s := range list {
+				cmd.Printf("height: %d format: %d chunks: %d hash: %X\n", s.Height, s.Format, s.Chunks, s.Hash)
+			}
+			return nil
+		},
+	}
+}
+
+func exportSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [height] [archive]",
+		Short: "Export a local snapshot to a single archive file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, format, err := parseSnapshotID(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			store, snapshotDB, err := openSnapshotStore(client.GetClientContextFromCmd(cmd).HomeDir)
+			if err != nil {
+				return err
+			}
+			defer snapshotDB.Close()
+			snapshot, chunks, err := store.Load(height, format)
+			if err != nil {
+				return err
+			}
+			if snapshot == nil {
+				return fmt.Errorf("snapshot of height %d format %d not found", height, format)
+			}
+			defer snapshots.DrainChunks(chunks)
+
+			file, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			if err := writeSnapshotArchive(file, snapshot, chunks); err != nil {
+				return err
+			}
+			cmd.Printf("exported snapshot of height %d format %d with %d chunks to %s\n",
+				snapshot.Height, snapshot.Format, snapshot.Chunks, args[1])
+			return file.Close()
+		},
+	}
+	return cmd
+}
+
+func importSnapshotCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [archive]",
+		Short: "Import a snapshot archive created by export into the local snapshot store",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, snapshotDB, err := openSnapshotStore(client.GetClientContextFromCmd(cmd).HomeDir)
+			if err != nil {
+				return err
+			}
+			defer snapshotDB.Close()
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			snapshot, err := readSnapshotArchive(file, store)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("imported snapshot of height %d format %d with %d chunks\n", snapshot.Height, snapshot.Format, snapshot.Chunks)
+			return nil
+		},
+	}
+}
+
+func deleteSnapshotCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [height]",
+		Short: "Delete a local snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, format, err := parseSnapshotID(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			store, snapshotDB, err := openSnapshotStore(client.GetClientContextFromCmd(cmd).HomeDir)
+			if err != nil {
+				return err
+			}
+			defer snapshotDB.Close()
+			if snapshot, err := store.Get(height, format); err != nil {
+				return err
+			} else if snapshot == nil {
+				return fmt.Errorf("snapshot of height %d format %d not found", height, format)
+			}
+			return store.Delete(height, format)
+		},
+	}
+}
+
+func restoreSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [height]",
+		Short: "Restore the application state from a local snapshot into an empty data directory",
+		Long: `Restore the application state from a local snapshot into an empty data directory,
+so that the node starts at the height of the snapshot without state sync over p2p.
+
+The ostracon state and the commit at the height are fetched and verified with a
+light client, using rpc_servers, trust_height, trust_hash and trust_period of the
+[statesync] section of config.toml. With --app-only, only the application state
+is restored and the ostracon state must be bootstrapped separately.
+
+Note that wasm code binaries are not part of snapshots; they are stored under the
+wasm directory of the node home and have to be copied from another node.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, format, err := parseSnapshotID(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			appOnly, _ := cmd.Flags().GetBool(flagAppOnly)
+
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+			home := config.RootDir
+
+			blockStoreDB, err := openOstraconDB(config, "blockstore")
+			if err != nil {
+				return err
+			}
+			defer blockStoreDB.Close()
+			blockStore := ostcstore.NewBlockStore(blockStoreDB)
+			if !appOnly && blockStore.Height() != 0 {
+				return fmt.Errorf("the block store is not empty; it has blocks up to height %d", blockStore.Height())
+			}
+
+			// fetch the ostracon state before touching the application database,
+			// so that a misconfigured light client does not leave a half restored node
+			var (
+				state  sm.State
+				commit *octypes.Commit
+			)
+			if !appOnly {
+				if state, commit, err = fetchStateAndCommit(cmd.Context(), serverCtx, height); err != nil {
+					return err
+				}
+			}
+
+			store, snapshotDB, err := openSnapshotStore(home)
+			if err != nil {
+				return err
+			}
+			defer snapshotDB.Close()
+			snapshot, chunks, err := store.Load(height, format)
+			if err != nil {
+				return err
+			}
+			if snapshot == nil {
+				return fmt.Errorf("snapshot of height %d format %d not found", height, format)
+			}
+			defer snapshots.DrainChunks(chunks)
+
+			db, err := openAppDB(home)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			linkApp := app.NewLinkApp(
+				serverCtx.Logger, db, nil, true, map[int64]bool{}, home, 0, app.MakeEncodingConfig(),
+				serverCtx.Viper, nil, baseapp.SetSnapshotStore(store),
+			)
+			if linkApp.LastBlockHeight() != 0 {
+				return fmt.Errorf("the application database is not empty; it is at height %d", linkApp.LastBlockHeight())
+			}
+
+			if err := restoreSnapshot(linkApp, snapshot, chunks); err != nil {
+				return err
+			}
+			commitID := linkApp.LastCommitID()
+			cmd.Printf("restored application state at height %d with app hash %X\n", commitID.Version, commitID.Hash)
+			if appOnly {
+				return nil
+			}
+
+			if !bytes.Equal(state.AppHash, commitID.Hash) {
+				return fmt.Errorf("app hash of the restored state %X does not match the app hash %X verified by the light client",
+					commitID.Hash, state.AppHash)
+			}
+
+			stateDB, err := openOstraconDB(config, "state")
+			if err != nil {
+				return err
+			}
+			defer stateDB.Close()
+			if err := sm.NewStore(stateDB).Bootstrap(state); err != nil {
+				return fmt.Errorf("failed to bootstrap ostracon state: %w", err)
+			}
+			if err := blockStore.SaveSeenCommit(state.LastBlockHeight, commit); err != nil {
+				return fmt.Errorf("failed to store the commit of height %d: %w", state.LastBlockHeight, err)
+			}
+			cmd.Printf("bootstrapped ostracon state at height %d\n", state.LastBlockHeight)
+			return nil
+		},
+	}
+	cmd.Flags().Bool(flagAppOnly, false, "Restore the application state only, without bootstrapping the ostracon state")
+	return cmd
+}
+
+func parseSnapshotID(cmd *cobra.Command, heightArg string) (uint64, uint32, error) {
+	height, err := strconv.ParseUint(heightArg, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid height %q: %w", heightArg, err)
+	}
+	format, err := cmd.Flags().GetUint32(flagSnapshotFormat)
+	return height, format, err
+}
+
+// restoreSnapshot feeds the snapshot to the application through ABCI, the same way state sync does.
+func restoreSnapshot(linkApp *app.LinkApp, snapshot *snapshottypes.Snapshot, chunks <-chan io.ReadCloser) error {
+	abciSnapshot, err := snapshot.ToABCI()
+	if err != nil {
+		return err
+	}
+	offer := linkApp.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: &abciSnapshot})
+	if offer.Result != abci.ResponseOfferSnapshot_ACCEPT {
+		return fmt.Errorf("the application did not accept the snapshot: %s", offer.Result)
+	}
+
+	index := uint32(0)
+	for chunk := range chunks {
+		bz, err := ioutil.ReadAll(chunk)
+		chunk.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+		res := linkApp.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: index, Chunk: bz})
+		if res.Result != abci.ResponseApplySnapshotChunk_ACCEPT {
+			return fmt.Errorf("the application did not accept chunk %d: %s", index, res.Result)
+		}
+		index++
+	}
+	if index != snapshot.Chunks {
+		return fmt.Errorf("the snapshot has %d chunks, but %d are stored", snapshot.Chunks, index)
+	}
+	return nil
+}
+
+// fetchStateAndCommit returns the ostracon state and commit of the height verified with a light client.
+func fetchStateAndCommit(ctx context.Context, serverCtx *server.Context, height uint64) (sm.State, *octypes.Commit, error) {
+	config := serverCtx.Config
+	syncConfig := config.StateSync
+	if len(syncConfig.RPCServers) < 2 || syncConfig.TrustHeight <= 0 || syncConfig.TrustHash == "" {
+		return sm.State{}, nil, errors.New("rpc_servers (at least 2), trust_height and trust_hash of [statesync] in config.toml " +
+			"are required to bootstrap the ostracon state; use --app-only to restore the application state only")
+	}
+
+	genDoc, err := octypes.GenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+	genState, err := sm.MakeGenesisState(genDoc)
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+
+	provider, err := statesync.NewLightClientStateProvider(ctx, genDoc.ChainID, genState.Version, genDoc.InitialHeight,
+		syncConfig.RPCServers, light.TrustOptions{
+			Period: syncConfig.TrustPeriod,
+			Height: syncConfig.TrustHeight,
+			Hash:   syncConfig.TrustHashBytes(),
+		}, serverCtx.Logger.With("module", "light"))
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("failed to set up light client state provider: %w", err)
+	}
+	state, err := provider.State(ctx, height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("failed to fetch the ostracon state of height %d: %w", height, err)
+	}
+	commit, err := provider.Commit(ctx, height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("failed to fetch the commit of height %d: %w", height, err)
+	}
+	return state, commit, nil
+}
+
+// writeSnapshotArchive writes the snapshot metadata and its chunks as a gzipped tar archive.
+func writeSnapshotArchive(w io.Writer, snapshot *snapshottypes.Snapshot, chunks <-chan io.ReadCloser) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	metadata, err := proto.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveEntry(tw, snapshotArchiveMetadata, metadata); err != nil {
+		return err
+	}
+
+	index := uint32(0)
+	for chunk := range chunks {
+		bz, err := ioutil.ReadAll(chunk)
+		chunk.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+		if err := writeArchiveEntry(tw, strconv.FormatUint(uint64(index), 10), bz); err != nil {
+			return err
+		}
+		index++
+	}
+	if index != snapshot.Chunks {
+		return fmt.Errorf("the snapshot has %d chunks, but %d are stored", snapshot.Chunks, index)
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, bz []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(bz))}); err != nil {
+		return err
+	}
+	_, err := tw.Write(bz)
+	return err
+}
+
+// readSnapshotArchive saves the snapshot of an archive written by writeSnapshotArchive
+// into the store, and verifies the hashes of the saved snapshot against the archive.
+func readSnapshotArchive(r io.Reader, store *snapshots.Store) (*snapshottypes.Snapshot, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gzr)
+
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot archive: %w", err)
+	}
+	if header.Name != snapshotArchiveMetadata {
+		return nil, fmt.Errorf("invalid snapshot archive: expected %s as the first entry, got %s", snapshotArchiveMetadata, header.Name)
+	}
+	bz, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return nil, err
+	}
+	var expected snapshottypes.Snapshot
+	if err := proto.Unmarshal(bz, &expected); err != nil {
+		return nil, fmt.Errorf("invalid snapshot metadata: %w", err)
+	}
+
+	// done stops the reader when this function returns, whether or not the store
+	// consumed every chunk
+	chunks := make(chan io.ReadCloser)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(chunks)
+		for index := uint32(0); index < expected.Chunks; index++ {
+			header, err := tr.Next()
+			if err == nil && header.Name != strconv.FormatUint(uint64(index), 10) {
+				err = fmt.Errorf("invalid snapshot archive: expected chunk %d, got %s", index, header.Name)
+			}
+			var bz []byte
+			if err == nil {
+				bz, err = ioutil.ReadAll(tr)
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case chunks <- ioutil.NopCloser(bytes.NewReader(bz)):
+			case <-done:
+				return
+			}
+		}
+		readErr <- nil
+	}()
+
+	saved, err := store.Save(expected.Height, expected.Format, chunks)
+	if err != nil {
+		return nil, err
+	}
+	if err := <-readErr; err != nil {
+		_ = store.Delete(saved.Height, saved.Format)
+		return nil, err
+	}
+	if saved.Chunks != expected.Chunks || !bytes.Equal(saved.Hash, expected.Hash) {
+		_ = store.Delete(saved.Height, saved.Format)
+		return nil, fmt.Errorf("checksum mismatch: expected %d chunks with hash %X, got %d chunks with hash %X",
+			expected.Chunks, expected.Hash, saved.Chunks, saved.Hash)
+	}
+	return saved, nil
+}
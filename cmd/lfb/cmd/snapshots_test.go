@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/snapshots"
+	snapshottypes "github.com/line/lbm-sdk/snapshots/types"
+)
+
+func newTestSnapshotStore(t *testing.T) *snapshots.Store {
+	store, err := snapshots.NewStore(memdb.NewDB(), t.TempDir())
+	require.NoError(t, err)
+	return store
+}
+
+func chunksOf(data ...string) <-chan io.ReadCloser {
+	chunks := make(chan io.ReadCloser, len(data))
+	for _, d := range data {
+		chunks <- ioutil.NopCloser(bytes.NewBufferString(d))
+	}
+	close(chunks)
+	return chunks
+}
+
+// exportArchive writes the snapshot of the store as an archive.
+func exportArchive(t *testing.T, store *snapshots.Store, height uint64) []byte {
+	snapshot, chunks, err := store.Load(height, 1)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, writeSnapshotArchive(&buf, snapshot, chunks))
+	return buf.Bytes()
+}
+
+func TestSnapshotArchive(t *testing.T) {
+	store := newTestSnapshotStore(t)
+	expected, err := store.Save(3, 1, chunksOf("first", "second", "third"))
+	require.NoError(t, err)
+	archive := exportArchive(t, store, 3)
+
+	other := newTestSnapshotStore(t)
+	saved, err := readSnapshotArchive(bytes.NewReader(archive), other)
+	require.NoError(t, err)
+	require.Equal(t, expected.Hash, saved.Hash)
+	require.Equal(t, expected.Chunks, saved.Chunks)
+	_, chunks, err := other.Load(3, 1)
+	require.NoError(t, err)
+	var data []string
+	for chunk := range chunks {
+		bz, err := ioutil.ReadAll(chunk)
+		require.NoError(t, err)
+		data = append(data, string(bz))
+	}
+	require.Equal(t, []string{"first", "second", "third"}, data)
+
+	// the store refuses a snapshot it already has
+	_, err = readSnapshotArchive(bytes.NewReader(archive), other)
+	require.Error(t, err)
+
+	// a snapshot whose hash does not match the metadata is not kept
+	snapshot, chunks, err := store.Load(3, 1)
+	require.NoError(t, err)
+	snapshots.DrainChunks(chunks)
+	var buf bytes.Buffer
+	require.NoError(t, writeSnapshotArchive(&buf, snapshot, chunksOf("first", "changed", "third")))
+	other = newTestSnapshotStore(t)
+	_, err = readSnapshotArchive(&buf, other)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch")
+	list, err := other.List()
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	// a truncated archive is rejected
+	_, err = readSnapshotArchive(bytes.NewReader(archive[:len(archive)/2]), newTestSnapshotStore(t))
+	require.Error(t, err)
+	_, err = readSnapshotArchive(bytes.NewReader(nil), other)
+	require.Error(t, err)
+
+	var empty bytes.Buffer
+	require.Error(t, writeSnapshotArchive(&empty, &snapshottypes.Snapshot{Height: 3, Format: 1, Chunks: 2}, chunksOf("first")))
+}
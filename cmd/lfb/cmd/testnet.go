@@ -40,6 +40,7 @@ var (
 	flagOutputDir         = "output-dir"
 	flagNodeDaemonHome    = "node-daemon-home"
 	flagStartingIPAddress = "starting-ip-address"
+	flagRemoteSigner      = "remote-signer"
 )
 
 // get cmd to initialize all files for tendermint testnet and application
@@ -52,6 +53,10 @@ necessary files (private validator, genesis, config, etc.).
 
 Note, strict routability for addresses is turned off in the config file.
 
+With --remote-signer, the nodes listen for a remote signer on priv_validator_laddr, and
+the key and the state of each validator are moved to the signer home directory next to
+the node directory, to be used with "lfb signer".
+
 Example:
 	lfb testnet --v 4 --output-dir ./output --starting-ip-address 192.168.10.2
 	`,
@@ -72,10 +77,12 @@ Example:
 			startingIPAddress, _ := cmd.Flags().GetString(flagStartingIPAddress) // nolint: errcheck
 			numValidators, _ := cmd.Flags().GetInt(flagNumValidators)            // nolint: errcheck
 			algo, _ := cmd.Flags().GetString(flags.FlagKeyAlgorithm)             // nolint: errcheck
+			remoteSigner, _ := cmd.Flags().GetBool(flagRemoteSigner)             // nolint: errcheck
 
 			return InitTestnet(
 				clientCtx, cmd, config, mbm, genBalIterator, outputDir, chainID, minGasPrices,
 				nodeDirPrefix, nodeDaemonHome, startingIPAddress, keyringBackend, algo, numValidators,
+				remoteSigner,
 			)
 		},
 	}
@@ -89,11 +96,20 @@ Example:
 	cmd.Flags().String(server.FlagMinGasPrices, fmt.Sprintf("0.000006%s", sdk.DefaultBondDenom), "Minimum gas prices to accept for transactions; All fees in a tx must meet this minimum (e.g. 0.01photino,0.001stake)")
 	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|test)")
 	cmd.Flags().String(flags.FlagKeyAlgorithm, string(hd.Secp256k1Type), "Key signing algorithm to generate keys for")
+	cmd.Flags().Bool(flagRemoteSigner, false, "Sign with a remote signer: configure priv_validator_laddr of each node and move the validator key and state to <output-dir>/<node-dir>/signer")
 
 	return cmd
 }
 
-const nodeDirPerm = 0755
+const (
+	nodeDirPerm = 0755
+
+	// signerDirName is the name of the home directory of the remote signer of a node,
+	// next to the home directory of the node.
+	signerDirName = "signer"
+	// signerListenPort is the port of priv_validator_laddr of the nodes with a remote signer.
+	signerListenPort = 26659
+)
 
 // Initialize the testnet
 func InitTestnet(
@@ -111,12 +127,17 @@ func InitTestnet(
 	keyringBackend,
 	algoStr string,
 	numValidators int,
+	remoteSigner bool,
 ) error {
 	if chainID == "" {
 		chainID = "chain-" + ostrand.NewRand().Str(6)
 	}
+	if remoteSigner {
+		nodeConfig.PrivValidatorListenAddr = fmt.Sprintf("tcp://0.0.0.0:%d", signerListenPort)
+	}
 
 	nodeIDs := make([]string, numValidators)
+	ips := make([]string, numValidators)
 	valPubKeys := make([]cryptotypes.PubKey, numValidators)
 
 	simappConfig := srvconfig.DefaultConfig()
@@ -156,6 +177,7 @@ func InitTestnet(
 			_ = os.RemoveAll(outputDir)
 			return err
 		}
+		ips[i] = ip
 
 		nodeIDs[i], valPubKeys[i], err = genutil.InitializeNodeValidatorFiles(nodeConfig)
 		if err != nil {
@@ -163,6 +185,13 @@ func InitTestnet(
 			return err
 		}
 
+		if remoteSigner {
+			if err := movePrivValidatorFiles(nodeConfig, filepath.Join(outputDir, nodeDirName, signerDirName)); err != nil {
+				_ = os.RemoveAll(outputDir)
+				return err
+			}
+		}
+
 		memo := fmt.Sprintf("%s@%s:26656", nodeIDs[i], ip)
 		genFiles = append(genFiles, nodeConfig.GenesisFile())
 
@@ -245,7 +274,9 @@ func InitTestnet(
 			return err
 		}
 
-		srvconfig.WriteConfigFile(filepath.Join(nodeDir, "config/app.toml"), simappConfig)
+		if err := writeAppConfigFile(filepath.Join(nodeDir, "config/app.toml"), simappConfig); err != nil {
+			return err
+		}
 	}
 
 	if err := initGenFiles(clientCtx, mbm, chainID, genAccounts, genBalances, genFiles, numValidators); err != nil {
@@ -261,6 +292,33 @@ func InitTestnet(
 	}
 
 	cmd.PrintErrf("Successfully initialized %d node directories\n", numValidators)
+	if remoteSigner {
+		cmd.PrintErrln("Run the remote signers of the nodes with:")
+		for i := 0; i < numValidators; i++ {
+			cmd.PrintErrf("\tlfb signer --home %s --addr tcp://%s:%d --chain-id %s\n",
+				filepath.Join(outputDir, fmt.Sprintf("%s%d", nodeDirPrefix, i), signerDirName), ips[i], signerListenPort, chainID)
+		}
+	}
+	return nil
+}
+
+// movePrivValidatorFiles moves the private validator key and state of a node to the
+// same paths in the home directory of its remote signer.
+func movePrivValidatorFiles(nodeConfig *ostconfig.Config, signerDir string) error {
+	signerConfig := ostconfig.DefaultConfig()
+	signerConfig.SetRoot(signerDir)
+
+	for _, files := range [][2]string{
+		{nodeConfig.PrivValidatorKeyFile(), signerConfig.PrivValidatorKeyFile()},
+		{nodeConfig.PrivValidatorStateFile(), signerConfig.PrivValidatorStateFile()},
+	} {
+		if err := os.MkdirAll(filepath.Dir(files[1]), nodeDirPerm); err != nil {
+			return err
+		}
+		if err := os.Rename(files[0], files[1]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
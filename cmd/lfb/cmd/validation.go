@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/codec"
+	"github.com/line/lbm-sdk/server"
+	sdk "github.com/line/lbm-sdk/types"
+	genutiltypes "github.com/line/lbm-sdk/x/genutil/types"
+	stakingtypes "github.com/line/lbm-sdk/x/staking/types"
+
+	"github.com/line/lfb/app"
+	lfbtypes "github.com/line/lfb/types"
+)
+
+const (
+	flagSkipNetworkCheck = "skip-network-check"
+	flagGenTxDir         = "gentx-dir"
+)
+
+// configuredNetwork returns the network selected by lfbPreRunE.
+func configuredNetwork() lfbtypes.Network {
+	return lfbtypes.NetworkFromConfig(sdk.GetConfig())
+}
+
+// skipNetworkCheck returns true if the user explicitly allowed addresses of other networks.
+func skipNetworkCheck(cmd *cobra.Command) bool {
+	skip, _ := cmd.Flags().GetBool(flagSkipNetworkCheck)
+	return skip
+}
+
+// wrapNetworkError names the override flag in the error of a network mismatch.
+func wrapNetworkError(err error) error {
+	var mismatch *lfbtypes.AddressMismatchError
+	if errors.As(err, &mismatch) && mismatch.NetworkMismatch() {
+		return fmt.Errorf("%w (the %s network is selected; use --%s to allow it anyway)",
+			err, mismatch.Expected.Network.Name, flagSkipNetworkCheck)
+	}
+	return err
+}
+
+// validateAddress checks that the address is of the given kind on the configured network.
+func validateAddress(cmd *cobra.Command, address string, kind lfbtypes.AddressKind) error {
+	network := configuredNetwork()
+	err := lfbtypes.ValidateAddress(address, network, kind)
+	if mismatch, ok := err.(*lfbtypes.AddressMismatchError); ok && !mismatch.KindMismatch() && skipNetworkCheck(cmd) {
+		return nil
+	}
+	return wrapNetworkError(err)
+}
+
+// crossChainTxCommands are the tx commands whose arguments may be addresses on other chains,
+// such as the receiver of an IBC transfer.
+var crossChainTxCommands = []string{"ibc-transfer"}
+
+// validateTxArgs checks the bech32 arguments and the --from flag of a tx
+// command against the configured network. Arguments which are not bech32
+// strings, such as amounts and file names, are ignored, and only the --from
+// flag of crossChainTxCommands is checked.
+func validateTxArgs(cmd *cobra.Command, args []string) error {
+	txPath := cmd.Root().Name() + " tx "
+	if !strings.HasPrefix(cmd.CommandPath(), txPath) || skipNetworkCheck(cmd) {
+		return nil
+	}
+	for _, name := range crossChainTxCommands {
+		if strings.HasPrefix(cmd.CommandPath()+" ", txPath+name+" ") {
+			args = nil
+		}
+	}
+
+	candidates := args
+	if from, _ := cmd.Flags().GetString(flags.FlagFrom); from != "" {
+		candidates = append([]string{from}, args...)
+	}
+
+	network := configuredNetwork()
+	for _, arg := range candidates {
+		if _, err := lfbtypes.ParseAddress(arg); err != nil {
+			continue
+		}
+		if err := lfbtypes.ValidateNetwork(arg, network); err != nil {
+			return wrapNetworkError(err)
+		}
+	}
+	return nil
+}
+
+// clientMarshaler returns the codec of the client context, which the genesis state needs to be
+// a codec.Marshaler.
+func clientMarshaler(clientCtx client.Context) (codec.Marshaler, error) {
+	cdc, ok := clientCtx.JSONMarshaler.(codec.Marshaler)
+	if !ok {
+		return nil, fmt.Errorf("the codec %T of the client context is not a codec.Marshaler", clientCtx.JSONMarshaler)
+	}
+	return cdc, nil
+}
+
+// validateGenesisAddresses checks the accounts and balances of the genesis file
+// and the messages of the genesis transactions against the configured network.
+func validateGenesisAddresses(cmd *cobra.Command, _ []string) error {
+	if skipNetworkCheck(cmd) {
+		return nil
+	}
+
+	clientCtx := client.GetClientContextFromCmd(cmd)
+	cdc, err := clientMarshaler(clientCtx)
+	if err != nil {
+		return err
+	}
+	config := server.GetServerContextFromCmd(cmd).Config
+	config.SetRoot(clientCtx.HomeDir)
+
+	network := configuredNetwork()
+	appState, _, err := genutiltypes.GenesisStateFromGenFile(config.GenesisFile())
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal genesis state: %w", err)
+	}
+	addrs, err := app.GenesisState(appState).Addresses(cdc)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := lfbtypes.ValidateAddress(addr, network, lfbtypes.KindAccAddr); err != nil {
+			return wrapNetworkError(fmt.Errorf("genesis account: %w", err))
+		}
+	}
+
+	genTxsDir, _ := cmd.Flags().GetString(flagGenTxDir)
+	if genTxsDir == "" {
+		genTxsDir = filepath.Join(config.RootDir, "config", "gentx")
+	}
+	files, err := ioutil.ReadDir(genTxsDir)
+	if err != nil {
+		// the command itself reports a missing gentx directory
+		return nil
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		bz, err := ioutil.ReadFile(filepath.Join(genTxsDir, file.Name()))
+		if err != nil {
+			return err
+		}
+		tx, err := clientCtx.TxConfig.TxJSONDecoder()(bz)
+		if err != nil {
+			return fmt.Errorf("failed to decode genesis transaction %s: %w", file.Name(), err)
+		}
+		for _, msg := range tx.GetMsgs() {
+			if err := validateGenTxMsg(msg, network); err != nil {
+				return wrapNetworkError(fmt.Errorf("genesis transaction %s: %w", file.Name(), err))
+			}
+		}
+	}
+	return nil
+}
+
+func validateGenTxMsg(msg sdk.Msg, network lfbtypes.Network) error {
+	if msg, ok := msg.(*stakingtypes.MsgCreateValidator); ok {
+		if err := lfbtypes.ValidateAddress(msg.DelegatorAddress, network, lfbtypes.KindAccAddr); err != nil {
+			return err
+		}
+		return lfbtypes.ValidateAddress(msg.ValidatorAddress, network, lfbtypes.KindValAddr)
+	}
+	for _, signer := range msg.GetSigners() {
+		if err := lfbtypes.ValidateAddress(signer.String(), network, lfbtypes.KindAccAddr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withGenesisAddressCheck runs validateGenesisAddresses before the genesis command.
+func withGenesisAddressCheck(cmd *cobra.Command) *cobra.Command {
+	cmd.PreRunE = validateGenesisAddresses
+	return cmd
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/bech32"
+
+	lfbtypes "github.com/line/lfb/types"
+)
+
+func TestValidateTxArgs(t *testing.T) {
+	lfbtypes.Mainnet.SetConfig(sdk.GetConfig())
+
+	root := &cobra.Command{Use: "lfb", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().Bool(flagSkipNetworkCheck, false, "")
+	tx := &cobra.Command{Use: "tx"}
+	newCmd := func(use string) *cobra.Command {
+		cmd := &cobra.Command{Use: use, RunE: validateTxArgs}
+		cmd.Flags().String(flags.FlagFrom, "", "")
+		return cmd
+	}
+	bank := &cobra.Command{Use: "bank"}
+	bank.AddCommand(newCmd("send"))
+	ibcTransfer := &cobra.Command{Use: "ibc-transfer"}
+	ibcTransfer.AddCommand(newCmd("transfer"))
+	tx.AddCommand(bank, ibcTransfer)
+	root.AddCommand(tx)
+	// the flags keep their values across the runs
+	validate := func(args ...string) error {
+		root.SetArgs(args)
+		return root.Execute()
+	}
+
+	addr := func(prefix string) string {
+		s, err := bech32.ConvertAndEncode(prefix, secp256k1.GenPrivKey().PubKey().Address())
+		require.NoError(t, err)
+		return s
+	}
+	local, testnet, cosmos := addr("link"), addr("tlink"), addr("cosmos")
+
+	require.NoError(t, validate("tx", "bank", "send", local, local, "10stake"))
+	require.Error(t, validate("tx", "bank", "send", local, testnet, "10stake"))
+	require.Error(t, validate("tx", "bank", "send", local, cosmos, "10stake"))
+
+	// the receiver of an IBC transfer is on another chain, but the sender must be local
+	require.NoError(t, validate("tx", "ibc-transfer", "transfer", "transfer", "channel-0", cosmos, "10stake", "--from", local))
+	require.Error(t, validate("tx", "ibc-transfer", "transfer", "transfer", "channel-0", cosmos, "10stake", "--from", testnet))
+
+	require.NoError(t, validate("tx", "bank", "send", local, cosmos, "10stake", "--"+flagSkipNetworkCheck))
+}
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+
+set -eo pipefail
+
+go mod download github.com/line/lbm-sdk
+LBM_SDK_DIR=$(go list -m -f '{{ .Dir }}' github.com/line/lbm-sdk)
+
+# generate the swagger files of the query services configured in config.json, which are
+# those of the modules in ModuleBasics and of the services registered by the app
+mkdir -p ./tmp-swagger-gen
+query_files=$(grep -o '"./tmp-swagger-gen/[^"]*\.swagger\.json"' ./client/docs/config.json | sed -e 's#"./tmp-swagger-gen/##' -e 's#\.swagger\.json"#.proto#')
+for query_file in $query_files; do
+  buf protoc  \
+  -I "proto" \
+  -I "$LBM_SDK_DIR/proto" \
+  -I "$LBM_SDK_DIR/third_party/proto" \
+  "$query_file" \
+  --swagger_out=./tmp-swagger-gen \
+  --swagger_opt=logtostderr=true --swagger_opt=fqn_for_swagger_name=true --swagger_opt=simple_operation_ids=true
+done
+
+# combine swagger files
+# uses nodejs package `swagger-combine`.
+# all the individual swagger files need to be configured in `config.json` for merging
+swagger-combine ./client/docs/config.json -o ./client/docs/swagger-ui/swagger.yaml -f yaml --continueOnConflictingPaths true --includeDefinitions true
+
+# clean swagger files
+rm -rf ./tmp-swagger-gen
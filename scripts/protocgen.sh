@@ -0,0 +1,24 @@
+#!/usr/bin/env bash
+
+set -eo pipefail
+
+# the protos of lbm-sdk and its third party protos are imported from the module cache
+go mod download github.com/line/lbm-sdk
+LBM_SDK_DIR=$(go list -m -f '{{ .Dir }}' github.com/line/lbm-sdk)
+
+proto_dirs=$(find ./proto -path -prune -o -name '*.proto' -print0 | xargs -0 -n1 dirname | sort | uniq)
+for dir in $proto_dirs; do
+  buf protoc \
+  -I "proto" \
+  -I "$LBM_SDK_DIR/proto" \
+  -I "$LBM_SDK_DIR/third_party/proto" \
+  --gocosmos_out=plugins=interfacetype+grpc,\
+Mgoogle/protobuf/any.proto=github.com/line/lbm-sdk/codec/types:. \
+  --grpc-gateway_out=logtostderr=true:. \
+  $(find "${dir}" -maxdepth 1 -name '*.proto')
+
+done
+
+# move proto files to the right places
+cp -r github.com/line/lfb/* ./
+rm -rf github.com
@@ -0,0 +1,208 @@
+// Package signer implements a remote signer for validator nodes over the privval socket
+// protocol of ostracon.
+//
+// A validator node with priv_validator_laddr set in config.toml listens on that address
+// and waits for a signer to connect; the signer dials the node and serves its requests
+// for the public key, votes, proposals and VRF proofs. The signer keeps the key in
+// priv_validator_key.json and the last signed height, round and step in
+// priv_validator_state.json, like the file signer of a node, and refuses to sign
+// conflicting votes and proposals for a height it has already signed.
+//
+// The signer redials the node whenever the connection is lost, e.g. when the node
+// restarts, until it is stopped.
+package signer
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/line/ostracon/crypto/ed25519"
+	"github.com/line/ostracon/libs/log"
+	ostnet "github.com/line/ostracon/libs/net"
+	ostos "github.com/line/ostracon/libs/os"
+	"github.com/line/ostracon/libs/protoio"
+	"github.com/line/ostracon/libs/service"
+	"github.com/line/ostracon/privval"
+	privvalproto "github.com/line/ostracon/proto/ostracon/privval"
+)
+
+const (
+	// DefaultRetryInterval is the default interval between two dials of the node.
+	DefaultRetryInterval = time.Second
+	// DefaultTimeoutReadWrite is the default timeout of reading a request and writing its response.
+	DefaultTimeoutReadWrite = 5 * time.Second
+
+	// maxMsgSize is the maximum size of a request, as accepted by the nodes.
+	maxMsgSize = 1024 * 10
+)
+
+// Signer signs the requests of a validator node with a file-backed private validator.
+type Signer struct {
+	service.BaseService
+
+	addr    string
+	chainID string
+	privVal *privval.FilePV
+	dialer  privval.SocketDialer
+
+	retryInterval    time.Duration
+	timeoutReadWrite time.Duration
+
+	mtx  sync.Mutex
+	conn net.Conn
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Option sets an optional parameter of a Signer.
+type Option func(*Signer)
+
+// WithRetryInterval sets the interval between two dials of the node.
+func WithRetryInterval(interval time.Duration) Option {
+	return func(s *Signer) { s.retryInterval = interval }
+}
+
+// WithTimeoutReadWrite sets the timeout of reading a request and writing its response.
+func WithTimeoutReadWrite(timeout time.Duration) Option {
+	return func(s *Signer) { s.timeoutReadWrite = timeout }
+}
+
+// New returns a signer which dials the node at addr, a tcp:// or unix:// address, and
+// signs with the key and the last sign state in the given files for the chain chainID.
+func New(logger log.Logger, addr, chainID, keyFile, stateFile string, opts ...Option) (*Signer, error) {
+	if chainID == "" {
+		return nil, fmt.Errorf("chain id must not be empty")
+	}
+	if !ostos.FileExists(keyFile) {
+		return nil, fmt.Errorf("private validator key file %s not found", keyFile)
+	}
+	if !ostos.FileExists(stateFile) {
+		return nil, fmt.Errorf("private validator state file %s not found", stateFile)
+	}
+
+	s := &Signer{
+		addr:             addr,
+		chainID:          chainID,
+		privVal:          privval.LoadFilePV(keyFile, stateFile),
+		retryInterval:    DefaultRetryInterval,
+		timeoutReadWrite: DefaultTimeoutReadWrite,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	protocol, address := ostnet.ProtocolAndAddress(addr)
+	switch protocol {
+	case "tcp":
+		s.dialer = privval.DialTCPFn(address, s.timeoutReadWrite, ed25519.GenPrivKey())
+	case "unix":
+		s.dialer = privval.DialUnixFn(address)
+	default:
+		return nil, fmt.Errorf("invalid address %s: expected either the tcp or the unix protocol", addr)
+	}
+
+	s.BaseService = *service.NewBaseService(logger, "Signer", s)
+	return s, nil
+}
+
+// OnStart implements service.Service.
+func (s *Signer) OnStart() error {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.serviceLoop()
+	return nil
+}
+
+// OnStop implements service.Service.
+func (s *Signer) OnStop() {
+	close(s.stop)
+	s.closeConn()
+	<-s.done
+}
+
+// IsConnected returns whether the signer is connected to the node.
+func (s *Signer) IsConnected() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.conn != nil
+}
+
+func (s *Signer) serviceLoop() {
+	defer close(s.done)
+	for {
+		conn, err := s.dialer()
+		if err != nil {
+			s.Logger.Debug("failed to dial the node", "addr", s.addr, "err", err)
+			select {
+			case <-s.stop:
+				return
+			case <-time.After(s.retryInterval):
+				continue
+			}
+		}
+
+		// the connection must not be set after OnStop has closed it
+		s.mtx.Lock()
+		select {
+		case <-s.stop:
+			s.mtx.Unlock()
+			_ = conn.Close()
+			return
+		default:
+			s.conn = conn
+		}
+		s.mtx.Unlock()
+
+		s.Logger.Info("connected to the node", "addr", s.addr)
+		err = s.serve(conn)
+		s.closeConn()
+
+		select {
+		case <-s.stop:
+			return
+		default:
+			s.Logger.Info("lost the connection to the node", "addr", s.addr, "err", err)
+		}
+	}
+}
+
+// serve handles the requests of the node on conn until the connection fails.
+func (s *Signer) serve(conn net.Conn) error {
+	r := protoio.NewDelimitedReader(conn, maxMsgSize)
+	w := protoio.NewDelimitedWriter(conn)
+	for {
+		// the node pings the signer regularly, so a read only times out on a broken connection
+		if err := conn.SetReadDeadline(time.Now().Add(s.timeoutReadWrite)); err != nil {
+			return err
+		}
+		var req privvalproto.Message
+		if _, err := r.ReadMsg(&req); err != nil {
+			return err
+		}
+
+		// the file signer checks the request against the last sign state, and the
+		// error is returned to the node in the response
+		res, err := privval.DefaultValidationRequestHandler(s.privVal, req, s.chainID)
+		if err != nil {
+			s.Logger.Error("failed to handle a request", "err", err)
+		}
+
+		if err := conn.SetWriteDeadline(time.Now().Add(s.timeoutReadWrite)); err != nil {
+			return err
+		}
+		if _, err := w.WriteMsg(&res); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Signer) closeConn() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+	"strings"
+
 	sdk "github.com/line/lbm-sdk/types"
 )
 
@@ -11,32 +14,147 @@ const (
 	// LINK in [SLIP-044](https://github.com/satoshilabs/slips/blob/master/slip-0044.md)
 	CoinType           = 438
 	FullFundraiserPath = "m/44'/438'/0'/0/0"
+
+	// bech32MaxLen is the maximum length of a bech32 string.
+	bech32MaxLen = 90
+	// maxBech32DataLen is the length of the longest data encoded with the prefix: the amino
+	// encoding of a 33-byte secp256k1 public key, a 4-byte prefix and a length byte before it.
+	maxBech32DataLen = 4 + 1 + 33
+	// maxBech32PrefixLen is the longest prefix whose longest encoded form, the valconspub of the
+	// longest data, fits in bech32MaxLen: the prefix followed by "valconspub", the separator, the
+	// data in 5-bit characters and the 6-character checksum. It is 12.
+	maxBech32PrefixLen = bech32MaxLen - len(sdk.PrefixValidator+sdk.PrefixConsensus+sdk.PrefixPublic) - 1 -
+		(maxBech32DataLen*8+4)/5 - 6
 )
 
-func Bech32PrefixAcc(testnet bool) (prefix string) {
-	prefix = Bech32MainPrefix
+// Network defines the address prefix and the HD coin type of an LFB network.
+type Network struct {
+	Name         string
+	Bech32Prefix string
+	CoinType     uint32
+}
+
+var (
+	// Mainnet is the built-in preset of the LFB main network.
+	Mainnet = Network{Name: "mainnet", Bech32Prefix: Bech32MainPrefix, CoinType: CoinType}
+	// Testnet is the built-in preset of the LFB test network.
+	Testnet = Network{Name: "testnet", Bech32Prefix: Bech32TestnetPrefix, CoinType: CoinType}
+)
+
+// NetworkPreset returns the built-in network of the given name.
+func NetworkPreset(name string) (Network, bool) {
+	switch name {
+	case Mainnet.Name:
+		return Mainnet, true
+	case Testnet.Name:
+		return Testnet, true
+	default:
+		return Network{}, false
+	}
+}
+
+// Validate checks that the prefix can be used as a bech32 human readable part.
+func (n Network) Validate() error {
+	prefix := n.Bech32Prefix
+	if prefix == "" {
+		return fmt.Errorf("empty bech32 prefix")
+	}
+	if len(prefix) > maxBech32PrefixLen {
+		return fmt.Errorf("bech32 prefix %q is longer than %d characters", prefix, maxBech32PrefixLen)
+	}
+	if strings.ToLower(prefix) != prefix {
+		return fmt.Errorf("bech32 prefix %q must be lowercase", prefix)
+	}
+	for _, c := range prefix {
+		if c < 33 || c > 126 || c == '1' {
+			return fmt.Errorf("bech32 prefix %q contains invalid character %q", prefix, c)
+		}
+	}
+	return nil
+}
+
+// FullFundraiserPath returns the BIP44 path of the first account of the network's coin type.
+func (n Network) FullFundraiserPath() string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/0", n.CoinType)
+}
+
+func (n Network) Bech32PrefixAccAddr() string {
+	return n.Bech32Prefix
+}
+
+func (n Network) Bech32PrefixAccPub() string {
+	return n.Bech32Prefix + sdk.PrefixPublic
+}
+
+func (n Network) Bech32PrefixValAddr() string {
+	return n.Bech32Prefix + sdk.PrefixValidator + sdk.PrefixOperator
+}
+
+func (n Network) Bech32PrefixValPub() string {
+	return n.Bech32Prefix + sdk.PrefixValidator + sdk.PrefixOperator + sdk.PrefixPublic
+}
+
+func (n Network) Bech32PrefixConsAddr() string {
+	return n.Bech32Prefix + sdk.PrefixValidator + sdk.PrefixConsensus
+}
+
+func (n Network) Bech32PrefixConsPub() string {
+	return n.Bech32Prefix + sdk.PrefixValidator + sdk.PrefixConsensus + sdk.PrefixPublic
+}
+
+// SetConfig applies the prefixes and the coin type of the network to the SDK config.
+// The config is not sealed.
+func (n Network) SetConfig(config *sdk.Config) {
+	config.SetCoinType(n.CoinType)
+	config.SetFullFundraiserPath(n.FullFundraiserPath())
+	config.SetBech32PrefixForAccount(n.Bech32PrefixAccAddr(), n.Bech32PrefixAccPub())
+	config.SetBech32PrefixForConsensusNode(n.Bech32PrefixConsAddr(), n.Bech32PrefixConsPub())
+	config.SetBech32PrefixForValidator(n.Bech32PrefixValAddr(), n.Bech32PrefixValPub())
+}
+
+// NetworkFromConfig returns the network whose prefixes and coin type are set in the SDK config.
+func NetworkFromConfig(config *sdk.Config) Network {
+	network := Network{
+		Name:         "custom",
+		Bech32Prefix: config.GetBech32AccountAddrPrefix(),
+		CoinType:     config.GetCoinType(),
+	}
+	for _, preset := range []Network{Mainnet, Testnet} {
+		if preset.Bech32Prefix == network.Bech32Prefix {
+			network.Name = preset.Name
+		}
+	}
+	return network
+}
+
+// NetworkOf returns the built-in network selected by the testnet flag.
+func NetworkOf(testnet bool) Network {
 	if testnet {
-		prefix = Bech32TestnetPrefix
+		return Testnet
 	}
-	return
+	return Mainnet
+}
+
+func Bech32PrefixAcc(testnet bool) (prefix string) {
+	return NetworkOf(testnet).Bech32PrefixAccAddr()
 }
 
 func Bech32PrefixAccPub(testnet bool) string {
-	return Bech32PrefixAcc(testnet) + sdk.PrefixPublic
+	return NetworkOf(testnet).Bech32PrefixAccPub()
 }
 
 func Bech32PrefixValAddr(testnet bool) string {
-	return Bech32PrefixAcc(testnet) + sdk.PrefixValidator + sdk.PrefixOperator
+	return NetworkOf(testnet).Bech32PrefixValAddr()
 }
 
 func Bech32PrefixValPub(testnet bool) string {
-	return Bech32PrefixAcc(testnet) + sdk.PrefixValidator + sdk.PrefixOperator + sdk.PrefixPublic
+	return NetworkOf(testnet).Bech32PrefixValPub()
 }
 
 func Bech32PrefixConsAddr(testnet bool) string {
-	return Bech32PrefixAcc(testnet) + sdk.PrefixValidator + sdk.PrefixConsensus
+	return NetworkOf(testnet).Bech32PrefixConsAddr()
 }
 
 func Bech32PrefixConsPub(testnet bool) string {
-	return Bech32PrefixAcc(testnet) + sdk.PrefixValidator + sdk.PrefixConsensus + sdk.PrefixPublic
+	return NetworkOf(testnet).Bech32PrefixConsPub()
 }
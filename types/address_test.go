@@ -0,0 +1,31 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/codec/legacy"
+	"github.com/line/lbm-sdk/crypto/keys/secp256k1"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/bech32"
+)
+
+func TestNetworkValidate(t *testing.T) {
+	require.NoError(t, Mainnet.Validate())
+	require.NoError(t, Testnet.Validate())
+
+	// the valconspub of a secp256k1 key with the longest prefix is a valid bech32 string
+	prefix := strings.Repeat("a", maxBech32PrefixLen)
+	require.NoError(t, Network{Bech32Prefix: prefix}.Validate())
+	pubKey := legacy.Cdc.MustMarshalBinaryBare(secp256k1.GenPrivKey().PubKey())
+	require.Len(t, pubKey, maxBech32DataLen)
+	valConsPub, err := bech32.ConvertAndEncode(prefix+sdk.PrefixValidator+sdk.PrefixConsensus+sdk.PrefixPublic, pubKey)
+	require.NoError(t, err)
+	require.Len(t, valConsPub, bech32MaxLen)
+
+	for _, prefix := range []string{"", strings.Repeat("a", maxBech32PrefixLen+1), "Link", "li1nk", "li nk"} {
+		require.Error(t, Network{Bech32Prefix: prefix}.Validate(), prefix)
+	}
+}
@@ -0,0 +1,181 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/bech32"
+)
+
+// AddressKind is the form of a bech32 encoded address or public key.
+type AddressKind int
+
+const (
+	KindUnknown AddressKind = iota
+	KindAccAddr
+	KindAccPub
+	KindValAddr
+	KindValPub
+	KindConsAddr
+	KindConsPub
+)
+
+// AddressKinds lists every known kind in the order of their bech32 prefix suffix length.
+var AddressKinds = []AddressKind{KindConsPub, KindValPub, KindConsAddr, KindValAddr, KindAccPub, KindAccAddr}
+
+func (k AddressKind) String() string {
+	switch k {
+	case KindAccAddr:
+		return "account address"
+	case KindAccPub:
+		return "account public key"
+	case KindValAddr:
+		return "validator operator address"
+	case KindValPub:
+		return "validator operator public key"
+	case KindConsAddr:
+		return "consensus address"
+	case KindConsPub:
+		return "consensus public key"
+	default:
+		return "unknown"
+	}
+}
+
+// IsPubKey returns true if the kind is one of the public key forms.
+func (k AddressKind) IsPubKey() bool {
+	return k == KindAccPub || k == KindValPub || k == KindConsPub
+}
+
+// PrefixOf returns the bech32 prefix of the given kind on the network.
+func (n Network) PrefixOf(kind AddressKind) string {
+	switch kind {
+	case KindAccAddr:
+		return n.Bech32PrefixAccAddr()
+	case KindAccPub:
+		return n.Bech32PrefixAccPub()
+	case KindValAddr:
+		return n.Bech32PrefixValAddr()
+	case KindValPub:
+		return n.Bech32PrefixValPub()
+	case KindConsAddr:
+		return n.Bech32PrefixConsAddr()
+	case KindConsPub:
+		return n.Bech32PrefixConsPub()
+	default:
+		return ""
+	}
+}
+
+// kindOf returns the kind of the bech32 prefix on the network.
+func (n Network) kindOf(hrp string) AddressKind {
+	for _, kind := range AddressKinds {
+		if hrp == n.PrefixOf(kind) {
+			return kind
+		}
+	}
+	return KindUnknown
+}
+
+// ParsedAddress is a bech32 string classified by its network and kind.
+type ParsedAddress struct {
+	Network Network
+	Kind    AddressKind
+	Bytes   []byte
+}
+
+func (a ParsedAddress) String() string {
+	if a.Network.Name == "" {
+		return fmt.Sprintf("%s of an unknown network with prefix %q", a.Kind, a.Network.Bech32Prefix)
+	}
+	return fmt.Sprintf("%s %s", a.Network.Name, a.Kind)
+}
+
+// ParseAddress decodes a bech32 encoded address or public key and explains which
+// form it is. The given networks are tried first, then the built-in presets.
+// For any other prefix the network is guessed from the longest known suffix and
+// returned without a name.
+func ParseAddress(bech32str string, networks ...Network) (ParsedAddress, error) {
+	hrp, bz, err := bech32.DecodeAndConvert(bech32str)
+	if err != nil {
+		return ParsedAddress{}, fmt.Errorf("decoding bech32 address %q failed: %w", bech32str, err)
+	}
+
+	candidates := append(append([]Network{}, networks...), Mainnet, Testnet)
+	for _, network := range candidates {
+		if kind := network.kindOf(hrp); kind != KindUnknown {
+			return ParsedAddress{Network: network, Kind: kind, Bytes: bz}, nil
+		}
+	}
+
+	for _, kind := range AddressKinds {
+		suffix := Network{}.PrefixOf(kind)
+		if strings.HasSuffix(hrp, suffix) && len(hrp) > len(suffix) {
+			network := Network{Bech32Prefix: strings.TrimSuffix(hrp, suffix)}
+			return ParsedAddress{Network: network, Kind: kind, Bytes: bz}, nil
+		}
+	}
+	return ParsedAddress{}, fmt.Errorf("unknown bech32 prefix %q", hrp)
+}
+
+// AddressMismatchError describes an address of another network or form than expected.
+type AddressMismatchError struct {
+	Address  string
+	Expected ParsedAddress
+	Actual   ParsedAddress
+}
+
+func (e *AddressMismatchError) Error() string {
+	return fmt.Sprintf("%s is %s, but %s is expected; expected prefix %q, got %q",
+		e.Address, withArticle(e.Actual.String()), withArticle(e.Expected.String()), e.Expected.Network.PrefixOf(e.Expected.Kind),
+		e.Actual.Network.PrefixOf(e.Actual.Kind))
+}
+
+// NetworkMismatch returns true if the address belongs to another network.
+func (e *AddressMismatchError) NetworkMismatch() bool {
+	return e.Expected.Network.Bech32Prefix != e.Actual.Network.Bech32Prefix
+}
+
+// KindMismatch returns true if the address is another form than expected.
+func (e *AddressMismatchError) KindMismatch() bool {
+	return e.Expected.Kind != e.Actual.Kind
+}
+
+// ValidateAddress checks that the bech32 string is an address or public key of
+// the given kind on the network. It returns an *AddressMismatchError describing
+// the actual form of a well-formed address of another network or kind.
+func ValidateAddress(bech32str string, network Network, kind AddressKind) error {
+	parsed, err := ParseAddress(bech32str, network)
+	if err != nil {
+		return err
+	}
+	if parsed.Network.Bech32Prefix != network.Bech32Prefix || parsed.Kind != kind {
+		return &AddressMismatchError{
+			Address:  bech32str,
+			Expected: ParsedAddress{Network: network, Kind: kind},
+			Actual:   parsed,
+		}
+	}
+	if !kind.IsPubKey() {
+		return sdk.VerifyAddressFormat(parsed.Bytes)
+	}
+	return nil
+}
+
+func withArticle(noun string) string {
+	if noun != "" && strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
+
+// ValidateNetwork checks that the bech32 string is an address or public key of
+// any kind on the given network, returning an *AddressMismatchError otherwise.
+func ValidateNetwork(bech32str string, network Network) error {
+	parsed, err := ParseAddress(bech32str, network)
+	if err != nil {
+		return err
+	}
+	return ValidateAddress(bech32str, network, parsed.Kind)
+}
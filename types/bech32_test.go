@@ -0,0 +1,118 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/bech32"
+)
+
+var testAddr = bytes.Repeat([]byte{1}, sdk.BytesAddrLen)
+
+func mustEncode(t *testing.T, hrp string, bz []byte) string {
+	s, err := bech32.ConvertAndEncode(hrp, bz)
+	require.NoError(t, err)
+	return s
+}
+
+func TestParseAddress(t *testing.T) {
+	custom := Network{Name: "custom", Bech32Prefix: "cons"}
+
+	for _, tc := range []struct {
+		hrp     string
+		network Network
+		kind    AddressKind
+		str     string
+	}{
+		{"link", Mainnet, KindAccAddr, "mainnet account address"},
+		{"linkpub", Mainnet, KindAccPub, "mainnet account public key"},
+		{"linkvaloper", Mainnet, KindValAddr, "mainnet validator operator address"},
+		{"linkvaloperpub", Mainnet, KindValPub, "mainnet validator operator public key"},
+		{"linkvalcons", Mainnet, KindConsAddr, "mainnet consensus address"},
+		{"linkvalconspub", Mainnet, KindConsPub, "mainnet consensus public key"},
+		{"tlink", Testnet, KindAccAddr, "testnet account address"},
+		{"tlinkvaloper", Testnet, KindValAddr, "testnet validator operator address"},
+		// the given networks are tried before the presets
+		{"consvalcons", custom, KindConsAddr, "custom consensus address"},
+		// the network of an unknown prefix is guessed from the longest known suffix
+		{"cosmosvalconspub", Network{Bech32Prefix: "cosmos"}, KindConsPub, `consensus public key of an unknown network with prefix "cosmos"`},
+		{"cosmospub", Network{Bech32Prefix: "cosmos"}, KindAccPub, `account public key of an unknown network with prefix "cosmos"`},
+		{"cosmos", Network{Bech32Prefix: "cosmos"}, KindAccAddr, `account address of an unknown network with prefix "cosmos"`},
+	} {
+		parsed, err := ParseAddress(mustEncode(t, tc.hrp, testAddr), custom)
+		require.NoError(t, err, tc.hrp)
+		require.Equal(t, ParsedAddress{Network: tc.network, Kind: tc.kind, Bytes: testAddr}, parsed, tc.hrp)
+		require.Equal(t, tc.str, parsed.String(), tc.hrp)
+	}
+
+	// a prefix that is only a suffix of another form is the account address of its own network
+	parsed, err := ParseAddress(mustEncode(t, "pub", testAddr))
+	require.NoError(t, err)
+	require.Equal(t, ParsedAddress{Network: Network{Bech32Prefix: "pub"}, Kind: KindAccAddr, Bytes: testAddr}, parsed)
+
+	_, err = ParseAddress("link1invalid")
+	require.Error(t, err)
+	_, err = ParseAddress("")
+	require.Error(t, err)
+}
+
+func TestValidateAddress(t *testing.T) {
+	addr := mustEncode(t, "link", testAddr)
+	require.NoError(t, ValidateAddress(addr, Mainnet, KindAccAddr))
+	require.NoError(t, ValidateAddress(mustEncode(t, "tlinkvaloper", testAddr), Testnet, KindValAddr))
+	// the length of public keys is not checked
+	require.NoError(t, ValidateAddress(mustEncode(t, "linkpub", []byte{1, 2, 3}), Mainnet, KindAccPub))
+
+	require.Error(t, ValidateAddress(mustEncode(t, "link", []byte{1, 2, 3}), Mainnet, KindAccAddr))
+	require.Error(t, ValidateAddress("link1invalid", Mainnet, KindAccAddr))
+
+	for _, tc := range []struct {
+		addr        string
+		network     Network
+		kind        AddressKind
+		networkDiff bool
+		kindDiff    bool
+		msg         string
+	}{
+		{
+			mustEncode(t, "tlink", testAddr), Mainnet, KindAccAddr, true, false,
+			`is a testnet account address, but a mainnet account address is expected; expected prefix "link", got "tlink"`,
+		},
+		{
+			addr, Mainnet, KindValAddr, false, true,
+			`is a mainnet account address, but a mainnet validator operator address is expected; expected prefix "linkvaloper", got "link"`,
+		},
+		{
+			mustEncode(t, "cosmosvalcons", testAddr), Testnet, KindAccAddr, true, true,
+			`is a consensus address of an unknown network with prefix "cosmos", but a testnet account address is expected; expected prefix "tlink", got "cosmosvalcons"`,
+		},
+	} {
+		err := ValidateAddress(tc.addr, tc.network, tc.kind)
+		var mismatch *AddressMismatchError
+		require.True(t, errors.As(err, &mismatch), tc.msg)
+		require.Equal(t, tc.addr, mismatch.Address)
+		require.Equal(t, tc.networkDiff, mismatch.NetworkMismatch(), tc.msg)
+		require.Equal(t, tc.kindDiff, mismatch.KindMismatch(), tc.msg)
+		require.EqualError(t, err, tc.addr+" "+tc.msg)
+	}
+}
+
+func TestValidateNetwork(t *testing.T) {
+	for _, hrp := range []string{"link", "linkpub", "linkvaloper", "linkvalconspub"} {
+		require.NoError(t, ValidateNetwork(mustEncode(t, hrp, testAddr), Mainnet), hrp)
+	}
+	require.Error(t, ValidateNetwork(mustEncode(t, "linkvalcons", []byte{1}), Mainnet))
+
+	err := ValidateNetwork(mustEncode(t, "tlinkvaloper", testAddr), Mainnet)
+	var mismatch *AddressMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.True(t, mismatch.NetworkMismatch())
+	require.False(t, mismatch.KindMismatch())
+	require.Equal(t, ParsedAddress{Network: Testnet, Kind: KindValAddr, Bytes: testAddr}, mismatch.Actual)
+
+	require.Error(t, ValidateNetwork("tlink1invalid", Testnet))
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/x/audit/types"
+)
+
+// FlagModule is the flag of the module whose records are queried.
+const FlagModule = "module"
+
+// GetQueryCmd returns the CLI command with all audit module query commands mounted.
+func GetQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Query the history of the changes made by governance proposals",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		recordsCmd("params", "param changes", types.KindParamChange,
+			fmt.Sprintf("$ %s query %s params --module staking", version.AppName, types.ModuleName)),
+		recordsCmd("wasm", "changes of the admins, codes and statuses of the contracts and of the pinned codes",
+			types.KindWasmAdmin, fmt.Sprintf("$ %s query %s wasm", version.AppName, types.ModuleName)),
+		recordsCmd("upgrades", "changes of the upgrade plan", types.KindUpgradePlan,
+			fmt.Sprintf("$ %s query %s upgrades", version.AppName, types.ModuleName)),
+	)
+	return cmd
+}
+
+// recordsCmd returns the command listing the records of the kind, oldest first.
+func recordsCmd(use, what string, kind types.Kind, example string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     use,
+		Short:   fmt.Sprintf("Query the history of the %s", what),
+		Long:    fmt.Sprintf("Query the history of the %s, oldest first, optionally of a module only.", what),
+		Example: example,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			module, err := cmd.Flags().GetString(FlagModule)
+			if err != nil {
+				return err
+			}
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Records(cmd.Context(), &types.QueryRecordsRequest{
+				Kind:       kind,
+				Module:     module,
+				Pagination: pageReq,
+			})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().String(FlagModule, "", "the module whose changes are queried, e.g. staking")
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, use)
+	return cmd
+}
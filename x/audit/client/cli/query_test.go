@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"context"
+	"testing"
+
+	ostbytes "github.com/line/ostracon/libs/bytes"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	rpcclient "github.com/line/ostracon/rpc/client"
+	"github.com/line/ostracon/rpc/client/mock"
+	ctypes "github.com/line/ostracon/rpc/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/client"
+	clitestutil "github.com/line/lbm-sdk/testutil/cli"
+
+	"github.com/line/lfb/app"
+	"github.com/line/lfb/x/audit/client/cli"
+	"github.com/line/lfb/x/audit/types"
+)
+
+// node queries the app directly, unlike mock.Client which queries the node of the process.
+type node struct {
+	mock.Client
+}
+
+func (n node) ABCIQueryWithOptions(ctx context.Context, path string, data ostbytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	return n.ABCIClient.ABCIQueryWithOptions(ctx, path, data, opts)
+}
+
+func TestQueryRecords(t *testing.T) {
+	linkApp := app.Setup(false)
+	ctx := linkApp.BaseApp.NewContext(false, ocproto.Header{})
+	for _, r := range []types.Record{
+		{Kind: types.KindParamChange, Module: "staking", Key: "MaxValidators", OldValue: "100", NewValue: "10"},
+		{Kind: types.KindParamChange, Module: "slashing", Key: "SignedBlocksWindow", OldValue: `"100"`, NewValue: `"200"`},
+		{Kind: types.KindParamChange, Module: "staking", Key: "UnbondingTime", OldValue: `"1814400000000000"`, NewValue: `"1"`},
+		{Kind: types.KindUpgradePlan, Module: "upgrade", Key: "plan", NewValue: `{"name":"v2"}`},
+	} {
+		linkApp.AuditKeeper.AddRecord(ctx, r)
+	}
+	linkApp.Commit()
+
+	encCfg := app.MakeEncodingConfig()
+	clientCtx := client.Context{}.
+		WithClient(node{mock.Client{ABCIClient: mock.ABCIApp{App: linkApp}}}).
+		WithInterfaceRegistry(encCfg.InterfaceRegistry).
+		WithJSONMarshaler(encCfg.Marshaler).
+		WithLegacyAmino(encCfg.Amino)
+	query := func(args ...string) []types.Record {
+		out, err := clitestutil.ExecTestCLICmd(clientCtx, cli.GetQueryCmd(), append(args, "--output", "json"))
+		require.NoError(t, err)
+		var res types.QueryRecordsResponse
+		require.NoError(t, clientCtx.JSONMarshaler.UnmarshalJSON(out.Bytes(), &res))
+		return res.Records
+	}
+
+	records := query("params")
+	require.Len(t, records, 3)
+
+	records = query("params", "--"+cli.FlagModule, "staking")
+	require.Len(t, records, 2)
+	require.Equal(t, "MaxValidators", records[0].Key)
+	require.Equal(t, "UnbondingTime", records[1].Key)
+
+	require.Empty(t, query("params", "--"+cli.FlagModule, "bank"))
+	require.Len(t, query("upgrades"), 1)
+}
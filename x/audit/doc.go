@@ -0,0 +1,15 @@
+/*
+Package audit implements the audit log of the changes made by the governance proposals.
+
+The handlers of the param change, upgrade and wasm proposals are wrapped so that, when a proposal
+passes, every param, upgrade plan, contract admin, code, status and pinned code it changes is
+recorded with its old and new value, the ID of the proposal and the height and time of the block.
+The records are kept in the state, exported with the genesis and queried with:
+
+	lfb query audit params --module staking
+	lfb query audit wasm
+	lfb query audit upgrades
+
+or the /lfb/audit/v1/records route of the gRPC Gateway.
+*/
+package audit
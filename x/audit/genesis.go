@@ -0,0 +1,31 @@
+package audit
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+
+	"github.com/line/lfb/x/audit/keeper"
+	"github.com/line/lfb/x/audit/types"
+)
+
+// InitGenesis initializes the audit module's state from a given genesis state.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, gs *types.GenesisState) {
+	if err := gs.Validate(); err != nil {
+		panic(err)
+	}
+	for _, r := range gs.Records {
+		k.SetRecord(ctx, r)
+	}
+	if n := len(gs.Records); n > 0 {
+		k.SetNextRecordID(ctx, gs.Records[n-1].Id+1)
+	}
+}
+
+// ExportGenesis returns the audit module's exported genesis.
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	var records []types.Record
+	k.IterateRecords(ctx, func(r types.Record) bool {
+		records = append(records, r)
+		return false
+	})
+	return types.NewGenesisState(records)
+}
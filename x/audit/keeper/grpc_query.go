@@ -0,0 +1,60 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/line/lbm-sdk/store/prefix"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/query"
+
+	"github.com/line/lfb/x/audit/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// Records implements the Query/Records gRPC method
+func (k Keeper) Records(c context.Context, req *types.QueryRecordsRequest) (*types.QueryRecordsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	if _, ok := types.Kind_name[int32(req.Kind)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid kind %d", req.Kind)
+	}
+	if req.Module != "" && req.Kind == types.KindUnspecified {
+		return nil, status.Error(codes.InvalidArgument, "the records of a module require the kind")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	store := ctx.KVStore(k.storeKey)
+	var records []types.Record
+	var pageRes *query.PageResponse
+	var err error
+	if req.Kind == types.KindUnspecified {
+		pageRes, err = query.Paginate(prefix.NewStore(store, types.RecordKeyPrefix), req.Pagination, func(_, value []byte) error {
+			var r types.Record
+			if err := k.cdc.UnmarshalBinaryBare(value, &r); err != nil {
+				return err
+			}
+			records = append(records, r)
+			return nil
+		})
+	} else {
+		indexStore := prefix.NewStore(store, types.RecordIndexPrefix(req.Kind, req.Module))
+		pageRes, err = query.Paginate(indexStore, req.Pagination, func(key, _ []byte) error {
+			// the index key ends with the ID of the record
+			r, found := k.GetRecord(ctx, sdk.BigEndianToUint64(key[len(key)-8:]))
+			if !found {
+				return status.Errorf(codes.Internal, "no record of the index key %X", key)
+			}
+			records = append(records, r)
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &types.QueryRecordsResponse{Records: records, Pagination: pageRes}, nil
+}
@@ -0,0 +1,112 @@
+package keeper
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/line/ostracon/libs/log"
+
+	"github.com/line/lbm-sdk/codec"
+	sdk "github.com/line/lbm-sdk/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+
+	"github.com/line/lfb/x/audit/types"
+)
+
+// Keeper of the audit store
+type Keeper struct {
+	cdc       codec.Marshaler
+	storeKey  sdk.StoreKey
+	govKeeper types.GovKeeper
+}
+
+// NewKeeper creates a new audit Keeper instance. The gov keeper is only used when the proposals
+// are executed, so it may be a pointer to the gov keeper of the app that is not created yet.
+func NewKeeper(cdc codec.Marshaler, key sdk.StoreKey, govKeeper types.GovKeeper) Keeper {
+	return Keeper{
+		cdc:       cdc,
+		storeKey:  key,
+		govKeeper: govKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// AddRecord adds the record of a change made at the block of the context, assigning it the next ID.
+func (k Keeper) AddRecord(ctx sdk.Context, r types.Record) types.Record {
+	r.Id = k.nextRecordID(ctx)
+	r.Height = ctx.BlockHeight()
+	r.Time = ctx.BlockTime()
+	k.SetRecord(ctx, r)
+	k.SetNextRecordID(ctx, r.Id+1)
+	return r
+}
+
+// SetNextRecordID sets the ID of the next record.
+func (k Keeper) SetNextRecordID(ctx sdk.Context, id uint64) {
+	ctx.KVStore(k.storeKey).Set(types.NextRecordIDKey, sdk.Uint64ToBigEndian(id))
+}
+
+func (k Keeper) nextRecordID(ctx sdk.Context) uint64 {
+	bz := ctx.KVStore(k.storeKey).Get(types.NextRecordIDKey)
+	if bz == nil {
+		return 1
+	}
+	return sdk.BigEndianToUint64(bz)
+}
+
+// SetRecord sets the record and its index.
+func (k Keeper) SetRecord(ctx sdk.Context, r types.Record) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.RecordKey(r.Id), k.cdc.MustMarshalBinaryBare(&r))
+	store.Set(types.RecordIndexKey(r), []byte{})
+}
+
+// GetRecord returns the record of the ID.
+func (k Keeper) GetRecord(ctx sdk.Context, id uint64) (types.Record, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.RecordKey(id))
+	if bz == nil {
+		return types.Record{}, false
+	}
+	var r types.Record
+	k.cdc.MustUnmarshalBinaryBare(bz, &r)
+	return r, true
+}
+
+// IterateRecords iterates over the records in the order of their IDs.
+func (k Keeper) IterateRecords(ctx sdk.Context, cb func(r types.Record) (stop bool)) {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.RecordKeyPrefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var r types.Record
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &r)
+		if cb(r) {
+			break
+		}
+	}
+}
+
+// ProposalID returns the ID of the proposal of the content that is being executed by the gov
+// module; 0 if there is none, e.g. as the content is not executed by the gov module.
+//
+// The gov EndBlocker executes the proposals whose voting period ended in the order of the queue
+// of the active proposals, and removes each of them from the queue before executing the next
+// one, so the proposal being executed is the first of the queue. Its content is compared to the
+// one being executed, so that a content executed outside of the gov EndBlocker, e.g. by the
+// EndBlocker of another module, isn't taken for the proposal. Two proposals of the same content
+// get their own IDs.
+func (k Keeper) ProposalID(ctx sdk.Context, content govtypes.Content) uint64 {
+	msg, ok := content.(proto.Message)
+	if !ok {
+		return 0
+	}
+	var id uint64
+	k.govKeeper.IterateActiveProposalsQueue(ctx, ctx.BlockTime(), func(p govtypes.Proposal) bool {
+		if other, ok := p.GetContent().(proto.Message); ok && proto.Equal(other, msg) {
+			id = p.ProposalId
+		}
+		return true
+	})
+	return id
+}
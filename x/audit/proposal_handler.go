@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/line/lbm-sdk/codec"
+	sdk "github.com/line/lbm-sdk/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramproposal "github.com/line/lbm-sdk/x/params/types/proposal"
+	upgradetypes "github.com/line/lbm-sdk/x/upgrade/types"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+
+	"github.com/line/lfb/x/audit/keeper"
+	"github.com/line/lfb/x/audit/types"
+)
+
+// value reads the value of a key changed by a proposal.
+type value struct {
+	module string
+	key    string
+	get    func(ctx sdk.Context) string
+}
+
+// recordChanges executes the proposal with the handler and, if it succeeds, records the changes
+// of the values. The records are written in the context the handler writes its changes in, so
+// that they are discarded with the changes if the proposal fails.
+func recordChanges(ctx sdk.Context, k keeper.Keeper, next govtypes.Handler, content govtypes.Content,
+	kind types.Kind, values []value) error {
+	old := make([]string, len(values))
+	for i, v := range values {
+		old[i] = v.get(ctx)
+	}
+	if err := next(ctx, content); err != nil {
+		return err
+	}
+
+	proposalID := k.ProposalID(ctx, content)
+	for i, v := range values {
+		r := k.AddRecord(ctx, types.Record{
+			Kind:       kind,
+			ProposalId: proposalID,
+			Module:     v.module,
+			Key:        v.key,
+			OldValue:   old[i],
+			NewValue:   v.get(ctx),
+		})
+		k.Logger(ctx).Info("recorded change", "id", r.Id, "proposal", proposalID, "module", v.module, "key", v.key)
+	}
+	return nil
+}
+
+// NewParamChangeProposalHandler wraps the handler of the param change proposals so that the
+// changes of the params are recorded.
+func NewParamChangeProposalHandler(k keeper.Keeper, pk types.ParamsKeeper, next govtypes.Handler) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) error {
+		c, ok := content.(*paramproposal.ParameterChangeProposal)
+		if !ok {
+			return next(ctx, content)
+		}
+		values := make([]value, len(c.Changes))
+		for i, change := range c.Changes {
+			subspace, key := change.Subspace, change.Key
+			values[i] = value{module: subspace, key: key, get: func(ctx sdk.Context) string {
+				ss, ok := pk.GetSubspace(subspace)
+				if !ok {
+					return ""
+				}
+				return string(ss.GetRaw(ctx, []byte(key)))
+			}}
+		}
+		return recordChanges(ctx, k, next, content, types.KindParamChange, values)
+	}
+}
+
+// NewWasmProposalHandler wraps the handler of the wasm proposals so that the changes of the
+// admins, codes and statuses of the contracts and of the pinned codes are recorded.
+func NewWasmProposalHandler(k keeper.Keeper, wk types.WasmKeeper, next govtypes.Handler) govtypes.Handler {
+	contract := func(addr, field string, get func(info *wasmtypes.ContractInfo) string) value {
+		return value{module: wasmtypes.ModuleName, key: addr + "/" + field, get: func(ctx sdk.Context) string {
+			if err := sdk.ValidateAccAddress(addr); err != nil {
+				return ""
+			}
+			info := wk.GetContractInfo(ctx, sdk.AccAddress(addr))
+			if info == nil {
+				return ""
+			}
+			return get(info)
+		}}
+	}
+	admin := func(info *wasmtypes.ContractInfo) string { return info.Admin }
+	codes := func(ids []uint64) []value {
+		values := make([]value, len(ids))
+		for i, id := range ids {
+			id := id
+			values[i] = value{module: wasmtypes.ModuleName, key: fmt.Sprintf("code/%d/pinned", id), get: func(ctx sdk.Context) string {
+				return fmt.Sprint(wk.IsPinnedCode(ctx, id))
+			}}
+		}
+		return values
+	}
+
+	return func(ctx sdk.Context, content govtypes.Content) error {
+		var values []value
+		switch c := content.(type) {
+		case *wasmtypes.UpdateAdminProposal:
+			values = []value{contract(c.Contract, "admin", admin)}
+		case *wasmtypes.ClearAdminProposal:
+			values = []value{contract(c.Contract, "admin", admin)}
+		case *wasmtypes.MigrateContractProposal:
+			values = []value{contract(c.Contract, "code_id", func(info *wasmtypes.ContractInfo) string {
+				return fmt.Sprint(info.CodeID)
+			})}
+		case *wasmtypes.UpdateContractStatusProposal:
+			values = []value{contract(c.Contract, "status", func(info *wasmtypes.ContractInfo) string {
+				return info.Status.String()
+			})}
+		case *wasmtypes.PinCodesProposal:
+			values = codes(c.CodeIDs)
+		case *wasmtypes.UnpinCodesProposal:
+			values = codes(c.CodeIDs)
+		default:
+			return next(ctx, content)
+		}
+		return recordChanges(ctx, k, next, content, types.KindWasmAdmin, values)
+	}
+}
+
+// NewUpgradeProposalHandler wraps the handler of the upgrade proposals so that the changes of the
+// upgrade plan are recorded, the plan as JSON.
+func NewUpgradeProposalHandler(k keeper.Keeper, uk types.UpgradeKeeper, cdc codec.JSONMarshaler, next govtypes.Handler) govtypes.Handler {
+	plan := value{module: upgradetypes.ModuleName, key: "plan", get: func(ctx sdk.Context) string {
+		p, ok := uk.GetUpgradePlan(ctx)
+		if !ok {
+			return ""
+		}
+		return string(cdc.MustMarshalJSON(&p))
+	}}
+
+	return func(ctx sdk.Context, content govtypes.Content) error {
+		switch content.(type) {
+		case *upgradetypes.SoftwareUpgradeProposal, *upgradetypes.CancelSoftwareUpgradeProposal:
+			return recordChanges(ctx, k, next, content, types.KindUpgradePlan, []value{plan})
+		default:
+			return next(ctx, content)
+		}
+	}
+}
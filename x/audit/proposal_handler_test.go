@@ -0,0 +1,113 @@
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/crypto/keys/ed25519"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/x/gov"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramproposal "github.com/line/lbm-sdk/x/params/types/proposal"
+	"github.com/line/lbm-sdk/x/staking"
+	"github.com/line/lbm-sdk/x/staking/teststaking"
+	upgradetypes "github.com/line/lbm-sdk/x/upgrade/types"
+
+	"github.com/line/lfb/app"
+	"github.com/line/lfb/x/audit/types"
+)
+
+// setup returns an app with a validator whose operator votes for the proposals.
+func setup(t *testing.T) (*app.LinkApp, sdk.Context, func(content govtypes.Content) uint64) {
+	linkApp := app.Setup(false)
+	ctx := linkApp.BaseApp.NewContext(false, ocproto.Header{Height: 1, Time: time.Now().UTC()})
+	addr := app.AddTestAddrs(linkApp, ctx, 1, sdk.TokensFromConsensusPower(200))[0]
+	teststaking.NewHelper(t, ctx, linkApp.StakingKeeper).CreateValidatorWithValPower(addr.ToValAddress(), ed25519.GenPrivKey().PubKey(), 100, true)
+	staking.EndBlocker(ctx, linkApp.StakingKeeper)
+
+	submit := func(content govtypes.Content) uint64 {
+		proposal, err := linkApp.GovKeeper.SubmitProposal(ctx, content)
+		require.NoError(t, err)
+		linkApp.GovKeeper.ActivateVotingPeriod(ctx, proposal)
+		require.NoError(t, linkApp.GovKeeper.AddVote(ctx, proposal.ProposalId, addr, govtypes.OptionYes))
+		return proposal.ProposalId
+	}
+	return linkApp, ctx, submit
+}
+
+// endVotingPeriod executes the proposals at the end of their voting period.
+func endVotingPeriod(linkApp *app.LinkApp, ctx sdk.Context) sdk.Context {
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1).
+		WithBlockTime(ctx.BlockTime().Add(linkApp.GovKeeper.GetVotingParams(ctx).VotingPeriod))
+	gov.EndBlocker(ctx, linkApp.GovKeeper)
+	return ctx
+}
+
+func records(linkApp *app.LinkApp, ctx sdk.Context) []types.Record {
+	var records []types.Record
+	linkApp.AuditKeeper.IterateRecords(ctx, func(r types.Record) bool {
+		records = append(records, r)
+		return false
+	})
+	return records
+}
+
+func TestParamChangeRecords(t *testing.T) {
+	linkApp, ctx, submit := setup(t)
+	oldMaxValidators := linkApp.StakingKeeper.MaxValidators(ctx)
+
+	change := paramproposal.NewParameterChangeProposal("max validators", "max validators", []paramproposal.ParamChange{
+		{Subspace: "staking", Key: "MaxValidators", Value: "10"},
+	})
+	// the plan is in the past when the proposal is executed
+	invalid := upgradetypes.NewSoftwareUpgradeProposal("upgrade", "upgrade", upgradetypes.Plan{Name: "v2", Height: ctx.BlockHeight() + 1})
+	first := submit(change)
+	failed := submit(invalid)
+	// the same content again
+	second := submit(change)
+	ctx = endVotingPeriod(linkApp, ctx)
+
+	proposal, _ := linkApp.GovKeeper.GetProposal(ctx, failed)
+	require.Equal(t, govtypes.StatusFailed, proposal.Status)
+	require.Equal(t, uint32(10), linkApp.StakingKeeper.MaxValidators(ctx))
+	require.Equal(t, []types.Record{
+		{
+			Id: 1, Kind: types.KindParamChange, ProposalId: first, Height: ctx.BlockHeight(), Time: ctx.BlockTime(),
+			Module: "staking", Key: "MaxValidators", OldValue: sdk.NewInt(int64(oldMaxValidators)).String(), NewValue: "10",
+		},
+		{
+			Id: 2, Kind: types.KindParamChange, ProposalId: second, Height: ctx.BlockHeight(), Time: ctx.BlockTime(),
+			Module: "staking", Key: "MaxValidators", OldValue: "10", NewValue: "10",
+		},
+	}, records(linkApp, ctx))
+}
+
+func TestUpgradePlanRecords(t *testing.T) {
+	linkApp, ctx, submit := setup(t)
+
+	id := submit(upgradetypes.NewSoftwareUpgradeProposal("upgrade", "upgrade", upgradetypes.Plan{Name: "v2", Height: 100}))
+	ctx = endVotingPeriod(linkApp, ctx)
+
+	rs := records(linkApp, ctx)
+	require.Len(t, rs, 1)
+	require.Equal(t, types.KindUpgradePlan, rs[0].Kind)
+	require.Equal(t, id, rs[0].ProposalId)
+	require.Empty(t, rs[0].OldValue)
+	require.Contains(t, rs[0].NewValue, `"name":"v2"`)
+}
+
+func TestRecordsOutsideGov(t *testing.T) {
+	linkApp, ctx, _ := setup(t)
+
+	// a proposal executed without the gov module, e.g. by a schedule, has no proposal ID
+	handler := linkApp.GovKeeper.Router().GetRoute(paramproposal.RouterKey)
+	require.NoError(t, handler(ctx, paramproposal.NewParameterChangeProposal("max validators", "max validators", []paramproposal.ParamChange{
+		{Subspace: "staking", Key: "MaxValidators", Value: "10"},
+	})))
+	rs := records(linkApp, ctx)
+	require.Len(t, rs, 1)
+	require.Zero(t, rs[0].ProposalId)
+}
@@ -0,0 +1,16 @@
+package types
+
+import (
+	"fmt"
+)
+
+// Validate performs a basic validation of the record.
+func (r Record) Validate() error {
+	if _, ok := Kind_name[int32(r.Kind)]; !ok || r.Kind == KindUnspecified {
+		return fmt.Errorf("invalid kind of the record %d: %s", r.Id, r.Kind)
+	}
+	if r.Module == "" || len(r.Module) > 255 {
+		return fmt.Errorf("invalid module of the record %d: %q", r.Id, r.Module)
+	}
+	return nil
+}
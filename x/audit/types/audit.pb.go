@@ -0,0 +1,734 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: lfb/audit/v1/audit.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	time "time"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Kind is the kind of the governance proposal a change is made by.
+type Kind int32
+
+const (
+	// KIND_UNSPECIFIED defines no kind.
+	KindUnspecified Kind = 0
+	// KIND_PARAM_CHANGE defines a change of a param by a parameter change proposal.
+	KindParamChange Kind = 1
+	// KIND_WASM_ADMIN defines a change of a contract or a code by a wasm admin proposal.
+	KindWasmAdmin Kind = 2
+	// KIND_UPGRADE_PLAN defines a change of the upgrade plan by a software upgrade proposal.
+	KindUpgradePlan Kind = 3
+)
+
+var Kind_name = map[int32]string{
+	0: "KIND_UNSPECIFIED",
+	1: "KIND_PARAM_CHANGE",
+	2: "KIND_WASM_ADMIN",
+	3: "KIND_UPGRADE_PLAN",
+}
+
+var Kind_value = map[string]int32{
+	"KIND_UNSPECIFIED":  0,
+	"KIND_PARAM_CHANGE": 1,
+	"KIND_WASM_ADMIN":   2,
+	"KIND_UPGRADE_PLAN": 3,
+}
+
+func (x Kind) String() string {
+	return proto.EnumName(Kind_name, int32(x))
+}
+
+func (Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6a312854ad039d93, []int{0}
+}
+
+// Record is the record of a change of a value by a governance proposal.
+type Record struct {
+	// id is the sequence number of the record.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// kind is the kind of the proposal.
+	Kind Kind `protobuf:"varint,2,opt,name=kind,proto3,enum=lfb.audit.v1.Kind" json:"kind,omitempty"`
+	// proposal_id is the ID of the proposal; 0 if the change is not made by a proposal of the gov module.
+	ProposalId uint64 `protobuf:"varint,3,opt,name=proposal_id,json=proposalId,proto3" json:"proposal_id,omitempty" yaml:"proposal_id"`
+	// height is the height of the block the change is made at.
+	Height int64 `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	// time is the time of the block the change is made at.
+	Time time.Time `protobuf:"bytes,5,opt,name=time,proto3,stdtime" json:"time"`
+	// module is the module the value belongs to: the subspace of a param, wasm or upgrade.
+	Module string `protobuf:"bytes,6,opt,name=module,proto3" json:"module,omitempty"`
+	// key identifies the value in the module: the key of a param, the address of a contract or the ID
+	// of a code with the changed attribute, e.g. link1.../admin or code/1/pinned, or plan.
+	Key string `protobuf:"bytes,7,opt,name=key,proto3" json:"key,omitempty"`
+	// old_value is the value before the change as JSON; empty if there was none.
+	OldValue string `protobuf:"bytes,8,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty" yaml:"old_value"`
+	// new_value is the value after the change as JSON; empty if there is none.
+	NewValue string `protobuf:"bytes,9,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty" yaml:"new_value"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6a312854ad039d93, []int{0}
+}
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(m, src)
+}
+func (m *Record) XXX_Size() int {
+	return m.Size()
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Record) GetKind() Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return KindUnspecified
+}
+
+func (m *Record) GetProposalId() uint64 {
+	if m != nil {
+		return m.ProposalId
+	}
+	return 0
+}
+
+func (m *Record) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Record) GetTime() time.Time {
+	if m != nil {
+		return m.Time
+	}
+	return time.Time{}
+}
+
+func (m *Record) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *Record) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Record) GetOldValue() string {
+	if m != nil {
+		return m.OldValue
+	}
+	return ""
+}
+
+func (m *Record) GetNewValue() string {
+	if m != nil {
+		return m.NewValue
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("lfb.audit.v1.Kind", Kind_name, Kind_value)
+	proto.RegisterType((*Record)(nil), "lfb.audit.v1.Record")
+}
+
+func init() { proto.RegisterFile("lfb/audit/v1/audit.proto", fileDescriptor_6a312854ad039d93) }
+
+var fileDescriptor_6a312854ad039d93 = []byte{
+	// 522 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x52, 0xcd, 0x6e, 0xda, 0x4c,
+	0x14, 0xf5, 0x80, 0x3f, 0x3e, 0x32, 0x69, 0x13, 0x67, 0x1a, 0x45, 0x96, 0x17, 0xc6, 0x65, 0x11,
+	0xd1, 0x2c, 0x6c, 0x91, 0x2e, 0x5a, 0xb5, 0x2b, 0xf3, 0xd3, 0x14, 0xa5, 0x20, 0xcb, 0x29, 0x8d,
+	0xd4, 0x8d, 0x65, 0x98, 0xc1, 0x8c, 0x32, 0xf6, 0x58, 0x60, 0x48, 0x79, 0x83, 0x2a, 0xab, 0xbc,
+	0x40, 0x56, 0x7d, 0x88, 0xbe, 0x42, 0x96, 0x59, 0x56, 0xaa, 0x44, 0x2b, 0x78, 0x83, 0x3c, 0x41,
+	0x35, 0x36, 0x50, 0xd4, 0xdd, 0x39, 0xf7, 0x9c, 0x73, 0xe7, 0x5e, 0xfb, 0x42, 0x95, 0x0d, 0x7a,
+	0x96, 0x3f, 0xc1, 0x34, 0xb1, 0xa6, 0xd5, 0x0c, 0x98, 0xf1, 0x88, 0x27, 0x1c, 0x3d, 0x61, 0x83,
+	0x9e, 0x99, 0x15, 0xa6, 0x55, 0xed, 0x30, 0xe0, 0x01, 0x4f, 0x05, 0x4b, 0xa0, 0xcc, 0xa3, 0x95,
+	0x02, 0xce, 0x03, 0x46, 0xac, 0x94, 0xf5, 0x26, 0x03, 0x2b, 0xa1, 0x21, 0x19, 0x27, 0x7e, 0x18,
+	0x67, 0x86, 0xf2, 0xcf, 0x1c, 0x2c, 0xb8, 0xa4, 0xcf, 0x47, 0x18, 0xed, 0xc1, 0x1c, 0xc5, 0x2a,
+	0x30, 0x40, 0x45, 0x76, 0x73, 0x14, 0xa3, 0x63, 0x28, 0x5f, 0xd1, 0x08, 0xab, 0x39, 0x03, 0x54,
+	0xf6, 0x4e, 0x91, 0xb9, 0xfd, 0x9c, 0x79, 0x4e, 0x23, 0xec, 0xa6, 0x3a, 0x7a, 0x05, 0x77, 0xe3,
+	0x11, 0x8f, 0xf9, 0xd8, 0x67, 0x1e, 0xc5, 0x6a, 0x5e, 0x34, 0xa8, 0x1d, 0x3d, 0xce, 0x4b, 0x68,
+	0xe6, 0x87, 0xec, 0x4d, 0x79, 0x4b, 0x2c, 0xbb, 0x70, 0xcd, 0x5a, 0x18, 0x1d, 0xc1, 0xc2, 0x90,
+	0xd0, 0x60, 0x98, 0xa8, 0xb2, 0x01, 0x2a, 0x79, 0x77, 0xc5, 0xd0, 0x6b, 0x28, 0x8b, 0x31, 0xd5,
+	0xff, 0x0c, 0x50, 0xd9, 0x3d, 0xd5, 0xcc, 0x6c, 0x07, 0x73, 0xbd, 0x83, 0xf9, 0x71, 0xbd, 0x43,
+	0xad, 0x78, 0x3f, 0x2f, 0x49, 0xb7, 0xbf, 0x4a, 0xc0, 0x4d, 0x13, 0xa2, 0x63, 0xc8, 0xf1, 0x84,
+	0x11, 0xb5, 0x60, 0x80, 0xca, 0x8e, 0xbb, 0x62, 0x48, 0x81, 0xf9, 0x2b, 0x32, 0x53, 0xff, 0x4f,
+	0x8b, 0x02, 0xa2, 0x2a, 0xdc, 0xe1, 0x0c, 0x7b, 0x53, 0x9f, 0x4d, 0x88, 0x5a, 0x14, 0xf5, 0xda,
+	0xe1, 0xe3, 0xbc, 0xa4, 0x64, 0x23, 0x6f, 0xa4, 0xb2, 0x5b, 0xe4, 0x0c, 0x7f, 0x12, 0x50, 0x44,
+	0x22, 0x72, 0xbd, 0x8a, 0xec, 0xfc, 0x1b, 0xd9, 0x48, 0x65, 0xb7, 0x18, 0x91, 0xeb, 0x34, 0x72,
+	0xf2, 0x1d, 0x40, 0x59, 0x7c, 0x29, 0xf4, 0x02, 0x2a, 0xe7, 0xad, 0x4e, 0xc3, 0xeb, 0x76, 0x2e,
+	0x9c, 0x66, 0xbd, 0xf5, 0xae, 0xd5, 0x6c, 0x28, 0x92, 0xf6, 0xec, 0xe6, 0xce, 0xd8, 0x17, 0x7a,
+	0x37, 0x1a, 0xc7, 0xa4, 0x4f, 0x07, 0x94, 0x60, 0x74, 0x02, 0x0f, 0x52, 0xab, 0x63, 0xbb, 0x76,
+	0xdb, 0xab, 0xbf, 0xb7, 0x3b, 0x67, 0x4d, 0x05, 0xfc, 0xf5, 0x3a, 0xfe, 0xc8, 0x0f, 0xeb, 0x43,
+	0x3f, 0x0a, 0x08, 0x3a, 0x86, 0xfb, 0xa9, 0xf7, 0xd2, 0xbe, 0x68, 0x7b, 0x76, 0xa3, 0xdd, 0xea,
+	0x28, 0x39, 0xed, 0xe0, 0xe6, 0xce, 0x78, 0x2a, 0x9c, 0x97, 0xfe, 0x38, 0xb4, 0x71, 0x48, 0xa3,
+	0x4d, 0xcf, 0xae, 0x73, 0xe6, 0xda, 0x8d, 0xa6, 0xe7, 0x7c, 0xb0, 0x3b, 0x4a, 0x7e, 0xeb, 0xfd,
+	0x38, 0x18, 0xf9, 0x98, 0x38, 0xcc, 0x8f, 0x34, 0xf9, 0xeb, 0x37, 0x5d, 0xaa, 0xbd, 0xbd, 0x5f,
+	0xe8, 0xe0, 0x61, 0xa1, 0x83, 0xdf, 0x0b, 0x1d, 0xdc, 0x2e, 0x75, 0xe9, 0x61, 0xa9, 0x4b, 0x3f,
+	0x96, 0xba, 0xf4, 0xf9, 0x79, 0x40, 0x93, 0xe1, 0xa4, 0x67, 0xf6, 0x79, 0x68, 0x31, 0x1a, 0x11,
+	0x4b, 0x1c, 0xe8, 0x97, 0xd5, 0x89, 0x26, 0xb3, 0x98, 0x8c, 0x7b, 0x85, 0xf4, 0x57, 0xbd, 0xfc,
+	0x33, 0x00, 0x54, 0x02, 0xb0, 0xfa, 0xbc, 0x02, 0x00, 0x00,
+}
+
+func (m *Record) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Record) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Record) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NewValue) > 0 {
+		i -= len(m.NewValue)
+		copy(dAtA[i:], m.NewValue)
+		i = encodeVarintAudit(dAtA, i, uint64(len(m.NewValue)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.OldValue) > 0 {
+		i -= len(m.OldValue)
+		copy(dAtA[i:], m.OldValue)
+		i = encodeVarintAudit(dAtA, i, uint64(len(m.OldValue)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintAudit(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Module) > 0 {
+		i -= len(m.Module)
+		copy(dAtA[i:], m.Module)
+		i = encodeVarintAudit(dAtA, i, uint64(len(m.Module)))
+		i--
+		dAtA[i] = 0x32
+	}
+	n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err1 != nil {
+		return 0, err1
+	}
+	i -= n1
+	i = encodeVarintAudit(dAtA, i, uint64(n1))
+	i--
+	dAtA[i] = 0x2a
+	if m.Height != 0 {
+		i = encodeVarintAudit(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ProposalId != 0 {
+		i = encodeVarintAudit(dAtA, i, uint64(m.ProposalId))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Kind != 0 {
+		i = encodeVarintAudit(dAtA, i, uint64(m.Kind))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Id != 0 {
+		i = encodeVarintAudit(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintAudit(dAtA []byte, offset int, v uint64) int {
+	offset -= sovAudit(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Record) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != 0 {
+		n += 1 + sovAudit(uint64(m.Id))
+	}
+	if m.Kind != 0 {
+		n += 1 + sovAudit(uint64(m.Kind))
+	}
+	if m.ProposalId != 0 {
+		n += 1 + sovAudit(uint64(m.ProposalId))
+	}
+	if m.Height != 0 {
+		n += 1 + sovAudit(uint64(m.Height))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Time)
+	n += 1 + l + sovAudit(uint64(l))
+	l = len(m.Module)
+	if l > 0 {
+		n += 1 + l + sovAudit(uint64(l))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovAudit(uint64(l))
+	}
+	l = len(m.OldValue)
+	if l > 0 {
+		n += 1 + l + sovAudit(uint64(l))
+	}
+	l = len(m.NewValue)
+	if l > 0 {
+		n += 1 + l + sovAudit(uint64(l))
+	}
+	return n
+}
+
+func sovAudit(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozAudit(x uint64) (n int) {
+	return sovAudit(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Record) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAudit
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Record: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Record: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+			}
+			m.Kind = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Kind |= Kind(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProposalId", wireType)
+			}
+			m.ProposalId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProposalId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Time, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Module", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Module = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OldValue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAudit
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewValue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAudit(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAudit
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipAudit(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowAudit
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowAudit
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthAudit
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupAudit
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthAudit
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthAudit        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowAudit          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupAudit = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/line/lbm-sdk/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramstypes "github.com/line/lbm-sdk/x/params/types"
+	upgradetypes "github.com/line/lbm-sdk/x/upgrade/types"
+	wasmtypes "github.com/line/lbm-sdk/x/wasm/types"
+)
+
+// GovKeeper defines the gov keeper the IDs of the proposals being executed are read from.
+type GovKeeper interface {
+	IterateActiveProposalsQueue(ctx sdk.Context, endTime time.Time, cb func(proposal govtypes.Proposal) (stop bool))
+}
+
+// ParamsKeeper defines the params keeper the values of the params are read from.
+type ParamsKeeper interface {
+	GetSubspace(s string) (*paramstypes.Subspace, bool)
+}
+
+// WasmKeeper defines the wasm keeper the contracts and the codes are read from.
+type WasmKeeper interface {
+	GetContractInfo(ctx sdk.Context, contractAddress sdk.AccAddress) *wasmtypes.ContractInfo
+	IsPinnedCode(ctx sdk.Context, codeID uint64) bool
+}
+
+// UpgradeKeeper defines the upgrade keeper the upgrade plan is read from.
+type UpgradeKeeper interface {
+	GetUpgradePlan(ctx sdk.Context) (plan upgradetypes.Plan, havePlan bool)
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"fmt"
+)
+
+// NewGenesisState creates a new genesis state.
+func NewGenesisState(records []Record) *GenesisState {
+	return &GenesisState{Records: records}
+}
+
+// DefaultGenesisState returns a default genesis state, without records.
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{}
+}
+
+// Validate performs basic genesis state validation, returning an error upon any failure.
+func (gs GenesisState) Validate() error {
+	var last uint64
+	for _, r := range gs.Records {
+		if r.Id <= last {
+			return fmt.Errorf("the IDs of the records must be positive and increasing: %d after %d", r.Id, last)
+		}
+		last = r.Id
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
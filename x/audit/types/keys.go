@@ -0,0 +1,47 @@
+package types
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+const (
+	// ModuleName is the name of the audit module
+	ModuleName = "audit"
+
+	// StoreKey is the store key string for the audit module
+	StoreKey = ModuleName
+
+	// QuerierRoute is the querier route for the audit module
+	QuerierRoute = ModuleName
+)
+
+// The prefixes of the keys of the store:
+//
+//	RecordKeyPrefix | id                                  -> Record
+//	RecordIndexKeyPrefix | kind | len(module) | module | id -> nothing
+//	NextRecordIDKey                                       -> the ID of the next record
+var (
+	RecordKeyPrefix      = []byte{0x01}
+	RecordIndexKeyPrefix = []byte{0x02}
+	NextRecordIDKey      = []byte{0x03}
+)
+
+// RecordKey returns the key of the record of the ID.
+func RecordKey(id uint64) []byte {
+	return append(append([]byte{}, RecordKeyPrefix...), sdk.Uint64ToBigEndian(id)...)
+}
+
+// RecordIndexPrefix returns the prefix of the index keys of the records of the kind and module.
+// The module is left out if it is empty.
+func RecordIndexPrefix(kind Kind, module string) []byte {
+	key := append(append([]byte{}, RecordIndexKeyPrefix...), byte(kind))
+	if module == "" {
+		return key
+	}
+	return append(append(key, byte(len(module))), module...)
+}
+
+// RecordIndexKey returns the index key of the record.
+func RecordIndexKey(r Record) []byte {
+	return append(RecordIndexPrefix(r.Kind, r.Module), sdk.Uint64ToBigEndian(r.Id)...)
+}
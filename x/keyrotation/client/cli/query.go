@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/x/keyrotation/types"
+)
+
+// GetQueryCmd returns the CLI command with all keyrotation module query commands mounted.
+func GetQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Query the rotations of the validator consensus keys",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		GetCmdQueryParams(),
+		GetCmdQueryRotations(),
+		GetCmdQueryRotation(),
+	)
+	return cmd
+}
+
+// GetCmdQueryParams returns the command showing the params of the keyrotation module.
+func GetCmdQueryParams() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "params",
+		Short:   "Query the params of the keyrotation module",
+		Example: fmt.Sprintf("$ %s query %s params", version.AppName, types.ModuleName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Params(cmd.Context(), &types.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(&res.Params)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// GetCmdQueryRotations returns the command listing the last rotations of the validators.
+func GetCmdQueryRotations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rotations",
+		Short:   "Query the last consensus key rotations of the validators",
+		Example: fmt.Sprintf("$ %s query %s rotations", version.AppName, types.ModuleName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Rotations(cmd.Context(), &types.QueryRotationsRequest{Pagination: pageReq})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "rotations")
+	return cmd
+}
+
+// GetCmdQueryRotation returns the command showing the last rotation of a validator.
+func GetCmdQueryRotation() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rotation [validator-addr]",
+		Short:   "Query the last consensus key rotation of a validator",
+		Example: fmt.Sprintf("$ %s query %s rotation linkvaloper1...", version.AppName, types.ModuleName),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			if err := sdk.ValidateValAddress(args[0]); err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Rotation(cmd.Context(), &types.QueryRotationRequest{ValidatorAddress: args[0]})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(&res.Rotation)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/client/tx"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/x/keyrotation/types"
+)
+
+// GetTxCmd returns the transaction commands of the keyrotation module.
+func GetTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Validator consensus key rotation transaction subcommands",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		NewRotateConsPubKeyCmd(),
+	)
+	return cmd
+}
+
+// NewRotateConsPubKeyCmd returns the command rotating the consensus key of the validator of the
+// sender.
+func NewRotateConsPubKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-cons-pubkey [new-pubkey]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Rotate the consensus key of the validator operated by the sender",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Replace the consensus key of the validator operated by the sender with the new key,
+given in its bech32 form as printed by "%[1]s tendermint show-validator --home <new-home>".
+The validator keeps its delegations. The validator set is updated with the new key at the
+end of the block, so the node must be switched to the new key by then to keep signing
+blocks. A validator may rotate its key once per cooldown period.
+`, version.AppName),
+		),
+		Example: fmt.Sprintf("$ %s tx %s rotate-cons-pubkey linkvalconspub1... --from mykey", version.AppName, types.ModuleName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeConsPub, args[0])
+			if err != nil {
+				return err
+			}
+
+			msg, err := types.NewMsgRotateConsPubKey(clientCtx.GetFromAddress().ToValAddress(), pk)
+			if err != nil {
+				return err
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
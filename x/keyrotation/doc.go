@@ -0,0 +1,30 @@
+/*
+Package keyrotation implements the rotation of the consensus key of a validator, so that a
+validator with a compromised key replaces it while keeping its delegations.
+
+A MsgRotateConsPubKey signed by the operator of a validator replaces its consensus key in the
+staking state and starts a signing info for the new consensus address in the slashing state,
+keeping the jail of the old one. The old consensus address still resolves to the validator, so
+that the votes and the evidence of the blocks signed with the old key are handled, and can't be
+taken by another validator. At the end of the block, StakingModule, which wraps the staking module
+in the module manager, amends the validator set updates of ApplyAndReturnValidatorSetUpdates:
+the old key of a bonded validator is removed from the validator set and the new key added with
+its power. A validator rotates its key once per cooldown_period.
+
+The evidence of a double sign with an old key tombstones and jails the old consensus address
+only. Until the evidence of the old key is too old to be submitted, the BeginBlocker of the
+module, which runs after the one of the evidence module, carries the tombstone and the jail of
+the old address over to the current address of the validator, so that a validator can't escape
+the slashing of a double sign by rotating its key.
+
+The node of the validator must sign with the new key from the second block after the rotation,
+when the validator set update takes effect.
+
+The rotations are sent with:
+
+	lfb tx keyrotation rotate-cons-pubkey <new-pubkey> --from <operator>
+
+and the last rotation of each validator is queried with lfb query keyrotation rotations or the
+/lfb/keyrotation/v1/rotations route of the gRPC Gateway.
+*/
+package keyrotation
@@ -0,0 +1,44 @@
+package keyrotation
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+
+	"github.com/line/lfb/x/keyrotation/keeper"
+	"github.com/line/lfb/x/keyrotation/types"
+)
+
+// InitGenesis initializes the keyrotation module's state from a given genesis state. The
+// validators of the recent rotations are found by their old consensus keys again, so it must run
+// after the InitGenesis of the staking and slashing modules.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, gs *types.GenesisState) {
+	if err := gs.Validate(); err != nil {
+		panic(err)
+	}
+	k.SetParams(ctx, gs.Params)
+	for _, r := range gs.Rotations {
+		k.SetRotation(ctx, r)
+	}
+	for _, r := range gs.RecentRotations {
+		k.SetRecentRotation(ctx, r)
+		if err := k.SetOldConsPubKey(ctx, r); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ExportGenesis returns the keyrotation module's exported genesis.
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	var rotations []types.Rotation
+	k.IterateRotations(ctx, func(r types.Rotation) bool {
+		rotations = append(rotations, r)
+		return false
+	})
+	var recentRotations []types.Rotation
+	k.IterateRecentRotations(ctx, func(r types.Rotation) bool {
+		recentRotations = append(recentRotations, r)
+		return false
+	})
+	gs := types.NewGenesisState(k.GetParams(ctx), rotations)
+	gs.RecentRotations = recentRotations
+	return gs
+}
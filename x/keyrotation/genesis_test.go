@@ -0,0 +1,75 @@
+package keyrotation_test
+
+import (
+	"testing"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/line/tm-db/v2/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/crypto/keys/ed25519"
+	"github.com/line/lbm-sdk/simapp"
+	sdk "github.com/line/lbm-sdk/types"
+	evidencetypes "github.com/line/lbm-sdk/x/evidence/types"
+	"github.com/line/lbm-sdk/x/staking"
+	"github.com/line/lbm-sdk/x/staking/teststaking"
+
+	"github.com/line/lfb/app"
+)
+
+// TestExportImportRecentRotation checks that the evidence of an old consensus key still slashes
+// its validator after the chain is exported and imported again.
+func TestExportImportRecentRotation(t *testing.T) {
+	linkApp := app.Setup(false)
+	start := time.Now().UTC()
+	ctx := linkApp.BaseApp.NewContext(false, ocproto.Header{Height: 1, Time: start})
+	ctx = ctx.WithConsensusParams(linkApp.GetConsensusParams(ctx))
+
+	addr := app.AddTestAddrs(linkApp, ctx, 1, sdk.TokensFromConsensusPower(200))[0]
+	valAddr := addr.ToValAddress()
+	oldPubKey := ed25519.GenPrivKey().PubKey()
+	oldConsAddr := sdk.BytesToConsAddress(oldPubKey.Address())
+	teststaking.NewHelper(t, ctx, linkApp.StakingKeeper).CreateValidatorWithValPower(valAddr, oldPubKey, 100, true)
+	staking.EndBlocker(ctx, linkApp.StakingKeeper)
+
+	newPubKey := ed25519.GenPrivKey().PubKey()
+	newConsAddr := sdk.BytesToConsAddress(newPubKey.Address())
+	rotation, err := linkApp.KeyRotationKeeper.RotateConsPubKey(ctx, valAddr, newPubKey)
+	require.NoError(t, err)
+	linkApp.KeyRotationKeeper.ValidatorSetUpdates(ctx, func() []abci.ValidatorUpdate {
+		return staking.EndBlocker(ctx, linkApp.StakingKeeper)
+	})
+	linkApp.Commit()
+
+	exported, err := linkApp.ExportAppStateAndValidators(false, nil)
+	require.NoError(t, err)
+	imported := app.NewLinkApp(log.NewNopLogger(), memdb.NewDB(), nil, true, map[int64]bool{}, app.DefaultNodeHome, 5,
+		app.MakeEncodingConfig(), simapp.EmptyAppOptions{}, nil)
+	imported.InitChain(abci.RequestInitChain{
+		ConsensusParams: app.DefaultConsensusParams,
+		AppStateBytes:   exported.AppState,
+	})
+	ctx = imported.BaseApp.NewContext(false, ocproto.Header{Height: exported.Height, Time: start.Add(5 * time.Second)})
+	ctx = ctx.WithConsensusParams(imported.GetConsensusParams(ctx))
+
+	validator, found := imported.StakingKeeper.GetValidatorByConsAddr(ctx, oldConsAddr)
+	require.True(t, found)
+	require.Equal(t, valAddr, validator.GetOperator())
+
+	// the validator double signed with the old key before the rotation
+	imported.EvidenceKeeper.HandleEquivocationEvidence(ctx, &evidencetypes.Equivocation{
+		Height:           rotation.Height,
+		Time:             rotation.Time,
+		Power:            100,
+		ConsensusAddress: oldConsAddr.String(),
+	})
+	require.True(t, imported.SlashingKeeper.IsTombstoned(ctx, oldConsAddr))
+	imported.KeyRotationKeeper.BeginBlocker(ctx)
+	require.True(t, imported.SlashingKeeper.IsTombstoned(ctx, newConsAddr))
+	validator, found = imported.StakingKeeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, validator.IsJailed())
+}
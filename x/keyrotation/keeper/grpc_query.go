@@ -0,0 +1,64 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/line/lbm-sdk/store/prefix"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/query"
+
+	"github.com/line/lfb/x/keyrotation/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// Params implements the Query/Params gRPC method
+func (k Keeper) Params(c context.Context, req *types.QueryParamsRequest) (*types.QueryParamsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	return &types.QueryParamsResponse{Params: k.GetParams(sdk.UnwrapSDKContext(c))}, nil
+}
+
+// Rotations implements the Query/Rotations gRPC method
+func (k Keeper) Rotations(c context.Context, req *types.QueryRotationsRequest) (*types.QueryRotationsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.RotationKeyPrefix)
+	var rotations []types.Rotation
+	pageRes, err := query.Paginate(store, req.Pagination, func(_, value []byte) error {
+		var r types.Rotation
+		if err := k.cdc.UnmarshalBinaryBare(value, &r); err != nil {
+			return err
+		}
+		rotations = append(rotations, r)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &types.QueryRotationsResponse{Rotations: rotations, Pagination: pageRes}, nil
+}
+
+// Rotation implements the Query/Rotation gRPC method
+func (k Keeper) Rotation(c context.Context, req *types.QueryRotationRequest) (*types.QueryRotationResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	if err := sdk.ValidateValAddress(req.ValidatorAddress); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid validator address %s: %s", req.ValidatorAddress, err)
+	}
+
+	r, found := k.GetRotation(sdk.UnwrapSDKContext(c), sdk.ValAddress(req.ValidatorAddress))
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no rotation of validator %s", req.ValidatorAddress)
+	}
+	return &types.QueryRotationResponse{Rotation: r}, nil
+}
@@ -0,0 +1,388 @@
+package keeper
+
+import (
+	"fmt"
+
+	abci "github.com/line/ostracon/abci/types"
+	"github.com/line/ostracon/libs/log"
+	ocstrings "github.com/line/ostracon/libs/strings"
+	ocprotocrypto "github.com/line/ostracon/proto/ostracon/crypto"
+
+	"github.com/line/lbm-sdk/codec"
+	codectypes "github.com/line/lbm-sdk/codec/types"
+	cryptocodec "github.com/line/lbm-sdk/crypto/codec"
+	cryptotypes "github.com/line/lbm-sdk/crypto/types"
+	sdk "github.com/line/lbm-sdk/types"
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+	paramtypes "github.com/line/lbm-sdk/x/params/types"
+	slashingtypes "github.com/line/lbm-sdk/x/slashing/types"
+
+	"github.com/line/lfb/x/keyrotation/types"
+)
+
+// Keeper of the keyrotation store
+type Keeper struct {
+	cdc            codec.Marshaler
+	storeKey       sdk.StoreKey
+	paramSpace     *paramtypes.Subspace
+	stakingKeeper  types.StakingKeeper
+	slashingKeeper types.SlashingKeeper
+}
+
+// NewKeeper creates a new keyrotation Keeper instance.
+func NewKeeper(cdc codec.Marshaler, key sdk.StoreKey, paramSpace *paramtypes.Subspace,
+	stakingKeeper types.StakingKeeper, slashingKeeper types.SlashingKeeper) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+	return Keeper{
+		cdc:            cdc,
+		storeKey:       key,
+		paramSpace:     paramSpace,
+		stakingKeeper:  stakingKeeper,
+		slashingKeeper: slashingKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the params of the keyrotation module.
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSet(ctx, &params)
+	return params
+}
+
+// SetParams sets the params of the keyrotation module.
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// RotateConsPubKey replaces the consensus key of the validator with the new key. The validator is
+// found by its new consensus address from then on, and by its old ones for the votes and the
+// evidence of the blocks signed with them. The signing info of the new consensus address starts
+// at the current height and keeps the jail of the old one; the tombstone and the jail set later
+// by the evidence of the old key are carried over by BeginBlocker. The validator set update is
+// emitted by ValidatorSetUpdates at the end of the block.
+func (k Keeper) RotateConsPubKey(ctx sdk.Context, valAddr sdk.ValAddress, newPubKey cryptotypes.PubKey) (types.Rotation, error) {
+	validator, found := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		return types.Rotation{}, sdkerrors.Wrapf(types.ErrValidatorNotFound, "validator %s", valAddr)
+	}
+	oldPubKey, err := validator.ConsPubKey()
+	if err != nil {
+		return types.Rotation{}, err
+	}
+	oldConsAddr := sdk.BytesToConsAddress(oldPubKey.Address())
+	newConsAddr := sdk.BytesToConsAddress(newPubKey.Address())
+
+	if k.slashingKeeper.IsTombstoned(ctx, oldConsAddr) {
+		return types.Rotation{}, sdkerrors.Wrapf(types.ErrValidatorTombstoned, "validator %s", valAddr)
+	}
+	if _, found := k.stakingKeeper.GetValidatorByConsAddr(ctx, newConsAddr); found {
+		return types.Rotation{}, sdkerrors.Wrapf(types.ErrPubKeyExists, "consensus address %s", newConsAddr)
+	}
+	if _, err := k.slashingKeeper.GetPubkey(ctx, newPubKey.Address()); err == nil {
+		return types.Rotation{}, sdkerrors.Wrapf(types.ErrPubKeyExists, "consensus address %s", newConsAddr)
+	}
+	if cp := ctx.ConsensusParams(); cp != nil && cp.Validator != nil {
+		if !ocstrings.StringInSlice(newPubKey.Type(), cp.Validator.PubKeyTypes) {
+			return types.Rotation{}, sdkerrors.Wrapf(types.ErrPubKeyTypeNotAllowed,
+				"got: %s, expected: %s", newPubKey.Type(), cp.Validator.PubKeyTypes)
+		}
+	}
+	if last, found := k.GetRotation(ctx, valAddr); found {
+		if end := last.Time.Add(k.GetParams(ctx).CooldownPeriod); ctx.BlockTime().Before(end) {
+			return types.Rotation{}, sdkerrors.Wrapf(types.ErrCooldown,
+				"the last rotation of validator %s was at height %d, the next one is allowed from %s", valAddr, last.Height, end)
+		}
+	}
+
+	newAny, err := codectypes.NewAnyWithValue(newPubKey)
+	if err != nil {
+		return types.Rotation{}, err
+	}
+	validator.ConsensusPubkey = newAny
+	k.stakingKeeper.SetValidator(ctx, validator)
+	if err := k.stakingKeeper.SetValidatorByConsAddr(ctx, validator); err != nil {
+		return types.Rotation{}, err
+	}
+
+	if err := k.slashingKeeper.AddPubkey(ctx, newPubKey); err != nil {
+		return types.Rotation{}, err
+	}
+	if info, found := k.slashingKeeper.GetValidatorSigningInfo(ctx, oldConsAddr); found {
+		k.slashingKeeper.SetValidatorSigningInfo(ctx, newConsAddr, slashingtypes.NewValidatorSigningInfo(
+			newConsAddr, ctx.BlockHeight(), 0, info.JailedUntil, false, 0,
+		))
+	}
+
+	rotation, err := types.NewRotation(valAddr, oldPubKey, newPubKey, ctx.BlockHeight(), ctx.BlockTime())
+	if err != nil {
+		return types.Rotation{}, err
+	}
+	k.SetRotation(ctx, rotation)
+	k.SetRecentRotation(ctx, rotation)
+	// the validator set knows only the key the validator had at the beginning of the block, so
+	// that a rotation following another one in the same block replaces that key
+	pending := rotation
+	if p, found := k.GetPendingRotation(ctx, valAddr); found {
+		pending.OldPubkey = p.OldPubkey
+	}
+	k.SetPendingRotation(ctx, pending)
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeRotateConsPubKey,
+			sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyOldConsAddr, oldConsAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyNewConsAddr, newConsAddr.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, valAddr.ToAccAddress().String()),
+		),
+	})
+	k.Logger(ctx).Info("rotated consensus key", "validator", valAddr, "old", oldConsAddr, "new", newConsAddr)
+	return rotation, nil
+}
+
+// SetOldConsPubKey makes the validator of the rotation found by the old consensus key of the
+// rotation again, as RotateConsPubKey does, so that the evidence of the blocks signed with it can
+// be handled. The staking and slashing genesis only know the current keys of the validators, so
+// the old keys of the recent rotations are restored by InitGenesis.
+func (k Keeper) SetOldConsPubKey(ctx sdk.Context, r types.Rotation) error {
+	valAddr := sdk.ValAddress(r.ValidatorAddress)
+	validator, found := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		// the validator was removed, and the evidence of its old key can't slash it anymore
+		return nil
+	}
+	oldPubKey, err := r.OldPubKey()
+	if err != nil {
+		return err
+	}
+	// the index is keyed by the consensus address of the given validator, which isn't saved
+	validator.ConsensusPubkey = r.OldPubkey
+	if err := k.stakingKeeper.SetValidatorByConsAddr(ctx, validator); err != nil {
+		return err
+	}
+	return k.slashingKeeper.AddPubkey(ctx, oldPubKey)
+}
+
+// ValidatorSetUpdates returns the validator set updates of the block returned by the staking
+// module, amended with the rotations of the block: the old key of a bonded validator is removed
+// from the validator set and its new key added with the power of the validator.
+func (k Keeper) ValidatorSetUpdates(ctx sdk.Context, stakingUpdates func() []abci.ValidatorUpdate) []abci.ValidatorUpdate {
+	// whether the old keys are in the validator set is known from the powers before the updates
+	var rotations []types.Rotation
+	var bonded []bool
+	k.IteratePendingRotations(ctx, func(r types.Rotation) bool {
+		rotations = append(rotations, r)
+		bonded = append(bonded, k.stakingKeeper.GetLastValidatorPower(ctx, sdk.ValAddress(r.ValidatorAddress)) > 0)
+		return false
+	})
+
+	updates := stakingUpdates()
+	for i, r := range rotations {
+		valAddr := sdk.ValAddress(r.ValidatorAddress)
+		k.deletePendingRotation(ctx, valAddr)
+		if !bonded[i] {
+			// the old key is unknown to the validator set, and the staking module bonds the
+			// validator with its new key
+			continue
+		}
+
+		oldPubKey, err := ocPubKey(r.OldPubKey())
+		if err != nil {
+			panic(fmt.Sprintf("rotation of validator %s: %s", valAddr, err))
+		}
+		newPubKey, err := ocPubKey(r.NewPubKey())
+		if err != nil {
+			panic(fmt.Sprintf("rotation of validator %s: %s", valAddr, err))
+		}
+		j := -1
+		for n, u := range updates {
+			if u.PubKey.Equal(newPubKey) {
+				j = n
+				break
+			}
+		}
+		switch {
+		case j >= 0 && updates[j].Power > 0:
+			updates = append(updates, abci.ValidatorUpdate{PubKey: oldPubKey, Power: 0})
+		case j >= 0:
+			// the validator leaves the validator set, which knows it by its old key only
+			updates[j].PubKey = oldPubKey
+		default:
+			power := k.stakingKeeper.GetLastValidatorPower(ctx, valAddr)
+			updates = append(updates,
+				abci.ValidatorUpdate{PubKey: oldPubKey, Power: 0},
+				abci.ValidatorUpdate{PubKey: newPubKey, Power: power},
+			)
+		}
+	}
+	return updates
+}
+
+// BeginBlocker carries the tombstone and the jail of the old consensus addresses of the recent
+// rotations over to the current consensus addresses of their validators, so that the evidence of
+// the blocks signed with an old key, handled by the evidence module for the old address only,
+// can't be escaped by rotating the key. It must run after the BeginBlocker of the evidence module.
+// The rotations are forgotten once their old keys are older than the evidence age.
+func (k Keeper) BeginBlocker(ctx sdk.Context) {
+	var expired []types.Rotation
+	k.IterateRecentRotations(ctx, func(r types.Rotation) bool {
+		if k.isEvidenceExpired(ctx, r) {
+			expired = append(expired, r)
+			return false
+		}
+		k.carryOverSlashing(ctx, r)
+		return false
+	})
+	for _, r := range expired {
+		k.deleteRecentRotation(ctx, r)
+	}
+}
+
+// isEvidenceExpired returns whether no evidence can be submitted anymore for the blocks signed
+// with the old key of the rotation, which stopped signing at the end of the rotation block.
+func (k Keeper) isEvidenceExpired(ctx sdk.Context, r types.Rotation) bool {
+	cp := ctx.ConsensusParams()
+	if cp == nil || cp.Evidence == nil {
+		return false
+	}
+	return ctx.BlockHeight()-r.Height > cp.Evidence.MaxAgeNumBlocks &&
+		ctx.BlockTime().Sub(r.Time) > cp.Evidence.MaxAgeDuration
+}
+
+func (k Keeper) carryOverSlashing(ctx sdk.Context, r types.Rotation) {
+	valAddr := sdk.ValAddress(r.ValidatorAddress)
+	oldPubKey, err := r.OldPubKey()
+	if err != nil {
+		panic(fmt.Sprintf("rotation of validator %s: %s", valAddr, err))
+	}
+	oldInfo, found := k.slashingKeeper.GetValidatorSigningInfo(ctx, sdk.BytesToConsAddress(oldPubKey.Address()))
+	if !found || (!oldInfo.Tombstoned && oldInfo.JailedUntil.IsZero()) {
+		return
+	}
+	validator, found := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		return
+	}
+	consAddr, err := validator.GetConsAddr()
+	if err != nil {
+		panic(fmt.Sprintf("validator %s: %s", valAddr, err))
+	}
+	info, found := k.slashingKeeper.GetValidatorSigningInfo(ctx, consAddr)
+	if !found {
+		return
+	}
+
+	changed := false
+	if oldInfo.Tombstoned && !info.Tombstoned {
+		info.Tombstoned = true
+		changed = true
+	}
+	if oldInfo.JailedUntil.After(info.JailedUntil) {
+		info.JailedUntil = oldInfo.JailedUntil
+		changed = true
+	}
+	if changed {
+		k.slashingKeeper.SetValidatorSigningInfo(ctx, consAddr, info)
+		k.Logger(ctx).Info("carried the slashing of an old consensus key over", "validator", valAddr,
+			"old", oldInfo.Address, "new", consAddr, "tombstoned", info.Tombstoned, "jailed_until", info.JailedUntil)
+	}
+}
+
+// SetRotation sets the last rotation of a validator.
+func (k Keeper) SetRotation(ctx sdk.Context, r types.Rotation) {
+	ctx.KVStore(k.storeKey).Set(types.RotationKey(sdk.ValAddress(r.ValidatorAddress)), k.cdc.MustMarshalBinaryBare(&r))
+}
+
+// GetRotation returns the last rotation of a validator.
+func (k Keeper) GetRotation(ctx sdk.Context, valAddr sdk.ValAddress) (types.Rotation, bool) {
+	return k.getRotation(ctx, types.RotationKey(valAddr))
+}
+
+// IterateRotations iterates over the last rotations of the validators, in the order of their
+// operator addresses, until the callback returns true.
+func (k Keeper) IterateRotations(ctx sdk.Context, cb func(r types.Rotation) (stop bool)) {
+	k.iterateRotations(ctx, types.RotationKeyPrefix, cb)
+}
+
+// SetPendingRotation sets the rotation of a validator in the current block.
+func (k Keeper) SetPendingRotation(ctx sdk.Context, r types.Rotation) {
+	ctx.KVStore(k.storeKey).Set(types.PendingRotationKey(sdk.ValAddress(r.ValidatorAddress)), k.cdc.MustMarshalBinaryBare(&r))
+}
+
+// GetPendingRotation returns the rotation of a validator in the current block.
+func (k Keeper) GetPendingRotation(ctx sdk.Context, valAddr sdk.ValAddress) (types.Rotation, bool) {
+	return k.getRotation(ctx, types.PendingRotationKey(valAddr))
+}
+
+// IteratePendingRotations iterates over the rotations in the current block until the callback
+// returns true.
+func (k Keeper) IteratePendingRotations(ctx sdk.Context, cb func(r types.Rotation) (stop bool)) {
+	k.iterateRotations(ctx, types.PendingRotationKeyPrefix, cb)
+}
+
+// SetRecentRotation sets a rotation whose old key evidence can still be submitted for.
+func (k Keeper) SetRecentRotation(ctx sdk.Context, r types.Rotation) {
+	ctx.KVStore(k.storeKey).Set(recentRotationKey(r), k.cdc.MustMarshalBinaryBare(&r))
+}
+
+// IterateRecentRotations iterates over the rotations whose old keys evidence can still be
+// submitted for, in the order of their heights, until the callback returns true.
+func (k Keeper) IterateRecentRotations(ctx sdk.Context, cb func(r types.Rotation) (stop bool)) {
+	k.iterateRotations(ctx, types.RecentRotationKeyPrefix, cb)
+}
+
+func (k Keeper) deleteRecentRotation(ctx sdk.Context, r types.Rotation) {
+	ctx.KVStore(k.storeKey).Delete(recentRotationKey(r))
+}
+
+func recentRotationKey(r types.Rotation) []byte {
+	oldPubKey, err := r.OldPubKey()
+	if err != nil {
+		panic(fmt.Sprintf("rotation of validator %s: %s", r.ValidatorAddress, err))
+	}
+	return types.RecentRotationKey(r.Height, sdk.ValAddress(r.ValidatorAddress), sdk.BytesToConsAddress(oldPubKey.Address()))
+}
+
+func (k Keeper) deletePendingRotation(ctx sdk.Context, valAddr sdk.ValAddress) {
+	ctx.KVStore(k.storeKey).Delete(types.PendingRotationKey(valAddr))
+}
+
+func (k Keeper) getRotation(ctx sdk.Context, key []byte) (types.Rotation, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(key)
+	if bz == nil {
+		return types.Rotation{}, false
+	}
+	var r types.Rotation
+	k.cdc.MustUnmarshalBinaryBare(bz, &r)
+	return r, true
+}
+
+func (k Keeper) iterateRotations(ctx sdk.Context, prefix []byte, cb func(r types.Rotation) (stop bool)) {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var r types.Rotation
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &r)
+		if cb(r) {
+			break
+		}
+	}
+}
+
+// ocPubKey converts a consensus key of a rotation to the key of the validator set updates.
+func ocPubKey(pk cryptotypes.PubKey, err error) (ocprotocrypto.PublicKey, error) {
+	if err != nil {
+		return ocprotocrypto.PublicKey{}, err
+	}
+	return cryptocodec.ToOcProtoPublicKey(pk)
+}
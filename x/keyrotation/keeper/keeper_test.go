@@ -0,0 +1,204 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	abci "github.com/line/ostracon/abci/types"
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/stretchr/testify/require"
+
+	cryptocodec "github.com/line/lbm-sdk/crypto/codec"
+	"github.com/line/lbm-sdk/crypto/keys/ed25519"
+	cryptotypes "github.com/line/lbm-sdk/crypto/types"
+	sdk "github.com/line/lbm-sdk/types"
+	evidencetypes "github.com/line/lbm-sdk/x/evidence/types"
+	slashingtypes "github.com/line/lbm-sdk/x/slashing/types"
+	"github.com/line/lbm-sdk/x/staking"
+	"github.com/line/lbm-sdk/x/staking/teststaking"
+
+	"github.com/line/lfb/app"
+	"github.com/line/lfb/x/keyrotation/types"
+)
+
+// setup returns an app with two bonded validators of power 100 and a third unbonded one of
+// power 10, as the validator set is limited to two validators.
+func setup(t *testing.T) (*app.LinkApp, sdk.Context, []sdk.ValAddress, []cryptotypes.PubKey) {
+	linkApp := app.Setup(false)
+	ctx := linkApp.BaseApp.NewContext(false, ocproto.Header{Height: 1, Time: time.Now().UTC()})
+	ctx = ctx.WithConsensusParams(linkApp.GetConsensusParams(ctx))
+
+	params := linkApp.StakingKeeper.GetParams(ctx)
+	params.MaxValidators = 2
+	linkApp.StakingKeeper.SetParams(ctx, params)
+	// allow several rotations in a block
+	linkApp.KeyRotationKeeper.SetParams(ctx, types.NewParams(0))
+
+	addrs := app.AddTestAddrs(linkApp, ctx, 3, sdk.TokensFromConsensusPower(200))
+	tstaking := teststaking.NewHelper(t, ctx, linkApp.StakingKeeper)
+	var valAddrs []sdk.ValAddress
+	var pks []cryptotypes.PubKey
+	for i, power := range []int64{100, 100, 10} {
+		valAddrs = append(valAddrs, addrs[i].ToValAddress())
+		pks = append(pks, ed25519.GenPrivKey().PubKey())
+		tstaking.CreateValidatorWithValPower(valAddrs[i], pks[i], power, true)
+	}
+	require.Len(t, staking.EndBlocker(ctx, linkApp.StakingKeeper), 2)
+	return linkApp, nextBlock(ctx), valAddrs, pks
+}
+
+func nextBlock(ctx sdk.Context) sdk.Context {
+	return ctx.WithBlockHeight(ctx.BlockHeight() + 1).WithBlockTime(ctx.BlockTime().Add(5 * time.Second))
+}
+
+// endBlock returns the validator set updates of the block as the wrapped staking module does.
+func endBlock(linkApp *app.LinkApp, ctx sdk.Context) []abci.ValidatorUpdate {
+	return linkApp.KeyRotationKeeper.ValidatorSetUpdates(ctx, func() []abci.ValidatorUpdate {
+		return staking.EndBlocker(ctx, linkApp.StakingKeeper)
+	})
+}
+
+func update(t *testing.T, pk cryptotypes.PubKey, power int64) abci.ValidatorUpdate {
+	ocPubKey, err := cryptocodec.ToOcProtoPublicKey(pk)
+	require.NoError(t, err)
+	return abci.ValidatorUpdate{PubKey: ocPubKey, Power: power}
+}
+
+func TestRotateConsPubKey(t *testing.T) {
+	linkApp, ctx, valAddrs, pks := setup(t)
+	k := linkApp.KeyRotationKeeper
+	oldConsAddr := sdk.BytesToConsAddress(pks[0].Address())
+	newPubKey := ed25519.GenPrivKey().PubKey()
+	newConsAddr := sdk.BytesToConsAddress(newPubKey.Address())
+
+	rotation, err := k.RotateConsPubKey(ctx, valAddrs[0], newPubKey)
+	require.NoError(t, err)
+	require.Equal(t, ctx.BlockHeight(), rotation.Height)
+
+	validator, found := linkApp.StakingKeeper.GetValidatorByConsAddr(ctx, newConsAddr)
+	require.True(t, found)
+	require.Equal(t, valAddrs[0], validator.GetOperator())
+	_, found = linkApp.StakingKeeper.GetValidatorByConsAddr(ctx, oldConsAddr)
+	require.True(t, found, "the evidence of the old key must still find the validator")
+	info, found := linkApp.SlashingKeeper.GetValidatorSigningInfo(ctx, newConsAddr)
+	require.True(t, found)
+	require.Equal(t, ctx.BlockHeight(), info.StartHeight)
+
+	// the key is taken now, by this validator or another one
+	_, err = k.RotateConsPubKey(ctx, valAddrs[1], newPubKey)
+	require.ErrorIs(t, err, types.ErrPubKeyExists)
+	_, err = k.RotateConsPubKey(ctx, valAddrs[1], pks[0])
+	require.ErrorIs(t, err, types.ErrPubKeyExists)
+
+	k.SetParams(ctx, types.DefaultParams())
+	_, err = k.RotateConsPubKey(ctx, valAddrs[0], ed25519.GenPrivKey().PubKey())
+	require.ErrorIs(t, err, types.ErrCooldown)
+	_, err = k.RotateConsPubKey(ctx, sdk.BytesToValAddress(newPubKey.Address()), ed25519.GenPrivKey().PubKey())
+	require.ErrorIs(t, err, types.ErrValidatorNotFound)
+
+	require.ElementsMatch(t, []abci.ValidatorUpdate{update(t, pks[0], 0), update(t, newPubKey, 100)}, endBlock(linkApp, ctx))
+	// the rotation is not pending anymore
+	require.Empty(t, endBlock(linkApp, nextBlock(ctx)))
+}
+
+func TestRotateConsPubKeyTwiceInABlock(t *testing.T) {
+	linkApp, ctx, valAddrs, pks := setup(t)
+	k := linkApp.KeyRotationKeeper
+	pk1 := ed25519.GenPrivKey().PubKey()
+	pk2 := ed25519.GenPrivKey().PubKey()
+
+	_, err := k.RotateConsPubKey(ctx, valAddrs[0], pk1)
+	require.NoError(t, err)
+	_, err = k.RotateConsPubKey(ctx, valAddrs[0], pk2)
+	require.NoError(t, err)
+
+	// the validator set never knew the intermediate key
+	require.ElementsMatch(t, []abci.ValidatorUpdate{update(t, pks[0], 0), update(t, pk2, 100)}, endBlock(linkApp, ctx))
+	last, found := k.GetRotation(ctx, valAddrs[0])
+	require.True(t, found)
+	oldPubKey, err := last.OldPubKey()
+	require.NoError(t, err)
+	require.True(t, pk1.Equals(oldPubKey))
+}
+
+func TestRotateConsPubKeyOfUnbondedValidator(t *testing.T) {
+	linkApp, ctx, valAddrs, _ := setup(t)
+	newPubKey := ed25519.GenPrivKey().PubKey()
+
+	_, err := linkApp.KeyRotationKeeper.RotateConsPubKey(ctx, valAddrs[2], newPubKey)
+	require.NoError(t, err)
+	require.Empty(t, endBlock(linkApp, ctx))
+
+	// the validator joins the validator set with the new key
+	ctx = nextBlock(ctx)
+	tstaking := teststaking.NewHelper(t, ctx, linkApp.StakingKeeper)
+	tstaking.DelegateWithPower(valAddrs[2].ToAccAddress(), valAddrs[2], 100)
+	require.Contains(t, endBlock(linkApp, ctx), update(t, newPubKey, 110))
+}
+
+func TestRotateConsPubKeyOfLeavingValidator(t *testing.T) {
+	linkApp, ctx, valAddrs, pks := setup(t)
+	newPubKey := ed25519.GenPrivKey().PubKey()
+
+	_, err := linkApp.KeyRotationKeeper.RotateConsPubKey(ctx, valAddrs[0], newPubKey)
+	require.NoError(t, err)
+	linkApp.StakingKeeper.Jail(ctx, sdk.BytesToConsAddress(newPubKey.Address()))
+
+	// the validator set removes the validator by the only key it knows, and the unbonded
+	// validator takes its place
+	require.ElementsMatch(t, []abci.ValidatorUpdate{update(t, pks[0], 0), update(t, pks[2], 10)}, endBlock(linkApp, ctx))
+}
+
+func TestRotateConsPubKeyAfterDoubleSign(t *testing.T) {
+	linkApp, ctx, valAddrs, pks := setup(t)
+	k := linkApp.KeyRotationKeeper
+	oldConsAddr := sdk.BytesToConsAddress(pks[0].Address())
+	newPubKey := ed25519.GenPrivKey().PubKey()
+	newConsAddr := sdk.BytesToConsAddress(newPubKey.Address())
+
+	// the validator double signs with the old key and rotates before the evidence is committed
+	infraction := ctx
+	_, err := k.RotateConsPubKey(ctx, valAddrs[0], newPubKey)
+	require.NoError(t, err)
+	endBlock(linkApp, ctx)
+
+	ctx = nextBlock(ctx)
+	linkApp.EvidenceKeeper.HandleEquivocationEvidence(ctx, &evidencetypes.Equivocation{
+		Height:           infraction.BlockHeight(),
+		Time:             infraction.BlockTime(),
+		Power:            100,
+		ConsensusAddress: oldConsAddr.String(),
+	})
+	require.True(t, linkApp.SlashingKeeper.IsTombstoned(ctx, oldConsAddr))
+	k.BeginBlocker(ctx)
+
+	info, found := linkApp.SlashingKeeper.GetValidatorSigningInfo(ctx, newConsAddr)
+	require.True(t, found)
+	require.True(t, info.Tombstoned)
+	require.True(t, evidencetypes.DoubleSignJailEndTime.Equal(info.JailedUntil))
+	require.ErrorIs(t, linkApp.SlashingKeeper.Unjail(ctx, valAddrs[0]), slashingtypes.ErrValidatorJailed)
+	_, err = k.RotateConsPubKey(ctx, valAddrs[0], ed25519.GenPrivKey().PubKey())
+	require.ErrorIs(t, err, types.ErrValidatorTombstoned)
+}
+
+func TestRecentRotationsExpire(t *testing.T) {
+	linkApp, ctx, valAddrs, _ := setup(t)
+	k := linkApp.KeyRotationKeeper
+
+	_, err := k.RotateConsPubKey(ctx, valAddrs[0], ed25519.GenPrivKey().PubKey())
+	require.NoError(t, err)
+	recent := func(ctx sdk.Context) (n int) {
+		k.IterateRecentRotations(ctx, func(types.Rotation) bool { n++; return false })
+		return n
+	}
+
+	// the evidence of the old key is too old once both its age in blocks and in time are
+	evidence := ctx.ConsensusParams().Evidence
+	later := ctx.WithBlockHeight(ctx.BlockHeight() + evidence.MaxAgeNumBlocks + 1)
+	k.BeginBlocker(later)
+	require.Equal(t, 1, recent(later))
+
+	later = later.WithBlockTime(ctx.BlockTime().Add(evidence.MaxAgeDuration + time.Second))
+	k.BeginBlocker(later)
+	require.Zero(t, recent(later))
+}
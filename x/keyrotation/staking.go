@@ -0,0 +1,33 @@
+package keyrotation
+
+import (
+	abci "github.com/line/ostracon/abci/types"
+
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/module"
+
+	"github.com/line/lfb/x/keyrotation/keeper"
+)
+
+// StakingModule wraps the staking module so that the validator set updates it returns from
+// ApplyAndReturnValidatorSetUpdates at the end of a block carry the consensus key rotations of
+// the block. Only one module of the manager may return validator set updates, so the rotations
+// can't be returned by the EndBlocker of the keyrotation module.
+type StakingModule struct {
+	module.AppModule
+
+	keeper keeper.Keeper
+}
+
+// NewStakingModule wraps the staking module with the keeper of the keyrotation module.
+func NewStakingModule(stakingModule module.AppModule, keeper keeper.Keeper) StakingModule {
+	return StakingModule{AppModule: stakingModule, keeper: keeper}
+}
+
+// EndBlock returns the validator set updates of the staking module amended with the rotations
+// of the block.
+func (am StakingModule) EndBlock(ctx sdk.Context, req abci.RequestEndBlock) []abci.ValidatorUpdate {
+	return am.keeper.ValidatorSetUpdates(ctx, func() []abci.ValidatorUpdate {
+		return am.AppModule.EndBlock(ctx, req)
+	})
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+)
+
+// keyrotation module sentinel errors
+var (
+	ErrValidatorNotFound    = sdkerrors.Register(ModuleName, 2, "validator not found")
+	ErrValidatorTombstoned  = sdkerrors.Register(ModuleName, 3, "validator tombstoned")
+	ErrPubKeyExists         = sdkerrors.Register(ModuleName, 4, "consensus pubkey already used")
+	ErrPubKeyTypeNotAllowed = sdkerrors.Register(ModuleName, 5, "consensus pubkey type not allowed")
+	ErrCooldown             = sdkerrors.Register(ModuleName, 6, "rotation within the cooldown period")
+	ErrRotationNotFound     = sdkerrors.Register(ModuleName, 7, "rotation not found")
+)
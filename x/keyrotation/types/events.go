@@ -0,0 +1,12 @@
+package types
+
+// keyrotation module event types
+const (
+	EventTypeRotateConsPubKey = "rotate_cons_pubkey"
+
+	AttributeKeyValidator   = "validator"
+	AttributeKeyOldConsAddr = "old_cons_address"
+	AttributeKeyNewConsAddr = "new_cons_address"
+
+	AttributeValueCategory = ModuleName
+)
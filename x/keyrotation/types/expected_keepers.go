@@ -0,0 +1,27 @@
+package types
+
+import (
+	cryptotypes "github.com/line/lbm-sdk/crypto/types"
+	sdk "github.com/line/lbm-sdk/types"
+	slashingtypes "github.com/line/lbm-sdk/x/slashing/types"
+	stakingtypes "github.com/line/lbm-sdk/x/staking/types"
+)
+
+// StakingKeeper defines the staking keeper the keyrotation module updates the validators with.
+type StakingKeeper interface {
+	GetValidator(ctx sdk.Context, addr sdk.ValAddress) (stakingtypes.Validator, bool)
+	GetValidatorByConsAddr(ctx sdk.Context, consAddr sdk.ConsAddress) (stakingtypes.Validator, bool)
+	SetValidator(ctx sdk.Context, validator stakingtypes.Validator)
+	SetValidatorByConsAddr(ctx sdk.Context, validator stakingtypes.Validator) error
+	GetLastValidatorPower(ctx sdk.Context, operator sdk.ValAddress) int64
+}
+
+// SlashingKeeper defines the slashing keeper the keyrotation module updates the signing infos
+// with.
+type SlashingKeeper interface {
+	AddPubkey(ctx sdk.Context, pubkey cryptotypes.PubKey) error
+	GetPubkey(ctx sdk.Context, a cryptotypes.Address) (cryptotypes.PubKey, error)
+	GetValidatorSigningInfo(ctx sdk.Context, address sdk.ConsAddress) (slashingtypes.ValidatorSigningInfo, bool)
+	SetValidatorSigningInfo(ctx sdk.Context, address sdk.ConsAddress, info slashingtypes.ValidatorSigningInfo)
+	IsTombstoned(ctx sdk.Context, consAddr sdk.ConsAddress) bool
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"fmt"
+
+	codectypes "github.com/line/lbm-sdk/codec/types"
+)
+
+var _ codectypes.UnpackInterfacesMessage = (*GenesisState)(nil)
+
+// NewGenesisState creates a new GenesisState instance.
+func NewGenesisState(params Params, rotations []Rotation) *GenesisState {
+	return &GenesisState{Params: params, Rotations: rotations}
+}
+
+// DefaultGenesisState returns the default genesis state of the keyrotation module.
+func DefaultGenesisState() *GenesisState {
+	return NewGenesisState(DefaultParams(), nil)
+}
+
+// Validate performs a basic validation of the genesis state.
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(gs.Rotations))
+	for _, r := range gs.Rotations {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		if seen[r.ValidatorAddress] {
+			return fmt.Errorf("duplicate rotation of validator %s", r.ValidatorAddress)
+		}
+		seen[r.ValidatorAddress] = true
+	}
+	for _, r := range gs.RecentRotations {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnpackInterfaces implements UnpackInterfacesMessage.UnpackInterfaces
+func (gs GenesisState) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error {
+	for _, r := range append(append([]Rotation{}, gs.Rotations...), gs.RecentRotations...) {
+		if err := r.UnpackInterfaces(unpacker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
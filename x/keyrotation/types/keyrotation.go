@@ -0,0 +1,76 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	codectypes "github.com/line/lbm-sdk/codec/types"
+	cryptotypes "github.com/line/lbm-sdk/crypto/types"
+	sdk "github.com/line/lbm-sdk/types"
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+)
+
+var _ codectypes.UnpackInterfacesMessage = (*Rotation)(nil)
+
+// NewRotation creates a new Rotation instance.
+func NewRotation(valAddr sdk.ValAddress, oldPubKey, newPubKey cryptotypes.PubKey, height int64, t time.Time) (Rotation, error) {
+	oldAny, err := codectypes.NewAnyWithValue(oldPubKey)
+	if err != nil {
+		return Rotation{}, err
+	}
+	newAny, err := codectypes.NewAnyWithValue(newPubKey)
+	if err != nil {
+		return Rotation{}, err
+	}
+	return Rotation{
+		ValidatorAddress: valAddr.String(),
+		OldPubkey:        oldAny,
+		NewPubkey:        newAny,
+		Height:           height,
+		Time:             t,
+	}, nil
+}
+
+// OldPubKey returns the consensus key replaced by the rotation.
+func (r Rotation) OldPubKey() (cryptotypes.PubKey, error) {
+	return pubKey(r.OldPubkey)
+}
+
+// NewPubKey returns the consensus key of the validator after the rotation.
+func (r Rotation) NewPubKey() (cryptotypes.PubKey, error) {
+	return pubKey(r.NewPubkey)
+}
+
+// Validate performs a basic validation of the rotation.
+func (r Rotation) Validate() error {
+	if err := sdk.ValidateValAddress(r.ValidatorAddress); err != nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid validator address %s: %s", r.ValidatorAddress, err)
+	}
+	if _, err := r.OldPubKey(); err != nil {
+		return err
+	}
+	if _, err := r.NewPubKey(); err != nil {
+		return err
+	}
+	if r.Height <= 0 {
+		return fmt.Errorf("height must be positive: %d", r.Height)
+	}
+	return nil
+}
+
+// UnpackInterfaces implements UnpackInterfacesMessage.UnpackInterfaces
+func (r Rotation) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error {
+	var pk cryptotypes.PubKey
+	if err := unpacker.UnpackAny(r.OldPubkey, &pk); err != nil {
+		return err
+	}
+	return unpacker.UnpackAny(r.NewPubkey, &pk)
+}
+
+func pubKey(any *codectypes.Any) (cryptotypes.PubKey, error) {
+	pk, ok := any.GetCachedValue().(cryptotypes.PubKey)
+	if !ok {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidType, "expecting cryptotypes.PubKey, got %T", any.GetCachedValue())
+	}
+	return pk, nil
+}
@@ -0,0 +1,725 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: lfb/keyrotation/v1/keyrotation.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
+	types "github.com/line/lbm-sdk/codec/types"
+	_ "github.com/regen-network/cosmos-proto"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	time "time"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Params defines the parameters of the keyrotation module.
+type Params struct {
+	// cooldown_period is the minimum time between two rotations of the consensus key of a validator.
+	CooldownPeriod time.Duration `protobuf:"bytes,1,opt,name=cooldown_period,json=cooldownPeriod,proto3,stdduration" json:"cooldown_period" yaml:"cooldown_period"`
+}
+
+func (m *Params) Reset()      { *m = Params{} }
+func (*Params) ProtoMessage() {}
+func (*Params) Descriptor() ([]byte, []int) {
+	return fileDescriptor_55d2c0a984424138, []int{0}
+}
+func (m *Params) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params.Merge(m, src)
+}
+func (m *Params) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params proto.InternalMessageInfo
+
+func (m *Params) GetCooldownPeriod() time.Duration {
+	if m != nil {
+		return m.CooldownPeriod
+	}
+	return 0
+}
+
+// Rotation is a rotation of the consensus key of a validator.
+type Rotation struct {
+	// validator_address is the operator address of the validator.
+	ValidatorAddress string `protobuf:"bytes,1,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty" yaml:"validator_address"`
+	// old_pubkey is the consensus key replaced by the rotation.
+	OldPubkey *types.Any `protobuf:"bytes,2,opt,name=old_pubkey,json=oldPubkey,proto3" json:"old_pubkey,omitempty" yaml:"old_pubkey"`
+	// new_pubkey is the consensus key of the validator after the rotation.
+	NewPubkey *types.Any `protobuf:"bytes,3,opt,name=new_pubkey,json=newPubkey,proto3" json:"new_pubkey,omitempty" yaml:"new_pubkey"`
+	// height is the height of the block of the rotation.
+	Height int64 `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	// time is the time of the block of the rotation.
+	Time time.Time `protobuf:"bytes,5,opt,name=time,proto3,stdtime" json:"time"`
+}
+
+func (m *Rotation) Reset()         { *m = Rotation{} }
+func (m *Rotation) String() string { return proto.CompactTextString(m) }
+func (*Rotation) ProtoMessage()    {}
+func (*Rotation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_55d2c0a984424138, []int{1}
+}
+func (m *Rotation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Rotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Rotation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Rotation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rotation.Merge(m, src)
+}
+func (m *Rotation) XXX_Size() int {
+	return m.Size()
+}
+func (m *Rotation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rotation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rotation proto.InternalMessageInfo
+
+func (m *Rotation) GetValidatorAddress() string {
+	if m != nil {
+		return m.ValidatorAddress
+	}
+	return ""
+}
+
+func (m *Rotation) GetOldPubkey() *types.Any {
+	if m != nil {
+		return m.OldPubkey
+	}
+	return nil
+}
+
+func (m *Rotation) GetNewPubkey() *types.Any {
+	if m != nil {
+		return m.NewPubkey
+	}
+	return nil
+}
+
+func (m *Rotation) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Rotation) GetTime() time.Time {
+	if m != nil {
+		return m.Time
+	}
+	return time.Time{}
+}
+
+func init() {
+	proto.RegisterType((*Params)(nil), "lfb.keyrotation.v1.Params")
+	proto.RegisterType((*Rotation)(nil), "lfb.keyrotation.v1.Rotation")
+}
+
+func init() {
+	proto.RegisterFile("lfb/keyrotation/v1/keyrotation.proto", fileDescriptor_55d2c0a984424138)
+}
+
+var fileDescriptor_55d2c0a984424138 = []byte{
+	// 443 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x31, 0x6b, 0xdb, 0x40,
+	0x14, 0xc7, 0xa5, 0xc4, 0x35, 0xc9, 0x15, 0xda, 0x5a, 0x84, 0xa0, 0x98, 0x22, 0x05, 0x51, 0x68,
+	0x28, 0x54, 0x22, 0xed, 0x52, 0xb2, 0x59, 0x74, 0x29, 0x5d, 0x8c, 0xe8, 0xd4, 0x45, 0x9c, 0xac,
+	0xb3, 0x2c, 0x72, 0xd2, 0x13, 0xa7, 0x93, 0xdd, 0xfb, 0x16, 0x19, 0x33, 0xf6, 0x43, 0xf4, 0x43,
+	0x84, 0x4e, 0x19, 0x3b, 0xa9, 0xc5, 0x9e, 0xba, 0xfa, 0x13, 0x14, 0xdd, 0x49, 0xb5, 0x6b, 0x53,
+	0xc8, 0x76, 0xef, 0xff, 0x7f, 0xef, 0xff, 0xe3, 0xee, 0x1d, 0x7a, 0x41, 0xa7, 0x91, 0x77, 0x4d,
+	0x04, 0x03, 0x8e, 0x79, 0x0a, 0xb9, 0x37, 0xbf, 0xdc, 0x2e, 0xdd, 0x82, 0x01, 0x07, 0xc3, 0xa0,
+	0xd3, 0xc8, 0xdd, 0x96, 0xe7, 0x97, 0xc3, 0x93, 0x04, 0x12, 0x90, 0xb6, 0xd7, 0x9c, 0x54, 0xe7,
+	0xf0, 0x2c, 0x01, 0x48, 0x28, 0xf1, 0x64, 0x15, 0x55, 0x53, 0x0f, 0xe7, 0xa2, 0xb5, 0xac, 0x5d,
+	0x2b, 0xae, 0xd8, 0x16, 0x64, 0x68, 0xef, 0xfa, 0x3c, 0xcd, 0x48, 0xc9, 0x71, 0x56, 0x74, 0xd9,
+	0x13, 0x28, 0x33, 0x28, 0x43, 0x05, 0x55, 0x85, 0xb2, 0x9c, 0x39, 0xea, 0x8f, 0x31, 0xc3, 0x59,
+	0x69, 0x4c, 0xd1, 0xd3, 0x09, 0x00, 0x8d, 0x61, 0x91, 0x87, 0x05, 0x61, 0x29, 0xc4, 0xa6, 0x7e,
+	0xae, 0x5f, 0x3c, 0x7e, 0x73, 0xe6, 0xaa, 0x7c, 0xb7, 0xcb, 0x77, 0xdf, 0xb7, 0x7c, 0xdf, 0xb9,
+	0xab, 0x6d, 0x6d, 0x5d, 0xdb, 0xa7, 0x02, 0x67, 0xf4, 0xca, 0xd9, 0x99, 0x77, 0x6e, 0x7f, 0xda,
+	0x7a, 0xf0, 0xa4, 0x53, 0xc7, 0x52, 0xbc, 0xea, 0xdd, 0x7e, 0xb5, 0x35, 0xe7, 0xf7, 0x01, 0x3a,
+	0x0a, 0xda, 0x47, 0x31, 0x3e, 0xa0, 0xc1, 0x1c, 0xd3, 0x34, 0xc6, 0x1c, 0x58, 0x88, 0xe3, 0x98,
+	0x91, 0xb2, 0x94, 0xf0, 0x63, 0xff, 0xf9, 0xba, 0xb6, 0x4d, 0x95, 0xbe, 0xd7, 0xe2, 0x04, 0xcf,
+	0xfe, 0x6a, 0x23, 0x25, 0x19, 0x21, 0x42, 0x40, 0xe3, 0xb0, 0xa8, 0xa2, 0x6b, 0x22, 0xcc, 0x03,
+	0x79, 0x81, 0x93, 0xbd, 0x0b, 0x8c, 0x72, 0xe1, 0xbf, 0x5a, 0xd7, 0xf6, 0x40, 0x25, 0x6f, 0x26,
+	0x9c, 0xef, 0xdf, 0x5e, 0x0f, 0x68, 0x94, 0xb9, 0x13, 0x26, 0x0a, 0x0e, 0xee, 0xb8, 0x8a, 0x3e,
+	0x12, 0x11, 0x1c, 0x03, 0x8d, 0xc7, 0xb2, 0xa1, 0x01, 0xe4, 0x64, 0xd1, 0x01, 0x0e, 0x1f, 0x06,
+	0xd8, 0x4c, 0xfc, 0x0f, 0x90, 0x93, 0x45, 0x0b, 0x38, 0x45, 0xfd, 0x19, 0x49, 0x93, 0x19, 0x37,
+	0x7b, 0xe7, 0xfa, 0xc5, 0x61, 0xd0, 0x56, 0xc6, 0x3b, 0xd4, 0x6b, 0xf6, 0x6a, 0x3e, 0x92, 0xc8,
+	0xe1, 0x1e, 0xf2, 0x53, 0xb7, 0x74, 0xff, 0xa8, 0xd9, 0xca, 0x4d, 0xf3, 0xf6, 0x72, 0xc2, 0x1f,
+	0xdd, 0x2d, 0x2d, 0xfd, 0x7e, 0x69, 0xe9, 0xbf, 0x96, 0x96, 0x7e, 0xb3, 0xb2, 0xb4, 0xfb, 0x95,
+	0xa5, 0xfd, 0x58, 0x59, 0xda, 0xe7, 0x97, 0x49, 0xca, 0x67, 0x55, 0xe4, 0x4e, 0x20, 0xf3, 0x68,
+	0x9a, 0x13, 0xaf, 0xf9, 0xd4, 0x5f, 0xfe, 0xf9, 0xd6, 0x5c, 0x14, 0xa4, 0x8c, 0xfa, 0x12, 0xf3,
+	0xf6, 0xcf, 0x00, 0xa9, 0xf3, 0x91, 0xa7, 0xf6, 0x02, 0x00, 0x00,
+}
+
+func (m *Params) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Params) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n1, err1 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.CooldownPeriod, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.CooldownPeriod):])
+	if err1 != nil {
+		return 0, err1
+	}
+	i -= n1
+	i = encodeVarintKeyrotation(dAtA, i, uint64(n1))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *Rotation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Rotation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Rotation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err2 != nil {
+		return 0, err2
+	}
+	i -= n2
+	i = encodeVarintKeyrotation(dAtA, i, uint64(n2))
+	i--
+	dAtA[i] = 0x2a
+	if m.Height != 0 {
+		i = encodeVarintKeyrotation(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.NewPubkey != nil {
+		{
+			size, err := m.NewPubkey.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKeyrotation(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.OldPubkey != nil {
+		{
+			size, err := m.OldPubkey.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintKeyrotation(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ValidatorAddress) > 0 {
+		i -= len(m.ValidatorAddress)
+		copy(dAtA[i:], m.ValidatorAddress)
+		i = encodeVarintKeyrotation(dAtA, i, uint64(len(m.ValidatorAddress)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintKeyrotation(dAtA []byte, offset int, v uint64) int {
+	offset -= sovKeyrotation(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Params) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.CooldownPeriod)
+	n += 1 + l + sovKeyrotation(uint64(l))
+	return n
+}
+
+func (m *Rotation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ValidatorAddress)
+	if l > 0 {
+		n += 1 + l + sovKeyrotation(uint64(l))
+	}
+	if m.OldPubkey != nil {
+		l = m.OldPubkey.Size()
+		n += 1 + l + sovKeyrotation(uint64(l))
+	}
+	if m.NewPubkey != nil {
+		l = m.NewPubkey.Size()
+		n += 1 + l + sovKeyrotation(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovKeyrotation(uint64(m.Height))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Time)
+	n += 1 + l + sovKeyrotation(uint64(l))
+	return n
+}
+
+func sovKeyrotation(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozKeyrotation(x uint64) (n int) {
+	return sovKeyrotation(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Params) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowKeyrotation
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Params: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Params: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CooldownPeriod", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.CooldownPeriod, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipKeyrotation(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Rotation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowKeyrotation
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Rotation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Rotation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ValidatorAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldPubkey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OldPubkey == nil {
+				m.OldPubkey = &types.Any{}
+			}
+			if err := m.OldPubkey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewPubkey", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.NewPubkey == nil {
+				m.NewPubkey = &types.Any{}
+			}
+			if err := m.NewPubkey.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Time, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipKeyrotation(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthKeyrotation
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipKeyrotation(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowKeyrotation
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowKeyrotation
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthKeyrotation
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupKeyrotation
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthKeyrotation
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthKeyrotation        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowKeyrotation          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupKeyrotation = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,49 @@
+package types
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+const (
+	// ModuleName is the name of the keyrotation module
+	ModuleName = "keyrotation"
+
+	// StoreKey is the store key string for the keyrotation module
+	StoreKey = ModuleName
+
+	// RouterKey is the message route for the keyrotation module
+	RouterKey = ModuleName
+
+	// QuerierRoute is the querier route for the keyrotation module
+	QuerierRoute = ModuleName
+)
+
+// The prefixes of the keys of the store:
+//
+//	RotationKeyPrefix        | operator -> the last Rotation of the validator
+//	PendingRotationKeyPrefix | operator -> the Rotation of the validator in the current block
+//	RecentRotationKeyPrefix  | height | len(operator) | operator | old consensus address -> a Rotation
+//	                           whose old key evidence can still be submitted for
+var (
+	RotationKeyPrefix        = []byte{0x01}
+	PendingRotationKeyPrefix = []byte{0x02}
+	RecentRotationKeyPrefix  = []byte{0x03}
+)
+
+// RotationKey returns the key of the last rotation of the validator.
+func RotationKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, RotationKeyPrefix...), valAddr.Bytes()...)
+}
+
+// PendingRotationKey returns the key of the rotation of the validator in the current block.
+func PendingRotationKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, PendingRotationKeyPrefix...), valAddr.Bytes()...)
+}
+
+// RecentRotationKey returns the key of a rotation whose old key evidence can still be submitted for.
+func RecentRotationKey(height int64, valAddr sdk.ValAddress, oldConsAddr sdk.ConsAddress) []byte {
+	key := append(append([]byte{}, RecentRotationKeyPrefix...), sdk.Uint64ToBigEndian(uint64(height))...)
+	key = append(key, byte(len(valAddr.Bytes())))
+	key = append(key, valAddr.Bytes()...)
+	return append(key, oldConsAddr.Bytes()...)
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	paramtypes "github.com/line/lbm-sdk/x/params/types"
+)
+
+// DefaultCooldownPeriod is the default minimum time between two rotations of a validator.
+const DefaultCooldownPeriod = 24 * time.Hour
+
+// Parameter store keys
+var (
+	KeyCooldownPeriod = []byte("CooldownPeriod")
+)
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the key table of the params of the keyrotation module.
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// NewParams creates a new Params instance.
+func NewParams(cooldownPeriod time.Duration) Params {
+	return Params{CooldownPeriod: cooldownPeriod}
+}
+
+// DefaultParams returns the default params of the keyrotation module.
+func DefaultParams() Params {
+	return NewParams(DefaultCooldownPeriod)
+}
+
+// Validate validates the params.
+func (p Params) Validate() error {
+	return validateCooldownPeriod(p.CooldownPeriod)
+}
+
+// String implements the Stringer interface.
+func (p Params) String() string {
+	out, _ := yaml.Marshal(p)
+	return string(out)
+}
+
+// ParamSetPairs implements the ParamSet interface.
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyCooldownPeriod, &p.CooldownPeriod, validateCooldownPeriod),
+	}
+}
+
+func validateCooldownPeriod(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v < 0 {
+		return fmt.Errorf("cooldown period must not be negative: %s", v)
+	}
+	return nil
+}
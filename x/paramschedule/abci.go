@@ -0,0 +1,12 @@
+package paramschedule
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+
+	"github.com/line/lfb/x/paramschedule/keeper"
+)
+
+// EndBlocker applies the scheduled param changes due at the block.
+func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
+	k.ApplyDueSchedules(ctx)
+}
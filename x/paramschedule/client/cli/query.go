@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/flags"
+	"github.com/line/lbm-sdk/version"
+
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+// GetQueryCmd returns the CLI command with all paramschedule module query commands mounted.
+func GetQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Query the scheduled param changes",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		GetCmdQuerySchedules(),
+		GetCmdQuerySchedule(),
+	)
+	return cmd
+}
+
+// GetCmdQuerySchedules returns the command listing the schedules not applied yet.
+func GetCmdQuerySchedules() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedules",
+		Short:   "Query the schedules of param changes not applied yet",
+		Example: fmt.Sprintf("$ %s query %s schedules", version.AppName, types.ModuleName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			pageReq, err := client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Schedules(cmd.Context(), &types.QuerySchedulesRequest{Pagination: pageReq})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "schedules")
+	return cmd
+}
+
+// GetCmdQuerySchedule returns the command showing a schedule not applied yet.
+func GetCmdQuerySchedule() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule [schedule-id]",
+		Short:   "Query a schedule of param changes not applied yet",
+		Example: fmt.Sprintf("$ %s query %s schedule 1", version.AppName, types.ModuleName),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("schedule-id %s not a valid uint: %w", args[0], err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Schedule(cmd.Context(), &types.QueryScheduleRequest{ScheduleId: id})
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(&res.Schedule)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
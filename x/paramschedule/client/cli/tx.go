@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/tx"
+	"github.com/line/lbm-sdk/codec"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/version"
+	govcli "github.com/line/lbm-sdk/x/gov/client/cli"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramscutils "github.com/line/lbm-sdk/x/params/client/utils"
+
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+// ScheduleParamChangeProposalJSON defines a ScheduleParamChangeProposal with a deposit used to
+// parse the proposals from a JSON file.
+type ScheduleParamChangeProposalJSON struct {
+	Title       string                        `json:"title" yaml:"title"`
+	Description string                        `json:"description" yaml:"description"`
+	Changes     paramscutils.ParamChangesJSON `json:"changes" yaml:"changes"`
+	Height      int64                         `json:"height" yaml:"height"`
+	Time        time.Time                     `json:"time" yaml:"time"`
+	Deposit     string                        `json:"deposit" yaml:"deposit"`
+}
+
+// ParseScheduleParamChangeProposalJSON reads and parses a ScheduleParamChangeProposalJSON from
+// the file.
+func ParseScheduleParamChangeProposalJSON(cdc *codec.LegacyAmino, proposalFile string) (ScheduleParamChangeProposalJSON, error) {
+	var proposal ScheduleParamChangeProposalJSON
+	contents, err := ioutil.ReadFile(proposalFile)
+	if err != nil {
+		return proposal, err
+	}
+	if err := cdc.UnmarshalJSON(contents, &proposal); err != nil {
+		return proposal, err
+	}
+	return proposal, nil
+}
+
+// NewCmdSubmitScheduleParamChangeProposal returns a CLI command handler for creating a proposal
+// to schedule param changes.
+func NewCmdSubmitScheduleParamChangeProposal() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schedule-param-change [proposal-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Submit a proposal to schedule param changes at a future height or time",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Submit a proposal to schedule param changes along with an initial deposit.
+The changes are applied together at the end of the first block at or after the activation
+height or time, which must be after the block the proposal passes at. The proposal details
+must be supplied via a JSON file, with either the height or the time.
+
+Example:
+$ %s tx gov submit-proposal schedule-param-change <path/to/proposal.json> --from=<key_or_address>
+
+Where proposal.json contains:
+
+{
+  "title": "Gas Price Floor",
+  "description": "Raise the gas price floor on Monday",
+  "changes": [
+    {
+      "subspace": "staking",
+      "key": "MaxValidators",
+      "value": 105
+    }
+  ],
+  "time": "2021-11-01T00:00:00Z",
+  "deposit": "1000stake"
+}
+`,
+				version.AppName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			proposal, err := ParseScheduleParamChangeProposalJSON(clientCtx.LegacyAmino, args[0])
+			if err != nil {
+				return err
+			}
+
+			content := types.NewScheduleParamChangeProposal(proposal.Title, proposal.Description,
+				proposal.Changes.ToParamChanges(), proposal.Height, proposal.Time)
+			deposit, err := sdk.ParseCoinsNormalized(proposal.Deposit)
+			if err != nil {
+				return err
+			}
+
+			msg, err := govtypes.NewMsgSubmitProposal(content, deposit, clientCtx.GetFromAddress())
+			if err != nil {
+				return err
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+}
+
+// NewCmdSubmitCancelParamChangeScheduleProposal returns a CLI command handler for creating a
+// proposal to cancel a schedule of param changes.
+func NewCmdSubmitCancelParamChangeScheduleProposal() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-param-change-schedule [schedule-id]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Submit a proposal to cancel a schedule of param changes not applied yet",
+		Example: fmt.Sprintf("$ %s tx gov submit-proposal cancel-param-change-schedule 1 --title=<title> "+
+			"--description=<description> --deposit=1000stake --from=<key_or_address>", version.AppName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("schedule-id %s not a valid uint: %w", args[0], err)
+			}
+			title, err := cmd.Flags().GetString(govcli.FlagTitle)
+			if err != nil {
+				return err
+			}
+			description, err := cmd.Flags().GetString(govcli.FlagDescription)
+			if err != nil {
+				return err
+			}
+			depositStr, err := cmd.Flags().GetString(govcli.FlagDeposit)
+			if err != nil {
+				return err
+			}
+			deposit, err := sdk.ParseCoinsNormalized(depositStr)
+			if err != nil {
+				return err
+			}
+
+			content := types.NewCancelParamChangeScheduleProposal(title, description, id)
+			msg, err := govtypes.NewMsgSubmitProposal(content, deposit, clientCtx.GetFromAddress())
+			if err != nil {
+				return err
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+	_ = cmd.MarkFlagRequired(govcli.FlagTitle)
+	_ = cmd.MarkFlagRequired(govcli.FlagDescription)
+	return cmd
+}
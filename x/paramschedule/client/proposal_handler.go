@@ -0,0 +1,14 @@
+package client
+
+import (
+	govclient "github.com/line/lbm-sdk/x/gov/client"
+
+	"github.com/line/lfb/x/paramschedule/client/cli"
+	"github.com/line/lfb/x/paramschedule/client/rest"
+)
+
+// The proposal handlers of the proposals to schedule param changes and to cancel the schedules.
+var (
+	ProposalHandler       = govclient.NewProposalHandler(cli.NewCmdSubmitScheduleParamChangeProposal, rest.ScheduleProposalRESTHandler)
+	CancelProposalHandler = govclient.NewProposalHandler(cli.NewCmdSubmitCancelParamChangeScheduleProposal, rest.CancelProposalRESTHandler)
+)
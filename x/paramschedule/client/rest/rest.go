@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/line/lbm-sdk/client"
+	"github.com/line/lbm-sdk/client/tx"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/rest"
+	govrest "github.com/line/lbm-sdk/x/gov/client/rest"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramscutils "github.com/line/lbm-sdk/x/params/client/utils"
+
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+// ScheduleParamChangeProposalReq defines a request body of a proposal to schedule param changes.
+type ScheduleParamChangeProposalReq struct {
+	BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+
+	Title       string                        `json:"title" yaml:"title"`
+	Description string                        `json:"description" yaml:"description"`
+	Changes     paramscutils.ParamChangesJSON `json:"changes" yaml:"changes"`
+	Height      int64                         `json:"height" yaml:"height"`
+	Time        time.Time                     `json:"time" yaml:"time"`
+	Deposit     sdk.Coins                     `json:"deposit" yaml:"deposit"`
+}
+
+// CancelParamChangeScheduleProposalReq defines a request body of a proposal to cancel a schedule
+// of param changes.
+type CancelParamChangeScheduleProposalReq struct {
+	BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+
+	Title       string    `json:"title" yaml:"title"`
+	Description string    `json:"description" yaml:"description"`
+	ScheduleID  uint64    `json:"schedule_id" yaml:"schedule_id"`
+	Deposit     sdk.Coins `json:"deposit" yaml:"deposit"`
+}
+
+// ScheduleProposalRESTHandler returns the REST handler of the proposals to schedule param
+// changes.
+func ScheduleProposalRESTHandler(clientCtx client.Context) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "schedule_param_change",
+		Handler:  postScheduleProposalHandlerFn(clientCtx),
+	}
+}
+
+// CancelProposalRESTHandler returns the REST handler of the proposals to cancel a schedule of
+// param changes.
+func CancelProposalRESTHandler(clientCtx client.Context) govrest.ProposalRESTHandler {
+	return govrest.ProposalRESTHandler{
+		SubRoute: "cancel_param_change_schedule",
+		Handler:  postCancelProposalHandlerFn(clientCtx),
+	}
+}
+
+func postScheduleProposalHandlerFn(clientCtx client.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ScheduleParamChangeProposalReq
+		if !rest.ReadRESTReq(w, r, clientCtx.LegacyAmino, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewScheduleParamChangeProposal(req.Title, req.Description, req.Changes.ToParamChanges(),
+			req.Height, req.Time)
+		writeProposalTx(w, clientCtx, req.BaseReq, content, req.Deposit)
+	}
+}
+
+func postCancelProposalHandlerFn(clientCtx client.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CancelParamChangeScheduleProposalReq
+		if !rest.ReadRESTReq(w, r, clientCtx.LegacyAmino, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		content := types.NewCancelParamChangeScheduleProposal(req.Title, req.Description, req.ScheduleID)
+		writeProposalTx(w, clientCtx, req.BaseReq, content, req.Deposit)
+	}
+}
+
+func writeProposalTx(w http.ResponseWriter, clientCtx client.Context, baseReq rest.BaseReq,
+	content govtypes.Content, deposit sdk.Coins) {
+	msg, err := govtypes.NewMsgSubmitProposal(content, deposit, sdk.AccAddress(baseReq.From))
+	if rest.CheckBadRequestError(w, err) {
+		return
+	}
+	if rest.CheckBadRequestError(w, msg.ValidateBasic()) {
+		return
+	}
+
+	tx.WriteGeneratedTxResponse(clientCtx, w, baseReq, msg)
+}
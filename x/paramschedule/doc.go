@@ -0,0 +1,21 @@
+/*
+Package paramschedule implements the param changes scheduled by governance proposals to take
+effect at a coordinated height or time.
+
+A ScheduleParamChangeProposal carries a set of param changes with an activation height or time.
+When it passes, the changes are checked against the current params and stored as a schedule,
+which is applied at the end of the first block at or after the activation, before the EndBlocker
+of staking, emitting an apply_scheduled_param_change event per change and recording the changes
+in the audit log. The changes of a schedule are applied all or none: if one is no longer valid
+at the activation, a param_change_schedule_failed event is emitted instead. A schedule not
+applied yet is cancelled by a CancelParamChangeScheduleProposal.
+
+The proposals are submitted with:
+
+	lfb tx gov submit-proposal schedule-param-change <proposal.json>
+	lfb tx gov submit-proposal cancel-param-change-schedule <schedule-id>
+
+and the schedules not applied yet are queried with lfb query paramschedule schedules or the
+/lfb/paramschedule/v1/schedules route of the gRPC Gateway.
+*/
+package paramschedule
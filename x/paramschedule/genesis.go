@@ -0,0 +1,31 @@
+package paramschedule
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+
+	"github.com/line/lfb/x/paramschedule/keeper"
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+// InitGenesis initializes the paramschedule module's state from a given genesis state.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, gs *types.GenesisState) {
+	if err := gs.Validate(); err != nil {
+		panic(err)
+	}
+	for _, s := range gs.Schedules {
+		k.SetSchedule(ctx, s)
+	}
+	if gs.NextScheduleId > 0 {
+		k.SetNextScheduleID(ctx, gs.NextScheduleId)
+	}
+}
+
+// ExportGenesis returns the paramschedule module's exported genesis.
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	var schedules []types.Schedule
+	k.IterateSchedules(ctx, func(s types.Schedule) bool {
+		schedules = append(schedules, s)
+		return false
+	})
+	return types.NewGenesisState(schedules, k.GetNextScheduleID(ctx))
+}
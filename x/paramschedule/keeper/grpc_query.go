@@ -0,0 +1,55 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/line/lbm-sdk/store/prefix"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/types/query"
+
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+var _ types.QueryServer = Keeper{}
+
+// Schedules implements the Query/Schedules gRPC method
+func (k Keeper) Schedules(c context.Context, req *types.QuerySchedulesRequest) (*types.QuerySchedulesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.ScheduleKeyPrefix)
+	var schedules []types.Schedule
+	pageRes, err := query.Paginate(store, req.Pagination, func(_, value []byte) error {
+		var s types.Schedule
+		if err := k.cdc.UnmarshalBinaryBare(value, &s); err != nil {
+			return err
+		}
+		schedules = append(schedules, s)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &types.QuerySchedulesResponse{Schedules: schedules, Pagination: pageRes}, nil
+}
+
+// Schedule implements the Query/Schedule gRPC method
+func (k Keeper) Schedule(c context.Context, req *types.QueryScheduleRequest) (*types.QueryScheduleResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	if req.ScheduleId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the schedule ID must be positive")
+	}
+
+	s, found := k.GetSchedule(sdk.UnwrapSDKContext(c), req.ScheduleId)
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "schedule %d not found", req.ScheduleId)
+	}
+	return &types.QueryScheduleResponse{Schedule: s}, nil
+}
@@ -0,0 +1,218 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/line/ostracon/libs/log"
+
+	"github.com/line/lbm-sdk/codec"
+	sdk "github.com/line/lbm-sdk/types"
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+	paramproposal "github.com/line/lbm-sdk/x/params/types/proposal"
+
+	audittypes "github.com/line/lfb/x/audit/types"
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+// Keeper of the paramschedule store
+type Keeper struct {
+	cdc          codec.Marshaler
+	storeKey     sdk.StoreKey
+	paramsKeeper types.ParamsKeeper
+	auditKeeper  types.AuditKeeper
+}
+
+// NewKeeper creates a new paramschedule Keeper instance.
+func NewKeeper(cdc codec.Marshaler, key sdk.StoreKey, paramsKeeper types.ParamsKeeper, auditKeeper types.AuditKeeper) Keeper {
+	return Keeper{
+		cdc:          cdc,
+		storeKey:     key,
+		paramsKeeper: paramsKeeper,
+		auditKeeper:  auditKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// ScheduleChanges schedules the changes of the proposal being executed. The activation must be
+// after the block of the context, and the changes must be applicable to the current params.
+func (k Keeper) ScheduleChanges(ctx sdk.Context, p *types.ScheduleParamChangeProposal) (types.Schedule, error) {
+	if err := types.ValidateActivation(p.Height, p.Time); err != nil {
+		return types.Schedule{}, err
+	}
+	if p.Height > 0 && p.Height <= ctx.BlockHeight() {
+		return types.Schedule{}, sdkerrors.Wrapf(types.ErrInvalidActivation,
+			"height %d is not after the current height %d", p.Height, ctx.BlockHeight())
+	}
+	if p.Height == 0 && !p.Time.After(ctx.BlockTime()) {
+		return types.Schedule{}, sdkerrors.Wrapf(types.ErrInvalidActivation,
+			"time %s is not after the current time %s", p.Time.UTC().Format(time.RFC3339), ctx.BlockTime().UTC().Format(time.RFC3339))
+	}
+	// the changes are tried out on a branch of the state discarded afterwards so that invalid
+	// changes fail the proposal rather than the schedule
+	cacheCtx, _ := ctx.CacheContext()
+	if err := k.applyChanges(cacheCtx, p.Changes, nil); err != nil {
+		return types.Schedule{}, err
+	}
+
+	s := types.Schedule{
+		Id:         k.GetNextScheduleID(ctx),
+		ProposalId: k.auditKeeper.ProposalID(ctx, p),
+		Changes:    p.Changes,
+		Height:     p.Height,
+		Time:       p.Time,
+	}
+	k.SetSchedule(ctx, s)
+	k.SetNextScheduleID(ctx, s.Id+1)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeScheduleParamChange,
+		sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprint(s.Id)),
+		sdk.NewAttribute(types.AttributeKeyProposalID, fmt.Sprint(s.ProposalId)),
+		sdk.NewAttribute(types.AttributeKeyHeight, fmt.Sprint(s.Height)),
+		sdk.NewAttribute(types.AttributeKeyTime, s.Time.UTC().Format(time.RFC3339)),
+	))
+	return s, nil
+}
+
+// CancelSchedule cancels the schedule of the ID.
+func (k Keeper) CancelSchedule(ctx sdk.Context, id uint64) error {
+	if _, found := k.GetSchedule(ctx, id); !found {
+		return sdkerrors.Wrapf(types.ErrScheduleNotFound, "%d", id)
+	}
+	k.DeleteSchedule(ctx, id)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		types.EventTypeCancelParamChangeSchedule,
+		sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprint(id)),
+	))
+	return nil
+}
+
+// ApplyDueSchedules applies the changes of the schedules due at the block of the context, in the
+// order of the IDs of the schedules, and removes the schedules. The changes of a schedule are
+// applied all or none: if one fails, e.g. as the value became invalid since the schedule, none
+// of the changes of the schedule is applied.
+func (k Keeper) ApplyDueSchedules(ctx sdk.Context) {
+	var due []types.Schedule
+	k.IterateSchedules(ctx, func(s types.Schedule) bool {
+		if s.IsDue(ctx.BlockHeight(), ctx.BlockTime()) {
+			due = append(due, s)
+		}
+		return false
+	})
+
+	for _, s := range due {
+		k.DeleteSchedule(ctx, s.Id)
+
+		cacheCtx, writeCache := ctx.CacheContext()
+		if err := k.applySchedule(cacheCtx, s); err != nil {
+			k.Logger(ctx).Error("failed to apply scheduled param changes", "schedule", s.Id, "err", err)
+			ctx.EventManager().EmitEvent(sdk.NewEvent(
+				types.EventTypeParamChangeScheduleFailed,
+				sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprint(s.Id)),
+				sdk.NewAttribute(types.AttributeKeyProposalID, fmt.Sprint(s.ProposalId)),
+				sdk.NewAttribute(types.AttributeKeyError, err.Error()),
+			))
+			continue
+		}
+		writeCache()
+		// the events of the branch are not emitted by writing it
+		ctx.EventManager().EmitEvents(cacheCtx.EventManager().Events())
+		k.Logger(ctx).Info("applied scheduled param changes", "schedule", s.Id, "proposal", s.ProposalId)
+	}
+}
+
+// applySchedule applies the changes of the schedule, recording them in the audit log.
+func (k Keeper) applySchedule(ctx sdk.Context, s types.Schedule) error {
+	return k.applyChanges(ctx, s.Changes, func(c paramproposal.ParamChange, old, new string) {
+		k.auditKeeper.AddRecord(ctx, audittypes.Record{
+			Kind:       audittypes.KindParamChange,
+			ProposalId: s.ProposalId,
+			Module:     c.Subspace,
+			Key:        c.Key,
+			OldValue:   old,
+			NewValue:   new,
+		})
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeApplyParamChange,
+			sdk.NewAttribute(types.AttributeKeyScheduleID, fmt.Sprint(s.Id)),
+			sdk.NewAttribute(types.AttributeKeyProposalID, fmt.Sprint(s.ProposalId)),
+			sdk.NewAttribute(types.AttributeKeySubspace, c.Subspace),
+			sdk.NewAttribute(types.AttributeKeyKey, c.Key),
+			sdk.NewAttribute(types.AttributeKeyValue, c.Value),
+		))
+	})
+}
+
+// applyChanges applies the changes in order, calling the callback, if any, with the raw values
+// before and after every change.
+func (k Keeper) applyChanges(ctx sdk.Context, changes []paramproposal.ParamChange,
+	cb func(c paramproposal.ParamChange, old, new string)) error {
+	for _, c := range changes {
+		ss, ok := k.paramsKeeper.GetSubspace(c.Subspace)
+		if !ok {
+			return sdkerrors.Wrap(paramproposal.ErrUnknownSubspace, c.Subspace)
+		}
+		old := string(ss.GetRaw(ctx, []byte(c.Key)))
+		if err := ss.Update(ctx, []byte(c.Key), []byte(c.Value)); err != nil {
+			return sdkerrors.Wrapf(paramproposal.ErrSettingParameter, "key: %s, value: %s, err: %s", c.Key, c.Value, err.Error())
+		}
+		if cb != nil {
+			cb(c, old, string(ss.GetRaw(ctx, []byte(c.Key))))
+		}
+	}
+	return nil
+}
+
+// SetNextScheduleID sets the ID of the next schedule.
+func (k Keeper) SetNextScheduleID(ctx sdk.Context, id uint64) {
+	ctx.KVStore(k.storeKey).Set(types.NextScheduleIDKey, sdk.Uint64ToBigEndian(id))
+}
+
+// GetNextScheduleID returns the ID of the next schedule.
+func (k Keeper) GetNextScheduleID(ctx sdk.Context) uint64 {
+	bz := ctx.KVStore(k.storeKey).Get(types.NextScheduleIDKey)
+	if bz == nil {
+		return 1
+	}
+	return sdk.BigEndianToUint64(bz)
+}
+
+// SetSchedule sets the schedule.
+func (k Keeper) SetSchedule(ctx sdk.Context, s types.Schedule) {
+	ctx.KVStore(k.storeKey).Set(types.ScheduleKey(s.Id), k.cdc.MustMarshalBinaryBare(&s))
+}
+
+// GetSchedule returns the schedule of the ID.
+func (k Keeper) GetSchedule(ctx sdk.Context, id uint64) (types.Schedule, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.ScheduleKey(id))
+	if bz == nil {
+		return types.Schedule{}, false
+	}
+	var s types.Schedule
+	k.cdc.MustUnmarshalBinaryBare(bz, &s)
+	return s, true
+}
+
+// DeleteSchedule deletes the schedule of the ID.
+func (k Keeper) DeleteSchedule(ctx sdk.Context, id uint64) {
+	ctx.KVStore(k.storeKey).Delete(types.ScheduleKey(id))
+}
+
+// IterateSchedules iterates over the schedules in the order of their IDs.
+func (k Keeper) IterateSchedules(ctx sdk.Context, cb func(s types.Schedule) (stop bool)) {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.ScheduleKeyPrefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var s types.Schedule
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &s)
+		if cb(s) {
+			break
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	ocproto "github.com/line/ostracon/proto/ostracon/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/line/lbm-sdk/crypto/keys/ed25519"
+	sdk "github.com/line/lbm-sdk/types"
+	"github.com/line/lbm-sdk/x/gov"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramproposal "github.com/line/lbm-sdk/x/params/types/proposal"
+	"github.com/line/lbm-sdk/x/staking"
+	"github.com/line/lbm-sdk/x/staking/teststaking"
+
+	"github.com/line/lfb/app"
+	audittypes "github.com/line/lfb/x/audit/types"
+	"github.com/line/lfb/x/paramschedule"
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+func setup() (*app.LinkApp, sdk.Context) {
+	linkApp := app.Setup(false)
+	ctx := linkApp.BaseApp.NewContext(false, ocproto.Header{Height: 10, Time: time.Now().UTC()})
+	return linkApp, ctx
+}
+
+func maxValidators(value string) []paramproposal.ParamChange {
+	return []paramproposal.ParamChange{{Subspace: "staking", Key: "MaxValidators", Value: value}}
+}
+
+// endBlock runs the EndBlocker of the module at the block of the context with a new event manager.
+func endBlock(linkApp *app.LinkApp, ctx sdk.Context) sdk.Events {
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	paramschedule.EndBlocker(ctx, linkApp.ParamScheduleKeeper)
+	return ctx.EventManager().Events()
+}
+
+func eventTypes(events sdk.Events) []string {
+	var types []string
+	for _, ev := range events {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+func TestScheduleChangesActivation(t *testing.T) {
+	linkApp, ctx := setup()
+	k := linkApp.ParamScheduleKeeper
+
+	for _, p := range []*types.ScheduleParamChangeProposal{
+		types.NewScheduleParamChangeProposal("past", "past", maxValidators("10"), ctx.BlockHeight()-1, time.Time{}),
+		types.NewScheduleParamChangeProposal("now", "now", maxValidators("10"), ctx.BlockHeight(), time.Time{}),
+		types.NewScheduleParamChangeProposal("past", "past", maxValidators("10"), 0, ctx.BlockTime().Add(-time.Second)),
+		types.NewScheduleParamChangeProposal("now", "now", maxValidators("10"), 0, ctx.BlockTime()),
+		types.NewScheduleParamChangeProposal("both", "both", maxValidators("10"), ctx.BlockHeight()+1, ctx.BlockTime().Add(time.Hour)),
+	} {
+		_, err := k.ScheduleChanges(ctx, p)
+		require.ErrorIs(t, err, types.ErrInvalidActivation, "%s", p)
+	}
+	require.Equal(t, uint64(1), k.GetNextScheduleID(ctx))
+
+	s, err := k.ScheduleChanges(ctx, types.NewScheduleParamChangeProposal("next", "next", maxValidators("10"), ctx.BlockHeight()+1, time.Time{}))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), s.Id)
+	got, found := k.GetSchedule(ctx, s.Id)
+	require.True(t, found)
+	require.Equal(t, s, got)
+}
+
+func TestScheduleChangesDryRun(t *testing.T) {
+	linkApp, ctx := setup()
+	k := linkApp.ParamScheduleKeeper
+	old := linkApp.StakingKeeper.MaxValidators(ctx)
+
+	for _, changes := range [][]paramproposal.ParamChange{
+		maxValidators(`"ten"`),
+		{{Subspace: "unknown", Key: "MaxValidators", Value: "10"}},
+		// the invalid change is tried out after the valid one, which is not kept
+		append(maxValidators("10"), paramproposal.ParamChange{Subspace: "staking", Key: "BondDenom", Value: "10"}),
+	} {
+		_, err := k.ScheduleChanges(ctx, types.NewScheduleParamChangeProposal("invalid", "invalid", changes, ctx.BlockHeight()+1, time.Time{}))
+		require.Error(t, err, "%v", changes)
+	}
+	require.Equal(t, old, linkApp.StakingKeeper.MaxValidators(ctx))
+	require.Equal(t, uint64(1), k.GetNextScheduleID(ctx))
+	k.IterateSchedules(ctx, func(s types.Schedule) bool {
+		t.Fatalf("unexpected schedule %v", s)
+		return true
+	})
+}
+
+func TestApplyAtHeight(t *testing.T) {
+	linkApp := app.Setup(false)
+	ctx := linkApp.BaseApp.NewContext(false, ocproto.Header{Height: 1, Time: time.Now().UTC()})
+	k := linkApp.ParamScheduleKeeper
+	old := linkApp.StakingKeeper.MaxValidators(ctx)
+
+	// the schedule is made by a proposal voted by a validator
+	addr := app.AddTestAddrs(linkApp, ctx, 1, sdk.TokensFromConsensusPower(200))[0]
+	teststaking.NewHelper(t, ctx, linkApp.StakingKeeper).CreateValidatorWithValPower(addr.ToValAddress(), ed25519.GenPrivKey().PubKey(), 100, true)
+	staking.EndBlocker(ctx, linkApp.StakingKeeper)
+	proposal, err := linkApp.GovKeeper.SubmitProposal(ctx,
+		types.NewScheduleParamChangeProposal("max validators", "max validators", maxValidators("10"), 5, time.Time{}))
+	require.NoError(t, err)
+	linkApp.GovKeeper.ActivateVotingPeriod(ctx, proposal)
+	require.NoError(t, linkApp.GovKeeper.AddVote(ctx, proposal.ProposalId, addr, govtypes.OptionYes))
+	ctx = ctx.WithBlockHeight(2).WithBlockTime(ctx.BlockTime().Add(linkApp.GovKeeper.GetVotingParams(ctx).VotingPeriod))
+	gov.EndBlocker(ctx, linkApp.GovKeeper)
+
+	s, found := k.GetSchedule(ctx, 1)
+	require.True(t, found)
+	require.Equal(t, proposal.ProposalId, s.ProposalId)
+
+	for h := int64(2); h < 5; h++ {
+		require.Empty(t, endBlock(linkApp, ctx.WithBlockHeight(h)))
+	}
+	require.Equal(t, old, linkApp.StakingKeeper.MaxValidators(ctx))
+
+	ctx = ctx.WithBlockHeight(5)
+	require.Equal(t, []string{types.EventTypeApplyParamChange}, eventTypes(endBlock(linkApp, ctx)))
+	require.Equal(t, uint32(10), linkApp.StakingKeeper.MaxValidators(ctx))
+	_, found = k.GetSchedule(ctx, 1)
+	require.False(t, found)
+
+	var records []audittypes.Record
+	linkApp.AuditKeeper.IterateRecords(ctx, func(r audittypes.Record) bool {
+		records = append(records, r)
+		return false
+	})
+	require.Len(t, records, 1)
+	require.Equal(t, proposal.ProposalId, records[0].ProposalId)
+	require.Equal(t, int64(5), records[0].Height)
+	require.Equal(t, "10", records[0].NewValue)
+
+	// the schedule is applied once
+	require.Empty(t, endBlock(linkApp, ctx.WithBlockHeight(6)))
+}
+
+func TestApplyAtTime(t *testing.T) {
+	linkApp, ctx := setup()
+	k := linkApp.ParamScheduleKeeper
+	old := linkApp.StakingKeeper.MaxValidators(ctx)
+
+	activation := ctx.BlockTime().Add(time.Hour)
+	_, err := k.ScheduleChanges(ctx, types.NewScheduleParamChangeProposal("max validators", "max validators", maxValidators("10"), 0, activation))
+	require.NoError(t, err)
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1000).WithBlockTime(activation.Add(-time.Nanosecond))
+	require.Empty(t, endBlock(linkApp, ctx))
+	require.Equal(t, old, linkApp.StakingKeeper.MaxValidators(ctx))
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1).WithBlockTime(activation)
+	require.Equal(t, []string{types.EventTypeApplyParamChange}, eventTypes(endBlock(linkApp, ctx)))
+	require.Equal(t, uint32(10), linkApp.StakingKeeper.MaxValidators(ctx))
+}
+
+func TestApplyAllOrNone(t *testing.T) {
+	linkApp, ctx := setup()
+	k := linkApp.ParamScheduleKeeper
+	old := linkApp.StakingKeeper.MaxValidators(ctx)
+	oldDenom := linkApp.StakingKeeper.BondDenom(ctx)
+
+	// the second change became invalid since the schedule, e.g. by an upgrade
+	k.SetSchedule(ctx, types.Schedule{
+		Id: 1,
+		Changes: []paramproposal.ParamChange{
+			{Subspace: "staking", Key: "MaxValidators", Value: "10"},
+			{Subspace: "staking", Key: "BondDenom", Value: "10"},
+		},
+		Height: ctx.BlockHeight() + 1,
+	})
+	// the schedules are independent of one another
+	k.SetSchedule(ctx, types.Schedule{
+		Id:      2,
+		Changes: []paramproposal.ParamChange{{Subspace: "staking", Key: "HistoricalEntries", Value: "20"}},
+		Height:  ctx.BlockHeight() + 1,
+	})
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	events := endBlock(linkApp, ctx)
+	require.Equal(t, []string{types.EventTypeParamChangeScheduleFailed, types.EventTypeApplyParamChange}, eventTypes(events))
+	require.Equal(t, old, linkApp.StakingKeeper.MaxValidators(ctx))
+	require.Equal(t, oldDenom, linkApp.StakingKeeper.BondDenom(ctx))
+	require.Equal(t, uint32(20), linkApp.StakingKeeper.HistoricalEntries(ctx))
+
+	// the failed schedule is removed, and only the applied one is recorded
+	_, found := k.GetSchedule(ctx, 1)
+	require.False(t, found)
+	var records []audittypes.Record
+	linkApp.AuditKeeper.IterateRecords(ctx, func(r audittypes.Record) bool {
+		records = append(records, r)
+		return false
+	})
+	require.Len(t, records, 1)
+	require.Equal(t, "20", records[0].NewValue)
+}
+
+func TestCancelSchedule(t *testing.T) {
+	linkApp, ctx := setup()
+	k := linkApp.ParamScheduleKeeper
+	old := linkApp.StakingKeeper.MaxValidators(ctx)
+	handler := paramschedule.NewProposalHandler(k)
+
+	require.NoError(t, handler(ctx, types.NewScheduleParamChangeProposal("max validators", "max validators",
+		maxValidators("10"), ctx.BlockHeight()+1, time.Time{})))
+	require.ErrorIs(t, handler(ctx, types.NewCancelParamChangeScheduleProposal("cancel", "cancel", 2)), types.ErrScheduleNotFound)
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, handler(ctx, types.NewCancelParamChangeScheduleProposal("cancel", "cancel", 1)))
+	require.Equal(t, []string{types.EventTypeCancelParamChangeSchedule}, eventTypes(ctx.EventManager().Events()))
+	_, found := k.GetSchedule(ctx, 1)
+	require.False(t, found)
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	require.Empty(t, endBlock(linkApp, ctx))
+	require.Equal(t, old, linkApp.StakingKeeper.MaxValidators(ctx))
+	require.ErrorIs(t, handler(ctx, types.NewCancelParamChangeScheduleProposal("cancel", "cancel", 1)), types.ErrScheduleNotFound)
+}
@@ -0,0 +1,28 @@
+package paramschedule
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+
+	"github.com/line/lfb/x/paramschedule/keeper"
+	"github.com/line/lfb/x/paramschedule/types"
+)
+
+// NewProposalHandler creates a governance handler of the proposals to schedule param changes
+// and to cancel the schedules.
+func NewProposalHandler(k keeper.Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) error {
+		switch c := content.(type) {
+		case *types.ScheduleParamChangeProposal:
+			_, err := k.ScheduleChanges(ctx, c)
+			return err
+
+		case *types.CancelParamChangeScheduleProposal:
+			return k.CancelSchedule(ctx, c.ScheduleId)
+
+		default:
+			return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s proposal content type: %T", types.ModuleName, c)
+		}
+	}
+}
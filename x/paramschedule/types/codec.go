@@ -0,0 +1,23 @@
+package types
+
+import (
+	"github.com/line/lbm-sdk/codec"
+	"github.com/line/lbm-sdk/codec/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+)
+
+// RegisterLegacyAminoCodec registers the proposals of the paramschedule module on the LegacyAmino
+// codec.
+func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&ScheduleParamChangeProposal{}, "lfb/ScheduleParamChangeProposal", nil)
+	cdc.RegisterConcrete(&CancelParamChangeScheduleProposal{}, "lfb/CancelParamChangeScheduleProposal", nil)
+}
+
+// RegisterInterfaces registers the proposals of the paramschedule module as gov contents.
+func RegisterInterfaces(registry types.InterfaceRegistry) {
+	registry.RegisterImplementations(
+		(*govtypes.Content)(nil),
+		&ScheduleParamChangeProposal{},
+		&CancelParamChangeScheduleProposal{},
+	)
+}
@@ -0,0 +1,11 @@
+package types
+
+import (
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+)
+
+// paramschedule module sentinel errors
+var (
+	ErrInvalidActivation = sdkerrors.Register(ModuleName, 2, "invalid activation")
+	ErrScheduleNotFound  = sdkerrors.Register(ModuleName, 3, "schedule not found")
+)
@@ -0,0 +1,18 @@
+package types
+
+// paramschedule module event types
+const (
+	EventTypeScheduleParamChange       = "schedule_param_change"
+	EventTypeCancelParamChangeSchedule = "cancel_param_change_schedule"
+	EventTypeApplyParamChange          = "apply_scheduled_param_change"
+	EventTypeParamChangeScheduleFailed = "param_change_schedule_failed"
+
+	AttributeKeyScheduleID = "schedule_id"
+	AttributeKeyProposalID = "proposal_id"
+	AttributeKeyHeight     = "height"
+	AttributeKeyTime       = "time"
+	AttributeKeySubspace   = "subspace"
+	AttributeKeyKey        = "key"
+	AttributeKeyValue      = "value"
+	AttributeKeyError      = "error"
+)
@@ -0,0 +1,20 @@
+package types
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramstypes "github.com/line/lbm-sdk/x/params/types"
+
+	audittypes "github.com/line/lfb/x/audit/types"
+)
+
+// ParamsKeeper defines the params keeper the scheduled changes are applied with.
+type ParamsKeeper interface {
+	GetSubspace(s string) (*paramstypes.Subspace, bool)
+}
+
+// AuditKeeper defines the audit keeper the applied changes are recorded with.
+type AuditKeeper interface {
+	ProposalID(ctx sdk.Context, content govtypes.Content) uint64
+	AddRecord(ctx sdk.Context, r audittypes.Record) audittypes.Record
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"fmt"
+)
+
+// NewGenesisState creates a new genesis state.
+func NewGenesisState(schedules []Schedule, nextScheduleID uint64) *GenesisState {
+	return &GenesisState{Schedules: schedules, NextScheduleId: nextScheduleID}
+}
+
+// DefaultGenesisState returns a default genesis state, without schedules.
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{NextScheduleId: 1}
+}
+
+// Validate performs basic genesis state validation, returning an error upon any failure.
+func (gs GenesisState) Validate() error {
+	var last uint64
+	for _, s := range gs.Schedules {
+		if s.Id <= last {
+			return fmt.Errorf("the IDs of the schedules must be positive and increasing: %d after %d", s.Id, last)
+		}
+		last = s.Id
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+	if last > 0 && gs.NextScheduleId <= last {
+		return fmt.Errorf("the next schedule ID %d must be greater than the IDs of the schedules", gs.NextScheduleId)
+	}
+	return nil
+}
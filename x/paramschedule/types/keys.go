@@ -0,0 +1,33 @@
+package types
+
+import (
+	sdk "github.com/line/lbm-sdk/types"
+)
+
+const (
+	// ModuleName is the name of the paramschedule module
+	ModuleName = "paramschedule"
+
+	// StoreKey is the store key string for the paramschedule module
+	StoreKey = ModuleName
+
+	// RouterKey is the message route for the proposals of the paramschedule module
+	RouterKey = ModuleName
+
+	// QuerierRoute is the querier route for the paramschedule module
+	QuerierRoute = ModuleName
+)
+
+// The prefixes of the keys of the store:
+//
+//	ScheduleKeyPrefix | id -> Schedule
+//	NextScheduleIDKey      -> the ID of the next schedule
+var (
+	ScheduleKeyPrefix = []byte{0x01}
+	NextScheduleIDKey = []byte{0x02}
+)
+
+// ScheduleKey returns the key of the schedule of the ID.
+func ScheduleKey(id uint64) []byte {
+	return append(append([]byte{}, ScheduleKeyPrefix...), sdk.Uint64ToBigEndian(id)...)
+}
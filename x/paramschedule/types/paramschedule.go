@@ -0,0 +1,30 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	paramproposal "github.com/line/lbm-sdk/x/params/types/proposal"
+)
+
+// Validate validates the schedule.
+func (s Schedule) Validate() error {
+	if s.Id == 0 {
+		return fmt.Errorf("the ID of the schedule must be positive")
+	}
+	if err := paramproposal.ValidateChanges(s.Changes); err != nil {
+		return fmt.Errorf("schedule %d: %w", s.Id, err)
+	}
+	if err := ValidateActivation(s.Height, s.Time); err != nil {
+		return fmt.Errorf("schedule %d: %w", s.Id, err)
+	}
+	return nil
+}
+
+// IsDue returns whether the changes are to be applied at the block of the height and time.
+func (s Schedule) IsDue(height int64, t time.Time) bool {
+	if s.Height > 0 {
+		return height >= s.Height
+	}
+	return !t.Before(s.Time)
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/line/lbm-sdk/types/errors"
+	govtypes "github.com/line/lbm-sdk/x/gov/types"
+	paramproposal "github.com/line/lbm-sdk/x/params/types/proposal"
+)
+
+// The types of the proposals of the paramschedule module.
+const (
+	ProposalTypeScheduleParamChange       = "ScheduleParamChange"
+	ProposalTypeCancelParamChangeSchedule = "CancelParamChangeSchedule"
+)
+
+var (
+	_ govtypes.Content = &ScheduleParamChangeProposal{}
+	_ govtypes.Content = &CancelParamChangeScheduleProposal{}
+)
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeScheduleParamChange)
+	govtypes.RegisterProposalTypeCodec(&ScheduleParamChangeProposal{}, "lfb/ScheduleParamChangeProposal")
+	govtypes.RegisterProposalType(ProposalTypeCancelParamChangeSchedule)
+	govtypes.RegisterProposalTypeCodec(&CancelParamChangeScheduleProposal{}, "lfb/CancelParamChangeScheduleProposal")
+}
+
+// NewScheduleParamChangeProposal creates a proposal to schedule the param changes at the
+// activation height, or at the activation time if the height is 0.
+func NewScheduleParamChangeProposal(title, description string, changes []paramproposal.ParamChange,
+	height int64, t time.Time) *ScheduleParamChangeProposal {
+	return &ScheduleParamChangeProposal{
+		Title:       title,
+		Description: description,
+		Changes:     changes,
+		Height:      height,
+		Time:        t,
+	}
+}
+
+func (p *ScheduleParamChangeProposal) GetTitle() string       { return p.Title }
+func (p *ScheduleParamChangeProposal) GetDescription() string { return p.Description }
+func (p *ScheduleParamChangeProposal) ProposalRoute() string  { return RouterKey }
+func (p *ScheduleParamChangeProposal) ProposalType() string   { return ProposalTypeScheduleParamChange }
+
+// ValidateBasic validates the proposal, which must have changes and either an activation height
+// or an activation time.
+func (p *ScheduleParamChangeProposal) ValidateBasic() error {
+	if err := govtypes.ValidateAbstract(p); err != nil {
+		return err
+	}
+	if err := paramproposal.ValidateChanges(p.Changes); err != nil {
+		return err
+	}
+	return ValidateActivation(p.Height, p.Time)
+}
+
+func (p ScheduleParamChangeProposal) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`Schedule Param Change Proposal:
+  Title:       %s
+  Description: %s
+  Activation:  %s
+  Changes:
+`, p.Title, p.Description, activation(p.Height, p.Time)))
+	for _, c := range p.Changes {
+		b.WriteString(fmt.Sprintf("    %s/%s: %s\n", c.Subspace, c.Key, c.Value))
+	}
+	return b.String()
+}
+
+// NewCancelParamChangeScheduleProposal creates a proposal to cancel the schedule of the ID.
+func NewCancelParamChangeScheduleProposal(title, description string, scheduleID uint64) *CancelParamChangeScheduleProposal {
+	return &CancelParamChangeScheduleProposal{
+		Title:       title,
+		Description: description,
+		ScheduleId:  scheduleID,
+	}
+}
+
+func (p *CancelParamChangeScheduleProposal) GetTitle() string       { return p.Title }
+func (p *CancelParamChangeScheduleProposal) GetDescription() string { return p.Description }
+func (p *CancelParamChangeScheduleProposal) ProposalRoute() string  { return RouterKey }
+func (p *CancelParamChangeScheduleProposal) ProposalType() string {
+	return ProposalTypeCancelParamChangeSchedule
+}
+
+// ValidateBasic validates the proposal.
+func (p *CancelParamChangeScheduleProposal) ValidateBasic() error {
+	if err := govtypes.ValidateAbstract(p); err != nil {
+		return err
+	}
+	if p.ScheduleId == 0 {
+		return sdkerrors.Wrap(ErrScheduleNotFound, "the schedule ID must be positive")
+	}
+	return nil
+}
+
+func (p CancelParamChangeScheduleProposal) String() string {
+	return fmt.Sprintf(`Cancel Param Change Schedule Proposal:
+  Title:       %s
+  Description: %s
+  Schedule ID: %d
+`, p.Title, p.Description, p.ScheduleId)
+}
+
+// ValidateActivation validates that exactly one of the activation height and time is set.
+func ValidateActivation(height int64, t time.Time) error {
+	switch {
+	case height < 0:
+		return sdkerrors.Wrapf(ErrInvalidActivation, "negative height %d", height)
+	case height == 0 && t.IsZero():
+		return sdkerrors.Wrap(ErrInvalidActivation, "either the height or the time must be set")
+	case height > 0 && !t.IsZero():
+		return sdkerrors.Wrap(ErrInvalidActivation, "only one of the height and the time can be set")
+	}
+	return nil
+}
+
+func activation(height int64, t time.Time) string {
+	if height > 0 {
+		return fmt.Sprintf("height %d", height)
+	}
+	return "time " + t.UTC().Format(time.RFC3339)
+}